@@ -7,7 +7,15 @@ import (
 )
 
 func main() {
-	if err := cli.Execute(); err != nil {
-		os.Exit(1)
+	if err := run(); err != nil {
+		os.Exit(cli.ExitCode(err))
 	}
 }
+
+// run executes the command. Its defer runs before os.Exit would skip it,
+// and on a panic, so a proxy never outlives the process with its session
+// token still in the keyring.
+func run() error {
+	defer cli.StopProxy()
+	return cli.Execute()
+}
@@ -0,0 +1,201 @@
+// Package alerts keeps the price alerts set with `boba alerts` and decides
+// when one fires. The proxy polls prices and reports crossings; the alerts
+// themselves are persisted in the data directory.
+package alerts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// Hysteresis is how far back across a threshold, as a fraction of it, the
+// price has to move before a fired alert can fire again. It keeps a price
+// hovering at the threshold from raising an alert every poll.
+const Hysteresis = 0.02
+
+// Alert watches one token's price. Above and Below are USD thresholds; zero
+// leaves that side unwatched.
+type Alert struct {
+	ID      string    `json:"id"`
+	Address string    `json:"address"`
+	Symbol  string    `json:"symbol,omitempty"`
+	Chain   string    `json:"chain"`
+	Above   float64   `json:"above,omitempty"`
+	Below   float64   `json:"below,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Label names the alert's token by symbol when known.
+func (a Alert) Label() string {
+	if a.Symbol != "" {
+		return a.Symbol
+	}
+	return a.Address
+}
+
+var mu sync.Mutex
+
+// Path returns the alerts file, stored next to the config file.
+func Path() string {
+	return filepath.Join(config.DataDir(), "alerts.json")
+}
+
+// List returns every alert, oldest first. A missing file is not an error.
+func List() ([]Alert, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	return load()
+}
+
+func load() ([]Alert, error) {
+	data, err := os.ReadFile(Path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []Alert
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", Path(), err)
+	}
+	return list, nil
+}
+
+func store(list []Alert) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return config.WritePrivateFile(Path(), data)
+}
+
+// ValidThresholds checks that a watches at least one side and that its
+// thresholds make sense together.
+func (a Alert) ValidThresholds() error {
+	if a.Above < 0 || a.Below < 0 {
+		return errors.New("alert thresholds can't be negative")
+	}
+	if a.Above == 0 && a.Below == 0 {
+		return errors.New("set --above, --below or both")
+	}
+	if a.Above > 0 && a.Below > 0 && a.Below >= a.Above {
+		return fmt.Errorf("--below (%g) must be less than --above (%g)", a.Below, a.Above)
+	}
+	return nil
+}
+
+// Add validates a, gives it the next free ID and saves it.
+func Add(a Alert) (Alert, error) {
+	if a.Address == "" || a.Chain == "" {
+		return Alert{}, errors.New("an alert needs a token address and a chain")
+	}
+	if err := a.ValidThresholds(); err != nil {
+		return Alert{}, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	list, err := load()
+	if err != nil {
+		return Alert{}, err
+	}
+	next := 1
+	for _, existing := range list {
+		if n, err := strconv.Atoi(existing.ID); err == nil && n >= next {
+			next = n + 1
+		}
+	}
+	a.ID = strconv.Itoa(next)
+	if a.Created.IsZero() {
+		a.Created = time.Now()
+	}
+	if err := store(append(list, a)); err != nil {
+		return Alert{}, err
+	}
+	return a, nil
+}
+
+// Remove deletes the alert with the given ID.
+func Remove(id string) error {
+	mu.Lock()
+	defer mu.Unlock()
+	list, err := load()
+	if err != nil {
+		return err
+	}
+	for i, a := range list {
+		if a.ID == id {
+			return store(append(list[:i], list[i+1:]...))
+		}
+	}
+	return fmt.Errorf("no alert with ID %s (see 'boba alerts list')", id)
+}
+
+// Crossing is an alert firing: the price went past one of its thresholds.
+type Crossing struct {
+	Alert Alert
+	Price float64
+	Above bool // crossed the upper threshold rather than the lower one
+}
+
+// String describes the crossing in one line, e.g. "BONK rose above
+// $0.00003 (now $0.0000312)".
+func (c Crossing) String() string {
+	if c.Above {
+		return fmt.Sprintf("%s rose above $%s (now $%s)", c.Alert.Label(), formatPrice(c.Alert.Above), formatPrice(c.Price))
+	}
+	return fmt.Sprintf("%s fell below $%s (now $%s)", c.Alert.Label(), formatPrice(c.Alert.Below), formatPrice(c.Price))
+}
+
+func formatPrice(p float64) string {
+	return strconv.FormatFloat(p, 'g', 6, 64)
+}
+
+// Tracker remembers which thresholds have fired, so each fires once per
+// crossing. It lives in memory: after a restart an alert whose price is
+// still past its threshold fires once more.
+type Tracker struct {
+	fired map[string]bool
+}
+
+// NewTracker returns a tracker with every threshold armed.
+func NewTracker() *Tracker {
+	return &Tracker{fired: make(map[string]bool)}
+}
+
+// Check compares a fresh price with a's thresholds and returns the ones
+// that fire. A fired threshold re-arms once the price is back past it by
+// the hysteresis band.
+func (t *Tracker) Check(a Alert, price float64) []Crossing {
+	var out []Crossing
+	if a.Above > 0 {
+		key := a.ID + ":" + a.Address + ":above"
+		switch {
+		case !t.fired[key] && price >= a.Above:
+			t.fired[key] = true
+			out = append(out, Crossing{Alert: a, Price: price, Above: true})
+		case t.fired[key] && price < a.Above*(1-Hysteresis):
+			delete(t.fired, key)
+		}
+	}
+	if a.Below > 0 {
+		key := a.ID + ":" + a.Address + ":below"
+		switch {
+		case !t.fired[key] && price <= a.Below:
+			t.fired[key] = true
+			out = append(out, Crossing{Alert: a, Price: price})
+		case t.fired[key] && price > a.Below*(1+Hysteresis):
+			delete(t.fired, key)
+		}
+	}
+	return out
+}
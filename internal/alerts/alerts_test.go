@@ -0,0 +1,60 @@
+package alerts
+
+import "testing"
+
+// A threshold fires once when crossed and again only after the price has
+// moved back past it by the hysteresis band.
+func TestTrackerHysteresis(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		alert  Alert
+		prices []float64
+		fires  []bool
+	}{
+		{
+			name:   "above",
+			alert:  Alert{ID: "a", Address: "tok", Above: 100},
+			prices: []float64{99, 100, 101, 99, 98.1, 100, 97.9, 99, 100.5},
+			fires:  []bool{false, true, false, false, false, false, false, false, true},
+		},
+		{
+			name:   "below",
+			alert:  Alert{ID: "b", Address: "tok", Below: 50},
+			prices: []float64{51, 50, 49, 50.9, 49, 51.1, 50.5, 49.9},
+			fires:  []bool{false, true, false, false, false, false, false, true},
+		},
+	} {
+		tr := NewTracker()
+		for i, p := range tc.prices {
+			got := tr.Check(tc.alert, p)
+			if fired := len(got) == 1; fired != tc.fires[i] || len(got) > 1 {
+				t.Errorf("%s: price %v (step %d) gave %d crossings, want fired=%v", tc.name, p, i, len(got), tc.fires[i])
+			}
+		}
+	}
+}
+
+// Each side of an alert, and each alert, is armed on its own.
+func TestTrackerIndependentThresholds(t *testing.T) {
+	tr := NewTracker()
+	band := Alert{ID: "a", Address: "tok", Symbol: "BONK", Above: 2, Below: 1}
+	other := Alert{ID: "b", Address: "tok", Above: 2}
+
+	if got := tr.Check(band, 2.5); len(got) != 1 || !got[0].Above {
+		t.Fatalf("above: %+v", got)
+	}
+	if got := tr.Check(other, 2.5); len(got) != 1 {
+		t.Errorf("a second alert on the same token didn't fire: %+v", got)
+	}
+	got := tr.Check(band, 0.5)
+	if len(got) != 1 || got[0].Above {
+		t.Fatalf("below: %+v", got)
+	}
+	if want := "BONK fell below $1 (now $0.5)"; got[0].String() != want {
+		t.Errorf("String() = %q, want %q", got[0].String(), want)
+	}
+	// The drop re-armed the upper threshold, so the next rise fires again.
+	if got := tr.Check(band, 2.1); len(got) != 1 || !got[0].Above {
+		t.Errorf("above after re-arm: %+v", got)
+	}
+}
@@ -0,0 +1,54 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// notifyTimeout bounds how long a notification helper may take.
+const notifyTimeout = 10 * time.Second
+
+// toastScript shows a Windows toast through PowerShell's own app ID, which
+// is registered on every install. The text comes in through the environment
+// so it needs no quoting.
+const toastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$t = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$x = $t.GetElementsByTagName('text')
+$x.Item(0).AppendChild($t.CreateTextNode($env:BOBA_NOTIFY_TITLE)) > $null
+$x.Item(1).AppendChild($t.CreateTextNode($env:BOBA_NOTIFY_BODY)) > $null
+$id = '{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe'
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($id).Show([Windows.UI.Notifications.ToastNotification]::new($t))`
+
+// Notify shows a desktop notification: osascript on macOS, notify-send on
+// Linux and a PowerShell toast on Windows.
+func Notify(title, body string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, body)
+	case "windows":
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+		cmd.Env = append(os.Environ(), "BOBA_NOTIFY_TITLE="+title, "BOBA_NOTIFY_BODY="+body)
+	default:
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=boba", title, body)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if len(out) > 0 {
+			return fmt.Errorf("%s: %w: %s", cmd.Args[0], err, out)
+		}
+		return fmt.Errorf("%s: %w", cmd.Args[0], err)
+	}
+	return nil
+}
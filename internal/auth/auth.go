@@ -7,40 +7,50 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/tradeboba/boba-cli/internal/client"
 	"github.com/tradeboba/boba-cli/internal/config"
 	"github.com/tradeboba/boba-cli/internal/logger"
 )
 
-// noRedirectClient returns an HTTP client that refuses to follow redirects,
-// preventing Authorization headers from being forwarded to unintended hosts.
-func noRedirectClient(timeout time.Duration) *http.Client {
-	return &http.Client{
-		Timeout: timeout,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return errors.New("redirects are not followed for requests carrying credentials")
-		},
-	}
-}
-
 type authRequest struct {
 	AuthMethod  string `json:"auth_method"`
 	AgentID     string `json:"agent_id"`
 	AgentSecret string `json:"agent_secret"`
 }
 
+// timestamp is an expiry as the auth service sends it: a date string or a
+// unix time as a JSON number, which is kept as its digits.
+type timestamp string
+
+func (t *timestamp) UnmarshalJSON(data []byte) error {
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err == nil {
+		*t = timestamp(n)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*t = timestamp(s)
+	return nil
+}
+
 type authResponseData struct {
-	SessionID             string `json:"session_id"`
-	AccessToken           string `json:"access_token"`
-	AccessTokenExpiresAt  string `json:"access_token_expires_at"`
-	RefreshToken          string `json:"refresh_token"`
-	RefreshTokenExpiresAt string `json:"refresh_token_expires_at"`
-	AgentID               string `json:"agent_id"`
-	AgentName             string `json:"agent_name"`
-	EVMAddress            string `json:"evm_address"`
-	SolanaAddress         string `json:"solana_address"`
-	SubOrganizationID     string `json:"sub_organization_id"`
+	SessionID             string    `json:"session_id"`
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  timestamp `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt timestamp `json:"refresh_token_expires_at"`
+	AgentID               string    `json:"agent_id"`
+	AgentName             string    `json:"agent_name"`
+	EVMAddress            string    `json:"evm_address"`
+	SolanaAddress         string    `json:"solana_address"`
+	SubOrganizationID     string    `json:"sub_organization_id"`
 }
 
 type authResponse struct {
@@ -52,21 +62,73 @@ type refreshRequest struct {
 }
 
 type refreshResponseData struct {
-	AccessToken          string `json:"access_token"`
-	AccessTokenExpiresAt string `json:"access_token_expires_at"`
+	AccessToken          string    `json:"access_token"`
+	AccessTokenExpiresAt timestamp `json:"access_token_expires_at"`
 }
 
 type refreshResponse struct {
 	Data refreshResponseData `json:"data"`
 }
 
+// Refreshes counts new access tokens obtained by refreshing or
+// re-authenticating, and Failures counts attempts that failed, since the
+// process started. The proxy reports both in its metrics.
+var (
+	Refreshes atomic.Int64
+	Failures  atomic.Int64
+)
+
 // Authenticate performs a full authentication flow using agent credentials.
+// Concurrent calls share one request.
 func Authenticate() (*config.AuthTokens, error) {
+	return renew(authenticate)
+}
+
+func authenticate() (*config.AuthTokens, error) {
 	creds, err := config.GetCredentials()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get credentials: %w", err)
 	}
+	tokens, err := requestTokens(creds.AgentID, creds.AgentSecret)
+	if err != nil {
+		return nil, err
+	}
+	if err := Activate(tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// ErrRejected is wrapped by the error of an authentication the auth
+// service turned down because of the credentials.
+var ErrRejected = errors.New("the agent ID or secret was not accepted")
+
+// AuthenticateWithCredentials authenticates with the given credentials
+// instead of the stored ones, and stores nothing, so a mistyped secret can
+// be caught before it is saved. Save the credentials, then pass the tokens
+// to Activate.
+func AuthenticateWithCredentials(agentID, secret string) (*config.AuthTokens, error) {
+	return requestTokens(agentID, secret)
+}
+
+// Activate stores tokens from a full authentication and registers the
+// agent with the trading services, which is best effort.
+func Activate(tokens *config.AuthTokens) error {
+	if err := config.SetTokens(tokens); err != nil {
+		return fmt.Errorf("failed to store tokens: %w", err)
+	}
+
+	// Register with limit orders service (non-fatal, silent)
+	_ = RegisterWithLimitOrders(tokens)
+
+	// Initialize wallet monitoring (non-fatal, silent)
+	_ = InitializeWalletMonitoring(tokens)
 
+	return nil
+}
+
+// requestTokens exchanges agent credentials for tokens.
+func requestTokens(agentID, secret string) (*config.AuthTokens, error) {
 	authURL := config.GetAuthURL()
 	if !config.IsHTTPSOrLocal(authURL) {
 		return nil, fmt.Errorf("authentication URL must use HTTPS or localhost: %s", authURL)
@@ -74,8 +136,8 @@ func Authenticate() (*config.AuthTokens, error) {
 
 	reqBody := authRequest{
 		AuthMethod:  "agent",
-		AgentID:     creds.AgentID,
-		AgentSecret: creds.AgentSecret,
+		AgentID:     agentID,
+		AgentSecret: secret,
 	}
 
 	bodyBytes, err := json.Marshal(reqBody)
@@ -84,7 +146,7 @@ func Authenticate() (*config.AuthTokens, error) {
 	}
 
 	endpoint := fmt.Sprintf("%s/user/auth/authenticate", authURL)
-	client := noRedirectClient(30 * time.Second)
+	client := client.NoRedirect(30 * time.Second)
 
 	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(bodyBytes))
 	if err != nil {
@@ -97,6 +159,9 @@ func Authenticate() (*config.AuthTokens, error) {
 		return nil, fmt.Errorf("failed to read auth response: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("authentication failed with status %d: %w", resp.StatusCode, ErrRejected)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, fmt.Errorf("authentication failed with status %d", resp.StatusCode)
 	}
@@ -126,8 +191,8 @@ func Authenticate() (*config.AuthTokens, error) {
 	tokens := &config.AuthTokens{
 		AccessToken:           authData.AccessToken,
 		RefreshToken:          authData.RefreshToken,
-		AccessTokenExpiresAt:  authData.AccessTokenExpiresAt,
-		RefreshTokenExpiresAt: authData.RefreshTokenExpiresAt,
+		AccessTokenExpiresAt:  string(authData.AccessTokenExpiresAt),
+		RefreshTokenExpiresAt: string(authData.RefreshTokenExpiresAt),
 		AgentID:               authData.AgentID,
 		AgentName:             authData.AgentName,
 		EVMAddress:            authData.EVMAddress,
@@ -137,25 +202,20 @@ func Authenticate() (*config.AuthTokens, error) {
 
 	logger.Debug("authenticated successfully", "agent", tokens.AgentName, "agentId", tokens.AgentID)
 
-	if err := config.SetTokens(tokens); err != nil {
-		return nil, fmt.Errorf("failed to store tokens: %w", err)
-	}
-
-	// Register with limit orders service (non-fatal, silent)
-	_ = RegisterWithLimitOrders(tokens)
-
-	// Initialize wallet monitoring (non-fatal, silent)
-	_ = InitializeWalletMonitoring(tokens)
-
 	return tokens, nil
 }
 
 // RefreshTokens attempts to refresh the access token using the refresh token.
+// Concurrent calls share one request.
 func RefreshTokens() (*config.AuthTokens, error) {
+	return renew(refreshTokens)
+}
+
+func refreshTokens() (*config.AuthTokens, error) {
 	existingTokens, err := config.GetTokens()
 	if err != nil || existingTokens.RefreshToken == "" {
 		logger.Debug("no refresh token available, falling back to full authentication")
-		return Authenticate()
+		return authenticate()
 	}
 
 	authURL := config.GetAuthURL()
@@ -173,7 +233,7 @@ func RefreshTokens() (*config.AuthTokens, error) {
 	}
 
 	endpoint := fmt.Sprintf("%s/user/auth/refresh", authURL)
-	client := noRedirectClient(30 * time.Second)
+	client := client.NoRedirect(30 * time.Second)
 
 	resp, err := client.Post(endpoint, "application/json", bytes.NewReader(bodyBytes))
 	if err != nil {
@@ -214,7 +274,7 @@ func RefreshTokens() (*config.AuthTokens, error) {
 	tokens := &config.AuthTokens{
 		AccessToken:           refreshData.AccessToken,
 		RefreshToken:          existingTokens.RefreshToken,
-		AccessTokenExpiresAt:  refreshData.AccessTokenExpiresAt,
+		AccessTokenExpiresAt:  string(refreshData.AccessTokenExpiresAt),
 		RefreshTokenExpiresAt: existingTokens.RefreshTokenExpiresAt,
 		AgentID:               existingTokens.AgentID,
 		AgentName:             existingTokens.AgentName,
@@ -230,6 +290,49 @@ func RefreshTokens() (*config.AuthTokens, error) {
 	return tokens, nil
 }
 
+// Renewals are serialized, and a caller that waited while another renewal
+// succeeded takes its tokens instead of starting its own, so callers that
+// notice expiry together, or all get a 401 after the machine wakes from
+// sleep, make one request.
+var (
+	renewMu  sync.Mutex
+	renewGen atomic.Int64 // bumped by every successful renewal
+)
+
+// renew gets new tokens with fn unless another caller renewed them while this
+// one waited.
+func renew(fn func() (*config.AuthTokens, error)) (*config.AuthTokens, error) {
+	gen := renewGen.Load()
+	renewMu.Lock()
+	defer renewMu.Unlock()
+	if renewGen.Load() != gen {
+		if tokens, err := config.GetTokens(); err == nil {
+			return tokens, nil
+		}
+	}
+	tokens, err := fn()
+	if err != nil {
+		Failures.Add(1)
+		return nil, err
+	}
+	renewGen.Add(1)
+	Refreshes.Add(1)
+	return tokens, nil
+}
+
+// refreshOrAuthenticate tries the refresh token first and falls back to a
+// full authentication.
+func refreshOrAuthenticate() (*config.AuthTokens, error) {
+	tokens, err := refreshTokens()
+	if err != nil {
+		logger.Debug("token refresh failed, attempting full authentication", "error", err)
+		if tokens, err = authenticate(); err != nil {
+			return nil, fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+	return tokens, nil
+}
+
 // EnsureAuthenticated checks if the current tokens are valid and refreshes or
 // re-authenticates as needed.
 func EnsureAuthenticated() (*config.AuthTokens, error) {
@@ -241,15 +344,22 @@ func EnsureAuthenticated() (*config.AuthTokens, error) {
 	}
 
 	// Token is expired or missing, try refresh first
-	tokens, err := RefreshTokens()
-	if err != nil {
-		logger.Debug("token refresh failed, attempting full authentication", "error", err)
-		tokens, err = Authenticate()
-		if err != nil {
-			return nil, fmt.Errorf("authentication failed: %w", err)
-		}
+	return renew(refreshOrAuthenticate)
+}
+
+// Refresh renews the access token before it expires, for the proxy's
+// background refresher. With full set it authenticates from scratch instead
+// of using the refresh token.
+func Refresh(full bool) (*config.AuthTokens, error) {
+	if full {
+		return Authenticate()
 	}
+	return RefreshTokens()
+}
 
-	return tokens, nil
+// Reauthenticate runs a full authentication after the backend rejected the
+// current access token. Callers rejected together share one authentication.
+func Reauthenticate() (*config.AuthTokens, error) {
+	return Authenticate()
 }
 
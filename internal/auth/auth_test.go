@@ -0,0 +1,173 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/zalando/go-keyring"
+)
+
+// fakeAuthServer answers authentication slowly enough for concurrent
+// callers to overlap, and returns the number of authentication requests
+// it has seen.
+func fakeAuthServer(t *testing.T) *atomic.Int64 {
+	t.Helper()
+	var posts atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/user/auth/authenticate" {
+			io.WriteString(w, `{}`)
+			return
+		}
+		posts.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		io.WriteString(w, `{"data":{"access_token":"access","refresh_token":"refresh",`+
+			`"access_token_expires_at":"2099-01-01T00:00:00Z","agent_id":"agent-1","agent_name":"Taro"}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	keyring.MockInit()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config.UseDir(filepath.Join(home, ".config"))
+	t.Setenv(config.EnvAllowAnyHost, "1")
+	t.Setenv(config.EnvAuthURL, srv.URL)
+	if err := config.SetCredentials("agent-1", "secret", "Taro"); err != nil {
+		t.Fatal(err)
+	}
+	return &posts
+}
+
+// Callers that find no tokens together share one authentication and all
+// get its tokens.
+func TestEnsureAuthenticatedSingleFlight(t *testing.T) {
+	posts := fakeAuthServer(t)
+
+	const callers = 20
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		got   [callers]string
+		errs  [callers]error
+	)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			tokens, err := EnsureAuthenticated()
+			if err == nil {
+				got[i] = tokens.AccessToken
+			}
+			errs[i] = err
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	for i := range callers {
+		if errs[i] != nil {
+			t.Errorf("caller %d: %v", i, errs[i])
+		} else if got[i] != "access" {
+			t.Errorf("caller %d: access token %q, want access", i, got[i])
+		}
+	}
+	if n := posts.Load(); n != 1 {
+		t.Errorf("auth server saw %d authentications, want 1", n)
+	}
+}
+
+// Rejected callers re-authenticating together make one request too.
+func TestReauthenticateSingleFlight(t *testing.T) {
+	posts := fakeAuthServer(t)
+
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if _, err := Reauthenticate(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if n := posts.Load(); n != 1 {
+		t.Errorf("auth server saw %d authentications, want 1", n)
+	}
+}
+
+// credentialServer accepts only the secret "right", and fails with a
+// server error for the secret "crash".
+func credentialServer(t *testing.T) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/user/auth/authenticate" {
+			io.WriteString(w, `{}`)
+			return
+		}
+		var body struct {
+			Secret string `json:"agent_secret"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		switch body.Secret {
+		case "right":
+			io.WriteString(w, `{"data":{"access_token":"access","refresh_token":"refresh",`+
+				`"access_token_expires_at":"2099-01-01T00:00:00Z","agent_id":"agent-1","agent_name":"Taro"}}`)
+		case "crash":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"error":"invalid credentials"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	keyring.MockInit()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config.UseDir(filepath.Join(home, ".config"))
+	t.Setenv(config.EnvAllowAnyHost, "1")
+	t.Setenv(config.EnvAuthURL, srv.URL)
+}
+
+// Credentials are checked without storing anything; only Activate stores
+// the tokens.
+func TestAuthenticateWithCredentials(t *testing.T) {
+	credentialServer(t)
+
+	if _, err := AuthenticateWithCredentials("agent-1", "wrong"); !errors.Is(err, ErrRejected) {
+		t.Errorf("wrong secret: %v, want ErrRejected", err)
+	}
+	if _, err := AuthenticateWithCredentials("agent-1", "crash"); err == nil || errors.Is(err, ErrRejected) {
+		t.Errorf("server error: %v, want an error other than ErrRejected", err)
+	}
+
+	tokens, err := AuthenticateWithCredentials("agent-1", "right")
+	if err != nil || tokens.AccessToken != "access" {
+		t.Fatalf("right secret: %+v, %v", tokens, err)
+	}
+	if _, err := config.GetTokens(); config.HasCredentials() || err == nil {
+		t.Error("stored something before Activate")
+	}
+	if err := Activate(tokens); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := config.GetTokens(); err != nil || got.AccessToken != "access" {
+		t.Errorf("tokens after Activate = %+v, %v", got, err)
+	}
+}
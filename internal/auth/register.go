@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tradeboba/boba-cli/internal/client"
 	"github.com/tradeboba/boba-cli/internal/config"
 	"github.com/tradeboba/boba-cli/internal/logger"
 )
@@ -40,7 +41,7 @@ func RegisterWithLimitOrders(tokens *config.AuthTokens) error {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokens.AccessToken))
 
-	client := noRedirectClient(10 * time.Second)
+	client := client.NoRedirect(10 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		logger.Warn("limit orders registration request failed", "error", err)
@@ -73,7 +74,7 @@ func InitializeWalletMonitoring(tokens *config.AuthTokens) error {
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokens.AccessToken))
 
-	client := noRedirectClient(10 * time.Second)
+	client := client.NoRedirect(10 * time.Second)
 	resp, err := client.Do(req)
 	if err != nil {
 		logger.Warn("wallet monitoring initialization request failed", "error", err)
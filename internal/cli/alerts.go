@@ -0,0 +1,212 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/alerts"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/tokencache"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var alertsCmd = &cobra.Command{
+	Use:   "alerts",
+	Short: "Get notified when a token's price crosses a threshold",
+	Long: "Price alerts are checked by the running proxy ('boba start'), which logs an\n" +
+		"ALERT in its dashboard and shows a desktop notification when one fires. An\n" +
+		"alert fires again only after the price has moved back past its threshold.",
+}
+
+var alertsAddCmd = &cobra.Command{
+	Use:   "add <token-or-symbol>",
+	Short: "Add a price alert",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAlertsAdd,
+}
+
+var alertsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List price alerts",
+	RunE:  runAlertsList,
+}
+
+var alertsRemoveCmd = &cobra.Command{
+	Use:   "remove <id>",
+	Short: "Remove a price alert",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runAlertsRemove,
+}
+
+var (
+	flagAlertAbove float64
+	flagAlertBelow float64
+	flagAlertChain string
+)
+
+func init() {
+	alertsAddCmd.Flags().Float64Var(&flagAlertAbove, "above", 0, "Alert when the price rises to this USD value")
+	alertsAddCmd.Flags().Float64Var(&flagAlertBelow, "below", 0, "Alert when the price falls to this USD value")
+	alertsAddCmd.Flags().StringVar(&flagAlertChain, "chain", "solana", "Chain the token is on")
+	alertsCmd.AddCommand(alertsAddCmd, alertsListCmd, alertsRemoveCmd)
+}
+
+func runAlertsAdd(cmd *cobra.Command, args []string) error {
+	chain, err := config.NormalizeChain(flagAlertChain)
+	if err != nil {
+		return err
+	}
+
+	a := alerts.Alert{Chain: chain, Above: flagAlertAbove, Below: flagAlertBelow}
+	if err := a.ValidThresholds(); err != nil {
+		return err
+	}
+	token := args[0]
+	if isTokenAddress(token) {
+		a.Address = token
+		a.Symbol, _ = tokencache.Default.Symbol(token)
+	} else {
+		if !config.HasCredentials() {
+			return fmt.Errorf("no credentials configured. Run 'boba login' first, or pass the token address")
+		}
+		a.Symbol = strings.ToUpper(strings.TrimPrefix(token, "$"))
+		ctx, stop := interruptible(cmd)
+		defer stop()
+		err := ui.RunWithSpinner(fmt.Sprintf("Looking up %s...", a.Symbol), func() error {
+			var err error
+			a.Address, err = lookupTokenAddress(ctx, a.Symbol, chain)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	a, err = alerts.Add(a)
+	if err != nil {
+		return err
+	}
+
+	if !ui.Decorate() {
+		printAlertsPlain([]alerts.Alert{a})
+		return nil
+	}
+	ui.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.DimStyle.Render("Alert "+a.ID+" set for ") +
+		ui.BrightStyle.Render(a.Label()) + ui.DimStyle.Render(" ("+alertThresholds(a)+")"))
+	if _, running := config.ReadProxyLock(); !running {
+		ui.Println("  " + ui.DimStyle.Render("Alerts are checked while the proxy runs. Start it with 'boba start'."))
+	}
+	return nil
+}
+
+// lookupTokenAddress finds the address of the token with the given symbol
+// on chain.
+func lookupTokenAddress(ctx context.Context, symbol, chain string) (string, error) {
+	body, err := proxy.CallToolDirect(ctx, "search_tokens", map[string]any{"query": symbol, "chain": chain})
+	if err != nil {
+		return "", fmt.Errorf("looking up %s: %w", symbol, err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return "", fmt.Errorf("looking up %s: %w", symbol, err)
+	}
+	address, ok := formatter.FindToken(data, symbol)
+	if !ok {
+		return "", fmt.Errorf("no token with symbol %s found on %s; pass its address instead", symbol, chain)
+	}
+	return address, nil
+}
+
+// isTokenAddress tells a token address from a symbol.
+func isTokenAddress(s string) bool {
+	return strings.HasPrefix(s, "0x") || len(s) >= 32
+}
+
+func runAlertsList(cmd *cobra.Command, args []string) error {
+	list, err := alerts.List()
+	if err != nil {
+		return err
+	}
+	if !ui.Decorate() {
+		printAlertsPlain(list)
+		return nil
+	}
+	if len(list) == 0 {
+		ui.Println("No price alerts. Add one with 'boba alerts add <token> --above <price>'.")
+		return nil
+	}
+
+	col := func(w int) lipgloss.Style { return lipgloss.NewStyle().Width(w) }
+	ui.Println()
+	ui.Println("  " + lipgloss.JoinHorizontal(lipgloss.Top,
+		col(5).Bold(true).Render("ID"),
+		col(14).Bold(true).Render("Token"),
+		col(10).Bold(true).Render("Chain"),
+		col(14).Bold(true).Render("Above"),
+		col(14).Bold(true).Render("Below")))
+	for _, a := range list {
+		ui.Println("  " + lipgloss.JoinHorizontal(lipgloss.Top,
+			col(5).Foreground(ui.ColorBright).Render(a.ID),
+			col(14).MaxWidth(14).Render(alertToken(a)),
+			col(10).Render(a.Chain),
+			col(14).Render(alertPrice(a.Above)),
+			col(14).Render(alertPrice(a.Below))))
+	}
+	ui.Println()
+	return nil
+}
+
+// printAlertsPlain writes one tab-separated line per alert.
+func printAlertsPlain(list []alerts.Alert) {
+	for _, a := range list {
+		ui.Printf("%s\t%s\t%s\t%s\t%s\t%s\n", a.ID, a.Address, a.Symbol, a.Chain,
+			strconv.FormatFloat(a.Above, 'g', -1, 64), strconv.FormatFloat(a.Below, 'g', -1, 64))
+	}
+}
+
+func runAlertsRemove(cmd *cobra.Command, args []string) error {
+	if err := alerts.Remove(args[0]); err != nil {
+		return err
+	}
+	if !ui.Decorate() {
+		ui.Field("removed", args[0])
+		return nil
+	}
+	ui.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.DimStyle.Render("Removed alert ") + ui.BrightStyle.Render(args[0]))
+	return nil
+}
+
+// alertToken labels an alert's token by symbol, or its shortened address.
+func alertToken(a alerts.Alert) string {
+	if a.Symbol != "" {
+		return a.Symbol
+	}
+	return formatter.TruncateAddress(a.Address)
+}
+
+// alertPrice renders a threshold, or a dash when it is unset.
+func alertPrice(p float64) string {
+	if p == 0 {
+		return ui.DimStyle.Render("—")
+	}
+	return "$" + strconv.FormatFloat(p, 'g', -1, 64)
+}
+
+// alertThresholds describes an alert's thresholds in words.
+func alertThresholds(a alerts.Alert) string {
+	var parts []string
+	if a.Above > 0 {
+		parts = append(parts, "above "+alertPrice(a.Above))
+	}
+	if a.Below > 0 {
+		parts = append(parts, "below "+alertPrice(a.Below))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,362 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/client"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit [address...]",
+	Short: "Check tokens for security risks",
+	Long: "Audit tokens for honeypots, mint and freeze authority, holder concentration and\n" +
+		"liquidity locks. One address is audited in full; several, or a --file with one\n" +
+		"address per line, are audited in batches and summarized by risk. With\n" +
+		"--fail-on, the exit code is non-zero when a token is at that risk or above, or\n" +
+		"couldn't be audited, so the command can gate a script.",
+	Example: "  boba audit DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263\n" +
+		"  boba audit --chain base --addresses 0xabc...,0xdef...\n" +
+		"  boba audit --file tokens.txt --fail-on high --json",
+	RunE: runAudit,
+	// Errors are printed by runAudit in the error style.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+var (
+	flagAuditChain     string
+	flagAuditFile      string
+	flagAuditAddresses []string
+	flagAuditJSON      bool
+	flagAuditFailOn    string
+)
+
+// defaultAuditBatchSize is how many tokens go in one audit_tokens_batch
+// call when the tool's schema doesn't say how many it accepts.
+const defaultAuditBatchSize = 10
+
+// auditRisks are the risk levels in increasing order.
+var auditRisks = []string{"LOW", "MEDIUM", "HIGH"}
+
+func init() {
+	auditCmd.Flags().StringVar(&flagAuditChain, "chain", "solana", "Chain the tokens are on")
+	auditCmd.Flags().StringVar(&flagAuditFile, "file", "", "File with one token address per line (- for stdin); # starts a comment")
+	auditCmd.Flags().StringSliceVar(&flagAuditAddresses, "addresses", nil, "Comma-separated token addresses")
+	auditCmd.Flags().BoolVar(&flagAuditJSON, "json", false, "Print the audit results as JSON")
+	auditCmd.Flags().StringVar(&flagAuditFailOn, "fail-on", "", "Exit non-zero when a token is at this risk or above: high or medium")
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	err := auditTokens(cmd, args)
+	if err != nil {
+		ui.Errorln(ui.ErrorStyle.Render("Error: " + err.Error()))
+	}
+	return err
+}
+
+func auditTokens(cmd *cobra.Command, args []string) error {
+	failOn := strings.ToUpper(flagAuditFailOn)
+	if failOn != "" && failOn != "HIGH" && failOn != "MEDIUM" {
+		return fmt.Errorf("invalid --fail-on %q, expected high or medium", flagAuditFailOn)
+	}
+	chain, err := config.NormalizeChain(flagAuditChain)
+	if err != nil {
+		return err
+	}
+	addresses, err := auditAddresses(args, flagAuditAddresses, flagAuditFile)
+	if err != nil {
+		return err
+	}
+	if !config.HasCredentials() {
+		return fmt.Errorf("no credentials configured. Run 'boba login' first")
+	}
+	ctx, stop := interruptible(cmd)
+	defer stop()
+
+	// A single address named on the command line gets the full report.
+	if len(addresses) == 1 && flagAuditFile == "" {
+		return auditOne(ctx, addresses[0], chain, failOn)
+	}
+	return auditBatch(ctx, addresses, chain, failOn)
+}
+
+// auditAddresses collects the addresses to audit from the arguments,
+// --addresses and --file, dropping blanks and duplicates.
+func auditAddresses(args, list []string, file string) ([]string, error) {
+	all := append(append([]string{}, args...), list...)
+	if file != "" {
+		var r io.Reader = os.Stdin
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			r = f
+		}
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line, _, _ := strings.Cut(scanner.Text(), "#")
+			all = append(all, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+	}
+
+	var addresses []string
+	seen := make(map[string]bool)
+	for _, a := range all {
+		a = strings.TrimSpace(a)
+		if a == "" || seen[a] {
+			continue
+		}
+		seen[a] = true
+		addresses = append(addresses, a)
+	}
+	if len(addresses) == 0 {
+		if file != "" {
+			return nil, fmt.Errorf("no token addresses in %s", file)
+		}
+		return nil, fmt.Errorf("no token addresses given; pass them as arguments, with --addresses or with --file")
+	}
+	return addresses, nil
+}
+
+// auditOne audits a single token with audit_token.
+func auditOne(ctx context.Context, address, chain, failOn string) error {
+	var body []byte
+	err := ui.RunWithSpinner("Auditing token...", func() error {
+		var err error
+		body, err = proxy.CallToolDirect(ctx, "audit_token", map[string]any{"token": address, "chain": chain})
+		return err
+	})
+	var upstream *client.UpstreamError
+	if errors.As(err, &upstream) {
+		return fmt.Errorf("audit failed with status %d: %s", upstream.Status, upstream.Body)
+	}
+	if err != nil {
+		return err
+	}
+	var data map[string]any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Errorf("failed to parse audit: %w", err)
+	}
+	risk := auditRisk(data)
+
+	switch {
+	case flagAuditJSON:
+		printIndentedJSON(body)
+	case ui.Decorate():
+		ui.Println(formatter.FormatAuditToken(data))
+	default:
+		ui.Printf("%s\t%s\n", address, risk)
+	}
+	if atOrAbove(risk, failOn) {
+		return fmt.Errorf("%s is at %s risk", address, risk)
+	}
+	return nil
+}
+
+// auditBatch audits tokens with audit_tokens_batch, as many per call as the
+// tool accepts. A call that fails marks its tokens as failed; the others
+// are still audited.
+func auditBatch(ctx context.Context, addresses []string, chain, failOn string) error {
+	param, size := auditBatchParams(ctx)
+
+	var audits []map[string]any
+	var failed []formatter.AuditFailure
+	for start := 0; start < len(addresses); start += size {
+		chunk := addresses[start:min(start+size, len(addresses))]
+		label := fmt.Sprintf("Auditing tokens %d–%d of %d...", start+1, start+len(chunk), len(addresses))
+		var body []byte
+		err := ui.RunWithSpinner(label, func() error {
+			var err error
+			body, err = proxy.CallToolDirect(ctx, "audit_tokens_batch", map[string]any{param: chunk, "chain": chain})
+			return err
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var data map[string]any
+		if err == nil {
+			err = json.Unmarshal(body, &data)
+		}
+		if err != nil {
+			for _, a := range chunk {
+				failed = append(failed, formatter.AuditFailure{Token: a, Reason: auditChunkError(err)})
+			}
+			continue
+		}
+		ok, bad := formatter.SplitAuditBatch(data)
+		audits = append(audits, ok...)
+		failed = append(failed, bad...)
+	}
+
+	counts := make(map[string]int)
+	for _, a := range audits {
+		counts[auditRisk(a)]++
+	}
+	errs := make([]any, len(failed))
+	for i, f := range failed {
+		errs[i] = map[string]any{"token": f.Token, "error": f.Reason}
+	}
+	merged := map[string]any{"chain": chain, "count": len(audits), "audits": toAny(audits), "errors": errs}
+
+	switch {
+	case flagAuditJSON:
+		summary := map[string]any{"failed": len(failed)}
+		for _, r := range auditRisks {
+			summary[strings.ToLower(r)] = counts[r]
+		}
+		merged["summary"] = summary
+		data, _ := json.Marshal(merged)
+		printIndentedJSON(data)
+	case ui.Decorate():
+		ui.Println(formatter.FormatAuditBatch(merged))
+		ui.Println("  " + auditSummary(counts, len(failed)))
+	default:
+		for _, a := range audits {
+			ui.Printf("%s\t%s\n", a["token"], auditRisk(a))
+		}
+		for _, f := range failed {
+			ui.Printf("%s\tFAILED\t%s\n", f.Token, f.Reason)
+		}
+	}
+
+	var risky int
+	for r, n := range counts {
+		if atOrAbove(r, failOn) {
+			risky += n
+		}
+	}
+	switch {
+	case len(audits) == 0:
+		return fmt.Errorf("none of the %d tokens could be audited", len(addresses))
+	case risky > 0:
+		return fmt.Errorf("%d of %d tokens at %s risk or above", risky, len(addresses), failOn)
+	case failOn != "" && len(failed) > 0:
+		// An unknown risk is never read as safe.
+		return fmt.Errorf("%d of %d tokens couldn't be audited", len(failed), len(addresses))
+	}
+	return nil
+}
+
+// auditBatchParams returns the argument audit_tokens_batch takes its
+// addresses in and how many it accepts, from the tool's input schema when
+// the backend publishes one.
+func auditBatchParams(ctx context.Context) (param string, size int) {
+	param, size = "tokens", defaultAuditBatchSize
+	body, err := proxy.ListToolsDirect(ctx)
+	if err != nil {
+		return param, size
+	}
+	var manifest struct {
+		Tools []struct {
+			Name        string `json:"name"`
+			InputSchema struct {
+				Properties map[string]struct {
+					Type     string `json:"type"`
+					MaxItems int    `json:"maxItems"`
+				} `json:"properties"`
+			} `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	if json.Unmarshal(body, &manifest) != nil {
+		return param, size
+	}
+	for _, t := range manifest.Tools {
+		if t.Name != "audit_tokens_batch" {
+			continue
+		}
+		for name, p := range t.InputSchema.Properties {
+			if p.Type != "array" {
+				continue
+			}
+			param = name
+			if p.MaxItems > 0 {
+				size = p.MaxItems
+			}
+			break
+		}
+	}
+	return param, size
+}
+
+// auditChunkError describes why a batch call failed, for each of its tokens.
+func auditChunkError(err error) string {
+	var upstream *client.UpstreamError
+	if errors.As(err, &upstream) {
+		return fmt.Sprintf("batch failed with status %d", upstream.Status)
+	}
+	return "batch failed: " + err.Error()
+}
+
+// auditRisk returns an audit's risk level in upper case.
+func auditRisk(audit map[string]any) string {
+	risk, _ := audit["risk_level"].(string)
+	return strings.ToUpper(risk)
+}
+
+// atOrAbove reports whether risk is at threshold or higher. An empty
+// threshold is never reached.
+func atOrAbove(risk, threshold string) bool {
+	if threshold == "" {
+		return false
+	}
+	r, t := -1, -1
+	for i, level := range auditRisks {
+		if level == risk {
+			r = i
+		}
+		if level == threshold {
+			t = i
+		}
+	}
+	return r >= 0 && r >= t
+}
+
+// auditSummary counts the audited tokens by risk.
+func auditSummary(counts map[string]int, failed int) string {
+	parts := []string{
+		ui.ErrorStyle.Render(fmt.Sprintf("HIGH %d", counts["HIGH"])),
+		ui.GoldStyle.Render(fmt.Sprintf("MEDIUM %d", counts["MEDIUM"])),
+		ui.SuccessStyle.Render(fmt.Sprintf("LOW %d", counts["LOW"])),
+	}
+	if failed > 0 {
+		parts = append(parts, ui.DimStyle.Render(fmt.Sprintf("failed %d", failed)))
+	}
+	return strings.Join(parts, ui.DimStyle.Render(" · "))
+}
+
+// printIndentedJSON prints a JSON document indented, or as is if it isn't
+// valid JSON.
+func printIndentedJSON(data []byte) {
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, data, "", "  "); err != nil {
+		ui.Println(string(data))
+		return
+	}
+	ui.Println(pretty.String())
+}
+
+func toAny(audits []map[string]any) []any {
+	out := make([]any, len(audits))
+	for i, a := range audits {
+		out[i] = a
+	}
+	return out
+}
@@ -23,7 +23,7 @@ func runAuth(cmd *cobra.Command, args []string) error {
 	}
 
 	ui.PrintLogo()
-	fmt.Println()
+	ui.Decor()
 
 	var tokens *config.AuthTokens
 	err := ui.RunWithSpinner("Authenticating with Boba network...", func() error {
@@ -32,11 +32,16 @@ func runAuth(cmd *cobra.Command, args []string) error {
 		return authErr
 	})
 	if err != nil {
-		fmt.Println()
-		fmt.Println(ui.ErrorBox("Authentication failed: " + err.Error()))
+		ui.Decor()
+		ui.Decor(ui.ErrorBox("Authentication failed: " + err.Error()))
 		return err
 	}
 
+	if !ui.Decorate() {
+		printTokensPlain(tokens)
+		return nil
+	}
+
 	fmt.Println()
 
 	lines := buildAuthResultLines(tokens)
@@ -45,6 +50,21 @@ func runAuth(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// printTokensPlain writes the authenticated agent details as key/value lines.
+func printTokensPlain(tokens *config.AuthTokens) {
+	if tokens.AgentName != "" {
+		ui.Field("agent", tokens.AgentName)
+	}
+	ui.Field("agent_id", tokens.AgentID)
+	if tokens.EVMAddress != "" {
+		ui.Field("evm", tokens.EVMAddress)
+	}
+	if tokens.SolanaAddress != "" {
+		ui.Field("solana", tokens.SolanaAddress)
+	}
+	ui.Field("expires", tokens.AccessTokenExpiresAt)
+}
+
 func buildAuthResultLines(tokens *config.AuthTokens) []string {
 	var lines []string
 
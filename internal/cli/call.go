@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/client"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/orders"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var callCmd = &cobra.Command{
+	Use:   "call <tool>",
+	Short: "Call a tool directly, without Claude",
+	Long: "Call any Boba tool from the shell, without starting the proxy. Arguments are\n" +
+		"given as --arg key=value (always a string) or --arg key:=<json> for numbers,\n" +
+		"booleans and objects, on top of any --json-args. Missing wallet and chain\n" +
+		"parameters are filled in as for agents. Exits non-zero when the call fails.",
+	Example: "  boba call get_token_price --arg address=So11111111111111111111111111111111111111112\n" +
+		"  boba call search_tokens --arg query=BONK --arg limit:=5 --raw\n" +
+		"  boba call --list",
+	Args: func(cmd *cobra.Command, args []string) error {
+		if flagCallList {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	RunE: runCall,
+	// Errors are printed by runCall in the error style.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+var (
+	flagCallArgs     []string
+	flagCallJSONArgs string
+	flagCallRaw      bool
+	flagCallList     bool
+)
+
+func init() {
+	callCmd.Flags().StringArrayVar(&flagCallArgs, "arg", nil, "Tool argument as key=value, or key:=<json> for a typed value (repeatable)")
+	callCmd.Flags().StringVar(&flagCallJSONArgs, "json-args", "", "Tool arguments as a JSON object")
+	callCmd.Flags().BoolVar(&flagCallRaw, "raw", false, "Print the response JSON as received")
+	callCmd.Flags().BoolVar(&flagCallList, "list", false, "List the tools the backend offers, by category")
+}
+
+func runCall(cmd *cobra.Command, args []string) error {
+	err := callTool(cmd, args)
+	if err != nil {
+		ui.Errorln(ui.ErrorStyle.Render("Error: " + err.Error()))
+	}
+	return err
+}
+
+func callTool(cmd *cobra.Command, args []string) error {
+	if !config.HasCredentials() {
+		return fmt.Errorf("no credentials configured. Run 'boba login' first")
+	}
+	ctx, stop := interruptible(cmd)
+	defer stop()
+
+	if flagCallList {
+		var body []byte
+		err := ui.RunWithSpinner("Fetching tools...", func() error {
+			var err error
+			body, err = proxy.ListToolsDirect(ctx)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if flagCallRaw {
+			ui.Println(string(body))
+			return nil
+		}
+		return printToolList(body)
+	}
+
+	tool := args[0]
+	toolArgs, err := parseCallArgs(flagCallJSONArgs, flagCallArgs)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	err = ui.RunWithSpinner(fmt.Sprintf("Calling %s...", tool), func() error {
+		var err error
+		body, err = proxy.CallToolDirect(ctx, tool, toolArgs)
+		return err
+	})
+	var upstream *client.UpstreamError
+	if errors.As(err, &upstream) {
+		// The backend's error body usually says what was wrong with the call.
+		printCallResult(tool, []byte(upstream.Body), true)
+		return fmt.Errorf("%s failed with status %d", tool, upstream.Status)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !flagCallRaw && ui.Decorate() {
+		body = withCurrentPrices(ctx, tool, body)
+	}
+	printCallResult(tool, body, false)
+	if callFailed(body) {
+		return fmt.Errorf("%s reported failure", tool)
+	}
+	return nil
+}
+
+// parseCallArgs builds the tool arguments from --json-args and then each
+// --arg, later ones overriding earlier ones. key=value is always a string;
+// key:=value is parsed as JSON, so limit:=5 is a number.
+func parseCallArgs(jsonArgs string, pairs []string) (map[string]any, error) {
+	args := make(map[string]any)
+	if jsonArgs != "" {
+		if err := json.Unmarshal([]byte(jsonArgs), &args); err != nil {
+			return nil, fmt.Errorf("--json-args must be a JSON object: %w", err)
+		}
+		if args == nil {
+			args = make(map[string]any)
+		}
+	}
+	for _, pair := range pairs {
+		eq := strings.Index(pair, "=")
+		if eq <= 0 {
+			return nil, fmt.Errorf("invalid --arg %q, expected key=value or key:=<json>", pair)
+		}
+		key, value := pair[:eq], pair[eq+1:]
+		if !strings.HasSuffix(key, ":") {
+			args[key] = value
+			continue
+		}
+		key = strings.TrimSuffix(key, ":")
+		if key == "" {
+			return nil, fmt.Errorf("invalid --arg %q, expected key:=<json>", pair)
+		}
+		var typed any
+		if err := json.Unmarshal([]byte(value), &typed); err != nil {
+			return nil, fmt.Errorf("invalid --arg %s: %q is not JSON (use %s=%s for a string)", key, value, key, value)
+		}
+		args[key] = typed
+	}
+	return args, nil
+}
+
+// printCallResult prints a tool's response: as received with --raw, with
+// the tool's formatter when it has one, and as indented JSON otherwise.
+// Failed calls are never run through a formatter.
+func printCallResult(tool string, body []byte, failed bool) {
+	if flagCallRaw {
+		ui.Println(string(body))
+		return
+	}
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		ui.Println(string(body))
+		return
+	}
+	if !failed && ui.Decorate() {
+		if out := formatter.FormatToolResult(tool, data); out != "" {
+			ui.Println(out)
+			return
+		}
+	}
+	printIndentedJSON(body)
+}
+
+// withCurrentPrices adds the market price to the limit orders in a
+// get_limit_orders or get_limit_order response, so the formatter can show
+// how far each order is from its trigger. The body is returned unchanged
+// when there is nothing to add.
+func withCurrentPrices(ctx context.Context, tool string, body []byte) []byte {
+	if tool != "get_limit_orders" && tool != "get_limit_order" {
+		return body
+	}
+	var data map[string]any
+	if json.Unmarshal(body, &data) != nil {
+		return body
+	}
+	var list []map[string]any
+	if tool == "get_limit_order" {
+		list = append(list, data)
+	} else {
+		raw, _ := data["orders"].([]any)
+		for _, o := range raw {
+			if order, ok := o.(map[string]any); ok {
+				list = append(list, order)
+			}
+		}
+	}
+	orders.FillCurrentPrices(ctx, proxy.CallToolDirect, list)
+	out, err := json.Marshal(data)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+// callFailed reports whether a 2xx response still says the tool failed,
+// as { "success": false, ... }.
+func callFailed(body []byte) bool {
+	var result struct {
+		Success *bool `json:"success"`
+	}
+	return json.Unmarshal(body, &result) == nil && result.Success != nil && !*result.Success
+}
+
+// printToolList prints the tool manifest grouped by category.
+func printToolList(body []byte) error {
+	var manifest struct {
+		Tools []struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return fmt.Errorf("failed to parse tool list: %w", err)
+	}
+
+	groups := make(map[string][]string)
+	descriptions := make(map[string]string)
+	for _, t := range manifest.Tools {
+		category := ui.ToolCategory(t.Name)
+		groups[category] = append(groups[category], t.Name)
+		descriptions[t.Name] = firstLine(t.Description)
+	}
+	categories := make([]string, 0, len(groups))
+	for c := range groups {
+		categories = append(categories, c)
+	}
+	slices.Sort(categories)
+
+	for _, c := range categories {
+		names := groups[c]
+		slices.Sort(names)
+		if !ui.Decorate() {
+			for _, name := range names {
+				ui.Printf("%s\t%s\t%s\n", c, name, descriptions[name])
+			}
+			continue
+		}
+		ui.Println()
+		ui.Println("  " + ui.ToolTag(names[0]))
+		for _, name := range names {
+			ui.Println("  " + ui.BrightStyle.Render(fmt.Sprintf("%-28s", name)) + " " + ui.DimStyle.Render(descriptions[name]))
+		}
+	}
+	if ui.Decorate() {
+		ui.Println()
+	}
+	return nil
+}
+
+// firstLine returns the first line of s, trimmed.
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var configChainsCmd = &cobra.Command{
+	Use:   "chains [chain...]",
+	Short: "Choose which chains Boba shows and touches",
+	Long: "Choose which chains appear in the proxy tabs, portfolio output and --chain flags.\n" +
+		"With no arguments an interactive picker is shown; otherwise the listed chains become the enabled set.",
+	RunE: runConfigChains,
+}
+
+var flagChainsAll bool
+
+func init() {
+	configChainsCmd.Flags().BoolVar(&flagChainsAll, "all", false, "Enable every chain")
+	configCmd.AddCommand(configChainsCmd)
+}
+
+func runConfigChains(cmd *cobra.Command, args []string) error {
+	var selected []string
+
+	switch {
+	case flagChainsAll:
+		// nil clears the list, which enables everything
+	case len(args) > 0:
+		selected = args
+	case ui.Decorate():
+		var err error
+		if selected, err = pickChains(); err != nil {
+			return err
+		}
+	default:
+		printEnabledChains()
+		return nil
+	}
+
+	if err := config.SetEnabledChains(selected); err != nil {
+		return err
+	}
+
+	printEnabledChains()
+	return nil
+}
+
+// pickChains shows a multi-select of every chain, preselecting enabled ones.
+func pickChains() ([]string, error) {
+	var options []huh.Option[string]
+	for _, c := range config.Chains {
+		options = append(options, huh.NewOption(c.Name, c.Slug).Selected(config.IsChainEnabled(c.Slug)))
+	}
+
+	var selected []string
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Which chains should Boba use?").
+				Options(options...).
+				Validate(func(s []string) error {
+					if len(s) == 0 {
+						return fmt.Errorf("select at least one chain")
+					}
+					return nil
+				}).
+				Value(&selected),
+		),
+	).WithTheme(ui.BobaTheme())
+
+	if err := form.Run(); err != nil {
+		return nil, fmt.Errorf("selection cancelled")
+	}
+	return selected, nil
+}
+
+func printEnabledChains() {
+	var names []string
+	for _, c := range config.Chains {
+		if config.IsChainEnabled(c.Slug) {
+			names = append(names, c.Slug)
+		}
+	}
+	if !ui.Decorate() {
+		ui.Field("enabled_chains", strings.Join(names, ","))
+		return
+	}
+	fmt.Println()
+	fmt.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.DimStyle.Render("Enabled chains: ") + ui.BrightStyle.Render(strings.Join(names, ", ")))
+	fmt.Println()
+}
@@ -2,12 +2,16 @@ package cli
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/client"
 	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/proxy"
 	"github.com/tradeboba/boba-cli/internal/ui"
 )
 
@@ -18,11 +22,28 @@ var configCmd = &cobra.Command{
 }
 
 var (
-	flagMCPURL  string
-	flagAuthURL string
-	flagCfgPort string
-	flagReset   bool
-	flagForce   bool
+	flagMCPURL      string
+	flagAuthURL     string
+	flagCfgPort     string
+	flagReset       bool
+	flagForce       bool
+	flagAccessible  bool
+	flagSlowTerm    bool
+	flagHeartbeat   int
+	flagLogHistory  int
+	flagAlertEvery  int
+	flagExplorerKey string
+	flagToolBudget  int
+	flagToolCap     int
+	flagCfgMaxResp  int
+	flagCfgRate     string
+	flagDust        float64
+	flagCurrency    string
+	flagTimeouts    []string
+
+	flagConfirmTrades bool
+	flagAuditTrail    bool
+	flagUpdateCheck   bool
 )
 
 func init() {
@@ -31,6 +52,22 @@ func init() {
 	configCmd.Flags().StringVar(&flagCfgPort, "port", "", "Set default proxy port")
 	configCmd.Flags().BoolVar(&flagReset, "reset", false, "Reset all config to defaults")
 	configCmd.Flags().BoolVar(&flagForce, "force", false, "Skip URL validation")
+	configCmd.Flags().BoolVar(&flagAccessible, "accessible", false, "Screen-reader friendly output (--accessible=false to disable)")
+	configCmd.Flags().BoolVar(&flagSlowTerm, "slow-terminal", false, "Static menus and no animations, for slow SSH links (--slow-terminal=false to disable)")
+	configCmd.Flags().StringVar(&flagExplorerKey, "explorer-key", "", "Set a block explorer API key as chain=KEY (empty KEY removes it)")
+	configCmd.Flags().IntVar(&flagHeartbeat, "heartbeat", 0, "Proxy dashboard refresh interval in seconds (0 for default)")
+	configCmd.Flags().IntVar(&flagLogHistory, "log-history", 0, "Requests kept in the proxy dashboard's activity log (0 for default)")
+	configCmd.Flags().IntVar(&flagAlertEvery, "alert-interval", 0, "Seconds between price checks for 'boba alerts' (0 for default)")
+	configCmd.Flags().IntVar(&flagToolBudget, "tool-budget", 0, "Tool calls per agent session before the agent is warned (0 for default)")
+	configCmd.Flags().BoolVar(&flagConfirmTrades, "confirm-trades", false, "Hold swaps and order changes until confirmed in the proxy dashboard (--confirm-trades=false to disable)")
+	configCmd.Flags().BoolVar(&flagUpdateCheck, "update-check", true, "Look for a newer release once a day on 'boba start' and 'boba status' (--update-check=false to disable)")
+	configCmd.Flags().BoolVar(&flagAuditTrail, "audit", false, "Record the full arguments and response of swaps and order changes (--audit=false to disable)")
+	configCmd.Flags().IntVar(&flagToolCap, "tool-cap", 0, "Tool calls per agent session after which calls are refused (0 disables)")
+	configCmd.Flags().IntVar(&flagCfgMaxResp, "max-response-size", 0, "Size in MB above which a backend response fails the tool call (0 for default)")
+	configCmd.Flags().Float64Var(&flagDust, "dust", 0, "Hide dashboard positions worth less than this, in the display currency (0 shows all)")
+	configCmd.Flags().StringVar(&flagCurrency, "currency", "", "Show values in this currency: "+strings.Join(formatter.CurrencyCodes(), ", "))
+	configCmd.Flags().StringArrayVar(&flagTimeouts, "timeout", nil, "Tool call timeout as category=seconds, categories: "+strings.Join(proxy.TimeoutCategories, ", ")+" (0 for default, repeatable)")
+	configCmd.Flags().StringVar(&flagCfgRate, "rate-limit", "", "Per-tool calls per second and in-flight cap, e.g. 5,burst=10,inflight=8 (off disables)")
 }
 
 func runConfig(cmd *cobra.Command, args []string) error {
@@ -67,12 +104,237 @@ func runConfig(cmd *cobra.Command, args []string) error {
 		changed = true
 	}
 
+	if cmd.Flags().Changed("accessible") {
+		if err := config.SetAccessible(flagAccessible); err != nil {
+			return fmt.Errorf("failed to set accessible mode: %w", err)
+		}
+		ui.SetAccessible(flagAccessible)
+		changed = true
+	}
+
+	if cmd.Flags().Changed("slow-terminal") {
+		if err := config.SetSlowTerminal(flagSlowTerm); err != nil {
+			return fmt.Errorf("failed to set slow terminal mode: %w", err)
+		}
+		ui.SetSlowTerminal(flagSlowTerm)
+		changed = true
+	}
+
+	if cmd.Flags().Changed("confirm-trades") {
+		if err := config.SetConfirmTrades(flagConfirmTrades); err != nil {
+			return fmt.Errorf("failed to set trade confirmation: %w", err)
+		}
+		changed = true
+	}
+
+	if cmd.Flags().Changed("update-check") {
+		if err := config.SetUpdateCheck(flagUpdateCheck); err != nil {
+			return fmt.Errorf("failed to set the update check: %w", err)
+		}
+		changed = true
+	}
+
+	if cmd.Flags().Changed("audit") {
+		if err := config.SetAuditTrail(flagAuditTrail); err != nil {
+			return fmt.Errorf("failed to set the audit trail: %w", err)
+		}
+		changed = true
+	}
+
+	if cmd.Flags().Changed("dust") {
+		if err := config.SetDustThreshold(flagDust); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if flagCurrency != "" {
+		c, ok := formatter.LookupCurrency(flagCurrency)
+		if !ok {
+			return fmt.Errorf("unsupported currency %q, expected one of %s", flagCurrency, strings.Join(formatter.CurrencyCodes(), ", "))
+		}
+		if err := config.SetDisplayCurrency(c.Code); err != nil {
+			return fmt.Errorf("failed to set the display currency: %w", err)
+		}
+		formatter.SetDisplayCurrency(c)
+		changed = true
+	}
+
+	for _, t := range flagTimeouts {
+		category, secs, ok := strings.Cut(t, "=")
+		n, err := strconv.Atoi(secs)
+		if !ok || err != nil || !slices.Contains(proxy.TimeoutCategories, category) {
+			return fmt.Errorf("invalid --timeout %q, expected category=seconds with category one of %s", t, strings.Join(proxy.TimeoutCategories, ", "))
+		}
+		if err := config.SetTimeout(category, n); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if cmd.Flags().Changed("heartbeat") {
+		if err := config.SetHeartbeatSeconds(flagHeartbeat); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if cmd.Flags().Changed("log-history") {
+		if err := config.SetLogHistory(flagLogHistory); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if cmd.Flags().Changed("alert-interval") {
+		if err := config.SetAlertInterval(flagAlertEvery); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if cmd.Flags().Changed("tool-budget") {
+		if err := config.SetToolCallBudget(flagToolBudget); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if cmd.Flags().Changed("tool-cap") {
+		if err := config.SetToolCallHardCap(flagToolCap); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if cmd.Flags().Changed("max-response-size") {
+		if err := config.SetMaxResponseSize(flagCfgMaxResp); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if flagCfgRate != "" {
+		limit, err := proxy.ParseRateLimit(flagCfgRate, proxy.DefaultRateLimit())
+		if err != nil {
+			return err
+		}
+		if err := config.SetRateLimit(limit.PerSecond, limit.Burst, limit.MaxInFlight); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if flagExplorerKey != "" {
+		chain, key, ok := strings.Cut(flagExplorerKey, "=")
+		if !ok {
+			return fmt.Errorf("invalid --explorer-key %q (use chain=KEY)", flagExplorerKey)
+		}
+		c, found := config.LookupChain(chain)
+		if !found {
+			return fmt.Errorf("unknown chain %q", chain)
+		}
+		if err := config.SetExplorerAPIKey(c.Slug, key); err != nil {
+			return fmt.Errorf("failed to set explorer key: %w", err)
+		}
+		changed = true
+	}
+
+	if !ui.Decorate() {
+		printConfigPlain()
+		return nil
+	}
+
 	lines := buildConfigLines(flagReset, changed)
 	runScanReveal(lines)
 
 	return nil
 }
 
+// printConfigPlain writes the effective configuration as key/value lines.
+func printConfigPlain() {
+	ui.Field("mcp_url", config.GetMCPURL())
+	ui.Field("auth_url", config.GetAuthURL())
+	if hosts := customHostsInUse(); len(hosts) > 0 {
+		ui.Field("custom_hosts_in_use", strings.Join(hosts, ","))
+	}
+	ui.Field("proxy_port", fmt.Sprintf("%d", config.GetProxyPort()))
+	ui.Field("log_level", config.GetLogLevel())
+	ui.Field("accessible", onOff(config.GetAccessible()))
+	ui.Field("slow_terminal", onOff(ui.SlowTerminal()))
+	ui.Field("heartbeat", heartbeatLabel())
+	ui.Field("log_history", fmt.Sprintf("%d requests", config.GetLogHistory()))
+	ui.Field("alert_interval", config.GetAlertInterval().String())
+	ui.Field("tool_budget", toolBudgetLabel())
+	ui.Field("rate_limit", proxy.DefaultRateLimit().String())
+	ui.Field("max_response", maxResponseLabel())
+	ui.Field("confirm_trades", onOff(config.GetConfirmTrades()))
+	ui.Field("audit_trail", onOff(config.GetAuditTrail()))
+	ui.Field("update_check", onOff(config.GetUpdateCheck()))
+	ui.Field("currency", config.GetDisplayCurrency())
+	ui.Field("timeouts", timeoutsLabel())
+	ui.Field("dust_threshold", dustLabel())
+	ui.Field("tool_policy", config.GetToolPolicy().String())
+	ui.Field("config", config.ConfigPath())
+}
+
+// maxResponseLabel describes the largest backend response a tool call may
+// return.
+func maxResponseLabel() string {
+	n := config.GetMaxResponseSize()
+	if n == 0 {
+		return client.FormatSize(client.DefaultMaxResponseSize) + " (default)"
+	}
+	return client.FormatSize(n)
+}
+
+// heartbeatLabel describes the proxy dashboard refresh interval.
+func heartbeatLabel() string {
+	if secs := config.GetHeartbeatSeconds(); secs > 0 {
+		return fmt.Sprintf("%ds", secs)
+	}
+	return "default"
+}
+
+// toolBudgetLabel describes the per-session tool-call budget and cap.
+func toolBudgetLabel() string {
+	label := fmt.Sprintf("%d calls", config.GetToolCallBudget())
+	if hard := config.GetToolCallHardCap(); hard > 0 {
+		label += fmt.Sprintf(", capped at %d", hard)
+	}
+	return label
+}
+
+// timeoutsLabel lists the tool call timeout of each category.
+func timeoutsLabel() string {
+	overrides := config.GetTimeouts()
+	parts := make([]string, len(proxy.TimeoutCategories))
+	for i, c := range proxy.TimeoutCategories {
+		secs := int(proxy.DefaultTimeouts[c].Seconds())
+		if n := overrides[c]; n > 0 {
+			secs = n
+		}
+		parts[i] = fmt.Sprintf("%s %ds", c, secs)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// dustLabel describes the value below which the dashboard hides positions.
+func dustLabel() string {
+	t := config.GetDustThreshold()
+	if t == 0 {
+		return "off"
+	}
+	return "below " + formatter.DisplayCurrency().Format(t, 2)
+}
+
+func onOff(v bool) string {
+	if v {
+		return "on"
+	}
+	return "off"
+}
+
 func buildConfigLines(wasReset, wasChanged bool) []string {
 	var lines []string
 
@@ -111,12 +373,30 @@ func buildConfigLines(wasReset, wasChanged bool) []string {
 	val := lipgloss.NewStyle().Foreground(ui.ColorPearl)
 
 	configRows := []string{
-		fmt.Sprintf("  %s %s", label.Render("MCP URL"), val.Render(config.GetMCPURL())),
-		fmt.Sprintf("  %s %s", label.Render("Auth URL"), val.Render(config.GetAuthURL())),
-		fmt.Sprintf("  %s %s", label.Render("Proxy Port"), val.Render(fmt.Sprintf("%d", config.GetProxyPort()))),
+		fmt.Sprintf("  %s %s", label.Render("MCP URL"), val.Render(config.GetMCPURL())+envNote("mcpUrl")),
+		fmt.Sprintf("  %s %s", label.Render("Auth URL"), val.Render(config.GetAuthURL())+envNote("authUrl")),
+		fmt.Sprintf("  %s %s", label.Render("Proxy Port"), val.Render(fmt.Sprintf("%d", config.GetProxyPort()))+envNote("proxyPort")),
 		fmt.Sprintf("  %s %s", label.Render("Log Level"), val.Render(config.GetLogLevel())),
+		fmt.Sprintf("  %s %s", label.Render("Accessible"), val.Render(onOff(config.GetAccessible()))),
+		fmt.Sprintf("  %s %s", label.Render("Slow Terminal"), val.Render(onOff(ui.SlowTerminal()))),
+		fmt.Sprintf("  %s %s", label.Render("Heartbeat"), val.Render(heartbeatLabel())),
+		fmt.Sprintf("  %s %s", label.Render("Log History"), val.Render(fmt.Sprintf("%d requests", config.GetLogHistory()))),
+		fmt.Sprintf("  %s %s", label.Render("Alert Interval"), val.Render(config.GetAlertInterval().String())),
+		fmt.Sprintf("  %s %s", label.Render("Tool Budget"), val.Render(toolBudgetLabel())),
+		fmt.Sprintf("  %s %s", label.Render("Rate Limit"), val.Render(proxy.DefaultRateLimit().String())),
+		fmt.Sprintf("  %s %s", label.Render("Max Response"), val.Render(maxResponseLabel())),
+		fmt.Sprintf("  %s %s", label.Render("Confirm Trades"), val.Render(onOff(config.GetConfirmTrades()))),
+		fmt.Sprintf("  %s %s", label.Render("Audit Trail"), val.Render(onOff(config.GetAuditTrail()))),
+		fmt.Sprintf("  %s %s", label.Render("Update Check"), val.Render(onOff(config.GetUpdateCheck()))),
+		fmt.Sprintf("  %s %s", label.Render("Currency"), val.Render(config.GetDisplayCurrency())),
+		fmt.Sprintf("  %s %s", label.Render("Timeouts"), val.Render(timeoutsLabel())),
+		fmt.Sprintf("  %s %s", label.Render("Dust"), val.Render(dustLabel())),
+		fmt.Sprintf("  %s %s", label.Render("Tool Policy"), val.Render(config.GetToolPolicy().String())),
 		fmt.Sprintf("  %s %s", label.Render("Config"), val.Render(config.ConfigPath())),
 	}
+	if warning := customHostWarning(); warning != "" {
+		configRows = append(configRows, "", "  "+warning)
+	}
 
 	configCard := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
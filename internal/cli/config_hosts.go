@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"crypto/tls"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var configAllowHostCmd = &cobra.Command{
+	Use:   "allow-host <hostname>",
+	Short: "Allow the MCP and auth URLs to point at another host",
+	Long: "Add a host, such as a staging deployment, to the hosts --mcp-url and --auth-url\n" +
+		"may point at, so they can be set without --force. Pass the bare hostname, without\n" +
+		"scheme, port or path. 'boba status' shows when a URL uses an added host.",
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigAllowHost,
+}
+
+var configRemoveHostCmd = &cobra.Command{
+	Use:   "remove-host <hostname>",
+	Short: "Take an added host off the allowlist",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigRemoveHost,
+}
+
+var configListHostsCmd = &cobra.Command{
+	Use:   "list-hosts",
+	Short: "List the hosts the MCP and auth URLs may point at",
+	RunE:  runConfigListHosts,
+}
+
+func init() {
+	configCmd.AddCommand(configAllowHostCmd, configRemoveHostCmd, configListHostsCmd)
+}
+
+// httpsProbeTimeout bounds the check that an added host serves HTTPS.
+const httpsProbeTimeout = 5 * time.Second
+
+func runConfigAllowHost(cmd *cobra.Command, args []string) error {
+	host, err := config.AddAllowedHost(args[0])
+	if err != nil {
+		return err
+	}
+	var probeErr error
+	if !isLocalHost(host) {
+		_ = ui.RunWithSpinner("Checking "+host+" serves HTTPS...", func() error {
+			probeErr = probeHTTPS(host)
+			return nil
+		})
+	}
+
+	if !ui.Decorate() {
+		ui.Field("allowed", host)
+		if probeErr != nil {
+			ui.Errorln("warning: " + host + " did not answer HTTPS on port 443: " + probeErr.Error())
+		}
+		return nil
+	}
+	ui.Println()
+	ui.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.DimStyle.Render("Allowed ") + ui.BrightStyle.Render(host))
+	if probeErr != nil {
+		ui.Println("  " + ui.GoldStyle.Render("! "+host+" did not answer HTTPS on port 443: "+probeErr.Error()))
+		ui.Println("    " + ui.DimStyle.Render("Tokens are sent to this host; only point boba at it over https://."))
+	}
+	ui.Println("    " + ui.DimStyle.Render("Point boba at it with ") + ui.BrightStyle.Render("boba config --mcp-url https://"+host+"/..."))
+	ui.Println()
+	return nil
+}
+
+// isLocalHost reports whether host is this machine, which is reached over
+// plain HTTP.
+func isLocalHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// probeHTTPS checks that host completes a TLS handshake on port 443 with a
+// certificate valid for it.
+func probeHTTPS(host string) error {
+	dialer := &net.Dialer{Timeout: httpsProbeTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(host, "443"), &tls.Config{ServerName: host})
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func runConfigRemoveHost(cmd *cobra.Command, args []string) error {
+	host, err := config.RemoveAllowedHost(args[0])
+	if err != nil {
+		return err
+	}
+	if !ui.Decorate() {
+		ui.Field("removed", host)
+		return nil
+	}
+	ui.Println()
+	ui.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.DimStyle.Render("Removed ") + ui.BrightStyle.Render(host) + ui.DimStyle.Render(" from the allowlist"))
+	ui.Println()
+	return nil
+}
+
+func runConfigListHosts(cmd *cobra.Command, args []string) error {
+	custom := config.GetCustomHosts()
+	if !ui.Decorate() {
+		for _, h := range config.AllowedHosts {
+			ui.Printf("%s\tbuilt-in\n", h)
+		}
+		for _, h := range custom {
+			ui.Printf("%s\tadded\n", h)
+		}
+		return nil
+	}
+	ui.Println()
+	for _, h := range config.AllowedHosts {
+		ui.Println("  " + ui.BrightStyle.Render(h) + ui.DimStyle.Render("  built in"))
+	}
+	inUse := customHostsInUse()
+	for _, h := range custom {
+		note := "  added"
+		if slices.Contains(inUse, h) {
+			note += ", in use"
+		}
+		ui.Println("  " + ui.GoldStyle.Render(h) + ui.DimStyle.Render(note))
+	}
+	if len(custom) == 0 {
+		ui.Println()
+		ui.Println("  " + ui.DimStyle.Render("Add a host with ") + ui.BrightStyle.Render("boba config allow-host <hostname>"))
+	}
+	ui.Println()
+	return nil
+}
+
+// customHostsInUse returns the added hosts the MCP or auth URL points at.
+func customHostsInUse() []string {
+	var hosts []string
+	for _, u := range []string{config.GetMCPURL(), config.GetAuthURL()} {
+		if host, ok := config.CustomHost(u); ok && !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// customHostWarning is the status line pointing out that boba talks to a
+// host that was added to the allowlist, or "" when it doesn't.
+func customHostWarning() string {
+	hosts := customHostsInUse()
+	if len(hosts) == 0 {
+		return ""
+	}
+	return ui.GoldStyle.Render("! Using added host "+strings.Join(hosts, ", ")) +
+		ui.DimStyle.Render(". Your tokens go there; if someone asked you to add it, make sure it's yours.")
+}
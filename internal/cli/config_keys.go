@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// configKey is a setting that `boba config get/set` can read and change.
+type configKey struct {
+	name  string // as typed on the command line
+	field string // name in the config file
+	get   func() string
+	set   func(value string) error
+}
+
+var configKeys = []configKey{
+	{
+		name: "mcp-url", field: "mcpUrl",
+		get: config.GetMCPURL,
+		set: func(v string) error { return config.SetMCPURL(v, flagForce) },
+	},
+	{
+		name: "auth-url", field: "authUrl",
+		get: config.GetAuthURL,
+		set: func(v string) error { return config.SetAuthURL(v, flagForce) },
+	},
+	{
+		name: "proxy-port", field: "proxyPort",
+		get: func() string { return strconv.Itoa(config.GetProxyPort()) },
+		set: func(v string) error {
+			port, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("invalid port: %s", v)
+			}
+			return config.SetProxyPort(port)
+		},
+	},
+	{
+		name: "bind", field: "bindAddress",
+		get: config.GetBindAddress,
+		set: config.SetBindAddress,
+	},
+	{
+		name: "log-level", field: "logLevel",
+		get: config.GetLogLevel,
+		set: config.SetLogLevel,
+	},
+	{
+		name: "theme", field: "theme",
+		get: config.GetTheme,
+		set: config.SetTheme,
+	},
+	{
+		name: "max-price-impact", field: "maxPriceImpact",
+		get: func() string { return strconv.FormatFloat(config.GetMaxPriceImpact(), 'f', -1, 64) },
+		set: func(v string) error {
+			p, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+			if err != nil {
+				return fmt.Errorf("invalid price impact: %s", v)
+			}
+			return config.SetMaxPriceImpact(p)
+		},
+	},
+}
+
+// envNote marks a value that an environment variable overrides, so the
+// config file value being ignored isn't a surprise.
+func envNote(field string) string {
+	if name := config.EnvOverride(field); name != "" {
+		return ui.DimStyle.Render("  (from " + name + ")")
+	}
+	return ""
+}
+
+func lookupConfigKey(name string) (configKey, error) {
+	for _, k := range configKeys {
+		if k.name == name {
+			return k, nil
+		}
+	}
+	names := make([]string, len(configKeys))
+	for i, k := range configKeys {
+		names[i] = k.name
+	}
+	return configKey{}, fmt.Errorf("unknown setting %q (use %s)", name, strings.Join(names, ", "))
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print one setting",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change one setting",
+	Long:  "Change one setting. Keys: mcp-url, auth-url, proxy-port, bind, log-level, theme, max-price-impact.",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print every setting and where it comes from",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigList,
+}
+
+func init() {
+	configSetCmd.Flags().BoolVar(&flagForce, "force", false, "Skip URL validation")
+	configCmd.AddCommand(configGetCmd, configSetCmd, configListCmd)
+}
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	key, err := lookupConfigKey(args[0])
+	if err != nil {
+		return err
+	}
+	value := key.get()
+	if !ui.Decorate() {
+		ui.Println(value)
+		return nil
+	}
+	fmt.Println("  " + ui.BrightStyle.Render(value) + ui.DimStyle.Render("  ("+config.SettingSource(key.field)+")"))
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	key, err := lookupConfigKey(args[0])
+	if err != nil {
+		return err
+	}
+	if err := key.set(args[1]); err != nil {
+		return err
+	}
+	if name := config.EnvOverride(key.field); name != "" {
+		ui.Errorln(fmt.Sprintf("note: %s is set and takes precedence over the saved value", name))
+	}
+	if !ui.Decorate() {
+		ui.Field(key.name, args[1])
+		return nil
+	}
+	fmt.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.DimStyle.Render(key.name+" set to ") + ui.BrightStyle.Render(args[1]))
+	return nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	if !ui.Decorate() {
+		for _, k := range configKeys {
+			ui.Printf("%s\t%s\t%s\n", k.name, k.get(), config.SettingSource(k.field))
+		}
+		return nil
+	}
+	fmt.Println()
+	for _, k := range configKeys {
+		fmt.Printf("  %s %s %s\n",
+			ui.DimStyle.Render(fmt.Sprintf("%-11s", k.name)),
+			ui.BrightStyle.Render(k.get()),
+			ui.DimStyle.Render("("+config.SettingSource(k.field)+")"))
+	}
+	fmt.Println()
+	return nil
+}
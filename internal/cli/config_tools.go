@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var configToolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "Choose which tools agents may call",
+	Long: "Restrict the tools agents can call through the proxy. --read-only blocks swaps,\n" +
+		"trades and order changes; --allow permits only the listed tools; --deny blocks\n" +
+		"the listed tools in any mode. Blocked tools are hidden from the agent's tool list.\n" +
+		"With no flags the current policy is shown.",
+	RunE: runConfigTools,
+}
+
+var (
+	flagToolsAll      bool
+	flagToolsReadOnly bool
+	flagToolsAllow    []string
+	flagToolsDeny     []string
+)
+
+func init() {
+	configToolsCmd.Flags().BoolVar(&flagToolsAll, "all", false, "Allow every tool not on the deny list")
+	configToolsCmd.Flags().BoolVar(&flagToolsReadOnly, "read-only", false, "Block tools that trade or change orders")
+	configToolsCmd.Flags().StringSliceVar(&flagToolsAllow, "allow", nil, "Allow only these tools, e.g. get_portfolio,search_tokens")
+	configToolsCmd.Flags().StringSliceVar(&flagToolsDeny, "deny", nil, "Block these tools in any mode (--deny \"\" clears the list)")
+	configCmd.AddCommand(configToolsCmd)
+}
+
+func runConfigTools(cmd *cobra.Command, args []string) error {
+	modes := 0
+	for _, name := range []string{"all", "read-only", "allow"} {
+		if cmd.Flags().Changed(name) {
+			modes++
+		}
+	}
+	if modes > 1 {
+		return fmt.Errorf("use only one of --all, --read-only and --allow")
+	}
+
+	policy := config.GetToolPolicy()
+	switch {
+	case flagToolsAll:
+		policy.Mode, policy.Allow = config.ToolPolicyAll, nil
+	case flagToolsReadOnly:
+		policy.Mode, policy.Allow = config.ToolPolicyReadOnly, nil
+	case cmd.Flags().Changed("allow"):
+		policy.Mode, policy.Allow = config.ToolPolicyCustom, flagToolsAllow
+	}
+	if cmd.Flags().Changed("deny") {
+		policy.Deny = flagToolsDeny
+	}
+
+	if modes > 0 || cmd.Flags().Changed("deny") {
+		if err := config.SetToolPolicy(policy); err != nil {
+			return err
+		}
+		if lock, running := config.ReadProxyLock(); running {
+			if err := reloadProxy(lock.Port); err != nil {
+				ui.Errorln(fmt.Sprintf("could not reload the running proxy: %v (restart it with 'boba start')", err))
+			}
+		}
+	}
+
+	printToolPolicy(config.GetToolPolicy())
+	return nil
+}
+
+// printToolPolicy shows the policy's mode and lists.
+func printToolPolicy(p config.ToolPolicy) {
+	if !ui.Decorate() {
+		ui.Field("tool_policy", p.Mode)
+		ui.Field("allow", strings.Join(p.Allow, ","))
+		ui.Field("deny", strings.Join(p.Deny, ","))
+		return
+	}
+	fmt.Println()
+	fmt.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.DimStyle.Render("Tool policy: ") + ui.BrightStyle.Render(p.Mode))
+	if len(p.Allow) > 0 {
+		fmt.Println("    " + ui.DimStyle.Render("Allowed: ") + strings.Join(p.Allow, ", "))
+	}
+	if len(p.Deny) > 0 {
+		fmt.Println("    " + ui.DimStyle.Render("Denied:  ") + strings.Join(p.Deny, ", "))
+	}
+	fmt.Println()
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/logger"
+)
+
+// fxRatesURL serves USD exchange rates as { "rates": { "EUR": 0.92, ... } }.
+// BOBA_FX_URL overrides it.
+var fxRatesURL = "https://open.er-api.com/v6/latest/USD"
+
+// applyDisplayCurrency shows values in the configured currency, at its
+// fallback rate until refreshCurrencyRate fetches a live one.
+func applyDisplayCurrency() {
+	if c, ok := formatter.LookupCurrency(config.GetDisplayCurrency()); ok {
+		formatter.SetDisplayCurrency(c)
+	}
+}
+
+// refreshCurrencyRate fetches the live rate of the display currency once,
+// for the rest of the proxy session. The fallback rate stays in use when
+// the rate can't be fetched.
+func refreshCurrencyRate(ctx context.Context) {
+	c := formatter.DisplayCurrency()
+	if c.Code == "USD" {
+		return
+	}
+	rate, err := fetchFXRate(ctx, c.Code)
+	if err != nil {
+		logger.Warn("failed to fetch exchange rate, using the built-in one", "currency", c.Code, "error", err)
+		return
+	}
+	c.Rate = rate
+	formatter.SetDisplayCurrency(c)
+}
+
+// fetchFXRate returns how many units of code one US dollar buys.
+func fetchFXRate(ctx context.Context, code string) (float64, error) {
+	url := fxRatesURL
+	if v := os.Getenv("BOBA_FX_URL"); v != "" {
+		url = v
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, err
+	}
+	rate := out.Rates[strings.ToUpper(code)]
+	if rate <= 0 {
+		return 0, fmt.Errorf("no %s rate in the response", code)
+	}
+	return rate, nil
+}
@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Diagnostics for bug reports",
+}
+
+var debugProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Save a profile from the running proxy",
+	Long: "Fetch a pprof profile from the proxy's debug server and save it to a file.\n" +
+		"The proxy must be started with 'boba start --debug-server' or BOBA_DEBUG=1.",
+	RunE: runDebugProfile,
+}
+
+var (
+	flagProfileSeconds int
+	flagProfileOut     string
+	flagProfileKind    string
+)
+
+func init() {
+	debugProfileCmd.Flags().IntVar(&flagProfileSeconds, "seconds", 30, "How long to sample a CPU profile")
+	debugProfileCmd.Flags().StringVar(&flagProfileOut, "out", "", "File to write (default <kind>.pprof)")
+	debugProfileCmd.Flags().StringVar(&flagProfileKind, "kind", "cpu", "Profile to take: cpu, heap, allocs or goroutine")
+	debugCmd.AddCommand(debugProfileCmd)
+}
+
+func runDebugProfile(cmd *cobra.Command, args []string) error {
+	var path string
+	switch flagProfileKind {
+	case "cpu":
+		if flagProfileSeconds < 1 {
+			return fmt.Errorf("--seconds must be at least 1")
+		}
+		path = fmt.Sprintf("/debug/pprof/profile?seconds=%d", flagProfileSeconds)
+	case "heap", "allocs", "goroutine":
+		path = "/debug/pprof/" + flagProfileKind
+	default:
+		return fmt.Errorf("unknown profile kind %q (use cpu, heap, allocs or goroutine)", flagProfileKind)
+	}
+
+	out := flagProfileOut
+	if out == "" {
+		out = flagProfileKind + ".pprof"
+	}
+
+	ep, err := proxy.LoadDebugEndpoint()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", "http://"+ep.Addr+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+ep.Token)
+
+	client := &http.Client{Timeout: time.Duration(flagProfileSeconds)*time.Second + 30*time.Second}
+
+	var written int64
+	label := "Collecting profile..."
+	if flagProfileKind == "cpu" {
+		label = fmt.Sprintf("Sampling CPU for %ds...", flagProfileSeconds)
+	}
+	err = ui.RunWithSpinner(label, func() error {
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("debug server unreachable at %s (is the proxy still running?): %w", ep.Addr, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return fmt.Errorf("debug server returned status %d: %s", resp.StatusCode, body)
+		}
+
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		written, err = io.Copy(f, resp.Body)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	ui.Field("profile", out)
+	ui.Field("bytes", fmt.Sprintf("%d", written))
+	ui.Decor(ui.DimStyle.Render("Inspect with ") + ui.BrightStyle.Render("go tool pprof "+out))
+	return nil
+}
@@ -0,0 +1,416 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check your setup for problems",
+	RunE:  runDoctor,
+}
+
+var (
+	flagDoctorFix  bool
+	flagDoctorJSON bool
+)
+
+func init() {
+	doctorCmd.Flags().BoolVar(&flagDoctorFix, "fix", false, "Apply available fixes without asking")
+	doctorCmd.Flags().BoolVar(&flagDoctorJSON, "json", false, "Print the report as JSON, for bug reports")
+}
+
+// doctorTimeout bounds each network check.
+const doctorTimeout = 5 * time.Second
+
+// doctorResult is the outcome of one check. hint tells the user how to
+// resolve a failure; fix, when set, repairs the problem and is offered to
+// the user.
+type doctorResult struct {
+	ok      bool
+	detail  string
+	hint    string
+	fixHint string
+	fix     func() error
+}
+
+// doctorReport is one check in the --json report.
+type doctorReport struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+type doctorCheck struct {
+	name string
+	run  func() doctorResult
+}
+
+var doctorChecks = []doctorCheck{
+	{name: "Config file", run: checkConfigFile},
+	{name: "Keyring", run: checkKeyring},
+	{name: "Credentials", run: checkCredentials},
+	{name: "Access token", run: checkTokenExpiry},
+	{name: "Auth server", run: checkAuthServer},
+	{name: "MCP server", run: checkMCPServer},
+	{name: "Proxy port", run: checkProxyPort},
+	{name: "Claude setup", run: checkClaudeSetup},
+	{name: "File permissions", run: checkFilePermissions},
+	{name: "MCP config entries", run: checkMCPEntries},
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	if flagDoctorJSON {
+		return runDoctorJSON()
+	}
+
+	failed := 0
+	for _, c := range doctorChecks {
+		res := c.run()
+		printDoctorResult(c.name, res)
+		if res.ok {
+			continue
+		}
+		if res.fix != nil && confirmFix(res.fixHint) {
+			if err := res.fix(); err != nil {
+				ui.Errorln(fmt.Sprintf("  fix failed: %v", err))
+			} else {
+				res = c.run()
+				printDoctorResult(c.name, res)
+			}
+		}
+		if !res.ok {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// runDoctorJSON runs every check without offering fixes and prints the
+// report as JSON.
+func runDoctorJSON() error {
+	report := make([]doctorReport, 0, len(doctorChecks))
+	failed := 0
+	for _, c := range doctorChecks {
+		res := c.run()
+		if !res.ok {
+			failed++
+		}
+		report = append(report, doctorReport{Name: c.name, OK: res.ok, Detail: res.detail, Hint: res.hint})
+	}
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	ui.Println(string(out))
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// confirmFix applies fixes automatically with --fix, asks on a terminal,
+// and otherwise leaves things alone.
+func confirmFix(hint string) bool {
+	if flagDoctorFix {
+		return true
+	}
+	if !ui.Decorate() {
+		return false
+	}
+	apply := false
+	err := huh.NewConfirm().
+		Title(hint + "?").
+		Value(&apply).
+		WithTheme(ui.BobaTheme()).
+		Run()
+	return err == nil && apply
+}
+
+func printDoctorResult(name string, res doctorResult) {
+	if !ui.Decorate() {
+		status := "ok"
+		if !res.ok {
+			status = "fail"
+		}
+		line := status
+		if res.detail != "" {
+			line += " " + res.detail
+		}
+		if !res.ok && res.hint != "" {
+			line += " (" + res.hint + ")"
+		}
+		ui.Field(strings.ToLower(strings.ReplaceAll(name, " ", "_")), line)
+		return
+	}
+
+	label := lipgloss.NewStyle().Foreground(ui.ColorBright).Width(22).Render(name)
+	if res.ok {
+		fmt.Printf("  %s %s %s\n", ui.SuccessStyle.Render("✓"), label, ui.DimStyle.Render(res.detail))
+		return
+	}
+	fmt.Printf("  %s %s %s\n", ui.ErrorStyle.Render("✗"), label, ui.ErrorStyle.Render(res.detail))
+	if res.hint != "" {
+		fmt.Printf("    %s %s\n", strings.Repeat(" ", 22), ui.DimStyle.Render("→ "+res.hint))
+	}
+}
+
+// checkConfigFile makes sure the config file, if there is one, parses.
+func checkConfigFile() doctorResult {
+	if err := config.CheckConfigFile(); err != nil {
+		return doctorResult{
+			detail: fmt.Sprintf("%s: %v", config.ConfigPath(), err),
+			hint:   "Fix the file by hand or run 'boba config --reset'",
+		}
+	}
+	return doctorResult{ok: true, detail: config.ConfigPath()}
+}
+
+// checkKeyring reports where secrets are kept. Without a keyring they come
+// from environment variables, which is only a problem when they are unset.
+func checkKeyring() doctorResult {
+	if config.KeyringAvailable() {
+		return doctorResult{ok: true, detail: "system keyring"}
+	}
+	if os.Getenv("BOBA_AGENT_SECRET") != "" {
+		return doctorResult{ok: true, detail: "unavailable, using environment variables"}
+	}
+	return doctorResult{
+		detail: "unavailable and BOBA_AGENT_SECRET is not set",
+		hint:   "Unlock or install a keyring service, or set BOBA_AGENT_SECRET",
+	}
+}
+
+func checkCredentials() doctorResult {
+	if !config.HasCredentials() {
+		return doctorResult{detail: "no agent credentials", hint: "Run 'boba login'"}
+	}
+	creds, err := config.GetCredentials()
+	if err != nil {
+		return doctorResult{detail: err.Error(), hint: "Run 'boba login'"}
+	}
+	if creds.Name != "" {
+		return doctorResult{ok: true, detail: "agent " + creds.Name}
+	}
+	return doctorResult{ok: true, detail: "agent " + creds.AgentID}
+}
+
+// checkTokenExpiry makes sure the stored token expiry parses. An expired
+// token is fine; it is refreshed on the next call.
+func checkTokenExpiry() doctorResult {
+	expiresAt, ok, err := config.AccessTokenExpiry()
+	switch {
+	case err != nil:
+		return doctorResult{detail: err.Error(), hint: "Run 'boba login' to fetch new tokens"}
+	case !ok:
+		return doctorResult{ok: true, detail: "none yet, fetched on first use"}
+	case time.Now().After(expiresAt):
+		return doctorResult{ok: true, detail: "expired, refreshed on next use"}
+	}
+	return doctorResult{ok: true, detail: "valid until " + expiresAt.Local().Format("2006-01-02 15:04")}
+}
+
+// checkAuthServer makes sure the auth server answers at all.
+func checkAuthServer() doctorResult {
+	url := config.GetAuthURL()
+	client := &http.Client{Timeout: doctorTimeout}
+	resp, err := client.Head(url)
+	if err != nil {
+		return doctorResult{
+			detail: fmt.Sprintf("%s unreachable: %v", url, err),
+			hint:   "Check your connection, proxy or firewall, and 'boba config --auth-url'",
+		}
+	}
+	resp.Body.Close()
+	return doctorResult{ok: true, detail: url}
+}
+
+// checkMCPServer lists the tools with the stored access token, which shows
+// both that the server is reachable and that it accepts the token.
+func checkMCPServer() doctorResult {
+	url := config.GetMCPURL()
+	tokens, err := config.GetTokens()
+	if err != nil || tokens.AccessToken == "" {
+		return doctorResult{detail: "no access token to try", hint: "Run 'boba login'"}
+	}
+	req, err := http.NewRequest("GET", url+"/tools", nil)
+	if err != nil {
+		return doctorResult{detail: err.Error(), hint: "Check 'boba config --mcp-url'"}
+	}
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	client := &http.Client{Timeout: doctorTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return doctorResult{
+			detail: fmt.Sprintf("%s unreachable: %v", url, err),
+			hint:   "Check your connection, proxy or firewall, and 'boba config --mcp-url'",
+		}
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return doctorResult{
+			detail: fmt.Sprintf("token rejected (%d)", resp.StatusCode),
+			hint:   "Run 'boba auth' to refresh it, or 'boba login' again",
+		}
+	case resp.StatusCode >= 300:
+		return doctorResult{
+			detail: fmt.Sprintf("%s/tools returned %d", url, resp.StatusCode),
+			hint:   "Check 'boba config --mcp-url', or try again later",
+		}
+	}
+	return doctorResult{ok: true, detail: url}
+}
+
+// checkProxyPort makes sure the proxy port is free, or held by a healthy
+// boba proxy.
+func checkProxyPort() doctorResult {
+	port := config.ActiveProxyPort()
+	if _, ok := proxyHealth(port); ok {
+		return doctorResult{ok: true, detail: fmt.Sprintf(":%d, proxy running", port)}
+	}
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return doctorResult{
+			detail: fmt.Sprintf(":%d is in use by another program", port),
+			hint:   "Stop that program or pick another port with 'boba config --port'",
+		}
+	}
+	ln.Close()
+	return doctorResult{ok: true, detail: fmt.Sprintf(":%d, free", port)}
+}
+
+// checkClaudeSetup makes sure Claude Desktop or Claude Code knows about the
+// boba MCP server.
+func checkClaudeSetup() doctorResult {
+	var found []string
+	if entry, _, _ := readMCPEntry(desktopConfigPath()); entry != nil {
+		found = append(found, "Claude Desktop")
+	}
+	if entry, _, _ := readMCPEntry(codeConfigPath()); entry != nil {
+		found = append(found, "Claude Code")
+	}
+	if len(found) == 0 {
+		return doctorResult{detail: "boba is not set up in Claude Desktop or Claude Code", hint: "Run 'boba install'"}
+	}
+	return doctorResult{ok: true, detail: strings.Join(found, ", ")}
+}
+
+// checkFilePermissions flags private files others can read.
+func checkFilePermissions() doctorResult {
+	issues, err := config.AuditPermissions()
+	if err != nil {
+		return doctorResult{detail: err.Error()}
+	}
+	if len(issues) == 0 {
+		return doctorResult{ok: true, detail: config.DataDir() + " is private"}
+	}
+
+	var paths []string
+	for _, is := range issues {
+		paths = append(paths, fmt.Sprintf("%s (%o)", is.Path, is.Mode))
+	}
+	return doctorResult{
+		detail:  "readable by others: " + strings.Join(paths, ", "),
+		hint:    "Run 'boba doctor --fix'",
+		fixHint: fmt.Sprintf("Restrict %d path(s) to owner-only access", len(issues)),
+		fix:     func() error { return config.FixPermissions(issues) },
+	}
+}
+
+// mcpEntryKeys are the only fields boba writes into its MCP server entry.
+var mcpEntryKeys = map[string]bool{"command": true, "args": true, "type": true}
+
+// checkMCPEntries makes sure the boba entry in Claude's config files only
+// holds the command and args. Those files are world-readable, so nothing
+// else (env vars, tokens) may end up there.
+func checkMCPEntries() doctorResult {
+	var problems, paths []string
+	for _, path := range []string{desktopConfigPath(), codeConfigPath()} {
+		entry, _, err := readMCPEntry(path)
+		if err != nil || entry == nil {
+			continue
+		}
+		var extra []string
+		for k := range entry {
+			if !mcpEntryKeys[k] {
+				extra = append(extra, k)
+			}
+		}
+		if len(extra) > 0 {
+			sort.Strings(extra)
+			problems = append(problems, fmt.Sprintf("%s has %s", path, strings.Join(extra, ", ")))
+			paths = append(paths, path)
+		}
+	}
+
+	if len(problems) > 0 {
+		return doctorResult{
+			detail:  strings.Join(problems, "; "),
+			hint:    "Run 'boba doctor --fix'",
+			fixHint: "Remove the extra fields from the boba MCP entry",
+			fix: func() error {
+				for _, p := range paths {
+					if err := stripMCPEntry(p); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		}
+	}
+	return doctorResult{ok: true, detail: "command and args only"}
+}
+
+// readMCPEntry returns the boba server entry from a Claude config file along
+// with the whole parsed file.
+func readMCPEntry(path string) (map[string]any, map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var existing map[string]any
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return nil, nil, err
+	}
+	servers, _ := existing["mcpServers"].(map[string]any)
+	entry, _ := servers["boba"].(map[string]any)
+	return entry, existing, nil
+}
+
+// stripMCPEntry removes every field but command and args from the boba
+// entry, leaving the rest of the file untouched.
+func stripMCPEntry(path string) error {
+	entry, existing, err := readMCPEntry(path)
+	if err != nil || entry == nil {
+		return err
+	}
+	for k := range entry {
+		if !mcpEntryKeys[k] {
+			delete(entry, k)
+		}
+	}
+	output, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, output, 0644)
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// The doctor flags a config file others can read, offers to fix it, and
+// passes once the fix has run.
+func TestDoctorFilePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows doesn't use Unix modes")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config.UseDir(filepath.Join(home, ".config"))
+	if err := config.WritePrivateFile(config.ConfigPath(), []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if res := checkFilePermissions(); !res.ok {
+		t.Fatalf("private config flagged: %+v", res)
+	}
+
+	if err := os.Chmod(config.ConfigPath(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	res := checkFilePermissions()
+	if res.ok || res.fix == nil {
+		t.Fatalf("loose config not flagged with a fix: %+v", res)
+	}
+	if !strings.Contains(res.detail, config.ConfigPath()+" (644)") {
+		t.Errorf("detail = %q, want the path and its mode", res.detail)
+	}
+	if err := res.fix(); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(config.ConfigPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != config.PrivateFileMode {
+		t.Errorf("after fix: mode %o, want %o", mode, config.PrivateFileMode)
+	}
+	if res := checkFilePermissions(); !res.ok {
+		t.Errorf("after fix: %+v", res)
+	}
+}
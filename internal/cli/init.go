@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os/exec"
 	"runtime"
 	"strings"
@@ -42,6 +44,8 @@ func bobaTheme() *huh.Theme { return ui.BobaTheme() }
 type onboardingStep struct {
 	label string
 	fn    func() error
+	// cosmetic steps only pace the animation and are skipped in plain output.
+	cosmetic bool
 }
 
 type stepStatus int
@@ -175,7 +179,7 @@ func (m onboardingModel) View() string {
 
 	labelDone := lipgloss.NewStyle().Foreground(ui.ColorGreen)
 	labelActive := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true)
-	labelPending := lipgloss.NewStyle().Foreground(lipgloss.Color("#333333"))
+	labelPending := lipgloss.NewStyle().Foreground(ui.ColorPending)
 	labelFailed := lipgloss.NewStyle().Foreground(ui.ColorRed)
 
 	for i, step := range m.steps {
@@ -323,11 +327,12 @@ func runNextStepMenu() {
 
 func runInit(cmd *cobra.Command, args []string) error {
 	ui.PrintLogo()
-	fmt.Println()
+	ui.Decor()
 
 	agentID := flagAgentID
 	secret := flagSecret
 	name := flagName
+	prompted := false
 
 	if agentID == "" || secret == "" {
 		hasCreds := true
@@ -355,49 +360,111 @@ func runInit(cmd *cobra.Command, args []string) error {
 			return nil
 		}
 
-		form := huh.NewForm(
-			huh.NewGroup(
-				huh.NewNote().
-					Title("Agent Setup").
-					Description("Enter your Boba Agent credentials."),
-				huh.NewInput().
-					Title("Agent ID").
-					Description("Your unique agent identifier").
-					Value(&agentID),
-				huh.NewInput().
-					Title("Agent Secret").
-					Description("Your agent secret key").
-					EchoMode(huh.EchoModePassword).
-					Value(&secret),
-				huh.NewInput().
-					Title("Agent Name").
-					Description("A friendly name for your agent (optional)").
-					Value(&name),
-			),
-		).WithTheme(bobaTheme())
-
-		if err := form.Run(); err != nil {
+		if err := credentialForm(&agentID, &secret, &name, "").Run(); err != nil {
 			return fmt.Errorf("form cancelled: %w", err)
 		}
+		prompted = true
 	}
 
 	if agentID == "" || secret == "" {
 		return fmt.Errorf("agent ID and secret are required")
 	}
 
-	fmt.Println()
+	// A running proxy keeps its copy of the config; offer to tell it
+	// about the new credentials once they're saved.
+	proxyRunning := proxyIsRunning(config.ActiveProxyPort())
+
+	ui.Decor()
 
+	// Nothing is saved until the credentials are accepted. When they were
+	// typed in, a rejection opens the form again, keeping the agent ID.
 	var tokens *config.AuthTokens
+	for attempt := 1; ; attempt++ {
+		tokens = nil
+		err := runOnboarding(onboardingSteps(agentID, secret, name, &tokens))
+		if err == nil {
+			break
+		}
+		if !prompted || !errors.Is(err, auth.ErrRejected) || attempt == maxLoginAttempts {
+			return err
+		}
+		secret = ""
+		failure := fmt.Sprintf("The agent ID or secret was not accepted. Check them and try again (attempt %d of %d).", attempt+1, maxLoginAttempts)
+		fmt.Println()
+		if err := credentialForm(&agentID, &secret, &name, failure).Run(); err != nil {
+			return fmt.Errorf("form cancelled: %w", err)
+		}
+		if agentID == "" || secret == "" {
+			return fmt.Errorf("agent ID and secret are required")
+		}
+	}
 
-	steps := []onboardingStep{
-		{
-			label: "Saving credentials to keychain...",
-			fn: func() error {
-				return config.SetCredentials(agentID, secret, name)
-			},
-		},
+	if tokens == nil {
+		return fmt.Errorf("authentication failed: no tokens received")
+	}
+
+	if !ui.Animate() {
+		if ui.Decorate() {
+			fmt.Println(renderSuccessCard(tokens))
+		} else {
+			printTokensPlain(tokens)
+		}
+		if proxyRunning {
+			offerProxyReload()
+		}
+		return nil
+	}
+
+	fmt.Println(renderSuccessCard(tokens))
+	fmt.Println()
+	if proxyRunning {
+		offerProxyReload()
+	}
+	runNextStepMenu()
+
+	return nil
+}
+
+// maxLoginAttempts is how many times boba login asks for credentials the
+// auth service rejects before giving up.
+const maxLoginAttempts = 3
+
+// credentialForm asks for the agent credentials. failure, when set, says
+// why the last ones didn't work.
+func credentialForm(agentID, secret, name *string, failure string) *huh.Form {
+	description := "Enter your Boba Agent credentials."
+	if failure != "" {
+		description += "\n\n" + ui.ErrorStyle.Render(failure)
+	}
+	return huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Agent Setup").
+				Description(description),
+			huh.NewInput().
+				Title("Agent ID").
+				Description("Your unique agent identifier").
+				Value(agentID),
+			huh.NewInput().
+				Title("Agent Secret").
+				Description("Your agent secret key").
+				EchoMode(huh.EchoModePassword).
+				Value(secret),
+			huh.NewInput().
+				Title("Agent Name").
+				Description("A friendly name for your agent (optional)").
+				Value(name),
+		),
+	).WithTheme(bobaTheme())
+}
+
+// onboardingSteps authenticates with the credentials and only then saves
+// them, setting *tokens on success.
+func onboardingSteps(agentID, secret, name string, tokens **config.AuthTokens) []onboardingStep {
+	return []onboardingStep{
 		{
-			label: "Connecting to Boba network...",
+			label:    "Connecting to Boba network...",
+			cosmetic: true,
 			fn: func() error {
 				// Connection is established as part of auth; small pause for UX
 				time.Sleep(200 * time.Millisecond)
@@ -408,27 +475,54 @@ func runInit(cmd *cobra.Command, args []string) error {
 			label: "Authenticating agent...",
 			fn: func() error {
 				var err error
-				tokens, err = auth.Authenticate()
+				*tokens, err = auth.AuthenticateWithCredentials(agentID, secret)
 				return err
 			},
 		},
 		{
-			label: "Registering with trading services...",
+			label: "Saving credentials to keychain...",
+			fn: func() error {
+				if err := config.SetCredentials(agentID, secret, name); err != nil {
+					return err
+				}
+				return auth.Activate(*tokens)
+			},
+		},
+		{
+			label:    "Registering with trading services...",
+			cosmetic: true,
 			fn: func() error {
-				// Registration happens inside auth.Authenticate() already
+				// Registration happens in auth.Activate() already
 				time.Sleep(250 * time.Millisecond)
 				return nil
 			},
 		},
 		{
-			label: "Initializing wallet monitoring...",
+			label:    "Initializing wallet monitoring...",
+			cosmetic: true,
 			fn: func() error {
-				// Wallet monitoring init happens inside auth.Authenticate() already
+				// Wallet monitoring init happens in auth.Activate() already
 				time.Sleep(200 * time.Millisecond)
 				return nil
 			},
 		},
 	}
+}
+
+// runOnboarding runs the steps, animated when the terminal allows, and
+// returns the first failure prefixed with its step's label.
+func runOnboarding(steps []onboardingStep) error {
+	if !ui.Animate() {
+		for _, step := range steps {
+			if step.cosmetic {
+				continue
+			}
+			if err := ui.Step(step.label, step.fn); err != nil {
+				return fmt.Errorf("%s: %w", step.label, err)
+			}
+		}
+		return nil
+	}
 
 	model := newOnboardingModel(steps)
 	model.statuses[0] = stepRunning
@@ -448,15 +542,68 @@ func runInit(cmd *cobra.Command, args []string) error {
 		}
 		return fmt.Errorf("initialization was interrupted")
 	}
+	return nil
+}
 
-	if tokens == nil {
-		return fmt.Errorf("authentication failed: no tokens received")
+// proxyIsRunning reports whether a proxy answers on port.
+func proxyIsRunning(port int) bool {
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/health", port))
+	if err != nil {
+		return false
 	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
 
-	fmt.Println(renderSuccessCard(tokens))
-	fmt.Println()
-	runNextStepMenu()
+// offerProxyReload asks whether to make the running proxy pick up the new
+// credentials, and does so without asking when there is no terminal.
+func offerProxyReload() {
+	if ui.Decorate() {
+		reload := true
+		err := huh.NewConfirm().
+			Title("A Boba proxy is running. Reload it with the new credentials?").
+			Value(&reload).
+			WithTheme(bobaTheme()).
+			Run()
+		if err != nil || !reload {
+			ui.Decor(ui.DimStyle.Render("  The running proxy keeps the old credentials until it restarts."))
+			return
+		}
+	}
+
+	if err := reloadProxy(config.ActiveProxyPort()); err != nil {
+		ui.Errorln(fmt.Sprintf("could not reload the running proxy: %v (restart it with 'boba start')", err))
+		return
+	}
+	ui.Field("proxy", "reloaded")
+}
 
+// reloadProxy tells the proxy on port to re-read the config file.
+func reloadProxy(port int) error {
+	return postProxy(port, "/reload", http.StatusNoContent)
+}
+
+// postProxy sends an empty POST to path on the proxy on port, with the
+// session token from the keyring, and expects the given status back.
+func postProxy(port int, path string, want int) error {
+	token, err := config.GetSessionToken()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d%s", port, path), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := (&http.Client{Timeout: 5 * time.Second}).Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != want {
+		return fmt.Errorf("proxy returned status %d", resp.StatusCode)
+	}
 	return nil
 }
 
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/auth"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/zalando/go-keyring"
+)
+
+// rejectFirst sets up an auth server that turns down the first
+// authentication and accepts the rest, with nothing stored yet.
+func rejectFirst(t *testing.T) {
+	t.Helper()
+	var posts atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path != "/user/auth/authenticate" {
+			io.WriteString(w, `{}`)
+			return
+		}
+		if posts.Add(1) == 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"error":"invalid credentials"}`)
+			return
+		}
+		io.WriteString(w, `{"data":{"access_token":"access","refresh_token":"refresh",`+
+			`"access_token_expires_at":"2099-01-01T00:00:00Z","agent_id":"agent-1","agent_name":"Taro"}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	keyring.MockInit()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config.UseDir(filepath.Join(home, ".config"))
+	t.Setenv(config.EnvAllowAnyHost, "1")
+	t.Setenv(config.EnvAuthURL, srv.URL)
+	t.Setenv(config.EnvMCPURL, srv.URL)
+}
+
+// Rejected credentials are never saved; the next attempt's are, with
+// their tokens.
+func TestOnboardingSavesAcceptedCredentials(t *testing.T) {
+	rejectFirst(t)
+
+	var tokens *config.AuthTokens
+	err := runOnboarding(onboardingSteps("agent-1", "typo", "Taro", &tokens))
+	if !errors.Is(err, auth.ErrRejected) {
+		t.Fatalf("first attempt: %v, want ErrRejected", err)
+	}
+	if config.HasCredentials() {
+		t.Fatal("rejected credentials saved")
+	}
+
+	if err := runOnboarding(onboardingSteps("agent-1", "secret", "Taro", &tokens)); err != nil {
+		t.Fatal(err)
+	}
+	creds, err := config.GetCredentials()
+	if err != nil || creds.AgentSecret != "secret" {
+		t.Errorf("saved credentials = %+v, %v", creds, err)
+	}
+	if stored, err := config.GetTokens(); err != nil || stored.AccessToken != "access" || tokens.AccessToken != "access" {
+		t.Errorf("tokens = %+v, stored %+v, %v", tokens, stored, err)
+	}
+}
+
+// Credentials passed as flags aren't asked for again: a rejection ends the
+// login with nothing saved.
+func TestLoginFlagsRejected(t *testing.T) {
+	rejectFirst(t)
+	t.Cleanup(func() { flagAgentID, flagSecret, flagName = "", "", "" })
+
+	_, _, err := run(t, "login", "--agent-id", "agent-1", "--secret", "typo")
+	if !errors.Is(err, auth.ErrRejected) {
+		t.Errorf("login: %v, want ErrRejected", err)
+	}
+	if config.HasCredentials() {
+		t.Error("rejected credentials saved")
+	}
+
+	if _, _, err := run(t, "login", "--agent-id", "agent-1", "--secret", "secret"); err != nil {
+		t.Fatal(err)
+	}
+	if !config.HasCredentials() {
+		t.Error("accepted credentials not saved")
+	}
+}
@@ -31,6 +31,43 @@ func init() {
 }
 
 func runInstall(cmd *cobra.Command, args []string) error {
+	binaryPath, err := bobaBinaryPath()
+	if err != nil {
+		return err
+	}
+	mcpCommand, mcpArgs := mcpServerCommand(binaryPath)
+
+	var desktopErr, codeErr error
+	desktopSkipped := flagCodeOnly
+	codeSkipped := flagDesktopOnly
+
+	if !desktopSkipped {
+		desktopErr = ui.Step("Installing for Claude Desktop...", func() error {
+			return installDesktop(mcpCommand, mcpArgs)
+		})
+	}
+	if !codeSkipped {
+		codeErr = ui.Step("Installing for Claude Code...", func() error {
+			return installCode(mcpCommand, mcpArgs)
+		})
+	}
+
+	if !ui.Decorate() {
+		ui.Field("claude_desktop", installResult(desktopErr, desktopSkipped))
+		ui.Field("claude_code", installResult(codeErr, codeSkipped))
+		ui.Field("command", mcpCommand)
+		ui.Field("args", strings.Join(mcpArgs, " "))
+		return nil
+	}
+
+	lines := buildInstallLines(mcpCommand, mcpArgs, desktopErr, codeErr, desktopSkipped, codeSkipped)
+	runScanReveal(lines)
+
+	return nil
+}
+
+// bobaBinaryPath returns the path Claude should launch boba from.
+func bobaBinaryPath() (string, error) {
 	// Use the PATH-resolved location (e.g. /Users/x/.nvm/.../bin/boba)
 	// rather than os.Executable() which resolves deep into node_modules
 	// and breaks when npm reorganizes on updates.
@@ -39,38 +76,34 @@ func runInstall(cmd *cobra.Command, args []string) error {
 		// Fallback to os.Executable if not in PATH
 		binaryPath, err = os.Executable()
 		if err != nil {
-			return fmt.Errorf("failed to determine binary path: %w", err)
+			return "", fmt.Errorf("failed to determine binary path: %w", err)
 		}
 	}
 	binaryPath, _ = filepath.Abs(binaryPath)
+	return binaryPath, nil
+}
 
+// mcpServerCommand returns the command and args of the MCP server entry
+// that runs the binary at binaryPath.
+func mcpServerCommand(binaryPath string) (string, []string) {
 	// On Windows, npm installs a .cmd wrapper. Claude Desktop can't
 	// execute .cmd files directly — wrap with cmd.exe /c.
-	var mcpCommand string
-	var mcpArgs []string
 	if runtime.GOOS == "windows" && strings.HasSuffix(strings.ToLower(binaryPath), ".cmd") {
-		mcpCommand = "cmd.exe"
-		mcpArgs = []string{"/c", binaryPath, "mcp"}
-	} else {
-		mcpCommand = binaryPath
-		mcpArgs = []string{"mcp"}
+		return "cmd.exe", []string{"/c", binaryPath, "mcp"}
 	}
+	return binaryPath, []string{"mcp"}
+}
 
-	var desktopErr, codeErr error
-	desktopSkipped := flagCodeOnly
-	codeSkipped := flagDesktopOnly
-
-	if !desktopSkipped {
-		desktopErr = installDesktop(mcpCommand, mcpArgs)
-	}
-	if !codeSkipped {
-		codeErr = installCode(mcpCommand, mcpArgs)
+// installResult describes the outcome of one install target in plain output.
+func installResult(err error, skipped bool) string {
+	switch {
+	case skipped:
+		return "skipped"
+	case err != nil:
+		return "failed: " + err.Error()
+	default:
+		return "installed"
 	}
-
-	lines := buildInstallLines(mcpCommand, mcpArgs, desktopErr, codeErr, desktopSkipped, codeSkipped)
-	runScanReveal(lines)
-
-	return nil
 }
 
 func buildInstallLines(mcpCommand string, mcpArgs []string, desktopErr, codeErr error, desktopSkipped, codeSkipped bool) []string {
@@ -145,27 +178,32 @@ func buildInstallLines(mcpCommand string, mcpArgs []string, desktopErr, codeErr
 	return lines
 }
 
-func installDesktop(command string, args []string) error {
-	var configPath string
+// desktopConfigPath returns the Claude Desktop MCP config file for this OS.
+func desktopConfigPath() string {
 	switch runtime.GOOS {
 	case "darwin":
 		home, _ := os.UserHomeDir()
-		configPath = filepath.Join(home, "Library", "Application Support", "Claude", "claude_desktop_config.json")
+		return filepath.Join(home, "Library", "Application Support", "Claude", "claude_desktop_config.json")
 	case "windows":
-		configPath = filepath.Join(os.Getenv("APPDATA"), "Claude", "claude_desktop_config.json")
+		return filepath.Join(os.Getenv("APPDATA"), "Claude", "claude_desktop_config.json")
 	default:
 		home, _ := os.UserHomeDir()
-		configPath = filepath.Join(home, ".config", "claude", "claude_desktop_config.json")
+		return filepath.Join(home, ".config", "claude", "claude_desktop_config.json")
 	}
-
-	return writeMCPConfig(configPath, command, args)
 }
 
-func installCode(command string, args []string) error {
+// codeConfigPath returns the Claude Code config file.
+func codeConfigPath() string {
 	home, _ := os.UserHomeDir()
-	configPath := filepath.Join(home, ".claude.json")
+	return filepath.Join(home, ".claude.json")
+}
 
-	return writeCodeConfig(configPath, command, args)
+func installDesktop(command string, args []string) error {
+	return writeMCPConfig(desktopConfigPath(), command, args)
+}
+
+func installCode(command string, args []string) error {
+	return writeCodeConfig(codeConfigPath(), command, args)
 }
 
 func writeMCPConfig(configPath, command string, args []string) error {
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+)
+
+// npm's .cmd shims on Windows run through cmd.exe /c, since Claude Desktop
+// can't run them directly; anything else runs as is.
+func TestMCPServerCommand(t *testing.T) {
+	onWindows := runtime.GOOS == "windows"
+	for _, tc := range []struct {
+		path    string
+		wrapped bool
+	}{
+		{`C:\Users\me\AppData\Roaming\npm\boba.cmd`, onWindows},
+		{`C:\Program Files\nodejs\BOBA.CMD`, onWindows},
+		{`C:\tools\boba.exe`, false},
+		{"/usr/local/bin/boba", false},
+		{"/opt/boba.cmd.d/boba", false},
+	} {
+		command, args := mcpServerCommand(tc.path)
+		wantCommand, wantArgs := tc.path, []string{"mcp"}
+		if tc.wrapped {
+			wantCommand, wantArgs = "cmd.exe", []string{"/c", tc.path, "mcp"}
+		}
+		if command != wantCommand || !slices.Equal(args, wantArgs) {
+			t.Errorf("mcpServerCommand(%q) = %s %q, want %s %q", tc.path, command, args, wantCommand, wantArgs)
+		}
+		// The entry check finds the binary behind the wrapper.
+		if got := mcpEntryBinary(command, args); got != tc.path {
+			t.Errorf("mcpEntryBinary of %q's entry = %q", tc.path, got)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
@@ -28,13 +30,19 @@ var launchCmd = &cobra.Command{
 }
 
 var (
-	flagDesktop bool
-	flagITerm   bool
+	flagDesktop   bool
+	flagITerm     bool
+	flagTmux      bool
+	flagDisplay   int
+	flagNoArrange bool
 )
 
 func init() {
 	launchCmd.Flags().BoolVar(&flagDesktop, "desktop", false, "Open Claude Desktop instead of Code")
 	launchCmd.Flags().BoolVar(&flagITerm, "iterm", false, "Use iTerm instead of Terminal.app (macOS only)")
+	launchCmd.Flags().BoolVar(&flagTmux, "tmux", false, "Run the proxy in a tmux split (the default inside tmux)")
+	launchCmd.Flags().IntVar(&flagDisplay, "display", 0, "Arrange the windows on this display, 1 being the one with the menu bar (macOS only; default: the one this terminal is on)")
+	launchCmd.Flags().BoolVar(&flagNoArrange, "no-arrange", false, "Leave the windows where the terminal opens them (macOS only)")
 }
 
 type layout int
@@ -244,7 +252,7 @@ func (m launchModel) View() string {
 
 	labelDone := lipgloss.NewStyle().Foreground(ui.ColorGreen)
 	labelActive := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF")).Bold(true)
-	labelPending := lipgloss.NewStyle().Foreground(lipgloss.Color("#333333"))
+	labelPending := lipgloss.NewStyle().Foreground(ui.ColorPending)
 	labelFailed := lipgloss.NewStyle().Foreground(ui.ColorRed)
 
 	for i, step := range m.steps {
@@ -265,6 +273,9 @@ func (m launchModel) View() string {
 		case stepFailed:
 			b.WriteString(failmark)
 			b.WriteString(labelFailed.Render(step.label + " \u2014 FAILED"))
+			if err := m.errors[i]; err != nil {
+				b.WriteString("\n    " + lipgloss.NewStyle().Foreground(ui.ColorDim).Render(err.Error()))
+			}
 		default:
 			scrambled := ui.GlitchText(step.label, 0.0)
 			b.WriteString("    ")
@@ -322,7 +333,7 @@ func (m launchModel) renderSuccessCard() string {
 
 	b.WriteString(box)
 	b.WriteString("\n\n")
-	b.WriteString(ui.DimStyle.Render("  Proxy is running in a separate terminal window."))
+	b.WriteString(ui.DimStyle.Render("  Proxy is running in a separate terminal."))
 	b.WriteString("\n")
 	if m.selected != "proxy-only" {
 		b.WriteString(ui.DimStyle.Render("  Claude should open momentarily."))
@@ -376,45 +387,148 @@ func computeRects(l layout, s screenBounds) (proxyRect, claudeRect windowRect) {
 	return
 }
 
-// getScreenBounds returns the bounds of the main screen (the one with the
-// menu bar), not the full desktop span across all monitors.
-func getScreenBounds() screenBounds {
-	fallback := screenBounds{0, 0, 1920, 1080}
+// defaultScreenBounds is assumed when the screen can't be measured.
+var defaultScreenBounds = screenBounds{0, 0, 1920, 1080}
 
-	script := `use framework "AppKit"
-set scr to current application's NSScreen's mainScreen()
-set f to scr's visibleFrame()
-set x to (f's origin's x) as integer
-set y to (f's origin's y) as integer
-set w to (f's |size|'s width) as integer
-set h to (f's |size|'s height) as integer
-return (x as text) & "," & (y as text) & "," & (w as text) & "," & (h as text)`
+// macScreen is a display as AppKit reports it: frame is the whole screen
+// and visible leaves out the menu bar and Dock. AppKit puts the origin at
+// the bottom left of the primary screen, with y growing upwards.
+type macScreen struct {
+	frame, visible screenBounds
+}
 
-	out, err := exec.Command("osascript", "-e", script).Output()
+// listScreensScript prints a line per screen, the primary one (with the
+// menu bar) first, as the frame and then the visible frame, followed by a
+// line with the mouse pointer's position.
+const listScreensScript = `use framework "AppKit"
+on rectText(r)
+	return ((r's origin's x) as integer as text) & "," & ((r's origin's y) as integer as text) & "," & ((r's |size|'s width) as integer as text) & "," & ((r's |size|'s height) as integer as text)
+end rectText
+set out to ""
+repeat with scr in (current application's NSScreen's screens() as list)
+	set out to out & rectText(scr's frame()) & "," & rectText(scr's visibleFrame()) & linefeed
+end repeat
+set p to current application's NSEvent's mouseLocation()
+return out & "mouse," & ((p's x) as integer as text) & "," & ((p's y) as integer as text)`
+
+// listScreens returns the connected screens, the primary one first, and the
+// mouse pointer's position, in AppKit coordinates.
+func listScreens() (screens []macScreen, mouseX, mouseY int, err error) {
+	out, err := exec.Command("osascript", "-e", listScreensScript).Output()
 	if err != nil {
-		return fallback
+		return nil, 0, 0, err
 	}
-
-	parts := strings.Split(strings.TrimSpace(string(out)), ",")
-	if len(parts) != 4 {
-		return fallback
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "mouse,"); ok {
+			if v, ok := parseInts(rest, 2); ok {
+				mouseX, mouseY = v[0], v[1]
+			}
+			continue
+		}
+		v, ok := parseInts(line, 8)
+		if !ok {
+			return nil, 0, 0, fmt.Errorf("unexpected screen list %q", line)
+		}
+		screens = append(screens, macScreen{
+			frame:   screenBounds{v[0], v[1], v[2], v[3]},
+			visible: screenBounds{v[4], v[5], v[6], v[7]},
+		})
+	}
+	if len(screens) == 0 {
+		return nil, 0, 0, fmt.Errorf("no screens found")
 	}
+	return screens, mouseX, mouseY, nil
+}
 
-	vals := make([]int, 4)
+// parseInts parses n comma-separated integers.
+func parseInts(s string, n int) ([]int, bool) {
+	parts := strings.Split(s, ",")
+	if len(parts) != n {
+		return nil, false
+	}
+	vals := make([]int, n)
 	for i, p := range parts {
 		v, err := strconv.Atoi(strings.TrimSpace(p))
 		if err != nil {
-			return fallback
+			return nil, false
 		}
 		vals[i] = v
 	}
+	return vals, true
+}
+
+// topLeft converts a rect from AppKit coordinates to the ones Terminal and
+// iTerm window bounds use: origin at the top left of the primary screen,
+// with y growing downwards. primaryHeight is the primary screen's height.
+func topLeft(r screenBounds, primaryHeight int) screenBounds {
+	return screenBounds{x: r.x, y: primaryHeight - (r.y + r.h), w: r.w, h: r.h}
+}
+
+// contains reports whether the point x, y lies on the screen.
+func (s screenBounds) contains(x, y int) bool {
+	return x >= s.x && x < s.x+s.w && y >= s.y && y < s.y+s.h
+}
+
+// callingWindowCenter returns the middle of the terminal window boba was
+// run from, in window bounds coordinates, when that terminal is Terminal
+// or iTerm and says where its front window is.
+func callingWindowCenter() (x, y int, ok bool) {
+	var app string
+	switch os.Getenv("TERM_PROGRAM") {
+	case "Apple_Terminal":
+		app = "Terminal"
+	case "iTerm.app":
+		app = "iTerm"
+	default:
+		return 0, 0, false
+	}
+	script := fmt.Sprintf(`tell application "%s" to get bounds of front window`, app)
+	out, err := exec.Command("osascript", "-e", script).Output()
+	if err != nil {
+		return 0, 0, false
+	}
+	v, ok := parseInts(strings.TrimSpace(string(out)), 4)
+	if !ok {
+		return 0, 0, false
+	}
+	return (v[0] + v[2]) / 2, (v[1] + v[3]) / 2, true
+}
 
-	return screenBounds{
-		x: vals[0],
-		y: vals[1],
-		w: vals[2],
-		h: vals[3],
+// getScreenBounds returns the visible part of the screen the windows go
+// on, in window bounds coordinates: display n (1 is the one with the menu
+// bar) when n is set, otherwise the screen holding the calling terminal's
+// window, or else the mouse pointer. It falls back to defaultScreenBounds
+// when the screens can't be listed.
+func getScreenBounds(n int) (screenBounds, error) {
+	if n < 0 {
+		return screenBounds{}, fmt.Errorf("--display must be 1 or more")
 	}
+	screens, mouseX, mouseY, err := listScreens()
+	if err != nil {
+		if n > 1 {
+			return screenBounds{}, fmt.Errorf("could not list displays: %w", err)
+		}
+		return defaultScreenBounds, nil
+	}
+	primaryHeight := screens[0].frame.h
+	if n > 0 {
+		if n > len(screens) {
+			return screenBounds{}, fmt.Errorf("--display %d: only %d displays are connected", n, len(screens))
+		}
+		return topLeft(screens[n-1].visible, primaryHeight), nil
+	}
+
+	x, y, ok := callingWindowCenter()
+	if !ok {
+		x, y = mouseX, primaryHeight-mouseY
+	}
+	for _, scr := range screens {
+		if topLeft(scr.frame, primaryHeight).contains(x, y) {
+			return topLeft(scr.visible, primaryHeight), nil
+		}
+	}
+	return topLeft(screens[0].visible, primaryHeight), nil
 }
 
 // escapeAppleScript escapes a string for safe interpolation into AppleScript.
@@ -424,27 +538,33 @@ func escapeAppleScript(s string) string {
 	return s
 }
 
-// launchTerminalWindow opens a macOS terminal window at the given position.
-func launchTerminalWindow(cmd string, rect windowRect, useITerm bool) error {
+// launchTerminalWindow opens a macOS terminal window at the given position,
+// or wherever the terminal puts it when rect is nil.
+func launchTerminalWindow(cmd string, rect *windowRect, useITerm bool) error {
 	escaped := escapeAppleScript(cmd)
 	var script string
 
 	if useITerm {
+		bounds := ""
+		if rect != nil {
+			bounds = fmt.Sprintf("\n\tset bounds of newWindow to {%d, %d, %d, %d}", rect.left, rect.top, rect.right, rect.bottom)
+		}
 		script = fmt.Sprintf(`tell application "iTerm"
 	activate
-	set newWindow to (create window with default profile)
-	set bounds of newWindow to {%d, %d, %d, %d}
+	set newWindow to (create window with default profile)%s
 	tell current session of newWindow
 		write text "%s"
 	end tell
-end tell`, rect.left, rect.top, rect.right, rect.bottom, escaped)
+end tell`, bounds, escaped)
 	} else {
+		bounds := ""
+		if rect != nil {
+			bounds = fmt.Sprintf("\n\tdelay 0.5\n\tset bounds of front window to {%d, %d, %d, %d}", rect.left, rect.top, rect.right, rect.bottom)
+		}
 		script = fmt.Sprintf(`tell application "Terminal"
 	activate
-	do script "%s"
-	delay 0.5
-	set bounds of front window to {%d, %d, %d, %d}
-end tell`, escaped, rect.left, rect.top, rect.right, rect.bottom)
+	do script "%s"%s
+end tell`, escaped, bounds)
 	}
 
 	return exec.Command("osascript", "-e", script).Run()
@@ -452,7 +572,7 @@ end tell`, escaped, rect.left, rect.top, rect.right, rect.bottom)
 
 // launchTerminalLinux tries common terminal emulators in order.
 func launchTerminalLinux(bobaPath string) error {
-	startCmd := bobaPath + " start"
+	startCmd := shellQuote(bobaPath) + " start"
 
 	terminals := []struct {
 		bin  string
@@ -460,7 +580,7 @@ func launchTerminalLinux(bobaPath string) error {
 	}{
 		{"gnome-terminal", func(cmd string) []string { return []string{"--", "bash", "-c", cmd} }},
 		{"konsole", func(cmd string) []string { return []string{"-e", "bash", "-c", cmd} }},
-		{"xfce4-terminal", func(cmd string) []string { return []string{"-e", "bash -c '" + cmd + "'"} }},
+		{"xfce4-terminal", func(cmd string) []string { return []string{"-x", "bash", "-c", cmd} }},
 		{"xterm", func(cmd string) []string { return []string{"-e", "bash", "-c", cmd} }},
 	}
 
@@ -473,17 +593,85 @@ func launchTerminalLinux(bobaPath string) error {
 	return fmt.Errorf("no supported terminal emulator found (tried gnome-terminal, konsole, xfce4-terminal, xterm)")
 }
 
-// launchTerminalWindows tries Windows Terminal, then falls back to cmd.exe.
-func launchTerminalWindows(bobaPath string) error {
-	quoted := `"` + bobaPath + `"`
+// wtCellWidth and wtCellHeight approximate a Windows Terminal character cell
+// in pixels at 100% scaling, to turn a window rectangle into the columns and
+// rows wt.exe --size takes.
+const (
+	wtCellWidth  = 9
+	wtCellHeight = 19
+)
+
+// wtArgs returns the wt.exe arguments that open a new window at rect running
+// argv in dir. Each argument is passed on as is, so paths with spaces need no
+// quoting.
+func wtArgs(rect windowRect, dir string, argv []string) []string {
+	cols := max((rect.right-rect.left)/wtCellWidth, 20)
+	rows := max((rect.bottom-rect.top)/wtCellHeight, 5)
+	args := []string{
+		"--window", "new",
+		"--pos", fmt.Sprintf("%d,%d", rect.left, rect.top),
+		"--size", fmt.Sprintf("%d,%d", cols, rows),
+		"new-tab", "--startingDirectory", dir, "--",
+	}
+	for _, a := range argv {
+		// wt.exe splits its command line into commands at semicolons.
+		args = append(args, strings.ReplaceAll(a, ";", `\;`))
+	}
+	return args
+}
 
+// launchTerminalWindows opens a window at rect running argv in dir: a
+// Windows Terminal window when wt.exe is installed, otherwise a console
+// window, which can't be placed.
+func launchTerminalWindows(rect windowRect, dir string, argv ...string) error {
 	if wtPath, err := exec.LookPath("wt.exe"); err == nil {
-		c := exec.Command(wtPath, "new-tab", "--", quoted, "start")
-		return c.Start()
+		return exec.Command(wtPath, wtArgs(rect, dir, argv)...).Start()
 	}
-	// cmd.exe /c start requires an empty title arg (the "") before the command
-	c := exec.Command("cmd.exe", "/c", "start", "", "cmd.exe", "/k", quoted, "start")
-	return c.Start()
+	return startConsoleWindow(dir, argv)
+}
+
+func runLaunchWindows(bobaPath string) error {
+	ui.PrintLogo()
+	ui.Decor()
+
+	claudeApp, selected, err := askLaunchChoices("Claude Code (in its own window)", "windows", "Proxy Only")
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	bounds, ok := workArea()
+	if !ok {
+		bounds = defaultScreenBounds
+	}
+	chosenLayout := parseLayout(selected)
+	proxyRect, claudeRect := computeRects(chosenLayout, bounds)
+	cwd, _ := os.Getwd()
+
+	steps := proxySteps(func() error {
+		return launchTerminalWindows(proxyRect, cwd, bobaPath, "start")
+	})
+	switch {
+	case chosenLayout == layoutProxyOnly:
+	case claudeApp == "desktop":
+		steps = append(steps, launchStep{
+			label: "Opening Claude Desktop...",
+			fn:    openClaudeDesktop,
+		})
+	default:
+		steps = append(steps, launchStep{
+			label: "Opening Claude Code...",
+			fn: func() error {
+				claudePath, err := exec.LookPath("claude")
+				if err != nil {
+					return fmt.Errorf("the claude command (Claude Code) was not found in PATH")
+				}
+				return launchTerminalWindows(claudeRect, cwd, claudePath)
+			},
+		})
+	}
+
+	return runLaunchAnimation(selected, steps)
 }
 
 // openClaudeDesktop attempts to open the Claude Desktop application.
@@ -505,22 +693,78 @@ func openClaudeDesktop() error {
 	return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
 }
 
-func waitForHealth(port int, timeout time.Duration) error {
-	url := fmt.Sprintf("http://localhost:%d/health", port)
+// startErrorGrace is how long the proxy may take to start listening before
+// waitForHealth looks for the error 'boba start' records when it fails.
+const startErrorGrace = 3 * time.Second
+
+// waitForHealth waits for the proxy started at launched to answer. The port
+// is looked up on every attempt because the proxy records it once it has
+// bound one, which may not be the configured port. When nothing is listening
+// a few seconds in, the proxy's own startup error is returned as soon as it
+// has recorded one.
+func waitForHealth(launched time.Time, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
 	client := &http.Client{Timeout: 2 * time.Second}
 
+	port := config.ActiveProxyPort()
+	listening := false
 	for time.Now().Before(deadline) {
-		resp, err := client.Get(url)
+		port = config.ActiveProxyPort()
+		resp, err := client.Get(fmt.Sprintf("http://localhost:%d/health", port))
 		if err == nil {
+			listening = true
 			resp.Body.Close()
 			if resp.StatusCode == http.StatusOK {
 				return nil
 			}
+		} else if !dialFailed(err) {
+			// Something accepted the connection but didn't answer in time.
+			listening = true
+		} else if time.Since(launched) > startErrorGrace {
+			if msg, ok := config.StartErrorSince(launched); ok {
+				return fmt.Errorf("proxy failed to start: %s", msg)
+			}
 		}
 		time.Sleep(500 * time.Millisecond)
 	}
-	return fmt.Errorf("proxy did not become healthy within %s", timeout)
+	if msg, ok := config.StartErrorSince(launched); ok {
+		return fmt.Errorf("proxy failed to start: %s", msg)
+	}
+	if !listening {
+		return fmt.Errorf("nothing is listening on port %d after %s; check the proxy window for errors", port, timeout)
+	}
+	return fmt.Errorf("proxy on port %d did not become healthy within %s", port, timeout)
+}
+
+// dialFailed reports whether a request failed because no connection could be
+// made, as when nothing is listening on the port.
+func dialFailed(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// proxySteps opens a window running the proxy with start and waits for it
+// to answer, or reuses the proxy that is already running.
+func proxySteps(start func() error) []launchStep {
+	if lock, ok := runningProxy(); ok {
+		return []launchStep{{
+			label: fmt.Sprintf("Using the proxy on port %d...", lock.Port),
+			fn:    func() error { return nil },
+		}}
+	}
+	var launched time.Time
+	return []launchStep{
+		{label: "Initializing proxy...", fn: func() error {
+			launched = time.Now()
+			return start()
+		}},
+		{
+			label: "Waiting for proxy...",
+			fn: func() error {
+				return waitForHealth(launched, 15*time.Second)
+			},
+		},
+	}
 }
 
 func runLaunch(cmd *cobra.Command, args []string) error {
@@ -533,73 +777,94 @@ func runLaunch(cmd *cobra.Command, args []string) error {
 		bobaPath, _ = os.Executable()
 	}
 	bobaPath, _ = filepath.Abs(bobaPath)
-	port := config.GetProxyPort()
 
-	if runtime.GOOS == "darwin" {
-		return runLaunchMacOS(bobaPath, port)
+	if flagTmux || insideTmux() {
+		return runLaunchTmux(bobaPath)
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return runLaunchMacOS(bobaPath)
+	case "windows":
+		return runLaunchWindows(bobaPath)
 	}
-	return runLaunchGeneric(bobaPath, port)
+	return runLaunchGeneric(bobaPath)
 }
 
-func runLaunchMacOS(bobaPath string, port int) error {
-	ui.PrintLogo()
-	fmt.Println()
-
-	selected := "side-by-side"
-	claudeApp := "code"
+// askLaunchChoices asks which Claude to open and how to arrange it next to
+// the proxy. Choosing neither means the proxy runs alone. With arranged
+// empty, the windows aren't arranged and only the first is asked.
+func askLaunchChoices(codeLabel, arranged, proxyOnlyLabel string) (claudeApp, selected string, err error) {
+	claudeApp = "code"
+	if flagDesktop {
+		claudeApp = "desktop"
+	}
+	selected = "side-by-side"
+
+	fields := []huh.Field{
+		huh.NewSelect[string]().
+			Title("Which Claude do you use?").
+			Options(
+				huh.NewOption(codeLabel, "code"),
+				huh.NewOption("Claude Desktop (standalone app)", "desktop"),
+				huh.NewOption("Neither — just run the proxy", "none"),
+			).
+			Value(&claudeApp),
+	}
+	if arranged != "" {
+		fields = append(fields, huh.NewSelect[string]().
+			Title(fmt.Sprintf("How should %s be arranged?", arranged)).
+			Options(
+				huh.NewOption("Side by Side", "side-by-side"),
+				huh.NewOption("Stacked", "stacked"),
+				huh.NewOption(proxyOnlyLabel, "proxy-only"),
+			).
+			Value(&selected))
+	} else {
+		selected = "default"
+	}
 
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewSelect[string]().
-				Title("Which Claude do you use?").
-				Options(
-					huh.NewOption("Claude Code (runs in terminal)", "code"),
-					huh.NewOption("Claude Desktop (standalone app)", "desktop"),
-					huh.NewOption("Neither — just run the proxy", "none"),
-				).
-				Value(&claudeApp),
-			huh.NewSelect[string]().
-				Title("How should windows be arranged?").
-				Options(
-					huh.NewOption("Side by Side", "side-by-side"),
-					huh.NewOption("Stacked", "stacked"),
-					huh.NewOption("Proxy Only", "proxy-only"),
-				).
-				Value(&selected),
-		),
-	).WithTheme(ui.BobaTheme())
+	form := huh.NewForm(huh.NewGroup(fields...)).WithTheme(ui.BobaTheme())
 
 	if err := form.Run(); err != nil {
-		return fmt.Errorf("selection cancelled")
+		return "", "", fmt.Errorf("selection cancelled")
 	}
-
 	if claudeApp == "none" {
 		selected = "proxy-only"
 	}
+	return claudeApp, selected, nil
+}
+
+func runLaunchMacOS(bobaPath string) error {
+	ui.PrintLogo()
+	ui.Decor()
 
+	arranged := "windows"
+	if flagNoArrange {
+		arranged = ""
+	}
+	claudeApp, selected, err := askLaunchChoices("Claude Code (runs in terminal)", arranged, "Proxy Only")
+	if err != nil {
+		return err
+	}
 	fmt.Println()
 
 	chosenLayout := parseLayout(selected)
-	bounds := getScreenBounds()
 	cwd, _ := os.Getwd()
 
-	proxyRect, claudeRect := computeRects(chosenLayout, bounds)
-
-	steps := []launchStep{
-		{
-			label: "Initializing proxy...",
-			fn: func() error {
-				return launchTerminalWindow(bobaPath+" start", proxyRect, flagITerm)
-			},
-		},
-		{
-			label: "Waiting for proxy...",
-			fn: func() error {
-				return waitForHealth(port, 15*time.Second)
-			},
-		},
+	var proxyRect, claudeRect *windowRect
+	if !flagNoArrange {
+		bounds, err := getScreenBounds(flagDisplay)
+		if err != nil {
+			return err
+		}
+		p, c := computeRects(chosenLayout, bounds)
+		proxyRect, claudeRect = &p, &c
 	}
 
+	steps := proxySteps(func() error {
+		return launchTerminalWindow(shellQuote(bobaPath)+" start", proxyRect, flagITerm)
+	})
+
 	if chosenLayout != layoutProxyOnly {
 		if claudeApp == "desktop" || flagDesktop {
 			steps = append(steps, launchStep{
@@ -612,7 +877,7 @@ func runLaunchMacOS(bobaPath string, port int) error {
 			steps = append(steps, launchStep{
 				label: "Opening Claude Code...",
 				fn: func() error {
-					shellCmd := fmt.Sprintf("cd '%s' && claude", escapeAppleScript(cwd))
+					shellCmd := fmt.Sprintf("cd %s && claude", shellQuote(cwd))
 					return launchTerminalWindow(shellCmd, claudeRect, flagITerm)
 				},
 			})
@@ -622,9 +887,9 @@ func runLaunchMacOS(bobaPath string, port int) error {
 	return runLaunchAnimation(selected, steps)
 }
 
-func runLaunchGeneric(bobaPath string, port int) error {
+func runLaunchGeneric(bobaPath string) error {
 	ui.PrintLogo()
-	fmt.Println()
+	ui.Decor()
 
 	claudeApp := "code"
 	if flagDesktop {
@@ -653,27 +918,12 @@ func runLaunchGeneric(bobaPath string, port int) error {
 
 	selected := "default"
 
-	steps := []launchStep{
-		{
-			label: "Initializing proxy...",
-			fn: func() error {
-				switch runtime.GOOS {
-				case "linux":
-					return launchTerminalLinux(bobaPath)
-				case "windows":
-					return launchTerminalWindows(bobaPath)
-				default:
-					return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
-				}
-			},
-		},
-		{
-			label: "Waiting for proxy...",
-			fn: func() error {
-				return waitForHealth(port, 15*time.Second)
-			},
-		},
-	}
+	steps := proxySteps(func() error {
+		if runtime.GOOS != "linux" {
+			return fmt.Errorf("unsupported platform: %s", runtime.GOOS)
+		}
+		return launchTerminalLinux(bobaPath)
+	})
 
 	if claudeApp == "desktop" {
 		steps = append(steps, launchStep{
@@ -688,6 +938,17 @@ func runLaunchGeneric(bobaPath string, port int) error {
 }
 
 func runLaunchAnimation(selected string, steps []launchStep) error {
+	if !ui.Animate() {
+		for _, step := range steps {
+			if err := ui.Step(step.label, step.fn); err != nil {
+				return fmt.Errorf("%s: %w", step.label, err)
+			}
+		}
+		ui.Field("status", "launched")
+		ui.Field("layout", layoutDisplayName(selected))
+		return nil
+	}
+
 	model := newLaunchModel(selected, steps)
 	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithInputTTY())
 	finalModel, err := p.Run()
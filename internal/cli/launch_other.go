@@ -0,0 +1,13 @@
+//go:build !windows
+
+package cli
+
+import "fmt"
+
+// workArea measures the screen on Windows only; macOS asks AppKit instead.
+func workArea() (screenBounds, bool) { return screenBounds{}, false }
+
+// startConsoleWindow opens a console window on Windows only.
+func startConsoleWindow(dir string, argv []string) error {
+	return fmt.Errorf("console windows can only be opened on Windows")
+}
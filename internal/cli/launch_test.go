@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+// Windows Terminal gets the command's arguments one by one, so a .cmd
+// shim's path with spaces needs no quoting; only semicolons, which wt.exe
+// splits commands at, are escaped.
+func TestWTArgs(t *testing.T) {
+	rect := windowRect{left: 90, top: 38, right: 990, bottom: 988}
+	shim := `C:\Users\Jo Doe\AppData\Roaming\npm\boba.cmd`
+	got := wtArgs(rect, `C:\work\my project`, []string{shim, "start", "a;b"})
+	want := []string{
+		"--window", "new",
+		"--pos", "90,38",
+		"--size", "100,50",
+		"new-tab", "--startingDirectory", `C:\work\my project`, "--",
+		shim, "start", `a\;b`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("wtArgs =\n%q\nwant\n%q", got, want)
+	}
+
+	// A tiny rect still gets a usable window.
+	small := wtArgs(windowRect{right: 10, bottom: 10}, "C:\\", []string{shim})
+	if small[5] != "20,5" {
+		t.Errorf("size of a tiny window = %s, want 20,5", small[5])
+	}
+}
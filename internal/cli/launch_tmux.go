@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// tmuxProxyShare is the proxy pane's share of the window, matching the
+// 65/35 split of the macOS window layouts.
+const tmuxProxyShare = "35%"
+
+// insideTmux reports whether boba runs in a tmux pane.
+func insideTmux() bool {
+	return os.Getenv("TMUX") != ""
+}
+
+// runLaunchTmux runs the proxy in a new tmux pane next to this one and
+// then, unless only the proxy was asked for, Claude Code in this pane.
+func runLaunchTmux(bobaPath string) error {
+	if !insideTmux() {
+		return fmt.Errorf("--tmux must be run inside a tmux session; start one with 'tmux new -s boba' and run 'boba launch' there")
+	}
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return fmt.Errorf("$TMUX is set but the tmux command was not found in PATH")
+	}
+
+	ui.PrintLogo()
+	ui.Decor()
+
+	claudeApp, selected, err := askLaunchChoices("Claude Code (in this pane)", "panes", "Proxy Only (new tmux window)")
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	chosenLayout := parseLayout(selected)
+	steps := proxySteps(func() error {
+		return tmuxSplit(chosenLayout, bobaPath)
+	})
+	if claudeApp == "desktop" && chosenLayout != layoutProxyOnly {
+		steps = append(steps, launchStep{
+			label: "Opening Claude Desktop...",
+			fn:    openClaudeDesktop,
+		})
+	}
+
+	if err := runLaunchAnimation(selected, steps); err != nil {
+		return err
+	}
+	if claudeApp != "code" || chosenLayout == layoutProxyOnly {
+		return nil
+	}
+	return runClaudeHere()
+}
+
+// tmuxSplitArgs returns the tmux command that opens a pane running command
+// for the layout: beside this pane, below it, or in a window of its own.
+// The new pane doesn't take focus.
+func tmuxSplitArgs(l layout, command string) []string {
+	if l == layoutProxyOnly {
+		return []string{"new-window", "-d", "-n", "boba", command}
+	}
+	direction := "-h"
+	if l == layoutStacked {
+		direction = "-v"
+	}
+	args := []string{"split-window", direction, "-d", "-l", tmuxProxyShare}
+	if pane := os.Getenv("TMUX_PANE"); pane != "" {
+		args = append(args, "-t", pane)
+	}
+	return append(args, command)
+}
+
+// tmuxSplit starts `boba start` in a new tmux pane. The pane stays open if
+// the proxy fails to start so its error can be read.
+func tmuxSplit(l layout, bobaPath string) error {
+	script := shellQuote(bobaPath) + ` start || { echo; echo "boba start failed. Press enter to close this pane."; read _; }`
+	cmd := exec.Command("tmux", tmuxSplitArgs(l, "sh -c "+shellQuote(script))...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		if strings.Contains(msg, "no space for new pane") {
+			return fmt.Errorf("tmux could not split this pane: %s (enlarge or zoom out the window, or choose Proxy Only)", msg)
+		}
+		return fmt.Errorf("tmux could not open a pane for the proxy: %s (run 'boba start' in a pane yourself)", msg)
+	}
+	return nil
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// runClaudeHere runs Claude Code in this pane once the proxy is up.
+func runClaudeHere() error {
+	claudePath, err := exec.LookPath("claude")
+	if err != nil {
+		ui.Println(ui.DimStyle.Render("  Claude Code was not found in PATH. Run 'claude' here once it is installed."))
+		return nil
+	}
+	// Give the success card a moment on screen before Claude takes over.
+	time.Sleep(time.Second)
+	cmd := exec.Command(claudePath)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil // Claude reports its own errors
+		}
+		return fmt.Errorf("could not run Claude Code: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+//go:build windows
+
+package cli
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var procSystemParametersInfo = windows.NewLazySystemDLL("user32.dll").NewProc("SystemParametersInfoW")
+
+// spiGetWorkArea asks SystemParametersInfo for the primary monitor's work
+// area: the screen less the taskbar.
+const spiGetWorkArea = 0x0030
+
+// workArea returns the primary monitor's work area.
+func workArea() (screenBounds, bool) {
+	var r struct{ left, top, right, bottom int32 }
+	ok, _, _ := procSystemParametersInfo.Call(spiGetWorkArea, 0, uintptr(unsafe.Pointer(&r)), 0)
+	if ok == 0 || r.right <= r.left || r.bottom <= r.top {
+		return screenBounds{}, false
+	}
+	return screenBounds{x: int(r.left), y: int(r.top), w: int(r.right - r.left), h: int(r.bottom - r.top)}, true
+}
+
+// startConsoleWindow opens a console window running argv in dir.
+func startConsoleWindow(dir string, argv []string) error {
+	cmd := exec.Command("cmd.exe")
+	cmd.SysProcAttr = &syscall.SysProcAttr{CmdLine: consoleCmdLine(dir, argv)}
+	return cmd.Start()
+}
+
+// consoleCmdLine is the command line startConsoleWindow runs. cmd.exe
+// doesn't unquote arguments the way Go quotes them, so it is written out
+// by hand; /k strips the outer pair of quotes around the command, which
+// keeps the quotes around a .cmd shim's path.
+func consoleCmdLine(dir string, argv []string) string {
+	quoted := make([]string, len(argv))
+	for i, a := range argv {
+		quoted[i] = `"` + a + `"`
+	}
+	return fmt.Sprintf(`cmd.exe /c start "" /D "%s" cmd.exe /k "%s"`, dir, strings.Join(quoted, " "))
+}
@@ -0,0 +1,52 @@
+//go:build windows
+
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/version"
+)
+
+// The console fallback quotes each argument, so a .cmd shim under a path
+// with spaces starts as one command.
+func TestConsoleCmdLine(t *testing.T) {
+	got := consoleCmdLine(`C:\work\my project`, []string{`C:\Users\Jo Doe\AppData\Roaming\npm\boba.cmd`, "start"})
+	want := `cmd.exe /c start "" /D "C:\work\my project" cmd.exe /k ""C:\Users\Jo Doe\AppData\Roaming\npm\boba.cmd" "start""`
+	if got != want {
+		t.Errorf("consoleCmdLine =\n%s\nwant\n%s", got, want)
+	}
+}
+
+// An npm install puts a boba.cmd shim on PATH: install finds it, writes the
+// cmd.exe /c wrapper, and the entry check runs the shim through it.
+func TestInstallCmdShim(t *testing.T) {
+	bin := filepath.Join(t.TempDir(), "npm dir")
+	if err := os.MkdirAll(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	shim := filepath.Join(bin, "boba.cmd")
+	if err := os.WriteFile(shim, []byte("@echo off\r\necho boba version "+version.Version+"\r\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin)
+	t.Setenv("PATHEXT", ".COM;.EXE;.BAT;.CMD")
+
+	path, err := bobaBinaryPath()
+	if err != nil || !strings.EqualFold(path, shim) {
+		t.Fatalf("bobaBinaryPath() = %q, %v; want %s", path, err, shim)
+	}
+	command, args := mcpServerCommand(path)
+	if command != "cmd.exe" || len(args) != 3 || args[0] != "/c" || args[1] != path || args[2] != "mcp" {
+		t.Fatalf("entry = %s %q, want cmd.exe /c %s mcp", command, args, path)
+	}
+
+	config := filepath.Join(t.TempDir(), "claude_desktop_config.json")
+	writeEntry(t, config, map[string]any{"command": command, "args": args})
+	if state, reason := checkMCPEntry(config); state != mcpOK {
+		t.Errorf("entry check: %s %s", state, reason)
+	}
+}
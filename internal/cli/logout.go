@@ -84,7 +84,7 @@ func (m logoutModel) View() string {
 
 	checkStyle := lipgloss.NewStyle().Foreground(ui.ColorGreen).Bold(true)
 	activeStyle := lipgloss.NewStyle().Foreground(ui.ColorRed).Bold(true)
-	pendingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#333333"))
+	pendingStyle := lipgloss.NewStyle().Foreground(ui.ColorPending)
 
 	for i, step := range m.steps {
 		if i < m.current {
@@ -124,6 +124,14 @@ func (m logoutModel) View() string {
 }
 
 func runLogout(cmd *cobra.Command, args []string) error {
+	if !ui.Animate() {
+		if err := ui.Step("Clearing credentials from keychain...", config.ClearCredentials); err != nil {
+			return fmt.Errorf("failed to clear credentials: %w", err)
+		}
+		ui.Field("status", "logged out")
+		return nil
+	}
+
 	ui.PrintLogo()
 	fmt.Println()
 
@@ -0,0 +1,207 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/tui"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Review the activity log of a proxy session",
+	Long: "Print the tool calls of a past or running proxy session, one line per request.\n" +
+		"Session logs are kept for 14 days. Call arguments are only recorded when the\n" +
+		"proxy runs with BOBA_DEBUG=1.\n\n" +
+		"With --audit, print the audit trail instead: the full arguments and response\n" +
+		"of every swap and order change made while the proxy ran with --audit. The\n" +
+		"trail is rotated at --audit-max-size, keeping one previous file (audit.jsonl.1).",
+	RunE: runLogs,
+}
+
+var (
+	flagLogsSession    string
+	flagLogsTool       string
+	flagLogsErrorsOnly bool
+	flagLogsTail       int
+	flagLogsAudit      bool
+)
+
+func init() {
+	logsCmd.Flags().StringVar(&flagLogsSession, "session", "latest", "Session to show: latest, or a session file")
+	logsCmd.Flags().StringVar(&flagLogsTool, "tool", "", "Only calls to this tool")
+	logsCmd.Flags().BoolVar(&flagLogsErrorsOnly, "errors-only", false, "Only calls that failed")
+	logsCmd.Flags().IntVar(&flagLogsTail, "tail", 0, "Only the last N calls")
+	logsCmd.Flags().BoolVar(&flagLogsAudit, "audit", false, "Show the audit trail of swaps and order changes")
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	if flagLogsAudit {
+		return runAuditLogs()
+	}
+	path, err := proxy.FindSessionLog(flagLogsSession)
+	if err != nil {
+		return err
+	}
+	records, err := proxy.ReadSessionLog(path)
+	if err != nil {
+		return fmt.Errorf("failed to read session log: %w", err)
+	}
+
+	var shown []proxy.LogEntry
+	for _, e := range requestEntries(records) {
+		if flagLogsTool != "" && e.Tool != flagLogsTool {
+			continue
+		}
+		if flagLogsErrorsOnly && e.Status != "error" {
+			continue
+		}
+		shown = append(shown, e)
+	}
+	if flagLogsTail > 0 && len(shown) > flagLogsTail {
+		shown = shown[len(shown)-flagLogsTail:]
+	}
+
+	if !ui.Decorate() {
+		for _, e := range shown {
+			ui.Println(logLine(e))
+		}
+		return nil
+	}
+
+	ui.Println(ui.DimStyle.Render("  " + filepath.Base(path)))
+	ui.Println()
+	if len(shown) == 0 {
+		ui.Println(ui.DimStyle.Render("  No matching calls."))
+		return nil
+	}
+	for _, e := range shown {
+		ui.Println(tui.FormatLogEntry(e))
+	}
+	return nil
+}
+
+// logLine renders an entry as one tab-separated line: time, tool, status,
+// duration and preview, or the error of a failed call.
+func logLine(e proxy.LogEntry) string {
+	detail := e.Preview
+	if e.Status == "error" {
+		detail = e.Error
+		if e.ErrorKind != "" {
+			detail = e.ErrorKind + ": " + detail
+		}
+		if e.RequestID != "" {
+			detail += " (request ID " + e.RequestID + ")"
+		}
+	}
+	if e.Warning != "" {
+		detail += " (warning: " + e.Warning + ")"
+	}
+	return fmt.Sprintf("%s\t%s\t%s\t%dms\t%s",
+		e.Timestamp.Format("2006-01-02T15:04:05"), e.Tool, e.Status, e.Duration.Milliseconds(), detail)
+}
+
+// requestEntries folds a session's records into one entry per request,
+// holding its final state and start time, as the dashboard shows them.
+func requestEntries(records []proxy.SessionRecord) []proxy.LogEntry {
+	var entries []proxy.LogEntry
+	byID := make(map[string]int)
+	for _, rec := range records {
+		e := rec.LogEntry()
+		i, seen := byID[e.ID]
+		if e.ID == "" || !seen {
+			if e.ID != "" {
+				byID[e.ID] = len(entries)
+			}
+			entries = append(entries, e)
+			continue
+		}
+		if prev := entries[i]; prev.Status != "success" && prev.Status != "error" && prev.Status != proxy.StatusRateLimited {
+			e.Timestamp = prev.Timestamp
+			entries[i] = e
+		}
+	}
+	return entries
+}
+
+// runAuditLogs prints the audit trail, honoring --tool, --errors-only and
+// --tail.
+func runAuditLogs() error {
+	records, err := proxy.ReadAuditTrail()
+	if err != nil {
+		return fmt.Errorf("failed to read audit trail: %w", err)
+	}
+
+	var shown []proxy.AuditRecord
+	for _, rec := range records {
+		if flagLogsTool != "" && rec.Tool != flagLogsTool {
+			continue
+		}
+		if flagLogsErrorsOnly && !auditFailed(rec) {
+			continue
+		}
+		shown = append(shown, rec)
+	}
+	if flagLogsTail > 0 && len(shown) > flagLogsTail {
+		shown = shown[len(shown)-flagLogsTail:]
+	}
+
+	if !ui.Decorate() {
+		for _, rec := range shown {
+			detail := string(rec.Response)
+			if rec.Error != "" {
+				detail = rec.Error
+			}
+			ui.Printf("%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+				rec.Time.Local().Format("2006-01-02T15:04:05"), rec.Tool, rec.Status, rec.TxHash, compactJSON(rec.Args), detail, rec.RequestID)
+		}
+		return nil
+	}
+
+	ui.Println(ui.DimStyle.Render("  " + proxy.AuditTrailPath()))
+	ui.Println()
+	if len(shown) == 0 {
+		ui.Println(ui.DimStyle.Render("  No audited calls. Run 'boba start --audit' to record swaps and order changes."))
+		return nil
+	}
+	for _, rec := range shown {
+		status := ui.SuccessStyle.Render(fmt.Sprintf("%d", rec.Status))
+		if auditFailed(rec) {
+			status = ui.ErrorStyle.Render(fmt.Sprintf("%d", rec.Status))
+		}
+		line := "  " + ui.DimStyle.Render(rec.Time.Local().Format("2006-01-02 15:04:05")) + " " +
+			ui.ToolTag(rec.Tool) + " " + ui.BrightStyle.Render(rec.Tool) + " " + status
+		if rec.TxHash != "" {
+			line += " " + ui.GoldStyle.Render(rec.TxHash)
+		}
+		ui.Println(line)
+		ui.Println("    " + ui.DimStyle.Render("args "+compactJSON(rec.Args)))
+		if rec.RequestID != "" {
+			ui.Println("    " + ui.DimStyle.Render("request ID "+rec.RequestID))
+		}
+		if rec.Error != "" {
+			ui.Println("    " + ui.ErrorStyle.Render(rec.Error))
+		} else if len(rec.Response) > 0 {
+			ui.Println("    " + ui.DimStyle.Render("resp "+string(rec.Response)))
+		}
+	}
+	return nil
+}
+
+// auditFailed reports whether an audited call failed.
+func auditFailed(rec proxy.AuditRecord) bool {
+	return rec.Error != "" || rec.Status < 200 || rec.Status >= 300
+}
+
+// compactJSON renders v as single-line JSON.
+func compactJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/proxy"
+)
+
+// Error lines carry the full request ID to quote to the backend team.
+func TestLogLineRequestID(t *testing.T) {
+	e := proxy.LogEntry{
+		Timestamp: time.Date(2026, 1, 2, 14, 5, 0, 0, time.Local),
+		Tool:      "execute_swap",
+		Status:    "error",
+		Error:     "swap failed",
+		RequestID: "1a2b3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d",
+	}
+	if got := logLine(e); !strings.HasSuffix(got, "\tswap failed (request ID 1a2b3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d)") {
+		t.Errorf("error line = %q", got)
+	}
+	e.Status, e.Preview = "success", "Swapped"
+	if got := logLine(e); strings.Contains(got, "request ID") {
+		t.Errorf("success line = %q", got)
+	}
+}
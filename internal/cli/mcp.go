@@ -2,7 +2,11 @@ package cli
 
 import (
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -17,26 +21,73 @@ var mcpCmd = &cobra.Command{
 	RunE:   runMCP,
 }
 
+var (
+	flagMCPMaxMessage   int
+	flagMCPProxyURL     string
+	flagMCPSessionToken string
+)
+
+func init() {
+	mcpCmd.Flags().IntVar(&flagMCPMaxMessage, "max-message-mb", mcp.DefaultMaxMessageSize>>20, "Largest request or tool result passed through, in MB; bigger results are truncated")
+	mcpCmd.Flags().StringVar(&flagMCPProxyURL, "proxy-url", "", "Proxy to bridge to, e.g. an SSH-forwarded port of a proxy on another machine (or BOBA_PROXY_URL)")
+	mcpCmd.Flags().StringVar(&flagMCPSessionToken, "session-token", "", "Session token of that proxy, from 'boba session-token print' (or BOBA_SESSION_TOKEN)")
+}
+
+// onPrivateNetwork reports whether rawURL points at a private network
+// address, such as a proxy started with --bind on another machine of the
+// LAN.
+func onPrivateNetwork(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "http" {
+		return false
+	}
+	ip := net.ParseIP(u.Hostname())
+	return ip != nil && (ip.IsPrivate() || ip.IsLinkLocalUnicast())
+}
+
 func runMCP(cmd *cobra.Command, args []string) error {
-	if !config.HasCredentials() {
-		return fmt.Errorf("no credentials. Run 'boba login' first")
+	proxyURL := flagMCPProxyURL
+	if proxyURL == "" {
+		proxyURL = os.Getenv("BOBA_PROXY_URL")
+	}
+	sessionToken := flagMCPSessionToken
+	if sessionToken == "" {
+		sessionToken = os.Getenv("BOBA_SESSION_TOKEN")
 	}
 
-	port := config.GetProxyPort()
-	proxyURL := fmt.Sprintf("http://127.0.0.1:%d", port)
+	// A remote proxy holds the credentials; only a local one needs them here.
+	remote := proxyURL != ""
+	if !remote {
+		if !config.HasCredentials() {
+			return fmt.Errorf("no credentials. Run 'boba login' first")
+		}
+		proxyURL = fmt.Sprintf("http://127.0.0.1:%d", config.ActiveProxyPort())
+	}
+	proxyURL = strings.TrimRight(proxyURL, "/")
+	if !config.IsHTTPSOrLocal(proxyURL) && !onPrivateNetwork(proxyURL) {
+		return fmt.Errorf("proxy URL must use HTTPS, localhost or a private network address: %s", proxyURL)
+	}
 
 	client := &http.Client{Timeout: 3 * time.Second}
 	resp, err := client.Get(proxyURL + "/health")
 	if err != nil {
+		if remote {
+			return fmt.Errorf("proxy at %s not reachable: %w", proxyURL, err)
+		}
 		return fmt.Errorf("proxy not running. Start it with 'boba start' first")
 	}
 	resp.Body.Close()
 
-	sessionToken, err := config.GetSessionToken()
-	if err != nil || sessionToken == "" {
-		return fmt.Errorf("proxy session token not found. Is the proxy running?")
+	fixed := sessionToken != ""
+	if !fixed {
+		sessionToken, err = config.GetSessionToken()
+		if err != nil || sessionToken == "" {
+			return fmt.Errorf("proxy session token not found. Is the proxy running?")
+		}
 	}
 
 	bridge := mcp.NewBridge(proxyURL, sessionToken)
+	bridge.MaxMessageSize = flagMCPMaxMessage << 20
+	bridge.FixedToken = fixed
 	return bridge.Run()
 }
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/version"
+)
+
+// States of a Claude MCP config entry.
+const (
+	mcpOK      = "OK"
+	mcpStale   = "STALE"
+	mcpMissing = "MISSING"
+)
+
+// versionCheckTimeout bounds running the configured binary with --version.
+const versionCheckTimeout = 5 * time.Second
+
+// mcpTarget is a Claude app whose MCP config points at boba.
+type mcpTarget struct {
+	Name    string
+	Key     string // plain-output and JSON key
+	Path    func() string
+	Install func(command string, args []string) error
+}
+
+var mcpTargets = []mcpTarget{
+	{Name: "Claude Desktop", Key: "claude_desktop", Path: desktopConfigPath, Install: installDesktop},
+	{Name: "Claude Code", Key: "claude_code", Path: codeConfigPath, Install: installCode},
+}
+
+// mcpCheck is the state of one target's boba entry. Reason says what is
+// wrong when State isn't OK.
+type mcpCheck struct {
+	Target mcpTarget
+	State  string
+	Reason string
+}
+
+// checkMCPTargets checks the boba entry of every target.
+func checkMCPTargets() []mcpCheck {
+	checks := make([]mcpCheck, len(mcpTargets))
+	for i, t := range mcpTargets {
+		state, reason := checkMCPEntry(t.Path())
+		checks[i] = mcpCheck{Target: t, State: state, Reason: reason}
+	}
+	return checks
+}
+
+// checkMCPEntry checks the boba entry in the MCP config at path: that it
+// exists, that the binary it runs is there and executable, and that the
+// binary is this version of boba.
+func checkMCPEntry(path string) (state, reason string) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return mcpMissing, "no config file at " + path
+	}
+	if err != nil {
+		return mcpStale, err.Error()
+	}
+	var cfg struct {
+		MCPServers map[string]struct {
+			Command string   `json:"command"`
+			Args    []string `json:"args"`
+		} `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return mcpStale, fmt.Sprintf("%s is not valid JSON: %v", path, err)
+	}
+	entry, ok := cfg.MCPServers["boba"]
+	if !ok {
+		return mcpMissing, "no boba entry in " + path
+	}
+
+	binary := mcpEntryBinary(entry.Command, entry.Args)
+	if binary == "" {
+		return mcpStale, "the boba entry has no command"
+	}
+	info, err := os.Stat(binary)
+	if err != nil {
+		return mcpStale, binary + " does not exist"
+	}
+	// Windows has no exec bit; a .cmd or .exe runs regardless.
+	if info.IsDir() || (runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0) {
+		return mcpStale, binary + " is not executable"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionCheckTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, binary, "--version").Output()
+	if err != nil {
+		return mcpStale, fmt.Sprintf("running %s --version failed: %v", binary, err)
+	}
+	if !strings.Contains(string(out), version.Version) {
+		return mcpStale, fmt.Sprintf("%s is a different version (%s)", binary, strings.TrimSpace(string(out)))
+	}
+	return mcpOK, ""
+}
+
+// mcpEntryBinary returns the boba binary an MCP entry runs, looking through
+// the cmd.exe /c wrapper used for npm's .cmd shims on Windows.
+func mcpEntryBinary(command string, args []string) string {
+	if strings.EqualFold(command, "cmd.exe") || strings.EqualFold(command, "cmd") {
+		if len(args) >= 2 && strings.EqualFold(args[0], "/c") {
+			return args[1]
+		}
+		return ""
+	}
+	return command
+}
+
+// repairMCPTargets rewrites the boba entry of every check that isn't OK,
+// the way 'boba install' writes it. It returns the checks after the repair.
+func repairMCPTargets(checks []mcpCheck) ([]mcpCheck, error) {
+	binaryPath, err := bobaBinaryPath()
+	if err != nil {
+		return checks, err
+	}
+	command, args := mcpServerCommand(binaryPath)
+	repaired := make([]mcpCheck, len(checks))
+	for i, c := range checks {
+		repaired[i] = c
+		if c.State == mcpOK {
+			continue
+		}
+		if err := c.Target.Install(command, args); err != nil {
+			repaired[i].Reason = "repair failed: " + err.Error()
+			continue
+		}
+		repaired[i].State, repaired[i].Reason = checkMCPEntry(c.Target.Path())
+	}
+	return repaired, nil
+}
@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/version"
+)
+
+// fakeBoba writes an executable script to dir that prints versionLine for
+// --version, and returns its path.
+func fakeBoba(t *testing.T, dir, versionLine string) string {
+	t.Helper()
+	path := filepath.Join(dir, "boba")
+	script := "#!/bin/sh\necho '" + versionLine + "'\n"
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// writeEntry writes an MCP config at path whose boba entry is entry, or
+// raw JSON when entry is a string.
+func writeEntry(t *testing.T, path string, entry any) {
+	t.Helper()
+	data, ok := entry.(string)
+	if !ok {
+		b, _ := json.Marshal(map[string]any{"mcpServers": map[string]any{"boba": entry}})
+		data = string(b)
+	}
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckMCPEntry(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the boba binary")
+	}
+	dir := t.TempDir()
+	current := fakeBoba(t, dir, "boba version "+version.Version)
+	old := fakeBoba(t, t.TempDir(), "boba version 0.0.1-old")
+	notExec := filepath.Join(dir, "boba-noexec")
+	os.WriteFile(notExec, []byte("#!/bin/sh\n"), 0644)
+
+	for _, tc := range []struct {
+		name   string
+		config any // nil for no file
+		state  string
+		reason string
+	}{
+		{"no config file", nil, mcpMissing, "no config file"},
+		{"invalid JSON", `{"mcpServers":`, mcpStale, "not valid JSON"},
+		{"no boba entry", `{"mcpServers":{"other":{"command":"x"}}}`, mcpMissing, "no boba entry"},
+		{"empty command", map[string]any{"args": []string{"mcp"}}, mcpStale, "has no command"},
+		{"binary gone", map[string]any{"command": filepath.Join(dir, "gone"), "args": []string{"mcp"}}, mcpStale, "does not exist"},
+		{"directory", map[string]any{"command": dir}, mcpStale, "not executable"},
+		{"not executable", map[string]any{"command": notExec}, mcpStale, "not executable"},
+		{"other version", map[string]any{"command": old, "args": []string{"mcp"}}, mcpStale, "different version (boba version 0.0.1-old)"},
+		{"cmd.exe without a binary", map[string]any{"command": "cmd.exe", "args": []string{"mcp"}}, mcpStale, "has no command"},
+		{"cmd.exe wrapper", map[string]any{"command": "cmd.exe", "args": []string{"/c", current, "mcp"}}, mcpOK, ""},
+		{"current", map[string]any{"command": current, "args": []string{"mcp"}}, mcpOK, ""},
+	} {
+		path := filepath.Join(t.TempDir(), "claude.json")
+		if tc.config != nil {
+			writeEntry(t, path, tc.config)
+		}
+		state, reason := checkMCPEntry(path)
+		if state != tc.state || !strings.Contains(reason, tc.reason) || (tc.reason == "") != (reason == "") {
+			t.Errorf("%s: %s %q, want %s %q", tc.name, state, reason, tc.state, tc.reason)
+		}
+	}
+}
+
+func TestMCPEntryBinary(t *testing.T) {
+	for _, tc := range []struct {
+		command string
+		args    []string
+		want    string
+	}{
+		{"/usr/local/bin/boba", []string{"mcp"}, "/usr/local/bin/boba"},
+		{"cmd.exe", []string{"/c", `C:\npm\boba.cmd`, "mcp"}, `C:\npm\boba.cmd`},
+		{"CMD", []string{"/C", `C:\npm\boba.cmd`}, `C:\npm\boba.cmd`},
+		{"cmd.exe", []string{"/k", `C:\npm\boba.cmd`}, ""},
+		{"cmd.exe", nil, ""},
+		{"", nil, ""},
+	} {
+		if got := mcpEntryBinary(tc.command, tc.args); got != tc.want {
+			t.Errorf("mcpEntryBinary(%q, %q) = %q, want %q", tc.command, tc.args, got, tc.want)
+		}
+	}
+}
+
+// --repair rewrites broken entries to the boba on PATH, keeping the rest
+// of each config, and leaves OK ones alone.
+func TestRepairMCPTargets(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the boba binary")
+	}
+	t.Setenv("HOME", t.TempDir())
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	boba := fakeBoba(t, bin, "boba version "+version.Version)
+
+	desktop := desktopConfigPath()
+	os.MkdirAll(filepath.Dir(desktop), 0755)
+	writeEntry(t, desktop, `{"mcpServers":{"boba":{"command":"/old/boba","args":["mcp"]},"other":{"command":"x"}},"theme":"dark"}`)
+
+	checks := checkMCPTargets()
+	if checks[0].State != mcpStale || checks[1].State != mcpMissing {
+		t.Fatalf("before repair: %s, %s", checks[0].State, checks[1].State)
+	}
+	repaired, err := repairMCPTargets(checks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range repaired {
+		if c.State != mcpOK {
+			t.Errorf("%s after repair: %s %s", c.Target.Name, c.State, c.Reason)
+		}
+	}
+
+	var cfg struct {
+		MCPServers map[string]struct {
+			Command string   `json:"command"`
+			Args    []string `json:"args"`
+		} `json:"mcpServers"`
+		Theme string `json:"theme"`
+	}
+	data, _ := os.ReadFile(desktop)
+	json.Unmarshal(data, &cfg)
+	if e := cfg.MCPServers["boba"]; e.Command != boba || !slices.Equal(e.Args, []string{"mcp"}) {
+		t.Errorf("repaired entry = %+v, want %s mcp", e, boba)
+	}
+	if _, ok := cfg.MCPServers["other"]; !ok || cfg.Theme != "dark" {
+		t.Errorf("repair dropped the rest of the config: %s", data)
+	}
+}
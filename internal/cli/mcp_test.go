@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/zalando/go-keyring"
+)
+
+// A remote proxy URL needs no local credentials, must be HTTPS unless it is
+// local or private, and can come from the environment.
+func TestMCPProxyURL(t *testing.T) {
+	keyring.MockInit()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config.UseDir(filepath.Join(home, ".config"))
+	t.Cleanup(func() { flagMCPProxyURL, flagMCPSessionToken = "", "" })
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := "http://" + l.Addr().String()
+	l.Close()
+
+	for _, tc := range []struct {
+		name string
+		args []string
+		env  string
+		want string
+	}{
+		{"local without credentials", []string{"mcp"}, "", "no credentials"},
+		{"plain HTTP", []string{"mcp", "--proxy-url", "http://proxy.example.com:7777"}, "", "must use HTTPS"},
+		{"unreachable flag", []string{"mcp", "--proxy-url", closed + "/"}, "", "proxy at " + closed + " not reachable"},
+		{"unreachable env", []string{"mcp"}, closed, "proxy at " + closed + " not reachable"},
+	} {
+		flagMCPProxyURL = ""
+		t.Setenv("BOBA_PROXY_URL", tc.env)
+		_, _, err := run(t, tc.args...)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestSessionTokenPrint(t *testing.T) {
+	fakeBackend(t, `{}`)
+	t.Cleanup(func() { flagSessionTokenYes = false })
+
+	if _, _, err := run(t, "session-token", "print", "--yes"); err == nil || !strings.Contains(err.Error(), "no proxy is running") {
+		t.Errorf("without a proxy: %v", err)
+	}
+
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"status":"ok"}`)
+	}))
+	t.Cleanup(proxy.Close)
+	if err := config.WriteProxyLock(proxy.Listener.Addr().(*net.TCPAddr).Port); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.SetSessionToken("session-abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Output that isn't a terminal can't confirm.
+	flagSessionTokenYes = false
+	if stdout, _, err := run(t, "session-token", "print"); err == nil || strings.Contains(stdout, "session-abc") {
+		t.Errorf("printed without confirmation: %q, %v", stdout, err)
+	}
+	stdout, _, err := run(t, "session-token", "print", "--yes")
+	if err != nil || strings.TrimSpace(stdout) != "session-abc" {
+		t.Errorf("--yes: %q, %v", stdout, err)
+	}
+}
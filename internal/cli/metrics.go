@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/metrics"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var metricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Monitoring helpers for headless deployments",
+}
+
+var metricsRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Generate Prometheus alert rules or a Grafana dashboard",
+	Long: "Write a Prometheus alerting rules file for the proxy, or with --grafana a basic\n" +
+		"Grafana dashboard. Both use the metric names the proxy exposes.",
+	RunE: runMetricsRules,
+}
+
+var (
+	flagRulesOut          string
+	flagRulesJob          string
+	flagRulesErrorRate    float64
+	flagRulesAuthFailures int
+	flagRulesPollStale    time.Duration
+	flagRulesGrafana      bool
+)
+
+func init() {
+	metricsRulesCmd.Flags().StringVar(&flagRulesOut, "out", "", "File to write (default stdout)")
+	metricsRulesCmd.Flags().StringVar(&flagRulesJob, "job", "boba", "Prometheus job label the proxy is scraped under")
+	metricsRulesCmd.Flags().Float64Var(&flagRulesErrorRate, "error-rate", 5, "Alert when more than this percent of tool calls fail")
+	metricsRulesCmd.Flags().IntVar(&flagRulesAuthFailures, "auth-failures", 0, "Alert when more auth failures than this happen in 10m")
+	metricsRulesCmd.Flags().DurationVar(&flagRulesPollStale, "poll-stale", 15*time.Minute, "Alert when no portfolio poll succeeded for this long")
+	metricsRulesCmd.Flags().BoolVar(&flagRulesGrafana, "grafana", false, "Emit a Grafana dashboard JSON instead of alert rules")
+	metricsCmd.AddCommand(metricsRulesCmd)
+}
+
+func runMetricsRules(cmd *cobra.Command, args []string) error {
+	if flagRulesErrorRate <= 0 || flagRulesErrorRate > 100 {
+		return fmt.Errorf("--error-rate must be between 0 and 100")
+	}
+	if flagRulesPollStale < time.Minute {
+		return fmt.Errorf("--poll-stale must be at least 1m")
+	}
+
+	var out []byte
+	if flagRulesGrafana {
+		var err error
+		if out, err = metrics.GrafanaDashboard(flagRulesJob); err != nil {
+			return err
+		}
+		out = append(out, '\n')
+	} else {
+		out = []byte(metrics.AlertRulesYAML(metrics.RuleOptions{
+			Job:          flagRulesJob,
+			ErrorRate:    flagRulesErrorRate / 100,
+			AuthFailures: flagRulesAuthFailures,
+			PollStale:    flagRulesPollStale,
+		}))
+	}
+
+	if flagRulesOut == "" {
+		ui.Printf("%s", out)
+		return nil
+	}
+	if err := os.WriteFile(flagRulesOut, out, 0644); err != nil {
+		return err
+	}
+	ui.Field("wrote", flagRulesOut)
+	return nil
+}
@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/orders"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var ordersCmd = &cobra.Command{
+	Use:   "orders",
+	Short: "Act on your resting orders",
+}
+
+var ordersCancelAllCmd = &cobra.Command{
+	Use:   "cancel-all",
+	Short: "Cancel every active order",
+	Long: "List every active limit, DCA and TWAP order, then cancel them all after you\n" +
+		"type the confirmation shown. Exits non-zero if any order could not be cancelled.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBulkOrders(cmd, orders.Cancel)
+	},
+}
+
+var ordersPauseAllCmd = &cobra.Command{
+	Use:   "pause-all",
+	Short: "Pause every running DCA and TWAP order",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBulkOrders(cmd, orders.Pause)
+	},
+}
+
+var (
+	flagOrdersType  string
+	flagOrdersChain string
+	flagOrdersYes   bool
+)
+
+func init() {
+	for _, c := range []*cobra.Command{ordersCancelAllCmd, ordersPauseAllCmd} {
+		c.Flags().BoolVarP(&flagOrdersYes, "yes", "y", false, "Skip the typed confirmation")
+		ordersCmd.AddCommand(c)
+	}
+	ordersCancelAllCmd.Flags().StringVar(&flagOrdersType, "type", "", "Only this order type: limit, dca or twap")
+	ordersCancelAllCmd.Flags().StringVar(&flagOrdersChain, "chain", "", "Only orders on this chain")
+	ordersPauseAllCmd.Flags().StringVar(&flagOrdersType, "type", "", "Only this order type: dca or twap")
+}
+
+func runBulkOrders(cmd *cobra.Command, action orders.Action) error {
+	if !config.HasCredentials() {
+		return fmt.Errorf("no credentials configured. Run 'boba login' first")
+	}
+	ctx, stop := interruptible(cmd)
+	defer stop()
+	filter := orders.Filter{Kind: strings.ToLower(flagOrdersType), Chain: flagOrdersChain}
+	if filter.Kind != "" {
+		k, ok := orders.KindByName(filter.Kind)
+		if !ok || action.Tool(k) == "" {
+			return fmt.Errorf("--type %q can't be used to %s orders", flagOrdersType, action)
+		}
+	}
+	if filter.Chain != "" {
+		// Disabled chains are fine here: their orders may still be resting.
+		c, ok := config.LookupChain(filter.Chain)
+		if !ok {
+			return fmt.Errorf("unknown chain %q", flagOrdersChain)
+		}
+		filter.Chain = c.Slug
+	}
+
+	var list []orders.Order
+	err := ui.RunWithSpinner("Fetching orders...", func() error {
+		var err error
+		list, err = orders.Active(ctx, proxy.CallToolDirect, action, filter)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		ui.Println(fmt.Sprintf("No orders to %s.", action))
+		return nil
+	}
+
+	printBulkOrders(list)
+
+	if !flagOrdersYes {
+		if err := confirmTyped(action.ConfirmPhrase(len(list))); err != nil {
+			return err
+		}
+	}
+
+	var results []orders.Result
+	_ = ui.RunWithSpinner(fmt.Sprintf("Sending %d %s requests...", len(list), action), func() error {
+		results = orders.Apply(ctx, proxy.CallToolDirect, action, list)
+		return nil
+	})
+	return reportBulkOrders(action, results)
+}
+
+// confirmTyped makes the user type phrase exactly before a destructive bulk
+// action. Without a terminal to ask on, --yes is required.
+func confirmTyped(phrase string) error {
+	if !ui.Decorate() {
+		return fmt.Errorf("refusing to %s without confirmation; pass --yes", phrase)
+	}
+	var typed string
+	err := huh.NewInput().
+		Title(fmt.Sprintf("Type %q to confirm", phrase)).
+		Value(&typed).
+		Validate(func(s string) error {
+			if strings.TrimSpace(s) != phrase {
+				return fmt.Errorf("type %q exactly, or press ctrl+c to abort", phrase)
+			}
+			return nil
+		}).
+		WithTheme(ui.BobaTheme()).
+		Run()
+	if err != nil {
+		return fmt.Errorf("aborted: %w", err)
+	}
+	return nil
+}
+
+func printBulkOrders(list []orders.Order) {
+	total, unknown := orders.TotalUSD(list)
+	totalStr := formatter.FormatUSD(total)
+	if unknown > 0 {
+		totalStr += fmt.Sprintf(" (+%d without a price)", unknown)
+	}
+
+	if !ui.Decorate() {
+		for _, o := range list {
+			value := "-"
+			if o.HasValue {
+				value = formatter.FormatUSD(o.ValueUSD)
+			}
+			ui.Printf("%s\t%s\t%s\t%s\t%s\t%s\n", o.ID, o.Kind.Name, o.Chain, o.Status, o.Amount, value)
+		}
+		ui.Field("total", totalStr)
+		return
+	}
+
+	col := func(w int) lipgloss.Style { return lipgloss.NewStyle().Width(w) }
+	ui.Println()
+	ui.Println("  " + lipgloss.JoinHorizontal(lipgloss.Top,
+		col(12).Bold(true).Render("ID"),
+		col(7).Bold(true).Render("Type"),
+		col(10).Bold(true).Render("Chain"),
+		col(10).Bold(true).Render("Status"),
+		col(18).Bold(true).Render("Amount"),
+		col(12).Bold(true).Render("Value")))
+	for _, o := range list {
+		id := o.ID
+		if len(id) > 10 {
+			id = id[:10]
+		}
+		value := ui.DimStyle.Render("—")
+		if o.HasValue {
+			value = formatter.FormatUSD(o.ValueUSD)
+		}
+		ui.Println("  " + lipgloss.JoinHorizontal(lipgloss.Top,
+			col(12).Foreground(ui.ColorBright).Render(id),
+			col(7).Render(strings.ToUpper(o.Kind.Name)),
+			col(10).Render(o.Chain),
+			col(10).Render(o.Status),
+			col(18).Render(o.Amount),
+			col(12).Render(value)))
+	}
+	ui.Println()
+	ui.Println(fmt.Sprintf("  %d orders, %s committed", len(list), ui.GoldStyle.Render(totalStr)))
+	ui.Println()
+}
+
+// reportBulkOrders prints the outcome per order and returns an error naming
+// the orders that are still active when any failed.
+func reportBulkOrders(action orders.Action, results []orders.Result) error {
+	for _, r := range results {
+		if !ui.Decorate() {
+			status := "ok"
+			if r.Err != nil {
+				status = "fail " + r.Err.Error()
+			}
+			ui.Field(r.Order.ID, status)
+			continue
+		}
+		if r.Err != nil {
+			ui.Println(fmt.Sprintf("  %s %s %s", ui.ErrorStyle.Render("✗"), r.Order.ID, ui.DimStyle.Render(r.Err.Error())))
+		} else {
+			ui.Println(fmt.Sprintf("  %s %s", ui.SuccessStyle.Render("✓"), r.Order.ID))
+		}
+	}
+
+	failed := orders.Failed(results)
+	if len(failed) == 0 {
+		return nil
+	}
+	ids := make([]string, len(failed))
+	for i, r := range failed {
+		ids[i] = r.Order.ID
+	}
+	state := "active"
+	if action == orders.Pause {
+		state = "running"
+	}
+	return fmt.Errorf("%d of %d orders failed to %s and are still %s: %s",
+		len(failed), len(results), action, state, strings.Join(ids, ", "))
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/orders"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// A partial failure lists every order's outcome and returns an error
+// naming the orders still active, so the command exits non-zero.
+func TestReportBulkOrders(t *testing.T) {
+	var out bytes.Buffer
+	ui.SetOutput(&out, &out)
+	t.Cleanup(func() { ui.SetOutput(os.Stdout, os.Stderr) })
+
+	results := []orders.Result{
+		{Order: orders.Order{ID: "o1"}},
+		{Order: orders.Order{ID: "o2"}, Err: errors.New("timeout")},
+		{Order: orders.Order{ID: "o3"}, Err: errors.New("order not found")},
+	}
+	err := reportBulkOrders(orders.Cancel, results)
+	if err == nil || err.Error() != "2 of 3 orders failed to cancel and are still active: o2, o3" {
+		t.Errorf("err = %v", err)
+	}
+	for _, want := range []string{"o1: ok", "o2: fail timeout", "o3: fail order not found"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	if err := reportBulkOrders(orders.Pause, results[1:2]); err == nil || !strings.Contains(err.Error(), "still running: o2") {
+		t.Errorf("pause err = %v", err)
+	}
+	if err := reportBulkOrders(orders.Cancel, results[:1]); err != nil {
+		t.Errorf("all cancelled: %v", err)
+	}
+}
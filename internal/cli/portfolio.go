@@ -0,0 +1,85 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var portfolioCmd = &cobra.Command{
+	Use:   "portfolio",
+	Short: "Check your balances",
+	Long: "Fetch the agent's portfolio once and print it, without starting the proxy.\n" +
+		"Use --json for the raw response.",
+	RunE: runPortfolio,
+	// Errors are printed by runPortfolio in the error style.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+var (
+	flagPortfolioChain string
+	flagPortfolioJSON  bool
+)
+
+func init() {
+	portfolioCmd.Flags().StringVar(&flagPortfolioChain, "chain", "", "Only this chain (solana, eth, base, ...)")
+	portfolioCmd.Flags().BoolVar(&flagPortfolioJSON, "json", false, "Print the raw JSON response")
+}
+
+func runPortfolio(cmd *cobra.Command, args []string) error {
+	ctx, stop := interruptible(cmd)
+	defer stop()
+	err := showPortfolio(ctx)
+	if err != nil {
+		ui.Errorln(ui.ErrorStyle.Render("Error: " + err.Error()))
+	}
+	return err
+}
+
+func showPortfolio(ctx context.Context) error {
+	if !config.HasCredentials() {
+		return fmt.Errorf("no credentials configured. Run 'boba login' first")
+	}
+
+	toolArgs := map[string]any{"user_id": "me"}
+	if flagPortfolioChain != "" {
+		slug, err := config.NormalizeChain(flagPortfolioChain)
+		if err != nil {
+			return err
+		}
+		toolArgs["chain"] = slug
+	}
+
+	if flagPortfolioJSON {
+		body, err := proxy.CallToolDirect(ctx, "get_portfolio", toolArgs)
+		if err != nil {
+			return err
+		}
+		ui.Println(string(body))
+		return nil
+	}
+
+	var body []byte
+	err := ui.RunWithSpinner("Fetching portfolio...", func() error {
+		var err error
+		body, err = proxy.CallToolDirect(ctx, "get_portfolio", toolArgs)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	var data any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return fmt.Errorf("failed to parse portfolio data: %w", err)
+	}
+	ui.Println(formatter.FormatToolResult("get_portfolio", data))
+	return nil
+}
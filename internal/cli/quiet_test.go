@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/ui"
+	"github.com/zalando/go-keyring"
+)
+
+// fakeBackend answers authentication with a fixed agent, and tool calls
+// with reply.
+func fakeBackend(t *testing.T, reply string) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/user/auth/authenticate":
+			io.WriteString(w, `{"data":{"access_token":"access","refresh_token":"refresh",`+
+				`"access_token_expires_at":"2099-01-01T00:00:00Z","agent_id":"agent-1","agent_name":"Taro",`+
+				`"evm_address":"0x52908400098527886E0F7030069857D2E4169EE7","solana_address":"9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"}}`)
+		case "/call":
+			io.WriteString(w, reply)
+		default:
+			io.WriteString(w, `{}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	keyring.MockInit()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("PATH", t.TempDir())
+	config.UseDir(filepath.Join(home, ".config"))
+	t.Setenv(config.EnvAllowAnyHost, "1")
+	t.Setenv(config.EnvMCPURL, srv.URL)
+	t.Setenv(config.EnvAuthURL, srv.URL)
+	if err := config.SetCredentials("agent-1", "secret", "Taro"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// run executes the CLI with args and returns what it wrote to stdout and
+// stderr.
+func run(t *testing.T, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	ui.SetOutput(&out, &errOut)
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&errOut)
+	t.Cleanup(func() {
+		ui.SetOutput(os.Stdout, os.Stderr)
+		flagQuiet, flagCallRaw, flagCallJSONArgs = false, false, ""
+	})
+	rootCmd.SetArgs(args)
+	err = rootCmd.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func TestQuietAuth(t *testing.T) {
+	fakeBackend(t, `{}`)
+	stdout, stderr, err := run(t, "--quiet", "auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "agent: Taro\n" +
+		"agent_id: agent-1\n" +
+		"evm: 0x52908400098527886E0F7030069857D2E4169EE7\n" +
+		"solana: 9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM\n" +
+		"expires: 2099-01-01T00:00:00Z\n"
+	if stdout != want {
+		t.Errorf("stdout:\n%q\nwant:\n%q", stdout, want)
+	}
+	if stderr != "" {
+		t.Errorf("stderr: %q", stderr)
+	}
+}
+
+func TestQuietCall(t *testing.T) {
+	fakeBackend(t, `{"success":true,"data":{"symbol":"WIF","price_usd":1.5}}`)
+	stdout, stderr, err := run(t, "--quiet", "call", "get_token_price", "--json-args", `{"address":"WIF"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n" +
+		"  \"success\": true,\n" +
+		"  \"data\": {\n" +
+		"    \"symbol\": \"WIF\",\n" +
+		"    \"price_usd\": 1.5\n" +
+		"  }\n" +
+		"}\n"
+	if stdout != want {
+		t.Errorf("stdout:\n%q\nwant:\n%q", stdout, want)
+	}
+	if stderr != "" {
+		t.Errorf("stderr: %q", stderr)
+	}
+
+	stdout, _, err = run(t, "--quiet", "call", "get_token_price", "--raw", "--json-args", `{"address":"WIF"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"success":true,"data":{"symbol":"WIF","price_usd":1.5}}` + "\n"; stdout != want {
+		t.Errorf("--raw stdout: %q, want %q", stdout, want)
+	}
+}
+
+func TestQuietCallFailure(t *testing.T) {
+	fakeBackend(t, `{"success":false,"error":"insufficient balance"}`)
+	stdout, stderr, err := run(t, "--quiet", "call", "execute_swap", "--json-args", `{}`)
+	if err == nil {
+		t.Fatal("a failed call succeeded")
+	}
+	if want := "{\n  \"success\": false,\n  \"error\": \"insufficient balance\"\n}\n"; stdout != want {
+		t.Errorf("stdout: %q, want %q", stdout, want)
+	}
+	if want := "Error: execute_swap reported failure\n"; stderr != want {
+		t.Errorf("stderr: %q, want %q", stderr, want)
+	}
+}
@@ -1,11 +1,13 @@
 package cli
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"time"
 
@@ -13,7 +15,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
 	"github.com/tradeboba/boba-cli/internal/logger"
+	"github.com/tradeboba/boba-cli/internal/tokencache"
 	"github.com/tradeboba/boba-cli/internal/ui"
 	"github.com/tradeboba/boba-cli/internal/version"
 )
@@ -36,6 +40,7 @@ func buildMenuOptions() []menuOption {
 			menuOption{"launch    Start trading with Claude", "launch"},
 			menuOption{"start     Run the Boba proxy", "start"},
 			menuOption{"status    See if everything's working", "status"},
+			menuOption{"portfolio Check your balances", "portfolio"},
 		)
 	}
 
@@ -58,14 +63,41 @@ var rootCmd = &cobra.Command{
 	Short: "Boba Agent CLI — Connect AI agents to Boba trading",
 	Long: lipgloss.NewStyle().Foreground(ui.ColorBoba).Render(
 		"Boba Agent CLI — Connect AI agents to decentralized trading via the Boba MCP protocol"),
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		ui.SetQuiet(flagQuiet)
+		ui.SetVerbose(flagVerbose)
+		ui.InitConsole()
+		ui.SetNoColor(flagNoColor)
 		config.Load()
+		if err := config.CheckEnvOverrides(); err != nil {
+			return err
+		}
+		ui.SetAccessible(config.GetAccessible())
+		ui.SetSlowTerminal(config.GetSlowTerminal())
+		ui.SetTheme(config.GetTheme())
+		formatter.Accessible = ui.Accessible()
+		formatter.Plain = ui.NoColor()
+		formatter.Hyperlinks = ui.Hyperlinks()
+		formatter.ChainFilter = config.IsChainEnabled
+		formatter.Symbols = tokencache.Default
+		formatter.MaxPriceImpact = config.GetMaxPriceImpact()
+		applyDisplayCurrency()
 		logger.Init(config.GetLogLevel())
-		ensureMCPConfig()
+		// status reports the MCP entries as it finds them; --repair fixes them.
+		if cmd != statusCmd {
+			ensureMCPConfig()
+		}
+		return nil
 	},
 	Version: version.Version,
 }
 
+var (
+	flagQuiet   bool
+	flagVerbose bool
+	flagNoColor bool
+)
+
 func runInteractiveMenu() {
 	options := buildMenuOptions()
 
@@ -118,10 +150,16 @@ type menuModel struct {
 }
 
 func newMenuModel(items []menuOption) menuModel {
-	return menuModel{
+	m := menuModel{
 		tagline: "Connect AI agents to decentralized trading",
 		items:   items,
 	}
+	// Slow terminals get the finished menu as a single frame.
+	if ui.SlowTerminal() {
+		m.phase = menuPhaseSelect
+		m.frame = m.animDoneFrame()
+	}
+	return m
 }
 
 func menuTick() tea.Cmd {
@@ -131,6 +169,9 @@ func menuTick() tea.Cmd {
 }
 
 func (m menuModel) Init() tea.Cmd {
+	if m.phase != menuPhaseAnimation {
+		return nil
+	}
 	return menuTick()
 }
 
@@ -333,6 +374,10 @@ func (m menuModel) renderSelectItems(b *strings.Builder) {
 
 func init() {
 	rootCmd.Run = func(cmd *cobra.Command, args []string) {
+		if !ui.ANSI() {
+			_ = cmd.Help()
+			return
+		}
 		runInteractiveMenu()
 	}
 
@@ -343,8 +388,14 @@ func init() {
 			"\n",
 	)
 
+	rootCmd.PersistentFlags().BoolVarP(&flagQuiet, "quiet", "q", false, "Only print essential results and errors")
+	rootCmd.PersistentFlags().BoolVar(&flagVerbose, "verbose", false, "Print each step with timings")
+	rootCmd.PersistentFlags().BoolVar(&flagNoColor, "no-color", false, "Plain text output without colors or escape codes (also NO_COLOR=1)")
+	rootCmd.MarkFlagsMutuallyExclusive("quiet", "verbose")
+
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(startCmd)
+	rootCmd.AddCommand(stopCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(authCmd)
@@ -352,6 +403,22 @@ func init() {
 	rootCmd.AddCommand(installCmd)
 	rootCmd.AddCommand(launchCmd)
 	rootCmd.AddCommand(mcpCmd)
+	rootCmd.AddCommand(updateCmd)
+	rootCmd.AddCommand(verifyTradeCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(debugCmd)
+	rootCmd.AddCommand(metricsCmd)
+	rootCmd.AddCommand(ordersCmd)
+	rootCmd.AddCommand(alertsCmd)
+	rootCmd.AddCommand(walletCmd)
+	rootCmd.AddCommand(portfolioCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(callCmd)
+	rootCmd.AddCommand(swapCmd)
+	rootCmd.AddCommand(auditCmd)
+	rootCmd.AddCommand(toolsCmd)
+	rootCmd.AddCommand(sessionTokenCmd)
+	rootCmd.AddCommand(watchCmd)
 }
 
 // ensureMCPConfig silently updates the MCP config so Claude always
@@ -363,16 +430,7 @@ func ensureMCPConfig() {
 	}
 	bobaPath, _ = filepath.Abs(bobaPath)
 
-	var mcpCommand string
-	var mcpArgs []string
-	if runtime.GOOS == "windows" && strings.HasSuffix(strings.ToLower(bobaPath), ".cmd") {
-		mcpCommand = "cmd.exe"
-		mcpArgs = []string{"/c", bobaPath, "mcp"}
-	} else {
-		mcpCommand = bobaPath
-		mcpArgs = []string{"mcp"}
-	}
-
+	mcpCommand, mcpArgs := mcpServerCommand(bobaPath)
 	_ = installDesktop(mcpCommand, mcpArgs)
 	_ = installCode(mcpCommand, mcpArgs)
 }
@@ -380,3 +438,33 @@ func ensureMCPConfig() {
 func Execute() error {
 	return rootCmd.Execute()
 }
+
+// exitError is an error that exits the process with a code of its own,
+// so scripts can tell which step of a command failed.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+func (e *exitError) Unwrap() error { return e.err }
+
+// ExitCode returns the code the process should exit with after err: 0
+// without an error, the command's own code when it set one, and 1
+// otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var e *exitError
+	if errors.As(err, &e) {
+		return e.code
+	}
+	return 1
+}
+
+// interruptible returns cmd's context, cancelled when the user presses
+// Ctrl+C, so a slow backend call is abandoned rather than waited out.
+func interruptible(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(cmd.Context(), os.Interrupt)
+}
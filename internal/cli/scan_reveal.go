@@ -99,6 +99,12 @@ func (m revealModel) View() string {
 
 // runScanReveal runs the glitch-decrypt reveal animation for pre-rendered lines.
 func runScanReveal(lines []string) {
+	if ui.SlowTerminal() {
+		for _, l := range lines {
+			fmt.Println(l)
+		}
+		return
+	}
 	model := revealModel{lines: lines}
 	p := tea.NewProgram(model, tea.WithInputTTY())
 	if _, err := p.Run(); err != nil {
@@ -0,0 +1,60 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var sessionTokenCmd = &cobra.Command{
+	Use:   "session-token",
+	Short: "Access the running proxy's session token",
+}
+
+var sessionTokenPrintCmd = &cobra.Command{
+	Use:   "print",
+	Short: "Print the session token, for a bridge on another machine",
+	Long: "Print the running proxy's session token, to pass to 'boba mcp --session-token'\n" +
+		"on a machine that reaches this proxy over a forwarded port. Anyone with the token\n" +
+		"can call tools through the proxy until it restarts, so you are asked to confirm\n" +
+		"first; without a terminal, pass --yes.",
+	RunE: runSessionTokenPrint,
+}
+
+var flagSessionTokenYes bool
+
+func init() {
+	sessionTokenPrintCmd.Flags().BoolVarP(&flagSessionTokenYes, "yes", "y", false, "Print without asking")
+	sessionTokenCmd.AddCommand(sessionTokenPrintCmd)
+}
+
+func runSessionTokenPrint(cmd *cobra.Command, args []string) error {
+	if _, running := config.ReadProxyLock(); !running {
+		return fmt.Errorf("no proxy is running. Start it with 'boba start' first")
+	}
+	token, err := config.GetSessionToken()
+	if err != nil || token == "" {
+		return fmt.Errorf("proxy session token not found")
+	}
+
+	if !flagSessionTokenYes {
+		if !ui.Decorate() {
+			return fmt.Errorf("refusing to print the session token without confirmation; pass --yes")
+		}
+		show := false
+		err := huh.NewConfirm().
+			Title("Print the proxy session token?").
+			Description("Anyone with it can call tools through this proxy until it restarts.").
+			Value(&show).
+			WithTheme(ui.BobaTheme()).
+			Run()
+		if err != nil || !show {
+			return fmt.Errorf("aborted")
+		}
+	}
+	ui.Println(token)
+	return nil
+}
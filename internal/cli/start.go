@@ -1,17 +1,29 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
 	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/auth"
 	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/logger"
 	"github.com/tradeboba/boba-cli/internal/proxy"
 	"github.com/tradeboba/boba-cli/internal/tui"
 	"github.com/tradeboba/boba-cli/internal/ui"
+	"github.com/tradeboba/boba-cli/internal/update"
+	"github.com/tradeboba/boba-cli/internal/version"
 )
 
 var startCmd = &cobra.Command{
@@ -20,29 +32,216 @@ var startCmd = &cobra.Command{
 	RunE:  runStart,
 }
 
-var flagPort int
+var (
+	flagPort        int
+	flagDebugServer bool
+	flagChaos       string
+	flagConfirm     bool
+	flagMetricsOpen bool
+	flagRateLimit   string
+	flagTakeover    bool
+	flagNoCache     bool
+	flagNoValidate  bool
+	flagAudit       bool
+	flagAuditMax    int
+	flagMaxResponse int
+	flagNoTUI       bool
+	flagLogFormat   string
+	flagSummaryFile string
+	flagStrictTools bool
+	flagStrictGas   bool
+	flagBind        string
+	flagLANAck      bool
+)
 
 func init() {
-	startCmd.Flags().IntVarP(&flagPort, "port", "p", 0, "Port to run proxy on")
+	startCmd.Flags().IntVarP(&flagPort, "port", "p", 0, "Port to run proxy on (0 picks any free port)")
+	startCmd.Flags().BoolVar(&flagDebugServer, "debug-server", false, "Expose pprof and runtime stats on a separate localhost port (or BOBA_DEBUG=1)")
+	startCmd.Flags().StringVar(&flagChaos, "chaos", "", "Inject upstream failures, e.g. error=0.1,latency=500ms:0.2,timeout=0.05 (or BOBA_CHAOS)")
+	_ = startCmd.Flags().MarkHidden("chaos")
+	startCmd.Flags().BoolVar(&flagConfirm, "confirm-trades", false, "Hold swaps and order changes until you confirm them in the dashboard")
+	startCmd.Flags().BoolVar(&flagMetricsOpen, "metrics-public", false, "Serve /metrics without the session token, for Prometheus scrapers")
+	startCmd.Flags().BoolVar(&flagTakeover, "takeover", false, "Stop a proxy that is already running and start in its place")
+	startCmd.Flags().BoolVar(&flagNoCache, "no-cache", false, "Forward every call to the backend instead of reusing recent read-only results")
+	startCmd.Flags().BoolVar(&flagNoValidate, "no-validate", false, "Forward calls without checking their arguments against the tools' input schemas")
+	startCmd.Flags().BoolVar(&flagAudit, "audit", false, "Record the full arguments and response of swaps and order changes (see 'boba logs --audit')")
+	startCmd.Flags().IntVar(&flagAuditMax, "audit-max-size", proxy.DefaultAuditMaxSize>>20, "Size in MB at which the audit trail is rotated")
+	startCmd.Flags().IntVar(&flagMaxResponse, "max-response-size", 0, "Size in MB above which a backend response fails the call (0 uses 'boba config', 8 unless set there)")
+	startCmd.Flags().BoolVar(&flagNoTUI, "no-tui", false, "Run without the dashboard, printing one line per log entry (the default when stdout isn't a terminal)")
+	startCmd.Flags().StringVar(&flagLogFormat, "log-format", "text", "Log line format without the dashboard: text or json")
+	startCmd.Flags().StringVar(&flagSummaryFile, "summary-file", "", "Also write the session summary shown on exit to this file as JSON")
+	startCmd.Flags().BoolVar(&flagStrictTools, "strict-tools", false, "Only let agents call the tools recorded with 'boba tools pin'")
+	startCmd.Flags().BoolVar(&flagStrictGas, "strict-preflight", false, "Refuse trades when the wallet's native balance looks too low for fees, instead of only warning")
+	startCmd.Flags().StringVar(&flagBind, "bind", "", "Address to listen on, e.g. 0.0.0.0 or a LAN IP to let other machines connect (default 127.0.0.1, or 'boba config set bind')")
+	startCmd.Flags().BoolVar(&flagLANAck, "i-understand-lan-exposure", false, "Allow --bind to a non-loopback address without asking; the session token is then all that protects the proxy")
+	startCmd.Flags().StringVar(&flagRateLimit, "rate-limit", "", "Per-tool calls per second and in-flight cap, e.g. 5,burst=10,inflight=8 (off disables)")
+	startCmd.Flags().BoolVar(&flagNoUpdateCheck, "disable-update-check", false, "Don't look for a newer release (or 'boba config --update-check=false')")
 }
 
+// runStart runs the proxy. A failure to start is recorded for 'boba launch',
+// which opens the proxy in a window of its own.
 func runStart(cmd *cobra.Command, args []string) error {
+	config.ClearStartError()
+	started := false
+	err := startProxy(cmd, &started)
+	if err != nil && !started {
+		config.RecordStartError(err)
+	}
+	return err
+}
+
+// startProxy starts the proxy and runs it until it is stopped. started is set
+// once the proxy is serving.
+func startProxy(cmd *cobra.Command, started *bool) error {
+	if flagLogFormat != "text" && flagLogFormat != "json" {
+		return fmt.Errorf("invalid --log-format %q, expected text or json", flagLogFormat)
+	}
 	if !config.HasCredentials() {
 		return fmt.Errorf("no credentials configured. Run 'boba login' first")
 	}
 
+	// A second proxy would fight the first over the session token.
+	if lock, ok := runningProxy(); ok {
+		if !flagTakeover {
+			return fmt.Errorf("proxy already running on port %d (pid %d). Stop it with 'boba stop' or pass --takeover", lock.Port, lock.PID)
+		}
+		ui.Errorln(fmt.Sprintf("stopping the proxy on port %d (pid %d)", lock.Port, lock.PID))
+		if err := stopProxy(lock.Port); err != nil {
+			return err
+		}
+	} else if _, locked := config.ReadProxyLock(); !locked {
+		// A token with no live proxy behind it was left by one that was
+		// killed before it could clear it. One that is still starting up
+		// holds the lock, so its token is left alone.
+		if token, _ := config.GetSessionToken(); token != "" {
+			logger.Info("clearing the session token of a proxy that didn't shut down")
+			_ = config.ClearSessionToken()
+		}
+	}
+
+	// An explicit --port is used as given; the configured port may move
+	// to a free one when it's taken.
 	port := flagPort
-	if port == 0 {
+	fallback := !cmd.Flags().Changed("port")
+	if fallback {
 		port = config.GetProxyPort()
 	}
 
+	bind := flagBind
+	if bind == "" {
+		bind = config.GetBindAddress()
+	} else if bind != "localhost" && net.ParseIP(bind) == nil {
+		return fmt.Errorf("invalid --bind %q, expected an IP address such as 127.0.0.1 or 0.0.0.0", bind)
+	}
+	if err := acknowledgeLANExposure(bind, flagLANAck); err != nil {
+		return err
+	}
+
 	server, err := proxy.NewProxyServer(port)
 	if err != nil {
 		return fmt.Errorf("failed to create proxy server: %w", err)
 	}
+	// However this returns, the session token and lock go with it. A token
+	// that never served anything is just cleared.
+	stop := sync.OnceValue(func() error {
+		if !*started {
+			return config.ClearSessionToken()
+		}
+		return server.Stop()
+	})
+	stopOnExit.Store(&stop)
+	defer stop()
 
-	if err := server.Start(); err != nil {
-		return fmt.Errorf("failed to start proxy server: %w", err)
+	chaos := flagChaos
+	if chaos == "" {
+		chaos = os.Getenv("BOBA_CHAOS")
+	}
+	if chaos != "" {
+		spec, err := proxy.ParseChaosSpec(chaos)
+		if err != nil {
+			return err
+		}
+		if err := server.EnableChaos(spec); err != nil {
+			return err
+		}
+		ui.Errorln("warning: chaos injection on: " + spec.String())
+	}
+
+	if flagConfirm || config.GetConfirmTrades() {
+		if ui.ANSI() {
+			server.EnableConfirmations(proxy.DefaultConfirmTimeout)
+		} else {
+			// Nobody can answer a prompt without the dashboard, so write
+			// calls are declined rather than executed unconfirmed.
+			server.EnableConfirmations(0)
+			ui.Errorln("warning: trade confirmation needs the dashboard; swaps and order changes will be declined")
+		}
+	}
+
+	if flagRateLimit != "" {
+		limit, err := proxy.ParseRateLimit(flagRateLimit, server.RateLimit())
+		if err != nil {
+			return err
+		}
+		server.SetRateLimit(limit)
+	}
+
+	if flagStrictTools {
+		pinned, err := proxy.LoadManifest(proxy.PinnedManifestPath())
+		if err != nil {
+			return err
+		}
+		if pinned == nil {
+			return fmt.Errorf("--strict-tools needs a pinned tool manifest; record one with 'boba tools pin'")
+		}
+		server.SetStrictTools(pinned)
+	}
+
+	if flagMaxResponse < 0 {
+		return fmt.Errorf("--max-response-size can't be negative")
+	} else if flagMaxResponse > 0 {
+		server.SetMaxResponseSize(int64(flagMaxResponse) << 20)
+	}
+	server.SetStrictPreflight(flagStrictGas)
+	server.SetMetricsPublic(flagMetricsOpen)
+	if flagNoCache {
+		server.DisableCache()
+	}
+	if flagNoValidate {
+		server.DisableValidation()
+	}
+	server.SetPortFallback(fallback)
+	server.SetBindAddress(bind)
+
+	// Keep a copy of the activity log for `boba logs`. The proxy runs
+	// fine without one.
+	if _, err := server.EnableSessionLog(); err != nil {
+		ui.Errorln("warning: " + err.Error())
+	}
+	if flagAudit || config.GetAuditTrail() {
+		if err := server.EnableAuditTrail(int64(flagAuditMax) << 20); err != nil {
+			ui.Errorln("warning: " + err.Error())
+		}
+	}
+
+	// listen binds the listener; the dashboard does it as a boot step so a
+	// port conflict shows up there.
+	listen := func() (moved string, err error) {
+		if err := server.Start(); err != nil {
+			return "", fmt.Errorf("failed to start proxy server: %w", err)
+		}
+		if port != 0 && server.Port() != port {
+			moved = fmt.Sprintf("port %d is in use; the proxy is on port %d instead", port, server.Port())
+		}
+		*started = true
+		if flagDebugServer || os.Getenv("BOBA_DEBUG") == "1" {
+			if err := server.StartDebugServer(); err != nil {
+				_ = stop()
+				*started = false
+				return "", err
+			}
+		}
+		return moved, nil
 	}
 
 	agentName := ""
@@ -55,22 +254,270 @@ func runStart(cmd *cobra.Command, args []string) error {
 		solAddr = tokens.SolanaAddress
 	}
 
-	model := tui.NewProxyViewModel(server, agentName, evmAddr, solAddr, port)
-	p := tea.NewProgram(model, tea.WithAltScreen())
+	updates := checkForUpdate()
+	if flagNoTUI || !ui.ANSI() || !ui.StdoutIsTerminal() {
+		moved, err := listen()
+		if err != nil {
+			return err
+		}
+		if moved != "" {
+			ui.Errorln("warning: " + moved)
+		}
+		return runStartHeadless(server, stop, updates)
+	}
+
+	// Values are shown at the built-in rate until the live one arrives.
+	go refreshCurrencyRate(cmd.Context())
+	// The dashboard clips and scrolls formatted results, which would cut
+	// links apart.
+	formatter.Hyperlinks = false
 
+	boot := []tui.BootTask{
+		// NewProxyServer has already generated and stored the session token.
+		nil,
+		func() (string, error) {
+			moved, err := listen()
+			if err != nil || moved != "" {
+				return moved, err
+			}
+			return strings.TrimPrefix(server.URL(), "http://"), nil
+		},
+		func() (string, error) {
+			tokens, err := auth.EnsureAuthenticated()
+			if err != nil {
+				return "", err
+			}
+			return tokens.AgentName, nil
+		},
+		func() (string, error) {
+			n, err := server.SyncTools(cmd.Context())
+			if err != nil {
+				return "", fmt.Errorf("failed to fetch the tool manifest: %w", err)
+			}
+			if n == 1 {
+				return "1 tool", nil
+			}
+			return fmt.Sprintf("%d tools", n), nil
+		},
+	}
+	model := tui.NewProxyViewModel(server, agentName, evmAddr, solAddr, port, boot)
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithoutSignalHandler())
+
+	// A signal plays the shutdown sequence like q does, unless the
+	// terminal is gone (SIGHUP) or the sequence doesn't finish in time.
 	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigCh, stopSignals...)
+	defer signal.Stop(sigCh)
 	go func() {
-		<-sigCh
-		p.Send(tea.Quit())
+		sig := <-sigCh
+		if sig != syscall.SIGHUP {
+			p.Send(tui.ShutdownMsg{})
+			time.Sleep(signalQuitGrace)
+		}
+		p.Quit()
 	}()
 
-	if _, err := p.Run(); err != nil {
-		_ = server.Stop()
+	if updates != nil {
+		go func() {
+			if notice := <-updates; notice != nil {
+				p.Send(tui.UpdateMsg{Version: notice.Version, Notes: notice.Notes})
+			}
+		}()
+	}
+
+	final, err := p.Run()
+	_ = stop()
+	if err != nil {
 		return fmt.Errorf("TUI error: %w", err)
 	}
+	view := final.(tui.ProxyViewModel)
+	if err := view.BootErr(); err != nil {
+		return err
+	}
 
-	_ = server.Stop()
+	if sum := view.Summary(); sum != nil {
+		fmt.Print("\n" + tui.RenderSessionSummary(*sum))
+		writeSessionSummary(*sum)
+	}
 	fmt.Println(ui.DimStyle.Render("\n  Proxy stopped. Goodbye!\n"))
 	return nil
 }
+
+// acknowledgeLANExposure lets the proxy listen on bind. A non-loopback
+// address puts it on the network with only the session token in the way,
+// so that needs --i-understand-lan-exposure or a yes at a prompt.
+func acknowledgeLANExposure(bind string, acknowledged bool) error {
+	if proxy.IsLoopback(bind) {
+		return nil
+	}
+	warning := fmt.Sprintf("listening on %s lets other machines reach the proxy; the session token is all that stops them from trading with your wallet", bind)
+	if acknowledged {
+		ui.Errorln("warning: " + warning)
+		return nil
+	}
+	if !ui.Decorate() || !ui.StdoutIsTerminal() {
+		return fmt.Errorf("%s. Pass --i-understand-lan-exposure to do it anyway", warning)
+	}
+	ok := false
+	err := huh.NewConfirm().
+		Title(fmt.Sprintf("Expose the proxy on %s?", bind)).
+		Description("Other machines on your network can reach it; only the session token stops them from trading with your wallet.").
+		Value(&ok).
+		WithTheme(ui.BobaTheme()).
+		Run()
+	if err != nil || !ok {
+		return fmt.Errorf("not exposing the proxy on %s; pass --bind 127.0.0.1 to keep it on this machine", bind)
+	}
+	return nil
+}
+
+// writeSessionSummary writes the session summary to --summary-file, if set.
+// The proxy has already stopped, so a failure is only reported.
+func writeSessionSummary(sum proxy.SessionSummary) {
+	if flagSummaryFile == "" {
+		return
+	}
+	data, err := json.MarshalIndent(sum, "", "  ")
+	if err == nil {
+		err = os.WriteFile(flagSummaryFile, append(data, '\n'), 0o600)
+	}
+	if err != nil {
+		ui.Errorln(ui.ErrorStyle.Render("failed to write the session summary: " + err.Error()))
+	}
+}
+
+// runStartHeadless keeps the proxy running without the dashboard, under a
+// service manager, in a container or on a console that can't render it. The
+// activity log goes to stdout, one line per entry; the portfolio isn't
+// polled. A newer release found by updates is noted on stderr.
+func runStartHeadless(server *proxy.ProxyServer, stop func() error, updates <-chan *update.Notice) error {
+	// Catch signals before saying the proxy runs, so one sent as soon as
+	// it does still stops it cleanly.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, stopSignals...)
+	defer signal.Stop(sigCh)
+
+	status := ui.Field
+	if flagLogFormat == "json" {
+		// Keep stdout to log records.
+		status = func(key, value string) { ui.Errorln(key + ": " + value) }
+	}
+	status("status", "running")
+	status("proxy", server.URL())
+	if flagLogFormat == "text" {
+		ui.Println("Press Ctrl+C to stop.")
+	}
+
+	logs := server.LogChannel()
+	debug := os.Getenv("BOBA_DEBUG") == "1"
+	var repeats repeatedErrors
+running:
+	for {
+		select {
+		case entry := <-logs:
+			repeats.print(entry, debug)
+		case notice := <-updates:
+			updates = nil
+			if notice != nil {
+				ui.Errorln(fmt.Sprintf("boba %s is available (you have %s); run 'boba update --install'", notice.Version, version.Version))
+			}
+		case <-sigCh:
+			break running
+		case <-server.ShutdownRequested():
+			break running
+		}
+	}
+
+	_ = stop()
+	// Entries logged while stopping are still printed.
+	for {
+		select {
+		case entry := <-logs:
+			repeats.print(entry, debug)
+		default:
+			repeats.flush()
+			sum := server.Summary()
+			if flagLogFormat == "text" {
+				ui.Printf("%s", tui.RenderSessionSummary(sum))
+			}
+			writeSessionSummary(sum)
+			status("status", "stopped")
+			return nil
+		}
+	}
+}
+
+// repeatedErrorsEvery is how many repeats of an error the text log holds
+// back before saying it is still failing.
+const repeatedErrorsEvery = 100
+
+// repeatedErrors folds a run of identical errors in the text log into one
+// line with a count, printed when the run ends. JSON records are all
+// printed, with their repeat count.
+type repeatedErrors struct {
+	last  proxy.LogEntry // latest repeat not printed yet
+	count int            // repeats not printed yet
+}
+
+// print prints an entry, holding back repeats of the error before it.
+func (r *repeatedErrors) print(entry proxy.LogEntry, debug bool) {
+	if flagLogFormat == "json" {
+		printLogEntry(entry, debug)
+		return
+	}
+	if entry.Repeat > 1 {
+		r.last = entry
+		r.count++
+		if entry.Repeat%repeatedErrorsEvery == 0 {
+			r.flush()
+		}
+		return
+	}
+	if !entry.InFlight() {
+		r.flush()
+	}
+	printLogEntry(entry, debug)
+}
+
+// flush prints the repeats held back, if any, as one line.
+func (r *repeatedErrors) flush() {
+	if r.count == 0 {
+		return
+	}
+	ui.Println(logLine(r.last) + fmt.Sprintf(" (×%d, last %s)", r.last.Repeat, r.last.Timestamp.Format("15:04:05")))
+	r.count = 0
+}
+
+// printLogEntry prints a log entry in the --log-format.
+func printLogEntry(entry proxy.LogEntry, debug bool) {
+	if flagLogFormat != "json" {
+		ui.Println(logLine(entry))
+		return
+	}
+	data, err := json.Marshal(entry.Record(debug))
+	if err != nil {
+		return
+	}
+	ui.Println(string(data))
+}
+
+// stopSignals stop the proxy cleanly: ^C, kill or systemd, and closing the
+// terminal it runs in.
+var stopSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
+
+// signalQuitGrace is how long a signalled dashboard gets to play its
+// shutdown sequence before it is closed regardless.
+const signalQuitGrace = 2 * time.Second
+
+// stopOnExit stops the proxy this process started, if any. StopProxy calls
+// it as a last resort.
+var stopOnExit atomic.Pointer[func() error]
+
+// StopProxy stops the proxy started by this process if it is still up,
+// clearing its session token and lock. It is safe to call at any time and
+// more than once; main defers it in case 'boba start' exits some other way.
+func StopProxy() {
+	if stop := stopOnExit.Load(); stop != nil {
+		_ = (*stop)()
+	}
+}
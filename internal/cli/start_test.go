@@ -0,0 +1,198 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+	"github.com/zalando/go-keyring"
+)
+
+// TestStartHeadlessHelper is the proxy process TestStartHeadlessSignal
+// signals. It runs 'boba start --no-tui' with an in-memory keyring and
+// reports the session token left in it once the command returns.
+func TestStartHeadlessHelper(t *testing.T) {
+	dir := os.Getenv("BOBA_TEST_START_DIR")
+	if dir == "" {
+		t.Skip("run by TestStartHeadlessSignal")
+	}
+	keyring.MockInit()
+	config.UseDir(dir)
+	if err := config.SetCredentials("agent-1", "secret", "Taro"); err != nil {
+		t.Fatal(err)
+	}
+	rootCmd.SetArgs([]string{"start", "--no-tui", "--port", "0", "--disable-update-check"})
+	err := rootCmd.Execute()
+	token, _ := config.GetSessionToken()
+	fmt.Printf("exited: err=%v token=%q\n", err, token)
+}
+
+// A headless proxy sent SIGTERM stops cleanly: it clears its session token
+// and lock and prints its summary.
+func TestStartHeadlessSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no SIGTERM on Windows")
+	}
+	home := t.TempDir()
+	dir := filepath.Join(home, ".config")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestStartHeadlessHelper$", "-test.v")
+	cmd.Env = append(os.Environ(), "BOBA_TEST_START_DIR="+dir, "HOME="+home, "BOBA_DEBUG=")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cmd.Process.Kill(); cmd.Wait() })
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(stdout)
+		for sc.Scan() {
+			lines <- sc.Text()
+		}
+	}()
+	var out []string
+	wait := func(prefix string) {
+		t.Helper()
+		timeout := time.After(10 * time.Second)
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					t.Fatalf("proxy exited before %q:\n%s", prefix, strings.Join(out, "\n"))
+				}
+				out = append(out, line)
+				if strings.HasPrefix(line, prefix) {
+					return
+				}
+			case <-timeout:
+				t.Fatalf("no %q within 10s:\n%s", prefix, strings.Join(out, "\n"))
+			}
+		}
+	}
+
+	wait("proxy:")
+	config.UseDir(dir)
+	lockPath := filepath.Join(config.DataDir(), "proxy.lock")
+	if lock, ok := config.ReadProxyLock(); !ok || lock.PID != cmd.Process.Pid {
+		t.Fatalf("running proxy's lock = %+v, %v", lock, ok)
+	}
+
+	if err := cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	wait("exited:")
+	if got := out[len(out)-1]; got != `exited: err=<nil> token=""` {
+		t.Errorf("after SIGTERM: %s", got)
+	}
+	if !strings.Contains(strings.Join(out, "\n"), "status: stopped") {
+		t.Errorf("no clean stop in the output:\n%s", strings.Join(out, "\n"))
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("proxy lock left behind: %v", err)
+	}
+}
+
+// The text log prints a run of repeated errors once more, as a single line
+// with the count, when the run ends; JSON prints every record.
+func TestRepeatedErrorsText(t *testing.T) {
+	var out bytes.Buffer
+	ui.SetOutput(&out, &out)
+	t.Cleanup(func() { ui.SetOutput(os.Stdout, os.Stderr); flagLogFormat = "text" })
+
+	at := time.Date(2026, 1, 2, 14, 32, 0, 0, time.UTC)
+	failed := func(repeat int) proxy.LogEntry {
+		return proxy.LogEntry{Timestamp: at.Add(time.Duration(repeat) * time.Second), Tool: "get_token_info", Status: "error", Error: "boom", Repeat: repeat}
+	}
+	feed := func() {
+		var r repeatedErrors
+		r.print(failed(0), false)
+		for n := 2; n <= 23; n++ {
+			r.print(failed(n), false)
+		}
+		r.print(proxy.LogEntry{Timestamp: at, Tool: "get_portfolio", Status: "pending"}, false)
+		r.print(proxy.LogEntry{Timestamp: at.Add(time.Minute), Tool: "get_portfolio", Status: "success"}, false)
+		r.flush()
+	}
+
+	flagLogFormat = "text"
+	feed()
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("%d lines, want 4:\n%s", len(lines), out.String())
+	}
+	if !strings.Contains(lines[1], "\tpending\t") || !strings.HasSuffix(lines[2], "boom (×23, last 14:32:23)") || !strings.Contains(lines[3], "\tsuccess\t") {
+		t.Errorf("text log:\n%s", out.String())
+	}
+
+	out.Reset()
+	flagLogFormat = "json"
+	feed()
+	if n := strings.Count(out.String(), "\n"); n != 25 {
+		t.Errorf("%d JSON records, want 25", n)
+	}
+	if !strings.Contains(out.String(), `"repeat":23`) {
+		t.Errorf("JSON records lack the repeat count:\n%s", out.String())
+	}
+}
+
+// A non-loopback --bind, or bind address in the config, needs
+// --i-understand-lan-exposure when there is no terminal to confirm at;
+// loopback needs nothing.
+func TestBindAcknowledgement(t *testing.T) {
+	fakeBackend(t, `{}`)
+	t.Cleanup(func() { flagBind, flagLANAck, flagNoTUI = "", false, false })
+
+	for _, args := range [][]string{
+		{"start", "--no-tui", "--bind", "0.0.0.0"},
+		{"start", "--no-tui", "--bind", "192.168.1.20"},
+	} {
+		flagBind = ""
+		if _, _, err := run(t, args...); err == nil || !strings.Contains(err.Error(), "--i-understand-lan-exposure") {
+			t.Errorf("%v: err = %v, want the acknowledgement asked for", args, err)
+		}
+	}
+	flagBind = ""
+	if _, _, err := run(t, "start", "--no-tui", "--bind", "lan"); err == nil || !strings.Contains(err.Error(), "invalid --bind") {
+		t.Errorf("--bind lan: err = %v", err)
+	}
+
+	flagBind = ""
+	if err := config.SetBindAddress("0.0.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := run(t, "start", "--no-tui"); err == nil || !strings.Contains(err.Error(), "--i-understand-lan-exposure") {
+		t.Errorf("configured bind: err = %v, want the acknowledgement asked for", err)
+	}
+	if _, ok := config.ReadProxyLock(); ok {
+		t.Error("proxy started without the acknowledgement")
+	}
+
+	var out bytes.Buffer
+	ui.SetOutput(&out, &out)
+	for _, bind := range []string{"127.0.0.1", "localhost", "::1"} {
+		if err := acknowledgeLANExposure(bind, false); err != nil {
+			t.Errorf("%s: %v", bind, err)
+		}
+	}
+	if out.Len() != 0 {
+		t.Errorf("loopback warned: %q", out.String())
+	}
+	if err := acknowledgeLANExposure("0.0.0.0", true); err != nil || !strings.Contains(out.String(), "warning: listening on 0.0.0.0") {
+		t.Errorf("acknowledged: %v, %q", err, out.String())
+	}
+}
@@ -1,13 +1,18 @@
 package cli
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"github.com/tradeboba/boba-cli/internal/config"
 	"github.com/tradeboba/boba-cli/internal/ui"
+	"github.com/tradeboba/boba-cli/internal/update"
+	"github.com/tradeboba/boba-cli/internal/version"
 )
 
 var statusCmd = &cobra.Command{
@@ -16,7 +21,117 @@ var statusCmd = &cobra.Command{
 	RunE:  runStatus,
 }
 
-func buildStatusLines() []string {
+var (
+	flagStatusJSON   bool
+	flagStatusRepair bool
+)
+
+func init() {
+	statusCmd.Flags().BoolVar(&flagStatusJSON, "json", false, "Print the status as JSON, for scripts and status lines")
+	statusCmd.Flags().BoolVar(&flagStatusRepair, "repair", false, "Rewrite Claude's boba MCP entries that are missing or stale")
+	statusCmd.Flags().BoolVar(&flagNoUpdateCheck, "disable-update-check", false, "Don't look for a newer release (or 'boba config --update-check=false')")
+}
+
+// statusReport is the --json output. Every key is always present; values
+// that are unknown, such as the proxy port when no proxy runs, are null.
+type statusReport struct {
+	Authenticated         bool    `json:"authenticated"`
+	AgentID               *string `json:"agentId"`
+	AgentName             *string `json:"agentName"`
+	EVMAddress            *string `json:"evmAddress"`
+	SolanaAddress         *string `json:"solanaAddress"`
+	TokenExpiresAt        *string `json:"tokenExpiresAt"`
+	TokenExpiresInSeconds *int64  `json:"tokenExpiresInSeconds"`
+	ProxyRunning          bool    `json:"proxyRunning"`
+	ProxyPort             *int    `json:"proxyPort"`
+	ProxyPID              *int    `json:"proxyPid"`
+	ProxyRequestCount     *int64  `json:"proxyRequestCount"`
+	ProxyUptimeSeconds    *int64  `json:"proxyUptimeSeconds"`
+	MCPURL                string  `json:"mcpUrl"`
+	AuthURL               string  `json:"authUrl"`
+	Version               string  `json:"version"`
+	// LatestVersion is a newer release found by the daily update check.
+	LatestVersion *string `json:"latestVersion"`
+
+	// CustomHostsInUse are the hosts added with 'boba config allow-host'
+	// that the MCP or auth URL points at.
+	CustomHostsInUse []string `json:"customHostsInUse"`
+
+	// MCPConfig is the state of boba's entry in each Claude app's config.
+	MCPConfig []mcpConfigReport `json:"mcpConfig"`
+}
+
+// mcpConfigReport is the state of one Claude app's boba entry in --json.
+type mcpConfigReport struct {
+	Target string  `json:"target"`
+	State  string  `json:"state"`
+	Reason *string `json:"reason"`
+}
+
+// nullable returns a pointer to v, or nil for the zero value, so it encodes
+// as null.
+func nullable[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
+}
+
+// buildStatusReport gathers the status for --json. Proxy details come from
+// its /health endpoint.
+func buildStatusReport(checks []mcpCheck) statusReport {
+	r := statusReport{
+		MCPURL:    config.GetMCPURL(),
+		AuthURL:   config.GetAuthURL(),
+		Version:   version.Version,
+		MCPConfig: make([]mcpConfigReport, 0, len(checks)),
+
+		CustomHostsInUse: append([]string{}, customHostsInUse()...),
+	}
+	for _, c := range checks {
+		r.MCPConfig = append(r.MCPConfig, mcpConfigReport{Target: c.Target.Key, State: c.State, Reason: nullable(c.Reason)})
+	}
+
+	if c := config.Load(); c.Credentials != nil {
+		r.AgentID = nullable(c.Credentials.AgentID)
+	}
+	if tokens, err := config.GetTokens(); err == nil {
+		if r.AgentID == nil {
+			r.AgentID = nullable(tokens.AgentID)
+		}
+		r.AgentName = nullable(tokens.AgentName)
+		r.EVMAddress = nullable(tokens.EVMAddress)
+		r.SolanaAddress = nullable(tokens.SolanaAddress)
+	}
+	if expiresAt, ok, err := config.AccessTokenExpiry(); ok && err == nil {
+		at := expiresAt.UTC().Format(time.RFC3339)
+		left := max(int64(time.Until(expiresAt).Seconds()), 0)
+		r.TokenExpiresAt = &at
+		r.TokenExpiresInSeconds = &left
+		r.Authenticated = config.HasCredentials() && left > 0
+	}
+
+	port := config.ActiveProxyPort()
+	if health, ok := proxyHealth(port); ok {
+		r.ProxyRunning = true
+		r.ProxyPort = &port
+		if lock, ok := config.ReadProxyLock(); ok && lock.Port == port {
+			r.ProxyPID = &lock.PID
+		}
+		if n, ok := health["requests"].(float64); ok {
+			requests := int64(n)
+			r.ProxyRequestCount = &requests
+		}
+		if n, ok := health["uptime"].(float64); ok {
+			uptime := int64(n)
+			r.ProxyUptimeSeconds = &uptime
+		}
+	}
+	return r
+}
+
+func buildStatusLines(checks []mcpCheck) []string {
 	var lines []string
 
 	for _, l := range strings.Split(ui.RenderLogo(), "\n") {
@@ -34,6 +149,7 @@ func buildStatusLines() []string {
 	brightVal := lipgloss.NewStyle().Foreground(ui.ColorBright)
 	greenDot := lipgloss.NewStyle().Foreground(ui.ColorGreen).Render("●")
 	redDot := lipgloss.NewStyle().Foreground(ui.ColorRed).Render("●")
+	dimDot := lipgloss.NewStyle().Foreground(ui.ColorDim).Render("●")
 
 	var statusRows []string
 	statusRows = append(statusRows, headerStyle.Render(" CONNECTION STATUS "))
@@ -72,6 +188,18 @@ func buildStatusLines() []string {
 					fmt.Sprintf("  %s %s %s", greenDot, dimLabel.Render("Solana"), brightVal.Render(truncateAddr(tokens.SolanaAddress))))
 			}
 		}
+
+		port := config.ActiveProxyPort()
+		if health, ok := proxyHealth(port); !ok {
+			statusRows = append(statusRows,
+				fmt.Sprintf("  %s %s %s", dimDot, dimLabel.Render("Proxy"), ui.DimStyle.Render("not running")))
+		} else if enforced, _ := health["authEnforced"].(bool); enforced {
+			statusRows = append(statusRows,
+				fmt.Sprintf("  %s %s %s", greenDot, dimLabel.Render("Proxy"), ui.SuccessStyle.Render(fmt.Sprintf("%s, auth enforced ✓", proxyLabel(port)))))
+		} else {
+			statusRows = append(statusRows,
+				fmt.Sprintf("  %s %s %s", redDot, dimLabel.Render("Proxy"), ui.ErrorStyle.Render(fmt.Sprintf("%s, auth not enforced ✗ (restart it with 'boba start')", proxyLabel(port)))))
+		}
 	} else {
 		statusRows = append(statusRows,
 			fmt.Sprintf("  %s %s %s", redDot, dimLabel.Render("Credentials"), ui.ErrorStyle.Render("not initialized")))
@@ -104,11 +232,36 @@ func buildStatusLines() []string {
 	var cfgRows []string
 	cfgRows = append(cfgRows, cfgHeader.Render(" CONFIGURATION "))
 	cfgRows = append(cfgRows, "")
-	cfgRows = append(cfgRows, fmt.Sprintf("  %s %s", cfgLabel.Render("MCP URL"), cfgVal.Render(config.GetMCPURL())))
-	cfgRows = append(cfgRows, fmt.Sprintf("  %s %s", cfgLabel.Render("Auth URL"), cfgVal.Render(config.GetAuthURL())))
-	cfgRows = append(cfgRows, fmt.Sprintf("  %s %s", cfgLabel.Render("Proxy Port"), cfgVal.Render(fmt.Sprintf("%d", config.GetProxyPort()))))
+	cfgRows = append(cfgRows, fmt.Sprintf("  %s %s", cfgLabel.Render("MCP URL"), cfgVal.Render(config.GetMCPURL())+envNote("mcpUrl")))
+	cfgRows = append(cfgRows, fmt.Sprintf("  %s %s", cfgLabel.Render("Auth URL"), cfgVal.Render(config.GetAuthURL())+envNote("authUrl")))
+	cfgRows = append(cfgRows, fmt.Sprintf("  %s %s", cfgLabel.Render("Proxy Port"), cfgVal.Render(fmt.Sprintf("%d", config.GetProxyPort()))+envNote("proxyPort")))
 	cfgRows = append(cfgRows, fmt.Sprintf("  %s %s", cfgLabel.Render("Log Level"), cfgVal.Render(config.GetLogLevel())))
+	cfgRows = append(cfgRows, fmt.Sprintf("  %s %s", cfgLabel.Render("Accessible"), cfgVal.Render(onOff(config.GetAccessible()))))
 	cfgRows = append(cfgRows, fmt.Sprintf("  %s %s", cfgLabel.Render("Config"), cfgVal.Render(config.ConfigPath())))
+	if warning := customHostWarning(); warning != "" {
+		cfgRows = append(cfgRows, "", "  "+warning)
+	}
+
+	cfgRows = append(cfgRows, "")
+	repairHint := false
+	for _, c := range checks {
+		var state string
+		switch c.State {
+		case mcpOK:
+			state = ui.SuccessStyle.Render("OK ✓")
+		case mcpMissing:
+			state = ui.DimStyle.Render("MISSING") + ui.DimStyle.Render(" · "+c.Reason)
+			repairHint = true
+		default:
+			state = ui.ErrorStyle.Render("STALE ✗") + ui.DimStyle.Render(" · "+c.Reason)
+			repairHint = true
+		}
+		cfgRows = append(cfgRows, fmt.Sprintf("  %s %s", cfgLabel.Render(c.Target.Name), state))
+	}
+	if repairHint {
+		cfgRows = append(cfgRows, "",
+			"  "+ui.DimStyle.Render("Run ")+ui.BrightStyle.Render("boba status --repair")+ui.DimStyle.Render(" to point Claude at this binary"))
+	}
 
 	cfgContent := strings.Join(cfgRows, "\n")
 	cfgCard := lipgloss.NewStyle().
@@ -122,11 +275,167 @@ func buildStatusLines() []string {
 	}
 	lines = append(lines, "")
 
+	if ui.ReducedMotion() {
+		lines = append(lines,
+			"  "+ui.DimStyle.Render("Reduced motion is on. Run ")+ui.BrightStyle.Render("boba config --accessible")+
+				ui.DimStyle.Render(" for screen-reader friendly output."))
+		lines = append(lines, "")
+	}
+
 	return lines
 }
 
+// printStatusPlain writes the status as plain key/value lines for quiet
+// mode and non-interactive output.
+func printStatusPlain(checks []mcpCheck) {
+	if !config.HasCredentials() {
+		ui.Field("credentials", "not initialized")
+	} else {
+		ui.Field("credentials", "configured")
+		c := config.Load()
+		if c.Credentials != nil {
+			ui.Field("agent_id", c.Credentials.AgentID)
+		}
+		if config.IsTokenExpired() {
+			ui.Field("token", "expired")
+		} else {
+			ui.Field("token", "valid")
+		}
+		if tokens, err := config.GetTokens(); err == nil {
+			if tokens.AgentName != "" {
+				ui.Field("agent", tokens.AgentName)
+			}
+			if tokens.EVMAddress != "" {
+				ui.Field("evm", tokens.EVMAddress)
+			}
+			if tokens.SolanaAddress != "" {
+				ui.Field("solana", tokens.SolanaAddress)
+			}
+		}
+		port := config.ActiveProxyPort()
+		if health, ok := proxyHealth(port); !ok {
+			ui.Field("proxy", "not running")
+		} else {
+			if enforced, _ := health["authEnforced"].(bool); enforced {
+				ui.Field("proxy", "running, auth enforced")
+			} else {
+				ui.Field("proxy", "running, auth not enforced")
+			}
+			ui.Field("active_port", fmt.Sprint(port))
+			if lock, ok := config.ReadProxyLock(); ok && lock.Port == port {
+				ui.Field("proxy_pid", fmt.Sprint(lock.PID))
+			}
+		}
+	}
+	printConfigPlain()
+	for _, c := range checks {
+		if c.Reason != "" {
+			ui.Field(c.Target.Key, c.State+": "+c.Reason)
+		} else {
+			ui.Field(c.Target.Key, c.State)
+		}
+	}
+}
+
+// awaitUpdate waits for the update check started with the command, for no
+// longer than it is allowed to take. It returns nil when the check is off,
+// found nothing or failed.
+func awaitUpdate(updates <-chan *update.Notice) *update.Notice {
+	if updates == nil {
+		return nil
+	}
+	select {
+	case notice := <-updates:
+		return notice
+	case <-time.After(update.CheckTimeout):
+		return nil
+	}
+}
+
+// updateBanner tells of a newer release with the start of its notes, or
+// is empty without one.
+func updateBanner(notice *update.Notice) []string {
+	if notice == nil {
+		return nil
+	}
+	badge := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#1a1a2e")).
+		Background(ui.ColorGold).
+		Bold(true).
+		Padding(0, 2).
+		Render(" UPDATE AVAILABLE ")
+	lines := []string{"  " + badge + "  " + ui.GoldStyle.Render(fmt.Sprintf("boba %s is out (you have %s)", notice.Version, version.Version))}
+	for _, note := range strings.Split(notice.Notes, "\n") {
+		if note != "" {
+			lines = append(lines, "    "+ui.DimStyle.Render(note))
+		}
+	}
+	return append(lines,
+		"    "+ui.DimStyle.Render("Run ")+ui.BrightStyle.Render("boba update --install")+ui.DimStyle.Render(" to upgrade, or see what changed with ")+ui.BrightStyle.Render("boba update --check"),
+		"")
+}
+
+// proxyLabel names the proxy on port, with its process when it holds the
+// proxy lock, e.g. ":3456 (pid 4242)".
+func proxyLabel(port int) string {
+	if lock, ok := config.ReadProxyLock(); ok && lock.Port == port {
+		return fmt.Sprintf(":%d (pid %d)", port, lock.PID)
+	}
+	return fmt.Sprintf(":%d", port)
+}
+
+// proxyHealth fetches the health report of the proxy on port. ok is false
+// when no proxy answers.
+func proxyHealth(port int) (map[string]any, bool) {
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/health", port))
+	if err != nil {
+		return nil, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, false
+	}
+	var health map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return nil, false
+	}
+	return health, true
+}
+
 func runStatus(cmd *cobra.Command, args []string) error {
-	lines := buildStatusLines()
+	updates := checkForUpdate()
+	checks := checkMCPTargets()
+	if flagStatusRepair {
+		var err error
+		if checks, err = repairMCPTargets(checks); err != nil {
+			return err
+		}
+	}
+
+	notice := awaitUpdate(updates)
+	if flagStatusJSON {
+		report := buildStatusReport(checks)
+		if notice != nil {
+			report.LatestVersion = &notice.Version
+		}
+		out, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return err
+		}
+		ui.Println(string(out))
+		return nil
+	}
+
+	if !ui.Decorate() {
+		printStatusPlain(checks)
+		if notice != nil {
+			ui.Field("update_available", notice.Version)
+		}
+		return nil
+	}
+
+	lines := append(buildStatusLines(checks), updateBanner(notice)...)
 	runScanReveal(lines)
 	return nil
 }
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/version"
+)
+
+// statusJSON runs 'boba status --json' and returns the decoded report.
+func statusJSON(t *testing.T) map[string]any {
+	t.Helper()
+	t.Cleanup(func() { flagStatusJSON, flagNoUpdateCheck = false, false })
+	stdout, _, err := run(t, "status", "--json", "--disable-update-check")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report map[string]any
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("%v:\n%s", err, stdout)
+	}
+	return report
+}
+
+// Consumers rely on every key being present, with null for unknowns.
+func TestStatusJSONKeys(t *testing.T) {
+	fakeBackend(t, `{}`)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+	t.Setenv(config.EnvProxyPort, strconv.Itoa(port))
+
+	report := statusJSON(t)
+	for _, key := range []string{
+		"authenticated", "agentId", "agentName", "evmAddress", "solanaAddress",
+		"tokenExpiresAt", "tokenExpiresInSeconds", "proxyRunning", "proxyPort",
+		"proxyRequestCount", "mcpUrl", "authUrl", "version",
+	} {
+		if _, ok := report[key]; !ok {
+			t.Errorf("report lacks %q", key)
+		}
+	}
+	for key, want := range map[string]any{
+		"authenticated":         false,
+		"agentId":               "agent-1",
+		"agentName":             nil,
+		"tokenExpiresAt":        nil,
+		"tokenExpiresInSeconds": nil,
+		"proxyRunning":          false,
+		"proxyPort":             nil,
+		"proxyRequestCount":     nil,
+		"version":               version.Version,
+	} {
+		if report[key] != want {
+			t.Errorf("%s = %v, want %v", key, report[key], want)
+		}
+	}
+}
+
+// A logged-in agent and a running proxy fill in the rest, the proxy's from
+// its /health endpoint.
+func TestStatusJSONProxy(t *testing.T) {
+	fakeBackend(t, `{}`)
+	if _, _, err := run(t, "--quiet", "auth"); err != nil {
+		t.Fatal(err)
+	}
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, `{"status":"ok","requests":7,"uptime":42}`)
+	}))
+	t.Cleanup(proxy.Close)
+	port := proxy.Listener.Addr().(*net.TCPAddr).Port
+	if err := config.WriteProxyLock(port); err != nil {
+		t.Fatal(err)
+	}
+
+	report := statusJSON(t)
+	for key, want := range map[string]any{
+		"authenticated":      true,
+		"agentName":          "Taro",
+		"evmAddress":         "0x52908400098527886E0F7030069857D2E4169EE7",
+		"tokenExpiresAt":     "2099-01-01T00:00:00Z",
+		"proxyRunning":       true,
+		"proxyPort":          float64(port),
+		"proxyPid":           float64(os.Getpid()),
+		"proxyRequestCount":  7.0,
+		"proxyUptimeSeconds": 42.0,
+	} {
+		if report[key] != want {
+			t.Errorf("%s = %v, want %v", key, report[key], want)
+		}
+	}
+	if left, _ := report["tokenExpiresInSeconds"].(float64); left <= 0 {
+		t.Errorf("tokenExpiresInSeconds = %v, want > 0", report["tokenExpiresInSeconds"])
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running proxy",
+	RunE:  runStop,
+}
+
+var flagStopStrict bool
+
+// stopTimeout is how long `boba stop` waits for the proxy to go away; the
+// dashboard plays its exit animation first.
+const stopTimeout = 10 * time.Second
+
+func init() {
+	stopCmd.Flags().BoolVar(&flagStopStrict, "strict", false, "Exit with an error when no proxy is running")
+}
+
+func runStop(cmd *cobra.Command, args []string) error {
+	port := config.ActiveProxyPort()
+	if !proxyIsRunning(port) {
+		if flagStopStrict {
+			return fmt.Errorf("no proxy is running on port %d", port)
+		}
+		if !ui.Decorate() {
+			ui.Field("proxy", "not running")
+			return nil
+		}
+		fmt.Println("  " + ui.DimStyle.Render("No proxy is running."))
+		return nil
+	}
+
+	if err := stopProxy(port); err != nil {
+		return err
+	}
+
+	if !ui.Decorate() {
+		ui.Field("proxy", "stopped")
+		return nil
+	}
+	fmt.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.DimStyle.Render("Proxy on port ") + ui.BrightStyle.Render(fmt.Sprint(port)) + ui.DimStyle.Render(" stopped"))
+	return nil
+}
+
+// stopProxy asks the proxy on port to shut down and waits until it no longer
+// answers.
+func stopProxy(port int) error {
+	if err := postProxy(port, "/shutdown", http.StatusAccepted); err != nil {
+		return fmt.Errorf("could not stop the proxy on port %d: %w", port, err)
+	}
+	deadline := time.Now().Add(stopTimeout)
+	for proxyIsRunning(port) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the proxy on port %d is still running after %s", port, stopTimeout)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	return nil
+}
+
+// runningProxy returns the lock of the proxy that is up and answering, if
+// any.
+func runningProxy() (config.ProxyLock, bool) {
+	lock, ok := config.ReadProxyLock()
+	if !ok || !proxyIsRunning(lock.Port) {
+		return config.ProxyLock{}, false
+	}
+	return lock, true
+}
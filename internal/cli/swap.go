@@ -0,0 +1,358 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/client"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/journal"
+	"github.com/tradeboba/boba-cli/internal/logger"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/tokencache"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var swapCmd = &cobra.Command{
+	Use:   "swap",
+	Short: "Swap tokens directly, without Claude",
+	Long: "Quote a swap, show it, and execute it once you confirm. Tokens are addresses\n" +
+		"or symbols, looked up with search_tokens. --amount is in units of the --from\n" +
+		"token, or in dollars with a usd suffix or $ prefix. Quotes with a price impact\n" +
+		"above max-price-impact (boba config set, default 5%) are refused unless\n" +
+		"--force-impact is given.\n\n" +
+		"Exit codes: 2 invalid flags, 3 token not found or priced, 4 quote failed,\n" +
+		"5 price impact too high, 6 not confirmed, 7 swap failed.",
+	Example: "  boba swap --from SOL --to BONK --amount 50usd\n" +
+		"  boba swap --from USDC --to 0x4200000000000000000000000000000000000006 --amount 25 --chain base\n" +
+		"  boba swap --from SOL --to BONK --amount 0.5 --quote-only",
+	Args: cobra.NoArgs,
+	RunE: runSwap,
+	// Errors are printed by runSwap in the error style.
+	SilenceErrors: true,
+	SilenceUsage:  true,
+}
+
+var (
+	flagSwapFrom        string
+	flagSwapTo          string
+	flagSwapAmount      string
+	flagSwapChain       string
+	flagSwapSlippage    float64
+	flagSwapYes         bool
+	flagSwapQuoteOnly   bool
+	flagSwapForceImpact bool
+)
+
+// Exit codes of boba swap, by the step that failed.
+const (
+	exitSwapUsage    = 2
+	exitSwapToken    = 3
+	exitSwapQuote    = 4
+	exitSwapImpact   = 5
+	exitSwapDeclined = 6
+	exitSwapFailed   = 7
+)
+
+// wrappedSOL is the mint swaps use for SOL on Solana.
+const wrappedSOL = "So11111111111111111111111111111111111111112"
+
+func init() {
+	swapCmd.Flags().StringVar(&flagSwapFrom, "from", "", "Token to sell: address or symbol")
+	swapCmd.Flags().StringVar(&flagSwapTo, "to", "", "Token to buy: address or symbol")
+	swapCmd.Flags().StringVar(&flagSwapAmount, "amount", "", "Amount to sell, in --from tokens or in dollars as 50usd or $50")
+	swapCmd.Flags().StringVar(&flagSwapChain, "chain", "solana", "Chain to swap on")
+	swapCmd.Flags().Float64Var(&flagSwapSlippage, "slippage", 1, "Maximum slippage in percent")
+	swapCmd.Flags().BoolVarP(&flagSwapYes, "yes", "y", false, "Execute without asking for confirmation")
+	swapCmd.Flags().BoolVar(&flagSwapQuoteOnly, "quote-only", false, "Show the quote and stop")
+	swapCmd.Flags().BoolVar(&flagSwapForceImpact, "force-impact", false, "Execute even when the price impact is above max-price-impact")
+}
+
+func runSwap(cmd *cobra.Command, args []string) error {
+	err := swap(cmd)
+	if err != nil {
+		ui.Errorln(ui.ErrorStyle.Render("Error: " + err.Error()))
+	}
+	return err
+}
+
+// swapFail returns an error that exits with code, the step of the swap
+// that failed.
+func swapFail(code int, format string, a ...any) error {
+	return &exitError{code: code, err: fmt.Errorf(format, a...)}
+}
+
+func swap(cmd *cobra.Command) error {
+	if !config.HasCredentials() {
+		return fmt.Errorf("no credentials configured. Run 'boba login' first")
+	}
+	if flagSwapFrom == "" || flagSwapTo == "" || flagSwapAmount == "" {
+		return swapFail(exitSwapUsage, "--from, --to and --amount are required")
+	}
+	chain, err := config.NormalizeChain(flagSwapChain)
+	if err != nil {
+		return swapFail(exitSwapUsage, "%w", err)
+	}
+	amount, inUSD, err := parseSwapAmount(flagSwapAmount)
+	if err != nil {
+		return swapFail(exitSwapUsage, "%w", err)
+	}
+	if flagSwapSlippage <= 0 || flagSwapSlippage > 50 {
+		return swapFail(exitSwapUsage, "--slippage must be above 0 and at most 50 percent")
+	}
+	ctx, stop := interruptible(cmd)
+	defer stop()
+
+	from, err := resolveSwapToken(ctx, flagSwapFrom, chain)
+	if err != nil {
+		return swapFail(exitSwapToken, "--from: %w", err)
+	}
+	to, err := resolveSwapToken(ctx, flagSwapTo, chain)
+	if err != nil {
+		return swapFail(exitSwapToken, "--to: %w", err)
+	}
+	if inUSD {
+		usd := amount
+		err := ui.RunWithSpinner(fmt.Sprintf("Pricing %s...", from.label()), func() error {
+			price, err := tokenPriceUSD(ctx, from.Address, chain)
+			if err != nil {
+				return err
+			}
+			amount = usd / price
+			return nil
+		})
+		if err != nil {
+			return swapFail(exitSwapToken, "could not convert $%s to %s: %w", formatter.FormatNumber(usd), from.label(), err)
+		}
+	}
+
+	toolArgs := map[string]any{
+		"from_token": from.Address,
+		"to_token":   to.Address,
+		"amount":     amount,
+		"chain":      chain,
+		"slippage":   flagSwapSlippage,
+	}
+	var quote map[string]any
+	err = ui.RunWithSpinner("Getting swap quote...", func() error {
+		var err error
+		quote, err = callSwapTool(ctx, "get_swap_quote", toolArgs)
+		return err
+	})
+	if err != nil {
+		return swapFail(exitSwapQuote, "quote failed: %w", err)
+	}
+	printSwapQuote(quote)
+
+	limit := config.GetMaxPriceImpact()
+	if impact, ok := formatter.SwapPriceImpact(quote); ok && math.Abs(impact) > limit && !flagSwapQuoteOnly && !flagSwapForceImpact {
+		return swapFail(exitSwapImpact, "price impact %.2f%% is above your %s%% limit; pass --force-impact to swap anyway, "+
+			"or raise the limit with 'boba config set max-price-impact'", math.Abs(impact), strconv.FormatFloat(limit, 'f', -1, 64))
+	}
+	if flagSwapQuoteOnly {
+		return nil
+	}
+
+	if !flagSwapYes {
+		if !ui.Decorate() {
+			return swapFail(exitSwapDeclined, "refusing to swap without confirmation; pass --yes")
+		}
+		ok := false
+		err := huh.NewConfirm().
+			Title(fmt.Sprintf("Swap %s for %s?", from.label(), to.label())).
+			Description("The trade is sent as quoted, within your slippage.").
+			Value(&ok).
+			WithTheme(ui.BobaTheme()).
+			Run()
+		if err != nil || !ok {
+			return swapFail(exitSwapDeclined, "aborted")
+		}
+	}
+
+	for _, k := range []string{"quote_id", "quoteId"} {
+		if id := swapQuoteID(quote, k); id != nil {
+			toolArgs[k] = id
+		}
+	}
+	var result map[string]any
+	err = ui.RunWithSpinner("Executing trade...", func() error {
+		var err error
+		result, err = callSwapTool(ctx, "execute_swap", toolArgs)
+		return err
+	})
+	if result != nil {
+		printTradeResult(result, chain)
+	}
+	if err != nil {
+		return swapFail(exitSwapFailed, "swap failed: %w", err)
+	}
+
+	// The proxy journals the trades agents make; do the same here so
+	// boba verify-trade can check this one.
+	if entry, ok := journal.FromResult("execute_swap", toolArgs, result, nil); ok {
+		if err := journal.Append(entry); err != nil {
+			logger.Debug("failed to journal trade", "tx", entry.TxHash, "error", err)
+		}
+	}
+	return nil
+}
+
+// parseSwapAmount parses an amount of tokens, or of dollars when it has a
+// usd suffix or a $ prefix.
+func parseSwapAmount(s string) (amount float64, inUSD bool, err error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	if rest, ok := strings.CutPrefix(v, "$"); ok {
+		v, inUSD = rest, true
+	} else if rest, ok := strings.CutSuffix(v, "usd"); ok {
+		v, inUSD = strings.TrimSpace(rest), true
+	}
+	amount, err = strconv.ParseFloat(strings.ReplaceAll(v, ",", ""), 64)
+	if err != nil || !(amount > 0) || math.IsInf(amount, 0) {
+		return 0, false, fmt.Errorf("invalid --amount %q, expected a positive number of tokens, or dollars as 50usd", s)
+	}
+	return amount, inUSD, nil
+}
+
+// swapToken is a token given to boba swap, resolved to its address.
+type swapToken struct {
+	Address string
+	Symbol  string
+}
+
+func (t swapToken) label() string {
+	if t.Symbol != "" {
+		return t.Symbol
+	}
+	return formatter.TruncateAddress(t.Address)
+}
+
+// resolveSwapToken returns the token an address or symbol names on chain,
+// asking which one was meant when several tokens share a symbol.
+func resolveSwapToken(ctx context.Context, arg, chain string) (swapToken, error) {
+	if isTokenAddress(arg) {
+		symbol, _ := tokencache.Default.Symbol(arg)
+		return swapToken{Address: arg, Symbol: symbol}, nil
+	}
+	symbol := strings.ToUpper(strings.TrimPrefix(arg, "$"))
+	if chain == "solana" && (symbol == "SOL" || symbol == "WSOL") {
+		return swapToken{Address: wrappedSOL, Symbol: "SOL"}, nil
+	}
+	var matches []formatter.TokenMatch
+	err := ui.RunWithSpinner(fmt.Sprintf("Looking up %s...", symbol), func() error {
+		var err error
+		matches, err = searchSymbol(ctx, symbol, chain)
+		return err
+	})
+	if err != nil {
+		return swapToken{}, err
+	}
+	m, err := pickMatch(symbol, matches)
+	if err != nil {
+		return swapToken{}, err
+	}
+	return swapToken{Address: m.Address, Symbol: m.Symbol}, nil
+}
+
+// tokenPriceUSD returns the price of the token at address on chain.
+func tokenPriceUSD(ctx context.Context, address, chain string) (float64, error) {
+	body, err := proxy.CallToolDirect(ctx, "get_token_price", map[string]any{"tokens": []string{address}, "chain": chain})
+	if err != nil {
+		return 0, err
+	}
+	var data map[string]any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return 0, err
+	}
+	for a, price := range formatter.TokenPrices(data) {
+		if (a == address || strings.HasPrefix(a, "0x") && strings.EqualFold(a, address)) && price > 0 {
+			return price, nil
+		}
+	}
+	return 0, fmt.Errorf("no price found")
+}
+
+// callSwapTool calls a swap tool and parses its response. A response that
+// says the call failed is returned along with the error, so its message
+// can be shown.
+func callSwapTool(ctx context.Context, tool string, args map[string]any) (map[string]any, error) {
+	body, err := proxy.CallToolDirect(ctx, tool, args)
+	var upstream *client.UpstreamError
+	if errors.As(err, &upstream) {
+		body = []byte(upstream.Body)
+	} else if err != nil {
+		return nil, err
+	}
+	var data map[string]any
+	if json.Unmarshal(body, &data) != nil {
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("unexpected response from %s", tool)
+	}
+	if err == nil && callFailed(body) {
+		err = fmt.Errorf("%s reported failure", tool)
+	}
+	if err != nil {
+		if msg := pickMessage(data); msg != "" {
+			err = errors.New(msg)
+		}
+		return data, err
+	}
+	return data, nil
+}
+
+// pickMessage returns the error message of a failed call's response.
+func pickMessage(data map[string]any) string {
+	for _, k := range []string{"error", "message", "detail"} {
+		if s, ok := data[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// swapQuoteID returns the quote's key, at the top level or under "quote",
+// for execute_swap to fill the quote that was shown.
+func swapQuoteID(quote map[string]any, key string) any {
+	if nested, ok := quote["quote"].(map[string]any); ok && nested[key] != nil {
+		return nested[key]
+	}
+	return quote[key]
+}
+
+func printSwapQuote(quote map[string]any) {
+	if ui.Decorate() {
+		ui.Println(formatter.FormatSwapQuote(quote))
+		return
+	}
+	out, _ := json.Marshal(quote)
+	printIndentedJSON(out)
+}
+
+// printTradeResult prints an execute_swap result and the explorer page of
+// its transaction.
+func printTradeResult(result map[string]any, chain string) {
+	hash := formatter.TradeTxHash(result)
+	url, hasURL := formatter.ExplorerTxURL(chain, hash)
+	if !ui.Decorate() {
+		if hash != "" {
+			ui.Field("tx", hash)
+		}
+		if hasURL {
+			ui.Field("explorer", url)
+		}
+		return
+	}
+	ui.Println(formatter.FormatTradeResult(result))
+	if hasURL {
+		ui.Println("  " + ui.DimStyle.Render("Explorer  ") + ui.BrightStyle.Render(url))
+	}
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var toolsCmd = &cobra.Command{
+	Use:   "tools",
+	Short: "Track changes to the backend's tool manifest",
+	Long: "The proxy snapshots the backend's tool list, with a hash of each tool's input\n" +
+		"schema, whenever it fetches it, and notes in the dashboard when tools were added,\n" +
+		"removed or changed since the last session. Pin a manifest to review changes\n" +
+		"against it, or to only let agents call those tools with 'boba start --strict-tools'.",
+}
+
+var toolsDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show how the tool manifest changed",
+	Long: "With a pinned manifest, compare it with the tools the backend offers now.\n" +
+		"Otherwise show the last change the proxy recorded between sessions.",
+	RunE: runToolsDiff,
+}
+
+var toolsPinCmd = &cobra.Command{
+	Use:   "pin",
+	Short: "Pin the backend's current tool manifest",
+	RunE:  runToolsPin,
+}
+
+var flagToolsDiffJSON bool
+
+func init() {
+	toolsDiffCmd.Flags().BoolVar(&flagToolsDiffJSON, "json", false, "Print the changes as JSON")
+	toolsCmd.AddCommand(toolsDiffCmd, toolsPinCmd)
+}
+
+// fetchManifest fetches the backend's tool list and reads it as a manifest.
+func fetchManifest(ctx context.Context) (*proxy.Manifest, error) {
+	if !config.HasCredentials() {
+		return nil, fmt.Errorf("no credentials configured. Run 'boba login' first")
+	}
+	var body []byte
+	err := ui.RunWithSpinner("Fetching tools...", func() error {
+		var err error
+		body, err = proxy.ListToolsDirect(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return proxy.ParseManifest(body)
+}
+
+func runToolsPin(cmd *cobra.Command, args []string) error {
+	ctx, stop := interruptible(cmd)
+	defer stop()
+
+	m, err := fetchManifest(ctx)
+	if err != nil {
+		return err
+	}
+	prev, err := proxy.LoadManifest(proxy.PinnedManifestPath())
+	if err != nil {
+		return err
+	}
+	if err := m.Save(proxy.PinnedManifestPath()); err != nil {
+		return err
+	}
+
+	summary := fmt.Sprintf("Pinned %d tools", len(m.Tools))
+	if prev != nil {
+		if d := proxy.DiffManifests(prev, m); !d.Empty() {
+			summary += " (" + d.String() + ")"
+		}
+	}
+	if !ui.Decorate() {
+		ui.Field("pinned", fmt.Sprint(len(m.Tools)))
+		ui.Field("path", proxy.PinnedManifestPath())
+		return nil
+	}
+	ui.Println()
+	ui.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.BrightStyle.Render(summary))
+	ui.Println("    " + ui.DimStyle.Render(proxy.PinnedManifestPath()))
+	ui.Println()
+	return nil
+}
+
+func runToolsDiff(cmd *cobra.Command, args []string) error {
+	ctx, stop := interruptible(cmd)
+	defer stop()
+
+	var from, to *proxy.Manifest
+	var fromLabel, toLabel string
+	pinned, err := proxy.LoadManifest(proxy.PinnedManifestPath())
+	if err != nil {
+		return err
+	}
+	if pinned != nil {
+		if to, err = fetchManifest(ctx); err != nil {
+			return err
+		}
+		from, fromLabel, toLabel = pinned, "pinned", "backend now"
+	} else {
+		if from, err = proxy.LoadManifest(proxy.PreviousManifestPath()); err != nil {
+			return err
+		}
+		if to, err = proxy.LoadManifest(proxy.ManifestSnapshotPath()); err != nil {
+			return err
+		}
+		fromLabel, toLabel = "previous session", "latest session"
+	}
+
+	diff := proxy.ManifestDiff{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	if from != nil && to != nil {
+		diff = proxy.DiffManifests(from, to)
+	}
+	if flagToolsDiffJSON {
+		out, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return err
+		}
+		ui.Println(string(out))
+		return nil
+	}
+	if from == nil || to == nil {
+		ui.Println("No tool changes recorded. Pin the current tools with 'boba tools pin' to compare against them.")
+		return nil
+	}
+	printManifestDiff(diff, fromLabel+" ("+from.Fetched.Local().Format("2006-01-02 15:04")+")", toLabel)
+	return nil
+}
+
+// printManifestDiff lists the tools added, removed and changed between two
+// manifests.
+func printManifestDiff(d proxy.ManifestDiff, from, to string) {
+	if !ui.Decorate() {
+		for _, name := range d.Added {
+			ui.Printf("added\t%s\n", name)
+		}
+		for _, name := range d.Removed {
+			ui.Printf("removed\t%s\n", name)
+		}
+		for _, name := range d.Changed {
+			ui.Printf("changed\t%s\n", name)
+		}
+		return
+	}
+	ui.Println()
+	ui.Println("  " + ui.DimStyle.Render(from+" → "+to))
+	if d.Empty() {
+		ui.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.BrightStyle.Render("No changes"))
+		ui.Println()
+		return
+	}
+	ui.Println("  " + ui.BrightStyle.Render(d.String()))
+	ui.Println()
+	for _, name := range d.Added {
+		ui.Println("    " + ui.SuccessStyle.Render("+ "+name))
+	}
+	for _, name := range d.Removed {
+		ui.Println("    " + ui.ErrorStyle.Render("- "+name))
+	}
+	for _, name := range d.Changed {
+		ui.Println("    " + ui.GoldStyle.Render("~ "+name) + ui.DimStyle.Render("  input schema changed"))
+	}
+	ui.Println()
+}
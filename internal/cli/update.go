@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/ui"
+	"github.com/tradeboba/boba-cli/internal/update"
+	"github.com/tradeboba/boba-cli/internal/version"
+)
+
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Check for a newer version of Boba",
+	RunE:  runUpdate,
+}
+
+var (
+	flagUpdateInstall   bool
+	flagUpdateCheckOnly bool
+	flagUpdateChannel   string
+
+	// flagNoUpdateCheck is --disable-update-check on boba start and boba
+	// status.
+	flagNoUpdateCheck bool
+)
+
+func init() {
+	updateCmd.Flags().BoolVar(&flagUpdateInstall, "install", false, "Download and install the latest version")
+	updateCmd.Flags().BoolVar(&flagUpdateCheckOnly, "check", false, "Only check, ignoring the daily check's cached answer, and show the new version's release notes")
+	updateCmd.Flags().StringVar(&flagUpdateChannel, "channel", "", "Release channel to follow: stable or beta (saved to config)")
+}
+
+func runUpdate(cmd *cobra.Command, args []string) error {
+	if flagUpdateChannel != "" {
+		if !update.ValidChannel(flagUpdateChannel) {
+			return fmt.Errorf("invalid channel %q (use stable or beta)", flagUpdateChannel)
+		}
+		if err := config.SetUpdateChannel(flagUpdateChannel); err != nil {
+			return fmt.Errorf("failed to save channel: %w", err)
+		}
+	}
+	channel := config.GetUpdateChannel()
+	if flagUpdateCheckOnly {
+		if flagUpdateInstall {
+			return fmt.Errorf("--check and --install can't be used together")
+		}
+		return runUpdateCheck(channel)
+	}
+
+	var release *update.Release
+	err := ui.RunWithSpinner("Checking for updates...", func() error {
+		var err error
+		release, err = update.Latest(channel)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	current := version.Version
+	latest := release.Version()
+
+	if !update.IsNewer(current, latest) {
+		ui.Field("status", "up to date")
+		ui.Field("version", current)
+		ui.Field("channel", channel)
+		return nil
+	}
+
+	ui.Field("status", "update available")
+	ui.Field("current", current)
+	ui.Field("latest", latest)
+	ui.Field("channel", channel)
+	printReleaseNotes(update.NotesExcerpt(release.Body))
+
+	if !flagUpdateInstall {
+		ui.Decor(ui.DimStyle.Render("Run ") + ui.BrightStyle.Render("boba update --install") + ui.DimStyle.Render(" to upgrade."))
+		return nil
+	}
+
+	return installUpdate(release)
+}
+
+// runUpdateCheck checks for a newer release right away, refreshing what the
+// daily check on boba start and boba status remembers.
+func runUpdateCheck(channel string) error {
+	var notice *update.Notice
+	err := ui.RunWithSpinner("Checking for updates...", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		var err error
+		notice, err = update.Check(ctx, version.Version, channel, true)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if notice == nil {
+		ui.Field("status", "up to date")
+		ui.Field("version", version.Version)
+		ui.Field("channel", channel)
+		return nil
+	}
+	ui.Field("status", "update available")
+	ui.Field("current", version.Version)
+	ui.Field("latest", notice.Version)
+	ui.Field("channel", channel)
+	printReleaseNotes(notice.Notes)
+	ui.Decor(ui.DimStyle.Render("Run ") + ui.BrightStyle.Render("boba update --install") + ui.DimStyle.Render(" to upgrade."))
+	return nil
+}
+
+// printReleaseNotes prints the start of a release's notes, if it has any.
+func printReleaseNotes(notes string) {
+	for _, line := range strings.Split(notes, "\n") {
+		if line != "" {
+			ui.Field("notes", line)
+		}
+	}
+}
+
+// checkForUpdate starts the daily update check for boba start and boba
+// status, or returns nil when it is turned off. The channel gets a notice
+// when a newer release is out, and nil otherwise.
+func checkForUpdate() <-chan *update.Notice {
+	if flagNoUpdateCheck || !config.GetUpdateCheck() {
+		return nil
+	}
+	return update.CheckInBackground(version.Version, config.GetUpdateChannel())
+}
+
+// installUpdate downloads the release archive for this platform, verifies
+// it and swaps it in for the running binary. npm-managed installs are left
+// alone with instructions instead.
+func installUpdate(release *update.Release) error {
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate current binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+
+	if update.IsNPMManaged(exe) {
+		ui.Errorln("This copy of boba was installed with npm; self-update would be overwritten by the next npm update.")
+		ui.Errorln("Upgrade with: " + update.NPMCommand(release.Version()))
+		return fmt.Errorf("refusing to replace npm-managed binary")
+	}
+
+	name := update.ArchiveName()
+	asset, err := release.FindAsset(name)
+	if err != nil {
+		return err
+	}
+
+	var sum string
+	if err := ui.RunWithSpinner("Fetching checksums...", func() error {
+		var err error
+		sum, err = release.Checksum(name)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	// Keep the download next to the binary so the final rename stays on the
+	// same filesystem, and so an interrupted download can resume later.
+	dir := filepath.Dir(exe)
+	archive := filepath.Join(dir, ".boba-update-"+release.Version()+"-"+name)
+	staged := filepath.Join(dir, ".boba-update-"+release.Version()+".bin")
+
+	if err := ui.RunWithSpinner("Downloading "+release.TagName+"...", func() error {
+		return update.Download(asset.URL, archive, sum, update.DefaultDownloadOptions)
+	}); err != nil {
+		return err
+	}
+	defer os.Remove(archive)
+
+	if err := ui.Step("Extracting...", func() error {
+		return update.ExtractBinary(archive, staged)
+	}); err != nil {
+		_ = os.Remove(staged)
+		return err
+	}
+
+	if err := ui.Step("Installing...", func() error {
+		return update.ReplaceExecutable(exe, staged)
+	}); err != nil {
+		_ = os.Remove(staged)
+		return err
+	}
+
+	ui.Field("installed", release.Version())
+	return nil
+}
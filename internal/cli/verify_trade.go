@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/journal"
+	"github.com/tradeboba/boba-cli/internal/ui"
+	"github.com/tradeboba/boba-cli/internal/verify"
+)
+
+var verifyTradeCmd = &cobra.Command{
+	Use:   "verify-trade [tx-hash]",
+	Short: "Check a trade against the chain",
+	Long: "Look up a transaction on the chain's explorer and compare it with the trade\n" +
+		"journaled by the proxy: that it succeeded, the amounts match and the output\n" +
+		"reached your agent wallet.",
+	Args: cobra.MaximumNArgs(1),
+	RunE: runVerifyTrade,
+}
+
+var (
+	flagVerifyChain     string
+	flagVerifyLast      bool
+	flagVerifyTolerance float64
+)
+
+func init() {
+	verifyTradeCmd.Flags().StringVar(&flagVerifyChain, "chain", "", "Chain the transaction is on (defaults to the journaled chain)")
+	verifyTradeCmd.Flags().BoolVar(&flagVerifyLast, "last", false, "Verify the most recent journaled trade")
+	verifyTradeCmd.Flags().Float64Var(&flagVerifyTolerance, "tolerance", 0.02, "Allowed relative difference between amounts")
+}
+
+func runVerifyTrade(cmd *cobra.Command, args []string) error {
+	var entry *journal.Entry
+	var hash string
+
+	switch {
+	case flagVerifyLast:
+		e, err := journal.Last()
+		if err != nil {
+			return err
+		}
+		entry, hash = e, e.TxHash
+	case len(args) == 1:
+		hash = strings.TrimSpace(args[0])
+		// Verification still runs without a journal entry; the report
+		// just has nothing to compare amounts against.
+		entry, _ = journal.Find(hash)
+	default:
+		return fmt.Errorf("pass a transaction hash or --last")
+	}
+
+	chain := flagVerifyChain
+	if chain == "" && entry != nil {
+		chain = entry.Chain
+	}
+	if chain == "" {
+		return fmt.Errorf("unknown chain for %s; pass --chain", hash)
+	}
+	chain, err := config.NormalizeChain(chain)
+	if err != nil {
+		return err
+	}
+
+	explorer, err := verify.ForChain(chain)
+	if err != nil {
+		return err
+	}
+
+	var tx *verify.Transaction
+	err = ui.RunWithSpinner("Looking up transaction...", func() error {
+		var err error
+		tx, err = explorer.Transaction(hash)
+		return err
+	})
+	if errors.Is(err, verify.ErrUnavailable) {
+		ui.Field("status", "explorer unavailable")
+		ui.Field("detail", strings.TrimPrefix(err.Error(), verify.ErrUnavailable.Error()+": "))
+		if chain != "solana" && config.GetExplorerAPIKey(chain) == "" {
+			ui.Decor(ui.DimStyle.Render("Set an API key with ") +
+				ui.BrightStyle.Render("boba config --explorer-key "+chain+"=KEY"))
+		}
+		return fmt.Errorf("could not verify %s", hash)
+	}
+	if err != nil {
+		return err
+	}
+
+	report := verify.Compare(entry, tx, flagVerifyTolerance)
+	if ui.Decorate() {
+		fmt.Println(renderVerifyReport(hash, chain, report))
+	} else {
+		printVerifyPlain(hash, chain, report)
+	}
+
+	if !report.Passed() {
+		return fmt.Errorf("verification failed")
+	}
+	return nil
+}
+
+func printVerifyPlain(hash, chain string, r *verify.Report) {
+	ui.Field("tx", hash)
+	ui.Field("chain", chain)
+	for _, c := range r.Checks {
+		ui.Field("check", fmt.Sprintf("%s: %s %s", checkStatus(c), c.Name, c.Detail))
+	}
+	if r.Passed() {
+		ui.Field("status", "pass")
+	} else {
+		ui.Field("status", "fail")
+	}
+}
+
+func checkStatus(c verify.Check) string {
+	switch {
+	case c.OK:
+		return "ok"
+	case c.Warn:
+		return "skip"
+	}
+	return "fail"
+}
+
+func renderVerifyReport(hash, chain string, r *verify.Report) string {
+	dim := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	name := lipgloss.NewStyle().Foreground(ui.ColorBright).Width(24)
+
+	var rows []string
+	for _, c := range r.Checks {
+		var icon, detail string
+		switch {
+		case c.OK:
+			icon = ui.SuccessStyle.Render("✓")
+			detail = dim.Render(c.Detail)
+		case c.Warn:
+			icon = ui.GoldStyle.Render("?")
+			detail = dim.Render(c.Detail)
+		default:
+			icon = ui.ErrorStyle.Render("✗")
+			detail = ui.ErrorStyle.Render(c.Detail)
+		}
+		rows = append(rows, fmt.Sprintf("%s %s %s", icon, name.Render(c.Name), detail))
+	}
+
+	header := ui.SuccessStyle.Bold(true).Render("TRADE VERIFIED ✓")
+	box := ui.SuccessBoxBorder
+	if !r.Passed() {
+		header = ui.ErrorStyle.Bold(true).Render("VERIFICATION FAILED ✗")
+		box = ui.ErrorBoxBorder
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		dim.Render(truncateAddr(hash)+" on "+chain),
+		"",
+		strings.Join(rows, "\n"),
+	)
+	return box.Render(content)
+}
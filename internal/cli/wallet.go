@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/qr"
+	"github.com/tradeboba/boba-cli/internal/ui"
+	"github.com/tradeboba/boba-cli/internal/wallet"
+)
+
+var walletCmd = &cobra.Command{
+	Use:   "wallet",
+	Short: "Your agent wallet",
+}
+
+var walletAddressCmd = &cobra.Command{
+	Use:   "address",
+	Short: "Show the full addresses to fund your agent wallet",
+	Long: "Print the agent wallet's receive addresses in full, with the networks each\n" +
+		"one is valid on. Use --qr to scan an address from a phone wallet.",
+	RunE: runWalletAddress,
+}
+
+var (
+	flagWalletChain string
+	flagWalletQR    bool
+)
+
+func init() {
+	walletAddressCmd.Flags().StringVar(&flagWalletChain, "chain", "", "Only this address: solana, evm or a chain name")
+	walletAddressCmd.Flags().BoolVar(&flagWalletQR, "qr", false, "Also draw a QR code for each address")
+	walletCmd.AddCommand(walletAddressCmd)
+}
+
+func runWalletAddress(cmd *cobra.Command, args []string) error {
+	tokens, err := config.GetTokens()
+	if err != nil {
+		return fmt.Errorf("no credentials configured. Run 'boba login' first")
+	}
+	addrs, err := wallet.Addresses(tokens, flagWalletChain)
+	if err != nil {
+		return err
+	}
+
+	for i, a := range addrs {
+		var code string
+		if flagWalletQR {
+			c, err := qr.Encode(a.Address)
+			if err != nil {
+				return err
+			}
+			code = c.Render()
+		}
+
+		if !ui.Decorate() {
+			ui.Field(a.Family, a.Address)
+			if code != "" {
+				ui.Println(code)
+			}
+			continue
+		}
+
+		if i > 0 {
+			ui.Println()
+		}
+		ui.Println("  " + ui.GoldStyle.Render(a.Label))
+		ui.Println("  " + ui.BrightStyle.Render(a.Address))
+		ui.Println("  " + ui.WarningStyle.Render(a.Networks()))
+		ui.Println("  " + ui.DimStyle.Render("Chains: "+strings.Join(a.Chains(), ", ")))
+		if code != "" {
+			ui.Println()
+			ui.Println("  " + strings.ReplaceAll(code, "\n", "\n  "))
+		}
+	}
+	return nil
+}
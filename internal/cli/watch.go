@@ -0,0 +1,279 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/spf13/cobra"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+	"github.com/tradeboba/boba-cli/internal/watchlist"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Manage the tokens on your watchlist",
+	Long: "The watchlist is kept by the backend, so agents see the same tokens. The\n" +
+		"dashboard's Watchlist tab shows their prices while the proxy runs.",
+}
+
+var watchAddCmd = &cobra.Command{
+	Use:   "add <address|symbol>",
+	Short: "Add a token to the watchlist",
+	Long: "Add a token by address, or by symbol: the symbol is looked up with\n" +
+		"search_tokens, and you are asked to pick when several tokens share it.",
+	Args: cobra.ExactArgs(1),
+	RunE: runWatchAdd,
+}
+
+var watchRemoveCmd = &cobra.Command{
+	Use:     "rm <address>",
+	Aliases: []string{"remove"},
+	Short:   "Remove a token from the watchlist",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runWatchRemove,
+}
+
+var watchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the watchlist with current prices",
+	RunE:  runWatchList,
+}
+
+var flagWatchChain string
+
+func init() {
+	watchAddCmd.Flags().StringVar(&flagWatchChain, "chain", "", "Chain the token is on (needed for EVM addresses)")
+	watchRemoveCmd.Flags().StringVar(&flagWatchChain, "chain", "", "Chain the token is on, if it is listed on several")
+	watchCmd.AddCommand(watchAddCmd, watchRemoveCmd, watchListCmd)
+}
+
+func runWatchAdd(cmd *cobra.Command, args []string) error {
+	if !config.HasCredentials() {
+		return fmt.Errorf("no credentials configured. Run 'boba login' first")
+	}
+	chain := ""
+	if flagWatchChain != "" {
+		var err error
+		if chain, err = config.NormalizeChain(flagWatchChain); err != nil {
+			return err
+		}
+	}
+	ctx, stop := interruptible(cmd)
+	defer stop()
+
+	token := formatter.TokenMatch{Address: args[0], Chain: chain}
+	if isTokenAddress(args[0]) {
+		if chain == "" {
+			if strings.HasPrefix(args[0], "0x") {
+				return fmt.Errorf("pass --chain for an EVM address, e.g. --chain base")
+			}
+			token.Chain = "solana"
+		}
+	} else {
+		symbol := strings.ToUpper(strings.TrimPrefix(args[0], "$"))
+		var matches []formatter.TokenMatch
+		err := ui.RunWithSpinner(fmt.Sprintf("Looking up %s...", symbol), func() error {
+			var err error
+			matches, err = searchSymbol(ctx, symbol, chain)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if token, err = pickMatch(symbol, matches); err != nil {
+			return err
+		}
+		if token.Chain == "" {
+			token.Chain = chain
+		}
+	}
+
+	err := ui.RunWithSpinner("Adding to watchlist...", func() error {
+		return watchlist.Add(ctx, proxy.CallToolDirect, token.Address, token.Chain)
+	})
+	if err != nil {
+		return err
+	}
+	label := token.Symbol
+	if label == "" {
+		label = formatter.TruncateAddress(token.Address)
+	}
+	if !ui.Decorate() {
+		ui.Field("added", token.Address)
+		return nil
+	}
+	fmt.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.DimStyle.Render("Added ") +
+		ui.BrightStyle.Render(label) + ui.DimStyle.Render(" to the watchlist"))
+	return nil
+}
+
+// searchSymbol finds the tokens with the given symbol, on chain if it is
+// set.
+func searchSymbol(ctx context.Context, symbol, chain string) ([]formatter.TokenMatch, error) {
+	args := map[string]any{"query": symbol}
+	if chain != "" {
+		args["chain"] = chain
+	}
+	body, err := proxy.CallToolDirect(ctx, "search_tokens", args)
+	if err != nil {
+		return nil, fmt.Errorf("looking up %s: %w", symbol, err)
+	}
+	var data map[string]any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("looking up %s: %w", symbol, err)
+	}
+	return formatter.FindTokens(data, symbol), nil
+}
+
+// pickMatch returns the only token found for symbol, or asks which one was
+// meant when several share it.
+func pickMatch(symbol string, matches []formatter.TokenMatch) (formatter.TokenMatch, error) {
+	switch {
+	case len(matches) == 0:
+		return formatter.TokenMatch{}, fmt.Errorf("no token with symbol %s found; pass its address instead", symbol)
+	case len(matches) == 1:
+		return matches[0], nil
+	}
+	if !ui.Decorate() {
+		var lines []string
+		for _, m := range matches {
+			lines = append(lines, fmt.Sprintf("  %s\t%s\t%s", m.Address, m.Chain, m.Name))
+		}
+		return formatter.TokenMatch{}, fmt.Errorf("%d tokens have the symbol %s; pass the address of the one you mean:\n%s",
+			len(matches), symbol, strings.Join(lines, "\n"))
+	}
+
+	options := make([]huh.Option[int], len(matches))
+	for i, m := range matches {
+		label := m.Symbol
+		if m.Name != "" {
+			label += " · " + m.Name
+		}
+		if m.Chain != "" {
+			label += " · " + m.Chain
+		}
+		options[i] = huh.NewOption(label+"  "+formatter.TruncateAddress(m.Address), i)
+	}
+	choice := 0
+	err := huh.NewSelect[int]().
+		Title(fmt.Sprintf("%d tokens have the symbol %s. Which one?", len(matches), symbol)).
+		Options(options...).
+		Value(&choice).
+		WithTheme(ui.BobaTheme()).
+		Run()
+	if err != nil {
+		return formatter.TokenMatch{}, fmt.Errorf("aborted: %w", err)
+	}
+	return matches[choice], nil
+}
+
+func runWatchRemove(cmd *cobra.Command, args []string) error {
+	if !config.HasCredentials() {
+		return fmt.Errorf("no credentials configured. Run 'boba login' first")
+	}
+	address := args[0]
+	ctx, stop := interruptible(cmd)
+	defer stop()
+
+	// Remove it on the chain the watchlist has it on, unless told.
+	chain := ""
+	if flagWatchChain != "" {
+		var err error
+		if chain, err = config.NormalizeChain(flagWatchChain); err != nil {
+			return err
+		}
+	}
+	label := formatter.TruncateAddress(address)
+	err := ui.RunWithSpinner("Removing from watchlist...", func() error {
+		list, err := watchlist.List(ctx, proxy.CallToolDirect)
+		if err != nil {
+			return err
+		}
+		found := false
+		for _, t := range list {
+			if strings.EqualFold(t.Address, address) && (chain == "" || strings.EqualFold(t.Chain, chain)) {
+				found, label = true, t.Label()
+				if chain == "" {
+					chain = t.Chain
+				}
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s is not on the watchlist", address)
+		}
+		return watchlist.Remove(ctx, proxy.CallToolDirect, address, chain)
+	})
+	if err != nil {
+		return err
+	}
+	if !ui.Decorate() {
+		ui.Field("removed", address)
+		return nil
+	}
+	fmt.Println("  " + ui.SuccessStyle.Render("✓") + " " + ui.DimStyle.Render("Removed ") +
+		ui.BrightStyle.Render(label) + ui.DimStyle.Render(" from the watchlist"))
+	return nil
+}
+
+func runWatchList(cmd *cobra.Command, args []string) error {
+	if !config.HasCredentials() {
+		return fmt.Errorf("no credentials configured. Run 'boba login' first")
+	}
+	ctx, stop := interruptible(cmd)
+	defer stop()
+
+	var list []watchlist.Token
+	err := ui.RunWithSpinner("Fetching watchlist...", func() error {
+		var err error
+		if list, err = watchlist.List(ctx, proxy.CallToolDirect); err != nil {
+			return err
+		}
+		watchlist.FillPrices(ctx, proxy.CallToolDirect, list)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !ui.Decorate() {
+		for _, t := range list {
+			change := ""
+			if t.HasChange {
+				change = strconv.FormatFloat(t.Change, 'f', 2, 64)
+			}
+			ui.Printf("%s\t%s\t%s\t%s\t%s\n", t.Address, t.Symbol, t.Chain,
+				strconv.FormatFloat(t.Price, 'g', -1, 64), change)
+		}
+		return nil
+	}
+	if len(list) == 0 {
+		ui.Println("The watchlist is empty. Add a token with 'boba watch add <address|symbol>'.")
+		return nil
+	}
+
+	// Show the looked-up prices in the watchlist table.
+	items := make([]any, len(list))
+	for i, t := range list {
+		item := make(map[string]any, len(t.Raw)+2)
+		for k, v := range t.Raw {
+			item[k] = v
+		}
+		item["price_usd"] = t.Price
+		if t.HasChange {
+			item["price_change_24h"] = t.Change
+		}
+		items[i] = item
+	}
+	ui.Println()
+	ui.Println(formatter.FormatWatchlist(map[string]any{"watchlist": items}))
+	ui.Println()
+	return nil
+}
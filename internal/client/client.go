@@ -0,0 +1,272 @@
+// Package client talks to the Boba MCP backend. Every request carries the
+// agent's access token and wallet headers, never follows a redirect, stops
+// when its context is cancelled and is retried once with fresh tokens when
+// the backend rejects the current ones.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/logger"
+)
+
+// Timeouts for requests that are read to the end. Streams have none: they
+// last until the backend or the caller ends them.
+const (
+	CallTimeout  = 60 * time.Second
+	ToolsTimeout = 30 * time.Second
+)
+
+// DefaultMaxResponseSize bounds a response read into memory, unless the
+// client sets its own MaxResponseSize.
+const DefaultMaxResponseSize = 8 << 20
+
+// TokenSource supplies the tokens requests are made with.
+type TokenSource interface {
+	Token() (*config.AuthTokens, error)
+}
+
+// TokenFunc adapts a function, such as auth.EnsureAuthenticated, to a
+// TokenSource.
+type TokenFunc func() (*config.AuthTokens, error)
+
+// Token calls f.
+func (f TokenFunc) Token() (*config.AuthTokens, error) { return f() }
+
+// BobaClient makes requests to the MCP backend.
+type BobaClient struct {
+	// BaseURL is the backend's address. Empty means the configured MCP URL,
+	// read at each request so a config reload takes effect.
+	BaseURL string
+	// Tokens supplies the tokens for each request.
+	Tokens TokenSource
+	// Reauth supplies new tokens after the backend rejected the current
+	// ones. Nil turns the retry off.
+	Reauth TokenSource
+	// MaxResponseSize is the most bytes of a response read into memory;
+	// a larger one fails with a *ResponseTooLargeError. Zero means
+	// DefaultMaxResponseSize. Streams aren't limited.
+	MaxResponseSize int64
+
+	http *http.Client
+}
+
+// New returns a client using tokens for requests and reauth to replace
+// them when they are rejected.
+func New(tokens, reauth TokenSource) *BobaClient {
+	return &BobaClient{Tokens: tokens, Reauth: reauth, http: NoRedirect(0)}
+}
+
+// NoRedirect returns an HTTP client that refuses to follow redirects, so
+// Authorization headers are never forwarded to unintended hosts. A zero
+// timeout means none.
+func NoRedirect(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return errors.New("redirects are not followed for requests carrying credentials")
+		},
+	}
+}
+
+// AuthError means no tokens could be obtained for a request.
+type AuthError struct{ Err error }
+
+func (e *AuthError) Error() string { return fmt.Sprintf("authentication failed: %v", e.Err) }
+func (e *AuthError) Unwrap() error { return e.Err }
+
+// TransportError means the backend couldn't be reached, or the exchange
+// broke off before a full response arrived. Cancelling the request's
+// context ends up here too; errors.Is tells that case apart.
+type TransportError struct{ Err error }
+
+func (e *TransportError) Error() string { return fmt.Sprintf("upstream request failed: %v", e.Err) }
+func (e *TransportError) Unwrap() error { return e.Err }
+
+// UpstreamError is a non-2xx response from the MCP backend.
+type UpstreamError struct {
+	Status int
+	Body   string
+}
+
+func (e *UpstreamError) Error() string {
+	return fmt.Sprintf("upstream returned status %d: %s", e.Status, e.Body)
+}
+
+// StatusCode returns the HTTP status the backend responded with.
+func (e *UpstreamError) StatusCode() int { return e.Status }
+
+// ResponseTooLargeError means the backend's response was larger than the
+// client reads, so it was abandoned rather than cut short.
+type ResponseTooLargeError struct{ Limit int64 }
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response exceeded %s limit", FormatSize(e.Limit))
+}
+
+// FormatSize renders a byte count in whole MB or KB where it divides
+// evenly, as the size limits are set.
+func FormatSize(n int64) string {
+	switch {
+	case n >= 1<<20 && n%(1<<20) == 0:
+		return fmt.Sprintf("%dMB", n>>20)
+	case n >= 1<<10 && n%(1<<10) == 0:
+		return fmt.Sprintf("%dKB", n>>10)
+	}
+	return fmt.Sprintf("%d bytes", n)
+}
+
+// Response is a backend response read in full.
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	// Tokens are the tokens the request was finally made with.
+	Tokens *config.AuthTokens
+}
+
+// OK reports whether the backend answered with a 2xx status.
+func (r *Response) OK() bool { return r.Status >= 200 && r.Status < 300 }
+
+// Token returns the tokens for the next request.
+func (c *BobaClient) Token() (*config.AuthTokens, error) {
+	tokens, err := c.Tokens.Token()
+	if err != nil {
+		return nil, &AuthError{Err: err}
+	}
+	return tokens, nil
+}
+
+func (c *BobaClient) baseURL() string {
+	if c.BaseURL != "" {
+		return c.BaseURL
+	}
+	return config.GetMCPURL()
+}
+
+// CallTool invokes a tool with args. The response is returned whenever the
+// backend answered; a non-2xx answer also returns an *UpstreamError.
+func (c *BobaClient) CallTool(ctx context.Context, name string, args map[string]any) (*Response, error) {
+	// Sent as { "tool": ..., "args": ... }, the format the backend expects.
+	payload, err := json.Marshal(map[string]any{"tool": name, "args": args})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	return c.fetch(ctx, CallTimeout, func(ctx context.Context) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL()+"/call", bytes.NewReader(payload))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req, err
+	})
+}
+
+// ListTools fetches the tools the backend offers this agent.
+func (c *BobaClient) ListTools(ctx context.Context) (*Response, error) {
+	return c.fetch(ctx, ToolsTimeout, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", c.baseURL()+"/tools", nil)
+	})
+}
+
+// Stream opens the backend's Server-Sent Events stream with the given raw
+// query. The caller reads and closes the body; it ends when ctx does.
+func (c *BobaClient) Stream(ctx context.Context, rawQuery string) (*http.Response, error) {
+	url := c.baseURL() + "/stream"
+	if rawQuery != "" {
+		url += "?" + rawQuery
+	}
+	resp, _, err := c.do(ctx, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, "GET", url, nil)
+	})
+	return resp, err
+}
+
+func (c *BobaClient) maxResponseSize() int64 {
+	if c.MaxResponseSize > 0 {
+		return c.MaxResponseSize
+	}
+	return DefaultMaxResponseSize
+}
+
+// fetch makes a request and reads the whole response within timeout, or
+// by the deadline ctx already carries.
+func (c *BobaClient) fetch(ctx context.Context, timeout time.Duration, build func(context.Context) (*http.Request, error)) (*Response, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	resp, tokens, err := c.do(ctx, build)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	limit := c.maxResponseSize()
+	if resp.ContentLength > limit {
+		return nil, &ResponseTooLargeError{Limit: limit}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, &TransportError{Err: fmt.Errorf("failed to read response: %w", err)}
+	}
+	if int64(len(body)) > limit {
+		return nil, &ResponseTooLargeError{Limit: limit}
+	}
+	out := &Response{Status: resp.StatusCode, Header: resp.Header, Body: body, Tokens: tokens}
+	if !out.OK() {
+		return out, &UpstreamError{Status: out.Status, Body: string(body)}
+	}
+	return out, nil
+}
+
+// do sends the request build makes, and once more with new tokens if the
+// backend rejects the first ones with 401 or 403.
+func (c *BobaClient) do(ctx context.Context, build func(context.Context) (*http.Request, error)) (*http.Response, *config.AuthTokens, error) {
+	tokens, err := c.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := c.send(ctx, build, tokens)
+	if err != nil || c.Reauth == nil || (resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusForbidden) {
+		return resp, tokens, err
+	}
+
+	logger.Debug("received auth error from upstream, re-authenticating", "status", resp.StatusCode)
+	newTokens, authErr := c.Reauth.Token()
+	if authErr != nil {
+		// Hand back the rejection; it says more than the failed renewal.
+		logger.Debug("re-authentication failed", "error", authErr)
+		return resp, tokens, nil
+	}
+	resp.Body.Close()
+	resp, err = c.send(ctx, build, newTokens)
+	return resp, newTokens, err
+}
+
+// send makes one attempt with the agent's headers.
+func (c *BobaClient) send(ctx context.Context, build func(context.Context) (*http.Request, error), tokens *config.AuthTokens) (*http.Response, error) {
+	req, err := build(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+tokens.AccessToken)
+	req.Header.Set("X-Agent-EVM-Address", tokens.EVMAddress)
+	req.Header.Set("X-Agent-Solana-Address", tokens.SolanaAddress)
+	req.Header.Set("X-Agent-Sub-Org-Id", tokens.SubOrganizationID)
+	if id := RequestID(ctx); id != "" {
+		req.Header.Set(RequestIDHeader, id)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, &TransportError{Err: err}
+	}
+	return resp, nil
+}
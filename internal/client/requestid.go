@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// RequestIDHeader carries the ID that ties a call to the backend's logs.
+// The backend answers with its own when it assigns one.
+const RequestIDHeader = "X-Request-Id"
+
+type requestIDKey struct{}
+
+// NewRequestID returns a random (version 4) UUID.
+func NewRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// WithRequestID returns a context whose requests are sent with id as their
+// RequestIDHeader.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID ctx carries, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID returns the ID the backend gave the request, or fallback when
+// it didn't send one.
+func (r *Response) RequestID(fallback string) string {
+	if r != nil {
+		if id := r.Header.Get(RequestIDHeader); id != "" {
+			return id
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,89 @@
+// Package clipboard copies text to the system clipboard from a terminal
+// program, including one running over ssh.
+package clipboard
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/muesli/termenv"
+)
+
+// ErrUnavailable means there was no way to reach a clipboard: stdout is not
+// a terminal and no clipboard tool was found.
+var ErrUnavailable = errors.New("no clipboard available")
+
+// Copy puts text on the clipboard. It asks the terminal to, with an OSC 52
+// escape sequence, which reaches the local clipboard over ssh in the
+// terminals that support it. On this machine it also runs the platform's
+// clipboard tool, for the terminals that ignore the sequence. The terminal
+// doesn't say whether it took the text, so Copy only fails when neither
+// could be tried.
+func Copy(text string) error {
+	osc := isTerminal(os.Stdout)
+	if osc {
+		termenv.DefaultOutput().Copy(text)
+	}
+	if remote() {
+		if osc {
+			return nil
+		}
+		return ErrUnavailable
+	}
+	err := runTool(text)
+	if err != nil && osc {
+		return nil
+	}
+	return err
+}
+
+// remote reports whether this is an ssh session, where the clipboard tools
+// would copy on the wrong machine.
+func remote() bool {
+	return os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != ""
+}
+
+// runTool copies text with the first clipboard tool found for the
+// platform.
+func runTool(text string) error {
+	for _, argv := range tools() {
+		path, err := exec.LookPath(argv[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, argv[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+	return ErrUnavailable
+}
+
+// tools lists the clipboard commands to try, best first.
+func tools() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip.exe"}}
+	}
+	var list [][]string
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		list = append(list, []string{"wl-copy"})
+	}
+	if os.Getenv("DISPLAY") != "" {
+		list = append(list, []string{"xclip", "-selection", "clipboard"}, []string{"xsel", "--clipboard", "--input"})
+	}
+	// WSL reaches the Windows clipboard through clip.exe.
+	return append(list, []string{"clip.exe"})
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Chain describes a supported chain: its display name as returned by the
+// portfolio API, the slug the MCP tools accept, extra accepted aliases, the
+// numeric chain ID the MCP tools use (EVM IDs, plus Solana's) and the ticker
+// of its native gas token.
+type Chain struct {
+	Name    string
+	Slug    string
+	Aliases []string
+	ChainID int
+	Native  string // ticker of the gas token
+}
+
+// SolanaChainID is the numeric ID the MCP backend uses for Solana.
+const SolanaChainID = 1399811149
+
+// Chains lists supported chains in display order.
+var Chains = []Chain{
+	{Name: "Solana", Slug: "solana", Aliases: []string{"sol"}, ChainID: SolanaChainID, Native: "SOL"},
+	{Name: "Base", Slug: "base", ChainID: 8453, Native: "ETH"},
+	{Name: "BSC", Slug: "bsc", Aliases: []string{"bnb", "binance"}, ChainID: 56, Native: "BNB"},
+	{Name: "Ethereum", Slug: "eth", Aliases: []string{"ethereum", "mainnet"}, ChainID: 1, Native: "ETH"},
+	{Name: "Arbitrum", Slug: "arb", Aliases: []string{"arbitrum"}, ChainID: 42161, Native: "ETH"},
+	{Name: "Avalanche", Slug: "avax", Aliases: []string{"avalanche"}, ChainID: 43114, Native: "AVAX"},
+	{Name: "Ape Chain", Slug: "apechain", Aliases: []string{"ape"}, ChainID: 33139, Native: "APE"},
+	{Name: "HyperEVM", Slug: "hyperevm", Aliases: []string{"hyper"}, ChainID: 999, Native: "HYPE"},
+	{Name: "Monad", Slug: "monad", ChainID: 143, Native: "MON"},
+}
+
+// LookupChain finds a chain by display name, slug or alias (case-insensitive).
+func LookupChain(s string) (Chain, bool) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	for _, c := range Chains {
+		if strings.ToLower(c.Name) == key || c.Slug == key {
+			return c, true
+		}
+		for _, a := range c.Aliases {
+			if a == key {
+				return c, true
+			}
+		}
+	}
+	return Chain{}, false
+}
+
+// LookupChainID finds a chain by its numeric ID.
+func LookupChainID(id int) (Chain, bool) {
+	for _, c := range Chains {
+		if c.ChainID == id {
+			return c, true
+		}
+	}
+	return Chain{}, false
+}
+
+// NormalizeChain resolves user input for a --chain flag to the MCP slug.
+// It rejects unknown chains and chains disabled in config.
+func NormalizeChain(s string) (string, error) {
+	c, ok := LookupChain(s)
+	if !ok {
+		return "", fmt.Errorf("unknown chain %q", s)
+	}
+	if !IsChainEnabled(c.Slug) {
+		return "", fmt.Errorf("chain %s disabled in config (enable it with 'boba config chains')", c.Name)
+	}
+	return c.Slug, nil
+}
+
+// EnabledChains returns the configured chain slugs. An empty list means
+// every chain is enabled.
+func EnabledChains() []string {
+	return Load().EnabledChains
+}
+
+// IsChainEnabled reports whether a chain (by name, slug or alias) is enabled.
+// Chains this build doesn't know about are only enabled when no explicit
+// list is configured.
+func IsChainEnabled(s string) bool {
+	enabled := Load().EnabledChains
+	if len(enabled) == 0 {
+		return true
+	}
+	c, ok := LookupChain(s)
+	if !ok {
+		return false
+	}
+	for _, slug := range enabled {
+		if slug == c.Slug {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEnabledChains saves the enabled chain list. Passing every chain (or
+// none) clears the setting so future chains are enabled by default.
+func SetEnabledChains(slugs []string) error {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, s := range slugs {
+		c, ok := LookupChain(s)
+		if !ok {
+			return fmt.Errorf("unknown chain %q", s)
+		}
+		if !seen[c.Slug] {
+			seen[c.Slug] = true
+			normalized = append(normalized, c.Slug)
+		}
+	}
+	if len(normalized) == len(Chains) {
+		normalized = nil
+	}
+
+	c := Load()
+	c.EnabledChains = normalized
+	return save()
+}
+
+// ReloadEnabledChains re-reads the chain list from disk so long-running
+// processes pick up changes made by `boba config chains` without a restart.
+// It holds the save lock so it can't interleave with a save merging the file.
+func ReloadEnabledChains() {
+	c := Load()
+	saveMu.Lock()
+	defer saveMu.Unlock()
+	disk := readDisk()
+	if disk == nil {
+		return
+	}
+	c.EnabledChains = disk.EnabledChains
+	// The list now matches the file, so a later save must not take it for a
+	// change of ours and write it back over someone else's.
+	if loaded != nil {
+		if v, ok := configFields(disk)["enabledChains"]; ok {
+			loaded["enabledChains"] = v
+		} else {
+			delete(loaded, "enabledChains")
+		}
+	}
+}
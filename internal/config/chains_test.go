@@ -0,0 +1,108 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeOther stands in for another process changing the chain list on disk.
+func writeOther(t *testing.T, chains []string) {
+	t.Helper()
+	other := *readDisk()
+	other.EnabledChains = chains
+	other.Generation++
+	data, _ := json.Marshal(other)
+	if err := os.WriteFile(ConfigPath(), data, PrivateFileMode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNormalizeChain(t *testing.T) {
+	useTempDir(t)
+	if err := SetEnabledChains([]string{"sol", "Base"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		in, want, err string
+	}{
+		{in: "solana", want: "solana"},
+		{in: "SOL", want: "solana"},
+		{in: " base ", want: "base"},
+		{in: "bnb", err: "chain BSC disabled in config"},
+		{in: "ethereum", err: "chain Ethereum disabled in config"},
+		{in: "dogechain", err: `unknown chain "dogechain"`},
+	} {
+		got, err := NormalizeChain(tc.in)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("NormalizeChain(%q) error = %v, want %q", tc.in, err, tc.err)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("NormalizeChain(%q) = %q, %v, want %q", tc.in, got, err, tc.want)
+		}
+	}
+}
+
+func TestIsChainEnabled(t *testing.T) {
+	useTempDir(t)
+	for _, name := range []string{"Solana", "Monad", "some-future-chain"} {
+		if !IsChainEnabled(name) {
+			t.Errorf("%s disabled with no list configured", name)
+		}
+	}
+
+	if err := SetEnabledChains([]string{"base", "base", "arbitrum"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := EnabledChains(), []string{"base", "arb"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("EnabledChains() = %v, want %v", got, want)
+	}
+	for name, want := range map[string]bool{"Base": true, "Arbitrum": true, "arb": true, "Solana": false, "some-future-chain": false} {
+		if got := IsChainEnabled(name); got != want {
+			t.Errorf("IsChainEnabled(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	var all []string
+	for _, c := range Chains {
+		all = append(all, c.Slug)
+	}
+	if err := SetEnabledChains(all); err != nil {
+		t.Fatal(err)
+	}
+	if got := EnabledChains(); got != nil {
+		t.Errorf("enabling every chain stored %v, want the setting cleared", got)
+	}
+	if err := SetEnabledChains([]string{"dogechain"}); err == nil {
+		t.Error("SetEnabledChains accepted an unknown chain")
+	}
+}
+
+// A running process picks up a chain list changed on disk, and its own
+// later saves don't write the list it read back over a newer one.
+func TestReloadEnabledChains(t *testing.T) {
+	useTempDir(t)
+	if err := SetEnabledChains([]string{"sol", "base"}); err != nil {
+		t.Fatal(err)
+	}
+
+	writeOther(t, []string{"base"})
+	ReloadEnabledChains()
+	if IsChainEnabled("solana") || !IsChainEnabled("base") {
+		t.Fatalf("reload kept the old list: %v", EnabledChains())
+	}
+
+	writeOther(t, []string{"sol"})
+	if err := SetProxyPort(4100); err != nil {
+		t.Fatal(err)
+	}
+	disk := readDisk()
+	if !reflect.DeepEqual(disk.EnabledChains, []string{"sol"}) || disk.ProxyPort != 4100 {
+		t.Errorf("save clobbered the chain list: chains %v, port %d", disk.EnabledChains, disk.ProxyPort)
+	}
+}
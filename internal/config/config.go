@@ -3,14 +3,18 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"net"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/zalando/go-keyring"
+	"github.com/tradeboba/boba-cli/internal/logger"
 )
 
 const (
@@ -19,11 +23,18 @@ const (
 	KeychainAccessToken  = "access-token"
 	KeychainRefreshToken = "refresh-token"
 	KeychainSessionToken = "session-token"
+	KeychainRetiredToken = "retired-session-token"
 
 	DefaultMCPURL   = "https://mcp-skunk.up.railway.app"
 	DefaultAuthURL  = "https://krakend-skunk.up.railway.app/v2"
 	DefaultPort     = 3456
 	DefaultLogLevel = "info"
+
+	DefaultToolCallBudget = 200
+	// Default per-tool call rate, burst and concurrent call cap for the proxy.
+	DefaultRateLimit      = 5
+	DefaultRateLimitBurst = 10
+	DefaultMaxInFlight    = 8
 )
 
 // Env var fallback names for headless systems without a keyring.
@@ -34,45 +45,8 @@ var envVarMap = map[string]string{
 	KeychainSessionToken: "BOBA_SESSION_TOKEN",
 }
 
-// keyringOK is true when the OS keyring backend is usable.
-var keyringOK = sync.OnceValue(func() bool {
-	const probe = "boba-cli-keyring-probe"
-	if err := keyring.Set(KeychainService, probe, "ok"); err != nil {
-		fmt.Fprintln(os.Stderr, "warning: system keyring unavailable, falling back to environment variables (BOBA_AGENT_SECRET, BOBA_ACCESS_TOKEN, etc.)")
-		return false
-	}
-	_ = keyring.Delete(KeychainService, probe)
-	return true
-})
-
-func secureGet(account string) (string, error) {
-	if keyringOK() {
-		if val, err := keyring.Get(KeychainService, account); err == nil {
-			return val, nil
-		}
-	}
-	if envVar, ok := envVarMap[account]; ok {
-		if val := os.Getenv(envVar); val != "" {
-			return val, nil
-		}
-	}
-	return "", fmt.Errorf("%s not found in keyring or environment", account)
-}
-
-func secureSet(account, value string) error {
-	if keyringOK() {
-		return keyring.Set(KeychainService, account, value)
-	}
-	// No keyring available — user manages secrets via env vars.
-	return nil
-}
-
-func secureDelete(account string) {
-	if keyringOK() {
-		_ = keyring.Delete(KeychainService, account)
-	}
-}
-
+// AllowedHosts are the built-in hosts the MCP and auth URLs may point at.
+// 'boba config allow-host' adds more, kept in the config file.
 var AllowedHosts = []string{
 	"mcp-skunk.up.railway.app",
 	"krakend-skunk.up.railway.app",
@@ -99,28 +73,83 @@ type AuthTokens struct {
 }
 
 type BobaConfig struct {
-	MCPURL      string `json:"mcpUrl"`
-	AuthURL     string `json:"authUrl"`
-	ProxyPort   int    `json:"proxyPort"`
-	LogLevel    string `json:"logLevel"`
+	MCPURL           string   `json:"mcpUrl"`
+	AuthURL          string   `json:"authUrl"`
+	ProxyPort        int      `json:"proxyPort"`
+	LogLevel         string   `json:"logLevel"`
+	Accessible       bool     `json:"accessible,omitempty"`
+	UpdateChannel    string   `json:"updateChannel,omitempty"`
+	NoUpdateCheck    bool     `json:"noUpdateCheck,omitempty"`
+	EnabledChains    []string `json:"enabledChains,omitempty"`
+	SlowTerminal     bool     `json:"slowTerminal,omitempty"`
+	HeartbeatSeconds int      `json:"heartbeatSeconds,omitempty"`
+	LogHistory       int      `json:"logHistory,omitempty"`
+	AlertInterval    int      `json:"alertIntervalSeconds,omitempty"`
+	ToolCallBudget   int      `json:"toolCallBudget,omitempty"`
+	ToolCallHardCap  int      `json:"toolCallHardCap,omitempty"`
+	ConfirmTrades    bool     `json:"confirmTrades,omitempty"`
+	AuditTrail       bool     `json:"auditTrail,omitempty"`
+	MaxResponseMB    int      `json:"maxResponseSizeMB,omitempty"`
+	// ToolPolicy limits which tools agents can call; nil allows all.
+	ToolPolicy *ToolPolicy `json:"toolPolicy,omitempty"`
+	// RateLimit is calls per second per tool and MaxInFlight the cap on
+	// concurrent calls; 0 means the default and a negative value turns the
+	// limit off.
+	RateLimit      float64 `json:"rateLimit,omitempty"`
+	RateLimitBurst int     `json:"rateLimitBurst,omitempty"`
+	MaxInFlight    int     `json:"maxInFlight,omitempty"`
+	// DustThreshold hides dashboard positions worth less, in the display
+	// currency; 0 means the default and a negative value shows everything.
+	DustThreshold   float64 `json:"dustThreshold,omitempty"`
+	DisplayCurrency string  `json:"displayCurrency,omitempty"`
+	// Theme is the color theme: auto, dark, light or mono.
+	Theme string `json:"theme,omitempty"`
+	// MaxPriceImpact is the price impact, in percent, above which boba swap
+	// refuses a quote; 0 means the default.
+	MaxPriceImpact float64 `json:"maxPriceImpact,omitempty"`
+	// BindAddress is the address the proxy listens on; empty means
+	// 127.0.0.1. Any other address exposes it to the network.
+	BindAddress string `json:"bindAddress,omitempty"`
+	// Timeouts overrides the proxy's tool call timeouts, in seconds, by
+	// category (default, lookup, portfolio, audit, trade).
+	Timeouts map[string]int `json:"timeouts,omitempty"`
+	// Generation is bumped on every save so concurrent boba processes can
+	// tell when the file changed under them.
+	Generation int `json:"generation,omitempty"`
+	// ExplorerAPIKeys maps chain slugs to block explorer API keys.
+	ExplorerAPIKeys map[string]string `json:"explorerApiKeys,omitempty"`
+	// CustomHosts are allowed in addition to AllowedHosts.
+	CustomHosts []string `json:"allowedHosts,omitempty"`
 	Credentials *struct {
 		AgentID string `json:"agentId"`
 		Name    string `json:"name,omitempty"`
 	} `json:"credentials,omitempty"`
-	Tokens *struct {
-		AccessTokenExpiresAt  string `json:"accessTokenExpiresAt"`
-		RefreshTokenExpiresAt string `json:"refreshTokenExpiresAt"`
-		AgentID               string `json:"agentId"`
-		AgentName             string `json:"agentName"`
-		EVMAddress            string `json:"evmAddress"`
-		SolanaAddress         string `json:"solanaAddress"`
-		SubOrganizationID     string `json:"subOrganizationId"`
-	} `json:"tokens,omitempty"`
+	Tokens *tokenInfo `json:"tokens,omitempty"`
+}
+
+// tokenInfo is what the config file keeps about the auth tokens; the tokens
+// themselves live in the keyring.
+type tokenInfo struct {
+	AccessTokenExpiresAt  string `json:"accessTokenExpiresAt"`
+	RefreshTokenExpiresAt string `json:"refreshTokenExpiresAt"`
+	AgentID               string `json:"agentId"`
+	AgentName             string `json:"agentName"`
+	EVMAddress            string `json:"evmAddress"`
+	SolanaAddress         string `json:"solanaAddress"`
+	SubOrganizationID     string `json:"subOrganizationId"`
 }
 
 var cfg *BobaConfig
 var configPath string
 
+// cfgMu guards the cfg pointer. tokensMu guards cfg.Tokens, which the proxy
+// replaces from request goroutines while others read it; the token writers
+// also hold the save lock.
+var (
+	cfgMu    sync.Mutex
+	tokensMu sync.RWMutex
+)
+
 func init() {
 	configPath = getConfigPath()
 }
@@ -139,55 +168,72 @@ func ConfigPath() string {
 }
 
 func Load() *BobaConfig {
-	if cfg != nil {
-		return cfg
+	cfgMu.Lock()
+	if c := cfg; c != nil {
+		cfgMu.Unlock()
+		return c
 	}
 
-	cfg = &BobaConfig{
+	c := &BobaConfig{
 		MCPURL:    DefaultMCPURL,
 		AuthURL:   DefaultAuthURL,
 		ProxyPort: DefaultPort,
 		LogLevel:  DefaultLogLevel,
 	}
+	cfg = c
 
 	data, err := os.ReadFile(configPath)
 	if err != nil {
+		loaded = configFields(c)
+		cfgMu.Unlock()
+		// Outside cfgMu: migrating saves, and saving takes the save lock,
+		// which Reload holds while taking cfgMu.
 		migrateFromTS()
-		return cfg
+		return c
 	}
+	defer cfgMu.Unlock()
 
-	if err := json.Unmarshal(data, cfg); err != nil {
-		return cfg
+	if err := json.Unmarshal(data, c); err != nil {
+		return c
 	}
 
-	if cfg.MCPURL == "" {
-		cfg.MCPURL = DefaultMCPURL
-	}
-	if cfg.AuthURL == "" {
-		cfg.AuthURL = DefaultAuthURL
-	}
-	if cfg.ProxyPort == 0 {
-		cfg.ProxyPort = DefaultPort
-	}
-	if cfg.LogLevel == "" {
-		cfg.LogLevel = DefaultLogLevel
-	}
+	applyDefaults(c)
+	loaded = configFields(c)
 
-	return cfg
+	return c
 }
 
-func save() error {
-	dir := filepath.Dir(configPath)
-	if err := os.MkdirAll(dir, 0700); err != nil {
-		return err
+// CheckConfigFile reports why the config file can't be read or parsed. A
+// missing file is fine; the defaults apply.
+func CheckConfigFile() error {
+	data, err := os.ReadFile(configPath)
+	if os.IsNotExist(err) {
+		return nil
 	}
-
-	data, err := json.MarshalIndent(cfg, "", "  ")
 	if err != nil {
 		return err
 	}
+	var c BobaConfig
+	return json.Unmarshal(data, &c)
+}
 
-	return os.WriteFile(configPath, data, 0600)
+func applyDefaults(c *BobaConfig) {
+	if c.MCPURL == "" {
+		c.MCPURL = DefaultMCPURL
+	}
+	if c.AuthURL == "" {
+		c.AuthURL = DefaultAuthURL
+	}
+	if c.ProxyPort == 0 {
+		c.ProxyPort = DefaultPort
+	}
+	if c.LogLevel == "" {
+		c.LogLevel = DefaultLogLevel
+	}
+}
+
+func save() error {
+	return withConfigLock(func() error { return writeConfig(false) })
 }
 
 // Credentials
@@ -239,21 +285,33 @@ func SetCredentials(agentID, secret, name string) error {
 func ClearCredentials() error {
 	c := Load()
 	c.Credentials = nil
+	tokensMu.Lock()
 	c.Tokens = nil
+	tokensMu.Unlock()
 
 	secureDelete(KeychainSecret)
 	secureDelete(KeychainAccessToken)
 	secureDelete(KeychainRefreshToken)
 	secureDelete(KeychainSessionToken)
+	secureDelete(KeychainRetiredToken)
 
 	return save()
 }
 
 // Tokens
 
-func GetTokens() (*AuthTokens, error) {
+// storedTokens returns the token details from the config file, or nil. The
+// result is never modified; SetTokens replaces it.
+func storedTokens() *tokenInfo {
 	c := Load()
-	if c.Tokens == nil {
+	tokensMu.RLock()
+	defer tokensMu.RUnlock()
+	return c.Tokens
+}
+
+func GetTokens() (*AuthTokens, error) {
+	info := storedTokens()
+	if info == nil {
 		return nil, fmt.Errorf("no auth tokens")
 	}
 
@@ -267,27 +325,20 @@ func GetTokens() (*AuthTokens, error) {
 	return &AuthTokens{
 		AccessToken:           accessToken,
 		RefreshToken:          refreshToken,
-		AccessTokenExpiresAt:  c.Tokens.AccessTokenExpiresAt,
-		RefreshTokenExpiresAt: c.Tokens.RefreshTokenExpiresAt,
-		AgentID:               c.Tokens.AgentID,
-		AgentName:             c.Tokens.AgentName,
-		EVMAddress:            c.Tokens.EVMAddress,
-		SolanaAddress:         c.Tokens.SolanaAddress,
-		SubOrganizationID:     c.Tokens.SubOrganizationID,
+		AccessTokenExpiresAt:  info.AccessTokenExpiresAt,
+		RefreshTokenExpiresAt: info.RefreshTokenExpiresAt,
+		AgentID:               info.AgentID,
+		AgentName:             info.AgentName,
+		EVMAddress:            info.EVMAddress,
+		SolanaAddress:         info.SolanaAddress,
+		SubOrganizationID:     info.SubOrganizationID,
 	}, nil
 }
 
+// SetTokens stores tokens in the keyring and their details in the config
+// file. Concurrent callers are serialized.
 func SetTokens(tokens *AuthTokens) error {
-	c := Load()
-	c.Tokens = &struct {
-		AccessTokenExpiresAt  string `json:"accessTokenExpiresAt"`
-		RefreshTokenExpiresAt string `json:"refreshTokenExpiresAt"`
-		AgentID               string `json:"agentId"`
-		AgentName             string `json:"agentName"`
-		EVMAddress            string `json:"evmAddress"`
-		SolanaAddress         string `json:"solanaAddress"`
-		SubOrganizationID     string `json:"subOrganizationId"`
-	}{
+	info := &tokenInfo{
 		AccessTokenExpiresAt:  tokens.AccessTokenExpiresAt,
 		RefreshTokenExpiresAt: tokens.RefreshTokenExpiresAt,
 		AgentID:               tokens.AgentID,
@@ -297,43 +348,105 @@ func SetTokens(tokens *AuthTokens) error {
 		SubOrganizationID:     tokens.SubOrganizationID,
 	}
 
-	if err := secureSet(KeychainAccessToken, tokens.AccessToken); err != nil {
-		return fmt.Errorf("failed to store access token: %w", err)
-	}
+	c := Load()
+	return withConfigLock(func() error {
+		if err := secureSet(KeychainAccessToken, tokens.AccessToken); err != nil {
+			return fmt.Errorf("failed to store access token: %w", err)
+		}
 
-	if tokens.RefreshToken != "" {
-		if err := secureSet(KeychainRefreshToken, tokens.RefreshToken); err != nil {
-			return fmt.Errorf("failed to store refresh token: %w", err)
+		if tokens.RefreshToken != "" {
+			if err := secureSet(KeychainRefreshToken, tokens.RefreshToken); err != nil {
+				return fmt.Errorf("failed to store refresh token: %w", err)
+			}
 		}
-	}
 
-	return save()
+		tokensMu.Lock()
+		c.Tokens = info
+		tokensMu.Unlock()
+		unparsedExpiry.Lock()
+		unparsedExpiry.since = time.Time{}
+		unparsedExpiry.Unlock()
+		return writeConfig(false)
+	})
 }
 
 func IsTokenExpired() bool {
-	c := Load()
-	if c.Tokens == nil || c.Tokens.AccessTokenExpiresAt == "" {
+	info := storedTokens()
+	if info == nil || info.AccessTokenExpiresAt == "" {
 		return true
 	}
 
-	expiresAt, err := parseTime(c.Tokens.AccessTokenExpiresAt)
+	expiresAt, err := parseTime(info.AccessTokenExpiresAt)
 	if err != nil {
-		return true
+		return !unparsedExpiryValid(info.AccessTokenExpiresAt)
 	}
 
 	// Consider expired 1 minute before actual expiry (matches TS version)
 	return time.Now().After(expiresAt.Add(-60 * time.Second))
 }
 
+// AccessTokenExpiry returns when the stored access token expires. ok is
+// false when no token has been fetched yet.
+func AccessTokenExpiry() (expiresAt time.Time, ok bool, err error) {
+	info := storedTokens()
+	if info == nil || info.AccessTokenExpiresAt == "" {
+		return time.Time{}, false, nil
+	}
+	expiresAt, err = parseTime(info.AccessTokenExpiresAt)
+	return expiresAt, true, err
+}
+
+// unparsedExpiryGrace is how long an access token whose expiry can't be
+// parsed is trusted before it is renewed anyway. A 401 renews it sooner.
+const unparsedExpiryGrace = 10 * time.Minute
+
+// unparsedExpiry tracks the grace window of the stored access token when
+// its expiry can't be parsed. SetTokens starts a new window.
+var unparsedExpiry struct {
+	sync.Mutex
+	since time.Time
+	warn  sync.Once
+}
+
+// unparsedExpiryValid reports whether an access token whose expiry is in an
+// unknown format should still be used. Treating it as expired would renew
+// it before every call; instead it is used for a grace window, warning
+// once about the format.
+func unparsedExpiryValid(raw string) bool {
+	unparsedExpiry.warn.Do(func() {
+		logger.Warn("unrecognized access token expiry format, renewing the token on a timer instead", "value", raw)
+	})
+	if _, err := secureGet(KeychainAccessToken); err != nil {
+		return false
+	}
+	unparsedExpiry.Lock()
+	defer unparsedExpiry.Unlock()
+	if unparsedExpiry.since.IsZero() {
+		unparsedExpiry.since = time.Now()
+	}
+	return time.Since(unparsedExpiry.since) < unparsedExpiryGrace
+}
+
 // parseTime tries multiple common timestamp formats to handle whatever the
-// backend returns (with or without fractional seconds, Z or offset).
+// backend returns (with or without fractional seconds, Z or offset, RFC
+// 1123), as well as unix seconds or milliseconds.
 func parseTime(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil && n > 0 {
+		// Milliseconds pass 1e12 in 2001; seconds won't for millennia.
+		if n >= 1e12 {
+			return time.UnixMilli(n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
 	formats := []string{
-		time.RFC3339Nano,                // 2006-01-02T15:04:05.999999999Z07:00
-		time.RFC3339,                    // 2006-01-02T15:04:05Z07:00
+		time.RFC3339Nano,               // 2006-01-02T15:04:05.999999999Z07:00
+		time.RFC3339,                   // 2006-01-02T15:04:05Z07:00
 		"2006-01-02T15:04:05.000Z0700", // milliseconds without colon
 		"2006-01-02T15:04:05Z0700",     // no colon in offset
 		"2006-01-02 15:04:05",          // plain datetime
+		time.RFC1123,                   // Mon, 02 Jan 2006 15:04:05 MST
+		time.RFC1123Z,                  // Mon, 02 Jan 2006 15:04:05 -0700
 	}
 	for _, f := range formats {
 		if t, err := time.Parse(f, s); err == nil {
@@ -358,39 +471,82 @@ func ClearSessionToken() error {
 	return nil
 }
 
+// RetireSessionToken moves the session token aside when the proxy stops, so
+// a proxy started shortly after can keep accepting it from bridges that
+// haven't picked up the new one yet.
+func RetireSessionToken() error {
+	token, err := secureGet(KeychainSessionToken)
+	secureDelete(KeychainSessionToken)
+	if err != nil || token == "" {
+		return nil
+	}
+	return secureSet(KeychainRetiredToken, fmt.Sprintf("%d:%s", time.Now().Unix(), token))
+}
+
+// TakeRetiredSessionToken returns the token retired by the previous proxy
+// and when it was retired, removing it from the keyring.
+func TakeRetiredSessionToken() (string, time.Time) {
+	val, err := secureGet(KeychainRetiredToken)
+	secureDelete(KeychainRetiredToken)
+	if err != nil {
+		return "", time.Time{}
+	}
+	ts, token, ok := strings.Cut(val, ":")
+	secs, convErr := strconv.ParseInt(ts, 10, 64)
+	if !ok || convErr != nil || token == "" {
+		return "", time.Time{}
+	}
+	return token, time.Unix(secs, 0)
+}
+
 // Config Getters/Setters
 
+// GetMCPURL returns BOBA_MCP_URL when set, then the config file value.
 func GetMCPURL() string {
+	if v, ok := envURL(EnvMCPURL); ok {
+		return v
+	}
 	return Load().MCPURL
 }
 
 func SetMCPURL(urlStr string, force bool) error {
 	if !force && !IsAllowedURL(urlStr) {
-		return fmt.Errorf("blocked: %s is not an allowed host. Allowed: %v. Use --force to override", urlStr, AllowedHosts)
+		return notAllowedError(urlStr)
 	}
 	c := Load()
 	c.MCPURL = urlStr
 	return save()
 }
 
+// GetAuthURL returns BOBA_AUTH_URL when set, then the config file value.
 func GetAuthURL() string {
+	if v, ok := envURL(EnvAuthURL); ok {
+		return v
+	}
 	return Load().AuthURL
 }
 
 func SetAuthURL(urlStr string, force bool) error {
 	if !force && !IsAllowedURL(urlStr) {
-		return fmt.Errorf("blocked: %s is not an allowed host. Allowed: %v. Use --force to override", urlStr, AllowedHosts)
+		return notAllowedError(urlStr)
 	}
 	c := Load()
 	c.AuthURL = urlStr
 	return save()
 }
 
+// GetProxyPort returns BOBA_PROXY_PORT when set, then the config file value.
 func GetProxyPort() int {
+	if port, ok := envPort(); ok {
+		return port
+	}
 	return Load().ProxyPort
 }
 
 func SetProxyPort(port int) error {
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port %d (must be 1-65535)", port)
+	}
 	c := Load()
 	c.ProxyPort = port
 	return save()
@@ -400,20 +556,457 @@ func GetLogLevel() string {
 	return Load().LogLevel
 }
 
+// LogLevels are the accepted log levels, from most to least verbose.
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+func SetLogLevel(level string) error {
+	if !slices.Contains(LogLevels, level) {
+		return fmt.Errorf("invalid log level %q (use %s)", level, strings.Join(LogLevels, ", "))
+	}
+	c := Load()
+	c.LogLevel = level
+	return save()
+}
+
+// Where a setting's value comes from, as `boba config list` reports it.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+)
+
+// SettingSource reports whether field, named as in the config file, comes
+// from an environment override, the file, or the default.
+func SettingSource(field string) string {
+	if EnvOverride(field) != "" {
+		return SourceEnv
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return SourceDefault
+	}
+	var raw map[string]json.RawMessage
+	if json.Unmarshal(data, &raw) != nil {
+		return SourceDefault
+	}
+	switch v := string(raw[field]); v {
+	case "", "null", `""`, "0":
+		return SourceDefault
+	}
+	return SourceFile
+}
+
+// GetAccessible reports whether screen-reader friendly output is enabled.
+func GetAccessible() bool {
+	return Load().Accessible
+}
+
+func SetAccessible(enabled bool) error {
+	c := Load()
+	c.Accessible = enabled
+	return save()
+}
+
+// GetUpdateChannel returns the release channel for self-update, "stable"
+// unless configured otherwise.
+func GetUpdateChannel() string {
+	if ch := Load().UpdateChannel; ch != "" {
+		return ch
+	}
+	return "stable"
+}
+
+func SetUpdateChannel(channel string) error {
+	c := Load()
+	c.UpdateChannel = channel
+	return save()
+}
+
+// GetUpdateCheck reports whether boba start and boba status look for a
+// newer release once a day.
+func GetUpdateCheck() bool {
+	return !Load().NoUpdateCheck
+}
+
+func SetUpdateCheck(enabled bool) error {
+	c := Load()
+	c.NoUpdateCheck = !enabled
+	return save()
+}
+
+// GetConfirmTrades reports whether the proxy holds swaps and order changes
+// until they are confirmed in the dashboard.
+func GetConfirmTrades() bool {
+	return Load().ConfirmTrades
+}
+
+func SetConfirmTrades(enabled bool) error {
+	c := Load()
+	c.ConfirmTrades = enabled
+	return save()
+}
+
+// GetAuditTrail reports whether the proxy records write calls in full to
+// the audit trail.
+func GetAuditTrail() bool {
+	return Load().AuditTrail
+}
+
+func SetAuditTrail(enabled bool) error {
+	c := Load()
+	c.AuditTrail = enabled
+	return save()
+}
+
+// GetSlowTerminal reports whether animations should be replaced with static
+// renders for high-latency terminals.
+func GetSlowTerminal() bool {
+	return Load().SlowTerminal
+}
+
+func SetSlowTerminal(enabled bool) error {
+	c := Load()
+	c.SlowTerminal = enabled
+	return save()
+}
+
+// GetHeartbeatSeconds returns the configured TUI heartbeat interval in
+// seconds, or 0 when unset.
+func GetHeartbeatSeconds() int {
+	return Load().HeartbeatSeconds
+}
+
+func SetHeartbeatSeconds(seconds int) error {
+	if seconds < 0 || seconds > 60 {
+		return fmt.Errorf("heartbeat must be between 1 and 60 seconds (0 for default)")
+	}
+	c := Load()
+	c.HeartbeatSeconds = seconds
+	return save()
+}
+
+// DefaultLogHistory is how many requests the proxy dashboard's activity log
+// keeps before dropping the oldest.
+const DefaultLogHistory = 500
+
+// GetLogHistory returns how many requests the activity log keeps.
+func GetLogHistory() int {
+	if n := Load().LogHistory; n > 0 {
+		return n
+	}
+	return DefaultLogHistory
+}
+
+func SetLogHistory(n int) error {
+	if n != 0 && (n < 50 || n > 10000) {
+		return fmt.Errorf("log history must be between 50 and 10000 requests (0 for default)")
+	}
+	c := Load()
+	c.LogHistory = n
+	return save()
+}
+
+// DefaultDustThreshold is the value, in the display currency, below which
+// the dashboard hides positions and native balances.
+const DefaultDustThreshold = 1.0
+
+// GetDustThreshold returns the value below which the dashboard hides
+// positions, or 0 when it shows them all.
+func GetDustThreshold() float64 {
+	t := Load().DustThreshold
+	switch {
+	case t < 0:
+		return 0
+	case t == 0:
+		return DefaultDustThreshold
+	}
+	return t
+}
+
+// SetDustThreshold sets the dust threshold; 0 shows every position.
+func SetDustThreshold(t float64) error {
+	if t < 0 {
+		return fmt.Errorf("dust threshold can't be negative")
+	}
+	c := Load()
+	c.DustThreshold = t
+	if t == 0 {
+		c.DustThreshold = -1
+	}
+	return save()
+}
+
+// GetDisplayCurrency returns the ISO code of the currency values are shown
+// in, USD unless set.
+func GetDisplayCurrency() string {
+	if code := Load().DisplayCurrency; code != "" {
+		return code
+	}
+	return "USD"
+}
+
+func SetDisplayCurrency(code string) error {
+	c := Load()
+	c.DisplayCurrency = strings.ToUpper(code)
+	if c.DisplayCurrency == "USD" {
+		c.DisplayCurrency = ""
+	}
+	return save()
+}
+
+// Themes are the accepted color themes. auto picks dark or light for the
+// terminal's background.
+var Themes = []string{"auto", "dark", "light", "mono"}
+
+// GetTheme returns the color theme, auto unless set.
+func GetTheme() string {
+	if t := Load().Theme; t != "" {
+		return t
+	}
+	return "auto"
+}
+
+func SetTheme(theme string) error {
+	if !slices.Contains(Themes, theme) {
+		return fmt.Errorf("invalid theme %q (use %s)", theme, strings.Join(Themes, ", "))
+	}
+	c := Load()
+	c.Theme = theme
+	if theme == "auto" {
+		c.Theme = ""
+	}
+	return save()
+}
+
+// DefaultBindAddress is the address the proxy listens on unless set.
+const DefaultBindAddress = "127.0.0.1"
+
+// GetBindAddress returns the address the proxy listens on.
+func GetBindAddress() string {
+	if a := Load().BindAddress; a != "" {
+		return a
+	}
+	return DefaultBindAddress
+}
+
+// SetBindAddress sets the address the proxy listens on: an IP address such
+// as 0.0.0.0 or one of this machine's, or localhost. Empty restores the
+// default.
+func SetBindAddress(addr string) error {
+	if addr != "" && addr != "localhost" && net.ParseIP(addr) == nil {
+		return fmt.Errorf("invalid bind address %q (use an IP address such as 127.0.0.1 or 0.0.0.0)", addr)
+	}
+	c := Load()
+	c.BindAddress = addr
+	return save()
+}
+
+// DefaultMaxPriceImpact is the price impact, in percent, above which boba
+// swap refuses a quote unless forced.
+const DefaultMaxPriceImpact = 5.0
+
+// GetMaxPriceImpact returns the price impact, in percent, above which boba
+// swap refuses a quote.
+func GetMaxPriceImpact() float64 {
+	if p := Load().MaxPriceImpact; p > 0 {
+		return p
+	}
+	return DefaultMaxPriceImpact
+}
+
+func SetMaxPriceImpact(p float64) error {
+	if p <= 0 || p > 100 {
+		return fmt.Errorf("max price impact must be above 0 and at most 100 percent")
+	}
+	c := Load()
+	c.MaxPriceImpact = p
+	return save()
+}
+
+// GetTimeout returns the configured timeout of a tool category, or 0 when
+// it isn't overridden.
+func GetTimeout(category string) time.Duration {
+	return time.Duration(Load().Timeouts[category]) * time.Second
+}
+
+// GetTimeouts returns the timeout overrides in seconds by category.
+func GetTimeouts() map[string]int {
+	return maps.Clone(Load().Timeouts)
+}
+
+// SetTimeout overrides the timeout of a tool category; 0 seconds restores
+// the default.
+func SetTimeout(category string, seconds int) error {
+	if seconds < 0 || seconds > 300 {
+		return fmt.Errorf("timeout must be between 1 and 300 seconds (0 for default)")
+	}
+	c := Load()
+	if seconds == 0 {
+		delete(c.Timeouts, category)
+	} else {
+		if c.Timeouts == nil {
+			c.Timeouts = make(map[string]int)
+		}
+		c.Timeouts[category] = seconds
+	}
+	return save()
+}
+
+// DefaultAlertInterval is how often, in seconds, the proxy checks the prices
+// of tokens with alerts.
+const DefaultAlertInterval = 60
+
+// GetAlertInterval returns how often the proxy polls prices for alerts.
+func GetAlertInterval() time.Duration {
+	secs := Load().AlertInterval
+	if secs <= 0 {
+		secs = DefaultAlertInterval
+	}
+	return time.Duration(secs) * time.Second
+}
+
+func SetAlertInterval(seconds int) error {
+	if seconds != 0 && (seconds < 10 || seconds > 3600) {
+		return fmt.Errorf("alert interval must be between 10 and 3600 seconds (0 for default)")
+	}
+	c := Load()
+	c.AlertInterval = seconds
+	return save()
+}
+
+// GetToolCallBudget returns the soft per-session tool-call budget. Agents are
+// warned as they approach it but calls keep working.
+func GetToolCallBudget() int {
+	if n := Load().ToolCallBudget; n > 0 {
+		return n
+	}
+	return DefaultToolCallBudget
+}
+
+func SetToolCallBudget(n int) error {
+	if n < 0 {
+		return fmt.Errorf("tool-call budget can't be negative (0 for default)")
+	}
+	c := Load()
+	c.ToolCallBudget = n
+	return save()
+}
+
+// GetToolCallHardCap returns the number of tool calls after which a session
+// is refused further calls; 0 means no cap.
+func GetToolCallHardCap() int {
+	return Load().ToolCallHardCap
+}
+
+// GetRateLimit returns the calls per second allowed per tool, and the burst
+// above that rate; a rate of 0 means no limit.
+func GetRateLimit() (float64, int) {
+	c := Load()
+	rate, burst := c.RateLimit, c.RateLimitBurst
+	switch {
+	case rate < 0:
+		rate = 0
+	case rate == 0:
+		rate = DefaultRateLimit
+	}
+	if burst <= 0 {
+		burst = DefaultRateLimitBurst
+	}
+	return rate, burst
+}
+
+// GetMaxInFlight returns how many tool calls the proxy runs at once; 0 means
+// no cap.
+func GetMaxInFlight() int {
+	switch n := Load().MaxInFlight; {
+	case n < 0:
+		return 0
+	case n == 0:
+		return DefaultMaxInFlight
+	default:
+		return n
+	}
+}
+
+// SetRateLimit saves the proxy's rate limits. A rate or cap of 0 turns that
+// limit off.
+func SetRateLimit(rate float64, burst, maxInFlight int) error {
+	if rate < 0 || burst < 0 || maxInFlight < 0 {
+		return fmt.Errorf("rate limits can't be negative (0 turns a limit off)")
+	}
+	c := Load()
+	c.RateLimit, c.RateLimitBurst, c.MaxInFlight = rate, burst, maxInFlight
+	if rate == 0 {
+		c.RateLimit = -1
+	}
+	if maxInFlight == 0 {
+		c.MaxInFlight = -1
+	}
+	return save()
+}
+
+func SetToolCallHardCap(n int) error {
+	if n < 0 {
+		return fmt.Errorf("tool-call cap can't be negative (0 disables it)")
+	}
+	c := Load()
+	c.ToolCallHardCap = n
+	return save()
+}
+
+// GetMaxResponseSize returns the largest backend response, in bytes, the
+// proxy reads, or 0 for the client's default.
+func GetMaxResponseSize() int64 {
+	return int64(Load().MaxResponseMB) << 20
+}
+
+func SetMaxResponseSize(mb int) error {
+	if mb < 0 || mb > 1024 {
+		return fmt.Errorf("max response size must be between 1 and 1024 MB (0 for default)")
+	}
+	c := Load()
+	c.MaxResponseMB = mb
+	return save()
+}
+
+// GetExplorerAPIKey returns the block explorer API key for a chain slug,
+// falling back to BOBA_EXPLORER_API_KEY.
+func GetExplorerAPIKey(chain string) string {
+	if key := Load().ExplorerAPIKeys[chain]; key != "" {
+		return key
+	}
+	return os.Getenv("BOBA_EXPLORER_API_KEY")
+}
+
+func SetExplorerAPIKey(chain, key string) error {
+	c := Load()
+	if c.ExplorerAPIKeys == nil {
+		c.ExplorerAPIKeys = make(map[string]string)
+	}
+	if key == "" {
+		delete(c.ExplorerAPIKeys, chain)
+	} else {
+		c.ExplorerAPIKeys[chain] = key
+	}
+	return save()
+}
+
 func Reset() error {
+	cfgMu.Lock()
 	cfg = &BobaConfig{
 		MCPURL:    DefaultMCPURL,
 		AuthURL:   DefaultAuthURL,
 		ProxyPort: DefaultPort,
 		LogLevel:  DefaultLogLevel,
 	}
+	cfgMu.Unlock()
 
 	secureDelete(KeychainSecret)
 	secureDelete(KeychainAccessToken)
 	secureDelete(KeychainRefreshToken)
 	secureDelete(KeychainSessionToken)
+	secureDelete(KeychainRetiredToken)
 
-	return save()
+	return withConfigLock(func() error { return writeConfig(true) })
 }
 
 // URL Allowlist
@@ -423,13 +1016,110 @@ func IsAllowedURL(urlStr string) bool {
 	if err != nil {
 		return false
 	}
-	hostname := parsed.Hostname()
-	for _, h := range AllowedHosts {
-		if h == hostname {
-			return true
+	return slices.Contains(AllowedHostList(), strings.ToLower(parsed.Hostname()))
+}
+
+// notAllowedError refuses a URL whose host isn't on the allowlist.
+func notAllowedError(urlStr string) error {
+	return fmt.Errorf("blocked: %s is not an allowed host. Allowed: %v. Allow it with 'boba config allow-host <hostname>', or use --force to override once",
+		urlStr, AllowedHostList())
+}
+
+// AllowedHostList returns the built-in allowed hosts followed by the ones
+// added with 'boba config allow-host'.
+func AllowedHostList() []string {
+	return append(slices.Clone(AllowedHosts), GetCustomHosts()...)
+}
+
+// GetCustomHosts returns the hosts added to the allowlist.
+func GetCustomHosts() []string {
+	return slices.Clone(Load().CustomHosts)
+}
+
+// CustomHost returns the host of urlStr if it is only allowed because it
+// was added to the allowlist, so status can point it out.
+func CustomHost(urlStr string) (string, bool) {
+	parsed, err := url.Parse(urlStr)
+	if err != nil {
+		return "", false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if slices.Contains(AllowedHosts, host) || !slices.Contains(GetCustomHosts(), host) {
+		return "", false
+	}
+	return host, true
+}
+
+// AddAllowedHost adds a bare hostname to the allowlist and returns it as
+// stored. Adding a host that is already allowed changes nothing.
+func AddAllowedHost(host string) (string, error) {
+	host, err := normalizeHost(host)
+	if err != nil {
+		return "", err
+	}
+	c := Load()
+	if slices.Contains(AllowedHosts, host) || slices.Contains(c.CustomHosts, host) {
+		return host, nil
+	}
+	c.CustomHosts = append(c.CustomHosts, host)
+	return host, save()
+}
+
+// RemoveAllowedHost takes a host added with AddAllowedHost off the
+// allowlist. Built-in hosts can't be removed, and neither can a host the MCP
+// or auth URL still points at.
+func RemoveAllowedHost(host string) (string, error) {
+	host, err := normalizeHost(host)
+	if err != nil {
+		return "", err
+	}
+	if slices.Contains(AllowedHosts, host) {
+		return "", fmt.Errorf("%s is built in and can't be removed", host)
+	}
+	c := Load()
+	i := slices.Index(c.CustomHosts, host)
+	if i < 0 {
+		return "", fmt.Errorf("%s is not on the allowlist", host)
+	}
+	for _, u := range []struct{ name, url string }{{"mcp-url", c.MCPURL}, {"auth-url", c.AuthURL}} {
+		if parsed, err := url.Parse(u.url); err == nil && strings.EqualFold(parsed.Hostname(), host) {
+			return "", fmt.Errorf("%s still points at %s; set it back first, e.g. 'boba config set %s <url>'", u.name, host, u.name)
 		}
 	}
-	return false
+	c.CustomHosts = slices.Delete(c.CustomHosts, i, i+1)
+	return host, save()
+}
+
+// normalizeHost checks that host is a bare hostname or IP address, with no
+// scheme, port or path, and lowercases it.
+func normalizeHost(host string) (string, error) {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	switch {
+	case host == "":
+		return "", fmt.Errorf("empty hostname")
+	case strings.Contains(host, "://"):
+		return "", fmt.Errorf("%s is a URL; pass the bare hostname, e.g. staging.example.com", host)
+	case net.ParseIP(host) != nil:
+		return host, nil
+	case strings.ContainsAny(host, "/?#@"):
+		return "", fmt.Errorf("%s is not a bare hostname: leave out the path", host)
+	case strings.Contains(host, ":"):
+		return "", fmt.Errorf("%s is not a bare hostname: leave out the port", host)
+	case len(host) > 253:
+		return "", fmt.Errorf("hostname is too long")
+	}
+	for _, label := range strings.Split(host, ".") {
+		valid := label != "" && len(label) <= 63 && label[0] != '-' && label[len(label)-1] != '-'
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				valid = false
+			}
+		}
+		if !valid {
+			return "", fmt.Errorf("%s is not a valid hostname", host)
+		}
+	}
+	return host, nil
 }
 
 func IsHTTPSOrLocal(urlStr string) bool {
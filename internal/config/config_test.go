@@ -0,0 +1,171 @@
+package config
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+// useTempDir points the config and the keyring at throwaway ones for the
+// length of the test.
+func useTempDir(t *testing.T) {
+	t.Helper()
+	keyring.MockInit()
+	t.Setenv("HOME", t.TempDir())
+	UseDir(t.TempDir())
+	loaded = nil
+	dropSecretCache()
+}
+
+func TestParseTime(t *testing.T) {
+	want := time.Date(2024, 5, 31, 16, 8, 37, 0, time.UTC)
+	for _, s := range []string{
+		"1717171717",
+		" 1717171717 ",
+		"1717171717000",
+		"2024-05-31T16:08:37Z",
+		"2024-05-31T16:08:37.000Z",
+		"2024-05-31T18:08:37+02:00",
+		"2024-05-31T16:08:37.000+0000",
+		"2024-05-31T16:08:37+0000",
+		"2024-05-31 16:08:37",
+		"Fri, 31 May 2024 16:08:37 UTC",
+		"Fri, 31 May 2024 18:08:37 +0200",
+	} {
+		got, err := parseTime(s)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parseTime(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "soon", "0", "-1717171717", "31/05/2024", "1717171717.5"} {
+		if got, err := parseTime(s); err == nil {
+			t.Errorf("parseTime(%q) = %v, want an error", s, got)
+		}
+	}
+}
+
+// Expiries as epoch seconds or milliseconds are honored; one that can't be
+// parsed keeps a stored token in use for a grace window instead of
+// renewing it before every call.
+func TestIsTokenExpired(t *testing.T) {
+	useTempDir(t)
+	if !IsTokenExpired() {
+		t.Error("no tokens: not expired")
+	}
+	setExpiry := func(expiresAt string) {
+		t.Helper()
+		if err := SetTokens(&AuthTokens{AccessToken: "access", AccessTokenExpiresAt: expiresAt}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	future, past := time.Now().Add(time.Hour), time.Now().Add(-time.Hour)
+	for _, tc := range []struct {
+		expiresAt string
+		expired   bool
+	}{
+		{strconv.FormatInt(future.Unix(), 10), false},
+		{strconv.FormatInt(future.UnixMilli(), 10), false},
+		{strconv.FormatInt(past.Unix(), 10), true},
+		{strconv.FormatInt(past.UnixMilli(), 10), true},
+		{strconv.FormatInt(time.Now().Add(30*time.Second).Unix(), 10), true}, // within the minute's margin
+		{future.Format(time.RFC1123), false},
+		{"garbage", false},
+	} {
+		setExpiry(tc.expiresAt)
+		if got := IsTokenExpired(); got != tc.expired {
+			t.Errorf("expiry %q: expired = %v, want %v", tc.expiresAt, got, tc.expired)
+		}
+	}
+
+	// The grace window runs out, and new tokens start a new one.
+	setExpiry("garbage")
+	IsTokenExpired()
+	unparsedExpiry.Lock()
+	unparsedExpiry.since = time.Now().Add(-unparsedExpiryGrace - time.Second)
+	unparsedExpiry.Unlock()
+	if !IsTokenExpired() {
+		t.Error("unparsable expiry still trusted after the grace window")
+	}
+	setExpiry("garbage")
+	if IsTokenExpired() {
+		t.Error("new tokens didn't start a new grace window")
+	}
+
+	// Without the access token itself there is nothing to trust.
+	secureDelete(KeychainAccessToken)
+	if !IsTokenExpired() {
+		t.Error("unparsable expiry without an access token: not expired")
+	}
+}
+
+func TestSetMaxResponseSize(t *testing.T) {
+	useTempDir(t)
+	if n := GetMaxResponseSize(); n != 0 {
+		t.Errorf("default = %d, want 0", n)
+	}
+	for _, bad := range []int{-1, 1025} {
+		if err := SetMaxResponseSize(bad); err == nil {
+			t.Errorf("%d MB accepted", bad)
+		}
+	}
+	if err := SetMaxResponseSize(16); err != nil {
+		t.Fatal(err)
+	}
+	Reload()
+	if n := GetMaxResponseSize(); n != 16<<20 {
+		t.Errorf("saved size = %d, want 16MB", n)
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	useTempDir(t)
+	if got := GetTheme(); got != "auto" {
+		t.Errorf("default theme = %q", got)
+	}
+	if err := SetTheme("solarized"); err == nil {
+		t.Error("unknown theme accepted")
+	}
+	if err := SetTheme("light"); err != nil {
+		t.Fatal(err)
+	}
+	Reload()
+	if got := GetTheme(); got != "light" {
+		t.Errorf("saved theme = %q", got)
+	}
+	if err := SetTheme("auto"); err != nil {
+		t.Fatal(err)
+	}
+	if Load().Theme != "" {
+		t.Errorf("auto saved as %q", Load().Theme)
+	}
+}
+
+func TestSetBindAddress(t *testing.T) {
+	useTempDir(t)
+	if got := GetBindAddress(); got != DefaultBindAddress {
+		t.Errorf("default bind address = %q", got)
+	}
+	for _, bad := range []string{"lan", "192.168.1", "0.0.0.0:7777"} {
+		if err := SetBindAddress(bad); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+	for _, addr := range []string{"0.0.0.0", "::", "localhost", "192.168.1.20"} {
+		if err := SetBindAddress(addr); err != nil {
+			t.Fatal(err)
+		}
+		Reload()
+		if got := GetBindAddress(); got != addr {
+			t.Errorf("saved bind address = %q, want %q", got, addr)
+		}
+	}
+	if err := SetBindAddress(""); err != nil {
+		t.Fatal(err)
+	}
+	if Load().BindAddress != "" || GetBindAddress() != DefaultBindAddress {
+		t.Errorf("reset saved %q", Load().BindAddress)
+	}
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables that override the config file without changing it,
+// for containers and CI runs against a staging backend.
+const (
+	EnvMCPURL    = "BOBA_MCP_URL"
+	EnvAuthURL   = "BOBA_AUTH_URL"
+	EnvProxyPort = "BOBA_PROXY_PORT"
+	// EnvAllowAnyHost lets the URL overrides point outside the allowlist.
+	EnvAllowAnyHost = "BOBA_ALLOW_ANY_HOST"
+)
+
+// SourceEnv marks a setting taken from an environment variable.
+const SourceEnv = "env"
+
+// envOverrides maps config file fields to the variables that override them.
+var envOverrides = map[string]string{
+	"mcpUrl":    EnvMCPURL,
+	"authUrl":   EnvAuthURL,
+	"proxyPort": EnvProxyPort,
+}
+
+// EnvOverride returns the environment variable overriding field, named as in
+// the config file, or "" when the file value applies.
+func EnvOverride(field string) string {
+	name, ok := envOverrides[field]
+	if !ok || os.Getenv(name) == "" {
+		return ""
+	}
+	return name
+}
+
+// CheckEnvOverrides reports the first override that can't be used. Run it at
+// startup so a bad value fails loudly instead of being ignored.
+func CheckEnvOverrides() error {
+	for _, name := range []string{EnvMCPURL, EnvAuthURL} {
+		v := os.Getenv(name)
+		if v == "" {
+			continue
+		}
+		if err := checkEnvURL(v); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if v := os.Getenv(EnvProxyPort); v != "" {
+		if _, err := parseEnvPort(v); err != nil {
+			return fmt.Errorf("%s: %w", EnvProxyPort, err)
+		}
+	}
+	return nil
+}
+
+func checkEnvURL(v string) error {
+	if os.Getenv(EnvAllowAnyHost) != "1" && !IsAllowedURL(v) {
+		return fmt.Errorf("%s is not an allowed host. Allowed: %v. Set %s=1 to override", v, AllowedHostList(), EnvAllowAnyHost)
+	}
+	if !IsHTTPSOrLocal(v) {
+		return fmt.Errorf("%s must use HTTPS", v)
+	}
+	return nil
+}
+
+func parseEnvPort(v string) (int, error) {
+	port, err := strconv.Atoi(v)
+	if err != nil || port < 1 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q (must be 1-65535)", v)
+	}
+	return port, nil
+}
+
+// envURL returns the override in name if it is set and usable.
+func envURL(name string) (string, bool) {
+	v := os.Getenv(name)
+	if v == "" || checkEnvURL(v) != nil {
+		return "", false
+	}
+	return v, true
+}
+
+// envPort returns the BOBA_PROXY_PORT override if it is set and valid.
+func envPort() (int, bool) {
+	v := os.Getenv(EnvProxyPort)
+	if v == "" {
+		return 0, false
+	}
+	port, err := parseEnvPort(v)
+	return port, err == nil
+}
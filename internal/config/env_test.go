@@ -0,0 +1,119 @@
+package config
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Each overridable setting comes from its environment variable when that
+// is set and usable, then from the config file, then the default.
+func TestEnvPrecedence(t *testing.T) {
+	for _, s := range []struct {
+		field, env string
+		get        func() string
+		set        func(string) error
+		def, file  string
+		envValue   string
+		// rejected are overrides that fall back to the file value.
+		rejected []string
+	}{
+		{
+			field: "mcpUrl", env: EnvMCPURL,
+			get: GetMCPURL, set: func(v string) error { return SetMCPURL(v, true) },
+			def: DefaultMCPURL, file: "https://mcp.file.example", envValue: "http://localhost:9000",
+			rejected: []string{"https://mcp.evil.example"},
+		},
+		{
+			field: "authUrl", env: EnvAuthURL,
+			get: GetAuthURL, set: func(v string) error { return SetAuthURL(v, true) },
+			def: DefaultAuthURL, file: "https://auth.file.example", envValue: "https://127.0.0.1:8443/v2",
+			rejected: []string{"https://auth.evil.example"},
+		},
+		{
+			field: "proxyPort", env: EnvProxyPort,
+			get: func() string { return strconv.Itoa(GetProxyPort()) },
+			set: func(v string) error { p, _ := strconv.Atoi(v); return SetProxyPort(p) },
+			def: strconv.Itoa(DefaultPort), file: "4000", envValue: "5000",
+			rejected: []string{"0", "70000", "http"},
+		},
+	} {
+		useTempDir(t)
+		t.Setenv(s.env, "")
+		t.Setenv(EnvAllowAnyHost, "")
+		if got := s.get(); got != s.def {
+			t.Errorf("%s default = %s, want %s", s.field, got, s.def)
+		}
+		if err := s.set(s.file); err != nil {
+			t.Fatal(err)
+		}
+		if got := s.get(); got != s.file {
+			t.Errorf("%s from the file = %s, want %s", s.field, got, s.file)
+		}
+		if EnvOverride(s.field) != "" {
+			t.Errorf("%s reported overridden without %s", s.field, s.env)
+		}
+
+		t.Setenv(s.env, s.envValue)
+		if got := s.get(); got != s.envValue {
+			t.Errorf("%s with %s set = %s, want %s", s.field, s.env, got, s.envValue)
+		}
+		if got := EnvOverride(s.field); got != s.env {
+			t.Errorf("EnvOverride(%s) = %q, want %s", s.field, got, s.env)
+		}
+		if got := Load(); strconv.Itoa(got.ProxyPort) == s.envValue || got.MCPURL == s.envValue || got.AuthURL == s.envValue {
+			t.Errorf("%s override written to the config: %+v", s.env, got)
+		}
+
+		for _, bad := range s.rejected {
+			t.Setenv(s.env, bad)
+			if got := s.get(); got != s.file {
+				t.Errorf("%s with %s=%s = %s, want the file's %s", s.field, s.env, bad, got, s.file)
+			}
+		}
+	}
+}
+
+// A URL override outside the allowlist is used only with
+// BOBA_ALLOW_ANY_HOST=1, and never over plain HTTP to another machine.
+func TestAllowAnyHost(t *testing.T) {
+	useTempDir(t)
+	t.Setenv(EnvAllowAnyHost, "")
+	t.Setenv(EnvMCPURL, "https://staging.example.com")
+	if got := GetMCPURL(); got != DefaultMCPURL {
+		t.Errorf("not allowlisted: %s", got)
+	}
+	t.Setenv(EnvAllowAnyHost, "1")
+	if got := GetMCPURL(); got != "https://staging.example.com" {
+		t.Errorf("with %s=1: %s", EnvAllowAnyHost, got)
+	}
+	t.Setenv(EnvMCPURL, "http://staging.example.com")
+	if got := GetMCPURL(); got != DefaultMCPURL {
+		t.Errorf("plain HTTP with %s=1: %s", EnvAllowAnyHost, got)
+	}
+}
+
+func TestCheckEnvOverrides(t *testing.T) {
+	for _, tc := range []struct {
+		env       map[string]string
+		wantError string // "" for none
+	}{
+		{map[string]string{}, ""},
+		{map[string]string{EnvMCPURL: "https://mcp-skunk.up.railway.app", EnvProxyPort: "4000"}, ""},
+		{map[string]string{EnvMCPURL: "https://evil.example"}, EnvMCPURL + ": https://evil.example is not an allowed host"},
+		{map[string]string{EnvAuthURL: "https://evil.example"}, EnvAuthURL + ": https://evil.example is not an allowed host"},
+		{map[string]string{EnvAuthURL: "https://evil.example", EnvAllowAnyHost: "1"}, ""},
+		{map[string]string{EnvAuthURL: "http://evil.example", EnvAllowAnyHost: "1"}, "must use HTTPS"},
+		{map[string]string{EnvAllowAnyHost: "true", EnvMCPURL: "https://evil.example"}, "not an allowed host"},
+		{map[string]string{EnvProxyPort: "99999"}, EnvProxyPort + `: invalid port "99999"`},
+	} {
+		useTempDir(t)
+		for _, name := range []string{EnvMCPURL, EnvAuthURL, EnvProxyPort, EnvAllowAnyHost} {
+			t.Setenv(name, tc.env[name])
+		}
+		err := CheckEnvOverrides()
+		if tc.wantError == "" && err != nil || tc.wantError != "" && (err == nil || !strings.Contains(err.Error(), tc.wantError)) {
+			t.Errorf("%v: err = %v, want %q", tc.env, err, tc.wantError)
+		}
+	}
+}
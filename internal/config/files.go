@@ -0,0 +1,137 @@
+package config
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Everything boba keeps in its data directory (config, trade journal, logs,
+// caches, exports) can contain balances, trades, tokens or wallet addresses,
+// so it is only readable by the owner.
+const (
+	PrivateFileMode os.FileMode = 0600
+	PrivateDirMode  os.FileMode = 0700
+)
+
+// DataDir returns the directory holding the config file and every other
+// private file boba writes.
+func DataDir() string {
+	return filepath.Dir(configPath)
+}
+
+// UseDir moves the config file and the data directory under dir, dropping
+// the loaded config. Tests use it to keep off the user's own files.
+func UseDir(dir string) {
+	cfgMu.Lock()
+	defer cfgMu.Unlock()
+	configPath = filepath.Join(dir, "boba-cli", "config.json")
+	cfg = nil
+}
+
+// EnsurePrivateDir creates dir with owner-only permissions, tightening it if
+// it already exists.
+func EnsurePrivateDir(dir string) error {
+	if err := os.MkdirAll(dir, PrivateDirMode); err != nil {
+		return err
+	}
+	return os.Chmod(dir, PrivateDirMode)
+}
+
+// WritePrivateFile writes data to path with owner-only permissions. The
+// data goes to a temporary file in the same directory that is synced and
+// then renamed over path, so readers that don't take the config lock see
+// the old contents or the new, never a half-written file.
+func WritePrivateFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := EnsurePrivateDir(dir); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp) // gone already once renamed
+	if err := f.Chmod(PrivateFileMode); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// OpenPrivateAppend opens path for appending with owner-only permissions,
+// creating it and its directory if needed.
+func OpenPrivateAppend(path string) (*os.File, error) {
+	if err := EnsurePrivateDir(filepath.Dir(path)); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, PrivateFileMode); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, PrivateFileMode)
+}
+
+// PermissionIssue is a private file or directory that others can access.
+type PermissionIssue struct {
+	Path string
+	Mode os.FileMode
+	Want os.FileMode
+}
+
+// AuditPermissions lists files and directories under the data directory
+// that are readable or writable by group or others. Windows doesn't use
+// Unix modes, so nothing is reported there.
+func AuditPermissions() ([]PermissionIssue, error) {
+	if runtime.GOOS == "windows" {
+		return nil, nil
+	}
+
+	var issues []PermissionIssue
+	err := filepath.WalkDir(DataDir(), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		want := PrivateFileMode
+		if d.IsDir() {
+			want = PrivateDirMode
+		}
+		if mode := info.Mode().Perm(); mode&0077 != 0 {
+			issues = append(issues, PermissionIssue{Path: path, Mode: mode, Want: want})
+		}
+		return nil
+	})
+	return issues, err
+}
+
+// FixPermissions applies the wanted mode to each issue.
+func FixPermissions(issues []PermissionIssue) error {
+	for _, is := range issues {
+		if err := os.Chmod(is.Path, is.Want); err != nil {
+			return err
+		}
+	}
+	return nil
+}
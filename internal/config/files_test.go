@@ -0,0 +1,150 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// assertPrivate fails the test unless path is an owner-only file in an
+// owner-only directory.
+func assertPrivate(t *testing.T, path string) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != PrivateFileMode {
+		t.Errorf("%s: mode %o, want %o", path, mode, PrivateFileMode)
+	}
+	dir, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := dir.Mode().Perm(); mode != PrivateDirMode {
+		t.Errorf("%s: mode %o, want %o", filepath.Dir(path), mode, PrivateDirMode)
+	}
+}
+
+func skipOnWindows(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("Windows doesn't use Unix modes")
+	}
+}
+
+// Every writer leaves its file owner-only, even when the data directory or
+// the file was created looser by an older version or by hand.
+func TestWritersArePrivate(t *testing.T) {
+	skipOnWindows(t)
+	useTempDir(t)
+	dir := DataDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name  string
+		path  string
+		write func() error
+	}{
+		{"config", ConfigPath(), func() error { return SetProxyPort(8123) }},
+		{"proxy lock", proxyLockPath(), func() error { return WriteProxyLock(8123) }},
+		{"start error", startErrorPath(), func() error { RecordStartError(errors.New("port in use")); return nil }},
+		{"private file", filepath.Join(dir, "cache", "tokens.json"), func() error {
+			return WritePrivateFile(filepath.Join(dir, "cache", "tokens.json"), []byte("{}"))
+		}},
+	} {
+		if err := tc.write(); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		assertPrivate(t, tc.path)
+		os.Chmod(dir, 0755)
+	}
+}
+
+// Appending to an existing file tightens it before writing.
+func TestOpenPrivateAppend(t *testing.T) {
+	skipOnWindows(t)
+	useTempDir(t)
+	path := filepath.Join(DataDir(), "trades.jsonl")
+	if err := os.MkdirAll(DataDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenPrivateAppend(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{}\n")
+	f.Close()
+	assertPrivate(t, path)
+	if data, _ := os.ReadFile(path); string(data) != "{}\n{}\n" {
+		t.Errorf("contents = %q, want both lines", data)
+	}
+}
+
+// The audit finds every loose file and directory under the data directory,
+// and fixing them leaves nothing to report.
+func TestAuditPermissions(t *testing.T) {
+	skipOnWindows(t)
+	useTempDir(t)
+	if err := WritePrivateFile(ConfigPath(), []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+	if issues, err := AuditPermissions(); err != nil || len(issues) != 0 {
+		t.Fatalf("private data dir: issues %v, err %v", issues, err)
+	}
+
+	logs := filepath.Join(DataDir(), "logs")
+	loose := filepath.Join(logs, "proxy.log")
+	if err := os.MkdirAll(logs, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(loose, []byte("log"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(ConfigPath(), 0640)
+
+	issues, err := AuditPermissions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]PermissionIssue{
+		ConfigPath(): {Path: ConfigPath(), Mode: 0640, Want: PrivateFileMode},
+		logs:         {Path: logs, Mode: 0755, Want: PrivateDirMode},
+		loose:        {Path: loose, Mode: 0644, Want: PrivateFileMode},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("issues = %v, want %d", issues, len(want))
+	}
+	for _, is := range issues {
+		if is != want[is.Path] {
+			t.Errorf("issue %+v, want %+v", is, want[is.Path])
+		}
+	}
+
+	if err := FixPermissions(issues); err != nil {
+		t.Fatal(err)
+	}
+	if issues, err := AuditPermissions(); err != nil || len(issues) != 0 {
+		t.Errorf("after fix: issues %v, err %v", issues, err)
+	}
+	assertPrivate(t, loose)
+}
+
+// A data directory that doesn't exist yet has nothing to report.
+func TestAuditPermissionsMissingDir(t *testing.T) {
+	useTempDir(t)
+	if issues, err := AuditPermissions(); err != nil || len(issues) != 0 {
+		t.Errorf("issues %v, err %v", issues, err)
+	}
+}
@@ -0,0 +1,255 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+// secretStore is the OS keyring, behind an interface so it can be swapped
+// for an in-memory one.
+type secretStore interface {
+	Get(service, account string) (string, error)
+	Set(service, account, value string) error
+	Delete(service, account string) error
+}
+
+// osKeyring is the system keyring: Keychain, Secret Service or Credential
+// Manager.
+type osKeyring struct{}
+
+func (osKeyring) Get(service, account string) (string, error) {
+	return keyring.Get(service, account)
+}
+
+func (osKeyring) Set(service, account, value string) error {
+	return keyring.Set(service, account, value)
+}
+
+func (osKeyring) Delete(service, account string) error {
+	return keyring.Delete(service, account)
+}
+
+// keyringBackend is the keyring secrets are read from and written to.
+var keyringBackend secretStore = osKeyring{}
+
+// keyringRecheck is how long an unusable keyring is left alone before it
+// is tried again. A locked keychain shouldn't push a long-running proxy
+// into env-var mode for good.
+const keyringRecheck = 5 * time.Minute
+
+// keyringState remembers whether the keyring answered. Nothing touches the
+// keyring until a secret is first needed, so commands that never read one
+// (`boba --version`, `boba config get`) never trigger an unlock prompt.
+var keyringState struct {
+	mu      sync.Mutex
+	checked time.Time // zero until the first probe
+	ok      bool
+	warned  bool
+}
+
+// keyringOK is true when the OS keyring backend is usable. The probe reads
+// an entry that doesn't exist rather than writing one.
+func keyringOK() bool {
+	keyringState.mu.Lock()
+	defer keyringState.mu.Unlock()
+	if !keyringState.checked.IsZero() && (keyringState.ok || time.Since(keyringState.checked) < keyringRecheck) {
+		return keyringState.ok
+	}
+
+	const probe = "boba-cli-keyring-probe"
+	err := keyringRetry(func() error {
+		_, err := keyringBackend.Get(KeychainService, probe)
+		return err
+	})
+	keyringState.checked = time.Now()
+	keyringState.ok = err == nil || errors.Is(err, keyring.ErrNotFound)
+	if !keyringState.ok && !keyringState.warned {
+		keyringState.warned = true
+		fmt.Fprintln(os.Stderr, "warning: system keyring unavailable, falling back to environment variables (BOBA_AGENT_SECRET, BOBA_ACCESS_TOKEN, etc.)")
+	}
+	return keyringState.ok
+}
+
+// KeyringAvailable reports whether secrets are kept in the OS keyring rather
+// than read from environment variables.
+func KeyringAvailable() bool {
+	return keyringOK()
+}
+
+// Windows Credential Manager fails transiently for a little while after
+// login, so keyring calls there are retried before giving up.
+const (
+	keyringAttempts   = 3
+	keyringRetryDelay = 300 * time.Millisecond
+)
+
+// keyringRetry runs fn, retrying on Windows when it fails with anything but
+// a definitive answer (not found, too big).
+func keyringRetry(fn func() error) error {
+	var err error
+	for i := 0; i < keyringAttempts; i++ {
+		err = fn()
+		if err == nil || errors.Is(err, keyring.ErrNotFound) || errors.Is(err, keyring.ErrSetDataTooBig) {
+			return err
+		}
+		if runtime.GOOS != "windows" {
+			return err
+		}
+		time.Sleep(keyringRetryDelay * time.Duration(i+1))
+	}
+	return err
+}
+
+// Secrets read from the keyring are kept in memory for secretCacheTTL, so
+// one command, or one proxied request, reads each at most once. Reload
+// drops them. Session tokens are never cached: bridges re-read them to
+// pick up a restarted proxy's new token.
+const secretCacheTTL = time.Minute
+
+var cachedAccounts = map[string]bool{
+	KeychainSecret:       true,
+	KeychainAccessToken:  true,
+	KeychainRefreshToken: true,
+}
+
+type cachedSecret struct {
+	value   string
+	fetched time.Time
+}
+
+var (
+	secretCacheMu sync.Mutex
+	secretCache   = make(map[string]cachedSecret)
+)
+
+func cacheSecret(account, value string) {
+	if !cachedAccounts[account] {
+		return
+	}
+	secretCacheMu.Lock()
+	defer secretCacheMu.Unlock()
+	secretCache[account] = cachedSecret{value: value, fetched: time.Now()}
+}
+
+func cachedSecretValue(account string) (string, bool) {
+	secretCacheMu.Lock()
+	defer secretCacheMu.Unlock()
+	c, ok := secretCache[account]
+	if !ok || time.Since(c.fetched) > secretCacheTTL {
+		return "", false
+	}
+	return c.value, true
+}
+
+func forgetSecret(account string) {
+	secretCacheMu.Lock()
+	defer secretCacheMu.Unlock()
+	delete(secretCache, account)
+}
+
+// dropSecretCache forgets every cached secret, so the next read goes to the
+// keyring again.
+func dropSecretCache() {
+	secretCacheMu.Lock()
+	defer secretCacheMu.Unlock()
+	clear(secretCache)
+}
+
+// Credential Manager caps a secret at 2560 bytes, which long tokens can
+// exceed. Oversized values are split across the account and a second
+// "<account>.2" entry, and the first half is prefixed with splitMarker so
+// reads know to join them.
+const (
+	splitMarker   = "boba-split:"
+	splitSuffix   = ".2"
+	keyringMaxLen = 2560
+)
+
+func keyringGet(account string) (string, error) {
+	var val string
+	err := keyringRetry(func() (err error) {
+		val, err = keyringBackend.Get(KeychainService, account)
+		return err
+	})
+	if err != nil || !strings.HasPrefix(val, splitMarker) {
+		return val, err
+	}
+	var rest string
+	err = keyringRetry(func() (err error) {
+		rest, err = keyringBackend.Get(KeychainService, account+splitSuffix)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s is split across two keyring entries and the second is unreadable: %w", account, err)
+	}
+	return strings.TrimPrefix(val, splitMarker) + rest, nil
+}
+
+func keyringSet(account, value string) error {
+	err := keyringRetry(func() error { return keyringBackend.Set(KeychainService, account, value) })
+	if !errors.Is(err, keyring.ErrSetDataTooBig) {
+		if err == nil {
+			_ = keyringBackend.Delete(KeychainService, account+splitSuffix)
+		}
+		return err
+	}
+
+	half := len(value) / 2
+	first, second := splitMarker+value[:half], value[half:]
+	if len(first) > keyringMaxLen || len(second) > keyringMaxLen {
+		return fmt.Errorf("%s is %d bytes, more than the system keyring can hold; set %s instead", account, len(value), envVarMap[account])
+	}
+	if err := keyringRetry(func() error { return keyringBackend.Set(KeychainService, account+splitSuffix, second) }); err != nil {
+		return fmt.Errorf("%s is too large for one keyring entry and splitting it failed: %w", account, err)
+	}
+	if err := keyringRetry(func() error { return keyringBackend.Set(KeychainService, account, first) }); err != nil {
+		return fmt.Errorf("%s is too large for one keyring entry and splitting it failed: %w", account, err)
+	}
+	return nil
+}
+
+func secureGet(account string) (string, error) {
+	if val, ok := cachedSecretValue(account); ok {
+		return val, nil
+	}
+	if keyringOK() {
+		if val, err := keyringGet(account); err == nil {
+			cacheSecret(account, val)
+			return val, nil
+		}
+	}
+	if envVar, ok := envVarMap[account]; ok {
+		if val := os.Getenv(envVar); val != "" {
+			return val, nil
+		}
+	}
+	return "", fmt.Errorf("%s not found in keyring or environment", account)
+}
+
+func secureSet(account, value string) error {
+	forgetSecret(account)
+	if keyringOK() {
+		if err := keyringSet(account, value); err != nil {
+			return err
+		}
+		cacheSecret(account, value)
+		return nil
+	}
+	// No keyring available — user manages secrets via env vars.
+	return nil
+}
+
+func secureDelete(account string) {
+	forgetSecret(account)
+	if keyringOK() {
+		_ = keyringRetry(func() error { return keyringBackend.Delete(KeychainService, account) })
+		_ = keyringBackend.Delete(KeychainService, account+splitSuffix)
+	}
+}
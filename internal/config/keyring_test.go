@@ -0,0 +1,186 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+)
+
+// fakeKeyring is an in-memory secretStore that counts reads and can be
+// locked like a keychain waiting for its password.
+type fakeKeyring struct {
+	mu      sync.Mutex
+	secrets map[string]string
+	gets    int
+	locked  bool
+	maxLen  int // 0 for no limit
+}
+
+var errLocked = errors.New("keychain is locked")
+
+func (k *fakeKeyring) Get(service, account string) (string, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.gets++
+	if k.locked {
+		return "", errLocked
+	}
+	v, ok := k.secrets[service+"/"+account]
+	if !ok {
+		return "", keyring.ErrNotFound
+	}
+	return v, nil
+}
+
+func (k *fakeKeyring) Set(service, account, value string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.locked {
+		return errLocked
+	}
+	if k.maxLen > 0 && len(value) > k.maxLen {
+		return keyring.ErrSetDataTooBig
+	}
+	k.secrets[service+"/"+account] = value
+	return nil
+}
+
+func (k *fakeKeyring) Delete(service, account string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.secrets, service+"/"+account)
+	return nil
+}
+
+func (k *fakeKeyring) reads() int {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.gets
+}
+
+// useFakeKeyring swaps in a fake keyring that hasn't been probed yet.
+func useFakeKeyring(t *testing.T) *fakeKeyring {
+	t.Helper()
+	useTempDir(t)
+	k := &fakeKeyring{secrets: make(map[string]string)}
+	prev := keyringBackend
+	keyringBackend = k
+	resetKeyringState := func() {
+		keyringState.mu.Lock()
+		keyringState.checked, keyringState.ok, keyringState.warned = time.Time{}, false, true
+		keyringState.mu.Unlock()
+	}
+	resetKeyringState()
+	t.Cleanup(func() {
+		keyringBackend = prev
+		resetKeyringState()
+		dropSecretCache()
+	})
+	return k
+}
+
+// Commands that never need a secret never touch the keyring.
+func TestKeyringLazy(t *testing.T) {
+	k := useFakeKeyring(t)
+	if err := SetProxyPort(4567); err != nil {
+		t.Fatal(err)
+	}
+	Reload()
+	GetProxyPort()
+	GetMCPURL()
+	if n := k.reads(); n != 0 {
+		t.Errorf("keyring read %d times without a secret being needed", n)
+	}
+}
+
+// A secret is read from the keyring once and then served from memory,
+// until Reload drops it.
+func TestSecretCache(t *testing.T) {
+	k := useFakeKeyring(t)
+	k.secrets[KeychainService+"/"+KeychainAccessToken] = "access"
+	k.secrets[KeychainService+"/"+KeychainRefreshToken] = "refresh"
+
+	for range 5 {
+		if v, err := secureGet(KeychainAccessToken); err != nil || v != "access" {
+			t.Fatalf("secureGet = %q, %v", v, err)
+		}
+		secureGet(KeychainRefreshToken)
+	}
+	// One probe and one read per account.
+	if n := k.reads(); n != 3 {
+		t.Errorf("keyring read %d times, want 3", n)
+	}
+
+	Reload()
+	secureGet(KeychainAccessToken)
+	if n := k.reads(); n != 4 {
+		t.Errorf("after Reload: keyring read %d times, want 4", n)
+	}
+
+	// A write replaces the cached value.
+	if err := secureSet(KeychainAccessToken, "rotated"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := secureGet(KeychainAccessToken); v != "rotated" {
+		t.Errorf("after secureSet: %q", v)
+	}
+}
+
+// A locked keyring falls back to the environment, and is tried again
+// once keyringRecheck has passed rather than never.
+func TestKeyringRecheck(t *testing.T) {
+	k := useFakeKeyring(t)
+	k.secrets[KeychainService+"/"+KeychainAccessToken] = "from-keyring"
+	t.Setenv(envVarMap[KeychainAccessToken], "from-env")
+	k.locked = true
+
+	if v, _ := secureGet(KeychainAccessToken); v != "from-env" {
+		t.Errorf("locked keyring: %q, want the env var", v)
+	}
+	k.mu.Lock()
+	k.locked = false
+	k.mu.Unlock()
+	if v, _ := secureGet(KeychainAccessToken); v != "from-env" {
+		t.Errorf("keyring retried before keyringRecheck: %q", v)
+	}
+
+	keyringState.mu.Lock()
+	keyringState.checked = time.Now().Add(-keyringRecheck - time.Second)
+	keyringState.mu.Unlock()
+	if v, _ := secureGet(KeychainAccessToken); v != "from-keyring" {
+		t.Errorf("after keyringRecheck: %q, want the keyring value", v)
+	}
+}
+
+// A value too long for one entry is split across two and read back whole.
+func TestKeyringSplit(t *testing.T) {
+	k := useFakeKeyring(t)
+	k.maxLen = keyringMaxLen
+	long := strings.Repeat("t", 4000)
+	if err := secureSet(KeychainAccessToken, long); err != nil {
+		t.Fatal(err)
+	}
+	if len(k.secrets) != 2 {
+		t.Errorf("stored in %d entries, want 2", len(k.secrets))
+	}
+	dropSecretCache()
+	if v, err := secureGet(KeychainAccessToken); err != nil || v != long {
+		t.Errorf("read back %d bytes, %v", len(v), err)
+	}
+
+	// A short value clears the old second half.
+	if err := secureSet(KeychainAccessToken, "short"); err != nil {
+		t.Fatal(err)
+	}
+	if len(k.secrets) != 1 {
+		t.Errorf("stale split entry kept: %v", len(k.secrets))
+	}
+
+	if err := secureSet(KeychainAccessToken, strings.Repeat("t", 3*keyringMaxLen)); err == nil {
+		t.Error("a value too long for two entries was accepted")
+	}
+}
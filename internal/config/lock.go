@@ -0,0 +1,140 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// Several boba processes can share the config file: the proxy refreshing
+// tokens while `boba login` runs in another terminal, for instance. Writes
+// are serialized with an advisory lock on a sibling lock file, and every
+// write bumps a generation counter. A process whose copy is older than the
+// file re-reads it and keeps the other process's changes, overwriting only
+// the fields it changed itself.
+
+var (
+	// saveMu serializes saves within this process; the file lock covers
+	// other processes.
+	saveMu sync.Mutex
+	// loaded holds the fields as this process last read or wrote them, to
+	// tell which fields it has changed since.
+	loaded map[string]json.RawMessage
+)
+
+// withConfigLock runs fn while holding the config file lock.
+func withConfigLock(fn func() error) error {
+	saveMu.Lock()
+	defer saveMu.Unlock()
+
+	if err := EnsurePrivateDir(DataDir()); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(configPath+".lock", os.O_CREATE|os.O_RDWR, PrivateFileMode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := lockFile(f); err != nil {
+		return err
+	}
+	defer unlockFile(f)
+	return fn()
+}
+
+// configFields splits a config into its top-level JSON fields, leaving out
+// the generation counter.
+func configFields(c *BobaConfig) map[string]json.RawMessage {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil
+	}
+	var fields map[string]json.RawMessage
+	_ = json.Unmarshal(data, &fields)
+	delete(fields, "generation")
+	return fields
+}
+
+// readDisk returns the config currently on disk, or nil if there is none.
+func readDisk() *BobaConfig {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil
+	}
+	var disk BobaConfig
+	if err := json.Unmarshal(data, &disk); err != nil {
+		return nil
+	}
+	return &disk
+}
+
+// mergeConfig returns disk with the fields ours changed relative to base
+// applied on top.
+func mergeConfig(base map[string]json.RawMessage, ours, disk *BobaConfig) *BobaConfig {
+	merged := configFields(disk)
+	if merged == nil {
+		merged = make(map[string]json.RawMessage)
+	}
+	mine := configFields(ours)
+	for k, v := range mine {
+		if !bytes.Equal(v, base[k]) {
+			merged[k] = v
+		}
+	}
+	for k := range base {
+		if _, ok := mine[k]; !ok {
+			// Cleared here (omitempty dropped it), so clear it there too.
+			delete(merged, k)
+		}
+	}
+
+	data, _ := json.Marshal(merged)
+	var out BobaConfig
+	if err := json.Unmarshal(data, &out); err != nil {
+		return ours
+	}
+	applyDefaults(&out)
+	return &out
+}
+
+// writeConfig writes cfg, first merging in anything another process saved
+// since this one loaded. With replace set the file is overwritten as is.
+// The caller holds the config lock.
+func writeConfig(replace bool) error {
+	gen := cfg.Generation
+	if disk := readDisk(); disk != nil && disk.Generation > gen {
+		if !replace {
+			merged := mergeConfig(loaded, cfg, disk)
+			tokensMu.Lock()
+			*cfg = *merged
+			tokensMu.Unlock()
+		}
+		gen = disk.Generation
+	}
+	cfg.Generation = gen + 1
+
+	tokensMu.RLock()
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	tokensMu.RUnlock()
+	if err != nil {
+		return err
+	}
+	if err := WritePrivateFile(configPath, data); err != nil {
+		return err
+	}
+	loaded = configFields(cfg)
+	return nil
+}
+
+// Reload drops the cached config and secrets and reads the config from
+// disk again, picking up changes made by other boba processes.
+func Reload() *BobaConfig {
+	dropSecretCache()
+	saveMu.Lock()
+	cfgMu.Lock()
+	cfg = nil
+	cfgMu.Unlock()
+	saveMu.Unlock()
+	return Load()
+}
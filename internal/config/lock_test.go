@@ -0,0 +1,157 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+// writerRounds is how many saves each writer process makes.
+const writerRounds = 40
+
+// TestConfigWriterProcess is one of the writers TestInterleavedSaves starts
+// in a process of its own; it is skipped when run directly.
+func TestConfigWriterProcess(t *testing.T) {
+	dir := os.Getenv("BOBA_TEST_WRITER_DIR")
+	if dir == "" {
+		t.Skip("run by TestInterleavedSaves")
+	}
+	keyring.MockInit()
+	UseDir(dir)
+	for i := range writerRounds {
+		var err error
+		if os.Getenv("BOBA_TEST_WRITER_FIELD") == "port" {
+			err = SetProxyPort(4000 + i)
+		} else {
+			err = SetLogHistory(100 + i)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Two processes, started from two goroutines, save different fields at the
+// same time without losing each other's changes.
+func TestInterleavedSaves(t *testing.T) {
+	if testing.Short() {
+		t.Skip("starts processes")
+	}
+	dir := t.TempDir()
+	var wg sync.WaitGroup
+	for _, field := range []string{"port", "history"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cmd := exec.Command(os.Args[0], "-test.run=^TestConfigWriterProcess$")
+			cmd.Env = append(os.Environ(),
+				"BOBA_TEST_WRITER_DIR="+dir,
+				"BOBA_TEST_WRITER_FIELD="+field,
+				"HOME="+t.TempDir())
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("%s writer: %v\n%s", field, err, out)
+			}
+		}()
+	}
+	wg.Wait()
+
+	UseDir(dir)
+	disk := readDisk()
+	if disk == nil {
+		t.Fatal("no config on disk")
+	}
+	if disk.ProxyPort != 4000+writerRounds-1 || disk.LogHistory != 100+writerRounds-1 {
+		t.Errorf("disk has port %d and log history %d, want %d and %d", disk.ProxyPort, disk.LogHistory, 4000+writerRounds-1, 100+writerRounds-1)
+	}
+	if disk.Generation != 2*writerRounds {
+		t.Errorf("generation %d, want %d", disk.Generation, 2*writerRounds)
+	}
+}
+
+// A save from a process holding an older generation keeps the fields the
+// other process wrote since.
+func TestSaveMergesNewerGeneration(t *testing.T) {
+	useTempDir(t)
+	if err := SetProxyPort(4100); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process raises the log history and bumps the generation.
+	other := *readDisk()
+	other.LogHistory = 900
+	other.Generation++
+	data, _ := json.Marshal(other)
+	if err := os.WriteFile(ConfigPath(), data, PrivateFileMode); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetTheme("mono"); err != nil {
+		t.Fatal(err)
+	}
+	disk := readDisk()
+	if disk.LogHistory != 900 || disk.Theme != "mono" || disk.ProxyPort != 4100 {
+		t.Errorf("merged config lost a field: log history %d, theme %q, port %d", disk.LogHistory, disk.Theme, disk.ProxyPort)
+	}
+	if disk.Generation != other.Generation+1 {
+		t.Errorf("generation %d, want %d", disk.Generation, other.Generation+1)
+	}
+}
+
+// A reader that doesn't take the lock never sees a half-written file.
+func TestWritePrivateFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	big := func(i int) []byte {
+		data, _ := json.Marshal(map[string]string{"n": fmt.Sprint(i), "pad": strings.Repeat("x", 256<<10)})
+		return data
+	}
+	if err := WritePrivateFile(path, big(0)); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 50; i++ {
+			if err := WritePrivateFile(path, big(i)); err != nil {
+				t.Error(err)
+				break
+			}
+		}
+		close(done)
+	}()
+	for reads := 0; ; reads++ {
+		select {
+		case <-done:
+			wg.Wait()
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != PrivateFileMode {
+				t.Errorf("mode %v, want %v", info.Mode().Perm(), PrivateFileMode)
+			}
+			if left, _ := filepath.Glob(filepath.Join(dir, ".config.json.tmp-*")); len(left) > 0 {
+				t.Errorf("temporary files left behind: %v", left)
+			}
+			return
+		default:
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !json.Valid(data) {
+			t.Fatalf("read %d saw a partial file of %d bytes", reads, len(data))
+		}
+	}
+}
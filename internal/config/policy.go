@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Tool policy modes.
+const (
+	// ToolPolicyAll allows every tool not on the deny list.
+	ToolPolicyAll = "all"
+	// ToolPolicyReadOnly also blocks tools that trade or change orders.
+	ToolPolicyReadOnly = "read-only"
+	// ToolPolicyCustom allows only the tools on the allow list.
+	ToolPolicyCustom = "custom"
+)
+
+// ToolPolicy limits which tools agents can call through the proxy. The deny
+// list applies in every mode.
+type ToolPolicy struct {
+	Mode  string   `json:"mode"`
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+}
+
+// String describes the policy in a few words, e.g. "read-only, 2 denied".
+func (p ToolPolicy) String() string {
+	s := p.Mode
+	if p.Mode == ToolPolicyCustom {
+		s += fmt.Sprintf(" (%d allowed)", len(p.Allow))
+	}
+	if len(p.Deny) > 0 {
+		s += fmt.Sprintf(", %d denied", len(p.Deny))
+	}
+	return s
+}
+
+// Restricted reports whether the policy blocks anything.
+func (p ToolPolicy) Restricted() bool {
+	return p.Mode != ToolPolicyAll || len(p.Deny) > 0
+}
+
+// GetToolPolicy returns the configured tool policy, "all" by default.
+func GetToolPolicy() ToolPolicy {
+	var p ToolPolicy
+	if c := Load().ToolPolicy; c != nil {
+		p = *c
+	}
+	if p.Mode == "" {
+		p.Mode = ToolPolicyAll
+	}
+	return p
+}
+
+// SetToolPolicy validates and saves the tool policy. Tool names are
+// trimmed and deduplicated.
+func SetToolPolicy(p ToolPolicy) error {
+	switch p.Mode {
+	case ToolPolicyAll, ToolPolicyReadOnly, ToolPolicyCustom:
+	default:
+		return fmt.Errorf("unknown tool policy mode %q (use all, read-only or custom)", p.Mode)
+	}
+	p.Allow = toolNames(p.Allow)
+	p.Deny = toolNames(p.Deny)
+	if p.Mode == ToolPolicyCustom && len(p.Allow) == 0 {
+		return fmt.Errorf("a custom tool policy needs at least one allowed tool")
+	}
+
+	c := Load()
+	if p.Mode == ToolPolicyAll && len(p.Deny) == 0 {
+		c.ToolPolicy = nil
+	} else {
+		c.ToolPolicy = &p
+	}
+	return save()
+}
+
+// toolNames trims, drops empty names and deduplicates, keeping order.
+func toolNames(names []string) []string {
+	var out []string
+	for _, n := range names {
+		n = strings.TrimSpace(n)
+		if n != "" && !slices.Contains(out, n) {
+			out = append(out, n)
+		}
+	}
+	return out
+}
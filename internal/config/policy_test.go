@@ -0,0 +1,43 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSetToolPolicy(t *testing.T) {
+	useTempDir(t)
+	if p := GetToolPolicy(); p.Mode != ToolPolicyAll || p.Restricted() {
+		t.Errorf("default policy = %+v", p)
+	}
+
+	for _, bad := range []ToolPolicy{
+		{Mode: "none"},
+		{Mode: ToolPolicyCustom},
+		{Mode: ToolPolicyCustom, Allow: []string{" ", ""}},
+	} {
+		if err := SetToolPolicy(bad); err == nil {
+			t.Errorf("%+v accepted", bad)
+		}
+	}
+
+	if err := SetToolPolicy(ToolPolicy{Mode: ToolPolicyReadOnly, Deny: []string{" execute_swap", "execute_swap", "", "search_tokens"}}); err != nil {
+		t.Fatal(err)
+	}
+	Reload()
+	p := GetToolPolicy()
+	if p.Mode != ToolPolicyReadOnly || !slices.Equal(p.Deny, []string{"execute_swap", "search_tokens"}) {
+		t.Errorf("saved policy = %+v", p)
+	}
+	if got := p.String(); got != "read-only, 2 denied" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// Back to "all" with nothing denied drops the setting.
+	if err := SetToolPolicy(ToolPolicy{Mode: ToolPolicyAll}); err != nil {
+		t.Fatal(err)
+	}
+	if Load().ToolPolicy != nil {
+		t.Errorf("unrestricted policy saved: %+v", Load().ToolPolicy)
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ProxyLock records the running proxy: its process, the port it is bound
+// to, which differs from the configured one when that was taken, and when it
+// started.
+type ProxyLock struct {
+	PID       int       `json:"pid"`
+	Port      int       `json:"port"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+func proxyLockPath() string {
+	return filepath.Join(DataDir(), "proxy.lock")
+}
+
+// WriteProxyLock records this process as the running proxy on port.
+func WriteProxyLock(port int) error {
+	data, err := json.Marshal(ProxyLock{PID: os.Getpid(), Port: port, StartedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+	// Older versions recorded only the port.
+	os.Remove(filepath.Join(DataDir(), "proxy.port"))
+	return WritePrivateFile(proxyLockPath(), append(data, '\n'))
+}
+
+// ReleaseProxyLock removes the lock of a stopped proxy, unless another proxy
+// has since replaced it with its own.
+func ReleaseProxyLock(port int) {
+	if lock, ok := readProxyLock(); ok && lock.PID == os.Getpid() && lock.Port == port {
+		os.Remove(proxyLockPath())
+	}
+}
+
+// ReadProxyLock returns the lock of the running proxy. A lock left behind by
+// a process that has since died is stale; it is removed and ok is false.
+// The proxy may still be starting up or shutting down, so callers that need
+// it to answer should also check /health.
+func ReadProxyLock() (lock ProxyLock, ok bool) {
+	lock, ok = readProxyLock()
+	if !ok {
+		return ProxyLock{}, false
+	}
+	if !processAlive(lock.PID) {
+		os.Remove(proxyLockPath())
+		return ProxyLock{}, false
+	}
+	return lock, true
+}
+
+func readProxyLock() (ProxyLock, bool) {
+	data, err := os.ReadFile(proxyLockPath())
+	if err != nil {
+		return ProxyLock{}, false
+	}
+	var lock ProxyLock
+	if err := json.Unmarshal(data, &lock); err != nil || lock.PID <= 0 || lock.Port <= 0 {
+		return ProxyLock{}, false
+	}
+	return lock, true
+}
+
+// ActiveProxyPort returns the port of the running proxy, or the configured
+// port when no proxy holds the lock.
+func ActiveProxyPort() int {
+	if lock, ok := ReadProxyLock(); ok {
+		return lock.Port
+	}
+	return GetProxyPort()
+}
+
+func startErrorPath() string {
+	return filepath.Join(DataDir(), "last-start-error.txt")
+}
+
+// RecordStartError records why 'boba start' failed, for 'boba launch' to
+// report from the window it can't see into.
+func RecordStartError(err error) {
+	_ = WritePrivateFile(startErrorPath(), []byte(err.Error()+"\n"))
+}
+
+// ClearStartError removes the error left by an earlier failed start.
+func ClearStartError() {
+	os.Remove(startErrorPath())
+}
+
+// StartErrorSince returns the error recorded by a start that failed at or
+// after t. Older errors belong to earlier attempts and are ignored.
+func StartErrorSince(t time.Time) (string, bool) {
+	info, err := os.Stat(startErrorPath())
+	// Some filesystems keep modification times to the second only.
+	if err != nil || info.ModTime().Before(t.Truncate(time.Second)) {
+		return "", false
+	}
+	data, err := os.ReadFile(startErrorPath())
+	if err != nil {
+		return "", false
+	}
+	msg := strings.TrimSpace(string(data))
+	return msg, msg != ""
+}
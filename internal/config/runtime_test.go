@@ -0,0 +1,75 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// deadPID returns the pid of a process that has exited.
+func deadPID(t *testing.T) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^$")
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	return cmd.Process.Pid
+}
+
+// A lock left by a proxy that died is reported stale, removed, and taken
+// over by the next proxy.
+func TestStaleProxyLock(t *testing.T) {
+	useTempDir(t)
+	if err := SetProxyPort(3456); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := json.Marshal(ProxyLock{PID: deadPID(t), Port: 4999, StartedAt: time.Now().Add(-time.Hour)})
+	if err := WritePrivateFile(proxyLockPath(), data); err != nil {
+		t.Fatal(err)
+	}
+
+	if lock, ok := ReadProxyLock(); ok {
+		t.Fatalf("lock of a dead process reported live: %+v", lock)
+	}
+	if _, err := os.Stat(proxyLockPath()); !os.IsNotExist(err) {
+		t.Error("stale lock not removed")
+	}
+	if port := ActiveProxyPort(); port != 3456 {
+		t.Errorf("ActiveProxyPort() = %d, want the configured 3456", port)
+	}
+
+	if err := WriteProxyLock(5001); err != nil {
+		t.Fatal(err)
+	}
+	lock, ok := ReadProxyLock()
+	if !ok || lock.PID != os.Getpid() || lock.Port != 5001 {
+		t.Fatalf("takeover lock = %+v, %v", lock, ok)
+	}
+	if port := ActiveProxyPort(); port != 5001 {
+		t.Errorf("ActiveProxyPort() = %d, want 5001", port)
+	}
+
+	// Releasing for another port leaves the lock alone; our own is removed.
+	ReleaseProxyLock(5002)
+	if _, ok := ReadProxyLock(); !ok {
+		t.Error("released a lock for another port")
+	}
+	ReleaseProxyLock(5001)
+	if _, ok := ReadProxyLock(); ok {
+		t.Error("lock kept after release")
+	}
+}
+
+func TestProxyLockMalformed(t *testing.T) {
+	useTempDir(t)
+	for _, body := range []string{"", "3456\n", `{"pid":0,"port":3456}`, `{"pid":12,"port":0}`} {
+		if err := WritePrivateFile(proxyLockPath(), []byte(body)); err != nil {
+			t.Fatal(err)
+		}
+		if lock, ok := ReadProxyLock(); ok {
+			t.Errorf("%q read as a lock: %+v", body, lock)
+		}
+	}
+}
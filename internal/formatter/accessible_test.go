@@ -0,0 +1,81 @@
+package formatter
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// golden compares got with testdata/name.golden, or rewrites the file with
+// -update.
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from %s:\n%s", name, path, got)
+	}
+}
+
+func TestAccessibleGolden(t *testing.T) {
+	Accessible, TermWidth = true, 100
+	defer func() { Accessible = false }()
+
+	for _, tc := range []struct {
+		name   string
+		format func(map[string]any) string
+		data   map[string]any
+	}{
+		{"portfolio", FormatPortfolio, map[string]any{
+			"total_value_usd":    1750.0,
+			"position_value_usd": 1250.0,
+			"native_value_usd":   500.0,
+			"positions": []any{
+				map[string]any{"symbol": "WIF", "value_usd": "1000", "price_usd": 2.5, "pnl_percent": 12.5, "chain_name": "Solana"},
+				map[string]any{"symbol": "BONK", "value_usd": 250.0, "price_usd": 0.00002, "pnl_percent": -8.25, "chain_name": "Solana"},
+			},
+			"native_balances": []any{
+				map[string]any{"symbol": "SOL", "chain_name": "Solana", "balance": 3.2, "balance_usd": 500.0},
+			},
+		}},
+		{"orders", FormatOrders, map[string]any{
+			"total": 2.0,
+			"orders": []any{
+				map[string]any{"id": "ord_1a2b3c4d5e", "status": "active", "side": "buy", "trigger_price": 1.25,
+					"input_token": "USDC", "output_token": "WIF", "input_amount": 100.0},
+				map[string]any{"id": "ord_9f8e7d6c5b", "status": "filled", "side": "sell", "trigger_price": 3.0,
+					"input_token": "WIF", "output_token": "USDC", "input_amount": 40.0},
+			},
+		}},
+		{"audit", FormatAuditToken, map[string]any{
+			"token":      "EKpQGSJtjMFqKZ9KQanSqYXRcF8fBopzLHYxdM65zcjm",
+			"risk_level": "medium",
+			"security":   map[string]any{"is_honeypot": false, "is_mintable": true, "freezable": false},
+			"holder_analysis": map[string]any{
+				"top10_holders_percent": 34.5,
+				"dev_holding_percent":   4.0,
+			},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			out := tc.format(tc.data)
+			if strings.ContainsAny(out, "╭╮╰╯│─━█░") {
+				t.Errorf("accessible output has box drawing or bar characters:\n%s", out)
+			}
+			golden(t, "accessible_"+tc.name, out)
+		})
+	}
+}
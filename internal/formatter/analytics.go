@@ -99,7 +99,7 @@ func FormatSearchWallets(data map[string]any) string {
 
 	var colAddr, colProfit, colWinRate, colVol, colSwaps int
 	if compact {
-		colAddr = 12
+		colAddr = 14
 		colProfit = 12
 		colWinRate = 10
 		colSwaps = 8
@@ -155,7 +155,7 @@ func FormatSearchWallets(data map[string]any) string {
 		}
 
 		rowParts := []string{
-			lipgloss.NewStyle().Width(colAddr).Foreground(ui.ColorBright).Render(TruncateAddress(address)),
+			lipgloss.NewStyle().Width(colAddr).Render(addressLink(lipgloss.NewStyle().Foreground(ui.ColorBright).Render(TruncateAddress(address)), chainOf(wallet, data), address)),
 			profitStyle.Render(FormatUSD(profit)),
 			lipgloss.NewStyle().Width(colWinRate).Render(fmt.Sprintf("%.1f%%", winRate)),
 		}
@@ -335,7 +335,7 @@ func FormatHolders(data map[string]any) string {
 	var colAddr, colBought, colSold, colBuys, colSells, colProfit, colProfPct int
 	if compact {
 		// Compact: drop Buys/Sells columns
-		colAddr = 12
+		colAddr = 14
 		colBought = 12
 		colSold = 12
 		colProfit = 12
@@ -400,7 +400,7 @@ func FormatHolders(data map[string]any) string {
 		}
 
 		rowParts := []string{
-			lipgloss.NewStyle().Width(colAddr).Foreground(ui.ColorBright).Render(TruncateAddress(address)),
+			lipgloss.NewStyle().Width(colAddr).Render(addressLink(lipgloss.NewStyle().Foreground(ui.ColorBright).Render(TruncateAddress(address)), chainOf(holder, data), address)),
 			lipgloss.NewStyle().Width(colBought).Render(FormatUSD(bought)),
 			lipgloss.NewStyle().Width(colSold).Render(FormatUSD(sold)),
 		}
@@ -431,7 +431,7 @@ func FormatHolders(data map[string]any) string {
 
 	titleText := "TOP HOLDERS"
 	if token != "" {
-		titleText += " — " + TruncateAddress(token)
+		titleText += " — " + tokenTitle(token)
 	}
 	title := ui.TitleStyle.Render(titleText)
 
@@ -500,6 +500,9 @@ func FormatDeployerTokens(data map[string]any) string {
 		price := getFloat(token, "price_usd")
 		mcap := getFloat(token, "market_cap")
 		address := getString(token, "address")
+		if symbol == "" {
+			symbol, _ = resolveSymbol(address)
+		}
 
 		rowParts := []string{
 			lipgloss.NewStyle().Width(colSymbol).Foreground(ui.ColorBright).Render(symbol),
@@ -507,7 +510,7 @@ func FormatDeployerTokens(data map[string]any) string {
 			lipgloss.NewStyle().Width(colMcap).Render(FormatUSD(mcap)),
 		}
 		if !compact {
-			rowParts = append(rowParts, lipgloss.NewStyle().Width(colAddr).Render(ui.DimStyle.Render(TruncateAddress(address))))
+			rowParts = append(rowParts, lipgloss.NewStyle().Width(colAddr).Render(tokenLink(ui.DimStyle.Render(TruncateAddress(address)), chainOf(token, data), address)))
 		}
 		_ = address // used in full mode
 		row := lipgloss.JoinHorizontal(lipgloss.Top, rowParts...)
@@ -584,7 +587,7 @@ func FormatDeployerActivity(data map[string]any) string {
 		titleText += " — " + TruncateAddress(deployer)
 	}
 	if token != "" {
-		titleText += " [" + TruncateAddress(token) + "]"
+		titleText += " [" + tokenTitle(token) + "]"
 	}
 	title := ui.TitleStyle.Render(titleText)
 
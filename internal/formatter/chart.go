@@ -5,6 +5,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/guptarohit/asciigraph"
@@ -34,10 +35,11 @@ func FormatPnLChart(data map[string]any) string {
 	}
 
 	// Plot the chart
-	plot := asciigraph.Plot(values,
-		asciigraph.Height(10),
-		asciigraph.Caption("P&L"),
-	)
+	caption := "P&L"
+	if tf := chartTimeframe(data); tf != "" {
+		caption += " — " + tf
+	}
+	plot := plotChart(values, 10, caption)
 
 	// Sparkline of last 30 points
 	sparkValues := values
@@ -76,17 +78,14 @@ func FormatPnLChart(data map[string]any) string {
 
 	title := ui.TitleStyle.Render("P&L CHART")
 
-	content := lipgloss.JoinVertical(lipgloss.Left,
-		title,
-		"",
-		plot,
-		"",
-		sparkline,
-		"",
-		summary,
-	)
-
-	return ui.BoxBorder.Render(content)
+	lines := []string{title, "", plot}
+	points, _ := data["data_points"].([]any)
+	if span := chartSpan(points); span != "" {
+		lines = append(lines, ui.DimStyle.Render(span))
+	}
+	lines = append(lines, "", sparkline, "", summary)
+
+	return ui.BoxBorder.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
 }
 
 // FormatTokenChart renders a token price chart from OHLC/candle data with
@@ -150,7 +149,11 @@ func FormatTokenChart(data map[string]any) string {
 	}
 
 	// Plot the chart
-	plot := asciigraph.Plot(values, asciigraph.Height(12))
+	caption := ""
+	if tf := chartTimeframe(data); tf != "" {
+		caption = "Price — " + tf
+	}
+	plot := plotChart(values, 12, caption)
 
 	// Sparkline
 	sparkValues := values
@@ -190,17 +193,116 @@ func FormatTokenChart(data map[string]any) string {
 
 	title := ui.TitleStyle.Render("PRICE CHART")
 
-	content := lipgloss.JoinVertical(lipgloss.Left,
-		title,
-		"",
-		plot,
-		"",
-		sparkline,
-		"",
-		summary,
-	)
-
-	return ui.BoxBorder.Render(content)
+	lines := []string{title, "", plot}
+	if span := chartSpan(rawCandles); span != "" {
+		lines = append(lines, ui.DimStyle.Render(span))
+	}
+	lines = append(lines, "", sparkline, "", summary)
+
+	return ui.BoxBorder.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
+
+// chartTimeframe returns the timeframe a chart response covers, e.g. "1W",
+// or "" when it doesn't say.
+func chartTimeframe(data map[string]any) string {
+	for _, k := range []string{"timeframe", "period", "interval", "resolution"} {
+		if tf := getString(data, k); tf != "" {
+			return tf
+		}
+	}
+	return ""
+}
+
+// plotChart plots values at the given height, no wider than the content
+// width. Longer series are down-sampled to fit rather than cut short.
+func plotChart(values []float64, height int, caption string) string {
+	opts := []asciigraph.Option{asciigraph.Height(height)}
+	if caption != "" {
+		opts = append(opts, asciigraph.Caption(caption))
+	}
+	avail := contentWidth()
+	points := downsample(values, avail)
+	plot := asciigraph.Plot(points, opts...)
+	// The axis labels take part of the width; shrink the series by as much.
+	if over := lipgloss.Width(plot) - avail; over > 0 {
+		points = downsample(values, max(len(points)-over, 2))
+		plot = asciigraph.Plot(points, opts...)
+	}
+	return plot
+}
+
+// downsample reduces values to at most width points. Each bucket of the
+// series keeps both its lowest and highest value, in order, so spikes
+// survive where averaging or truncating would lose them.
+func downsample(values []float64, width int) []float64 {
+	if len(values) <= width || width < 2 {
+		return values
+	}
+	buckets := width / 2
+	out := make([]float64, 0, width)
+	for b := range buckets {
+		lo, hi := b*len(values)/buckets, (b+1)*len(values)/buckets
+		minI, maxI := lo, lo
+		for i := lo; i < hi; i++ {
+			if values[i] < values[minI] {
+				minI = i
+			}
+			if values[i] > values[maxI] {
+				maxI = i
+			}
+		}
+		switch {
+		case minI == maxI:
+			out = append(out, values[minI])
+		case minI < maxI:
+			out = append(out, values[minI], values[maxI])
+		default:
+			out = append(out, values[maxI], values[minI])
+		}
+	}
+	return out
+}
+
+// chartSpan returns the time range a chart's points cover, e.g.
+// "2024-05-01 → 2024-05-08", or "" when they carry no timestamps. Ranges
+// shorter than two days include the time of day.
+func chartSpan(points []any) string {
+	var first, last time.Time
+	for _, p := range points {
+		t, ok := pointTime(p)
+		if !ok {
+			continue
+		}
+		if first.IsZero() {
+			first = t
+		}
+		last = t
+	}
+	if first.IsZero() || !last.After(first) {
+		return ""
+	}
+	layout := "2006-01-02"
+	if last.Sub(first) < 48*time.Hour {
+		layout = "2006-01-02 15:04"
+	}
+	return first.Local().Format(layout) + " → " + last.Local().Format(layout)
+}
+
+// pointTime returns the timestamp of a chart point: an object with a
+// timestamp field, or a [time, open, high, low, close, volume] candle.
+func pointTime(p any) (time.Time, bool) {
+	switch v := p.(type) {
+	case map[string]any:
+		if t, ok := historyTime(v); ok {
+			return t, true
+		}
+		return historyTime(map[string]any{"time": v["t"]})
+	case []any:
+		if len(v) >= 6 {
+			return historyTime(map[string]any{"time": v[0]})
+		}
+	}
+	return time.Time{}, false
 }
 
 // smartFormatPrice formats a price with appropriate decimal places based on
@@ -0,0 +1,271 @@
+package formatter
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// cachedBalances holds the most recent token balances known to the CLI, keyed
+// by BalanceKey: a chain and a lowercased token address or symbol. The TUI
+// refreshes it whenever a portfolio poll completes so formatters can compare
+// order commitments against what the agent actually holds without making
+// extra API calls.
+var (
+	cachedBalancesMu sync.RWMutex
+	cachedBalances   map[string]float64
+)
+
+// BalanceKey returns the cached balance key of a token, given by address or
+// symbol, on a chain given by name, slug or alias. The chain is part of the
+// key because the same symbol on two chains is two different holdings.
+func BalanceKey(chain, token string) string {
+	return chainKey(chain) + ":" + strings.ToLower(token)
+}
+
+// chainKey returns the slug of a known chain, given by name, slug, alias or
+// numeric ID, or the lowercased input.
+func chainKey(chain string) string {
+	if c, ok := config.LookupChain(chain); ok {
+		return c.Slug
+	}
+	if id, err := strconv.Atoi(chain); err == nil {
+		if c, ok := config.LookupChainID(id); ok {
+			return c.Slug
+		}
+	}
+	return strings.ToLower(strings.TrimSpace(chain))
+}
+
+// SetCachedBalances replaces the cached balance table, keyed by BalanceKey.
+func SetCachedBalances(balances map[string]float64) {
+	normalized := make(map[string]float64, len(balances))
+	for k, v := range balances {
+		if k == "" {
+			continue
+		}
+		normalized[strings.ToLower(k)] = v
+	}
+	cachedBalancesMu.Lock()
+	cachedBalances = normalized
+	cachedBalancesMu.Unlock()
+}
+
+// cachedBalance looks up a token balance on a chain by address first, then by
+// symbol. Without a chain the token matches only when a single chain holds
+// it, since a balance on another chain can't fund the order.
+func cachedBalance(chain, address, symbol string) (float64, bool) {
+	cachedBalancesMu.RLock()
+	defer cachedBalancesMu.RUnlock()
+	if cachedBalances == nil {
+		return 0, false
+	}
+	for _, token := range []string{address, symbol} {
+		if token == "" {
+			continue
+		}
+		if chain != "" {
+			if v, ok := cachedBalances[BalanceKey(chain, token)]; ok {
+				return v, true
+			}
+			continue
+		}
+		suffix := ":" + strings.ToLower(token)
+		var found float64
+		matches := 0
+		for k, v := range cachedBalances {
+			if strings.HasSuffix(k, suffix) {
+				found = v
+				matches++
+			}
+		}
+		if matches == 1 {
+			return found, true
+		}
+	}
+	return 0, false
+}
+
+// Commitment is the remaining capital a group of active DCA/TWAP orders will
+// still consume from a single input token on one chain.
+type Commitment struct {
+	Chain       string
+	InputToken  string
+	Symbol      string
+	Remaining   float64
+	OrderCount  int
+	PausedCount int
+}
+
+// AggregateCommitments sums the remaining committed amount of every active or
+// paused DCA/TWAP order, grouped by chain and input token. Remaining capital
+// is computed from remaining intervals × amount_per_interval (or remaining
+// slices × amount_per_slice), falling back to total_amount minus the executed amount
+// when the per-interval fields are missing. Finished orders are skipped.
+// Results are sorted by remaining amount, largest first.
+func AggregateCommitments(orders []any) []Commitment {
+	groups := make(map[string]*Commitment)
+	var keys []string
+
+	for _, o := range orders {
+		order, ok := o.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		status := strings.ToLower(getString(order, "status"))
+		if !isCommittedStatus(status) {
+			continue
+		}
+
+		remaining, ok := remainingCommitment(order)
+		if !ok || remaining <= 0 {
+			continue
+		}
+
+		chain := pickString(order, "chain", "chain_name", "chain_id")
+		token := getString(order, "input_token")
+		symbol := orderInputSymbol(order)
+		key := token
+		if key == "" {
+			key = symbol
+		}
+		key = BalanceKey(chain, key)
+
+		c, exists := groups[key]
+		if !exists {
+			c = &Commitment{Chain: chain, InputToken: token, Symbol: symbol}
+			groups[key] = c
+			keys = append(keys, key)
+		}
+		if c.Symbol == "" {
+			c.Symbol = symbol
+		}
+		c.Remaining += remaining
+		c.OrderCount++
+		if status == "paused" {
+			c.PausedCount++
+		}
+	}
+
+	result := make([]Commitment, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, *groups[k])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Remaining > result[j].Remaining
+	})
+	return result
+}
+
+// isCommittedStatus reports whether an order in the given status will still
+// draw capital. Paused orders count because they can be resumed at any time.
+func isCommittedStatus(status string) bool {
+	switch status {
+	case "completed", "filled", "executed", "cancelled", "canceled", "expired", "failed":
+		return false
+	}
+	return true
+}
+
+// remainingCommitment returns how much of the input token an order still has
+// to spend. The second return value is false when the order carries no
+// DCA/TWAP sizing fields at all.
+func remainingCommitment(order map[string]any) (float64, bool) {
+	if perInterval := getFloat(order, "amount_per_interval"); perInterval > 0 {
+		if total := getFloat(order, "total_intervals"); total > 0 {
+			done := firstFloat(order, "executed_intervals", "intervals_executed", "completed_intervals")
+			return clampPositive(total-done) * perInterval, true
+		}
+	}
+
+	if perSlice := getFloat(order, "amount_per_slice"); perSlice > 0 {
+		if total := getFloat(order, "total_slices"); total > 0 {
+			done := firstFloat(order, "executed_slices", "slices_executed", "completed_slices")
+			return clampPositive(total-done) * perSlice, true
+		}
+	}
+
+	if total := getFloat(order, "total_amount"); total > 0 {
+		executed := firstFloat(order, "executed_amount", "amount_executed", "filled_amount")
+		return clampPositive(total - executed), true
+	}
+
+	return 0, false
+}
+
+// orderInputSymbol returns the display symbol of an order's input token, or a
+// truncated address when the backend did not include one.
+func orderInputSymbol(order map[string]any) string {
+	for _, key := range []string{"input_symbol", "input_token_symbol", "from_symbol"} {
+		if s := getString(order, key); s != "" {
+			return s
+		}
+	}
+	addr := getString(order, "input_token")
+	if sym, ok := resolveSymbol(addr); ok {
+		return sym
+	}
+	return TruncateAddress(addr)
+}
+
+// firstFloat returns the first non-zero float found under any of the keys.
+func firstFloat(m map[string]any, keys ...string) float64 {
+	for _, k := range keys {
+		if v := getFloat(m, k); v != 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+func clampPositive(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
+
+// FormatCommitments renders the committed-capital footer shown under DCA and
+// TWAP order tables. Returns "" when no active order commits any capital.
+func FormatCommitments(orders []any) string {
+	commitments := AggregateCommitments(orders)
+	if len(commitments) == 0 {
+		return ""
+	}
+
+	title := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true).Render("Committed Capital")
+	symStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true).Width(12)
+
+	lines := []string{title}
+	for _, c := range commitments {
+		orderWord := "orders"
+		if c.OrderCount == 1 {
+			orderWord = "order"
+		}
+		meta := fmt.Sprintf("%d %s", c.OrderCount, orderWord)
+		if c.PausedCount > 0 {
+			meta += fmt.Sprintf(", %d paused", c.PausedCount)
+		}
+
+		line := symStyle.Render(c.Symbol) + FormatNumber(c.Remaining) + "  " + ui.DimStyle.Render("("+meta+")")
+
+		if available, ok := cachedBalance(c.Chain, c.InputToken, c.Symbol); ok {
+			availStr := "avail " + FormatNumber(available)
+			if c.Remaining > available {
+				line += "  " + ui.ErrorStyle.Bold(true).Render(availStr+" — exceeds balance")
+			} else {
+				line += "  " + ui.DimStyle.Render(availStr)
+			}
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
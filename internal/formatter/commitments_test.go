@@ -0,0 +1,96 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAggregateCommitments(t *testing.T) {
+	orders := []any{
+		// 6 of 10 intervals left at 5 each: 30.
+		map[string]any{"status": "active", "chain": "solana", "input_token": "USDCsol", "input_symbol": "USDC",
+			"amount_per_interval": 5.0, "total_intervals": 10.0, "executed_intervals": 4.0},
+		// Paused, nothing run yet: 3 × 10.
+		map[string]any{"status": "paused", "chain": "Solana", "input_token": "USDCsol", "input_symbol": "USDC",
+			"amount_per_interval": "10", "total_intervals": 3.0},
+		// TWAP slices, 2 of 4 left at 25: 50.
+		map[string]any{"status": "running", "chain": "base", "input_token": "USDCbase", "input_symbol": "USDC",
+			"amount_per_slice": 25.0, "total_slices": 4.0, "executed_slices": 2.0},
+		// No per-interval fields: 1.5 - 0.5.
+		map[string]any{"status": "active", "chain_id": 1399811149, "input_symbol": "SOL",
+			"total_amount": 1.5, "executed_amount": 0.5},
+		// Finished, or with nothing left to spend.
+		map[string]any{"status": "completed", "chain": "solana", "input_token": "USDCsol", "total_amount": 100.0},
+		map[string]any{"status": "cancelled", "chain": "base", "input_token": "USDCbase", "total_amount": 100.0},
+		map[string]any{"status": "active", "chain": "base", "input_token": "USDCbase", "total_amount": 5.0, "executed_amount": 5.0},
+		map[string]any{"status": "active", "chain": "base", "input_token": "USDCbase"},
+	}
+
+	got := AggregateCommitments(orders)
+	// Largest first; the two Solana orders share a group.
+	want := []Commitment{
+		{Chain: "solana", InputToken: "USDCsol", Symbol: "USDC", Remaining: 60, OrderCount: 2, PausedCount: 1},
+		{Chain: "base", InputToken: "USDCbase", Symbol: "USDC", Remaining: 50, OrderCount: 1},
+		{Chain: "1399811149", Symbol: "SOL", Remaining: 1, OrderCount: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d groups, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("group %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// The same symbol on two chains is two groups, each compared against the
+// balance on its own chain.
+func TestCommitmentsBalancePerChain(t *testing.T) {
+	defer SetCachedBalances(nil)
+	SetCachedBalances(map[string]float64{
+		BalanceKey("Solana", "USDC"): 100,
+		BalanceKey("Base", "USDC"):   10,
+	})
+	orders := []any{
+		map[string]any{"status": "active", "chain": "sol", "input_symbol": "USDC", "total_amount": 50.0},
+		map[string]any{"status": "active", "chain": "base", "input_symbol": "USDC", "total_amount": 40.0},
+	}
+	out := FormatCommitments(orders)
+	lines := strings.Split(out, "\n")
+	if len(lines) != 3 {
+		t.Fatalf("want a title and two groups:\n%s", out)
+	}
+	if !strings.Contains(lines[1], "avail 100") || strings.Contains(lines[1], "exceeds") {
+		t.Errorf("Solana group: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "avail 10") || !strings.Contains(lines[2], "exceeds balance") {
+		t.Errorf("Base group: %q", lines[2])
+	}
+}
+
+func TestCachedBalanceLookup(t *testing.T) {
+	defer SetCachedBalances(nil)
+	SetCachedBalances(map[string]float64{
+		BalanceKey("Solana", "EPjFWdd5"): 7,
+		BalanceKey("Solana", "USDC"):     7,
+		BalanceKey("Base", "USDC"):       3,
+		BalanceKey("Base", "DEGEN"):      900,
+	})
+	for _, tc := range []struct {
+		chain, address, symbol string
+		want                   float64
+		ok                     bool
+	}{
+		{"solana", "epjfwdd5", "", 7, true},
+		{"8453", "", "usdc", 3, true},
+		{"eth", "", "USDC", 0, false},
+		// Without a chain only a symbol held on one chain matches.
+		{"", "", "DEGEN", 900, true},
+		{"", "", "USDC", 0, false},
+	} {
+		got, ok := cachedBalance(tc.chain, tc.address, tc.symbol)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("cachedBalance(%q, %q, %q) = %v, %v, want %v, %v", tc.chain, tc.address, tc.symbol, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package formatter
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Currency is a fiat currency values can be displayed in. The backend
+// reports values in USD; they are converted at Rate.
+type Currency struct {
+	Code    string
+	Symbol  string
+	After   bool   // the symbol follows the amount, as in "12,50 €"
+	Decimal string // decimal separator
+	Group   string // thousands separator
+	Rate    float64
+}
+
+// Currencies are the supported display currencies. Their rates are
+// fallbacks, used until a live rate has been fetched.
+var Currencies = map[string]Currency{
+	"USD": {Code: "USD", Symbol: "$", Decimal: ".", Group: ",", Rate: 1},
+	"EUR": {Code: "EUR", Symbol: "€", After: true, Decimal: ",", Group: ".", Rate: 0.92},
+	"GBP": {Code: "GBP", Symbol: "£", Decimal: ".", Group: ",", Rate: 0.79},
+	"JPY": {Code: "JPY", Symbol: "¥", Decimal: ".", Group: ",", Rate: 150},
+	"CAD": {Code: "CAD", Symbol: "C$", Decimal: ".", Group: ",", Rate: 1.36},
+	"AUD": {Code: "AUD", Symbol: "A$", Decimal: ".", Group: ",", Rate: 1.52},
+	"CHF": {Code: "CHF", Symbol: "CHF ", Decimal: ".", Group: "'", Rate: 0.88},
+	"INR": {Code: "INR", Symbol: "₹", Decimal: ".", Group: ",", Rate: 83},
+}
+
+// LookupCurrency returns the display currency with the given ISO code.
+func LookupCurrency(code string) (Currency, bool) {
+	c, ok := Currencies[strings.ToUpper(strings.TrimSpace(code))]
+	return c, ok
+}
+
+// CurrencyCodes lists the supported currency codes, sorted.
+func CurrencyCodes() []string {
+	codes := make([]string, 0, len(Currencies))
+	for code := range Currencies {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// display is the currency FormatUSD shows values in. The proxy dashboard
+// replaces it once a live rate arrives, while panels are rendering.
+var display atomic.Pointer[Currency]
+
+// DisplayCurrency returns the currency values are shown in, USD by default.
+func DisplayCurrency() Currency {
+	if c := display.Load(); c != nil {
+		return *c
+	}
+	return Currencies["USD"]
+}
+
+// SetDisplayCurrency switches every FormatUSD and FormatPnLUSD to c.
+func SetDisplayCurrency(c Currency) {
+	display.Store(&c)
+}
+
+// Amount formats a USD value in c with fixed decimals and thousands
+// separators, unstyled.
+func (c Currency) Amount(usd float64, decimals int) string {
+	return c.Format(usd*c.Rate, decimals)
+}
+
+// Format formats an amount already in c with fixed decimals and thousands
+// separators, unstyled.
+func (c Currency) Format(amount float64, decimals int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	return sign + c.place(c.number(math.Abs(amount), decimals, ""))
+}
+
+// Compact formats a USD value in c the way FormatUSD does: with a B, M or K
+// suffix for large values and more decimals for small ones, unstyled.
+func (c Currency) Compact(usd float64) string {
+	v := usd * c.Rate
+	abs := math.Abs(v)
+	sign := ""
+	if v < 0 {
+		sign = "-"
+	}
+
+	var num string
+	switch {
+	case abs >= 1_000_000_000:
+		num = c.number(abs/1_000_000_000, 1, "B")
+	case abs >= 1_000_000:
+		num = c.number(abs/1_000_000, 1, "M")
+	case abs >= 1_000:
+		num = c.number(abs/1_000, 1, "K")
+	case abs >= 1:
+		num = c.number(abs, 2, "")
+	case abs >= 0.01:
+		num = c.number(abs, 4, "")
+	default:
+		num = c.number(abs, 8, "")
+	}
+	return sign + c.place(num)
+}
+
+// number renders a non-negative amount with c's separators, followed by
+// suffix.
+func (c Currency) number(abs float64, decimals int, suffix string) string {
+	s := strconv.FormatFloat(abs, 'f', decimals, 64)
+	whole, frac, _ := strings.Cut(s, ".")
+	whole = groupDigits(whole, c.Group)
+	if frac != "" {
+		whole += c.Decimal + frac
+	}
+	return whole + suffix
+}
+
+// place puts c's symbol before or after a formatted number.
+func (c Currency) place(num string) string {
+	if c.After {
+		return num + " " + c.Symbol
+	}
+	return c.Symbol + num
+}
+
+// groupDigits inserts sep between every three digits of a whole number.
+func groupDigits(digits, sep string) string {
+	if len(digits) <= 3 || sep == "" {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
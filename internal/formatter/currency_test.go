@@ -0,0 +1,111 @@
+package formatter
+
+import "testing"
+
+// useCurrency shows values in the currency with code for the rest of the
+// test.
+func useCurrency(t *testing.T, code string) {
+	t.Helper()
+	c, ok := LookupCurrency(code)
+	if !ok {
+		t.Fatalf("no currency %s", code)
+	}
+	SetDisplayCurrency(c)
+	t.Cleanup(func() { SetDisplayCurrency(Currencies["USD"]) })
+}
+
+// Symbol placement and separators follow each currency's convention.
+func TestCurrencyFormat(t *testing.T) {
+	for _, tc := range []struct {
+		code     string
+		amount   float64
+		decimals int
+		want     string
+	}{
+		{"USD", 1234567.891, 2, "$1,234,567.89"},
+		{"USD", -12.5, 2, "-$12.50"},
+		{"EUR", 1234567.891, 2, "1.234.567,89 €"},
+		{"EUR", -12.5, 2, "-12,50 €"},
+		{"EUR", 999, 2, "999,00 €"},
+		{"EUR", 1000, 0, "1.000 €"},
+		{"GBP", 1234.5, 2, "£1,234.50"},
+		{"CHF", 1234.5, 2, "CHF 1'234.50"},
+		{"JPY", 1500000, 0, "¥1,500,000"},
+	} {
+		c, _ := LookupCurrency(tc.code)
+		if got := c.Format(tc.amount, tc.decimals); got != tc.want {
+			t.Errorf("%s Format(%v, %d) = %q, want %q", tc.code, tc.amount, tc.decimals, got, tc.want)
+		}
+	}
+}
+
+// Values arrive in USD and are converted at the currency's rate.
+func TestCurrencyConversion(t *testing.T) {
+	eur := Currency{Code: "EUR", Symbol: "€", After: true, Decimal: ",", Group: ".", Rate: 0.5}
+	for _, tc := range []struct {
+		usd  float64
+		want string
+	}{
+		{2_000_000_000, "1,0B €"},
+		{5_000_000, "2,5M €"},
+		{3000, "1,5K €"},
+		{2469.12, "1,2K €"},
+		{10, "5,00 €"},
+		{0.1, "0,0500 €"},
+		{0.00001, "0,00000500 €"},
+		{-3000, "-1,5K €"},
+	} {
+		if got := eur.Compact(tc.usd); got != tc.want {
+			t.Errorf("Compact(%v) = %q, want %q", tc.usd, got, tc.want)
+		}
+	}
+	if got := eur.Amount(2469.12, 2); got != "1.234,56 €" {
+		t.Errorf("Amount = %q", got)
+	}
+}
+
+// FormatUSD and FormatPnLUSD follow the display currency.
+func TestDisplayCurrency(t *testing.T) {
+	if got := FormatUSD(1234.5); got != "$1.2K" {
+		t.Errorf("USD: FormatUSD = %q", got)
+	}
+	useCurrency(t, "eur")
+	eur := Currencies["EUR"].Rate
+	if got, want := FormatUSD(1000/eur), "1,0K €"; got != want {
+		t.Errorf("EUR: FormatUSD = %q, want %q", got, want)
+	}
+	if got, want := FormatPnLUSD(12.5/eur), "+12,50 €"; got != want {
+		t.Errorf("EUR: FormatPnLUSD gain = %q, want %q", got, want)
+	}
+	if got, want := FormatPnLUSD(-20000/eur), "-20,0K €"; got != want {
+		t.Errorf("EUR: FormatPnLUSD loss = %q, want %q", got, want)
+	}
+}
+
+func TestLookupCurrency(t *testing.T) {
+	if c, ok := LookupCurrency(" eur "); !ok || c.Code != "EUR" {
+		t.Errorf("LookupCurrency(eur) = %+v, %v", c, ok)
+	}
+	if _, ok := LookupCurrency("XYZ"); ok {
+		t.Error("unknown currency found")
+	}
+	codes := CurrencyCodes()
+	if len(codes) != len(Currencies) || codes[0] != "AUD" {
+		t.Errorf("CurrencyCodes = %v", codes)
+	}
+}
+
+func TestGroupDigits(t *testing.T) {
+	for _, tc := range []struct{ in, sep, want string }{
+		{"1", ",", "1"},
+		{"123", ",", "123"},
+		{"1234", ",", "1,234"},
+		{"123456", ".", "123.456"},
+		{"1234567", "'", "1'234'567"},
+		{"1234567", "", "1234567"},
+	} {
+		if got := groupDigits(tc.in, tc.sep); got != tc.want {
+			t.Errorf("groupDigits(%q, %q) = %q, want %q", tc.in, tc.sep, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// stableTokens lists well-known stablecoin addresses (lowercased) whose
+// amounts can be shown as dollars.
+var stableTokens = map[string]bool{
+	"epjfwdd5aufqssqem2qn1xzybapc8g4weggkzwytdt1v": true, // USDC (Solana)
+	"es9vmfrzacermjfrf4h2fyd4kconky11mcce8benwnyb": true, // USDT (Solana)
+	"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48":   true, // USDC (Ethereum)
+	"0xdac17f958d2ee523a2206206994597c13d831ec7":   true, // USDT (Ethereum)
+	"0x833589fcd6edb6e08f4c7c32d4f71b54bda02913":   true, // USDC (Base)
+	"0xaf88d065e77c8cc2239327c5edb3a432268e5831":   true, // USDC (Arbitrum)
+	"0x55d398326f99059ff775485246999027b3197955":   true, // USDT (BSC)
+}
+
+// stableSymbols lists stablecoin tickers used when no address matches.
+var stableSymbols = map[string]bool{
+	"USDC": true, "USDT": true, "DAI": true, "USDE": true, "PYUSD": true, "FDUSD": true,
+}
+
+// nativeTokens lists native-asset placeholder addresses (lowercased).
+var nativeTokens = map[string]bool{
+	"so11111111111111111111111111111111111111112": true,
+	"0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee":  true,
+	"0x0000000000000000000000000000000000000000":  true,
+}
+
+// nativeSymbols lists native gas-token tickers.
+var nativeSymbols = map[string]bool{
+	"SOL": true, "ETH": true, "WETH": true, "WSOL": true, "BNB": true, "MATIC": true, "POL": true, "AVAX": true,
+	"APE": true, "HYPE": true, "MON": true,
+}
+
+// orderSide returns the lowercased order side. Newer backend responses use
+// order_side or direction instead of side.
+func orderSide(order map[string]any) string {
+	for _, key := range []string{"side", "order_side", "direction"} {
+		if s := getString(order, key); s != "" {
+			return strings.ToLower(s)
+		}
+	}
+	return ""
+}
+
+// isStableInput reports whether an order's input token is a USD stablecoin.
+func isStableInput(order map[string]any) bool {
+	if stableTokens[strings.ToLower(getString(order, "input_token"))] {
+		return true
+	}
+	return stableSymbols[strings.ToUpper(orderInputSymbol(order))]
+}
+
+// isNativeInput reports whether an order's input token is a chain's native asset.
+func isNativeInput(order map[string]any) bool {
+	if nativeTokens[strings.ToLower(getString(order, "input_token"))] {
+		return true
+	}
+	return nativeSymbols[strings.ToUpper(orderInputSymbol(order))]
+}
+
+// sellSymbol returns the symbol of the token being sold. Sell orders may only
+// carry the traded token's symbol in the generic symbol field.
+func sellSymbol(order map[string]any) string {
+	for _, key := range []string{"input_symbol", "input_token_symbol", "from_symbol", "symbol", "token_symbol"} {
+		if s := getString(order, key); s != "" {
+			return s
+		}
+	}
+	addr := getString(order, "input_token")
+	if sym, ok := resolveSymbol(addr); ok {
+		return sym
+	}
+	return TruncateAddress(addr)
+}
+
+// FormatTokenAmount formats a token quantity with precision suited to its
+// magnitude, so small balances are not rounded away.
+func FormatTokenAmount(value float64) string {
+	abs := math.Abs(value)
+	switch {
+	case abs >= 1_000:
+		return FormatNumber(value)
+	case abs >= 1:
+		return trimZeros(fmt.Sprintf("%.4f", value))
+	case abs == 0:
+		return "0"
+	default:
+		return trimZeros(fmt.Sprintf("%.8f", value))
+	}
+}
+
+func trimZeros(s string) string {
+	if !strings.Contains(s, ".") {
+		return s
+	}
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// formatOrderAmount renders an order's input amount in its real denomination.
+// Sell-side amounts are quantities of the token being sold; buy-side amounts
+// are dollars when the input is a stablecoin and native units otherwise.
+func formatOrderAmount(order map[string]any, amount float64) string {
+	if orderSide(order) == "sell" {
+		return FormatTokenAmount(amount) + " " + sellSymbol(order)
+	}
+	if isStableInput(order) {
+		return FormatUSD(amount)
+	}
+	if isNativeInput(order) {
+		return FormatTokenAmount(amount) + " " + nativeInputSymbol(order)
+	}
+	return FormatNumber(amount)
+}
+
+// nativeInputSymbol returns the ticker of a native input token. When the
+// backend omitted the symbol it is the native token of the order's chain, or
+// a guess from the placeholder address when the chain is unknown.
+func nativeInputSymbol(order map[string]any) string {
+	for _, key := range []string{"input_symbol", "input_token_symbol", "from_symbol"} {
+		if s := getString(order, key); s != "" {
+			return s
+		}
+	}
+	if c, ok := orderChain(order); ok {
+		return c.Native
+	}
+	if strings.HasPrefix(getString(order, "input_token"), "So111") {
+		return "SOL"
+	}
+	return "ETH"
+}
+
+// orderChain looks up the chain of an order, given by name, slug, alias or
+// numeric ID. A decoded chain_id is a float, which getString would print in
+// exponent form for Solana's.
+func orderChain(order map[string]any) (config.Chain, bool) {
+	if id, ok := order["chain_id"].(float64); ok {
+		return config.LookupChainID(int(id))
+	}
+	return config.LookupChain(chainKey(pickString(order, "chain", "chain_name", "chain_id")))
+}
+
+// estimateOrderValue converts an order amount to USD when price data allows.
+// Sell orders use the trigger price; stablecoin inputs are already dollars;
+// otherwise an explicit input token USD price is used if the backend sent one.
+func estimateOrderValue(order map[string]any, amount float64) (float64, bool) {
+	if amount <= 0 {
+		return 0, false
+	}
+	if orderSide(order) == "sell" {
+		if trigger := getFloat(order, "trigger_price"); trigger > 0 {
+			return amount * trigger, true
+		}
+	} else if isStableInput(order) {
+		return amount, true
+	}
+	if price := firstFloat(order, "input_price_usd", "input_token_price_usd"); price > 0 {
+		return amount * price, true
+	}
+	return 0, false
+}
+
+// orderAmount returns the amount shown for an order in tables: the limit
+// order input amount, or the total amount for DCA/TWAP orders.
+func orderAmount(order map[string]any) float64 {
+	if v := getFloat(order, "input_amount"); v > 0 {
+		return v
+	}
+	return getFloat(order, "total_amount")
+}
+
+// OrderAmount renders an order's amount in its own denomination, as the
+// orders table shows it.
+func OrderAmount(order map[string]any) string {
+	return formatOrderAmount(order, orderAmount(order))
+}
+
+// OrderValueUSD estimates an order's value in USD. The second return value is
+// false when the order carries no usable price data.
+func OrderValueUSD(order map[string]any) (float64, bool) {
+	return estimateOrderValue(order, orderAmount(order))
+}
@@ -0,0 +1,62 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	usdcSolana   = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+	wifSolana    = "EKpQGSJtjMFqKZ9KQanSqYXRcF8fBopzLHYxdM65zcjm"
+	evmNative    = "0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE"
+	buyLimitJSON = `{"order_type":"limit","side":"buy","chain":"solana","input_token":"` + usdcSolana + `",
+		"output_token":"` + wifSolana + `","input_amount":500,"trigger_price":2.1}`
+	sellLimitJSON = `{"order_type":"limit","side":"sell","chain":"solana","input_token":"` + wifSolana + `",
+		"symbol":"WIF","output_token":"` + usdcSolana + `","input_amount":1250.5,"trigger_price":2.5}`
+	dcaSellJSON = `{"order_type":"dca","direction":"SELL","chain_id":1399811149,"input_token":"` + wifSolana + `",
+		"input_symbol":"WIF","total_amount":300,"amount_per_interval":25,"input_price_usd":2.4}`
+)
+
+func TestOrderDenomination(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		amount string
+		est    float64 // 0 when the value can't be told
+	}{
+		{"buy limit", buyLimitJSON, "$500.00", 500},
+		{"sell limit", sellLimitJSON, "1.3K WIF", 3126.25},
+		{"dca sell", dcaSellJSON, "300 WIF", 720},
+		{"native by chain", `{"side":"buy","chain":"bsc","input_token":"` + evmNative + `","input_amount":0.5}`, "0.5 BNB", 0},
+		{"native by chain ID", `{"side":"buy","chain_id":43114,"input_token":"` + evmNative + `","input_amount":2,"input_price_usd":30}`, "2 AVAX", 60},
+		{"native on unknown chain", `{"side":"buy","input_token":"` + evmNative + `","input_amount":1}`, "1 ETH", 0},
+		{"wrapped SOL", `{"side":"buy","input_token":"So11111111111111111111111111111111111111112","input_amount":1.5}`, "1.5 SOL", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := decode(t, tt.json)
+			if got := OrderAmount(order); got != tt.amount {
+				t.Errorf("amount = %q, want %q", got, tt.amount)
+			}
+			est, ok := OrderValueUSD(order)
+			if ok != (tt.est != 0) || est != tt.est {
+				t.Errorf("value = %v, %v, want %v", est, ok, tt.est)
+			}
+		})
+	}
+}
+
+func TestFormatOrderCreatedSell(t *testing.T) {
+	TermWidth = 120
+	defer func() { TermWidth = 80 }()
+
+	out := FormatOrderCreated(decode(t, dcaSellJSON))
+	for _, want := range []string{"300 WIF", "25 WIF", "$720.00"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("DCA sell lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "$300") {
+		t.Errorf("DCA sell shows its token amount as dollars:\n%s", out)
+	}
+}
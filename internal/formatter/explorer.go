@@ -0,0 +1,126 @@
+package formatter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// Hyperlinks renders addresses as OSC 8 links to the chain's block
+// explorer, for terminals that make them clickable. Set by the CLI when
+// stdout is a terminal; off, addresses are printed as plain text.
+var Hyperlinks = false
+
+// blockExplorer is where a chain's tokens and accounts can be looked up.
+type blockExplorer struct {
+	base    string
+	account string // path of account pages: /account/ or /address/
+}
+
+// blockExplorers are the explorers addresses link to, by chain slug.
+var blockExplorers = map[string]blockExplorer{
+	"solana":   {base: "https://solscan.io", account: "/account/"},
+	"base":     {base: "https://basescan.org", account: "/address/"},
+	"bsc":      {base: "https://bscscan.com", account: "/address/"},
+	"eth":      {base: "https://etherscan.io", account: "/address/"},
+	"arb":      {base: "https://arbiscan.io", account: "/address/"},
+	"avax":     {base: "https://snowtrace.io", account: "/address/"},
+	"apechain": {base: "https://apescan.io", account: "/address/"},
+	"hyperevm": {base: "https://hyperevmscan.io", account: "/address/"},
+	"monad":    {base: "https://monadscan.com", account: "/address/"},
+}
+
+// ExplorerTokenURL returns the explorer page of a token. chain is a chain
+// slug, name, alias or numeric chain ID, as a string or a JSON number. ok
+// is false when the chain has no known explorer.
+func ExplorerTokenURL(chain any, address string) (string, bool) {
+	e, ok := explorerFor(chain, address)
+	if !ok {
+		return "", false
+	}
+	return e.base + "/token/" + address, true
+}
+
+// ExplorerAddressURL returns the explorer page of a wallet or contract
+// address, like ExplorerTokenURL.
+func ExplorerAddressURL(chain any, address string) (string, bool) {
+	e, ok := explorerFor(chain, address)
+	if !ok {
+		return "", false
+	}
+	return e.base + e.account + address, true
+}
+
+// ExplorerTxURL returns the explorer page of a transaction, like
+// ExplorerTokenURL. A Solana signature is recognized without a chain.
+func ExplorerTxURL(chain any, hash string) (string, bool) {
+	e, ok := explorerFor(chain, hash)
+	if !ok {
+		return "", false
+	}
+	return e.base + "/tx/" + hash, true
+}
+
+// explorerFor finds the explorer of chain. Without a chain, a base58
+// address can only be on Solana; an EVM one could be on any EVM chain.
+func explorerFor(chain any, address string) (blockExplorer, bool) {
+	if address == "" {
+		return blockExplorer{}, false
+	}
+	slug := ""
+	switch c := chain.(type) {
+	case float64:
+		if ch, ok := config.LookupChainID(int(c)); ok {
+			slug = ch.Slug
+		}
+	case string:
+		if ch, ok := config.LookupChain(c); ok {
+			slug = ch.Slug
+		} else if id, err := strconv.Atoi(strings.TrimSpace(c)); err == nil {
+			if ch, ok := config.LookupChainID(id); ok {
+				slug = ch.Slug
+			}
+		}
+	}
+	if slug == "" && !strings.HasPrefix(strings.ToLower(address), "0x") {
+		slug = "solana"
+	}
+	e, ok := blockExplorers[slug]
+	return e, ok
+}
+
+// hyperlink makes text a link to url when Hyperlinks is on. It is applied
+// after styling, so the escape codes wrap the styled text.
+func hyperlink(text, url string) string {
+	if !Hyperlinks || url == "" {
+		return text
+	}
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// tokenLink links text to a token's explorer page, when its chain is known.
+func tokenLink(text string, chain any, address string) string {
+	url, _ := ExplorerTokenURL(chain, address)
+	return hyperlink(text, url)
+}
+
+// addressLink links text to an address's explorer page, when its chain is
+// known.
+func addressLink(text string, chain any, address string) string {
+	url, _ := ExplorerAddressURL(chain, address)
+	return hyperlink(text, url)
+}
+
+// chainOf returns the chain a response or one of its items is on, from the
+// keys the backend uses for it, or nil.
+func chainOf(items ...map[string]any) any {
+	for _, m := range items {
+		for _, k := range []string{"chain", "chain_id", "chainId", "network"} {
+			if v, ok := m[k]; ok && v != nil && v != "" {
+				return v
+			}
+		}
+	}
+	return nil
+}
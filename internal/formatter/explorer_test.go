@@ -0,0 +1,112 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	solMint = "So11111111111111111111111111111111111111112"
+	evmAddr = "0x4200000000000000000000000000000000000006"
+)
+
+func TestExplorerURLs(t *testing.T) {
+	for _, tc := range []struct {
+		chain   any
+		address string
+		token   string // "" for no explorer
+		account string
+	}{
+		{"solana", solMint, "https://solscan.io/token/" + solMint, "https://solscan.io/account/" + solMint},
+		{nil, solMint, "https://solscan.io/token/" + solMint, "https://solscan.io/account/" + solMint},
+		{"base", evmAddr, "https://basescan.org/token/" + evmAddr, "https://basescan.org/address/" + evmAddr},
+		{8453.0, evmAddr, "https://basescan.org/token/" + evmAddr, "https://basescan.org/address/" + evmAddr},
+		{"8453", evmAddr, "https://basescan.org/token/" + evmAddr, "https://basescan.org/address/" + evmAddr},
+		{"ethereum", evmAddr, "https://etherscan.io/token/" + evmAddr, "https://etherscan.io/address/" + evmAddr},
+		{1.0, evmAddr, "https://etherscan.io/token/" + evmAddr, "https://etherscan.io/address/" + evmAddr},
+		{"Arbitrum", evmAddr, "https://arbiscan.io/token/" + evmAddr, "https://arbiscan.io/address/" + evmAddr},
+		{"bnb", evmAddr, "https://bscscan.com/token/" + evmAddr, "https://bscscan.com/address/" + evmAddr},
+		{56.0, evmAddr, "https://bscscan.com/token/" + evmAddr, "https://bscscan.com/address/" + evmAddr},
+		{"avax", evmAddr, "https://snowtrace.io/token/" + evmAddr, "https://snowtrace.io/address/" + evmAddr},
+		{nil, evmAddr, "", ""},         // an EVM address could be on any EVM chain
+		{"dogechain", evmAddr, "", ""}, // unknown chain
+		{"base", "", "", ""},
+	} {
+		token, ok := ExplorerTokenURL(tc.chain, tc.address)
+		if token != tc.token || ok != (tc.token != "") {
+			t.Errorf("ExplorerTokenURL(%v, %q) = %q, %v; want %q", tc.chain, tc.address, token, ok, tc.token)
+		}
+		account, _ := ExplorerAddressURL(tc.chain, tc.address)
+		if account != tc.account {
+			t.Errorf("ExplorerAddressURL(%v, %q) = %q, want %q", tc.chain, tc.address, account, tc.account)
+		}
+	}
+	if got, _ := ExplorerTxURL("base", "0xabc"); got != "https://basescan.org/tx/0xabc" {
+		t.Errorf("ExplorerTxURL = %q", got)
+	}
+}
+
+// useHyperlinks sets Hyperlinks for the rest of the test.
+func useHyperlinks(t *testing.T, on bool) {
+	t.Helper()
+	prev := Hyperlinks
+	Hyperlinks = on
+	t.Cleanup(func() { Hyperlinks = prev })
+}
+
+// Addresses in tables link to their explorer page only when Hyperlinks is
+// on; otherwise they are plain text without escape codes.
+func TestTableLinks(t *testing.T) {
+	TermWidth = 120
+	trending := map[string]any{"chain": "solana", "tokens": []any{
+		map[string]any{"symbol": "WIF", "address": solMint, "price_usd": 1.5},
+	}}
+	brewing := map[string]any{"chain": "bsc", "tokens": []any{
+		map[string]any{"symbol": "CAKE", "address": evmAddr},
+	}}
+	holders := map[string]any{"chain_id": 8453.0, "holders": []any{
+		map[string]any{"address": evmAddr, "percentage": 12.5},
+	}}
+	deployer := map[string]any{"chain": "base", "tokens": []any{
+		map[string]any{"symbol": "BRETT", "address": evmAddr},
+	}}
+	for _, tc := range []struct {
+		name string
+		out  func() string
+		url  string
+	}{
+		{"trending", func() string { return FormatTrendingTokens(trending) }, "https://solscan.io/token/" + solMint},
+		{"brewing", func() string { return FormatBrewingTokens(brewing) }, "https://bscscan.com/token/" + evmAddr},
+		{"holders", func() string { return FormatHolders(holders) }, "https://basescan.org/address/" + evmAddr},
+		{"deployer", func() string { return FormatDeployerTokens(deployer) }, "https://basescan.org/token/" + evmAddr},
+	} {
+		useHyperlinks(t, false)
+		if out := tc.out(); strings.Contains(out, "\x1b]8;;") {
+			t.Errorf("%s: link without Hyperlinks:\n%q", tc.name, out)
+		}
+		useHyperlinks(t, true)
+		if out := tc.out(); !strings.Contains(out, "\x1b]8;;"+tc.url+"\x1b\\") {
+			t.Errorf("%s: no link to %s:\n%q", tc.name, tc.url, out)
+		}
+	}
+}
+
+// Token info links the truncated address and prints it in full below.
+func TestTokenInfoAddress(t *testing.T) {
+	TermWidth = 120
+	data := map[string]any{"name": "Wrapped Ether", "symbol": "WETH", "address": evmAddr, "chain_id": "8453"}
+
+	useHyperlinks(t, false)
+	out := FormatTokenInfo(data)
+	if !strings.Contains(out, TruncateAddress(evmAddr)) || !strings.Contains(out, evmAddr) {
+		t.Errorf("truncated and full address not both shown:\n%s", out)
+	}
+	if strings.Contains(out, "\x1b]8;;") {
+		t.Errorf("link without Hyperlinks:\n%q", out)
+	}
+
+	useHyperlinks(t, true)
+	if out := FormatTokenInfo(data); !strings.Contains(out, "\x1b]8;;https://basescan.org/token/"+evmAddr+"\x1b\\") {
+		t.Errorf("no explorer link:\n%q", out)
+	}
+}
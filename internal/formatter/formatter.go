@@ -1,6 +1,12 @@
 package formatter
 
-import "fmt"
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
 
 // unwrapData checks if the data map has a "data" wrapper and unwraps it.
 // Many MCP responses return { "data": { ...actual fields... } }.
@@ -30,7 +36,7 @@ func unwrapData(dataMap map[string]any) map[string]any {
 // parameter is expected to be a map[string]any parsed from JSON tool
 // output. Returns full multi-line rich formatted output (charts, tables, boxes).
 func FormatToolResult(toolName string, data any) string {
-	dataMap, ok := data.(map[string]any)
+	dataMap, ok := resultMap(toolName, data)
 	if !ok {
 		return ""
 	}
@@ -38,6 +44,80 @@ func FormatToolResult(toolName string, data any) string {
 	// Unwrap { "data": { ... } } wrapper if present
 	dataMap = unwrapData(dataMap)
 
+	// Cut huge arrays down before any formatter walks them.
+	dropped := 0
+	dataMap = clampArrays(dataMap, &dropped).(map[string]any)
+	out := formatResult(toolName, dataMap)
+	if out != "" && dropped > 0 {
+		out += "\n" + ui.DimStyle.Render(fmt.Sprintf("%d more items not shown; the response was too long to format in full.", dropped))
+	}
+	return out
+}
+
+// resultMap returns a tool response as a map. The launch feeds may answer
+// with a bare list, which is put under "items"; a list from any other tool
+// isn't formatted.
+func resultMap(toolName string, data any) (map[string]any, bool) {
+	if m, ok := data.(map[string]any); ok {
+		return m, true
+	}
+	if list, ok := data.([]any); ok && launchFeedTools[toolName] {
+		return map[string]any{"items": list}, true
+	}
+	return nil, false
+}
+
+// maxFormattedItems is the most elements of any one array the formatters
+// are given.
+const maxFormattedItems = 500
+
+// clampArrays returns v with every array longer than maxFormattedItems cut
+// to that length, adding the elements left out to dropped. Maps and arrays
+// are copied only where something inside them was cut.
+func clampArrays(v any, dropped *int) any {
+	switch t := v.(type) {
+	case []any:
+		if len(t) > maxFormattedItems {
+			*dropped += len(t) - maxFormattedItems
+			t = t[:maxFormattedItems:maxFormattedItems]
+		}
+		var out []any
+		for i, e := range t {
+			before := *dropped
+			c := clampArrays(e, dropped)
+			if *dropped != before && out == nil {
+				out = slices.Clone(t)
+			}
+			if out != nil {
+				out[i] = c
+			}
+		}
+		if out != nil {
+			return out
+		}
+		return t
+	case map[string]any:
+		var out map[string]any
+		for k, e := range t {
+			before := *dropped
+			c := clampArrays(e, dropped)
+			if *dropped != before {
+				if out == nil {
+					out = maps.Clone(t)
+				}
+				out[k] = c
+			}
+		}
+		if out != nil {
+			return out
+		}
+		return t
+	}
+	return v
+}
+
+// formatResult picks the formatter for toolName.
+func formatResult(toolName string, dataMap map[string]any) string {
 	switch toolName {
 	case "get_portfolio", "get_portfolio_summary":
 		return FormatPortfolio(dataMap)
@@ -53,6 +133,8 @@ func FormatToolResult(toolName string, data any) string {
 		return FormatTokenPrice(dataMap)
 	case "get_brewing_tokens":
 		return FormatBrewingTokens(dataMap)
+	case "get_launch_feed", "get_recent_launches":
+		return FormatLaunchFeed(dataMap)
 	case "get_swap_price", "get_swap_quote":
 		return FormatSwapQuote(dataMap)
 	case "execute_swap", "execute_trade":
@@ -99,6 +181,27 @@ func FormatToolResult(toolName string, data any) string {
 		return FormatDeployerTokens(dataMap)
 	case "get_deployer_activity":
 		return FormatDeployerActivity(dataMap)
+	// History
+	case "get_trade_history":
+		return FormatTradeHistory(dataMap)
+	case "get_transfers":
+		return FormatTransfers(dataMap)
+	// Tracking
+	case "get_watchlist":
+		return FormatWatchlist(dataMap)
+	case "add_to_watchlist":
+		if _, ok := dataMap["watchlist"].([]any); ok {
+			return FormatWatchlist(dataMap)
+		}
+		return ""
+	case "get_kol_wallets":
+		return FormatKOLWallets(dataMap)
+	case "get_kol_swaps":
+		return FormatKOLSwaps(dataMap)
+	case "get_kol_info":
+		return FormatKOLInfo(dataMap)
+	case "get_user_xp":
+		return FormatUserXP(dataMap)
 	default:
 		return ""
 	}
@@ -106,7 +209,7 @@ func FormatToolResult(toolName string, data any) string {
 
 // FormatToolPreview returns a short one-line summary for the TUI status line.
 func FormatToolPreview(toolName string, data any) string {
-	dataMap, ok := data.(map[string]any)
+	dataMap, ok := resultMap(toolName, data)
 	if !ok {
 		return fmt.Sprintf("%v", data)
 	}
@@ -128,13 +231,23 @@ func FormatToolPreview(toolName string, data any) string {
 		return fmt.Sprintf("Total: %s (%d positions)", FormatUSD(totalValue), int(count))
 
 	case "get_portfolio_pnl", "get_pnl_chart":
-		values := extractFloatSlice(dataMap, "chart", "data", "values", "points")
+		values := extractValueFromObjects(dataMap, "data_points", "value_usd")
+		if len(values) == 0 {
+			values = extractFloatSlice(dataMap, "chart", "data", "values", "points")
+		}
+		label := "P&L chart"
+		if tf := chartTimeframe(dataMap); tf != "" {
+			label += " " + tf
+		}
 		if len(values) > 0 {
-			return fmt.Sprintf("P&L chart (%d points)", len(values))
+			return fmt.Sprintf("%s (%d points)", label, len(values))
 		}
-		return "P&L chart loaded"
+		return label + " loaded"
 
 	case "get_token_chart", "get_token_ohlc", "get_ohlc", "get_price_chart":
+		if tf := chartTimeframe(dataMap); tf != "" {
+			return fmt.Sprintf("Token price chart %s loaded", tf)
+		}
 		return "Token price chart loaded"
 
 	case "search_tokens", "get_tokens_by_category", "search_token_by_slug", "get_category_tokens":
@@ -168,12 +281,13 @@ func FormatToolPreview(toolName string, data any) string {
 		}
 		return fmt.Sprintf("%d brewing tokens", len(tokens))
 
+	case "get_launch_feed", "get_recent_launches":
+		return launchFeedPreview(dataMap)
+
 	case "get_swap_price", "get_swap_quote":
-		fromSymbol := getString(dataMap, "from_symbol")
-		toSymbol := getString(dataMap, "to_symbol")
-		toAmount := getFloat(dataMap, "to_amount")
-		if fromSymbol != "" && toSymbol != "" {
-			return fmt.Sprintf("%s -> %s %s", fromSymbol, FormatNumber(toAmount), toSymbol)
+		q := parseSwapQuote(dataMap)
+		if q.FromSymbol != "" && q.ToSymbol != "" {
+			return fmt.Sprintf("%s -> %s %s", q.FromSymbol, FormatNumber(q.ToAmount), q.ToSymbol)
 		}
 		return "Swap quote ready"
 
@@ -213,7 +327,10 @@ func FormatToolPreview(toolName string, data any) string {
 		return fmt.Sprintf("Audit complete — Risk: %s", risk)
 
 	case "audit_tokens_batch":
-		audits, _ := dataMap["audits"].([]any)
+		audits, failed := SplitAuditBatch(dataMap)
+		if len(failed) > 0 {
+			return fmt.Sprintf("%d tokens audited, %d failed", len(audits), len(failed))
+		}
 		return fmt.Sprintf("%d tokens audited", len(audits))
 
 	case "is_token_verified":
@@ -302,6 +419,12 @@ func FormatToolPreview(toolName string, data any) string {
 		activity, _ := dataMap["activity"].([]any)
 		return fmt.Sprintf("%d dev trades", len(activity))
 
+	case "get_trade_history":
+		return tradeHistoryPreview(dataMap)
+
+	case "get_transfers":
+		return transfersPreview(dataMap)
+
 	case "get_maker_trades":
 		analysis := getString(dataMap, "analysis")
 		if analysis != "" {
@@ -311,13 +434,29 @@ func FormatToolPreview(toolName string, data any) string {
 
 	// Tracking
 	case "get_kol_wallets":
-		kols, _ := dataMap["kols"].([]any)
+		kols := historyList(dataMap, "kols", "wallets", "items")
 		return fmt.Sprintf("%d KOLs", len(kols))
 
 	case "get_kol_swaps":
-		swaps, _ := dataMap["swaps"].([]any)
+		swaps := historyList(dataMap, "swaps", "trades", "items")
+		if last := newestRecord(swaps); last != nil {
+			return fmt.Sprintf("%d KOL swaps, last: %s %s $%s", len(swaps), kolName(last), tradeSide(last), historyToken(last))
+		}
 		return fmt.Sprintf("%d KOL swaps", len(swaps))
 
+	case "get_kol_info":
+		kol := dataMap
+		if inner, ok := dataMap["kol"].(map[string]any); ok {
+			kol = inner
+		}
+		if name := kolName(kol); name != "" {
+			return "KOL " + name
+		}
+		return "KOL info loaded"
+
+	case "get_user_xp":
+		return userXPPreview(dataMap)
+
 	case "get_live_swaps":
 		swaps, _ := dataMap["swaps"].([]any)
 		return fmt.Sprintf("%d live swaps", len(swaps))
@@ -327,9 +466,12 @@ func FormatToolPreview(toolName string, data any) string {
 		return fmt.Sprintf("%d user swaps", len(swaps))
 
 	case "get_watchlist":
-		watchlist, _ := dataMap["watchlist"].([]any)
+		watchlist := historyList(dataMap, "watchlist", "tokens", "items")
 		return fmt.Sprintf("%d tokens in watchlist", len(watchlist))
 
+	case "add_to_watchlist":
+		return watchlistAddedPreview(dataMap)
+
 	case "get_streaming_status":
 		ready, _ := getBool(dataMap, "ready_to_stream")
 		if ready {
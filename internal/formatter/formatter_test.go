@@ -0,0 +1,46 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+// Huge arrays are cut before formatting, with a note of what was left out,
+// and the caller's data is left as it was.
+func TestFormatToolResultClampsArrays(t *testing.T) {
+	TermWidth = 120
+	holders := make([]any, 10000)
+	for i := range holders {
+		holders[i] = map[string]any{"address": evmAddr, "percentage": 0.01}
+	}
+	data := map[string]any{"data": map[string]any{"holders": holders, "chain": "base"}}
+
+	out := FormatToolResult("get_holders", data)
+	if !strings.Contains(out, "9500 more items not shown") {
+		t.Errorf("no note of the items left out:\n%s", out)
+	}
+	if n := len(data["data"].(map[string]any)["holders"].([]any)); n != 10000 {
+		t.Errorf("caller's array cut to %d", n)
+	}
+
+	if out := FormatToolResult("get_holders", map[string]any{"holders": holders[:3]}); strings.Contains(out, "not shown") {
+		t.Errorf("short result noted as cut:\n%s", out)
+	}
+}
+
+func TestClampArrays(t *testing.T) {
+	long := make([]any, maxFormattedItems+7)
+	nested := map[string]any{"a": []any{map[string]any{"b": long}}, "c": []any{1.0, 2.0}}
+	dropped := 0
+	out := clampArrays(nested, &dropped).(map[string]any)
+	if dropped != 7 {
+		t.Errorf("dropped %d, want 7", dropped)
+	}
+	inner := out["a"].([]any)[0].(map[string]any)["b"].([]any)
+	if len(inner) != maxFormattedItems || len(long) != maxFormattedItems+7 {
+		t.Errorf("clamped to %d, original now %d", len(inner), len(long))
+	}
+	if len(out["c"].([]any)) != 2 {
+		t.Errorf("short array changed: %v", out["c"])
+	}
+}
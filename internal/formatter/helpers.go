@@ -13,6 +13,32 @@ import (
 // Set by the TUI on init and resize. Default 80.
 var TermWidth = 80
 
+// Accessible switches formatters to screen-reader friendly output: no boxes
+// or separators, tables rendered as "label: value" groups, and words instead
+// of arrows or color for direction. Set from config at startup.
+var Accessible = false
+
+// Plain replaces the Unicode block characters of sparklines and progress
+// bars with ASCII, for output captured to files or CI logs. Set from
+// --no-color or NO_COLOR at startup, together with the plain lipgloss
+// profile that drops colors.
+var Plain = false
+
+// ChainFilter reports whether a chain should be shown in full. Set by the
+// CLI from the enabled-chains config; nil shows every chain.
+var ChainFilter func(chain string) bool
+
+// SymbolResolver looks up the symbol of a token address. Formatters call it
+// while rendering, so it must answer from local data and never block on the
+// network.
+type SymbolResolver interface {
+	Symbol(address string) (string, bool)
+}
+
+// Symbols labels token addresses with known symbols. Set by the CLI from the
+// token cache; nil leaves addresses bare.
+var Symbols SymbolResolver
+
 // contentWidth returns the usable width for table content inside a box border.
 // Box border uses 2 chars each side for border + 2 chars each side for padding = 8 total.
 // Plus 4 chars indent from activity log indentation.
@@ -25,7 +51,11 @@ func contentWidth() int {
 }
 
 // sepLine returns a dim separator line capped to content width.
+// In accessible mode it returns an empty string.
 func sepLine(cols int) string {
+	if Accessible {
+		return ""
+	}
 	w := cols
 	cw := contentWidth()
 	if w > cw {
@@ -39,40 +69,93 @@ func isCompact() bool {
 	return TermWidth < 90
 }
 
-// FormatUSD formats a float64 value as a USD currency string with appropriate
-// suffix (B, M, K) and precision, styled in gold.
-func FormatUSD(value float64) string {
-	style := lipgloss.NewStyle().Foreground(ui.ColorGold)
+// renderBox wraps content in the given border style. In accessible mode the
+// border is omitted and the padding lipgloss adds to align lines is trimmed.
+func renderBox(style lipgloss.Style, content string) string {
+	if Accessible {
+		lines := strings.Split(content, "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimRight(l, " ")
+		}
+		return strings.Join(lines, "\n")
+	}
+	return style.Render(content)
+}
 
-	var formatted string
-	abs := math.Abs(value)
-	sign := ""
-	if value < 0 {
-		sign = "-"
+// accessibleRecord renders one table row as a title followed by indented
+// "label: value" lines. Fields with an empty value are skipped.
+func accessibleRecord(title string, fields [][2]string) string {
+	lines := []string{lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true).Render(title)}
+	for _, f := range fields {
+		if f[1] == "" {
+			continue
+		}
+		lines = append(lines, "  "+f[0]+": "+f[1])
 	}
+	return strings.Join(lines, "\n")
+}
 
+// FormatUSD formats a float64 USD value in the display currency with
+// appropriate suffix (B, M, K) and precision, styled in gold.
+func FormatUSD(value float64) string {
+	return FormatMoney(value, DisplayCurrency())
+}
+
+// FormatMoney formats a USD value in currency c the way FormatUSD does,
+// styled in gold.
+func FormatMoney(value float64, c Currency) string {
+	return lipgloss.NewStyle().Foreground(ui.ColorGold).Render(c.Compact(value))
+}
+
+// FormatPnLUSD formats a profit or loss in USD with its sign: green with a
+// plus for gains, red with a minus for losses. It is shown in the display
+// currency.
+func FormatPnLUSD(value float64) string {
+	c := DisplayCurrency()
+	abs := math.Abs(value * c.Rate)
+	var amount string
 	switch {
-	case abs >= 1_000_000_000:
-		formatted = fmt.Sprintf("%s$%.1fB", sign, abs/1_000_000_000)
 	case abs >= 1_000_000:
-		formatted = fmt.Sprintf("%s$%.1fM", sign, abs/1_000_000)
-	case abs >= 1_000:
-		formatted = fmt.Sprintf("%s$%.1fK", sign, abs/1_000)
-	case abs >= 1:
-		formatted = fmt.Sprintf("%s$%.2f", sign, abs)
-	case abs >= 0.01:
-		formatted = fmt.Sprintf("%s$%.4f", sign, abs)
+		amount = c.place(c.number(abs/1_000_000, 1, "M"))
+	case abs >= 10_000:
+		amount = c.place(c.number(abs/1_000, 1, "K"))
 	default:
-		formatted = fmt.Sprintf("%s$%.8f", sign, abs)
+		amount = c.place(c.number(abs, 2, ""))
+	}
+	if Accessible {
+		switch {
+		case value > 0:
+			return "up " + amount
+		case value < 0:
+			return "down " + amount
+		default:
+			return "unchanged"
+		}
+	}
+	switch {
+	case value > 0:
+		return lipgloss.NewStyle().Foreground(ui.ColorGreen).Render("+" + amount)
+	case value < 0:
+		return lipgloss.NewStyle().Foreground(ui.ColorRed).Render("-" + amount)
+	default:
+		return ui.DimStyle.Render(amount)
 	}
-
-	return style.Render(formatted)
 }
 
 // FormatPercent formats a float64 as a percentage with color and direction
 // indicator. Positive values are green with an up arrow, negative values are
 // red with a down arrow, and zero is rendered dimly.
 func FormatPercent(value float64) string {
+	if Accessible {
+		switch {
+		case value > 0:
+			return fmt.Sprintf("up %.2f%%", value)
+		case value < 0:
+			return fmt.Sprintf("down %.2f%%", -value)
+		default:
+			return "unchanged"
+		}
+	}
 	switch {
 	case value > 0:
 		style := lipgloss.NewStyle().Foreground(ui.ColorGreen)
@@ -114,14 +197,46 @@ func TruncateAddress(addr string) string {
 	return addr
 }
 
+// resolveSymbol returns the cached symbol for a token address.
+func resolveSymbol(addr string) (string, bool) {
+	if Symbols == nil || addr == "" {
+		return "", false
+	}
+	sym, ok := Symbols.Symbol(addr)
+	return sym, ok && sym != ""
+}
+
+// tokenLabel truncates a token address and, when its symbol is known, appends
+// it dimmed: "0x7a3b...91c2 (PEPE)".
+func tokenLabel(addr string) string {
+	short := TruncateAddress(addr)
+	if sym, ok := resolveSymbol(addr); ok {
+		return short + ui.DimStyle.Render(" ("+sym+")")
+	}
+	return short
+}
+
+// tokenTitle is tokenLabel without styling, for use inside titles that are
+// already styled.
+func tokenTitle(addr string) string {
+	if sym, ok := resolveSymbol(addr); ok {
+		return TruncateAddress(addr) + " (" + sym + ")"
+	}
+	return TruncateAddress(addr)
+}
+
 // Sparkline renders a sparkline string from a slice of float64 values using
-// Unicode block characters. Values are normalized to the min/max range.
+// Unicode block characters, or ASCII ones in plain mode. Values are
+// normalized to the min/max range.
 func Sparkline(values []float64) string {
 	if len(values) == 0 {
 		return ""
 	}
 
 	blocks := []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+	if Plain {
+		blocks = []rune{'_', '.', ',', '-', '~', '=', '*', '#'}
+	}
 
 	minVal := values[0]
 	maxVal := values[0]
@@ -155,9 +270,22 @@ func Sparkline(values []float64) string {
 
 // ProgressBar renders a horizontal progress bar of the given width using filled
 // and empty block characters. The filled portion is colored with the boba color.
+// In accessible mode it renders the ratio as words, e.g. "42 percent"; in
+// plain mode it draws "###-------".
 func ProgressBar(current, total float64, width int) string {
+	if Accessible {
+		pct := 0.0
+		if total > 0 {
+			pct = math.Max(0, math.Min(1, current/total)) * 100
+		}
+		return fmt.Sprintf("%.0f percent", pct)
+	}
+	fullGlyph, emptyGlyph := "█", "░"
+	if Plain {
+		fullGlyph, emptyGlyph = "#", "-"
+	}
 	if total <= 0 || width <= 0 {
-		return strings.Repeat("░", width)
+		return strings.Repeat(emptyGlyph, width)
 	}
 
 	ratio := current / total
@@ -172,8 +300,8 @@ func ProgressBar(current, total float64, width int) string {
 	empty := width - filled
 
 	filledStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba)
-	filledStr := filledStyle.Render(strings.Repeat("█", filled))
-	emptyStr := strings.Repeat("░", empty)
+	filledStr := filledStyle.Render(strings.Repeat(fullGlyph, filled))
+	emptyStr := strings.Repeat(emptyGlyph, empty)
 
 	return filledStr + emptyStr
 }
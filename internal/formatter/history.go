@@ -0,0 +1,420 @@
+package formatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// historyRows is how many trades or transfers a table lists before
+// summarizing the rest.
+const historyRows = 15
+
+// historyList returns the records of a get_trade_history or get_transfers
+// response, under whichever key the backend used.
+func historyList(data map[string]any, keys ...string) []any {
+	for _, k := range keys {
+		if list, ok := data[k].([]any); ok {
+			return list
+		}
+	}
+	return nil
+}
+
+// pickString returns the first non-empty string among keys.
+func pickString(m map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if s := getString(m, k); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// pickFloat returns the first of keys present in m, as a number.
+func pickFloat(m map[string]any, keys ...string) (float64, bool) {
+	for _, k := range keys {
+		if _, ok := m[k]; ok {
+			return getFloat(m, k), true
+		}
+	}
+	return 0, false
+}
+
+// historyTime parses a record's timestamp, given as RFC 3339 or as Unix
+// seconds or milliseconds, either as a number or a string.
+func historyTime(m map[string]any) (time.Time, bool) {
+	for _, k := range []string{"timestamp", "block_time", "created_at", "time", "date"} {
+		v, ok := m[k]
+		if !ok {
+			continue
+		}
+		var secs float64
+		switch t := v.(type) {
+		case float64:
+			secs = t
+		case string:
+			if ts, err := time.Parse(time.RFC3339Nano, t); err == nil {
+				return ts, true
+			}
+			f, err := strconv.ParseFloat(t, 64)
+			if err != nil {
+				continue
+			}
+			secs = f
+		default:
+			continue
+		}
+		if secs > 1e12 {
+			secs /= 1000
+		}
+		if secs > 0 {
+			return time.Unix(int64(secs), 0), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// historyWhen renders a record's timestamp for a table cell.
+func historyWhen(m map[string]any) string {
+	if t, ok := historyTime(m); ok {
+		return t.Local().Format("01-02 15:04")
+	}
+	return ""
+}
+
+// historyToken returns a record's token symbol, or its shortened address.
+func historyToken(m map[string]any) string {
+	if sym := pickString(m, "token_symbol", "symbol"); sym != "" {
+		return sym
+	}
+	if tok, ok := m["token"].(map[string]any); ok {
+		if sym := pickString(tok, "symbol"); sym != "" {
+			return sym
+		}
+		m = tok
+	}
+	addr := pickString(m, "token_address", "address", "token")
+	if sym, ok := resolveSymbol(addr); ok {
+		return sym
+	}
+	return TruncateAddress(addr)
+}
+
+// newestRecord returns the most recent record, or the first one when their
+// times can't be compared.
+func newestRecord(list []any) map[string]any {
+	var newest map[string]any
+	var newestAt time.Time
+	for _, r := range list {
+		m, ok := r.(map[string]any)
+		if !ok {
+			continue
+		}
+		at, ok := historyTime(m)
+		if newest == nil {
+			newest, newestAt = m, at
+			if !ok {
+				return m
+			}
+			continue
+		}
+		if !ok {
+			return firstRecord(list)
+		}
+		if at.After(newestAt) {
+			newest, newestAt = m, at
+		}
+	}
+	return newest
+}
+
+func firstRecord(list []any) map[string]any {
+	for _, r := range list {
+		if m, ok := r.(map[string]any); ok {
+			return m
+		}
+	}
+	return nil
+}
+
+// tradeSide returns BUY or SELL for a trade record.
+func tradeSide(m map[string]any) string {
+	return strings.ToUpper(pickString(m, "side", "type", "direction"))
+}
+
+// sideStyle colors buys green and sells red.
+func sideStyle(side string) lipgloss.Style {
+	switch side {
+	case "BUY", "IN":
+		return lipgloss.NewStyle().Foreground(ui.ColorGreen).Bold(true)
+	case "SELL", "OUT":
+		return lipgloss.NewStyle().Foreground(ui.ColorRed).Bold(true)
+	}
+	return lipgloss.NewStyle().Foreground(ui.ColorBright)
+}
+
+// FormatTradeHistory renders a table of recent trades for get_trade_history.
+func FormatTradeHistory(data map[string]any) string {
+	trades := historyList(data, "trades", "history", "swaps", "items")
+	if len(trades) == 0 {
+		return ui.DimStyle.Render("No trades found.")
+	}
+
+	header := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true).Render("TRADE HISTORY")
+	subtitle := ui.DimStyle.Render(fmt.Sprintf("%d trades", len(trades)))
+
+	compact := isCompact()
+	wTime, wSide, wToken, wAmount, wValue, wTx := 13, 6, 10, 14, 12, 14
+	if compact {
+		wTime, wToken, wAmount, wValue = 0, 8, 12, 10
+		wTx = 0
+	}
+
+	var headerParts []string
+	if wTime > 0 {
+		headerParts = append(headerParts, lipgloss.NewStyle().Width(wTime).Bold(true).Render("Time"))
+	}
+	headerParts = append(headerParts,
+		lipgloss.NewStyle().Width(wSide).Bold(true).Render("Side"),
+		lipgloss.NewStyle().Width(wToken).Bold(true).Render("Token"),
+		lipgloss.NewStyle().Width(wAmount).Bold(true).Render("Amount"),
+		lipgloss.NewStyle().Width(wValue).Bold(true).Render("Value"),
+	)
+	if wTx > 0 {
+		headerParts = append(headerParts, lipgloss.NewStyle().Width(wTx).Bold(true).Render("Tx"))
+	}
+	totalCols := wTime + wSide + wToken + wAmount + wValue + wTx
+
+	var rows []string
+	if !Accessible {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, headerParts...), sepLine(totalCols))
+	}
+
+	displayed := trades
+	if len(displayed) > historyRows {
+		displayed = displayed[:historyRows]
+	}
+	for _, t := range displayed {
+		trade, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		side := tradeSide(trade)
+		token := historyToken(trade)
+		amount, hasAmount := pickFloat(trade, "token_amount", "amount", "quantity")
+		value, hasValue := pickFloat(trade, "value_usd", "amount_usd", "usd_value", "volume_usd")
+		tx := TruncateAddress(pickString(trade, "tx_hash", "transaction_hash", "signature", "hash"))
+		when := historyWhen(trade)
+
+		amountStr, valueStr := "—", "—"
+		if hasAmount {
+			amountStr = FormatNumber(amount)
+		}
+		if hasValue {
+			valueStr = FormatUSD(value)
+		}
+
+		if Accessible {
+			if !hasAmount {
+				amountStr = ""
+			}
+			if !hasValue {
+				valueStr = ""
+			}
+			rows = append(rows, accessibleRecord(strings.TrimSpace(side+" "+token), [][2]string{
+				{"Time", when},
+				{"Amount", amountStr},
+				{"Value", valueStr},
+				{"Transaction", tx},
+			}))
+			continue
+		}
+
+		var parts []string
+		if wTime > 0 {
+			parts = append(parts, lipgloss.NewStyle().Width(wTime).Render(ui.DimStyle.Render(when)))
+		}
+		parts = append(parts,
+			lipgloss.NewStyle().Width(wSide).Render(sideStyle(side).Render(side)),
+			lipgloss.NewStyle().Width(wToken).MaxWidth(wToken).Render(token),
+			lipgloss.NewStyle().Width(wAmount).Render(amountStr),
+			lipgloss.NewStyle().Width(wValue).Render(valueStr),
+		)
+		if wTx > 0 {
+			parts = append(parts, lipgloss.NewStyle().Width(wTx).Render(ui.DimStyle.Render(tx)))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, parts...))
+	}
+	if len(trades) > historyRows {
+		rows = append(rows, ui.DimStyle.Render(fmt.Sprintf("+%d more", len(trades)-historyRows)))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, header, subtitle, "", strings.Join(rows, "\n"))
+	return renderBox(ui.BoxBorder, content)
+}
+
+// transferDirection returns IN or OUT for a transfer record.
+func transferDirection(m map[string]any) string {
+	switch strings.ToLower(pickString(m, "direction", "type", "side")) {
+	case "in", "incoming", "received", "receive", "deposit":
+		return "IN"
+	case "out", "outgoing", "sent", "send", "withdrawal", "withdraw":
+		return "OUT"
+	}
+	return ""
+}
+
+// transferCounterparty returns the other side of a transfer: the sender of
+// incoming funds and the recipient of outgoing ones.
+func transferCounterparty(m map[string]any, direction string) string {
+	if cp := pickString(m, "counterparty", "counterparty_address"); cp != "" {
+		return cp
+	}
+	if direction == "IN" {
+		return pickString(m, "from", "from_address", "sender")
+	}
+	return pickString(m, "to", "to_address", "recipient")
+}
+
+// FormatTransfers renders a table of wallet transfers for get_transfers.
+func FormatTransfers(data map[string]any) string {
+	transfers := historyList(data, "transfers", "history", "items")
+	if len(transfers) == 0 {
+		return ui.DimStyle.Render("No transfers found.")
+	}
+
+	header := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true).Render("TRANSFERS")
+	subtitle := ui.DimStyle.Render(fmt.Sprintf("%d transfers", len(transfers)))
+
+	compact := isCompact()
+	wDir, wToken, wAmount, wParty, wChain := 7, 10, 14, 16, 10
+	if compact {
+		wToken, wAmount, wParty, wChain = 8, 12, 14, 0
+	}
+
+	headerParts := []string{
+		lipgloss.NewStyle().Width(wDir).Bold(true).Render("Dir"),
+		lipgloss.NewStyle().Width(wToken).Bold(true).Render("Token"),
+		lipgloss.NewStyle().Width(wAmount).Bold(true).Render("Amount"),
+		lipgloss.NewStyle().Width(wParty).Bold(true).Render("Counterparty"),
+	}
+	if wChain > 0 {
+		headerParts = append(headerParts, lipgloss.NewStyle().Width(wChain).Bold(true).Render("Chain"))
+	}
+	totalCols := wDir + wToken + wAmount + wParty + wChain
+
+	var rows []string
+	if !Accessible {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, headerParts...), sepLine(totalCols))
+	}
+
+	displayed := transfers
+	if len(displayed) > historyRows {
+		displayed = displayed[:historyRows]
+	}
+	for _, t := range displayed {
+		transfer, ok := t.(map[string]any)
+		if !ok {
+			continue
+		}
+		dir := transferDirection(transfer)
+		token := historyToken(transfer)
+		amount, hasAmount := pickFloat(transfer, "amount", "token_amount", "value")
+		party := TruncateAddress(transferCounterparty(transfer, dir))
+		chain := pickString(transfer, "chain", "chain_name", "network")
+
+		amountStr := "—"
+		if hasAmount {
+			amountStr = FormatNumber(amount)
+		}
+
+		if Accessible {
+			if !hasAmount {
+				amountStr = ""
+			}
+			label := map[string]string{"IN": "Received", "OUT": "Sent"}[dir]
+			if label == "" {
+				label = "Transfer"
+			}
+			rows = append(rows, accessibleRecord(label+" "+token, [][2]string{
+				{"Amount", amountStr},
+				{"Counterparty", party},
+				{"Chain", chain},
+				{"Time", historyWhen(transfer)},
+			}))
+			continue
+		}
+
+		arrow := map[string]string{"IN": "↓ in", "OUT": "↑ out"}[dir]
+		parts := []string{
+			lipgloss.NewStyle().Width(wDir).Render(sideStyle(dir).Render(arrow)),
+			lipgloss.NewStyle().Width(wToken).MaxWidth(wToken).Render(token),
+			lipgloss.NewStyle().Width(wAmount).Render(amountStr),
+			lipgloss.NewStyle().Width(wParty).Render(ui.DimStyle.Render(party)),
+		}
+		if wChain > 0 {
+			parts = append(parts, lipgloss.NewStyle().Width(wChain).Render(ui.DimStyle.Render(chain)))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, parts...))
+	}
+	if len(transfers) > historyRows {
+		rows = append(rows, ui.DimStyle.Render(fmt.Sprintf("+%d more", len(transfers)-historyRows)))
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, header, subtitle, "", strings.Join(rows, "\n"))
+	return renderBox(ui.BoxBorder, content)
+}
+
+// tradeHistoryPreview summarizes get_trade_history for the status line:
+// "12 trades, last: BUY $PEPE $42.00".
+func tradeHistoryPreview(data map[string]any) string {
+	trades := historyList(data, "trades", "history", "swaps", "items")
+	if len(trades) == 0 {
+		return "No trades"
+	}
+	summary := fmt.Sprintf("%d trades", len(trades))
+	last := newestRecord(trades)
+	if last == nil {
+		return summary
+	}
+	desc := tradeSide(last)
+	if token := historyToken(last); token != "" {
+		desc += " $" + token
+	}
+	if value, ok := pickFloat(last, "value_usd", "amount_usd", "usd_value", "volume_usd"); ok {
+		desc += " " + FormatUSD(value)
+	}
+	if desc = strings.TrimSpace(desc); desc != "" {
+		summary += ", last: " + desc
+	}
+	return summary
+}
+
+// transfersPreview summarizes get_transfers for the status line:
+// "8 transfers (5 in, 3 out)".
+func transfersPreview(data map[string]any) string {
+	transfers := historyList(data, "transfers", "history", "items")
+	if len(transfers) == 0 {
+		return "No transfers"
+	}
+	in, out := 0, 0
+	for _, t := range transfers {
+		if m, ok := t.(map[string]any); ok {
+			switch transferDirection(m) {
+			case "IN":
+				in++
+			case "OUT":
+				out++
+			}
+		}
+	}
+	if in+out == 0 {
+		return fmt.Sprintf("%d transfers", len(transfers))
+	}
+	return fmt.Sprintf("%d transfers (%d in, %d out)", len(transfers), in, out)
+}
@@ -0,0 +1,94 @@
+package formatter
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// decode parses a tool response the way the proxy hands it over.
+func decode(t *testing.T, s string) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+const tradeHistoryJSON = `{"trades":[
+	{"side":"sell","token_symbol":"WIF","token_amount":"1,250.5","value_usd":"310.2","tx_hash":"5VfYmGC2kJvKXqS9yM3nP7rT8wZ1aB4cD6eF","timestamp":"1718000000"},
+	{"type":"buy","token":{"symbol":"PEPE"},"amount":"1000000","amount_usd":42,"signature":"0xabc1234567890def1234567890abcdef","created_at":"2024-06-11T09:30:00Z"},
+	{"side":"BUY","symbol":"BONK","quantity":5000,"block_time":1717900000000}
+]}`
+
+func TestFormatTradeHistory(t *testing.T) {
+	data := decode(t, tradeHistoryJSON)
+	for _, width := range []int{80, 120} {
+		TermWidth = width
+		out := FormatTradeHistory(data)
+		for _, want := range []string{"TRADE HISTORY", "3 trades", "SELL", "WIF", "1.3K", "$310.20", "BUY", "PEPE", "1.0M", "$42.00", "BONK", "—"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("width %d: table lacks %q:\n%s", width, want, out)
+			}
+		}
+		if hasTx := strings.Contains(out, "5VfYmG...D6eF"); hasTx == isCompact() {
+			t.Errorf("width %d: Tx column shown = %v:\n%s", width, hasTx, out)
+		}
+	}
+	TermWidth = 80
+
+	if got, want := FormatToolPreview("get_trade_history", data), "3 trades, last: BUY $PEPE $42.00"; got != want {
+		t.Errorf("preview = %q, want %q", got, want)
+	}
+	if got := FormatToolPreview("get_trade_history", map[string]any{"trades": []any{}}); got != "No trades" {
+		t.Errorf("empty preview = %q", got)
+	}
+	if out := FormatTradeHistory(map[string]any{}); !strings.Contains(out, "No trades found.") {
+		t.Errorf("empty table = %q", out)
+	}
+}
+
+const transfersJSON = `{"transfers":[
+	{"direction":"incoming","token_symbol":"USDC","amount":"250.00","from":"7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU","to":"me","chain":"solana"},
+	{"type":"sent","symbol":"ETH","value":"0.5","to_address":"0x52908400098527886E0F7030069857D2E4169EE7","network":"base"},
+	{"side":"withdraw","symbol":"SOL","amount":1.5,"counterparty":"9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM","chain_name":"solana"}
+]}`
+
+func TestFormatTransfers(t *testing.T) {
+	data := decode(t, transfersJSON)
+	out := FormatTransfers(data)
+	for _, want := range []string{"TRANSFERS", "3 transfers", "↓ in", "↑ out", "USDC", "250.00", "ETH", "0.50", "SOL", "1.50", TruncateAddress("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU"), TruncateAddress("0x52908400098527886E0F7030069857D2E4169EE7")} {
+		if !strings.Contains(out, want) {
+			t.Errorf("table lacks %q:\n%s", want, out)
+		}
+	}
+
+	if got, want := FormatToolPreview("get_transfers", data), "3 transfers (1 in, 2 out)"; got != want {
+		t.Errorf("preview = %q, want %q", got, want)
+	}
+	if out := FormatTransfers(map[string]any{}); !strings.Contains(out, "No transfers found.") {
+		t.Errorf("empty table = %q", out)
+	}
+}
+
+// Both tables list 15 rows and summarize the rest.
+func TestHistoryRowCap(t *testing.T) {
+	var trades, transfers []any
+	for i := range 20 {
+		trades = append(trades, map[string]any{"side": "buy", "token_symbol": fmt.Sprintf("T%02d", i), "value_usd": "1"})
+		transfers = append(transfers, map[string]any{"direction": "in", "token_symbol": fmt.Sprintf("T%02d", i), "amount": "1"})
+	}
+	for name, out := range map[string]string{
+		"trades":    FormatTradeHistory(map[string]any{"trades": trades}),
+		"transfers": FormatTransfers(map[string]any{"transfers": transfers}),
+	} {
+		if !strings.Contains(out, "+5 more") {
+			t.Errorf("%s: no +5 more footer:\n%s", name, out)
+		}
+		if !strings.Contains(out, "T14") || strings.Contains(out, "T15") {
+			t.Errorf("%s: want rows T00-T14 only:\n%s", name, out)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// launchFeedTools are the tools FormatLaunchFeed renders.
+var launchFeedTools = map[string]bool{"get_launch_feed": true, "get_recent_launches": true}
+
+// launchList returns the tokens of a get_launch_feed or get_recent_launches
+// response. A bare list arrives as "items" (see resultMap).
+func launchList(data map[string]any) []map[string]any {
+	return records(historyList(data, "launches", "tokens", "items", "data"))
+}
+
+// launchAge returns how long ago a token launched, from an age field or
+// from its launch time.
+func launchAge(m map[string]any, now time.Time) (time.Duration, bool) {
+	for _, f := range []struct {
+		key  string
+		unit time.Duration
+	}{
+		{"age_seconds", time.Second},
+		{"age_minutes", time.Minute},
+		{"age_hours", time.Hour},
+	} {
+		if v, ok := xpNumber(m, f.key); ok && v >= 0 {
+			return time.Duration(v * float64(f.unit)), true
+		}
+	}
+	for _, k := range []string{"launched_at", "launch_time", "created_timestamp"} {
+		if v, ok := m[k]; ok {
+			m = map[string]any{"timestamp": v}
+			break
+		}
+	}
+	at, ok := historyTime(m)
+	if !ok {
+		return 0, false
+	}
+	return max(0, now.Sub(at)), true
+}
+
+// formatLaunchAge renders an age in its largest whole unit: 45s, 3m, 2h, 4d.
+func formatLaunchAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// Risk levels of a launch, from the audit hints in the feed.
+const (
+	launchRiskUnknown = iota
+	launchRiskOK
+	launchRiskCaution
+	launchRiskDanger
+)
+
+// launchRisk grades a launch by the audit hints it carries, at the top
+// level or under "audit", "security" or "safety", and names the worst of
+// them. It is launchRiskUnknown when there are none.
+func launchRisk(m map[string]any) (level int, reason string) {
+	sources := []map[string]any{m}
+	for _, k := range []string{"audit", "security", "safety"} {
+		if inner, ok := m[k].(map[string]any); ok {
+			sources = append(sources, inner)
+		}
+	}
+	note := func(l int, r string) {
+		if l > level {
+			level, reason = l, r
+		}
+	}
+	for _, s := range sources {
+		for _, k := range []string{"is_honeypot", "honeypot"} {
+			if hp, ok := getBool(s, k); ok {
+				if hp {
+					note(launchRiskDanger, "honeypot")
+				} else {
+					note(launchRiskOK, "")
+				}
+			}
+		}
+		switch strings.ToLower(pickString(s, "risk_level", "risk")) {
+		case "high", "critical":
+			note(launchRiskDanger, "high risk")
+		case "medium":
+			note(launchRiskCaution, "medium risk")
+		case "low":
+			note(launchRiskOK, "")
+		}
+		if dev, ok := xpNumber(s, "dev_holding_percent", "dev_holdings_percent", "creator_holding_percent", "dev_percent"); ok {
+			switch {
+			case dev >= 20:
+				note(launchRiskDanger, fmt.Sprintf("dev holds %.0f%%", dev))
+			case dev >= 5:
+				note(launchRiskCaution, fmt.Sprintf("dev holds %.0f%%", dev))
+			default:
+				note(launchRiskOK, "")
+			}
+		}
+		if top, ok := xpNumber(s, "top10_holding_percent", "top_10_holder_percent", "top10_percent"); ok && top >= 50 {
+			note(launchRiskCaution, fmt.Sprintf("top 10 hold %.0f%%", top))
+		}
+		for _, k := range []string{"is_mintable", "mint_authority_enabled"} {
+			if on, _ := getBool(s, k); on {
+				note(launchRiskCaution, "mintable")
+			}
+		}
+		if on, _ := getBool(s, "freeze_authority_enabled"); on {
+			note(launchRiskCaution, "freezable")
+		}
+	}
+	return level, reason
+}
+
+// launchRiskCell renders a risk level as ✓, ! or ✗, or in words in
+// accessible mode.
+func launchRiskCell(level int, reason string) string {
+	if Accessible {
+		words := map[int]string{launchRiskOK: "ok", launchRiskCaution: "caution", launchRiskDanger: "danger"}[level]
+		if reason != "" {
+			words += ", " + reason
+		}
+		return words
+	}
+	switch level {
+	case launchRiskOK:
+		return ui.SuccessStyle.Render("✓")
+	case launchRiskCaution:
+		return lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render("!")
+	case launchRiskDanger:
+		return ui.ErrorStyle.Render("✗")
+	}
+	return ui.DimStyle.Render("—")
+}
+
+// launchGraduation returns how far a token is along its launchpad's bonding
+// curve, in percent.
+func launchGraduation(m map[string]any) (float64, bool) {
+	return xpNumber(m, "graduation_percent", "graduation_progress", "grad_percent", "bonding_curve_progress", "bonding_progress")
+}
+
+// FormatLaunchFeed renders newly launched tokens for get_launch_feed and
+// get_recent_launches: age, launchpad, market cap, liquidity, graduation
+// progress and, when the feed has audit hints, a risk column.
+func FormatLaunchFeed(data map[string]any) string {
+	launches := launchList(data)
+	if len(launches) == 0 {
+		return ui.DimStyle.Render("No new launches.")
+	}
+
+	now := time.Now()
+	hasRisk := false
+	for _, l := range launches {
+		if level, _ := launchRisk(l); level != launchRiskUnknown {
+			hasRisk = true
+			break
+		}
+	}
+
+	cols := []trackColumn{{"Token", 12, false}, {"Age", 6, false}, {"Launchpad", 12, true}, {"Mkt Cap", 11, false}, {"Liquidity", 11, true}, {"Grad", 15, false}}
+	if hasRisk {
+		cols = append(cols, trackColumn{"Risk", 5, false})
+	}
+	var rows [][]string
+	for _, l := range launches {
+		age := ""
+		if d, ok := launchAge(l, now); ok {
+			age = formatLaunchAge(d)
+		}
+		mcap, liq := "", ""
+		if v, ok := xpNumber(l, "market_cap", "market_cap_usd", "mcap", "fdv"); ok {
+			mcap = FormatUSD(v)
+		}
+		if v, ok := xpNumber(l, "liquidity", "liquidity_usd"); ok {
+			liq = FormatUSD(v)
+		}
+		grad := ""
+		if pct, ok := launchGraduation(l); ok {
+			pct = math.Max(0, math.Min(100, pct))
+			grad = ProgressBar(pct, 100, 8)
+			if !Accessible {
+				grad += fmt.Sprintf(" %.0f%%", pct)
+			}
+		}
+		row := []string{
+			historyToken(l),
+			orDash(age),
+			ui.DimStyle.Render(pickString(l, "launchpad", "platform", "dex", "source")),
+			orDash(mcap),
+			orDash(liq),
+			orDash(grad),
+		}
+		if hasRisk {
+			row = append(row, launchRiskCell(launchRisk(l)))
+		}
+		rows = append(rows, row)
+	}
+
+	subtitle := fmt.Sprintf("%d tokens", len(launches))
+	if len(launches) == 1 {
+		subtitle = "1 token"
+	}
+	if chain := pickString(data, "chain", "chain_name"); chain != "" {
+		subtitle += " on " + chain
+	}
+	return trackTable("NEW LAUNCHES", subtitle, cols, rows)
+}
+
+// launchFeedPreview summarizes a launch feed for the status line:
+// "8 launches, newest: $WIF 3m ago".
+func launchFeedPreview(data map[string]any) string {
+	launches := launchList(data)
+	if len(launches) == 0 {
+		return "No new launches"
+	}
+	summary := fmt.Sprintf("%d launches", len(launches))
+	if len(launches) == 1 {
+		summary = "1 launch"
+	}
+
+	now := time.Now()
+	newest := launches[0]
+	newestAge, known := launchAge(newest, now)
+	for _, l := range launches[1:] {
+		if d, ok := launchAge(l, now); ok && (!known || d < newestAge) {
+			newest, newestAge, known = l, d, true
+		}
+	}
+	token := historyToken(newest)
+	if token == "" {
+		return summary
+	}
+	summary += ", newest: $" + token
+	if known {
+		summary += " " + formatLaunchAge(newestAge) + " ago"
+	}
+	return summary
+}
@@ -0,0 +1,93 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// launchSample returns a launch feed of three tokens, the newest launched
+// three minutes ago. With hints, the tokens carry audit hints.
+func launchSample(hints bool) []any {
+	wif := map[string]any{
+		"symbol":             "WIF",
+		"created_at":         time.Now().Add(-3*time.Minute - 10*time.Second).UTC().Format(time.RFC3339),
+		"launchpad":          "pump.fun",
+		"market_cap":         45000.0,
+		"liquidity":          12000.0,
+		"graduation_percent": 62.0,
+	}
+	bad := map[string]any{"symbol": "BAD", "age_seconds": 600.0, "market_cap": "9,000"}
+	meh := map[string]any{"token": map[string]any{"symbol": "MEH"}, "age_minutes": 90.0}
+	if hints {
+		wif["is_honeypot"] = false
+		bad["audit"] = map[string]any{"is_honeypot": true}
+		meh["dev_holding_percent"] = 8.0
+	}
+	return []any{wif, bad, meh}
+}
+
+func TestLaunchFeedWrappers(t *testing.T) {
+	TermWidth = 120
+	list := launchSample(false)
+	for name, data := range map[string]any{
+		"bare list": list,
+		"launches":  map[string]any{"launches": list},
+		"tokens":    map[string]any{"tokens": list},
+		"data":      map[string]any{"data": map[string]any{"tokens": list}},
+	} {
+		for _, tool := range []string{"get_launch_feed", "get_recent_launches"} {
+			out := FormatToolResult(tool, data)
+			for _, want := range []string{"NEW LAUNCHES", "3 tokens", "WIF", "BAD", "MEH", "pump.fun", "$45.0K", "62%", "3m", "10m", "1h"} {
+				if !strings.Contains(out, want) {
+					t.Errorf("%s/%s: output lacks %q:\n%s", name, tool, want, out)
+				}
+			}
+			if got, want := FormatToolPreview(tool, data), "3 launches, newest: $WIF 3m ago"; got != want {
+				t.Errorf("%s/%s: preview = %q, want %q", name, tool, got, want)
+			}
+		}
+	}
+}
+
+func TestLaunchFeedRiskColumn(t *testing.T) {
+	TermWidth = 120
+	if out := FormatToolResult("get_launch_feed", launchSample(false)); strings.Contains(out, "Risk") {
+		t.Errorf("risk column shown without audit hints:\n%s", out)
+	}
+	out := FormatToolResult("get_launch_feed", launchSample(true))
+	for _, want := range []string{"Risk", "✓", "✗", "!"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+
+	Accessible = true
+	defer func() { Accessible = false }()
+	out = FormatToolResult("get_launch_feed", launchSample(true))
+	for _, want := range []string{"Risk: ok", "Risk: danger, honeypot", "Risk: caution, dev holds 8%"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("accessible output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestLaunchFeedEmpty(t *testing.T) {
+	if got := FormatToolPreview("get_launch_feed", map[string]any{"launches": []any{}}); got != "No new launches" {
+		t.Errorf("preview = %q", got)
+	}
+	list := []any{map[string]any{"symbol": "X"}}
+	if got := FormatToolPreview("get_launch_feed", list); got != "1 launch, newest: $X" {
+		t.Errorf("preview = %q", got)
+	}
+}
+
+// Only the launch feeds read a bare list; other tools leave it unformatted.
+func TestBareListOtherTools(t *testing.T) {
+	list := []any{map[string]any{"symbol": "WIF", "price_usd": 1.5}}
+	for _, tool := range []string{"search_tokens", "get_trending_tokens", "get_brewing_tokens", "get_portfolio"} {
+		if out := FormatToolResult(tool, list); out != "" {
+			t.Errorf("%s formatted a bare list:\n%s", tool, out)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"fmt"
+	"math"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -49,7 +50,7 @@ func FormatOrderCreated(data map[string]any) string {
 	}
 
 	// Limit order fields
-	side := getString(data, "side")
+	side := orderSide(data)
 	if side != "" {
 		lines = append(lines, labelStyle.Render("Side")+formatSide(side))
 	}
@@ -57,15 +58,15 @@ func FormatOrderCreated(data map[string]any) string {
 	inputToken := getString(data, "input_token")
 	outputToken := getString(data, "output_token")
 	if inputToken != "" {
-		lines = append(lines, labelStyle.Render("Input Token")+ui.DimStyle.Render(TruncateAddress(inputToken)))
+		lines = append(lines, labelStyle.Render("Input Token")+ui.DimStyle.Render(tokenLabel(inputToken)))
 	}
 	if outputToken != "" {
-		lines = append(lines, labelStyle.Render("Output Token")+ui.DimStyle.Render(TruncateAddress(outputToken)))
+		lines = append(lines, labelStyle.Render("Output Token")+ui.DimStyle.Render(tokenLabel(outputToken)))
 	}
 
 	inputAmount := getFloat(data, "input_amount")
 	if inputAmount > 0 {
-		lines = append(lines, labelStyle.Render("Input Amount")+FormatNumber(inputAmount))
+		lines = append(lines, labelStyle.Render("Input Amount")+formatOrderAmount(data, inputAmount))
 	}
 
 	triggerPrice := getFloat(data, "trigger_price")
@@ -81,12 +82,12 @@ func FormatOrderCreated(data map[string]any) string {
 	// DCA order fields
 	totalAmount := getFloat(data, "total_amount")
 	if totalAmount > 0 {
-		lines = append(lines, labelStyle.Render("Total Amount")+FormatNumber(totalAmount))
+		lines = append(lines, labelStyle.Render("Total Amount")+formatOrderAmount(data, totalAmount))
 	}
 
 	amountPerInterval := getFloat(data, "amount_per_interval")
 	if amountPerInterval > 0 {
-		lines = append(lines, labelStyle.Render("Per Interval")+FormatNumber(amountPerInterval))
+		lines = append(lines, labelStyle.Render("Per Interval")+formatOrderAmount(data, amountPerInterval))
 	}
 
 	totalIntervals := getFloat(data, "total_intervals")
@@ -112,7 +113,11 @@ func FormatOrderCreated(data map[string]any) string {
 
 	amountPerSlice := getFloat(data, "amount_per_slice")
 	if amountPerSlice > 0 {
-		lines = append(lines, labelStyle.Render("Per Slice")+FormatNumber(amountPerSlice))
+		lines = append(lines, labelStyle.Render("Per Slice")+formatOrderAmount(data, amountPerSlice))
+	}
+
+	if est, ok := estimateOrderValue(data, orderAmount(data)); ok {
+		lines = append(lines, labelStyle.Render("Est. Value")+ui.DimStyle.Render("≈ ")+FormatUSD(est))
 	}
 
 	durationSeconds := getFloat(data, "duration_seconds")
@@ -156,42 +161,89 @@ func FormatOrders(data map[string]any) string {
 
 	compact := isCompact()
 
-	var wID, wStatus, wSide, wTrigger, wInput, wCreated int
+	var wID, wStatus, wSide, wTrigger, wDist, wInput, wEst, wCreated int
 	if compact {
 		wID = 8
 		wStatus = 10
 		wSide = 5
 		wTrigger = 12
-		wInput = 10
+		wDist = 9
+		wInput = 14
 	} else {
 		wID = 10
 		wStatus = 12
 		wSide = 6
 		wTrigger = 16
-		wInput = 14
+		wDist = 15
+		wInput = 18
+		wEst = 12
 		wCreated = 12
 	}
 
+	// Only show the distance to trigger when at least one order carries a
+	// current price and the column fits, even in compact mode.
+	base := wID + wStatus + wSide + wTrigger + wInput + wCreated
+	showDist := false
+	if contentWidth() >= base+wDist {
+		for _, o := range orders {
+			if order, ok := o.(map[string]any); ok {
+				if _, _, ok := triggerDistance(order); ok {
+					showDist = true
+					break
+				}
+			}
+		}
+	}
+	if showDist {
+		base += wDist
+	}
+
+	// Only show the estimated value column when it fits and at least one
+	// order carries enough price data to convert its amount to USD.
+	showEst := false
+	if !compact && contentWidth() >= base+wEst {
+		for _, o := range orders {
+			if order, ok := o.(map[string]any); ok {
+				if _, ok := estimateOrderValue(order, orderAmount(order)); ok {
+					showEst = true
+					break
+				}
+			}
+		}
+	}
+
 	headerParts := []string{
 		lipgloss.NewStyle().Width(wID).Bold(true).Render("ID"),
 		lipgloss.NewStyle().Width(wStatus).Bold(true).Render("Status"),
 		lipgloss.NewStyle().Width(wSide).Bold(true).Render("Side"),
 		lipgloss.NewStyle().Width(wTrigger).Bold(true).Render("Trigger $"),
-		lipgloss.NewStyle().Width(wInput).Bold(true).Render("Amount"),
+	}
+	if showDist {
+		title := "To trigger"
+		if compact {
+			title = "Distance"
+		}
+		headerParts = append(headerParts, lipgloss.NewStyle().Width(wDist).Bold(true).Render(title))
+	}
+	headerParts = append(headerParts, lipgloss.NewStyle().Width(wInput).Bold(true).Render("Amount"))
+	if showEst {
+		headerParts = append(headerParts, lipgloss.NewStyle().Width(wEst).Bold(true).Render("Est. value"))
 	}
 	if !compact {
 		headerParts = append(headerParts, lipgloss.NewStyle().Width(wCreated).Bold(true).Render("Created"))
 	}
 	headerRow := lipgloss.JoinHorizontal(lipgloss.Top, headerParts...)
 
-	totalCols := wID + wStatus + wSide + wTrigger + wInput
-	if !compact {
-		totalCols += wCreated
+	totalCols := base
+	if showEst {
+		totalCols += wEst
 	}
 
 	var rows []string
-	rows = append(rows, headerRow)
-	rows = append(rows, sepLine(totalCols))
+	if !Accessible {
+		rows = append(rows, headerRow)
+		rows = append(rows, sepLine(totalCols))
+	}
 
 	maxRows := 10
 	showMore := len(orders) > maxRows
@@ -212,9 +264,9 @@ func FormatOrders(data map[string]any) string {
 		}
 
 		status := getString(order, "status")
-		side := getString(order, "side")
+		side := orderSide(order)
 		triggerPrice := getFloat(order, "trigger_price")
-		inputAmount := getFloat(order, "input_amount")
+		inputAmount := orderAmount(order)
 		createdAt := getString(order, "created_at")
 		if len(createdAt) > 10 {
 			createdAt = createdAt[:10]
@@ -226,20 +278,60 @@ func FormatOrders(data map[string]any) string {
 			triggerStr = ui.DimStyle.Render("—")
 		}
 
-		inputStr := FormatNumber(inputAmount)
+		inputStr := formatOrderAmount(order, inputAmount)
 		if inputAmount == 0 {
 			inputStr = ui.DimStyle.Render("—")
 		}
 
+		if Accessible {
+			estStr := ""
+			if est, ok := estimateOrderValue(order, inputAmount); ok {
+				estStr = "about " + FormatUSD(est)
+			}
+			triggerText := ""
+			if triggerPrice > 0 {
+				triggerText = triggerStr
+			}
+			amountText := ""
+			if inputAmount > 0 {
+				amountText = inputStr
+			}
+			distText := ""
+			if pct, _, ok := triggerDistance(order); ok {
+				distText = formatTriggerDistance(pct, true)
+			}
+			rows = append(rows, accessibleRecord("Order "+id, [][2]string{
+				{"Status", status},
+				{"Side", side},
+				{"Trigger price", triggerText},
+				{"Distance to trigger", distText},
+				{"Amount", amountText},
+				{"Estimated value", estStr},
+				{"Created", createdAt},
+			}))
+			continue
+		}
+
+		cells := OrderRow(order)
 		rowParts := []string{
-			lipgloss.NewStyle().Width(wID).Foreground(ui.ColorBright).Render(id),
-			lipgloss.NewStyle().Width(wStatus).Render(colorStatus(status)),
-			lipgloss.NewStyle().Width(wSide).Render(formatSide(side)),
-			lipgloss.NewStyle().Width(wTrigger).Render(triggerStr),
-			lipgloss.NewStyle().Width(wInput).Render(inputStr),
+			lipgloss.NewStyle().Width(wID).Render(cells.ID),
+			lipgloss.NewStyle().Width(wStatus).Render(cells.Status),
+			lipgloss.NewStyle().Width(wSide).Render(cells.Side),
+			lipgloss.NewStyle().Width(wTrigger).Render(cells.Trigger),
+		}
+		if showDist {
+			dist := cells.Distance
+			if compact {
+				dist = cells.DistanceShort
+			}
+			rowParts = append(rowParts, lipgloss.NewStyle().Width(wDist).Render(dist))
+		}
+		rowParts = append(rowParts, lipgloss.NewStyle().Width(wInput).Render(cells.Amount))
+		if showEst {
+			rowParts = append(rowParts, lipgloss.NewStyle().Width(wEst).Render(cells.Est))
 		}
 		if !compact {
-			rowParts = append(rowParts, lipgloss.NewStyle().Width(wCreated).Render(ui.DimStyle.Render(createdAt)))
+			rowParts = append(rowParts, lipgloss.NewStyle().Width(wCreated).Render(cells.Created))
 		}
 		row := lipgloss.JoinHorizontal(lipgloss.Top, rowParts...)
 		rows = append(rows, row)
@@ -250,14 +342,83 @@ func FormatOrders(data map[string]any) string {
 		rows = append(rows, ui.DimStyle.Render(fmt.Sprintf("...and %d more orders", remaining)))
 	}
 
-	content := lipgloss.JoinVertical(lipgloss.Left,
+	sections := []string{
 		header,
 		subtitle,
 		"",
 		strings.Join(rows, "\n"),
-	)
+	}
 
-	return ui.BoxBorder.Render(content)
+	// DCA and TWAP orders keep drawing capital over time; summarize what is
+	// still committed so it can be compared against available balances.
+	if orderType == "DCA" || orderType == "TWAP" {
+		if footer := FormatCommitments(orders); footer != "" {
+			if Accessible {
+				sections = append(sections, "", footer)
+			} else {
+				sections = append(sections, "", sepLine(totalCols), footer)
+			}
+		}
+	}
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+
+	return renderBox(ui.BoxBorder, content)
+}
+
+// OrderCells are the styled columns of one row of the orders table.
+type OrderCells struct {
+	ID      string
+	Status  string
+	Side    string
+	Trigger string
+	// Distance is how far the market is from the trigger, as "▼ -3.2%
+	// away"; DistanceShort leaves out "away".
+	Distance      string
+	DistanceShort string
+	Amount        string
+	Est           string
+	Created       string
+}
+
+// OrderRow renders the columns FormatOrders shows for one order, so other
+// tables of orders, like the dashboard's, look the same.
+func OrderRow(order map[string]any) OrderCells {
+	id := getString(order, "id")
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	createdAt := getString(order, "created_at")
+	if len(createdAt) > 10 {
+		createdAt = createdAt[:10]
+	}
+
+	cells := OrderCells{
+		ID:            lipgloss.NewStyle().Foreground(ui.ColorBright).Render(id),
+		Status:        colorStatus(getString(order, "status")),
+		Side:          formatSide(orderSide(order)),
+		Trigger:       ui.DimStyle.Render("—"),
+		Distance:      ui.DimStyle.Render("—"),
+		DistanceShort: ui.DimStyle.Render("—"),
+		Amount:        ui.DimStyle.Render("—"),
+		Est:           ui.DimStyle.Render("—"),
+		Created:       ui.DimStyle.Render(createdAt),
+	}
+	if trigger := getFloat(order, "trigger_price"); trigger != 0 {
+		cells.Trigger = smartFormatPrice(trigger)
+	}
+	if pct, _, ok := triggerDistance(order); ok {
+		cells.Distance = formatTriggerDistance(pct, true)
+		cells.DistanceShort = formatTriggerDistance(pct, false)
+	}
+	amount := orderAmount(order)
+	if amount != 0 {
+		cells.Amount = formatOrderAmount(order, amount)
+	}
+	if est, ok := estimateOrderValue(order, amount); ok {
+		cells.Est = ui.DimStyle.Render("≈ ") + FormatUSD(est)
+	}
+	return cells
 }
 
 // FormatOrderDetail renders a detailed view of a single order.
@@ -287,7 +448,7 @@ func FormatOrderDetail(data map[string]any) string {
 		lines = append(lines, labelStyle.Render("Chain")+chain)
 	}
 
-	side := getString(data, "side")
+	side := orderSide(data)
 	if side != "" {
 		lines = append(lines, labelStyle.Render("Side")+formatSide(side))
 	}
@@ -295,31 +456,39 @@ func FormatOrderDetail(data map[string]any) string {
 	inputToken := getString(data, "input_token")
 	outputToken := getString(data, "output_token")
 	if inputToken != "" {
-		lines = append(lines, labelStyle.Render("Input Token")+ui.DimStyle.Render(TruncateAddress(inputToken)))
+		lines = append(lines, labelStyle.Render("Input Token")+ui.DimStyle.Render(tokenLabel(inputToken)))
 	}
 	if outputToken != "" {
-		lines = append(lines, labelStyle.Render("Output Token")+ui.DimStyle.Render(TruncateAddress(outputToken)))
+		lines = append(lines, labelStyle.Render("Output Token")+ui.DimStyle.Render(tokenLabel(outputToken)))
 	}
 
 	inputAmount := getFloat(data, "input_amount")
 	if inputAmount > 0 {
-		lines = append(lines, labelStyle.Render("Input Amount")+FormatNumber(inputAmount))
+		lines = append(lines, labelStyle.Render("Input Amount")+formatOrderAmount(data, inputAmount))
 	}
 
 	triggerPrice := getFloat(data, "trigger_price")
 	if triggerPrice > 0 {
 		lines = append(lines, labelStyle.Render("Trigger Price")+FormatUSD(triggerPrice))
 	}
+	if pct, current, ok := triggerDistance(data); ok {
+		lines = append(lines, labelStyle.Render("Current Price")+FormatUSD(current))
+		lines = append(lines, labelStyle.Render("To Trigger")+formatTriggerDistance(pct, true))
+		// The bar fills as the market closes in on the trigger.
+		closeness := math.Min(current, triggerPrice) / math.Max(current, triggerPrice)
+		lines = append(lines, labelStyle.Render("")+ui.DimStyle.Render("now ")+
+			ProgressBar(closeness, 1, 20)+ui.DimStyle.Render(" trigger"))
+	}
 
 	// DCA / TWAP fields
 	totalAmount := getFloat(data, "total_amount")
 	if totalAmount > 0 {
-		lines = append(lines, labelStyle.Render("Total Amount")+FormatNumber(totalAmount))
+		lines = append(lines, labelStyle.Render("Total Amount")+formatOrderAmount(data, totalAmount))
 	}
 
 	amountPerInterval := getFloat(data, "amount_per_interval")
 	if amountPerInterval > 0 {
-		lines = append(lines, labelStyle.Render("Per Interval")+FormatNumber(amountPerInterval))
+		lines = append(lines, labelStyle.Render("Per Interval")+formatOrderAmount(data, amountPerInterval))
 	}
 
 	totalIntervals := getFloat(data, "total_intervals")
@@ -334,7 +503,11 @@ func FormatOrderDetail(data map[string]any) string {
 
 	amountPerSlice := getFloat(data, "amount_per_slice")
 	if amountPerSlice > 0 {
-		lines = append(lines, labelStyle.Render("Per Slice")+FormatNumber(amountPerSlice))
+		lines = append(lines, labelStyle.Render("Per Slice")+formatOrderAmount(data, amountPerSlice))
+	}
+
+	if est, ok := estimateOrderValue(data, orderAmount(data)); ok {
+		lines = append(lines, labelStyle.Render("Est. Value")+ui.DimStyle.Render("≈ ")+FormatUSD(est))
 	}
 
 	intervalSeconds := getFloat(data, "interval_seconds")
@@ -582,6 +755,52 @@ func colorPositionStatus(status string) string {
 	}
 }
 
+// triggerDistance returns how far an order's trigger price is from the
+// current price, in percent of the current price, and the current price.
+// ok is false when the order carries no trigger or current price.
+func triggerDistance(order map[string]any) (pct, current float64, ok bool) {
+	trigger := getFloat(order, "trigger_price")
+	current = firstFloat(order, "current_price", "current_price_usd", "market_price")
+	if trigger <= 0 || current <= 0 {
+		return 0, 0, false
+	}
+	return (trigger - current) / current * 100, current, true
+}
+
+// formatTriggerDistance renders a distance to trigger with an arrow for
+// whether the trigger is above or below the market: "▲ +4.1% away" in
+// green, "▼ -3.2% away" in red. long adds "away".
+func formatTriggerDistance(pct float64, long bool) string {
+	if Accessible {
+		if pct >= 0 {
+			return fmt.Sprintf("%.1f%% above market", pct)
+		}
+		return fmt.Sprintf("%.1f%% below market", -pct)
+	}
+	up, down := "▲", "▼"
+	if Plain {
+		up, down = "^", "v"
+	}
+	arrow, color := up, ui.ColorGreen
+	if pct < 0 {
+		arrow, color = down, ui.ColorRed
+	}
+	text := fmt.Sprintf("%s %+.1f%%", arrow, pct)
+	if long {
+		text += " away"
+	}
+	return lipgloss.NewStyle().Foreground(color).Render(text)
+}
+
+// TriggerToken returns the token whose price an order's trigger refers to:
+// the token being sold for a sell order, the one being bought otherwise.
+func TriggerToken(order map[string]any) string {
+	if orderSide(order) == "sell" {
+		return getString(order, "input_token")
+	}
+	return getString(order, "output_token")
+}
+
 // formatSide returns a styled buy/sell side string.
 func formatSide(side string) string {
 	lower := strings.ToLower(side)
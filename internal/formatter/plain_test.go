@@ -0,0 +1,77 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+const plainPortfolioJSON = `{
+	"total_value_usd": 4750.5,
+	"pnl_usd": -120.25,
+	"native_balances": [
+		{"symbol":"SOL","chain_name":"Solana","balance":10,"balance_usd":1500},
+		{"symbol":"ETH","chain_name":"Ethereum","balance":1,"balance_usd":3000}
+	],
+	"positions": [
+		{"symbol":"POPCAT","value_usd":"250.00","pnl_percent":12.5,"pnl_usd":27.8,"chain":"solana"},
+		{"symbol":"WIF","value_usd":"0.50","pnl_percent":-40,"chain":"solana"}
+	]
+}`
+
+// Under NO_COLOR the portfolio carries no escape codes at all, where a
+// color terminal gets them.
+func TestFormatPortfolioNoColor(t *testing.T) {
+	data := decode(t, plainPortfolioJSON)
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		ui.SetNoColor(false)
+		Plain = false
+	})
+	if out := FormatPortfolio(data); !strings.Contains(out, "\x1b[") {
+		t.Fatalf("no escape codes even in color:\n%s", out)
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	ui.SetNoColor(false)
+	Plain = ui.NoColor()
+	out := FormatPortfolio(data)
+	if i := strings.IndexByte(out, 0x1b); i >= 0 {
+		t.Errorf("ESC at byte %d under NO_COLOR:\n%q", i, out)
+	}
+	for _, want := range []string{"POPCAT", "(Solana)", "(Ethereum)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("plain output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPlainGlyphs(t *testing.T) {
+	t.Cleanup(func() { Plain = false })
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8}
+	for _, tc := range []struct {
+		plain      bool
+		spark, bar string
+	}{
+		{false, "▁▂▃▄▅▆▇█", "█████░░░░░"},
+		{true, "_.,-~=*#", "#####-----"},
+	} {
+		Plain = tc.plain
+		if got := Sparkline(values); got != tc.spark {
+			t.Errorf("plain %v: Sparkline = %q, want %q", tc.plain, got, tc.spark)
+		}
+		if got := Sparkline([]float64{3, 3}); got != string([]rune(tc.spark)[3])+string([]rune(tc.spark)[3]) {
+			t.Errorf("plain %v: flat Sparkline = %q", tc.plain, got)
+		}
+		if got := ProgressBar(5, 10, 10); got != tc.bar {
+			t.Errorf("plain %v: ProgressBar = %q, want %q", tc.plain, got, tc.bar)
+		}
+		if got := ProgressBar(1, 0, 4); got != strings.Repeat(string([]rune(tc.bar)[9]), 4) {
+			t.Errorf("plain %v: empty ProgressBar = %q", tc.plain, got)
+		}
+	}
+}
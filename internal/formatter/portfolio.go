@@ -103,13 +103,14 @@ func FormatPortfolio(data map[string]any) string {
 		}
 		headerParts = append(headerParts, lipgloss.NewStyle().Width(colPnl).Bold(true).Render("PnL"))
 		headerRow := lipgloss.JoinHorizontal(lipgloss.Top, headerParts...)
-		rows = append(rows, headerRow)
 
 		totalCols := colSym + colVal + colPnl
 		if !compact {
 			totalCols += colAlloc + colPrice
 		}
-		rows = append(rows, sepLine(totalCols))
+		if !Accessible {
+			rows = append(rows, headerRow, sepLine(totalCols))
+		}
 
 		for _, t := range positions {
 			token, ok := t.(map[string]any)
@@ -148,6 +149,16 @@ func FormatPortfolio(data map[string]any) string {
 				allocation = value / totalValue
 			}
 
+			if Accessible {
+				rows = append(rows, accessibleRecord(symbol, [][2]string{
+					{"Value", FormatUSD(value)},
+					{"Allocation", ProgressBar(allocation, 1.0, 10)},
+					{"Price", FormatUSD(price)},
+					{"PnL", FormatPercent(pnlPct)},
+				}))
+				continue
+			}
+
 			rowParts := []string{
 				lipgloss.NewStyle().Width(colSym).Foreground(ui.ColorBright).Render(symbol),
 				lipgloss.NewStyle().Width(colVal).Render(FormatUSD(value)),
@@ -175,6 +186,8 @@ func FormatPortfolio(data map[string]any) string {
 			rows = append(rows, "")
 		}
 		rows = append(rows, lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true).Render("Native Balances"))
+		var hiddenCount int
+		var hiddenUSD float64
 		for _, nb := range nativeBalances {
 			bal, ok := nb.(map[string]any)
 			if !ok {
@@ -184,6 +197,22 @@ func FormatPortfolio(data map[string]any) string {
 			balance := getFloat(bal, "balance")
 			balUSD := getFloat(bal, "balance_usd")
 			chainName := getString(bal, "chain_name")
+			if chainName != "" && ChainFilter != nil && !ChainFilter(chainName) {
+				hiddenCount++
+				hiddenUSD += balUSD
+				continue
+			}
+			if Accessible {
+				title := symbol
+				if chainName != "" {
+					title += " on " + chainName
+				}
+				rows = append(rows, accessibleRecord(title, [][2]string{
+					{"Balance", FormatNumber(balance)},
+					{"Value", FormatUSD(balUSD)},
+				}))
+				continue
+			}
 			chain := ""
 			if chainName != "" {
 				chain = " (" + chainName + ")"
@@ -196,6 +225,11 @@ func FormatPortfolio(data map[string]any) string {
 			)
 			rows = append(rows, row)
 		}
+		// Summarise disabled chains rather than dropping them so the
+		// breakdown still accounts for the whole total.
+		if hiddenCount > 0 {
+			rows = append(rows, ui.DimStyle.Render(fmt.Sprintf("  %d on disabled chains  %s", hiddenCount, FormatUSD(hiddenUSD))))
+		}
 	}
 
 	holdingsTable := strings.Join(rows, "\n")
@@ -210,7 +244,7 @@ func FormatPortfolio(data map[string]any) string {
 	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
-	return ui.GoldBoxBorder.Render(content)
+	return renderBox(ui.GoldBoxBorder, content)
 }
 
 // getFloat safely extracts a float64 from a map with a string key.
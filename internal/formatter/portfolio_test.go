@@ -0,0 +1,44 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+// Native balances on disabled chains fold into one dim line, so the
+// breakdown still adds up to the total.
+func TestPortfolioChainFilter(t *testing.T) {
+	data := map[string]any{
+		"total_value_usd": 5100.0,
+		"native_balances": []any{
+			map[string]any{"symbol": "SOL", "chain_name": "Solana", "balance": 10.0, "balance_usd": 1500.0},
+			map[string]any{"symbol": "ETH", "chain_name": "Ethereum", "balance": 1.0, "balance_usd": 3000.0},
+			map[string]any{"symbol": "BNB", "chain_name": "BSC", "balance": 1.0, "balance_usd": 600.0},
+		},
+	}
+	defer func() { ChainFilter = nil }()
+
+	out := FormatPortfolio(data)
+	for _, want := range []string{"(Solana)", "(Ethereum)", "(BSC)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("unfiltered output lacks %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "disabled chains") {
+		t.Errorf("unfiltered output has a disabled chains line:\n%s", out)
+	}
+
+	ChainFilter = func(chain string) bool { return chain == "Solana" }
+	out = FormatPortfolio(data)
+	if !strings.Contains(out, "(Solana)") {
+		t.Errorf("filtered output lacks the enabled chain:\n%s", out)
+	}
+	for _, hidden := range []string{"(Ethereum)", "(BSC)"} {
+		if strings.Contains(out, hidden) {
+			t.Errorf("filtered output shows %s:\n%s", hidden, out)
+		}
+	}
+	if want := "2 on disabled chains  " + FormatUSD(3600); !strings.Contains(out, want) {
+		t.Errorf("filtered output lacks %q:\n%s", want, out)
+	}
+}
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -148,20 +149,104 @@ func FormatAuditToken(data map[string]any) string {
 	// Pick border based on risk level
 	switch strings.ToUpper(riskLevel) {
 	case "HIGH":
-		return ui.ErrorBoxBorder.Render(content)
+		return renderBox(ui.ErrorBoxBorder, content)
 	case "MEDIUM":
-		return ui.BoxBorder.Render(content)
+		return renderBox(ui.BoxBorder, content)
 	default:
-		return ui.BoxBorder.Render(content)
+		return renderBox(ui.BoxBorder, content)
 	}
 }
 
+// AuditFailure is a token in a batch audit whose audit errored. Its risk is
+// unknown, which must never be read as safe.
+type AuditFailure struct {
+	Token  string
+	Reason string
+}
+
+// SplitAuditBatch separates the successful audits in an audit_tokens_batch
+// response from the failed ones. Failures come either as entries in
+// "audits" carrying an "error" (or "success": false), or in a separate
+// "errors"/"failed" field that is a list of { "token", "error" } objects or
+// a map of token to reason.
+func SplitAuditBatch(data map[string]any) (audits []map[string]any, failed []AuditFailure) {
+	raw, _ := data["audits"].([]any)
+	for _, a := range raw {
+		audit, ok := a.(map[string]any)
+		if !ok {
+			continue
+		}
+		success, hasSuccess := getBool(audit, "success")
+		reason := auditErrorReason(audit)
+		if reason != "" || (hasSuccess && !success) {
+			if reason == "" {
+				reason = "audit failed"
+			}
+			failed = append(failed, AuditFailure{Token: getString(audit, "token"), Reason: reason})
+			continue
+		}
+		audits = append(audits, audit)
+	}
+
+	for _, key := range []string{"errors", "failed"} {
+		switch errs := data[key].(type) {
+		case []any:
+			for _, e := range errs {
+				switch v := e.(type) {
+				case map[string]any:
+					reason := auditErrorReason(v)
+					if reason == "" {
+						reason = "audit failed"
+					}
+					failed = append(failed, AuditFailure{Token: getString(v, "token"), Reason: reason})
+				case string:
+					failed = append(failed, AuditFailure{Token: v, Reason: "audit failed"})
+				}
+			}
+		case map[string]any:
+			tokens := make([]string, 0, len(errs))
+			for token := range errs {
+				tokens = append(tokens, token)
+			}
+			sort.Strings(tokens)
+			for _, token := range tokens {
+				reason := "audit failed"
+				switch v := errs[token].(type) {
+				case string:
+					reason = v
+				case map[string]any:
+					if r := auditErrorReason(v); r != "" {
+						reason = r
+					}
+				}
+				failed = append(failed, AuditFailure{Token: token, Reason: reason})
+			}
+		}
+	}
+	return audits, failed
+}
+
+// auditErrorReason returns the error message on a failed audit entry.
+func auditErrorReason(m map[string]any) string {
+	if e, ok := m["error"].(map[string]any); ok {
+		return getString(e, "message")
+	}
+	for _, key := range []string{"error", "message", "reason"} {
+		if s, ok := m[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
 // FormatAuditBatch renders a batch of token audits as a table with risk-colored rows.
 // Response: { "chain", "count", "audits": [{ "token", "is_honeypot", "is_mintable",
 // "top10_holders_percent", "lp_locked", "risk_level" }] }
+// Tokens whose audit failed get a row with the reason and a grey risk cell
+// rather than being dropped.
 func FormatAuditBatch(data map[string]any) string {
-	audits, _ := data["audits"].([]any)
-	if len(audits) == 0 {
+	audits, failed := SplitAuditBatch(data)
+	if len(audits) == 0 && len(failed) == 0 {
 		return ui.DimStyle.Render("No audit data available.")
 	}
 
@@ -181,6 +266,9 @@ func FormatAuditBatch(data map[string]any) string {
 		colLP = 10
 		colRisk = 10
 	}
+	if len(failed) > 0 {
+		colRisk = 14
+	}
 
 	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ui.ColorBright)
 
@@ -207,12 +295,7 @@ func FormatAuditBatch(data map[string]any) string {
 	rows = append(rows, header)
 	rows = append(rows, sepLine(totalCols))
 
-	for _, a := range audits {
-		audit, ok := a.(map[string]any)
-		if !ok {
-			continue
-		}
-
+	for _, audit := range audits {
 		token := TruncateAddress(getString(audit, "token"))
 		risk := getString(audit, "risk_level")
 
@@ -269,11 +352,28 @@ func FormatAuditBatch(data map[string]any) string {
 		rows = append(rows, row)
 	}
 
+	reasonWidth := totalCols - colToken - colRisk
+	for _, f := range failed {
+		reason := f.Reason
+		if w := reasonWidth - 2; len(reason) > w && w > 3 {
+			reason = reason[:w-3] + "..."
+		}
+		rowParts := []string{
+			lipgloss.NewStyle().Width(colToken).Foreground(ui.ColorBright).Render(TruncateAddress(f.Token)),
+			lipgloss.NewStyle().Width(reasonWidth).Render(ui.DimStyle.Render(reason)),
+			lipgloss.NewStyle().Width(colRisk).Render(ui.DimStyle.Render("AUDIT FAILED")),
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, rowParts...))
+	}
+
 	titleText := "BATCH AUDIT"
 	chain := getString(data, "chain")
 	if chain != "" {
 		titleText += " (" + chain + ")"
 	}
+	if len(failed) > 0 {
+		titleText += fmt.Sprintf(" — %d ok, %d failed", len(audits), len(failed))
+	}
 	title := ui.TitleStyle.Render(titleText)
 
 	content := lipgloss.JoinVertical(lipgloss.Left,
@@ -335,6 +435,12 @@ func FormatTokenVerified(data map[string]any) string {
 
 // boolCheck renders a checkmark or cross line for a boolean security check.
 func boolCheck(pass bool, passLabel, failLabel string) string {
+	if Accessible {
+		if pass {
+			return "  pass: " + passLabel
+		}
+		return "  fail: " + failLabel
+	}
 	if pass {
 		return fmt.Sprintf("  %s  %s", ui.SuccessStyle.Render("\u2713"), passLabel)
 	}
@@ -344,6 +450,9 @@ func boolCheck(pass bool, passLabel, failLabel string) string {
 // renderRiskBadge returns a styled risk level badge.
 func renderRiskBadge(level string) string {
 	upper := strings.ToUpper(level)
+	if Accessible {
+		return "Risk: " + strings.ToLower(upper)
+	}
 	switch upper {
 	case "LOW":
 		return lipgloss.NewStyle().
@@ -0,0 +1,114 @@
+package formatter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const auditOK = `{"token":"TokA","risk_level":"LOW","is_honeypot":false,"is_mintable":false,"top10_holders_percent":12,"lp_locked":true},
+	{"token":"TokB","risk_level":"HIGH","is_honeypot":true,"is_mintable":true,"top10_holders_percent":80,"lp_locked":false}`
+
+func TestSplitAuditBatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		ok     []string
+		failed []AuditFailure
+	}{
+		{
+			name: "inline errors",
+			json: `{"audits":[` + auditOK + `,
+				{"token":"TokC","error":"rpc timeout"},
+				{"token":"TokD","error":{"message":"not indexed"}},
+				{"token":"TokE","success":false}]}`,
+			ok: []string{"TokA", "TokB"},
+			failed: []AuditFailure{
+				{"TokC", "rpc timeout"},
+				{"TokD", "not indexed"},
+				{"TokE", "audit failed"},
+			},
+		},
+		{
+			name: "errors list",
+			json: `{"audits":[` + auditOK + `],"errors":[
+				{"token":"TokF","error":"rate limited"},
+				{"token":"TokG","reason":"unsupported chain"},
+				"TokH"]}`,
+			ok: []string{"TokA", "TokB"},
+			failed: []AuditFailure{
+				{"TokF", "rate limited"},
+				{"TokG", "unsupported chain"},
+				{"TokH", "audit failed"},
+			},
+		},
+		{
+			name: "failed map",
+			json: `{"audits":[` + auditOK + `],"failed":{
+				"TokZ":"no liquidity",
+				"TokY":{"message":"boom"},
+				"TokX":true}}`,
+			ok: []string{"TokA", "TokB"},
+			failed: []AuditFailure{
+				{"TokX", "audit failed"},
+				{"TokY", "boom"},
+				{"TokZ", "no liquidity"},
+			},
+		},
+		{
+			name: "all ok",
+			json: `{"audits":[` + auditOK + `,{"token":"TokC","success":true}]}`,
+			ok:   []string{"TokA", "TokB", "TokC"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audits, failed := SplitAuditBatch(decode(t, tt.json))
+			var ok []string
+			for _, a := range audits {
+				ok = append(ok, getString(a, "token"))
+			}
+			if !reflect.DeepEqual(ok, tt.ok) {
+				t.Errorf("audits = %v, want %v", ok, tt.ok)
+			}
+			if !reflect.DeepEqual(failed, tt.failed) {
+				t.Errorf("failed = %v, want %v", failed, tt.failed)
+			}
+		})
+	}
+}
+
+func TestFormatAuditBatch(t *testing.T) {
+	TermWidth = 120
+	defer func() { TermWidth = 80 }()
+
+	data := decode(t, `{"chain":"solana","audits":[`+auditOK+`,
+		{"token":"TokC","error":"rpc timeout"},
+		{"token":"TokD","success":false}],
+		"failed":{"TokE":"no liquidity"}}`)
+	out := FormatAuditBatch(data)
+	for _, want := range []string{"BATCH AUDIT (solana) — 2 ok, 3 failed", "TokA", "TokB", "TokC", "rpc timeout", "TokD", "TokE", "no liquidity"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("batch lacks %q:\n%s", want, out)
+		}
+	}
+	if n := strings.Count(out, "AUDIT FAILED"); n != 3 {
+		t.Errorf("%d AUDIT FAILED rows, want 3:\n%s", n, out)
+	}
+	if got, want := FormatToolPreview("audit_tokens_batch", data), "2 tokens audited, 3 failed"; got != want {
+		t.Errorf("preview = %q, want %q", got, want)
+	}
+
+	clean := decode(t, `{"chain":"solana","audits":[`+auditOK+`]}`)
+	out = FormatAuditBatch(clean)
+	if strings.Contains(out, "failed") || strings.Contains(out, "AUDIT FAILED") {
+		t.Errorf("clean batch mentions failures:\n%s", out)
+	}
+	if got, want := FormatToolPreview("audit_tokens_batch", clean), "2 tokens audited"; got != want {
+		t.Errorf("preview = %q, want %q", got, want)
+	}
+
+	if out := FormatAuditBatch(decode(t, `{"audits":[]}`)); !strings.Contains(out, "No audit data available.") {
+		t.Errorf("empty batch = %q", out)
+	}
+}
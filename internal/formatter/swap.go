@@ -2,21 +2,163 @@ package formatter
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/tradeboba/boba-cli/internal/ui"
 )
 
-// FormatSwapQuote renders a swap quote showing the from/to amounts, price
-// impact, and estimated gas cost.
+// swapQuoteKeys lists, for each quote field, the keys the backend has used
+// for it, in order of preference.
+var swapQuoteKeys = map[string][]string{
+	"from_amount":  {"from_amount", "amount_in", "in_amount", "input_amount", "inAmount"},
+	"to_amount":    {"to_amount", "amount_out", "out_amount", "output_amount", "outAmount", "expected_output"},
+	"from_symbol":  {"from_symbol", "input_symbol", "from_token_symbol", "in_symbol"},
+	"to_symbol":    {"to_symbol", "output_symbol", "to_token_symbol", "out_symbol"},
+	"price_impact": {"price_impact", "price_impact_pct", "price_impact_percent", "priceImpactPct"},
+	"gas_estimate": {"gas_estimate", "gas_usd", "estimated_gas_usd", "gas_cost_usd", "network_fee_usd"},
+	"slippage":     {"slippage", "slippage_pct", "slippage_percent", "max_slippage"},
+	"route":        {"route", "venue", "dex", "aggregator", "source"},
+}
+
+// swapQuote is a quote with its fields resolved from whichever keys the
+// response used. The has* flags tell a zero apart from a missing field.
+type swapQuote struct {
+	FromAmount, ToAmount float64
+	FromSymbol, ToSymbol string
+	Route                string
+
+	PriceImpact, Gas, Slippage     float64
+	HasImpact, HasGas, HasSlippage bool
+}
+
+// parseSwapQuote resolves a quote response. Fields nested under "quote" take
+// precedence over the top level.
+func parseSwapQuote(data map[string]any) swapQuote {
+	sources := []map[string]any{data}
+	if nested, ok := data["quote"].(map[string]any); ok {
+		sources = []map[string]any{nested, data}
+	}
+
+	var q swapQuote
+	q.FromAmount, _ = quoteFloat(sources, "from_amount")
+	q.ToAmount, _ = quoteFloat(sources, "to_amount")
+	q.FromSymbol = quoteString(sources, "from_symbol")
+	q.ToSymbol = quoteString(sources, "to_symbol")
+	q.Route = quoteRoute(sources)
+	q.PriceImpact, q.HasImpact = quoteFloat(sources, "price_impact")
+	q.Gas, q.HasGas = quoteFloat(sources, "gas_estimate")
+	q.Slippage, q.HasSlippage = quoteFloat(sources, "slippage")
+	if !q.HasSlippage {
+		if bps, ok := quoteFloatKeys(sources, "slippage_bps", "slippageBps"); ok {
+			q.Slippage, q.HasSlippage = bps/100, true
+		}
+	}
+	return q
+}
+
+func quoteFloat(sources []map[string]any, field string) (float64, bool) {
+	return quoteFloatKeys(sources, swapQuoteKeys[field]...)
+}
+
+// quoteFloatKeys returns the first value under any of the keys that parses
+// as a number, including string-encoded ones.
+func quoteFloatKeys(sources []map[string]any, keys ...string) (float64, bool) {
+	for _, m := range sources {
+		for _, k := range keys {
+			if f, ok := toFloat64(m[k]); ok {
+				return f, true
+			}
+			if str, ok := m[k].(string); ok {
+				str = strings.ReplaceAll(strings.TrimSuffix(strings.TrimSpace(str), "%"), ",", "")
+				if f, err := strconv.ParseFloat(str, 64); err == nil {
+					return f, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func quoteString(sources []map[string]any, field string) string {
+	for _, m := range sources {
+		for _, k := range swapQuoteKeys[field] {
+			if s, ok := m[k].(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// quoteRoute returns the route or venue, joining multi-hop routes given as a
+// list of names or of steps.
+func quoteRoute(sources []map[string]any) string {
+	for _, m := range sources {
+		for _, k := range swapQuoteKeys["route"] {
+			switch v := m[k].(type) {
+			case string:
+				if v != "" {
+					return v
+				}
+			case []any:
+				var hops []string
+				for _, hop := range v {
+					switch h := hop.(type) {
+					case string:
+						hops = append(hops, h)
+					case map[string]any:
+						for _, nk := range []string{"name", "dex", "venue", "label"} {
+							if s := getString(h, nk); s != "" {
+								hops = append(hops, s)
+								break
+							}
+						}
+					}
+				}
+				if len(hops) > 0 {
+					return strings.Join(hops, " → ")
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// MaxPriceImpact is the price impact, in percent, from which a quote's is
+// shown as a warning. Set by the CLI from the config.
+var MaxPriceImpact = 5.0
+
+// SwapPriceImpact returns the price impact of a quote, in percent, and
+// whether the quote has one.
+func SwapPriceImpact(data map[string]any) (float64, bool) {
+	q := parseSwapQuote(data)
+	return q.PriceImpact, q.HasImpact
+}
+
+// formatPriceImpact highlights an impact of MaxPriceImpact or more in red,
+// and one of 1% or more in gold. Backends report it with either sign.
+func formatPriceImpact(impact float64) string {
+	size := math.Abs(impact)
+	text := fmt.Sprintf("%.2f%%", size)
+	switch {
+	case size >= MaxPriceImpact:
+		if Accessible {
+			return text + " (high)"
+		}
+		return lipgloss.NewStyle().Foreground(ui.ColorRed).Bold(true).Render("⚠ " + text)
+	case size >= 1:
+		return ui.GoldStyle.Render(text)
+	}
+	return text
+}
+
+// FormatSwapQuote renders a swap quote showing the from/to amounts, and the
+// price impact, route, gas cost and slippage when the quote has them.
 func FormatSwapQuote(data map[string]any) string {
-	fromAmount := getFloat(data, "from_amount")
-	fromSymbol := getString(data, "from_symbol")
-	toAmount := getFloat(data, "to_amount")
-	toSymbol := getString(data, "to_symbol")
-	priceImpact := getFloat(data, "price_impact")
-	gasEstimate := getFloat(data, "gas_estimate")
+	q := parseSwapQuote(data)
 
 	title := ui.TitleStyle.Render("SWAP QUOTE")
 
@@ -24,32 +166,39 @@ func FormatSwapQuote(data map[string]any) string {
 	symbolStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba)
 
 	fromLine := fmt.Sprintf("FROM:  %s %s",
-		amountStyle.Render(FormatNumber(fromAmount)),
-		symbolStyle.Render(fromSymbol),
+		amountStyle.Render(FormatNumber(q.FromAmount)),
+		symbolStyle.Render(q.FromSymbol),
 	)
 
 	arrow := lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render("  →")
 
 	toLine := fmt.Sprintf("TO:    %s %s",
-		amountStyle.Render(FormatNumber(toAmount)),
-		symbolStyle.Render(toSymbol),
+		amountStyle.Render(FormatNumber(q.ToAmount)),
+		symbolStyle.Render(q.ToSymbol),
 	)
 
 	labelStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Width(16)
 
-	impactLine := labelStyle.Render("Price Impact") + FormatPercent(priceImpact)
-	gasLine := labelStyle.Render("Est. Gas") + FormatUSD(gasEstimate)
+	var details []string
+	if q.HasImpact {
+		details = append(details, labelStyle.Render("Price Impact")+formatPriceImpact(q.PriceImpact))
+	}
+	if q.Route != "" {
+		details = append(details, labelStyle.Render("Route")+ui.DimStyle.Render(q.Route))
+	}
+	if q.HasGas {
+		details = append(details, labelStyle.Render("Est. Gas")+FormatUSD(q.Gas))
+	}
+	if q.HasSlippage {
+		details = append(details, labelStyle.Render("Max Slippage")+fmt.Sprintf("%.2f%%", q.Slippage))
+	}
+
+	lines := []string{title, "", fromLine, arrow, toLine}
+	if len(details) > 0 {
+		lines = append(append(lines, ""), details...)
+	}
 
-	content := lipgloss.JoinVertical(lipgloss.Left,
-		title,
-		"",
-		fromLine,
-		arrow,
-		toLine,
-		"",
-		impactLine,
-		gasLine,
-	)
+	content := lipgloss.JoinVertical(lipgloss.Left, lines...)
 
 	return ui.BoxBorder.Render(content)
 }
@@ -77,6 +226,16 @@ func FormatTradeResult(data map[string]any) string {
 	return formatTradeSuccess(data)
 }
 
+// TradeTxHash returns the transaction hash of a trade result, or "".
+func TradeTxHash(data map[string]any) string {
+	if inner, ok := data["data"].(map[string]any); ok {
+		if hash := TradeTxHash(inner); hash != "" {
+			return hash
+		}
+	}
+	return pickString(data, "tx_hash", "txHash", "hash", "transaction_hash", "signature")
+}
+
 // formatTradeSuccess renders a successful trade with green styling.
 func formatTradeSuccess(data map[string]any) string {
 	header := lipgloss.NewStyle().
@@ -87,16 +246,10 @@ func formatTradeSuccess(data map[string]any) string {
 	var lines []string
 	lines = append(lines, header, "")
 
-	txHash := getString(data, "tx_hash")
-	if txHash == "" {
-		txHash = getString(data, "hash")
-	}
-	if txHash == "" {
-		txHash = getString(data, "transaction_hash")
-	}
-	if txHash != "" {
+	if txHash := TradeTxHash(data); txHash != "" {
 		labelStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true).Width(14)
-		lines = append(lines, labelStyle.Render("Tx Hash")+ui.DimStyle.Render(TruncateAddress(txHash)))
+		url, _ := ExplorerTxURL(chainOf(data), txHash)
+		lines = append(lines, labelStyle.Render("Tx Hash")+hyperlink(ui.DimStyle.Render(TruncateAddress(txHash)), url))
 	}
 
 	fromAmount := getFloat(data, "from_amount")
@@ -0,0 +1,105 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+// The shapes get_swap_price and get_swap_quote have answered with.
+func TestParseSwapQuote(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		data map[string]any
+		want swapQuote
+	}{
+		{
+			name: "canonical",
+			data: map[string]any{"from_amount": 1.0, "from_symbol": "SOL", "to_amount": 150.25, "to_symbol": "USDC"},
+			want: swapQuote{FromAmount: 1, ToAmount: 150.25, FromSymbol: "SOL", ToSymbol: "USDC"},
+		},
+		{
+			name: "input/output with amount_out",
+			data: map[string]any{"amount_in": 2.0, "input_symbol": "ETH", "amount_out": 6400.0, "output_symbol": "USDC"},
+			want: swapQuote{FromAmount: 2, ToAmount: 6400, FromSymbol: "ETH", ToSymbol: "USDC"},
+		},
+		{
+			name: "out_amount as a string",
+			data: map[string]any{"in_amount": "0.5", "in_symbol": "SOL", "out_amount": "1,234.5", "out_symbol": "BONK"},
+			want: swapQuote{FromAmount: 0.5, ToAmount: 1234.5, FromSymbol: "SOL", ToSymbol: "BONK"},
+		},
+		{
+			name: "nested quote wins over the envelope",
+			data: map[string]any{
+				"success":   true,
+				"to_amount": 1.0,
+				"quote": map[string]any{
+					"inAmount": "100", "from_token_symbol": "USDC",
+					"outAmount": "0.66", "to_token_symbol": "SOL",
+					"priceImpactPct": "0.12%", "slippageBps": 50.0,
+					"route": []any{map[string]any{"dex": "Raydium"}, map[string]any{"name": "Orca"}},
+				},
+			},
+			want: swapQuote{
+				FromAmount: 100, ToAmount: 0.66, FromSymbol: "USDC", ToSymbol: "SOL",
+				Route:       "Raydium → Orca",
+				PriceImpact: 0.12, HasImpact: true,
+				Slippage: 0.5, HasSlippage: true,
+			},
+		},
+		{
+			name: "details at the top level",
+			data: map[string]any{
+				"output_amount": 42.0, "expected_output": 41.0, "to_symbol": "PEPE",
+				"price_impact": -6.5, "gas_usd": "0.42", "max_slippage": 1.0, "venue": "Uniswap v3",
+			},
+			want: swapQuote{
+				ToAmount: 42, ToSymbol: "PEPE", Route: "Uniswap v3",
+				PriceImpact: -6.5, HasImpact: true,
+				Gas: 0.42, HasGas: true,
+				Slippage: 1, HasSlippage: true,
+			},
+		},
+		{
+			name: "zero impact is still an impact",
+			data: map[string]any{"to_amount": 1.0, "price_impact": 0.0},
+			want: swapQuote{ToAmount: 1, HasImpact: true},
+		},
+		{
+			name: "empty",
+			data: map[string]any{},
+		},
+	} {
+		if got := parseSwapQuote(tc.data); got != tc.want {
+			t.Errorf("%s:\n got %+v\nwant %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestFormatSwapQuote(t *testing.T) {
+	data := map[string]any{"quote": map[string]any{
+		"amount_in": 1.0, "input_symbol": "SOL", "amount_out": "150.25", "output_symbol": "USDC",
+		"price_impact_pct": 6.0, "estimated_gas_usd": 0.01, "slippage_percent": 0.5, "dex": "Jupiter",
+	}}
+	out := FormatSwapQuote(data)
+	for _, want := range []string{"SWAP QUOTE", "1.00 SOL", "150.25 USDC", "Price Impact", "6.00%", "Route", "Jupiter", "Est. Gas", "Max Slippage", "0.50%"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("quote lacks %q:\n%s", want, out)
+		}
+	}
+
+	for _, tool := range []string{"get_swap_price", "get_swap_quote"} {
+		if got, want := FormatToolPreview(tool, data), "SOL -> 150.25 USDC"; got != want {
+			t.Errorf("%s preview = %q, want %q", tool, got, want)
+		}
+	}
+	if got := FormatToolPreview("get_swap_price", map[string]any{"amount_out": 3.0}); got != "Swap quote ready" {
+		t.Errorf("preview without symbols = %q", got)
+	}
+
+	plain := FormatSwapQuote(map[string]any{"from_amount": 1.0, "from_symbol": "SOL", "to_amount": 2.0, "to_symbol": "X"})
+	for _, absent := range []string{"Price Impact", "Route", "Est. Gas", "Max Slippage"} {
+		if strings.Contains(plain, absent) {
+			t.Errorf("quote without %s shows it:\n%s", absent, plain)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package formatter
+
+import (
+	"strings"
+	"testing"
+)
+
+// fakeSymbols answers from a map and records every lookup.
+type fakeSymbols struct {
+	known   map[string]string
+	lookups []string
+}
+
+func (f *fakeSymbols) Symbol(address string) (string, bool) {
+	f.lookups = append(f.lookups, address)
+	s, ok := f.known[address]
+	return s, ok
+}
+
+const (
+	pepeAddr = "0x7a3b000000000000000000000000000000091c2a"
+	wifMint  = "EKpQGSJtjMFqKZ9KQanSqYXRcF8fBopzLHYxdM65zcjm"
+	unknown  = "0x1111000000000000000000000000000000002222"
+)
+
+// useSymbols installs a resolver knowing PEPE and WIF for the test.
+func useSymbols(t *testing.T) *fakeSymbols {
+	t.Helper()
+	f := &fakeSymbols{known: map[string]string{pepeAddr: "PEPE", wifMint: "WIF"}}
+	Symbols = f
+	t.Cleanup(func() { Symbols = nil })
+	return f
+}
+
+// Every formatter that shows a token address labels it with the cached
+// symbol, and leaves unknown addresses bare.
+func TestTokenAddressLabels(t *testing.T) {
+	TermWidth = 120
+	f := useSymbols(t)
+	order := map[string]any{"id": "ord-1", "status": "active", "input_token": pepeAddr, "output_token": unknown}
+
+	for _, tc := range []struct {
+		name string
+		out  string
+		want []string
+	}{
+		{"order created", FormatOrderCreated(order), []string{"0x7a3b...1c2a (PEPE)", "0x1111...2222"}},
+		{"order detail", FormatOrderDetail(order), []string{"0x7a3b...1c2a (PEPE)", "0x1111...2222"}},
+		{"holders", FormatHolders(map[string]any{
+			"token":   wifMint,
+			"holders": []any{map[string]any{"address": unknown, "bought_usd": 100.0}},
+		}), []string{"EKpQGS...zcjm (WIF)"}},
+		{"deployer tokens", FormatDeployerTokens(map[string]any{
+			"deployer": unknown,
+			"tokens":   []any{map[string]any{"address": pepeAddr, "price_usd": 0.01}},
+		}), []string{"PEPE", "0x7a3b...1c2a"}},
+		{"deployer activity", FormatDeployerActivity(map[string]any{
+			"deployer": unknown,
+			"token":    pepeAddr,
+			"activity": []any{map[string]any{"type": "sell", "amount_usd": 50.0, "tx_hash": "0xabcdef0123456789"}},
+		}), []string{"[0x7a3b...1c2a (PEPE)]"}},
+	} {
+		for _, want := range tc.want {
+			if !strings.Contains(tc.out, want) {
+				t.Errorf("%s: output lacks %q:\n%s", tc.name, want, tc.out)
+			}
+		}
+		if strings.Contains(tc.out, "0x1111...2222 (") {
+			t.Errorf("%s: unknown address labeled:\n%s", tc.name, tc.out)
+		}
+	}
+	if len(f.lookups) == 0 {
+		t.Error("resolver never asked")
+	}
+}
+
+// Without a resolver addresses are shown bare, as before.
+func TestTokenAddressNoResolver(t *testing.T) {
+	Symbols = nil
+	out := FormatOrderDetail(map[string]any{"id": "ord-1", "input_token": pepeAddr})
+	if !strings.Contains(out, "0x7a3b...1c2a") || strings.Contains(out, "PEPE") {
+		t.Errorf("output:\n%s", out)
+	}
+}
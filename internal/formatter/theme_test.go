@@ -0,0 +1,82 @@
+package formatter
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// colorCode matches an SGR sequence setting a foreground or background
+// color.
+var colorCode = regexp.MustCompile(`\x1b\[([0-9;]*;)?(3[0-8]|4[0-8]|9[0-7]|10[0-7])[;m]`)
+
+// Each theme's colors, escape codes included, are pinned by golden files,
+// and the dark theme is the default look.
+func TestThemeGolden(t *testing.T) {
+	TermWidth = 100
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	t.Cleanup(func() {
+		ui.ApplyPalette(ui.DarkPalette)
+		lipgloss.SetColorProfile(termenv.Ascii)
+	})
+
+	samples := []struct {
+		name   string
+		format func(map[string]any) string
+		data   map[string]any
+	}{
+		{"token", FormatTokenInfo, map[string]any{
+			"name": "dogwifhat", "symbol": "WIF", "price_usd": 2.5, "market_cap": 2500000000.0,
+			"volume_24h": 350000000.0, "liquidity": 42000000.0, "holders": 180000.0,
+			"address": "EKpQGSJtjMFqKZ9KQanSqYXRcF8fBopzLHYxdM65zcjm", "chain_id": "solana",
+		}},
+		{"orders", FormatOrders, map[string]any{
+			"total": 2.0,
+			"orders": []any{
+				map[string]any{"id": "ord_1a2b3c4d5e", "status": "active", "side": "buy", "trigger_price": 1.25,
+					"input_token": "USDC", "output_token": "WIF", "input_amount": 100.0},
+				map[string]any{"id": "ord_9f8e7d6c5b", "status": "filled", "side": "sell", "trigger_price": 3.0,
+					"input_token": "WIF", "output_token": "USDC", "input_amount": 40.0},
+			},
+		}},
+	}
+
+	defaults := make(map[string]string)
+	for _, s := range samples {
+		defaults[s.name] = s.format(s.data)
+	}
+	for _, theme := range []struct {
+		name    string
+		palette ui.Palette
+	}{
+		{"dark", ui.DarkPalette},
+		{"light", ui.LightPalette},
+		{"basic", ui.BasicPalette},
+		{"mono", ui.MonoPalette},
+	} {
+		ui.ApplyPalette(theme.palette)
+		for _, s := range samples {
+			t.Run(theme.name+"/"+s.name, func(t *testing.T) {
+				out := s.format(s.data)
+				golden(t, "theme_"+theme.name+"_"+s.name, out)
+				switch theme.name {
+				case "dark":
+					if out != defaults[s.name] {
+						t.Error("dark theme differs from the default look")
+					}
+				case "mono":
+					if colorCode.MatchString(out) {
+						t.Errorf("mono output has colors:\n%q", out)
+					}
+				default:
+					if out == defaults[s.name] {
+						t.Error("same colors as the dark theme")
+					}
+				}
+			})
+		}
+	}
+}
@@ -143,7 +143,9 @@ func FormatTokenInfo(data map[string]any) string {
 	}
 
 	if address != "" {
-		stats = append(stats, labelStyle.Render("Address")+ui.DimStyle.Render(TruncateAddress(address)))
+		stats = append(stats, labelStyle.Render("Address")+tokenLink(ui.DimStyle.Render(TruncateAddress(address)), chainOf(data), address))
+		// In full too: the truncated form can't be copied.
+		stats = append(stats, ui.DimStyle.Render(address))
 	}
 
 	if chainID != "" {
@@ -348,8 +350,9 @@ func FormatBrewingTokens(data map[string]any) string {
 		}
 		gradStr := ProgressBar(gradPct, 100, barW) + fmt.Sprintf(" %.0f%%", gradPct)
 
+		symbolCell := tokenLink(lipgloss.NewStyle().Foreground(ui.ColorBright).Render(symbolLabel), chainOf(token, data), getString(token, "address"))
 		rowParts := []string{
-			lipgloss.NewStyle().Width(colSymbol).Foreground(ui.ColorBright).Render(symbolLabel),
+			lipgloss.NewStyle().Width(colSymbol).Render(symbolCell),
 			lipgloss.NewStyle().Width(colPrice).Render(FormatUSD(price)),
 			lipgloss.NewStyle().Width(colMcap).Render(FormatUSD(mcap)),
 		}
@@ -428,3 +431,68 @@ func FormatTokenPrice(data map[string]any) string {
 
 	return ui.BoxBorder.Render(content)
 }
+
+// TokenPrices returns the USD price of each token in a get_token_price
+// response, keyed by address.
+func TokenPrices(data map[string]any) map[string]float64 {
+	prices := make(map[string]float64)
+	for _, item := range records(historyList(data, "prices")) {
+		address := getString(item, "address")
+		if price, ok := pickFloat(item, "price_usd", "price"); ok && address != "" {
+			prices[address] = price
+		}
+	}
+	return prices
+}
+
+// TokenPriceChanges returns the 24h price change, in percent, of each token
+// in a get_token_price response that reports one, keyed by address.
+func TokenPriceChanges(data map[string]any) map[string]float64 {
+	changes := make(map[string]float64)
+	for _, item := range records(historyList(data, "prices")) {
+		address := getString(item, "address")
+		if change, ok := pickFloat(item, "price_change_24h", "change_24h", "price_change_percent_24h"); ok && address != "" {
+			changes[address] = change
+		}
+	}
+	return changes
+}
+
+// TokenMatch is a token found by symbol in a search_tokens response.
+type TokenMatch struct {
+	Address string
+	Symbol  string
+	Name    string
+	Chain   string
+}
+
+// FindTokens returns every token in a search_tokens response whose symbol
+// is symbol, ignoring case, in the order the backend ranked them.
+func FindTokens(data map[string]any, symbol string) []TokenMatch {
+	var out []TokenMatch
+	for _, t := range records(historyList(data, "tokens", "results")) {
+		address := pickString(t, "address", "token_address", "mint")
+		sym := getString(t, "symbol")
+		if address != "" && strings.EqualFold(sym, strings.TrimPrefix(symbol, "$")) {
+			out = append(out, TokenMatch{
+				Address: address,
+				Symbol:  sym,
+				Name:    getString(t, "name"),
+				Chain:   pickString(t, "chain", "chain_name", "network"),
+			})
+		}
+	}
+	return out
+}
+
+// FindToken returns the address of the first token in a search_tokens
+// response whose symbol is symbol, ignoring case.
+func FindToken(data map[string]any, symbol string) (string, bool) {
+	for _, t := range records(historyList(data, "tokens", "results")) {
+		address := pickString(t, "address", "token_address", "mint")
+		if address != "" && strings.EqualFold(getString(t, "symbol"), strings.TrimPrefix(symbol, "$")) {
+			return address, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,260 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// trackColumn is one column of a watchlist or KOL table. Columns marked wide
+// are dropped in compact mode.
+type trackColumn struct {
+	title string
+	width int
+	wide  bool
+}
+
+// trackTable renders a titled table of cells, or accessible records titled
+// by each row's first cell. Rows past historyRows are summarized.
+func trackTable(title, subtitle string, cols []trackColumn, rows [][]string) string {
+	compact := isCompact()
+	keep := func(c trackColumn) bool { return !compact || !c.wide }
+
+	var lines []string
+	if !Accessible {
+		var head []string
+		total := 0
+		for _, c := range cols {
+			if keep(c) {
+				head = append(head, lipgloss.NewStyle().Width(c.width).Bold(true).Render(c.title))
+				total += c.width
+			}
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, head...), sepLine(total))
+	}
+
+	shown := rows
+	if len(shown) > historyRows {
+		shown = shown[:historyRows]
+	}
+	for _, row := range shown {
+		if Accessible {
+			var fields [][2]string
+			for i := 1; i < len(cols); i++ {
+				fields = append(fields, [2]string{cols[i].title, strings.TrimSpace(row[i])})
+			}
+			lines = append(lines, accessibleRecord(row[0], fields))
+			continue
+		}
+		var parts []string
+		for i, c := range cols {
+			if keep(c) {
+				parts = append(parts, lipgloss.NewStyle().Width(c.width).MaxWidth(c.width).Render(row[i]))
+			}
+		}
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, parts...))
+	}
+	if len(rows) > historyRows {
+		lines = append(lines, ui.DimStyle.Render(fmt.Sprintf("+%d more", len(rows)-historyRows)))
+	}
+
+	header := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true).Render(title)
+	content := lipgloss.JoinVertical(lipgloss.Left, header, ui.DimStyle.Render(subtitle), "", strings.Join(lines, "\n"))
+	return renderBox(ui.BoxBorder, content)
+}
+
+// records returns the maps in list, skipping anything else.
+func records(list []any) []map[string]any {
+	var out []map[string]any
+	for _, r := range list {
+		if m, ok := r.(map[string]any); ok {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// orDash returns s, or a dim dash when it is empty.
+func orDash(s string) string {
+	if s == "" {
+		return ui.DimStyle.Render("—")
+	}
+	return s
+}
+
+// winRatePercent returns a win rate as a percentage, whether the backend
+// sent a fraction or a percentage.
+func winRatePercent(m map[string]any) (float64, bool) {
+	rate, ok := pickFloat(m, "win_rate", "winrate", "win_rate_7d")
+	if ok && rate > 0 && rate <= 1 {
+		rate *= 100
+	}
+	return rate, ok
+}
+
+// kolName returns a KOL's display name, falling back to their handle.
+func kolName(m map[string]any) string {
+	if kol, ok := m["kol"].(map[string]any); ok {
+		m = kol
+	}
+	name := pickString(m, "kol_name", "name", "display_name")
+	if handle := pickString(m, "twitter", "handle", "twitter_handle", "username"); handle != "" {
+		handle = "@" + strings.TrimPrefix(handle, "@")
+		if name == "" {
+			return handle
+		}
+		if Accessible || !isCompact() {
+			return name + " " + ui.DimStyle.Render(handle)
+		}
+	}
+	return name
+}
+
+// FormatWatchlist renders the tokens on the agent's watchlist.
+func FormatWatchlist(data map[string]any) string {
+	tokens := records(historyList(data, "watchlist", "tokens", "items"))
+	if len(tokens) == 0 {
+		return ui.DimStyle.Render("Watchlist is empty.")
+	}
+
+	cols := []trackColumn{{"Token", 12, false}, {"Price", 14, false}, {"24h", 12, false}, {"Held", 12, true}, {"Chain", 10, true}}
+	var rows [][]string
+	for _, t := range tokens {
+		price := ""
+		if p, ok := pickFloat(t, "price_usd", "price", "current_price"); ok && p > 0 {
+			price = smartFormatPrice(p)
+		}
+		change := ""
+		if c, ok := pickFloat(t, "price_change_24h", "change_24h", "price_change_percent_24h"); ok {
+			change = FormatPercent(c)
+		}
+		held := ""
+		if v, ok := pickFloat(t, "value_usd", "balance_usd", "holding_value_usd"); ok && v > 0 {
+			held = FormatUSD(v)
+		}
+		rows = append(rows, []string{
+			historyToken(t),
+			orDash(price),
+			orDash(change),
+			orDash(held),
+			ui.DimStyle.Render(pickString(t, "chain", "chain_name")),
+		})
+	}
+	return trackTable("WATCHLIST", fmt.Sprintf("%d tokens", len(tokens)), cols, rows)
+}
+
+// FormatKOLWallets renders tracked KOL wallets and how they've been trading.
+func FormatKOLWallets(data map[string]any) string {
+	kols := records(historyList(data, "kols", "wallets", "items"))
+	if len(kols) == 0 {
+		return ui.DimStyle.Render("No KOL wallets found.")
+	}
+
+	cols := []trackColumn{{"KOL", 24, false}, {"Address", 15, true}, {"Win rate", 10, false}, {"7d profit", 12, false}}
+	var rows [][]string
+	for _, k := range kols {
+		rate := ""
+		if r, ok := winRatePercent(k); ok {
+			rate = fmt.Sprintf("%.0f%%", r)
+		}
+		profit := ""
+		if p, ok := pickFloat(k, "profit_7d", "pnl_7d", "realized_profit_7d", "profit_usd_7d"); ok {
+			profit = FormatUSD(p)
+		}
+		rows = append(rows, []string{
+			orDash(kolName(k)),
+			ui.DimStyle.Render(TruncateAddress(pickString(k, "address", "wallet_address", "wallet"))),
+			orDash(rate),
+			orDash(profit),
+		})
+	}
+	return trackTable("KOL WALLETS", fmt.Sprintf("%d wallets", len(kols)), cols, rows)
+}
+
+// FormatKOLSwaps renders recent swaps by tracked KOLs.
+func FormatKOLSwaps(data map[string]any) string {
+	swaps := records(historyList(data, "swaps", "trades", "items"))
+	if len(swaps) == 0 {
+		return ui.DimStyle.Render("No KOL swaps found.")
+	}
+
+	cols := []trackColumn{{"KOL", 18, false}, {"Time", 13, true}, {"Side", 6, false}, {"Token", 10, false}, {"Amount", 12, false}}
+	var rows [][]string
+	for _, s := range swaps {
+		side := tradeSide(s)
+		amount := ""
+		if v, ok := pickFloat(s, "amount_usd", "value_usd", "usd_value", "volume_usd"); ok {
+			amount = FormatUSD(v)
+		}
+		rows = append(rows, []string{
+			orDash(kolName(s)),
+			ui.DimStyle.Render(historyWhen(s)),
+			sideStyle(side).Render(side),
+			historyToken(s),
+			orDash(amount),
+		})
+	}
+	return trackTable("KOL SWAPS", fmt.Sprintf("%d swaps", len(swaps)), cols, rows)
+}
+
+// FormatKOLInfo renders one KOL's profile and performance.
+func FormatKOLInfo(data map[string]any) string {
+	kol := data
+	if inner, ok := data["kol"].(map[string]any); ok {
+		kol = inner
+	}
+
+	labelStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true).Width(14)
+	var fields [][2]string
+	add := func(label, value string) {
+		if value != "" {
+			fields = append(fields, [2]string{label, value})
+		}
+	}
+	add("Address", TruncateAddress(pickString(kol, "address", "wallet_address", "wallet")))
+	if r, ok := winRatePercent(kol); ok {
+		add("Win rate", fmt.Sprintf("%.0f%%", r))
+	}
+	for _, p := range []struct{ label, key string }{
+		{"7d profit", "profit_7d"},
+		{"30d profit", "profit_30d"},
+		{"Total profit", "total_profit"},
+	} {
+		if v, ok := pickFloat(kol, p.key, strings.Replace(p.key, "profit", "pnl", 1)); ok {
+			add(p.label, FormatUSD(v))
+		}
+	}
+	if n, ok := pickFloat(kol, "trades_7d", "total_trades", "trade_count"); ok {
+		add("Trades", fmt.Sprintf("%.0f", n))
+	}
+	if n, ok := pickFloat(kol, "followers", "twitter_followers"); ok && n > 0 {
+		add("Followers", FormatNumber(n))
+	}
+
+	name := kolName(kol)
+	if name == "" {
+		name = "KOL"
+	}
+	if Accessible {
+		return accessibleRecord(name, fields)
+	}
+
+	lines := []string{lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true).Render(name), ""}
+	for _, f := range fields {
+		lines = append(lines, labelStyle.Render(f[0])+f[1])
+	}
+	return renderBox(ui.BoxBorder, strings.Join(lines, "\n"))
+}
+
+// watchlistAddedPreview summarizes add_to_watchlist for the status line.
+func watchlistAddedPreview(data map[string]any) string {
+	if msg := getString(data, "message"); msg != "" {
+		return msg
+	}
+	if token := historyToken(data); token != "" {
+		return fmt.Sprintf("Added $%s to watchlist", token)
+	}
+	return "Added to watchlist"
+}
@@ -54,9 +54,11 @@ func FormatTrendingTokens(data map[string]any) string {
 			Bold(true).
 			Width(10)
 
+		symbolCell := tokenLink(symbolStyle.Render(symbol), chainOf(token, data), getString(token, "address"))
+
 		row := fmt.Sprintf("%s  %s  %s  %s",
 			medal,
-			symbolStyle.Render(symbol),
+			symbolCell,
 			lipgloss.NewStyle().Width(14).Render(FormatUSD(price)),
 			FormatPercent(change24h),
 		)
@@ -0,0 +1,193 @@
+package formatter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// xpEventsShown is how many recent XP events FormatUserXP lists.
+const xpEventsShown = 5
+
+// UserXP is a get_user_xp response with its fields resolved from whichever
+// keys the backend used.
+type UserXP struct {
+	Level    int
+	HasLevel bool
+	XP       float64 // total XP
+	// Progress is the XP earned toward the next level, out of Needed; both
+	// are 0 when the response doesn't say what the next level takes.
+	Progress, Needed float64
+
+	Rank          int
+	HasRank       bool
+	Percentile    float64
+	HasPercentile bool
+
+	Events []XPEvent // newest first, as the backend sent them
+}
+
+// XPEvent is one recent XP award.
+type XPEvent struct {
+	Amount float64
+	Reason string
+	When   string
+}
+
+// ParseUserXP reads a get_user_xp response. ok is false when it has
+// neither a level nor an XP count under any of the known keys. The fields
+// may be nested under "xp", "user_xp", "rewards" or "user".
+func ParseUserXP(data map[string]any) (xp UserXP, ok bool) {
+	data = unwrapData(data)
+	for _, k := range []string{"xp", "user_xp", "rewards", "user"} {
+		if inner, isMap := data[k].(map[string]any); isMap {
+			data = inner
+			break
+		}
+	}
+
+	level, hasLevel := xpNumber(data, "level", "current_level", "lvl")
+	total, hasXP := xpNumber(data, "xp", "total_xp", "experience", "points", "total_points", "current_xp")
+	if !hasLevel && !hasXP {
+		return UserXP{}, false
+	}
+	xp = UserXP{Level: int(level), HasLevel: hasLevel, XP: total}
+
+	// The next level is given as the XP it starts at, or as how much is
+	// left to reach it; the current level may say where it started.
+	start, _ := xpNumber(data, "level_start_xp", "current_level_xp", "level_min_xp")
+	next, hasNext := xpNumber(data, "next_level_xp", "xp_next_level", "next_level_at", "level_up_xp")
+	if left, ok := xpNumber(data, "xp_to_next_level", "xp_to_next", "xp_needed", "xp_remaining", "points_to_next_level"); ok && !hasNext {
+		next, hasNext = total+left, true
+	}
+	if hasNext && next > start {
+		xp.Progress, xp.Needed = math.Max(0, total-start), next-start
+	}
+
+	if rank, ok := xpNumber(data, "rank", "leaderboard_rank", "position"); ok && rank > 0 {
+		xp.Rank, xp.HasRank = int(rank), true
+	}
+	xp.Percentile, xp.HasPercentile = xpNumber(data, "percentile", "rank_percentile")
+
+	for _, e := range records(historyList(data, "recent_events", "events", "recent_xp", "history", "activities")) {
+		amount, ok := xpNumber(e, "xp", "amount", "points", "experience")
+		if !ok {
+			continue
+		}
+		xp.Events = append(xp.Events, XPEvent{
+			Amount: amount,
+			Reason: pickString(e, "reason", "description", "action", "event", "type"),
+			When:   historyWhen(e),
+		})
+	}
+	return xp, true
+}
+
+// xpNumber returns the first of keys holding a number, including one sent
+// as a string such as "1,250".
+func xpNumber(m map[string]any, keys ...string) (float64, bool) {
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case nil:
+			continue
+		case string:
+			f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(v), ",", ""), 64)
+			if err == nil {
+				return f, true
+			}
+		default:
+			if f, ok := toFloat64(v); ok {
+				return f, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// FormatXP formats an XP amount as a whole number with thousands
+// separators, e.g. 1,340.
+func FormatXP(v float64) string {
+	n := int64(math.Round(v))
+	if n < 0 {
+		return "-" + groupDigits(strconv.FormatInt(-n, 10), ",")
+	}
+	return groupDigits(strconv.FormatInt(n, 10), ",")
+}
+
+// FormatUserXP renders a get_user_xp response: level, XP and progress to
+// the next level, rank, and the latest XP events.
+func FormatUserXP(data map[string]any) string {
+	xp, ok := ParseUserXP(data)
+	if !ok {
+		return ""
+	}
+
+	var fields [][2]string
+	if xp.HasLevel {
+		fields = append(fields, [2]string{"Level", strconv.Itoa(xp.Level)})
+	}
+	fields = append(fields, [2]string{"XP", FormatXP(xp.XP)})
+	if xp.Needed > 0 {
+		fields = append(fields, [2]string{"Next level", ProgressBar(xp.Progress, xp.Needed, 20) +
+			fmt.Sprintf(" %s/%s", FormatXP(xp.Progress), FormatXP(xp.Needed)) +
+			ui.DimStyle.Render(fmt.Sprintf(" (%s to go)", FormatXP(math.Max(0, xp.Needed-xp.Progress))))})
+	}
+	if xp.HasRank {
+		fields = append(fields, [2]string{"Rank", fmt.Sprintf("#%d", xp.Rank)})
+	}
+	if xp.HasPercentile {
+		fields = append(fields, [2]string{"Percentile", fmt.Sprintf("%.0f", xp.Percentile)})
+	}
+
+	var events []string
+	for _, e := range xp.Events[:min(len(xp.Events), xpEventsShown)] {
+		amount := lipgloss.NewStyle().Foreground(ui.ColorGreen).Render("+" + FormatXP(e.Amount))
+		if e.Amount < 0 {
+			amount = lipgloss.NewStyle().Foreground(ui.ColorRed).Render(FormatXP(e.Amount))
+		}
+		line := amount
+		if e.Reason != "" {
+			line += " " + e.Reason
+		}
+		if e.When != "" {
+			line += " " + ui.DimStyle.Render(e.When)
+		}
+		events = append(events, line)
+	}
+
+	if Accessible {
+		for i, e := range events {
+			fields = append(fields, [2]string{fmt.Sprintf("Recent %d", i+1), e})
+		}
+		return accessibleRecord("XP", fields)
+	}
+
+	labelStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true).Width(14)
+	lines := []string{ui.TitleStyle.Render("XP & REWARDS"), ""}
+	for _, f := range fields {
+		lines = append(lines, labelStyle.Render(f[0])+f[1])
+	}
+	if len(events) > 0 {
+		lines = append(lines, "", labelStyle.Render("Recent"))
+		for _, e := range events {
+			lines = append(lines, "  "+e)
+		}
+	}
+	return renderBox(ui.BoxBorder, strings.Join(lines, "\n"))
+}
+
+// userXPPreview summarizes get_user_xp for the status line.
+func userXPPreview(data map[string]any) string {
+	xp, ok := ParseUserXP(data)
+	switch {
+	case !ok:
+		return "XP loaded"
+	case xp.HasLevel:
+		return fmt.Sprintf("Level %d · %s XP", xp.Level, FormatXP(xp.XP))
+	}
+	return FormatXP(xp.XP) + " XP"
+}
@@ -0,0 +1,75 @@
+package formatter
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// get_user_xp fields are read under each of their names, including
+// numbers sent as strings and fields nested under a wrapper.
+func TestParseUserXP(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		json string
+		want UserXP
+		ok   bool
+	}{
+		{"next level XP", `{"level":12,"xp":1340,"level_start_xp":1000,"next_level_xp":1500}`,
+			UserXP{Level: 12, HasLevel: true, XP: 1340, Progress: 340, Needed: 500}, true},
+		{"strings", `{"current_level":"12","experience":"1,340","current_level_xp":"1,000","xp_to_next_level":"160"}`,
+			UserXP{Level: 12, HasLevel: true, XP: 1340, Progress: 340, Needed: 500}, true},
+		{"points, nested", `{"data":{"rewards":{"points":250,"rank":"7","percentile":"93"}}}`,
+			UserXP{XP: 250, Rank: 7, HasRank: true, Percentile: 93, HasPercentile: true}, true},
+		{"level only", `{"user":{"lvl":3}}`, UserXP{Level: 3, HasLevel: true}, true},
+		{"rank 0 left out", `{"xp":10,"rank":0}`, UserXP{XP: 10}, true},
+		{"next level below start", `{"xp":10,"level_start_xp":100,"next_level_xp":50}`, UserXP{XP: 10}, true},
+		{"unparsable", `{"xp":"lots"}`, UserXP{}, false},
+		{"other fields", `{"score":10,"tier":"gold"}`, UserXP{}, false},
+	} {
+		got, ok := ParseUserXP(decode(t, tc.json))
+		if ok != tc.ok || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: %+v, %v; want %+v, %v", tc.name, got, ok, tc.want, tc.ok)
+		}
+	}
+
+	xp, _ := ParseUserXP(decode(t, `{"xp":50,"recent_events":[
+		{"amount":"25","reason":"first swap"},
+		{"note":"no amount"},
+		{"points":-5,"action":"refund"}
+	]}`))
+	if len(xp.Events) != 2 || xp.Events[0] != (XPEvent{Amount: 25, Reason: "first swap"}) || xp.Events[1].Amount != -5 || xp.Events[1].Reason != "refund" {
+		t.Errorf("events = %+v", xp.Events)
+	}
+}
+
+func TestFormatUserXP(t *testing.T) {
+	data := decode(t, `{"level":12,"xp":1340,"level_start_xp":1000,"next_level_xp":1500,"rank":42,
+		"events":[{"xp":25,"reason":"first swap"},{"xp":10,"reason":"daily login"}]}`)
+	out := FormatToolResult("get_user_xp", data)
+	for _, want := range []string{"XP & REWARDS", "Level", "12", "1,340", "340/500", "160 to go", "#42", "+25 first swap", "+10 daily login"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+	if got, want := FormatToolPreview("get_user_xp", data), "Level 12 · 1,340 XP"; got != want {
+		t.Errorf("preview = %q, want %q", got, want)
+	}
+	if got := FormatToolPreview("get_user_xp", decode(t, `{"points":"2,500"}`)); got != "2,500 XP" {
+		t.Errorf("preview without a level = %q", got)
+	}
+	if got := FormatToolPreview("get_user_xp", decode(t, `{"score":1}`)); got != "XP loaded" {
+		t.Errorf("preview of an unknown response = %q", got)
+	}
+	if out := FormatUserXP(decode(t, `{"score":1}`)); out != "" {
+		t.Errorf("unknown response formatted:\n%s", out)
+	}
+}
+
+func TestFormatXP(t *testing.T) {
+	for in, want := range map[float64]string{0: "0", 999.6: "1,000", 1234567: "1,234,567", -1500: "-1,500"} {
+		if got := FormatXP(in); got != want {
+			t.Errorf("FormatXP(%v) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// Entry is one executed trade as reported by the backend. The journal is a
+// local record so trades can later be checked against the chain.
+type Entry struct {
+	Time       time.Time `json:"time"`
+	Tool       string    `json:"tool"`
+	Chain      string    `json:"chain"`
+	TxHash     string    `json:"txHash"`
+	Wallet     string    `json:"wallet"`
+	FromToken  string    `json:"fromToken,omitempty"`
+	FromSymbol string    `json:"fromSymbol,omitempty"`
+	FromAmount float64   `json:"fromAmount,omitempty"`
+	ToToken    string    `json:"toToken,omitempty"`
+	ToSymbol   string    `json:"toSymbol,omitempty"`
+	ToAmount   float64   `json:"toAmount,omitempty"`
+}
+
+// TradeTools are the tools whose successful results are journaled.
+var TradeTools = map[string]bool{
+	"execute_swap":  true,
+	"execute_trade": true,
+}
+
+var mu sync.Mutex
+
+// Path returns the journal file, stored next to the config file.
+func Path() string {
+	return filepath.Join(config.DataDir(), "trades.jsonl")
+}
+
+// Append writes e as one JSON line to the journal.
+func Append(e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	f, err := config.OpenPrivateAppend(Path())
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Entries reads every journaled trade, oldest first. Malformed lines are
+// skipped. A missing journal is not an error.
+func Entries() ([]Entry, error) {
+	f, err := os.Open(Path())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil && e.TxHash != "" {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// Find returns the most recent entry for a transaction hash.
+func Find(hash string) (*Entry, error) {
+	entries, err := Entries()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		if sameHash(entries[i].TxHash, hash) {
+			return &entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no journaled trade with hash %s", hash)
+}
+
+// Last returns the most recently journaled trade.
+func Last() (*Entry, error) {
+	entries, err := Entries()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no trades journaled yet")
+	}
+	return &entries[len(entries)-1], nil
+}
+
+// EVM hashes are hex and case-insensitive; Solana signatures are base58.
+func sameHash(a, b string) bool {
+	if strings.HasPrefix(a, "0x") {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// FromResult builds a journal entry from a trade tool's arguments and its
+// parsed response. It returns false when the response carries no tx hash.
+func FromResult(tool string, args, resp map[string]any, tokens *config.AuthTokens) (Entry, bool) {
+	resp = unwrapData(resp)
+	if ok, isBool := resp["success"].(bool); isBool && !ok {
+		return Entry{}, false
+	}
+
+	e := Entry{
+		Time:       time.Now().UTC(),
+		Tool:       tool,
+		TxHash:     firstString(resp, "tx_hash", "hash", "transaction_hash", "signature"),
+		FromToken:  firstString(resp, "from_address", "from_token", "input_token"),
+		FromSymbol: firstString(resp, "from_symbol", "input_symbol"),
+		FromAmount: firstFloat(resp, "from_amount", "input_amount"),
+		ToToken:    firstString(resp, "to_address", "to_token", "output_token"),
+		ToSymbol:   firstString(resp, "to_symbol", "output_symbol"),
+		ToAmount:   firstFloat(resp, "to_amount", "output_amount"),
+	}
+	if e.TxHash == "" {
+		return e, false
+	}
+
+	// Fall back to the request when the response omits token addresses.
+	if e.FromToken == "" {
+		e.FromToken = firstString(args, "from_token", "fromToken", "input_token", "sell_token", "token_in")
+	}
+	if e.ToToken == "" {
+		e.ToToken = firstString(args, "to_token", "toToken", "output_token", "buy_token", "token_out")
+	}
+	if e.FromAmount == 0 {
+		e.FromAmount = firstFloat(args, "amount", "from_amount", "input_amount")
+	}
+
+	e.Chain = chainSlug(resp["chain"])
+	if e.Chain == "" {
+		e.Chain = chainSlug(args["chain"])
+	}
+	if e.Chain == "" {
+		e.Chain = chainSlug(args["chain_id"])
+	}
+
+	e.Wallet = firstString(args, "from_address", "fromAddress", "taker")
+	if e.Wallet == "" && tokens != nil {
+		if e.Chain == "solana" {
+			e.Wallet = tokens.SolanaAddress
+		} else {
+			e.Wallet = tokens.EVMAddress
+		}
+	}
+	return e, true
+}
+
+// chainSlug normalizes a chain given as a name, slug or numeric ID.
+func chainSlug(v any) string {
+	switch c := v.(type) {
+	case float64:
+		if ch, ok := config.LookupChainID(int(c)); ok {
+			return ch.Slug
+		}
+	case string:
+		if ch, ok := config.LookupChain(c); ok {
+			return ch.Slug
+		}
+		if id, err := strconv.Atoi(c); err == nil {
+			if ch, ok := config.LookupChainID(id); ok {
+				return ch.Slug
+			}
+		}
+	}
+	return ""
+}
+
+func unwrapData(m map[string]any) map[string]any {
+	inner, ok := m["data"].(map[string]any)
+	if !ok {
+		return m
+	}
+	merged := make(map[string]any, len(inner)+len(m))
+	for k, v := range m {
+		if k != "data" {
+			merged[k] = v
+		}
+	}
+	for k, v := range inner {
+		merged[k] = v
+	}
+	return merged
+}
+
+func firstString(m map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func firstFloat(m map[string]any, keys ...string) float64 {
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case float64:
+			if v != 0 {
+				return v
+			}
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil && f != 0 {
+				return f
+			}
+		}
+	}
+	return 0
+}
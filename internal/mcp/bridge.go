@@ -4,23 +4,50 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
+	"slices"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/tradeboba/boba-cli/internal/client"
 	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/proxy"
 	"github.com/tradeboba/boba-cli/internal/version"
 )
 
+// DefaultMaxMessageSize bounds a JSON-RPC message read from stdin and the
+// tool result written back, in bytes.
+const DefaultMaxMessageSize = 10 << 20
+
 type Bridge struct {
-	proxyURL     string
-	sessionToken string
-	stdin        io.Reader
-	stdout       io.Writer
-	stderr       io.Writer
-	client       *http.Client
+	// MaxMessageSize bounds the messages the bridge reads and the tool
+	// results it returns. Zero means DefaultMaxMessageSize.
+	MaxMessageSize int
+
+	// FixedToken is set when the session token was given on the command
+	// line, as for a proxy on another machine. It is never re-read from
+	// the keyring, which holds this machine's token if any.
+	FixedToken bool
+
+	proxyURL        string
+	sessionToken    string
+	clientID        string
+	protocolVersion string
+	stdin           io.Reader
+	stdout          io.Writer
+	stderr          io.Writer
+	client          *http.Client
+
+	// reports queues the bridge's problems for the proxy's activity log.
+	reports    chan bridgeReport
+	reportOnce sync.Once
 }
 
 // NewBridge creates a new MCP stdio bridge that proxies JSON-RPC requests
@@ -29,11 +56,22 @@ func NewBridge(proxyURL, sessionToken string) *Bridge {
 	return &Bridge{
 		proxyURL:     proxyURL,
 		sessionToken: sessionToken,
+		clientID:     fmt.Sprintf("mcp-%d", os.Getpid()),
 		stdin:        os.Stdin,
 		stdout:       os.Stdout,
 		stderr:       os.Stderr,
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			// The proxy times tool calls out itself; this only covers a
+			// proxy that stops answering, allowing for a held trade.
+			Timeout: proxy.MaxCallTimeout + proxy.DefaultConfirmTimeout + 30*time.Second,
+			// A kept-alive connection outlives a proxy restart, and a call
+			// sent on it fails with EOF, which can't be told apart from the
+			// proxy dying mid-call. A new connection per call is refused
+			// instead, and the call is safely retried once the proxy is back.
+			Transport: &http.Transport{
+				Proxy:             http.ProxyFromEnvironment,
+				DisableKeepAlives: true,
+			},
 		},
 	}
 }
@@ -41,30 +79,72 @@ func NewBridge(proxyURL, sessionToken string) *Bridge {
 // Run starts the main JSON-RPC stdio loop. It reads newline-delimited JSON-RPC
 // requests from stdin, dispatches them, and writes responses to stdout.
 func (b *Bridge) Run() error {
-	scanner := bufio.NewScanner(b.stdin)
-	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) == 0 {
+	r := bufio.NewReader(b.stdin)
+	for {
+		line, err := readLine(r, b.maxMessageSize())
+		if errors.Is(err, errMessageTooLarge) {
+			// The request's id is somewhere in the dropped bytes, so the
+			// error can only go out with a null id.
+			b.reportError("dropped a JSON-RPC request larger than %d bytes", b.maxMessageSize())
+			b.writeResponse(&JSONRPCResponse{
+				Jsonrpc: "2.0",
+				ID:      json.RawMessage("null"),
+				Error: &JSONRPCError{
+					Code:    -32700,
+					Message: fmt.Sprintf("request exceeds the %d byte limit", b.maxMessageSize()),
+				},
+			})
 			continue
 		}
-
-		var req JSONRPCRequest
-		if err := json.Unmarshal(line, &req); err != nil {
-			b.logError("failed to parse JSON-RPC request: %v", err)
-			continue
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read stdin: %w", err)
 		}
 
-		resp := b.handleRequest(&req)
-		if resp != nil {
-			b.writeResponse(resp)
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var req JSONRPCRequest
+			if jsonErr := json.Unmarshal(line, &req); jsonErr != nil {
+				b.reportError("failed to parse JSON-RPC request: %v", jsonErr)
+			} else if resp := b.handleRequest(&req); resp != nil {
+				b.writeResponse(resp)
+			}
+		}
+		if err == io.EOF {
+			return nil
 		}
 	}
+}
 
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("scanner error: %w", err)
+var errMessageTooLarge = errors.New("message too large")
+
+// readLine reads one newline-terminated line of at most max bytes. A longer
+// line is read to its end and dropped, so the next read starts at the
+// following message, and errMessageTooLarge is returned.
+func readLine(r *bufio.Reader, max int) ([]byte, error) {
+	var line []byte
+	tooLarge := false
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if !tooLarge && len(line)+len(chunk) > max+1 { // +1 for the newline
+			tooLarge, line = true, nil
+		}
+		if !tooLarge {
+			line = append(line, chunk...)
+		}
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if tooLarge {
+			return nil, errMessageTooLarge
+		}
+		return line, err
 	}
+}
 
-	return nil
+func (b *Bridge) maxMessageSize() int {
+	if b.MaxMessageSize > 0 {
+		return b.MaxMessageSize
+	}
+	return DefaultMaxMessageSize
 }
 
 // handleRequest dispatches a JSON-RPC request to the appropriate handler
@@ -73,13 +153,26 @@ func (b *Bridge) handleRequest(req *JSONRPCRequest) *JSONRPCResponse {
 	switch req.Method {
 	case "initialize":
 		return b.handleInitialize(req)
-	case "notifications/initialized":
-		return nil
+	case "ping":
+		return &JSONRPCResponse{Jsonrpc: "2.0", ID: req.ID, Result: map[string]any{}}
 	case "tools/list":
 		return b.handleToolsList(req)
 	case "tools/call":
 		return b.handleToolsCall(req)
+	// Boba has no prompts or resources, but clients ask regardless and flag
+	// a "Method not found" as a broken server.
+	case "prompts/list":
+		return &JSONRPCResponse{Jsonrpc: "2.0", ID: req.ID, Result: map[string]any{"prompts": []any{}}}
+	case "resources/list":
+		return &JSONRPCResponse{Jsonrpc: "2.0", ID: req.ID, Result: map[string]any{"resources": []any{}}}
+	case "resources/templates/list":
+		return &JSONRPCResponse{Jsonrpc: "2.0", ID: req.ID, Result: map[string]any{"resourceTemplates": []any{}}}
 	default:
+		// Notifications (notifications/initialized, notifications/cancelled,
+		// ...) never get a response, not even an error.
+		if strings.HasPrefix(req.Method, "notifications/") || len(req.ID) == 0 {
+			return nil
+		}
 		return &JSONRPCResponse{
 			Jsonrpc: "2.0",
 			ID:      req.ID,
@@ -91,14 +184,37 @@ func (b *Bridge) handleRequest(req *JSONRPCRequest) *JSONRPCResponse {
 	}
 }
 
+// protocolVersions are the MCP protocol versions the bridge speaks, newest
+// first.
+var protocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// negotiateProtocol returns the version the client asked for if the bridge
+// speaks it, and otherwise the newest one it does, as the spec requires.
+func negotiateProtocol(requested string) string {
+	if slices.Contains(protocolVersions, requested) {
+		return requested
+	}
+	return protocolVersions[0]
+}
+
 // handleInitialize responds to the MCP initialize handshake with server
-// capabilities and version information.
+// capabilities and version information. Only tools are advertised; the
+// empty prompt and resource lists are answered but not offered.
 func (b *Bridge) handleInitialize(req *JSONRPCRequest) *JSONRPCResponse {
+	// Name this bridge after the MCP client so the proxy can count tool
+	// calls per client; the pid keeps two windows of the same app apart.
+	var params InitializeParams
+	_ = json.Unmarshal(req.Params, &params)
+	b.protocolVersion = negotiateProtocol(params.ProtocolVersion)
+	if params.ClientInfo.Name != "" {
+		b.clientID = fmt.Sprintf("%s-%d", params.ClientInfo.Name, os.Getpid())
+	}
+
 	return &JSONRPCResponse{
 		Jsonrpc: "2.0",
 		ID:      req.ID,
 		Result: map[string]any{
-			"protocolVersion": "2024-11-05",
+			"protocolVersion": b.protocolVersion,
 			"capabilities": map[string]any{
 				"tools": map[string]any{},
 			},
@@ -115,8 +231,11 @@ func (b *Bridge) handleInitialize(req *JSONRPCRequest) *JSONRPCResponse {
 // token and retries once.
 func (b *Bridge) handleToolsList(req *JSONRPCRequest) *JSONRPCResponse {
 	result, err := b.doToolsList()
+	if isProxyDown(err) && b.waitForProxy(proxyRestartWait) {
+		result, err = b.doToolsList()
+	}
 	if err != nil {
-		b.logError("tools/list failed: %v", err)
+		b.reportError("tools/list failed: %v", err)
 		return &JSONRPCResponse{
 			Jsonrpc: "2.0",
 			ID:      req.ID,
@@ -148,7 +267,10 @@ func (b *Bridge) doToolsList() (any, error) {
 
 	if resp.StatusCode == http.StatusForbidden {
 		resp.Body.Close()
-		b.refreshSessionToken()
+		if err := b.refreshSessionToken(); err != nil {
+			return nil, err
+		}
+		b.report(proxy.BridgeLog{Message: "session token rejected on tools/list; re-read it and retried", Retry: true})
 
 		httpReq, err = http.NewRequest("GET", b.proxyURL+"/tools", nil)
 		if err != nil {
@@ -191,9 +313,23 @@ func (b *Bridge) handleToolsCall(req *JSONRPCRequest) *JSONRPCResponse {
 		}
 	}
 
-	text, err := b.doToolsCall(params)
+	result, err := b.doToolsCall(params)
+	if isProxyDown(err) {
+		// The proxy is most likely restarting. Wait for it to come back
+		// and retry once; only tell the client if it takes too long.
+		if b.waitForProxy(proxyRestartWait) {
+			result, err = b.doToolsCall(params)
+		} else {
+			result, err = toolResult{Text: proxyRestartingText}, nil
+		}
+	}
 	if err != nil {
-		b.logError("tools/call failed: %v", err)
+		// A failure the proxy answered with is in its log already.
+		if errors.As(err, new(answeredError)) {
+			b.logError("tools/call failed: %v", err)
+		} else {
+			b.reportError("tools/call failed: %v", err)
+		}
 		return &JSONRPCResponse{
 			Jsonrpc: "2.0",
 			ID:      req.ID,
@@ -208,80 +344,207 @@ func (b *Bridge) handleToolsCall(req *JSONRPCRequest) *JSONRPCResponse {
 		}
 	}
 
+	content := map[string]any{
+		"content": []map[string]any{
+			{
+				"type": "text",
+				"text": result.Text,
+			},
+		},
+	}
+	// Clients from 2025-06-18 on read a JSON object result as structured
+	// content; the text keeps it for older ones.
+	if result.Structured != nil && b.protocolVersion >= "2025-06-18" {
+		content["structuredContent"] = result.Structured
+	}
 	return &JSONRPCResponse{
 		Jsonrpc: "2.0",
 		ID:      req.ID,
-		Result: map[string]any{
-			"content": []map[string]any{
-				{
-					"type": "text",
-					"text": text,
-				},
-			},
-		},
+		Result:  content,
 	}
 }
 
-func (b *Bridge) doToolsCall(params ToolCallParams) (string, error) {
+// toolResult is a tool's output as returned to the client. Structured is
+// the output parsed, when it is a complete JSON object.
+type toolResult struct {
+	Text       string
+	Structured map[string]any
+}
+
+func (b *Bridge) doToolsCall(params ToolCallParams) (toolResult, error) {
 	body, err := json.Marshal(map[string]any{
 		"name":      params.Name,
 		"arguments": params.Arguments,
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request body: %w", err)
+		return toolResult{}, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
 	httpReq, err := http.NewRequest("POST", b.proxyURL+"/call", bytes.NewReader(body))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return toolResult{}, fmt.Errorf("failed to create request: %w", err)
 	}
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+b.sessionToken)
+	httpReq.Header.Set(proxy.ClientHeader, b.clientID)
 
 	resp, err := b.client.Do(httpReq)
 	if err != nil {
-		return "", fmt.Errorf("failed to call proxy: %w", err)
+		return toolResult{}, fmt.Errorf("failed to call proxy: %w", err)
 	}
 
-	if resp.StatusCode == http.StatusForbidden {
+	if resp.StatusCode == http.StatusForbidden && resp.Header.Get(proxy.PolicyHeader) == "" {
 		resp.Body.Close()
-		b.refreshSessionToken()
+		if err := b.refreshSessionToken(); err != nil {
+			return toolResult{}, err
+		}
+		b.report(proxy.BridgeLog{Message: "session token rejected on " + params.Name + "; re-read it and retried", Retry: true})
 
 		httpReq, err = http.NewRequest("POST", b.proxyURL+"/call", bytes.NewReader(body))
 		if err != nil {
-			return "", fmt.Errorf("failed to create retry request: %w", err)
+			return toolResult{}, fmt.Errorf("failed to create retry request: %w", err)
 		}
 		httpReq.Header.Set("Content-Type", "application/json")
 		httpReq.Header.Set("Authorization", "Bearer "+b.sessionToken)
+		httpReq.Header.Set(proxy.ClientHeader, b.clientID)
 
 		resp, err = b.client.Do(httpReq)
 		if err != nil {
-			return "", fmt.Errorf("failed to call proxy on retry: %w", err)
+			return toolResult{}, fmt.Errorf("failed to call proxy on retry: %w", err)
 		}
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusPreconditionFailed ||
+		resp.Header.Get(proxy.PolicyHeader) != "" {
+		// The budget, policy and pre-flight messages tell the agent to stop;
+		// pass them through verbatim rather than as a bare status code.
+		var budgetErr struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&budgetErr) == nil && budgetErr.Message != "" {
+			return toolResult{}, answeredError{budgetErr.Message}
+		}
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("proxy returned status %d", resp.StatusCode)
+		msg := fmt.Sprintf("proxy returned status %d", resp.StatusCode)
+		if id := resp.Header.Get(client.RequestIDHeader); id != "" {
+			// Quoted when the backend team is asked about the failure.
+			msg += " (request ID " + id + ")"
+		}
+		return toolResult{}, answeredError{msg}
 	}
 
-	respBody, err := io.ReadAll(resp.Body)
+	result, err := b.readToolResult(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return toolResult{}, err
 	}
+	if warning := resp.Header.Get(proxy.PreflightHeader); warning != "" {
+		result.Text += "\n\nwarning: " + warning
+	}
+	if note := resp.Header.Get(proxy.BudgetNoteHeader); note != "" {
+		result.Text += "\n\n" + note
+	}
+	return result, nil
+}
+
+// answeredError is a failure the proxy answered a call with, as opposed to
+// one reaching it.
+type answeredError struct{ msg string }
+
+func (e answeredError) Error() string { return e.msg }
+
+// proxyRestartWait is how long a call waits for a restarting proxy before
+// the client is told it is restarting.
+const proxyRestartWait = 5 * time.Second
+
+// readToolResult reads a tool's output from the proxy, keeping at most
+// MaxMessageSize bytes in memory. Anything past that is counted, dropped
+// and flagged at the end of the text, so the client never mistakes a cut
+// off payload for the whole one.
+func (b *Bridge) readToolResult(body io.Reader) (toolResult, error) {
+	max := b.maxMessageSize()
+	data, err := io.ReadAll(io.LimitReader(body, int64(max)+1))
+	if err != nil {
+		return toolResult{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if len(data) <= max {
+		result := toolResult{Text: string(data)}
+		var obj map[string]any
+		if json.Unmarshal(data, &obj) == nil {
+			result.Structured = obj
+		}
+		return result, nil
+	}
+
+	rest, err := io.Copy(io.Discard, body)
+	if err != nil {
+		return toolResult{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(data[cut]) {
+		cut--
+	}
+	dropped := int64(len(data)-cut) + rest
+	b.reportError("tool result larger than %d bytes, truncated %d bytes", max, dropped)
+	return toolResult{Text: fmt.Sprintf("%s\n\n[truncated %d bytes]", data[:cut], dropped)}, nil
+}
+
+const proxyRestartingText = "boba proxy restarting, retrying… call the tool again in a few seconds."
 
-	return string(respBody), nil
+// isProxyDown reports whether err means nothing is listening on the proxy
+// port, as happens while it restarts.
+func isProxyDown(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// waitForProxy polls /health with backoff until the proxy answers or max
+// elapses. Once it is back the session token is re-read, since a restarted
+// proxy generates a new one.
+func (b *Bridge) waitForProxy(max time.Duration) bool {
+	deadline := time.Now().Add(max)
+	delay := 100 * time.Millisecond
+	for {
+		resp, err := b.client.Get(b.proxyURL + "/health")
+		if err == nil {
+			resp.Body.Close()
+			if !b.FixedToken {
+				b.refreshSessionToken()
+			}
+			b.report(proxy.BridgeLog{Message: "proxy was unreachable and came back; retrying", Retry: true})
+			return true
+		}
+		if time.Now().Add(delay).After(deadline) {
+			b.logError("proxy still unreachable after %s: %v", max, err)
+			return false
+		}
+		time.Sleep(delay)
+		if delay < time.Second {
+			delay *= 2
+		}
+	}
 }
 
+// errFixedTokenRejected is returned when the proxy turns down a session
+// token given on the command line. There is nothing to re-read it from.
+var errFixedTokenRejected = errors.New("the proxy rejected the session token passed with --session-token; it changes whenever the proxy restarts. Run 'boba session-token print' on the proxy's machine and restart the bridge with the new token")
+
 // refreshSessionToken re-reads the session token from the system keyring.
-// This handles the case where the proxy was restarted and generated a new token.
-func (b *Bridge) refreshSessionToken() {
+// This handles the case where the proxy was restarted and generated a new
+// token. A token given on the command line can't be refreshed.
+func (b *Bridge) refreshSessionToken() error {
+	if b.FixedToken {
+		return errFixedTokenRejected
+	}
 	token, err := config.GetSessionToken()
 	if err != nil {
-		b.logError("failed to refresh session token: %v", err)
-		return
+		b.reportError("session token refresh failed: %v", err)
+		return nil
 	}
 	b.sessionToken = token
+	return nil
 }
 
 // writeResponse marshals a JSON-RPC response and writes it to stdout with a
@@ -294,7 +557,7 @@ func (b *Bridge) writeResponse(resp *JSONRPCResponse) {
 	}
 
 	if _, err := fmt.Fprintf(b.stdout, "%s\n", data); err != nil {
-		b.logError("failed to write response: %v", err)
+		b.reportError("failed to write response: %v", err)
 	}
 }
 
@@ -303,3 +566,59 @@ func (b *Bridge) writeResponse(resp *JSONRPCResponse) {
 func (b *Bridge) logError(msg string, args ...any) {
 	fmt.Fprintf(b.stderr, msg+"\n", args...)
 }
+
+// reportError logs an error that happened on the bridge's side and reports
+// it to the proxy, which wouldn't know of it otherwise.
+func (b *Bridge) reportError(msg string, args ...any) {
+	b.logError(msg, args...)
+	b.report(proxy.BridgeLog{Message: fmt.Sprintf(msg, args...)})
+}
+
+// Bridge reports are dropped rather than queued past maxPendingReports, and
+// each gets reportTimeout to reach the proxy.
+const (
+	maxPendingReports = 16
+	reportTimeout     = time.Second
+)
+
+// report sends a problem to the proxy's /bridge-log so it shows in the
+// activity log, where the user looks, instead of only on stderr, which
+// the MCP client swallows. It never blocks: reports are sent in the
+// background and dropped when the proxy can't take them.
+func (b *Bridge) report(msg proxy.BridgeLog) {
+	b.reportOnce.Do(func() {
+		b.reports = make(chan bridgeReport, maxPendingReports)
+		go b.sendReports()
+	})
+	select {
+	case b.reports <- bridgeReport{msg, b.sessionToken, b.clientID}:
+	default:
+	}
+}
+
+// bridgeReport is a queued report with the token and client ID of the
+// moment, which the request loop may change while it waits.
+type bridgeReport struct {
+	msg             proxy.BridgeLog
+	token, clientID string
+}
+
+func (b *Bridge) sendReports() {
+	httpClient := &http.Client{Timeout: reportTimeout}
+	for r := range b.reports {
+		body, err := json.Marshal(r.msg)
+		if err != nil {
+			continue
+		}
+		req, err := http.NewRequest("POST", b.proxyURL+"/bridge-log", bytes.NewReader(body))
+		if err != nil {
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+r.token)
+		req.Header.Set(proxy.ClientHeader, r.clientID)
+		if resp, err := httpClient.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+}
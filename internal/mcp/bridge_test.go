@@ -0,0 +1,478 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/client"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/zalando/go-keyring"
+)
+
+// fakeProxy answers /call for one session token, like a proxy does. It can
+// be stopped and started again on the same address with a new token.
+type fakeProxy struct {
+	t    *testing.T
+	addr string
+
+	mu      sync.Mutex
+	token   string
+	server  *http.Server
+	calls   []string
+	reports []proxy.BridgeLog
+}
+
+func (p *fakeProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	token := p.token
+	p.mu.Unlock()
+	switch r.URL.Path {
+	case "/health":
+		io.WriteString(w, `{"status":"ok"}`)
+	case "/call":
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var body struct {
+			Name string `json:"name"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		p.mu.Lock()
+		p.calls = append(p.calls, body.Name+" "+token)
+		p.mu.Unlock()
+		io.WriteString(w, `{"price":1.5}`)
+	case "/bridge-log":
+		if r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var msg proxy.BridgeLog
+		json.NewDecoder(r.Body).Decode(&msg)
+		p.mu.Lock()
+		p.reports = append(p.reports, msg)
+		p.mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// start listens on the proxy's address, a fresh one the first time, and
+// records token in the keyring the way 'boba start' does.
+func (p *fakeProxy) start(token string) {
+	p.t.Helper()
+	addr := p.addr
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	p.addr = ln.Addr().String()
+	if err := config.SetSessionToken(token); err != nil {
+		p.t.Fatal(err)
+	}
+	p.mu.Lock()
+	p.token = token
+	p.server = &http.Server{Handler: p}
+	p.mu.Unlock()
+	go p.server.Serve(ln)
+}
+
+func (p *fakeProxy) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.server.Close()
+}
+
+// A proxy restarting in the middle of a session, with a new session token,
+// is waited out: the call in flight completes and the MCP client never sees
+// an error.
+func TestBridgeSurvivesProxyRestart(t *testing.T) {
+	keyring.MockInit()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config.UseDir(filepath.Join(home, ".config"))
+
+	p := &fakeProxy{t: t}
+	p.start("token-1")
+	t.Cleanup(p.stop)
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	var stderr strings.Builder
+	b := NewBridge("http://"+p.addr, "token-1")
+	b.stdin, b.stdout, b.stderr = inR, outW, &stderr
+	done := make(chan error, 1)
+	go func() { done <- b.Run(); outW.Close() }()
+	responses := bufio.NewScanner(outR)
+
+	call := func(id int) map[string]any {
+		t.Helper()
+		fmt.Fprintf(inW, `{"jsonrpc":"2.0","id":%d,"method":"tools/call","params":{"name":"get_token_price","arguments":{}}}`+"\n", id)
+		if !responses.Scan() {
+			t.Fatalf("no response to call %d: %v", id, responses.Err())
+		}
+		var resp struct {
+			Result map[string]any `json:"result"`
+			Error  any            `json:"error"`
+		}
+		if err := json.Unmarshal(responses.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Error != nil {
+			t.Fatalf("call %d: JSON-RPC error %v", id, resp.Error)
+		}
+		return resp.Result
+	}
+	text := func(result map[string]any) string {
+		content, _ := result["content"].([]any)
+		if len(content) != 1 {
+			t.Fatalf("content = %v", result["content"])
+		}
+		s, _ := content[0].(map[string]any)["text"].(string)
+		return s
+	}
+
+	if got := text(call(1)); got != `{"price":1.5}` {
+		t.Fatalf("first call: %q", got)
+	}
+
+	p.stop()
+	go func() {
+		time.Sleep(300 * time.Millisecond)
+		p.start("token-2")
+	}()
+	got := text(call(2))
+	if got != `{"price":1.5}` {
+		t.Errorf("call across the restart: %q, want the tool result", got)
+	}
+	if strings.Contains(stderr.String(), "tools/call failed") {
+		t.Errorf("bridge reported a failure:\n%s", stderr.String())
+	}
+
+	p.mu.Lock()
+	calls := append([]string(nil), p.calls...)
+	p.mu.Unlock()
+	if want := []string{"get_token_price token-1", "get_token_price token-2"}; strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("proxy saw %q, want %q", calls, want)
+	}
+
+	inW.Close()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runBridge feeds lines to a bridge's stdin and returns what it wrote to
+// stdout, one response per line.
+func runBridge(t *testing.T, lines ...string) []string {
+	t.Helper()
+	var stdout, stderr strings.Builder
+	b := NewBridge("http://127.0.0.1:1", "token")
+	b.stdin = strings.NewReader(strings.Join(lines, "\n") + "\n")
+	b.stdout, b.stderr = &stdout, &stderr
+	if err := b.Run(); err != nil {
+		t.Fatal(err)
+	}
+	out := strings.TrimSuffix(stdout.String(), "\n")
+	if out == "" {
+		return nil
+	}
+	return strings.Split(out, "\n")
+}
+
+// The methods newer clients probe get valid empty answers, notifications
+// get none, and only unknown requests get "Method not found".
+func TestBridgeProtocol(t *testing.T) {
+	got := runBridge(t,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":1,"method":"ping"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"prompts/list"}`,
+		`{"jsonrpc":"2.0","id":"r","method":"resources/list","params":{}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"resources/templates/list"}`,
+		`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":2}}`,
+		`{"jsonrpc":"2.0","method":"sampling/unknown"}`,
+		`{"jsonrpc":"2.0","id":4,"method":"sampling/createMessage"}`,
+	)
+	want := []string{
+		`{"jsonrpc":"2.0","id":1,"result":{}}`,
+		`{"jsonrpc":"2.0","id":2,"result":{"prompts":[]}}`,
+		`{"jsonrpc":"2.0","id":"r","result":{"resources":[]}}`,
+		`{"jsonrpc":"2.0","id":3,"result":{"resourceTemplates":[]}}`,
+		`{"jsonrpc":"2.0","id":4,"error":{"code":-32601,"message":"Method not found"}}`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("responses:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// initialize echoes a protocol version the bridge speaks, falls back to
+// its newest otherwise, and advertises tools only.
+func TestBridgeInitialize(t *testing.T) {
+	for _, tc := range []struct{ requested, want string }{
+		{"2025-06-18", "2025-06-18"},
+		{"2025-03-26", "2025-03-26"},
+		{"2024-11-05", "2024-11-05"},
+		{"2099-01-01", protocolVersions[0]},
+		{"", protocolVersions[0]},
+	} {
+		got := runBridge(t, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+tc.requested+`","clientInfo":{"name":"claude-code"}}}`)
+		if len(got) != 1 {
+			t.Fatalf("%q: %d responses", tc.requested, len(got))
+		}
+		var resp struct {
+			Result struct {
+				ProtocolVersion string                     `json:"protocolVersion"`
+				Capabilities    map[string]json.RawMessage `json:"capabilities"`
+				ServerInfo      struct{ Name string }      `json:"serverInfo"`
+			} `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(got[0]), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Result.ProtocolVersion != tc.want {
+			t.Errorf("requested %q: got %q, want %q", tc.requested, resp.Result.ProtocolVersion, tc.want)
+		}
+		if len(resp.Result.Capabilities) != 1 || resp.Result.Capabilities["tools"] == nil {
+			t.Errorf("capabilities = %s, want tools only", got[0])
+		}
+		if resp.Result.ServerInfo.Name != "boba" {
+			t.Errorf("serverInfo = %s", got[0])
+		}
+	}
+}
+
+// A request line well past bufio.Scanner's 64KB default reaches the proxy
+// whole, and a result over 1MB comes back whole, as text and structured
+// content.
+func TestBridgeLargeMessages(t *testing.T) {
+	big := strings.Repeat("é", 600_000) // 1.2MB of two-byte runes
+	var received int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Arguments map[string]string `json:"arguments"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		received = len(body.Arguments["memo"])
+		fmt.Fprintf(w, `{"holders":%q}`, big)
+	}))
+	t.Cleanup(srv.Close)
+
+	memo := strings.Repeat("x", 100_000)
+	call := `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_holders","arguments":{"memo":"` + memo + `"}}}`
+	initialize := `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`
+
+	var stdout strings.Builder
+	b := NewBridge(srv.URL, "token")
+	b.stdin = strings.NewReader(initialize + "\n" + call + "\n")
+	b.stdout, b.stderr = &stdout, io.Discard
+	if err := b.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if received != len(memo) {
+		t.Errorf("proxy got a %d byte argument, want %d", received, len(memo))
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	var resp struct {
+		Result struct {
+			Content           []struct{ Text string } `json:"content"`
+			StructuredContent map[string]string       `json:"structuredContent"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if want := fmt.Sprintf(`{"holders":%q}`, big); resp.Result.Content[0].Text != want {
+		t.Errorf("text is %d bytes, want the whole %d", len(resp.Result.Content[0].Text), len(want))
+	}
+	if resp.Result.StructuredContent["holders"] != big {
+		t.Error("structured content missing or cut")
+	}
+}
+
+// Past MaxMessageSize a result is cut on a rune boundary and says how much
+// was dropped, and an oversized request is refused without losing the
+// requests after it.
+func TestBridgeMessageLimit(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, strings.Repeat("é", 1000)) // 2000 bytes
+	}))
+	t.Cleanup(srv.Close)
+
+	var stdout strings.Builder
+	b := NewBridge(srv.URL, "token")
+	b.MaxMessageSize = 1001
+	b.stdin = strings.NewReader(
+		`{"jsonrpc":"2.0","id":1,"method":"ping","params":{"pad":"` + strings.Repeat("x", 2000) + `"}}` + "\n" +
+			`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_holders"}}` + "\n")
+	b.stdout, b.stderr = &stdout, io.Discard
+	if err := b.Run(); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%d responses, want 2:\n%s", len(lines), stdout.String())
+	}
+	if want := `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"request exceeds the 1001 byte limit"}}`; lines[0] != want {
+		t.Errorf("oversized request: %s", lines[0])
+	}
+	var resp struct {
+		Result struct {
+			Content []struct{ Text string } `json:"content"`
+		} `json:"result"`
+	}
+	json.Unmarshal([]byte(lines[1]), &resp)
+	want := strings.Repeat("é", 500) + "\n\n[truncated 1000 bytes]"
+	if got := resp.Result.Content[0].Text; got != want {
+		t.Errorf("truncated result = %q…, want 500 runes and the marker", got[max(0, len(got)-40):])
+	}
+}
+
+// A session token given for a remote proxy is used as is: when the proxy
+// rejects it, the bridge says to restart with a new one rather than falling
+// back to this machine's keyring.
+func TestBridgeFixedToken(t *testing.T) {
+	keyring.MockInit()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config.UseDir(filepath.Join(home, ".config"))
+
+	p := &fakeProxy{t: t}
+	p.start("remote-1")
+	t.Cleanup(p.stop)
+
+	call := func(token string, fixed bool) string {
+		t.Helper()
+		var stdout, stderr strings.Builder
+		b := NewBridge("http://"+p.addr, token)
+		b.FixedToken = fixed
+		b.stdin = strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_token_price","arguments":{}}}` + "\n")
+		b.stdout, b.stderr = &stdout, &stderr
+		if err := b.Run(); err != nil {
+			t.Fatal(err)
+		}
+		return stdout.String()
+	}
+
+	if out := call("remote-1", true); !strings.Contains(out, `{\"price\":1.5}`) {
+		t.Errorf("valid fixed token: %s", out)
+	}
+
+	// The keyring holds the proxy's new token, but a fixed token never
+	// reads it.
+	p.stop()
+	p.start("remote-2")
+	out := call("remote-1", true)
+	if !strings.Contains(out, "boba session-token print") || !strings.Contains(out, "restart the bridge") {
+		t.Errorf("rejected fixed token: %s", out)
+	}
+	if out := call("remote-1", false); !strings.Contains(out, `{\"price\":1.5}`) {
+		t.Errorf("keyring token not picked up without a fixed token: %s", out)
+	}
+
+	p.mu.Lock()
+	calls := strings.Join(p.calls, ",")
+	p.mu.Unlock()
+	if want := "get_token_price remote-1,get_token_price remote-2"; calls != want {
+		t.Errorf("proxy saw %q, want %q", calls, want)
+	}
+}
+
+// A stale session token is re-read and the call retried, and the proxy
+// hears about it, since the bridge's stderr goes nowhere the user looks.
+func TestBridgeReportsRetry(t *testing.T) {
+	keyring.MockInit()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config.UseDir(filepath.Join(home, ".config"))
+
+	p := &fakeProxy{t: t}
+	p.start("token-2")
+	t.Cleanup(p.stop)
+
+	var stdout, stderr strings.Builder
+	b := NewBridge("http://"+p.addr, "token-1")
+	b.stdin = strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_token_price","arguments":{}}}` + "\n")
+	b.stdout, b.stderr = &stdout, &stderr
+	if err := b.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stdout.String(), `{\"price\":1.5}`) {
+		t.Errorf("call not retried with the new token: %s", stdout.String())
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		p.mu.Lock()
+		reports := append([]proxy.BridgeLog(nil), p.reports...)
+		p.mu.Unlock()
+		if len(reports) > 0 {
+			if r := reports[0]; !r.Retry || !strings.Contains(r.Message, "session token rejected on get_token_price") {
+				t.Errorf("report = %+v", r)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("no report reached the proxy")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Reports never hold up the bridge, even when the proxy takes connections
+// and never answers them.
+func TestBridgeReportNonBlocking(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var stderr strings.Builder
+	b := NewBridge("http://"+ln.Addr().String(), "token")
+	b.stderr = &stderr
+	start := time.Now()
+	for i := range 10 * maxPendingReports {
+		b.reportError("failure %d", i)
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("reporting took %s", d)
+	}
+	if n := strings.Count(stderr.String(), "\n"); n != 10*maxPendingReports {
+		t.Errorf("%d errors on stderr, want all %d", n, 10*maxPendingReports)
+	}
+}
+
+// A failure the proxy answered names its request ID, for the backend team.
+func TestBridgeErrorRequestID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(client.RequestIDHeader, "backend-req-42")
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+
+	var stdout, stderr strings.Builder
+	b := NewBridge(srv.URL, "token")
+	b.stdin = strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"execute_swap","arguments":{}}}` + "\n")
+	b.stdout, b.stderr = &stdout, &stderr
+	if err := b.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(stdout.String(), "proxy returned status 502 (request ID backend-req-42)") {
+		t.Errorf("response: %s", stdout.String())
+	}
+}
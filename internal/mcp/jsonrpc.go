@@ -26,3 +26,11 @@ type ToolCallParams struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]any `json:"arguments,omitempty"`
 }
+
+type InitializeParams struct {
+	ProtocolVersion string `json:"protocolVersion"`
+	ClientInfo      struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"clientInfo"`
+}
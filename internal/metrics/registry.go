@@ -0,0 +1,59 @@
+// Package metrics defines the proxy's Prometheus metrics. The same registry
+// drives the /metrics exposition and the generated alert rules and
+// dashboards, so metric names can't drift between them.
+package metrics
+
+// Type is a Prometheus metric type.
+type Type string
+
+const (
+	Counter   Type = "counter"
+	Gauge     Type = "gauge"
+	Histogram Type = "histogram"
+)
+
+// Def describes one metric.
+type Def struct {
+	Name   string
+	Type   Type
+	Help   string
+	Labels []string
+}
+
+// Metric names. Anything that refers to a metric by name uses these.
+const (
+	ToolCalls         = "boba_tool_calls_total"
+	ToolErrors        = "boba_tool_errors_total"
+	ToolDuration      = "boba_tool_call_duration_seconds"
+	CacheHits         = "boba_tool_cache_hits_total"
+	AuthFailures      = "boba_auth_failures_total"
+	AuthRefreshes     = "boba_auth_refreshes_total"
+	PortfolioPollOK   = "boba_portfolio_poll_last_success_timestamp_seconds"
+	UptimeSeconds     = "boba_uptime_seconds"
+	InFlightToolCalls = "boba_tool_calls_in_flight"
+	ChaosInjected     = "boba_chaos_injected_total"
+)
+
+// Registry lists every metric the proxy exposes.
+var Registry = []Def{
+	{Name: ToolCalls, Type: Counter, Help: "Tool calls handled, by tool.", Labels: []string{"tool"}},
+	{Name: ToolErrors, Type: Counter, Help: "Tool calls that failed, by tool and kind: rejected, auth, timeout, network, server, or other for calls the proxy refused.", Labels: []string{"tool", "kind"}},
+	{Name: ToolDuration, Type: Histogram, Help: "Tool call latency in seconds, by tool.", Labels: []string{"tool"}},
+	{Name: CacheHits, Type: Counter, Help: "Tool calls answered from the proxy's response cache, by tool. Not included in the call counts.", Labels: []string{"tool"}},
+	{Name: AuthFailures, Type: Counter, Help: "Failed authentications against the Boba backend."},
+	{Name: AuthRefreshes, Type: Counter, Help: "Access token refreshes."},
+	{Name: PortfolioPollOK, Type: Gauge, Help: "Unix time of the last successful portfolio poll."},
+	{Name: UptimeSeconds, Type: Gauge, Help: "Seconds since the proxy started."},
+	{Name: InFlightToolCalls, Type: Gauge, Help: "Tool calls currently being handled."},
+	{Name: ChaosInjected, Type: Counter, Help: "Failures injected by chaos testing, by kind. Not included in the error counts.", Labels: []string{"kind"}},
+}
+
+// Lookup returns the definition of a metric by name.
+func Lookup(name string) (Def, bool) {
+	for _, d := range Registry {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Def{}, false
+}
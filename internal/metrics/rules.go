@@ -0,0 +1,155 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RuleOptions parameterizes the generated alert rules.
+type RuleOptions struct {
+	// Job is the Prometheus job label the proxy is scraped under.
+	Job string
+	// ErrorRate is the fraction of failed tool calls (0.05 = 5%) over
+	// 5 minutes that raises an alert.
+	ErrorRate float64
+	// AuthFailures is how many backend auth failures in 10 minutes are
+	// tolerated before alerting.
+	AuthFailures int
+	// PollStale is how long without a successful portfolio poll raises an
+	// alert.
+	PollStale time.Duration
+}
+
+// Rule is one Prometheus alerting rule.
+type Rule struct {
+	Alert    string
+	Expr     string
+	For      string
+	Severity string
+	Summary  string
+}
+
+// Rules returns the alerting rules for a proxy scraped as opts.Job.
+func Rules(opts RuleOptions) []Rule {
+	sel := fmt.Sprintf(`{job=%q}`, opts.Job)
+	return []Rule{
+		{
+			Alert:    "BobaProxyDown",
+			Expr:     "up" + sel + " == 0",
+			For:      "2m",
+			Severity: "critical",
+			Summary:  "The Boba proxy is not responding to scrapes.",
+		},
+		{
+			Alert: "BobaToolErrorRate",
+			Expr: fmt.Sprintf("sum(rate(%s%s[5m])) / sum(rate(%s%s[5m])) > %g",
+				ToolErrors, sel, ToolCalls, sel, opts.ErrorRate),
+			For:      "5m",
+			Severity: "warning",
+			Summary:  fmt.Sprintf("More than %g%% of tool calls are failing.", opts.ErrorRate*100),
+		},
+		{
+			Alert:    "BobaAuthFailures",
+			Expr:     fmt.Sprintf("increase(%s%s[10m]) > %d", AuthFailures, sel, opts.AuthFailures),
+			Severity: "critical",
+			Summary:  "The proxy is failing to authenticate with the Boba backend.",
+		},
+		{
+			Alert:    "BobaPortfolioPollStale",
+			Expr:     fmt.Sprintf("time() - %s%s > %d", PortfolioPollOK, sel, int(opts.PollStale.Seconds())),
+			Severity: "warning",
+			Summary:  fmt.Sprintf("No successful portfolio poll in %s.", shortDuration(opts.PollStale)),
+		},
+	}
+}
+
+// AlertRulesYAML renders the rules as a Prometheus rules file.
+func AlertRulesYAML(opts RuleOptions) string {
+	var b strings.Builder
+	b.WriteString("# Generated by boba metrics rules. Metric names match the proxy's /metrics.\n")
+	b.WriteString("groups:\n")
+	b.WriteString("  - name: boba\n")
+	b.WriteString("    rules:\n")
+	for _, r := range Rules(opts) {
+		fmt.Fprintf(&b, "      - alert: %s\n", r.Alert)
+		fmt.Fprintf(&b, "        expr: %s\n", yamlQuote(r.Expr))
+		if r.For != "" {
+			fmt.Fprintf(&b, "        for: %s\n", r.For)
+		}
+		b.WriteString("        labels:\n")
+		fmt.Fprintf(&b, "          severity: %s\n", r.Severity)
+		b.WriteString("        annotations:\n")
+		fmt.Fprintf(&b, "          summary: %s\n", yamlQuote(r.Summary))
+	}
+	return b.String()
+}
+
+// yamlQuote renders s as a single-quoted YAML scalar, which needs no
+// escaping beyond doubling single quotes.
+func yamlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// shortDuration renders whole hours and minutes as 2h or 15m.
+func shortDuration(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return d.String()
+}
+
+// GrafanaDashboard returns a basic Grafana dashboard with request rate,
+// latency quantiles and per-tool errors.
+func GrafanaDashboard(job string) ([]byte, error) {
+	sel := fmt.Sprintf(`{job=%q}`, job)
+	panel := func(id int, title, unit string, y int, targets ...map[string]any) map[string]any {
+		return map[string]any{
+			"id":         id,
+			"type":       "timeseries",
+			"title":      title,
+			"datasource": map[string]any{"type": "prometheus", "uid": "${datasource}"},
+			"gridPos":    map[string]any{"h": 8, "w": 24, "x": 0, "y": y},
+			"fieldConfig": map[string]any{
+				"defaults":  map[string]any{"unit": unit},
+				"overrides": []any{},
+			},
+			"targets": targets,
+		}
+	}
+	target := func(ref, expr, legend string) map[string]any {
+		return map[string]any{"refId": ref, "expr": expr, "legendFormat": legend}
+	}
+	quantile := func(ref string, q float64) map[string]any {
+		return target(ref,
+			fmt.Sprintf("histogram_quantile(%g, sum by (le) (rate(%s_bucket%s[5m])))", q, ToolDuration, sel),
+			fmt.Sprintf("p%g", q*100))
+	}
+
+	dashboard := map[string]any{
+		"title":         "Boba proxy",
+		"uid":           "boba-proxy",
+		"schemaVersion": 39,
+		"time":          map[string]any{"from": "now-6h", "to": "now"},
+		"templating": map[string]any{
+			"list": []any{map[string]any{
+				"name":  "datasource",
+				"type":  "datasource",
+				"query": "prometheus",
+			}},
+		},
+		"panels": []any{
+			panel(1, "Request rate", "reqps", 0,
+				target("A", fmt.Sprintf("sum by (tool) (rate(%s%s[5m]))", ToolCalls, sel), "{{tool}}")),
+			panel(2, "Latency", "s", 8,
+				quantile("A", 0.5), quantile("B", 0.95), quantile("C", 0.99)),
+			panel(3, "Errors by tool", "reqps", 16,
+				target("A", fmt.Sprintf("sum by (tool) (rate(%s%s[5m]))", ToolErrors, sel), "{{tool}}")),
+		},
+	}
+	return json.MarshalIndent(dashboard, "", "  ")
+}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"encoding/json"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	metricName = regexp.MustCompile(`\bboba_[a-z_]+`)
+	byLabels   = regexp.MustCompile(`by \(([a-z_, ]+)\)`)
+)
+
+// checkExpr fails the test unless every metric expr names is in the
+// registry, with the _bucket series only on histograms, and every label it
+// groups by is one that metric carries.
+func checkExpr(t *testing.T, where, expr string) {
+	t.Helper()
+	names := metricName.FindAllString(expr, -1)
+	if len(names) == 0 && !strings.HasPrefix(expr, "up{") {
+		t.Errorf("%s: %q names no boba metric", where, expr)
+	}
+	var defs []Def
+	for _, name := range names {
+		base, bucket := strings.CutSuffix(name, "_bucket")
+		def, ok := Lookup(base)
+		if !ok {
+			t.Errorf("%s: %s is not in the registry", where, name)
+			continue
+		}
+		if bucket && def.Type != Histogram {
+			t.Errorf("%s: %s of a %s", where, name, def.Type)
+		}
+		defs = append(defs, def)
+	}
+	for _, m := range byLabels.FindAllStringSubmatch(expr, -1) {
+		for _, label := range strings.Split(m[1], ",") {
+			label = strings.TrimSpace(label)
+			for _, def := range defs {
+				if label != "le" && !slices.Contains(def.Labels, label) {
+					t.Errorf("%s: groups %s by %s, which it doesn't have", where, def.Name, label)
+				}
+			}
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	seen := map[string]bool{}
+	for _, def := range Registry {
+		if seen[def.Name] {
+			t.Errorf("%s registered twice", def.Name)
+		}
+		seen[def.Name] = true
+		if !strings.HasPrefix(def.Name, "boba_") || def.Help == "" {
+			t.Errorf("%s: want a boba_ name and help text", def.Name)
+		}
+		if def.Type == Counter && !strings.HasSuffix(def.Name, "_total") {
+			t.Errorf("counter %s should end in _total", def.Name)
+		}
+	}
+}
+
+func TestAlertRulesYAML(t *testing.T) {
+	opts := RuleOptions{Job: "boba", ErrorRate: 0.05, AuthFailures: 3, PollStale: 15 * time.Minute}
+	out := AlertRulesYAML(opts)
+	if !strings.HasPrefix(out, "# Generated") || !strings.Contains(out, "groups:\n  - name: boba\n    rules:\n") {
+		t.Fatalf("unexpected header:\n%s", out)
+	}
+
+	// Read the rules back from the file rather than from Rules, so the
+	// quoting is checked too.
+	var alerts, exprs []string
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ": ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "- alert":
+			alerts = append(alerts, value)
+		case "expr":
+			if !strings.HasPrefix(value, "'") || !strings.HasSuffix(value, "'") {
+				t.Errorf("expr not quoted: %s", value)
+			}
+			exprs = append(exprs, strings.ReplaceAll(value[1:len(value)-1], "''", "'"))
+		}
+	}
+	want := []string{"BobaProxyDown", "BobaToolErrorRate", "BobaAuthFailures", "BobaPortfolioPollStale"}
+	if !slices.Equal(alerts, want) || len(exprs) != len(want) {
+		t.Fatalf("alerts %v with %d exprs, want %v", alerts, len(exprs), want)
+	}
+	for i, expr := range exprs {
+		if !strings.Contains(expr, `{job="boba"}`) {
+			t.Errorf("%s: %q doesn't select the job", alerts[i], expr)
+		}
+		checkExpr(t, alerts[i], expr)
+	}
+
+	for _, want := range []string{"> 0.05", "[10m]) > 3", "> 900", "More than 5% of tool calls", "in 15m."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("rules lack %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestGrafanaDashboard(t *testing.T) {
+	data, err := GrafanaDashboard("boba")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dash struct {
+		Panels []struct {
+			Title   string `json:"title"`
+			Targets []struct {
+				RefID string `json:"refId"`
+				Expr  string `json:"expr"`
+			} `json:"targets"`
+		} `json:"panels"`
+	}
+	if err := json.Unmarshal(data, &dash); err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, p := range dash.Panels {
+		titles = append(titles, p.Title)
+		if len(p.Targets) == 0 {
+			t.Errorf("%s has no queries", p.Title)
+		}
+		for _, target := range p.Targets {
+			checkExpr(t, p.Title+"/"+target.RefID, target.Expr)
+		}
+	}
+	if want := []string{"Request rate", "Latency", "Errors by tool"}; !slices.Equal(titles, want) {
+		t.Errorf("panels %v, want %v", titles, want)
+	}
+}
@@ -0,0 +1,302 @@
+// Package orders implements bulk actions on resting orders: listing every
+// active limit, DCA and TWAP order and cancelling or pausing them in one go.
+// It only needs a way to call tools, so the CLI and the TUI can share it.
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+)
+
+// CallFunc makes one tool call and returns the raw response body. It gives
+// up when ctx is cancelled.
+type CallFunc func(ctx context.Context, tool string, args map[string]any) ([]byte, error)
+
+// Kind is an order type and the tools that act on it.
+type Kind struct {
+	Name   string
+	List   string
+	Cancel string
+	Pause  string // empty when the order type can't be paused
+}
+
+// Kinds lists every order type in the order they are shown.
+var Kinds = []Kind{
+	{Name: "limit", List: "get_limit_orders", Cancel: "cancel_limit_order"},
+	{Name: "dca", List: "get_dca_orders", Cancel: "cancel_dca_order", Pause: "pause_dca_order"},
+	{Name: "twap", List: "get_twap_orders", Cancel: "cancel_twap_order", Pause: "pause_twap_order"},
+}
+
+// KindByName returns the order type called name.
+func KindByName(name string) (Kind, bool) {
+	for _, k := range Kinds {
+		if k.Name == strings.ToLower(name) {
+			return k, true
+		}
+	}
+	return Kind{}, false
+}
+
+// Action is a bulk operation.
+type Action string
+
+const (
+	Cancel Action = "cancel"
+	Pause  Action = "pause"
+)
+
+// ConfirmPhrase is what the user types to confirm the action on n orders,
+// e.g. "cancel 7 orders".
+func (a Action) ConfirmPhrase(n int) string {
+	if n == 1 {
+		return fmt.Sprintf("%s 1 order", a)
+	}
+	return fmt.Sprintf("%s %d orders", a, n)
+}
+
+// Tool returns the tool that applies the action to an order of kind k.
+func (a Action) Tool(k Kind) string {
+	if a == Pause {
+		return k.Pause
+	}
+	return k.Cancel
+}
+
+// Order is one resting order.
+type Order struct {
+	ID     string
+	Kind   Kind
+	Chain  string // slug, when the chain is a known one
+	Status string
+	Amount string
+	// ValueUSD is the estimated value; HasValue is false when the order
+	// carries no price data.
+	ValueUSD float64
+	HasValue bool
+	// Raw is the order as the backend listed it.
+	Raw map[string]any
+}
+
+// Filter selects orders for a bulk action. Empty fields match everything;
+// Chain matches whichever name, alias or ID the backend gives the chain.
+type Filter struct {
+	Kind  string
+	Chain string
+}
+
+// Active lists the orders an action would apply to: every unfinished order
+// for cancel, and running DCA/TWAP orders for pause.
+func Active(ctx context.Context, call CallFunc, action Action, f Filter) ([]Order, error) {
+	var out []Order
+	for _, k := range Kinds {
+		if f.Kind != "" && k.Name != f.Kind {
+			continue
+		}
+		if action.Tool(k) == "" {
+			continue
+		}
+		body, err := call(ctx, k.List, map[string]any{})
+		if err != nil {
+			return nil, fmt.Errorf("listing %s orders: %w", k.Name, err)
+		}
+		var data map[string]any
+		if err := json.Unmarshal(body, &data); err != nil {
+			return nil, fmt.Errorf("listing %s orders: %w", k.Name, err)
+		}
+		list, _ := data["orders"].([]any)
+		for _, o := range list {
+			order, ok := o.(map[string]any)
+			if !ok {
+				continue
+			}
+			id, _ := order["id"].(string)
+			status, _ := order["status"].(string)
+			chain := orderChain(order)
+			if id == "" || !applies(action, strings.ToLower(status)) {
+				continue
+			}
+			if f.Chain != "" && !sameChain(chain, f.Chain) {
+				continue
+			}
+			value, hasValue := formatter.OrderValueUSD(order)
+			out = append(out, Order{
+				ID:       id,
+				Kind:     k,
+				Chain:    chain,
+				Status:   status,
+				Amount:   formatter.OrderAmount(order),
+				ValueUSD: value,
+				HasValue: hasValue,
+				Raw:      order,
+			})
+		}
+	}
+	return out, nil
+}
+
+// orderChain returns the slug of the chain an order is on, which the
+// backend may give by name, alias or numeric ID, or the chain as sent when
+// it isn't a known one.
+func orderChain(order map[string]any) string {
+	v, ok := order["chain"]
+	if !ok {
+		v = order["chain_id"]
+	}
+	var c config.Chain
+	switch t := v.(type) {
+	case float64:
+		c, ok = config.LookupChainID(int(t))
+	case string:
+		if id, err := strconv.Atoi(t); err == nil {
+			c, ok = config.LookupChainID(id)
+		} else if c, ok = config.LookupChain(t); !ok {
+			return t
+		}
+	default:
+		return ""
+	}
+	if !ok {
+		return fmt.Sprint(v)
+	}
+	return c.Slug
+}
+
+// sameChain reports whether two chains given by slug, name or alias are
+// the same one.
+func sameChain(a, b string) bool {
+	if ca, ok := config.LookupChain(a); ok {
+		if cb, ok := config.LookupChain(b); ok {
+			return ca.Slug == cb.Slug
+		}
+	}
+	return strings.EqualFold(a, b)
+}
+
+// applies reports whether an order in the given status can take the action.
+func applies(action Action, status string) bool {
+	switch status {
+	case "completed", "filled", "executed", "cancelled", "canceled", "expired", "failed":
+		return false
+	case "paused":
+		return action == Cancel
+	}
+	return true
+}
+
+// TotalUSD sums the estimated value of the orders. The count is how many had
+// no price data and are left out of the total.
+func TotalUSD(list []Order) (float64, int) {
+	var total float64
+	unknown := 0
+	for _, o := range list {
+		if o.HasValue {
+			total += o.ValueUSD
+		} else {
+			unknown++
+		}
+	}
+	return total, unknown
+}
+
+// Result is the outcome of the action on one order.
+type Result struct {
+	Order Order
+	Err   error
+}
+
+// concurrency bounds how many orders are acted on at once.
+const concurrency = 4
+
+// retryDelay is the pause before retrying a transient failure.
+var retryDelay = time.Second
+
+// Apply runs the action on every order, a few at a time, retrying transient
+// failures once unless ctx is cancelled first. Results are in the same
+// order as list.
+func Apply(ctx context.Context, call CallFunc, action Action, list []Order) []Result {
+	results := make([]Result, len(list))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, o := range list {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			err := applyOne(ctx, call, action, o)
+			if err != nil && transient(err) && ctx.Err() == nil {
+				select {
+				case <-ctx.Done():
+				case <-time.After(retryDelay):
+					err = applyOne(ctx, call, action, o)
+				}
+			}
+			results[i] = Result{Order: o, Err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func applyOne(ctx context.Context, call CallFunc, action Action, o Order) error {
+	body, err := call(ctx, action.Tool(o.Kind), map[string]any{"order_id": o.ID})
+	if err != nil {
+		return err
+	}
+	var resp map[string]any
+	if json.Unmarshal(body, &resp) != nil {
+		return nil
+	}
+	if ok, present := resp["success"].(bool); present && !ok {
+		msg, _ := resp["error"].(string)
+		if msg == "" {
+			msg = fmt.Sprintf("backend refused to %s the order", action)
+		}
+		return refusal(msg)
+	}
+	return nil
+}
+
+// refusal is a well-formed response saying the action failed. Retrying it
+// would get the same answer.
+type refusal string
+
+func (r refusal) Error() string { return string(r) }
+
+// transient reports whether a failure is worth retrying: transport errors,
+// rate limiting and server errors.
+func transient(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var refused refusal
+	if errors.As(err, &refused) {
+		return false
+	}
+	var status interface{ StatusCode() int }
+	if errors.As(err, &status) {
+		code := status.StatusCode()
+		return code == 429 || code >= 500
+	}
+	return true
+}
+
+// Failed returns the orders the action did not apply to.
+func Failed(results []Result) []Result {
+	var out []Result
+	for _, r := range results {
+		if r.Err != nil {
+			out = append(out, r)
+		}
+	}
+	return out
+}
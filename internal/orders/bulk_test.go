@@ -0,0 +1,228 @@
+package orders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// statusErr is an HTTP failure with a status code, like the client's.
+type statusErr int
+
+func (e statusErr) Error() string   { return fmt.Sprintf("status %d", int(e)) }
+func (e statusErr) StatusCode() int { return int(e) }
+
+// fakeBackend answers the list tools from lists and the cancel and pause
+// tools from fail, counting calls and the most made at once.
+type fakeBackend struct {
+	lists map[string]string
+	// fail holds the errors each order's calls return in turn; a call
+	// past the end succeeds.
+	fail  map[string][]error
+	delay time.Duration
+
+	mu       sync.Mutex
+	calls    map[string]int
+	inFlight int
+	peak     int
+}
+
+func (b *fakeBackend) call(ctx context.Context, tool string, args map[string]any) ([]byte, error) {
+	if list, ok := b.lists[tool]; ok {
+		return []byte(list), nil
+	}
+	id, _ := args["order_id"].(string)
+	b.mu.Lock()
+	if b.calls == nil {
+		b.calls = make(map[string]int)
+	}
+	n := b.calls[id]
+	b.calls[id]++
+	b.inFlight++
+	b.peak = max(b.peak, b.inFlight)
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.inFlight--
+		b.mu.Unlock()
+	}()
+
+	time.Sleep(b.delay)
+	if errs := b.fail[id]; n < len(errs) {
+		if refused, ok := errs[n].(refusal); ok {
+			return []byte(fmt.Sprintf(`{"success":false,"error":%q}`, string(refused))), nil
+		}
+		return nil, errs[n]
+	}
+	return []byte(`{"success":true}`), nil
+}
+
+func ids(list []Order) []string {
+	out := make([]string, len(list))
+	for i, o := range list {
+		out[i] = o.ID
+	}
+	return out
+}
+
+func TestActive(t *testing.T) {
+	b := &fakeBackend{lists: map[string]string{
+		"get_limit_orders": `{"orders":[
+			{"id":"l1","status":"active","chain":"ethereum"},
+			{"id":"l2","status":"open","chain":"Ethereum"},
+			{"id":"l3","status":"pending","chain":1},
+			{"id":"l4","status":"active","chain":"8453"},
+			{"id":"l5","status":"filled","chain":"eth"},
+			{"status":"active","chain":"eth"}
+		]}`,
+		"get_dca_orders": `{"orders":[
+			{"id":"d1","status":"running","chain":"eth"},
+			{"id":"d2","status":"paused","chain":"solana"},
+			{"id":"d3","status":"cancelled","chain":"eth"}
+		]}`,
+		"get_twap_orders": `{"orders":[{"id":"t1","status":"active","chain_id":"1"},{"id":"t2","status":"active","chain":"zkfoo"}]}`,
+	}}
+	ctx := context.Background()
+	for _, tc := range []struct {
+		action Action
+		filter Filter
+		want   []string
+	}{
+		{Cancel, Filter{}, []string{"l1", "l2", "l3", "l4", "d1", "d2", "t1", "t2"}},
+		{Cancel, Filter{Chain: "eth"}, []string{"l1", "l2", "l3", "d1", "t1"}},
+		{Cancel, Filter{Chain: "ethereum"}, []string{"l1", "l2", "l3", "d1", "t1"}},
+		{Cancel, Filter{Chain: "base"}, []string{"l4"}},
+		{Cancel, Filter{Chain: "zkfoo"}, []string{"t2"}},
+		{Cancel, Filter{Kind: "dca"}, []string{"d1", "d2"}},
+		{Pause, Filter{}, []string{"d1", "t1", "t2"}},
+		{Pause, Filter{Kind: "limit"}, nil},
+	} {
+		list, err := Active(ctx, b.call, tc.action, tc.filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(list); !slices.Equal(got, tc.want) {
+			t.Errorf("%s %+v: %v, want %v", tc.action, tc.filter, got, tc.want)
+		}
+	}
+
+	list, _ := Active(ctx, b.call, Cancel, Filter{Kind: "limit"})
+	if list[0].Chain != "eth" || list[2].Chain != "eth" || list[3].Chain != "base" {
+		t.Errorf("chains = %q, %q, %q; want slugs", list[0].Chain, list[2].Chain, list[3].Chain)
+	}
+
+	b.lists["get_dca_orders"] = `not json`
+	if _, err := Active(ctx, b.call, Cancel, Filter{}); err == nil {
+		t.Error("unreadable listing accepted")
+	}
+}
+
+func testOrders(n int) []Order {
+	var list []Order
+	for i := range n {
+		list = append(list, Order{ID: fmt.Sprintf("o%d", i), Kind: Kinds[0]})
+	}
+	return list
+}
+
+// No more than concurrency orders are acted on at once, and results keep
+// the order of the list.
+func TestApplyConcurrency(t *testing.T) {
+	b := &fakeBackend{delay: 20 * time.Millisecond}
+	list := testOrders(12)
+	results := Apply(context.Background(), b.call, Cancel, list)
+	if b.peak > concurrency || b.peak < 2 {
+		t.Errorf("%d calls at once, want 2 to %d", b.peak, concurrency)
+	}
+	for i, r := range results {
+		if r.Order.ID != list[i].ID || r.Err != nil {
+			t.Errorf("result %d = %s %v", i, r.Order.ID, r.Err)
+		}
+	}
+}
+
+// Transient failures are retried once; refusals and client errors aren't.
+func TestApplyRetry(t *testing.T) {
+	retryDelay = time.Millisecond
+	t.Cleanup(func() { retryDelay = time.Second })
+
+	b := &fakeBackend{fail: map[string][]error{
+		"o0": {errors.New("connection reset")},
+		"o1": {statusErr(503), statusErr(502)},
+		"o2": {statusErr(429)},
+		"o3": {refusal("order already filled")},
+		"o4": {statusErr(404)},
+	}}
+	results := Apply(context.Background(), b.call, Cancel, testOrders(6))
+	for _, tc := range []struct {
+		id    string
+		calls int
+		ok    bool
+	}{
+		{"o0", 2, true},
+		{"o1", 2, false},
+		{"o2", 2, true},
+		{"o3", 1, false},
+		{"o4", 1, false},
+		{"o5", 1, true},
+	} {
+		var r Result
+		for _, r = range results {
+			if r.Order.ID == tc.id {
+				break
+			}
+		}
+		if b.calls[tc.id] != tc.calls || (r.Err == nil) != tc.ok {
+			t.Errorf("%s: %d calls, err %v; want %d calls, ok %v", tc.id, b.calls[tc.id], r.Err, tc.calls, tc.ok)
+		}
+	}
+	if got := ids(failedOrders(Failed(results))); !slices.Equal(got, []string{"o1", "o3", "o4"}) {
+		t.Errorf("Failed = %v", got)
+	}
+}
+
+func failedOrders(results []Result) []Order {
+	out := make([]Order, len(results))
+	for i, r := range results {
+		out[i] = r.Order
+	}
+	return out
+}
+
+// A cancelled context ends the wait before a retry rather than retrying.
+func TestApplyRetryCancelled(t *testing.T) {
+	retryDelay = time.Minute
+	t.Cleanup(func() { retryDelay = time.Second })
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &fakeBackend{fail: map[string][]error{"o0": {statusErr(500)}}}
+
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	results := Apply(ctx, b.call, Cancel, testOrders(1))
+	if took := time.Since(start); took > 5*time.Second {
+		t.Errorf("Apply waited %s after the cancel", took)
+	}
+	if results[0].Err == nil || b.calls["o0"] != 1 {
+		t.Errorf("err %v after %d calls, want the first failure and no retry", results[0].Err, b.calls["o0"])
+	}
+}
+
+func TestConfirmPhrase(t *testing.T) {
+	if got := Cancel.ConfirmPhrase(7); got != "cancel 7 orders" {
+		t.Errorf("ConfirmPhrase(7) = %q", got)
+	}
+	if got := Pause.ConfirmPhrase(1); got != "pause 1 order" {
+		t.Errorf("ConfirmPhrase(1) = %q", got)
+	}
+}
+
+func TestTotalUSD(t *testing.T) {
+	total, unknown := TotalUSD([]Order{{ValueUSD: 10, HasValue: true}, {}, {ValueUSD: 2.5, HasValue: true}})
+	if total != 12.5 || unknown != 1 {
+		t.Errorf("TotalUSD = %v, %d", total, unknown)
+	}
+}
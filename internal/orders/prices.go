@@ -0,0 +1,64 @@
+package orders
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/tradeboba/boba-cli/internal/formatter"
+)
+
+// FillCurrentPrices sets current_price on the limit orders that have a
+// trigger but don't say where the market is, so the tables can show how
+// far each is from filling. Prices are looked up with get_token_price, one
+// call per chain; orders whose price can't be found are left as they are.
+func FillCurrentPrices(ctx context.Context, call CallFunc, list []map[string]any) {
+	byChain := make(map[string][]map[string]any)
+	for _, order := range list {
+		if _, ok := order["current_price"]; ok {
+			continue
+		}
+		chain, _ := order["chain"].(string)
+		if order["trigger_price"] == nil || chain == "" || formatter.TriggerToken(order) == "" {
+			continue
+		}
+		byChain[chain] = append(byChain[chain], order)
+	}
+
+	for chain, chainOrders := range byChain {
+		var tokens []string
+		seen := make(map[string]bool)
+		for _, order := range chainOrders {
+			if t := formatter.TriggerToken(order); !seen[t] {
+				seen[t] = true
+				tokens = append(tokens, t)
+			}
+		}
+		body, err := call(ctx, "get_token_price", map[string]any{"tokens": tokens, "chain": chain})
+		if err != nil {
+			continue
+		}
+		var data map[string]any
+		if json.Unmarshal(body, &data) != nil {
+			continue
+		}
+		prices := make(map[string]float64)
+		for address, price := range formatter.TokenPrices(data) {
+			prices[addressKey(address)] = price
+		}
+		for _, order := range chainOrders {
+			if price := prices[addressKey(formatter.TriggerToken(order))]; price > 0 {
+				order["current_price"] = price
+			}
+		}
+	}
+}
+
+// addressKey normalizes an address for lookups: EVM addresses are
+// case-insensitive, base58 addresses are not.
+func addressKey(address string) string {
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return strings.ToLower(address)
+	}
+	return address
+}
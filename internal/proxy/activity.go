@@ -0,0 +1,173 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ActivityRecord is one request as GET /activity reports it: its latest
+// state, stamped with when it started. Arguments and autofill changes are
+// left out, since they can hold wallet addresses and amounts.
+type ActivityRecord struct {
+	Seq        int64     `json:"seq"`
+	ID         string    `json:"id,omitempty"`
+	Tool       string    `json:"tool"`
+	Status     string    `json:"status"`
+	DurationMs int64     `json:"durationMs"`
+	Preview    string    `json:"preview,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+	Error      string    `json:"error,omitempty"`
+	ErrorKind  string    `json:"errorKind,omitempty"`
+	Warning    string    `json:"warning,omitempty"`
+	RequestID  string    `json:"requestId,omitempty"`
+	TxHash     string    `json:"txHash,omitempty"`
+	Cached     bool      `json:"cached,omitempty"`
+	Repeat     int       `json:"repeat,omitempty"`
+	// Output is the formatted result the dashboard shows, with ANSI
+	// styling; only sent when asked for with ?output=1.
+	Output string `json:"output,omitempty"`
+}
+
+// activityRing keeps the latest state of the last requests, oldest first,
+// overwriting the oldest once full. Entries sharing a request ID update
+// that request's record in place.
+type activityRing struct {
+	mu      sync.Mutex
+	records []ActivityRecord
+	start   int // index of the oldest record once the ring is full
+	seq     int64
+	byID    map[string]int64 // request ID -> seq of its record
+}
+
+func newActivityRing(size int) *activityRing {
+	size = max(size, 1)
+	return &activityRing{records: make([]ActivityRecord, 0, size), byID: make(map[string]int64)}
+}
+
+// add records an entry.
+func (a *activityRing) add(e LogEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if seq, ok := a.byID[e.ID]; ok && e.ID != "" {
+		if i, ok := a.index(seq); ok {
+			started := a.records[i].Timestamp
+			a.records[i] = activityRecord(seq, e)
+			a.records[i].Timestamp = started
+			return
+		}
+	}
+
+	a.seq++
+	rec := activityRecord(a.seq, e)
+	if e.ID != "" {
+		a.byID[e.ID] = a.seq
+	}
+	if len(a.records) < cap(a.records) {
+		a.records = append(a.records, rec)
+		return
+	}
+	if old := a.records[a.start]; old.ID != "" && a.byID[old.ID] == old.Seq {
+		delete(a.byID, old.ID)
+	}
+	a.records[a.start] = rec
+	a.start = (a.start + 1) % len(a.records)
+}
+
+// index returns where the record with seq is kept, if it still is.
+func (a *activityRing) index(seq int64) (int, bool) {
+	n := int64(len(a.records))
+	oldest := a.seq - n + 1
+	if seq < oldest || seq > a.seq {
+		return 0, false
+	}
+	return (a.start + int(seq-oldest)) % len(a.records), true
+}
+
+// page returns up to limit records, newest first, older than the record
+// with seq before; before 0 starts from the newest.
+func (a *activityRing) page(limit int, before int64) []ActivityRecord {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := make([]ActivityRecord, 0, min(limit, len(a.records)))
+	for k := len(a.records) - 1; k >= 0 && len(out) < limit; k-- {
+		rec := a.records[(a.start+k)%len(a.records)]
+		if before == 0 || rec.Seq < before {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+func activityRecord(seq int64, e LogEntry) ActivityRecord {
+	return ActivityRecord{
+		Seq:        seq,
+		ID:         e.ID,
+		Tool:       e.Tool,
+		Status:     e.Status,
+		DurationMs: e.Duration.Milliseconds(),
+		Preview:    e.Preview,
+		Timestamp:  e.Timestamp,
+		Error:      e.Error,
+		ErrorKind:  e.ErrorKind,
+		Warning:    e.Warning,
+		RequestID:  e.RequestID,
+		TxHash:     e.TxHash,
+		Cached:     e.Cached,
+		Repeat:     e.Repeat,
+		Output:     e.FormattedOutput,
+	}
+}
+
+// Activity paging limits for GET /activity.
+const (
+	defaultActivityPage = 50
+	maxActivityPage     = 500
+)
+
+// handleActivity serves the recent requests, newest first. ?limit= caps
+// the page, and ?before= takes the next from the "next" cursor of the
+// previous one.
+func (s *ProxyServer) handleActivity(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit := defaultActivityPage
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeJSONError(w, http.StatusBadRequest, "limit must be a positive number")
+			return
+		}
+		limit = min(n, maxActivityPage)
+	}
+	var before int64
+	if v := q.Get("before"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 1 {
+			writeJSONError(w, http.StatusBadRequest, "before must be a seq from an earlier page")
+			return
+		}
+		before = n
+	}
+
+	records := s.activity.page(limit, before)
+	if q.Get("output") != "1" {
+		for i := range records {
+			records[i].Output = ""
+		}
+	}
+	resp := map[string]any{"entries": records}
+	if len(records) == limit && records[len(records)-1].Seq > 1 {
+		resp["next"] = records[len(records)-1].Seq
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// writeJSONError answers with status and {"error": msg}.
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
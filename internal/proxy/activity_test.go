@@ -0,0 +1,222 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func seqs(records []ActivityRecord) string {
+	var s []string
+	for _, r := range records {
+		s = append(s, fmt.Sprint(r.Seq))
+	}
+	return strings.Join(s, ",")
+}
+
+// A full ring overwrites its oldest records; updates of a request still
+// held change its record in place, and of one overwritten start anew.
+func TestActivityRingWrap(t *testing.T) {
+	a := newActivityRing(5)
+	start := time.Date(2026, 1, 2, 14, 0, 0, 0, time.UTC)
+	for i := 1; i <= 12; i++ {
+		a.add(LogEntry{ID: fmt.Sprintf("req-%d", i), Timestamp: start.Add(time.Duration(i) * time.Second), Tool: "get_token_info", Status: "pending"})
+	}
+	if got := seqs(a.page(100, 0)); got != "12,11,10,9,8" {
+		t.Fatalf("after wrapping: %s", got)
+	}
+
+	a.add(LogEntry{ID: "req-9", Timestamp: start.Add(time.Minute), Tool: "get_token_info", Status: "success", Duration: 1500 * time.Millisecond})
+	rec := a.page(100, 0)[3]
+	if rec.Seq != 9 || rec.Status != "success" || rec.DurationMs != 1500 || !rec.Timestamp.Equal(start.Add(9*time.Second)) {
+		t.Errorf("updated record = %+v", rec)
+	}
+
+	a.add(LogEntry{ID: "req-3", Timestamp: start.Add(time.Minute), Tool: "get_token_info", Status: "success"})
+	if got := seqs(a.page(100, 0)); got != "13,12,11,10,9" {
+		t.Errorf("update of an overwritten request: %s", got)
+	}
+	if len(a.byID) != 5 {
+		t.Errorf("%d request IDs indexed for 5 records", len(a.byID))
+	}
+
+	// Pages walk back from a cursor.
+	if got := seqs(a.page(2, 0)); got != "13,12" {
+		t.Errorf("first page: %s", got)
+	}
+	if got := seqs(a.page(2, 12)); got != "11,10" {
+		t.Errorf("second page: %s", got)
+	}
+	if got := seqs(a.page(2, 9)); got != "" {
+		t.Errorf("past the oldest: %s", got)
+	}
+}
+
+// Concurrent writers, each updating its own requests, leave one record
+// per request in order. Run with -race.
+func TestActivityRingConcurrent(t *testing.T) {
+	const writers, perWriter, size = 8, 200, 64
+	a := newActivityRing(size)
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range perWriter {
+				id := fmt.Sprintf("w%d-%d", w, i)
+				a.add(LogEntry{ID: id, Timestamp: time.Now(), Status: "pending"})
+				a.add(LogEntry{ID: id, Timestamp: time.Now(), Status: "success"})
+				a.page(10, 0)
+			}
+		}()
+	}
+	wg.Wait()
+
+	records := a.page(size*2, 0)
+	if len(records) != size {
+		t.Fatalf("%d records, want %d", len(records), size)
+	}
+	seen := make(map[string]bool)
+	for i, r := range records {
+		if r.Seq != int64(writers*perWriter-i) {
+			t.Fatalf("record %d has seq %d, want %d", i, r.Seq, writers*perWriter-i)
+		}
+		if seen[r.ID] || r.Status != "success" {
+			t.Errorf("record %s: status %s, duplicate %v", r.ID, r.Status, seen[r.ID])
+		}
+		seen[r.ID] = true
+	}
+}
+
+// activityPage fetches GET /activity with query and decodes it.
+func activityPage(t *testing.T, s *ProxyServer, query string) (entries []map[string]any, next float64) {
+	t.Helper()
+	w := serve(s, "GET", "/activity"+query, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /activity%s: %d %s", query, w.Code, w.Body.String())
+	}
+	var page struct {
+		Entries []map[string]any `json:"entries"`
+		Next    float64          `json:"next"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	return page.Entries, page.Next
+}
+
+func TestActivityEndpoint(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+	for i := range 5 {
+		serve(s, "POST", "/call", fmt.Sprintf(`{"tool":"get_token_info","args":{"address":"secret-%d","wallet":"0xabc"}}`, i))
+	}
+
+	if w := serve(s, "GET", "/activity", "", "wrong-token"); w.Code == http.StatusOK {
+		t.Errorf("served without the session token: %d", w.Code)
+	}
+	for _, q := range []string{"?limit=0", "?limit=x", "?before=-1"} {
+		if w := serve(s, "GET", "/activity"+q, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: %d, want 400", q, w.Code)
+		}
+	}
+
+	entries, next := activityPage(t, s, "?limit=3")
+	if len(entries) != 3 || entries[0]["seq"] != 5.0 || next != 3 {
+		t.Fatalf("first page: %d entries from seq %v, next %v", len(entries), entries[0]["seq"], next)
+	}
+	e := entries[0]
+	if e["tool"] != "get_token_info" || e["status"] != "success" || e["timestamp"] == nil || e["durationMs"] == nil {
+		t.Errorf("entry = %v", e)
+	}
+	for _, key := range []string{"args", "modifications", "injected", "output"} {
+		if _, ok := e[key]; ok {
+			t.Errorf("entry has %q", key)
+		}
+	}
+	body := serve(s, "GET", "/activity", "").Body.String()
+	if strings.Contains(body, "secret-") || strings.Contains(body, "0xabc") {
+		t.Errorf("arguments leaked:\n%s", body)
+	}
+
+	entries, next = activityPage(t, s, "?limit=3&before=3")
+	if len(entries) != 2 || entries[0]["seq"] != 2.0 || next != 0 {
+		t.Errorf("last page: %d entries from seq %v, next %v", len(entries), entries[0]["seq"], next)
+	}
+
+	if entries, _ := activityPage(t, s, "?limit=1&output=1"); entries[0]["output"] == nil {
+		t.Error("?output=1 left out the formatted output")
+	}
+}
+
+// GET /portfolio fetches at most every portfolioMaxAge, reuses a full
+// portfolio an agent fetched, and serves a held one marked stale when a
+// refresh fails.
+func TestPortfolioEndpoint(t *testing.T) {
+	var mu sync.Mutex
+	fail := false
+	backend := &fakeBackend{reply: func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"total_value_usd":1234.5}`))
+	}}
+	s := newTestServer(t, backend)
+
+	portfolio := func() map[string]any {
+		t.Helper()
+		w := serve(s, "GET", "/portfolio", "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET /portfolio: %d %s", w.Code, w.Body.String())
+		}
+		var resp map[string]any
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() { defer wg.Done(); serve(s, "GET", "/portfolio", "") }()
+	}
+	wg.Wait()
+	resp := portfolio()
+	if n := backend.calls.Load(); n != 1 {
+		t.Errorf("%d backend calls for 6 requests, want 1", n)
+	}
+	if resp["stale"] != false || resp["portfolio"].(map[string]any)["total_value_usd"] != 1234.5 {
+		t.Errorf("response = %v", resp)
+	}
+
+	// Once it is old, a failed refresh serves the one held.
+	s.portfolio.mu.Lock()
+	s.portfolio.fetched = time.Now().Add(-portfolioMaxAge)
+	s.portfolio.mu.Unlock()
+	mu.Lock()
+	fail = true
+	mu.Unlock()
+	if resp := portfolio(); resp["stale"] != true || resp["portfolio"] == nil {
+		t.Errorf("failed refresh: %v", resp)
+	}
+
+	// An agent's full portfolio is reused; one chain's isn't.
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	serve(s, "POST", "/call", `{"tool":"get_portfolio","args":{"chain":"base"}}`)
+	if _, fetched := s.portfolio.latest(); time.Since(fetched) < portfolioMaxAge {
+		t.Error("a single chain's portfolio was kept")
+	}
+	serve(s, "POST", "/call", `{"tool":"get_portfolio","args":{}}`)
+	calls := backend.calls.Load()
+	portfolio()
+	if backend.calls.Load() != calls {
+		t.Error("GET /portfolio refetched an agent's fresh portfolio")
+	}
+}
@@ -0,0 +1,109 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/alerts"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/logger"
+)
+
+// StatusAlert marks a price alert that fired. It stands alone: there is no
+// request behind it.
+const StatusAlert = "alert"
+
+// AlertTool is the tool name price alerts are logged under.
+const AlertTool = "price_alert"
+
+// watchAlerts runs until stop is closed, checking the price of every token
+// with an alert on the configured interval. Alerts added or removed with
+// `boba alerts` are picked up on the next check.
+func (s *ProxyServer) watchAlerts(stop <-chan struct{}) {
+	tracker := alerts.NewTracker()
+	ticker := time.NewTicker(config.GetAlertInterval())
+	defer ticker.Stop()
+	for {
+		s.checkAlerts(tracker)
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkAlerts fetches current prices, one call per chain, and reports every
+// threshold crossed since the last check.
+func (s *ProxyServer) checkAlerts(tracker *alerts.Tracker) {
+	list, err := alerts.List()
+	if err != nil {
+		logger.Warn("could not read price alerts", "error", err)
+		return
+	}
+	if len(list) == 0 || !config.HasCredentials() {
+		return
+	}
+
+	byChain := make(map[string][]alerts.Alert)
+	for _, a := range list {
+		byChain[a.Chain] = append(byChain[a.Chain], a)
+	}
+	for chain, chainAlerts := range byChain {
+		var addresses []string
+		for _, a := range chainAlerts {
+			addresses = append(addresses, a.Address)
+		}
+		body, err := s.CallTool(context.Background(), "get_token_price", map[string]any{"tokens": addresses, "chain": chain})
+		if err != nil {
+			logger.Debug("price alert check failed", "chain", chain, "error", err)
+			continue
+		}
+		var data map[string]any
+		if err := json.Unmarshal(body, &data); err != nil {
+			logger.Debug("price alert check failed", "chain", chain, "error", err)
+			continue
+		}
+		prices := make(map[string]float64)
+		for address, price := range formatter.TokenPrices(data) {
+			prices[priceKey(address)] = price
+		}
+		for _, a := range chainAlerts {
+			price, ok := prices[priceKey(a.Address)]
+			if !ok || price <= 0 {
+				continue
+			}
+			for _, c := range tracker.Check(a, price) {
+				s.fireAlert(c)
+			}
+		}
+	}
+}
+
+// priceKey normalizes an address for lookups: EVM addresses are
+// case-insensitive, base58 addresses are not.
+func priceKey(address string) string {
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return strings.ToLower(address)
+	}
+	return address
+}
+
+// fireAlert logs a crossing to the activity log and raises a desktop
+// notification.
+func (s *ProxyServer) fireAlert(c alerts.Crossing) {
+	logger.Info("price alert", "id", c.Alert.ID, "token", c.Alert.Address, "price", c.Price)
+	s.sendLog(LogEntry{
+		Tool:    AlertTool,
+		Status:  StatusAlert,
+		Preview: c.String(),
+	})
+	go func() {
+		if err := alerts.Notify("Boba price alert", c.String()); err != nil {
+			logger.Debug("desktop notification failed", "error", err)
+		}
+	}()
+}
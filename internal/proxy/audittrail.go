@@ -0,0 +1,241 @@
+package proxy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/client"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/logger"
+)
+
+// The audit trail records every write call (swaps, order changes) in full:
+// the arguments as forwarded, after autofill, and the backend's complete
+// answer. It is opt-in because it holds wallet addresses and amounts.
+
+// DefaultAuditMaxSize is the size at which the audit trail is rotated.
+const DefaultAuditMaxSize = 10 << 20
+
+// AuditRecord is one audited call.
+type AuditRecord struct {
+	Time     time.Time       `json:"time"`
+	Tool     string          `json:"tool"`
+	Args     map[string]any  `json:"args"`
+	Status   int             `json:"status"` // 0 when the backend couldn't be reached
+	TxHash   string          `json:"txHash,omitempty"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	// RequestID is the ID the backend knows the call by.
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// secretArgs are argument names whose values are never written to the
+// audit trail.
+var secretArgs = []string{"authorization", "access_token", "accesstoken", "refresh_token", "session_token", "secret", "api_key", "apikey", "password"}
+
+// auditTrail appends records to the audit file, rotating it once it grows
+// past maxSize. Only the previous file is kept.
+type auditTrail struct {
+	mu      sync.Mutex
+	f       *os.File
+	size    int64
+	maxSize int64
+}
+
+// AuditTrailPath returns the audit trail file.
+func AuditTrailPath() string {
+	return filepath.Join(config.DataDir(), "audit.jsonl")
+}
+
+// EnableAuditTrail starts recording write calls to the audit trail,
+// rotating it at maxSize bytes (DefaultAuditMaxSize if zero).
+func (s *ProxyServer) EnableAuditTrail(maxSize int64) error {
+	if maxSize <= 0 {
+		maxSize = DefaultAuditMaxSize
+	}
+	a := &auditTrail{maxSize: maxSize}
+	if err := a.open(); err != nil {
+		return fmt.Errorf("failed to open audit trail: %w", err)
+	}
+	s.audit = a
+	return nil
+}
+
+func (a *auditTrail) open() error {
+	f, err := config.OpenPrivateAppend(AuditTrailPath())
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	a.f, a.size = f, info.Size()
+	return nil
+}
+
+// record writes the outcome of a write call. Read-only tools are skipped.
+// tokens, when known, are scrubbed from the error and the response in case
+// the backend ever echoes them.
+func (a *auditTrail) record(tool string, args map[string]any, requestID string, resp *client.Response, err error, tokens *config.AuthTokens) {
+	if a == nil || !NeedsConfirmation(tool) {
+		return
+	}
+	rec := AuditRecord{Time: time.Now().UTC(), Tool: tool, Args: redactSecrets(args), RequestID: requestID}
+	if err != nil {
+		rec.Error = scrubTokens(err.Error(), tokens)
+	}
+	if resp != nil {
+		rec.Status = resp.Status
+		body := scrubTokens(string(resp.Body), tokens)
+		var parsed map[string]any
+		if json.Unmarshal([]byte(body), &parsed) == nil {
+			rec.Response = json.RawMessage(body)
+			rec.TxHash = txHashOf(parsed)
+		} else if body != "" {
+			rec.Response, _ = json.Marshal(body)
+		}
+	}
+
+	data, err := json.Marshal(rec)
+	if err != nil {
+		logger.Warn("failed to encode audit record", "tool", tool, "error", err)
+		return
+	}
+	data = append(data, '\n')
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.f == nil {
+		return
+	}
+	if a.size > 0 && a.size+int64(len(data)) > a.maxSize {
+		a.rotate()
+	}
+	n, err := a.f.Write(data)
+	a.size += int64(n)
+	if err != nil {
+		logger.Warn("failed to write audit trail", "path", AuditTrailPath(), "error", err)
+	}
+}
+
+// rotate moves the current file aside as audit.jsonl.1, replacing the one
+// before, and starts a new file.
+func (a *auditTrail) rotate() {
+	path := AuditTrailPath()
+	a.f.Close()
+	a.f = nil
+	if err := os.Rename(path, path+".1"); err != nil {
+		logger.Warn("failed to rotate audit trail", "path", path, "error", err)
+	}
+	if err := a.open(); err != nil {
+		logger.Warn("failed to reopen audit trail", "path", path, "error", err)
+	}
+}
+
+func (a *auditTrail) close() {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.f != nil {
+		a.f.Close()
+		a.f = nil
+	}
+}
+
+// scrubTokens replaces the access and refresh tokens in s.
+func scrubTokens(s string, tokens *config.AuthTokens) string {
+	if tokens == nil {
+		return s
+	}
+	for _, secret := range []string{tokens.AccessToken, tokens.RefreshToken} {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, "[redacted]")
+		}
+	}
+	return s
+}
+
+// redactSecrets returns a copy of args with credential-like values
+// replaced, at any depth. Addresses and amounts are kept verbatim.
+func redactSecrets(args map[string]any) map[string]any {
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		if slices.Contains(secretArgs, strings.ToLower(k)) {
+			v = "[redacted]"
+		} else {
+			v = redactNested(v)
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// redactNested redacts the maps inside v, including those in lists.
+func redactNested(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		return redactSecrets(t)
+	case []any:
+		out := make([]any, len(t))
+		for i, e := range t {
+			out[i] = redactNested(e)
+		}
+		return out
+	}
+	return v
+}
+
+// txHashOf finds the transaction hash in a write call's response.
+func txHashOf(resp map[string]any) string {
+	if inner, ok := resp["data"].(map[string]any); ok {
+		if hash := txHashOf(inner); hash != "" {
+			return hash
+		}
+	}
+	for _, k := range []string{"tx_hash", "txHash", "hash", "transaction_hash", "signature"} {
+		if s, ok := resp[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// ReadAuditTrail reads the audit trail, the rotated file first, oldest
+// record first. Malformed lines are skipped; a missing trail is empty.
+func ReadAuditTrail() ([]AuditRecord, error) {
+	var records []AuditRecord
+	for _, path := range []string{AuditTrailPath() + ".1", AuditTrailPath()} {
+		f, err := os.Open(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+		for scanner.Scan() {
+			var rec AuditRecord
+			if json.Unmarshal(scanner.Bytes(), &rec) == nil && rec.Tool != "" {
+				records = append(records, rec)
+			}
+		}
+		err = scanner.Err()
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,153 @@
+package proxy
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/client"
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// newTestTrail opens an audit trail in a config dir of its own.
+func newTestTrail(t *testing.T, maxSize int64) *auditTrail {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config.UseDir(filepath.Join(home, ".config"))
+	a := &auditTrail{maxSize: maxSize}
+	if err := a.open(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(a.close)
+	return a
+}
+
+func readTrail(t *testing.T) []AuditRecord {
+	t.Helper()
+	records, err := ReadAuditTrail()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return records
+}
+
+func TestAuditRedaction(t *testing.T) {
+	a := newTestTrail(t, DefaultAuditMaxSize)
+	tokens := &config.AuthTokens{AccessToken: "access-abc123", RefreshToken: "refresh-def456"}
+	args := map[string]any{
+		"Authorization": "Bearer access-abc123",
+		"wallet":        map[string]any{"address": "7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU", "api_key": "key-in-map"},
+		"legs":          []any{map[string]any{"amount": "1.5", "password": "pw-in-list"}, "plain"},
+		"amount":        "0.25",
+	}
+	resp := &client.Response{Status: 502, Body: []byte(`{"error":"bad token access-abc123","data":{"tx_hash":"0xfeed"}}`)}
+	a.record("execute_swap", args, "req-1", resp, errors.New("refresh failed for refresh-def456"), tokens)
+
+	raw, err := os.ReadFile(AuditTrailPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"access-abc123", "refresh-def456", "key-in-map", "pw-in-list"} {
+		if strings.Contains(string(raw), secret) {
+			t.Errorf("audit trail holds %q:\n%s", secret, raw)
+		}
+	}
+	for _, kept := range []string{"7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU", `"1.5"`, `"0.25"`, `"plain"`} {
+		if !strings.Contains(string(raw), kept) {
+			t.Errorf("audit trail lacks %s:\n%s", kept, raw)
+		}
+	}
+
+	records := readTrail(t)
+	if len(records) != 1 {
+		t.Fatalf("%d records, want 1", len(records))
+	}
+	rec := records[0]
+	if rec.Tool != "execute_swap" || rec.Status != 502 || rec.TxHash != "0xfeed" || rec.RequestID != "req-1" {
+		t.Errorf("record = %+v", rec)
+	}
+	if rec.Error != "refresh failed for [redacted]" {
+		t.Errorf("error = %q", rec.Error)
+	}
+	// The caller's args are left alone.
+	if args["wallet"].(map[string]any)["api_key"] != "key-in-map" {
+		t.Error("redaction changed the forwarded args")
+	}
+}
+
+func TestAuditSkipsReadOnly(t *testing.T) {
+	a := newTestTrail(t, DefaultAuditMaxSize)
+	resp := &client.Response{Status: 200, Body: []byte(`{}`)}
+	for _, tool := range []string{"get_portfolio", "search_tokens", "audit_token", "create_limit_order", "cancel_order"} {
+		a.record(tool, map[string]any{}, "", resp, nil, nil)
+	}
+	var tools []string
+	for _, rec := range readTrail(t) {
+		tools = append(tools, rec.Tool)
+	}
+	if got := strings.Join(tools, ","); got != "create_limit_order,cancel_order" {
+		t.Errorf("audited %s, want only the write calls", got)
+	}
+}
+
+func TestAuditRotation(t *testing.T) {
+	const maxSize = 400
+	a := newTestTrail(t, maxSize)
+	resp := &client.Response{Status: 200, Body: []byte(`{"tx_hash":"0xabc"}`)}
+	for i := 0; i < 10; i++ {
+		a.record("execute_swap", map[string]any{"amount": strings.Repeat("9", i+1)}, "", resp, nil, nil)
+	}
+
+	path := AuditTrailPath()
+	for _, p := range []string{path, path + ".1"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > maxSize {
+			t.Errorf("%s is %d bytes, past the %d limit", filepath.Base(p), info.Size(), maxSize)
+		}
+	}
+	if _, err := os.Stat(path + ".2"); !os.IsNotExist(err) {
+		t.Errorf("more than one rotated file kept: %v", err)
+	}
+
+	// Only the two files survive, oldest record first and ending with the
+	// newest.
+	records := readTrail(t)
+	if len(records) == 0 || len(records) >= 10 {
+		t.Fatalf("%d records after rotation", len(records))
+	}
+	for i := 1; i < len(records); i++ {
+		if len(records[i].Args["amount"].(string)) != len(records[i-1].Args["amount"].(string))+1 {
+			t.Fatalf("records out of order: %v then %v", records[i-1].Args, records[i].Args)
+		}
+	}
+	if last := records[len(records)-1].Args["amount"]; last != strings.Repeat("9", 10) {
+		t.Errorf("last record %v, want the newest", last)
+	}
+}
+
+func TestAuditFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+	a := newTestTrail(t, 200)
+	resp := &client.Response{Status: 200, Body: []byte(`{}`)}
+	for i := 0; i < 5; i++ {
+		a.record("execute_swap", map[string]any{"amount": "1"}, "", resp, nil, nil)
+	}
+	for _, p := range []string{AuditTrailPath(), AuditTrailPath() + ".1"} {
+		info, err := os.Stat(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o600 {
+			t.Errorf("%s mode = %o, want 600", filepath.Base(p), perm)
+		}
+	}
+}
@@ -1,56 +1,106 @@
 package proxy
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 
 	"github.com/tradeboba/boba-cli/internal/config"
 )
 
-// userIDTools is the set of tools whose user_id / userId parameter should be
-// auto-filled with the authenticated agent's ID when it is missing or fake.
-var userIDTools = map[string]bool{
-	"get_portfolio":              true,
-	"get_portfolio_summary":      true,
-	"get_portfolio_pnl":          true,
-	"get_trade_history":          true,
-	"get_pnl_chart":              true,
-	"get_user_xp":                true,
-	"get_transfers":              true,
-	"get_wallet_balance":         true,
-	"get_limit_orders":           true,
-	"get_dca_orders":             true,
-	"get_twap_orders":            true,
-	"get_positions":              true,
-	"create_limit_order":         true,
-	"cancel_limit_order":         true,
-	"get_user_swaps":             true,
-	"refresh_native_balances":    true,
-	"start_portfolio_stream":     true,
-	"get_portfolio_price_updates": true,
-	"stop_portfolio_stream":      true,
+// Agents often send placeholder IDs and wallet addresses, or leave them out.
+// The proxy fills them in from the authenticated agent before forwarding a
+// call. What gets filled is data: each tool has a list of fill rules.
+
+// fillWhen says which values a rule replaces.
+type fillWhen int
+
+const (
+	// fillMissingOrFake replaces absent, empty and placeholder values, see
+	// IsFakeID.
+	fillMissingOrFake fillWhen = iota
+	// fillPlaceholder only replaces the wallet placeholders agents are told
+	// to use, such as "my-wallet-evm"; anything else is left alone.
+	fillPlaceholder
+)
+
+// fillValue says what a rule fills in.
+type fillValue int
+
+const (
+	fillAgentID fillValue = iota
+	// fillWallet is the Solana address for calls on Solana, for params
+	// named after it and for "my-wallet-svm", and the EVM address
+	// otherwise.
+	fillWallet
+)
+
+// fillRule fills one or more parameters of a tool call.
+type fillRule struct {
+	params []string
+	when   fillWhen
+	value  fillValue
+	reason string
 }
 
-// swapTools is the set of tools that need a from-address / taker parameter
-// auto-filled with the agent's wallet address.
-var swapTools = map[string]bool{
-	"get_swap_price":  true,
-	"get_swap_quote":  true,
-	"execute_swap":    true,
-	"execute_trade":   true,
+var (
+	agentIDRule = fillRule{
+		params: []string{"user_id", "userId"},
+		when:   fillMissingOrFake,
+		value:  fillAgentID,
+		reason: "autofill: agent ID",
+	}
+	swapSenderRule = fillRule{
+		params: []string{"from_address", "fromAddress", "taker"},
+		when:   fillMissingOrFake,
+		value:  fillWallet,
+		reason: "autofill: swap sender",
+	}
+	// walletPlaceholderRule applies to every tool.
+	walletPlaceholderRule = fillRule{
+		params: []string{"wallet", "wallet_address", "walletAddress", "evm_address", "taker", "from_address", "fromAddress", "solana_address"},
+		when:   fillPlaceholder,
+		value:  fillWallet,
+		reason: "autofill: wallet placeholder",
+	}
+)
+
+// fillRules maps tools to the rules applied to their arguments, after
+// walletPlaceholderRule.
+var fillRules = map[string][]fillRule{
+	// User-scoped tools
+	"get_portfolio":               {agentIDRule},
+	"get_portfolio_summary":       {agentIDRule},
+	"get_portfolio_pnl":           {agentIDRule},
+	"get_trade_history":           {agentIDRule},
+	"get_pnl_chart":               {agentIDRule},
+	"get_user_xp":                 {agentIDRule},
+	"get_transfers":               {agentIDRule},
+	"get_wallet_balance":          {agentIDRule},
+	"get_limit_orders":            {agentIDRule},
+	"get_dca_orders":              {agentIDRule},
+	"get_twap_orders":             {agentIDRule},
+	"get_positions":               {agentIDRule},
+	"create_limit_order":          {agentIDRule},
+	"cancel_limit_order":          {agentIDRule},
+	"get_user_swaps":              {agentIDRule},
+	"refresh_native_balances":     {agentIDRule},
+	"start_portfolio_stream":      {agentIDRule},
+	"get_portfolio_price_updates": {agentIDRule},
+	"stop_portfolio_stream":       {agentIDRule},
+	// Swaps
+	"get_swap_price": {swapSenderRule},
+	"get_swap_quote": {swapSenderRule},
+	"execute_swap":   {swapSenderRule},
+	"execute_trade":  {swapSenderRule},
 }
 
-// walletParams lists all parameter names that represent a wallet address and
-// should be auto-filled when the value is a placeholder.
-var walletParams = map[string]bool{
-	"wallet":          true,
-	"wallet_address":  true,
-	"walletAddress":   true,
-	"evm_address":     true,
-	"taker":           true,
-	"from_address":    true,
-	"fromAddress":     true,
-	"solana_address":  true,
+// walletPlaceholders are the values fillPlaceholder replaces.
+var walletPlaceholders = map[string]bool{
+	"my-wallet-evm": true,
+	"my-wallet-svm": true,
+	"me":            true,
+	"self":          true,
 }
 
 var (
@@ -98,52 +148,108 @@ func IsSolanaChain(chain any) bool {
 	return false
 }
 
+// ModifiedHeader carries how many arguments the proxy changed on a tool call.
+const ModifiedHeader = "X-Boba-Modified"
+
+// Modification records one change the proxy made to a tool call's arguments
+// before forwarding it. Access tokens are never placed in arguments, so they
+// never appear here.
+type Modification struct {
+	Field    string `json:"field"`
+	Original any    `json:"original,omitempty"`
+	Absent   bool   `json:"absent,omitempty"` // the field was not sent at all
+	Value    any    `json:"value"`
+	Reason   string `json:"reason"`
+}
+
+// String renders the change as "field: original -> value (reason)".
+func (m Modification) String() string {
+	orig := fmt.Sprintf("%v", m.Original)
+	if m.Absent {
+		orig = "(absent)"
+	}
+	return fmt.Sprintf("%s: %s -> %v (%s)", m.Field, orig, m.Value, m.Reason)
+}
+
+// setArg sets args[key] and appends the change to mods when the value
+// actually differs.
+func setArg(mods []Modification, args map[string]any, key string, value any, reason string) []Modification {
+	orig, present := args[key]
+	if present && orig == value {
+		return mods
+	}
+	args[key] = value
+	return append(mods, Modification{Field: key, Original: orig, Absent: !present, Value: value, Reason: reason})
+}
+
 // AutoFillParams mutates args in place, replacing placeholder / missing values
 // with the authenticated agent's real identifiers. This mirrors the TypeScript
 // proxy's auto-fill behaviour so that AI-generated tool calls work correctly
-// even when the model hallucinates IDs.
-func AutoFillParams(toolName string, args map[string]any, tokens *config.AuthTokens) {
+// even when the model hallucinates IDs. It returns what it changed.
+func AutoFillParams(toolName string, args map[string]any, tokens *config.AuthTokens) []Modification {
 	if tokens == nil {
-		return
+		return nil
 	}
-
-	// 1. Auto-fill user_id / userId for user-scoped tools.
-	if userIDTools[toolName] {
-		for _, key := range []string{"user_id", "userId"} {
-			val, _ := args[key].(string)
-			if val == "" || IsFakeID(val) {
-				args[key] = tokens.AgentID
+	var mods []Modification
+	solana := IsSolanaChain(args["chain"])
+	for _, rule := range append([]fillRule{walletPlaceholderRule}, fillRules[toolName]...) {
+		for _, param := range rule.params {
+			if !rule.applies(args, param) {
+				continue
 			}
+			mods = setArg(mods, args, param, rule.resolve(param, args[param], solana, tokens), rule.reason)
 		}
 	}
+	return mods
+}
 
-	// 2. Auto-fill wallet address parameters.
-	solana := IsSolanaChain(args["chain"])
-	for param := range walletParams {
+// applies reports whether the rule replaces the current value of param.
+func (r fillRule) applies(args map[string]any, param string) bool {
+	switch r.when {
+	case fillPlaceholder:
 		val, ok := args[param].(string)
-		if !ok {
-			continue
-		}
-		if val == "my-wallet-evm" || val == "my-wallet-svm" || val == "me" || val == "self" {
-			if solana || strings.Contains(param, "solana") {
-				args[param] = tokens.SolanaAddress
-			} else {
-				args[param] = tokens.EVMAddress
-			}
-		}
+		return ok && walletPlaceholders[val]
+	default:
+		val, _ := args[param].(string)
+		return val == "" || IsFakeID(val)
 	}
+}
 
-	// 3. Auto-fill swap tool from-address / taker.
-	if swapTools[toolName] {
-		for _, key := range []string{"from_address", "fromAddress", "taker"} {
-			val, _ := args[key].(string)
-			if val == "" || IsFakeID(val) {
-				if solana {
-					args[key] = tokens.SolanaAddress
-				} else {
-					args[key] = tokens.EVMAddress
-				}
-			}
-		}
+// resolve returns the value the rule fills param with, replacing current.
+// A placeholder naming a wallet family picks that family's address.
+func (r fillRule) resolve(param string, current any, solana bool, tokens *config.AuthTokens) string {
+	if r.value == fillAgentID {
+		return tokens.AgentID
+	}
+	switch current {
+	case "my-wallet-svm":
+		return tokens.SolanaAddress
+	case "my-wallet-evm":
+		return tokens.EVMAddress
+	}
+	if solana || strings.Contains(param, "solana") {
+		return tokens.SolanaAddress
+	}
+	return tokens.EVMAddress
+}
+
+// Injected summarizes the values autofill set, keyed by parameter, with the
+// values shortened so IDs and addresses aren't written out in full.
+func Injected(mods []Modification) map[string]string {
+	if len(mods) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(mods))
+	for _, m := range mods {
+		out[m.Field] = redactValue(fmt.Sprint(m.Value))
+	}
+	return out
+}
+
+// redactValue keeps the start and end of an identifier.
+func redactValue(s string) string {
+	if len(s) <= 10 {
+		return strings.Repeat("*", len(s))
 	}
+	return s[:6] + "…" + s[len(s)-4:]
 }
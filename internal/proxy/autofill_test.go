@@ -0,0 +1,246 @@
+package proxy
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/client"
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+const (
+	agentID   = "agent-7f3c2e"
+	evmAddr   = "0x52908400098527886E0F7030069857D2E4169EE7"
+	solAddr   = "9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM"
+	accessTok = "access-token-never-shown"
+)
+
+var agentTokens = &config.AuthTokens{AccessToken: accessTok, AgentID: agentID, EVMAddress: evmAddr, SolanaAddress: solAddr}
+
+func TestAutoFillParams(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		tool string
+		args map[string]any
+		want []Modification
+	}{
+		{
+			name: "missing user id",
+			tool: "get_portfolio",
+			args: map[string]any{},
+			want: []Modification{
+				{Field: "user_id", Absent: true, Value: agentID, Reason: "autofill: agent ID"},
+				{Field: "userId", Absent: true, Value: agentID, Reason: "autofill: agent ID"},
+			},
+		},
+		{
+			name: "hallucinated user id",
+			tool: "get_limit_orders",
+			args: map[string]any{"user_id": "11111111"},
+			want: []Modification{
+				{Field: "user_id", Original: "11111111", Value: agentID, Reason: "autofill: agent ID"},
+				{Field: "userId", Absent: true, Value: agentID, Reason: "autofill: agent ID"},
+			},
+		},
+		{
+			name: "real user id",
+			tool: "get_portfolio",
+			args: map[string]any{"user_id": agentID, "userId": agentID},
+		},
+		{
+			name: "swap sender on solana",
+			tool: "get_swap_quote",
+			args: map[string]any{"chain": "solana", "from_address": "me"},
+			want: []Modification{
+				{Field: "from_address", Original: "me", Value: solAddr, Reason: "autofill: wallet placeholder"},
+				{Field: "fromAddress", Absent: true, Value: solAddr, Reason: "autofill: swap sender"},
+				{Field: "taker", Absent: true, Value: solAddr, Reason: "autofill: swap sender"},
+			},
+		},
+		{
+			name: "named wallet placeholders",
+			tool: "get_token_info",
+			args: map[string]any{"wallet": "my-wallet-svm", "evm_address": "my-wallet-evm", "address": "my-wallet-evm"},
+			want: []Modification{
+				{Field: "wallet", Original: "my-wallet-svm", Value: solAddr, Reason: "autofill: wallet placeholder"},
+				{Field: "evm_address", Original: "my-wallet-evm", Value: evmAddr, Reason: "autofill: wallet placeholder"},
+			},
+		},
+	} {
+		args := tc.args
+		mods := AutoFillParams(tc.tool, args, agentTokens)
+		if len(mods) != len(tc.want) {
+			t.Errorf("%s: %v, want %v", tc.name, mods, tc.want)
+			continue
+		}
+		for i, m := range mods {
+			if m != tc.want[i] {
+				t.Errorf("%s: change %d = %+v, want %+v", tc.name, i, m, tc.want[i])
+			}
+			if args[m.Field] != m.Value {
+				t.Errorf("%s: %s = %v after autofill, want %v", tc.name, m.Field, args[m.Field], m.Value)
+			}
+		}
+	}
+	if mods := AutoFillParams("get_portfolio", map[string]any{}, nil); mods != nil {
+		t.Errorf("filled without credentials: %v", mods)
+	}
+}
+
+// The exact parameters filled for a call that sends none, for a
+// representative set of tools, and which address they get per chain.
+func TestAutoFillInjectedKeys(t *testing.T) {
+	for _, tc := range []struct {
+		tool  string
+		chain any
+		want  map[string]any
+	}{
+		{"get_portfolio", nil, map[string]any{"user_id": agentID, "userId": agentID}},
+		{"create_limit_order", "base", map[string]any{"user_id": agentID, "userId": agentID}},
+		{"get_swap_quote", "base", map[string]any{"from_address": evmAddr, "fromAddress": evmAddr, "taker": evmAddr}},
+		{"execute_swap", "solana", map[string]any{"from_address": solAddr, "fromAddress": solAddr, "taker": solAddr}},
+		{"execute_trade", 1399811149.0, map[string]any{"from_address": solAddr, "fromAddress": solAddr, "taker": solAddr}},
+		{"get_token_info", "solana", nil},
+		{"search_tokens", nil, nil},
+	} {
+		args := map[string]any{}
+		if tc.chain != nil {
+			args["chain"] = tc.chain
+		}
+		mods := AutoFillParams(tc.tool, args, agentTokens)
+		got := map[string]any{}
+		for _, m := range mods {
+			got[m.Field] = m.Value
+		}
+		if len(got) != len(tc.want) || !maps.Equal(got, tc.want) {
+			keys := slices.Sorted(maps.Keys(got))
+			t.Errorf("%s on %v: filled %v (%v), want %v", tc.tool, tc.chain, keys, got, tc.want)
+		}
+	}
+}
+
+// Values the agent sent that are real are never overwritten, whatever the
+// tool.
+func TestAutoFillKeepsUserValues(t *testing.T) {
+	sent := map[string]any{
+		"user_id": "agent-real", "userId": "agent-real",
+		"from_address": "alice.eth", "fromAddress": "alice.eth", "taker": "alice.eth",
+		"wallet": "alice.eth", "wallet_address": "alice.eth", "walletAddress": "alice.eth",
+		"evm_address": "alice.eth", "solana_address": "alice.sol",
+		"chain": "solana",
+	}
+	for tool := range fillRules {
+		args := maps.Clone(sent)
+		if mods := AutoFillParams(tool, args, agentTokens); len(mods) != 0 {
+			t.Errorf("%s overwrote %v", tool, mods)
+		}
+		if !maps.Equal(args, sent) {
+			t.Errorf("%s changed the arguments to %v", tool, args)
+		}
+	}
+}
+
+// The debug summary shortens every value and is only attached to log
+// entries with BOBA_DEBUG=1.
+func TestInjected(t *testing.T) {
+	mods := AutoFillParams("get_swap_quote", map[string]any{"user_id": "me"}, agentTokens)
+	got := Injected(mods)
+	want := map[string]string{"from_address": "0x5290…9EE7", "fromAddress": "0x5290…9EE7", "taker": "0x5290…9EE7"}
+	if !maps.Equal(got, want) {
+		t.Errorf("Injected = %v, want %v", got, want)
+	}
+	if got := Injected([]Modification{{Field: "user_id", Value: "short-id"}}); got["user_id"] != "********" {
+		t.Errorf("short value shown as %q", got["user_id"])
+	}
+	if Injected(nil) != nil {
+		t.Error("no changes gave a summary")
+	}
+
+	s := newTestServer(t, &fakeBackend{})
+	s.sendLog(LogEntry{ID: "1", Tool: "get_portfolio", Status: "success", Modifications: mods})
+	if e := lastEntry(t, s); e.Injected != nil {
+		t.Errorf("injected params logged without BOBA_DEBUG: %v", e.Injected)
+	}
+	s.debugArgs = true
+	s.sendLog(LogEntry{ID: "2", Tool: "get_portfolio", Status: "success", Modifications: mods})
+	if e := lastEntry(t, s); !maps.Equal(e.Injected, want) {
+		t.Errorf("with BOBA_DEBUG: injected %v, want %v", e.Injected, want)
+	}
+}
+
+func TestModificationString(t *testing.T) {
+	for _, tc := range []struct {
+		mod  Modification
+		want string
+	}{
+		{Modification{Field: "user_id", Absent: true, Value: "a1", Reason: "autofill: agent ID"}, "user_id: (absent) -> a1 (autofill: agent ID)"},
+		{Modification{Field: "wallet", Original: "me", Value: "0xabc", Reason: "autofill: wallet placeholder"}, "wallet: me -> 0xabc (autofill: wallet placeholder)"},
+	} {
+		if got := tc.mod.String(); got != tc.want {
+			t.Errorf("String() = %q, want %q", got, tc.want)
+		}
+	}
+}
+
+// A call several rules apply to carries the whole trail: the count in the
+// response header, every change in the log entry in the order it was made,
+// and the redacted values for the debug log. The backend gets the changed
+// arguments and the access token appears nowhere.
+func TestCallModificationTrail(t *testing.T) {
+	var forwarded map[string]any
+	backend := &fakeBackend{reply: func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Args map[string]any `json:"args"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		forwarded = body.Args
+		w.Write([]byte(`{"success":true}`))
+	}}
+	s := newTestServer(t, backend)
+	up := s.backend.BaseURL
+	s.backend = client.New(client.TokenFunc(func() (*config.AuthTokens, error) { return agentTokens, nil }), nil)
+	s.backend.BaseURL = up
+	s.debugArgs = true
+
+	w := serve(s, "POST", "/call", `{"tool":"get_portfolio","args":{"user_id":"me","wallet":"my-wallet-evm","solana_address":"my-wallet-svm","chain":"base"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get(ModifiedHeader); got != "4" {
+		t.Errorf("%s = %q, want 4", ModifiedHeader, got)
+	}
+
+	want := []string{
+		"wallet: my-wallet-evm -> " + evmAddr + " (autofill: wallet placeholder)",
+		"solana_address: my-wallet-svm -> " + solAddr + " (autofill: wallet placeholder)",
+		"user_id: me -> " + agentID + " (autofill: agent ID)",
+		"userId: (absent) -> " + agentID + " (autofill: agent ID)",
+	}
+	e := lastEntry(t, s)
+	if len(e.Modifications) != len(want) {
+		t.Fatalf("trail %v, want %d changes", e.Modifications, len(want))
+	}
+	for i, m := range e.Modifications {
+		if m.String() != want[i] {
+			t.Errorf("change %d = %q, want %q", i, m, want[i])
+		}
+		if forwarded[m.Field] != m.Value {
+			t.Errorf("backend got %s = %v, want %v", m.Field, forwarded[m.Field], m.Value)
+		}
+	}
+	if forwarded["chain"] != "base" {
+		t.Errorf("untouched argument changed: chain = %v", forwarded["chain"])
+	}
+	if got := e.Injected["wallet"]; got != "0x5290…9EE7" {
+		t.Errorf("debug log shows wallet as %q, want it redacted", got)
+	}
+
+	data, _ := json.Marshal(e)
+	if strings.Contains(string(data), accessTok) || strings.Contains(w.Body.String(), accessTok) {
+		t.Error("access token leaked into the trail")
+	}
+}
@@ -0,0 +1,121 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// IsLoopback reports whether host, an IP address or "localhost", only
+// accepts connections from this machine.
+func IsLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// SetBindAddress makes the proxy listen on host instead of 127.0.0.1. Any
+// address but loopback lets other machines reach it, and they must then
+// send the session token on every route, /health included; only /livez
+// stays open. It must be called before Start.
+func (s *ProxyServer) SetBindAddress(host string) {
+	s.host = host
+	s.server.Addr = net.JoinHostPort(host, strconv.Itoa(s.port))
+}
+
+// LANExposed reports whether the proxy listens on more than loopback.
+func (s *ProxyServer) LANExposed() bool {
+	return !IsLoopback(s.host)
+}
+
+// URL returns the address agents reach the proxy at: http://127.0.0.1:port,
+// or when it listens on the network, the address other machines use. An
+// unspecified bind address such as 0.0.0.0 is shown as this machine's
+// first LAN address.
+func (s *ProxyServer) URL() string {
+	host := s.host
+	if ip := net.ParseIP(host); ip != nil && ip.IsUnspecified() {
+		host = lanAddress(host)
+	} else if host == "localhost" {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, strconv.Itoa(s.port))
+}
+
+// lanAddress returns the first private IPv4 address of this machine's
+// interfaces, then the first other routable one, or fallback when it has
+// neither.
+func lanAddress(fallback string) string {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return fallback
+	}
+	routable := ""
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok || ipnet.IP.To4() == nil || !ipnet.IP.IsGlobalUnicast() {
+			continue
+		}
+		if ipnet.IP.IsPrivate() {
+			return ipnet.IP.String()
+		}
+		if routable == "" {
+			routable = ipnet.IP.String()
+		}
+	}
+	if routable != "" {
+		return routable
+	}
+	return fallback
+}
+
+// needsLoopback reports whether the proxy needs a second listener on
+// 127.0.0.1, so that boba commands and agents on this machine still find
+// it when it is bound to one network interface.
+func (s *ProxyServer) needsLoopback() bool {
+	ip := net.ParseIP(s.host)
+	return ip != nil && !ip.IsLoopback() && !ip.IsUnspecified()
+}
+
+// fromLoopback reports whether r came from this machine.
+func fromLoopback(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// openRoute wraps a route that doesn't normally need the session token, so
+// that it does for callers on other machines while the proxy is exposed.
+func (s *ProxyServer) openRoute(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.LANExposed() && !fromLoopback(r) {
+			s.withAuth(next)(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// handleLivez answers whether the proxy is up, to anyone and without
+// saying anything else about it.
+func (s *ProxyServer) handleLivez(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+}
+
+// listenLoopback adds the 127.0.0.1 listener needsLoopback asks for, on the
+// port the main listener got.
+func (s *ProxyServer) listenLoopback() (net.Listener, error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", s.port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on 127.0.0.1:%d as well as %s: %w", s.port, s.server.Addr, err)
+	}
+	return ln, nil
+}
@@ -0,0 +1,206 @@
+package proxy
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/zalando/go-keyring"
+)
+
+// takenPort binds a free port for the rest of the test and returns it.
+func takenPort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+// startProxy starts a proxy on port with its config kept apart from the
+// user's, stopping it when the test ends.
+func startProxy(t *testing.T, port int, fallback bool) (*ProxyServer, error) {
+	t.Helper()
+	keyring.MockInit()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config.UseDir(filepath.Join(home, ".config"))
+	s, err := NewProxyServer(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.SetPortFallback(fallback)
+	// Stop waits for the proxy's goroutines, which read the config dir the
+	// next test switches.
+	t.Cleanup(func() { s.Stop() })
+	if err := s.Start(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// A taken port moves the proxy to another one, which the lock records for
+// the bridge and launch to find.
+func TestStartPortTaken(t *testing.T) {
+	taken := takenPort(t)
+	s, err := startProxy(t, taken, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Port() == taken || s.Port() == 0 {
+		t.Fatalf("proxy on port %d, want one other than the taken %d", s.Port(), taken)
+	}
+	lock, ok := config.ReadProxyLock()
+	if !ok || lock.Port != s.Port() || lock.PID != os.Getpid() {
+		t.Errorf("lock = %+v, %v; want port %d of this process", lock, ok, s.Port())
+	}
+	if got := config.ActiveProxyPort(); got != s.Port() {
+		t.Errorf("ActiveProxyPort() = %d, want %d", got, s.Port())
+	}
+	if !strings.HasSuffix(s.URL(), ":"+strconv.Itoa(s.Port())) {
+		t.Errorf("URL() = %s, want the bound port", s.URL())
+	}
+
+	s.Stop()
+	if _, ok := config.ReadProxyLock(); ok {
+		t.Error("lock left after Stop")
+	}
+}
+
+// Without the fallback a taken port is an error naming the address.
+func TestStartPortTakenNoFallback(t *testing.T) {
+	taken := takenPort(t)
+	_, err := startProxy(t, taken, false)
+	if err == nil || !strings.Contains(err.Error(), strconv.Itoa(taken)) {
+		t.Errorf("err = %v, want a listen error for port %d", err, taken)
+	}
+	if _, ok := config.ReadProxyLock(); ok {
+		t.Error("lock written by a proxy that didn't start")
+	}
+}
+
+// Port 0 picks any free port.
+func TestStartAnyPort(t *testing.T) {
+	s, err := startProxy(t, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Port() == 0 {
+		t.Fatal("bound port not recorded")
+	}
+	if lock, ok := config.ReadProxyLock(); !ok || lock.Port != s.Port() {
+		t.Errorf("lock = %+v, %v; want port %d", lock, ok, s.Port())
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	for host, want := range map[string]bool{
+		"127.0.0.1": true, "127.1.2.3": true, "::1": true, "localhost": true,
+		"0.0.0.0": false, "::": false, "192.168.1.20": false, "proxy.lan": false, "": false,
+	} {
+		if got := IsLoopback(host); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", host, got, want)
+		}
+	}
+}
+
+// By default the proxy listens on loopback only, /health needs no token
+// and the URL agents use is 127.0.0.1.
+func TestBindLoopbackDefault(t *testing.T) {
+	s, err := startProxy(t, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.LANExposed() {
+		t.Error("default proxy exposed to the network")
+	}
+	want := "http://127.0.0.1:" + strconv.Itoa(s.Port())
+	if s.URL() != want || s.server.Addr != strings.TrimPrefix(want, "http://") {
+		t.Errorf("URL() = %s on %s, want %s", s.URL(), s.server.Addr, want)
+	}
+	resp, err := http.Get(want + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("/health without a token: %d", resp.StatusCode)
+	}
+
+	s.SetBindAddress("localhost")
+	if s.LANExposed() || s.URL() != want {
+		t.Errorf("localhost: exposed %v, URL() = %s", s.LANExposed(), s.URL())
+	}
+}
+
+// serveFrom is serve with the request coming from remote, a host:port.
+func serveFrom(s *ProxyServer, remote, path, token string) int {
+	r := httptest.NewRequest("GET", path, nil)
+	r.RemoteAddr = remote
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, r)
+	return w.Code
+}
+
+// Bound to the network, every route but /livez needs the session token
+// from other machines, /health and public metrics included; callers on
+// this machine are treated as before.
+func TestBindLANAuth(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+	s.SetMetricsPublic(true)
+	const lan, local = "192.168.1.30:50000", "127.0.0.1:50000"
+	for _, tc := range []struct {
+		path, remote, token string
+		want                int
+	}{
+		{"/health", lan, "", http.StatusOK},
+		{"/metrics", lan, "", http.StatusOK},
+	} {
+		if got := serveFrom(s, tc.remote, tc.path, tc.token); got != tc.want {
+			t.Errorf("loopback bind: %s from %s: %d, want %d", tc.path, tc.remote, got, tc.want)
+		}
+	}
+
+	s.SetBindAddress("0.0.0.0")
+	if !s.LANExposed() {
+		t.Fatal("0.0.0.0 not exposed")
+	}
+	for _, tc := range []struct {
+		path, remote, token string
+		want                int
+	}{
+		{"/health", lan, "", http.StatusForbidden},
+		{"/health", lan, "wrong", http.StatusForbidden},
+		{"/health", lan, testToken, http.StatusOK},
+		{"/health", local, "", http.StatusOK},
+		{"/livez", lan, "", http.StatusOK},
+		{"/metrics", lan, "", http.StatusForbidden},
+		{"/metrics", lan, testToken, http.StatusOK},
+		{"/metrics", local, "", http.StatusOK},
+		{"/tools", lan, "", http.StatusForbidden},
+		{"/tools", local, "", http.StatusForbidden},
+	} {
+		if got := serveFrom(s, tc.remote, tc.path, tc.token); got != tc.want {
+			t.Errorf("0.0.0.0: %s from %s with %q: %d, want %d", tc.path, tc.remote, tc.token, got, tc.want)
+		}
+	}
+	if strings.Contains(s.URL(), "0.0.0.0") && lanAddress("") != "" {
+		t.Errorf("URL() = %s, want this machine's LAN address", s.URL())
+	}
+
+	s.SetBindAddress("192.168.1.20")
+	if !s.needsLoopback() || s.URL() != "http://192.168.1.20:"+strconv.Itoa(s.port) {
+		t.Errorf("interface bind: needsLoopback %v, URL() = %s", s.needsLoopback(), s.URL())
+	}
+}
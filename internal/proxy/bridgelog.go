@@ -0,0 +1,63 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"unicode"
+)
+
+// BridgeLogTool is the tool name the stdio bridge's own errors are logged
+// under.
+const BridgeLogTool = "bridge"
+
+// maxBridgeLog caps a POST /bridge-log body.
+const maxBridgeLog = 4 << 10
+
+// BridgeLog is what the stdio bridge posts to /bridge-log about a problem on
+// its side, which would otherwise only reach its stderr.
+type BridgeLog struct {
+	Message string `json:"message"`
+	// Retry marks a problem the bridge recovered from by retrying, as
+	// opposed to a failure the agent saw.
+	Retry bool `json:"retry,omitempty"`
+}
+
+// handleBridgeLog records a bridge problem in the activity log.
+func (s *ProxyServer) handleBridgeLog(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBridgeLog)
+	var msg BridgeLog
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, "bridge log entries are limited to 4KB")
+			return
+		}
+		writeJSONError(w, http.StatusBadRequest, "invalid bridge log entry: "+err.Error())
+		return
+	}
+	text := "bridge: " + printable(msg.Message)
+	if text == "bridge: " {
+		writeJSONError(w, http.StatusBadRequest, "message is required")
+		return
+	}
+
+	entry := LogEntry{Tool: BridgeLogTool, Status: "error", Error: text}
+	if msg.Retry {
+		entry = LogEntry{Tool: BridgeLogTool, Status: StatusNotice, Preview: text}
+	}
+	s.sendLog(entry)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// printable flattens s to one line without control characters, so a bridge
+// can't move the cursor or restyle the dashboard.
+func printable(s string) string {
+	return strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s))
+}
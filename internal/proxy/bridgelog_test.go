@@ -0,0 +1,45 @@
+package proxy
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBridgeLogEndpoint(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+
+	if w := serve(s, "POST", "/bridge-log", `{"message":"x"}`, "wrong-token"); w.Code == http.StatusNoContent {
+		t.Errorf("accepted without the session token")
+	}
+
+	w := serve(s, "POST", "/bridge-log", `{"message":"session token refresh failed:\n\u001b[2Jkeyring locked"}`)
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("error report: %d %s", w.Code, w.Body.String())
+	}
+	e := lastEntry(t, s)
+	if e.Tool != BridgeLogTool || e.Status != "error" || e.Error != "bridge: session token refresh failed:  [2Jkeyring locked" {
+		t.Errorf("logged %s %s %q", e.Tool, e.Status, e.Error)
+	}
+
+	serve(s, "POST", "/bridge-log", `{"message":"proxy was unreachable and came back; retrying","retry":true}`)
+	if e := lastEntry(t, s); e.Status != StatusNotice || e.Preview != "bridge: proxy was unreachable and came back; retrying" {
+		t.Errorf("retry logged as %s %q", e.Status, e.Preview)
+	}
+
+	for _, tc := range []struct {
+		body string
+		code int
+	}{
+		{`{"message":"` + strings.Repeat("x", maxBridgeLog) + `"}`, http.StatusRequestEntityTooLarge},
+		{`{"message":" \t "}`, http.StatusBadRequest},
+		{`not json`, http.StatusBadRequest},
+	} {
+		if w := serve(s, "POST", "/bridge-log", tc.body); w.Code != tc.code {
+			t.Errorf("%.20q: %d, want %d", tc.body, w.Code, tc.code)
+		}
+	}
+	if e := lastEntry(t, s); e.Tool != "" {
+		t.Errorf("rejected report logged: %+v", e)
+	}
+}
@@ -0,0 +1,143 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ClientHeader identifies which agent session a call belongs to. The MCP
+// bridge sets it per process; callers without it share the session token's
+// count.
+const ClientHeader = "X-Boba-Client"
+
+// Budget headers sent on every /call and /stream response.
+const (
+	BudgetCountHeader = "X-Boba-Tool-Calls"
+	BudgetNoteHeader  = "X-Boba-Budget-Note"
+)
+
+// defaultClient is the count key for callers that don't send ClientHeader.
+const defaultClient = "session"
+
+// ClientCalls is the number of tool calls one client has made.
+type ClientCalls struct {
+	Client string
+	Calls  int
+}
+
+// callBudget counts tool calls per client against a soft budget, which only
+// adds notes, and an optional hard cap, which refuses further calls.
+type callBudget struct {
+	mu     sync.Mutex
+	counts map[string]int
+	soft   int
+	hard   int
+}
+
+func newCallBudget(soft, hard int) *callBudget {
+	return &callBudget{counts: make(map[string]int), soft: soft, hard: hard}
+}
+
+// take records a call for client and returns the new count. When the hard
+// cap is already reached the call is not counted and ok is false.
+func (b *callBudget) take(client string) (n int, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.hard > 0 && b.counts[client] >= b.hard {
+		return b.counts[client], false
+	}
+	b.counts[client]++
+	return b.counts[client], true
+}
+
+// limit is the number notes and headers count against: the hard cap when
+// set, since that is the one that matters, otherwise the soft budget.
+func (b *callBudget) limit() int {
+	if b.hard > 0 {
+		return b.hard
+	}
+	return b.soft
+}
+
+// note returns the line appended to a tool result once n crosses 80% and
+// again at 100% of the budget, or "" below that.
+func (b *callBudget) note(n int) string {
+	limit := b.limit()
+	if limit <= 0 || n*5 < limit*4 {
+		return ""
+	}
+	if n >= limit {
+		return fmt.Sprintf("note: %d/%d tool calls used this session; the budget is spent, wrap up and summarize for the user", n, limit)
+	}
+	return fmt.Sprintf("note: %d/%d tool calls used this session", n, limit)
+}
+
+// header returns the running count as "used/limit".
+func (b *callBudget) header(n int) string {
+	return fmt.Sprintf("%d/%d", n, b.limit())
+}
+
+// reset clears every client's count.
+func (b *callBudget) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counts = make(map[string]int)
+}
+
+// snapshot returns the counts sorted by client name.
+func (b *callBudget) snapshot() []ClientCalls {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]ClientCalls, 0, len(b.counts))
+	for c, n := range b.counts {
+		out = append(out, ClientCalls{Client: c, Calls: n})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Client < out[j].Client })
+	return out
+}
+
+// refuseOverBudget answers a call past the hard cap with a 429 that tells
+// the agent to stop.
+func (s *ProxyServer) refuseOverBudget(w http.ResponseWriter, id, toolName string, used int) {
+	errMsg := fmt.Sprintf("tool-call budget exceeded: %d/%d calls used this session", used, s.budget.limit())
+	s.sendLog(LogEntry{
+		ID:     id,
+		Tool:   toolName,
+		Status: "error",
+		Error:  errMsg,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error":   "tool_budget_exceeded",
+		"message": errMsg + ". Do not make further tool calls; summarize what you have done and found so far and stop.",
+		"used":    used,
+		"limit":   s.budget.limit(),
+	})
+}
+
+// budgetClient returns the count key for a request.
+func budgetClient(r *http.Request) string {
+	if c := r.Header.Get(ClientHeader); c != "" {
+		if len(c) > 64 {
+			c = c[:64]
+		}
+		return c
+	}
+	return defaultClient
+}
+
+// ClientCalls returns the tool-call count of every client seen since the
+// proxy started or the counts were last reset.
+func (s *ProxyServer) ClientCalls() []ClientCalls {
+	return s.budget.snapshot()
+}
+
+// handleBudgetReset clears the per-client tool-call counts.
+func (s *ProxyServer) handleBudgetReset(w http.ResponseWriter, r *http.Request) {
+	s.budget.reset()
+	w.WriteHeader(http.StatusNoContent)
+}
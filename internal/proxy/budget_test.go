@@ -0,0 +1,84 @@
+package proxy
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBudgetNote(t *testing.T) {
+	b := newCallBudget(10, 0)
+	for _, tc := range []struct {
+		n    int
+		want string
+	}{
+		{1, ""},
+		{7, ""},
+		{8, "note: 8/10 tool calls used this session"},
+		{9, "note: 9/10 tool calls used this session"},
+		{10, "note: 10/10 tool calls used this session; the budget is spent, wrap up and summarize for the user"},
+		{12, "note: 12/10 tool calls used this session; the budget is spent, wrap up and summarize for the user"},
+	} {
+		if got := b.note(tc.n); got != tc.want {
+			t.Errorf("note(%d) = %q, want %q", tc.n, got, tc.want)
+		}
+	}
+	if got := newCallBudget(0, 0).note(1000); got != "" {
+		t.Errorf("note without a budget = %q", got)
+	}
+	// The hard cap is what the notes count against when set.
+	if got := newCallBudget(100, 5).note(4); got != "note: 4/5 tool calls used this session" {
+		t.Errorf("note against the hard cap = %q", got)
+	}
+}
+
+func TestBudgetHardCap(t *testing.T) {
+	b := newCallBudget(0, 2)
+	for i := 1; i <= 2; i++ {
+		if n, ok := b.take("a"); !ok || n != i {
+			t.Fatalf("take %d = %d, %v", i, n, ok)
+		}
+	}
+	if n, ok := b.take("a"); ok || n != 2 {
+		t.Errorf("take past the cap = %d, %v; want 2, false", n, ok)
+	}
+	if _, ok := b.take("b"); !ok {
+		t.Error("another client refused")
+	}
+	b.reset()
+	if _, ok := b.take("a"); !ok {
+		t.Error("take after reset refused")
+	}
+}
+
+// Calls and streams share a client's budget, and past the hard cap both
+// are refused.
+func TestBudgetHardCapOverHTTP(t *testing.T) {
+	backend := &fakeBackend{}
+	s := newTestServer(t, backend)
+	s.budget = newCallBudget(0, 2)
+
+	if w := serve(s, "POST", "/call", `{"tool":"get_token_info","args":{}}`); w.Code != http.StatusOK {
+		t.Fatalf("call: status %d: %s", w.Code, w.Body)
+	}
+	w := serve(s, "GET", "/stream?tool=stream_prices", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("stream: status %d: %s", w.Code, w.Body)
+	}
+	if got := w.Header().Get(BudgetCountHeader); got != "2/2" {
+		t.Errorf("%s = %q, want 2/2", BudgetCountHeader, got)
+	}
+
+	for _, req := range [][3]string{
+		{"POST", "/call", `{"tool":"get_token_info","args":{}}`},
+		{"GET", "/stream?tool=stream_prices", ""},
+	} {
+		w := serve(s, req[0], req[1], req[2])
+		if w.Code != http.StatusTooManyRequests || !strings.Contains(w.Body.String(), "tool_budget_exceeded") {
+			t.Errorf("%s past the cap: status %d: %s", req[1], w.Code, w.Body)
+		}
+	}
+	if n := backend.calls.Load() + backend.streams.Load(); n != 2 {
+		t.Errorf("backend saw %d requests, want 2", n)
+	}
+}
@@ -0,0 +1,134 @@
+package proxy
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Agents often ask for the same token or portfolio several times within a
+// few seconds. Successful answers from read-only tools are kept briefly and
+// served again without a round trip to the backend.
+
+// cacheTTLs lists the tools whose responses are cached, and for how long.
+var cacheTTLs = map[string]time.Duration{
+	"get_portfolio":         10 * time.Second,
+	"get_portfolio_summary": 10 * time.Second,
+	"get_agent_balances":    10 * time.Second,
+	"get_token_price":       15 * time.Second,
+	"get_token_info":        30 * time.Second,
+	"get_token_details":     30 * time.Second,
+	"search_tokens":         60 * time.Second,
+	"search_token_by_slug":  60 * time.Second,
+	"get_trending_tokens":   60 * time.Second,
+	"get_category_tokens":   60 * time.Second,
+	"audit_token":           5 * time.Minute,
+	"get_user_xp":           60 * time.Second,
+}
+
+// cacheMaxEntries bounds the cache; the least recently used entry goes
+// first.
+const cacheMaxEntries = 256
+
+// FreshArg is the argument an agent sets to true to skip the cache. It is
+// removed before the call is forwarded.
+const FreshArg = "fresh"
+
+// CachedHeader marks a response served from the cache.
+const CachedHeader = "X-Boba-Cached"
+
+type cacheEntry struct {
+	key     string
+	body    []byte
+	expires time.Time
+}
+
+// responseCache is a TTL cache of tool responses keyed by tool and
+// arguments, bounded to cacheMaxEntries.
+type responseCache struct {
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // most recently used at the front
+}
+
+func newResponseCache() *responseCache {
+	return &responseCache{entries: make(map[string]*list.Element), order: list.New()}
+}
+
+// cacheKey identifies a call. json.Marshal sorts map keys, so equal
+// arguments give equal keys whatever order they arrived in. ok is false
+// for tools that aren't cached.
+func cacheKey(tool string, args map[string]any) (key string, ok bool) {
+	if _, cacheable := cacheTTLs[tool]; !cacheable {
+		return "", false
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", false
+	}
+	return tool + " " + string(data), true
+}
+
+// get returns a live cached response.
+func (c *responseCache) get(key string, now time.Time) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*cacheEntry)
+	if now.After(e.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(el)
+	return e.body, true
+}
+
+// put stores a response for the tool's TTL, evicting the least recently
+// used entry when full.
+func (c *responseCache) put(key, tool string, body []byte, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e := &cacheEntry{key: key, body: body, expires: now.Add(cacheTTLs[tool])}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(e)
+	for c.order.Len() > cacheMaxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// clear drops every entry, after a call that changes balances or orders.
+func (c *responseCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// DisableCache forwards every call to the backend. It must be called
+// before Start.
+func (s *ProxyServer) DisableCache() {
+	s.cache = nil
+}
+
+// takeFresh removes the fresh argument and reports whether it asked to
+// skip the cache.
+func takeFresh(args map[string]any) bool {
+	v, ok := args[FreshArg]
+	if !ok {
+		return false
+	}
+	delete(args, FreshArg)
+	fresh, _ := v.(bool)
+	return fresh
+}
@@ -0,0 +1,153 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Equal arguments give equal keys whatever order they arrived in, and only
+// the listed tools are cached.
+func TestCacheKey(t *testing.T) {
+	a, ok := cacheKey("get_token_info", map[string]any{"address": "x", "chain": "solana", "opts": map[string]any{"b": 1, "a": 2}})
+	if !ok {
+		t.Fatal("get_token_info is not cacheable")
+	}
+	var args map[string]any
+	json.Unmarshal([]byte(`{"opts":{"a":2,"b":1},"chain":"solana","address":"x"}`), &args)
+	if b, _ := cacheKey("get_token_info", args); a != b {
+		t.Errorf("keys differ by argument order:\n%s\n%s", a, b)
+	}
+	if c, _ := cacheKey("get_token_price", args); c == a {
+		t.Error("different tools share a key")
+	}
+	if _, ok := cacheKey("execute_swap", args); ok {
+		t.Error("execute_swap is cacheable")
+	}
+}
+
+// Entries live for their tool's TTL.
+func TestResponseCacheTTL(t *testing.T) {
+	c := newResponseCache()
+	now := time.Now()
+	c.put("info", "get_token_info", []byte("info"), now)
+	c.put("portfolio", "get_portfolio", []byte("portfolio"), now)
+
+	later := now.Add(15 * time.Second)
+	if body, ok := c.get("info", later); !ok || string(body) != "info" {
+		t.Errorf("token info after 15s: %q, %v", body, ok)
+	}
+	if _, ok := c.get("portfolio", later); ok {
+		t.Error("portfolio outlived its 10s TTL")
+	}
+	if _, ok := c.get("info", now.Add(31*time.Second)); ok {
+		t.Error("token info outlived its 30s TTL")
+	}
+	if len(c.entries) != 0 || c.order.Len() != 0 {
+		t.Errorf("expired entries kept: %d", c.order.Len())
+	}
+}
+
+// A full cache evicts the least recently used entry.
+func TestResponseCacheEviction(t *testing.T) {
+	c := newResponseCache()
+	now := time.Now()
+	for i := range cacheMaxEntries {
+		c.put(fmt.Sprint(i), "audit_token", []byte("x"), now)
+	}
+	c.get("0", now) // 1 is now the least recently used
+	c.put("new", "audit_token", []byte("x"), now)
+
+	if len(c.entries) != cacheMaxEntries {
+		t.Errorf("%d entries, want %d", len(c.entries), cacheMaxEntries)
+	}
+	if _, ok := c.get("1", now); ok {
+		t.Error("least recently used entry kept")
+	}
+	for _, key := range []string{"0", "2", "new"} {
+		if _, ok := c.get(key, now); !ok {
+			t.Errorf("entry %s evicted", key)
+		}
+	}
+}
+
+// The cache is shared by concurrent calls; run with -race.
+func TestResponseCacheConcurrent(t *testing.T) {
+	c := newResponseCache()
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 500 {
+				key := fmt.Sprint(i % 300)
+				now := time.Now()
+				c.put(key, "get_token_info", []byte(key), now)
+				if body, ok := c.get(key, now); ok && string(body) != key {
+					t.Errorf("key %s gave %q", key, body)
+				}
+				if g == 0 && i%100 == 0 {
+					c.clear()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if len(c.entries) > cacheMaxEntries || len(c.entries) != c.order.Len() {
+		t.Errorf("%d entries, %d in order", len(c.entries), c.order.Len())
+	}
+}
+
+// A repeated read-only call is answered from the cache, logged as cached
+// and kept out of the backend latency stats; fresh:true and --no-cache go
+// to the backend.
+func TestCallCached(t *testing.T) {
+	var forwarded []map[string]any
+	backend := &fakeBackend{reply: func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Args map[string]any }
+		json.NewDecoder(r.Body).Decode(&body)
+		forwarded = append(forwarded, body.Args)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"symbol":"WIF"}`))
+	}}
+	s := newTestServer(t, backend)
+	call := `{"tool":"get_token_info","args":{"address":"WIF"}}`
+
+	if w := serve(s, "POST", "/call", call); w.Header().Get(CachedHeader) != "" {
+		t.Error("first call marked cached")
+	}
+	w := serve(s, "POST", "/call", call)
+	if w.Code != http.StatusOK || w.Body.String() != `{"success":true,"symbol":"WIF"}` {
+		t.Errorf("cached call: status %d: %s", w.Code, w.Body)
+	}
+	if w.Header().Get(CachedHeader) != "1" {
+		t.Error("cached call not marked")
+	}
+	if e := lastEntry(t, s); !e.Cached || e.Status != "success" {
+		t.Errorf("log entry = %+v, want a cached success", e)
+	}
+	if n := backend.calls.Load(); n != 1 {
+		t.Errorf("backend saw %d calls, want 1", n)
+	}
+	st := s.metrics.tools["get_token_info"]
+	if st.calls != 1 || st.cacheHits != 1 {
+		t.Errorf("metrics: %d calls, %d cache hits; want 1 and 1", st.calls, st.cacheHits)
+	}
+
+	serve(s, "POST", "/call", `{"tool":"get_token_info","args":{"address":"WIF","fresh":true}}`)
+	if n := backend.calls.Load(); n != 2 {
+		t.Errorf("fresh call: backend saw %d calls, want 2", n)
+	}
+	if _, sent := forwarded[len(forwarded)-1][FreshArg]; sent {
+		t.Errorf("fresh was forwarded: %v", forwarded[len(forwarded)-1])
+	}
+
+	s.DisableCache()
+	serve(s, "POST", "/call", call)
+	if n := backend.calls.Load(); n != 3 {
+		t.Errorf("with the cache off: backend saw %d calls, want 3", n)
+	}
+}
@@ -0,0 +1,179 @@
+package proxy
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// Chaos injection makes upstream calls fail on purpose so alerting and retry
+// behavior can be exercised without a real outage. It is refused against the
+// default backend.
+
+// ChaosSpec is how often each kind of failure is injected. Rates are
+// probabilities between 0 and 1, rolled independently per upstream call.
+type ChaosSpec struct {
+	ErrorRate   float64
+	Latency     time.Duration
+	LatencyRate float64
+	TimeoutRate float64
+}
+
+// Kinds of injected failure, as counted by ChaosCounts.
+const (
+	ChaosError   = "error"
+	ChaosLatency = "latency"
+	ChaosTimeout = "timeout"
+)
+
+// errChaos marks injected failures. Its text shows up in log entries and
+// error responses so they can't be mistaken for real problems.
+var errChaos = errors.New("[chaos]")
+
+// chaosErrorBody is the response to an injected upstream error.
+var chaosErrorBody = []byte(`{"error":"[chaos] injected upstream error"}`)
+
+// injectedFailure reports whether an upstream call's failure was injected,
+// so it can be kept out of the error metrics.
+func injectedFailure(err error, body []byte) bool {
+	return errors.Is(err, errChaos) || bytes.Equal(body, chaosErrorBody)
+}
+
+// ParseChaosSpec parses a spec like "error=0.1,latency=500ms:0.2,timeout=0.05".
+func ParseChaosSpec(s string) (ChaosSpec, error) {
+	var spec ChaosSpec
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kind, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return spec, fmt.Errorf("chaos: %q is not kind=value", part)
+		}
+		var err error
+		switch kind {
+		case ChaosError:
+			spec.ErrorRate, err = parseRate(val)
+		case ChaosTimeout:
+			spec.TimeoutRate, err = parseRate(val)
+		case ChaosLatency:
+			d, rate, found := strings.Cut(val, ":")
+			if spec.Latency, err = time.ParseDuration(d); err == nil && spec.Latency <= 0 {
+				err = fmt.Errorf("latency must be positive")
+			}
+			spec.LatencyRate = 1
+			if err == nil && found {
+				spec.LatencyRate, err = parseRate(rate)
+			}
+		default:
+			return spec, fmt.Errorf("chaos: unknown kind %q (want error, latency or timeout)", kind)
+		}
+		if err != nil {
+			return spec, fmt.Errorf("chaos: %s: %w", kind, err)
+		}
+	}
+	if spec.ErrorRate == 0 && spec.LatencyRate == 0 && spec.TimeoutRate == 0 {
+		return spec, fmt.Errorf("chaos: spec %q injects nothing", s)
+	}
+	return spec, nil
+}
+
+func parseRate(s string) (float64, error) {
+	r, err := strconv.ParseFloat(s, 64)
+	if err != nil || r < 0 || r > 1 {
+		return 0, fmt.Errorf("rate %q must be between 0 and 1", s)
+	}
+	return r, nil
+}
+
+// String renders the spec in the form ParseChaosSpec accepts.
+func (c ChaosSpec) String() string {
+	var parts []string
+	if c.ErrorRate > 0 {
+		parts = append(parts, fmt.Sprintf("error=%g", c.ErrorRate))
+	}
+	if c.LatencyRate > 0 {
+		parts = append(parts, fmt.Sprintf("latency=%s:%g", c.Latency, c.LatencyRate))
+	}
+	if c.TimeoutRate > 0 {
+		parts = append(parts, fmt.Sprintf("timeout=%g", c.TimeoutRate))
+	}
+	return strings.Join(parts, ",")
+}
+
+// chaosState is the active spec and how many failures of each kind were
+// injected.
+type chaosState struct {
+	spec ChaosSpec
+
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+func (c *chaosState) count(kind string) {
+	c.mu.Lock()
+	c.counts[kind]++
+	c.mu.Unlock()
+}
+
+// inject rolls the dice before an upstream call. It may sleep, and returns a
+// synthetic status and body for an injected upstream error, or an error for
+// an injected timeout. A zero status means the call should go ahead.
+func (c *chaosState) inject() (int, []byte, error) {
+	if c.spec.LatencyRate > 0 && rand.Float64() < c.spec.LatencyRate {
+		c.count(ChaosLatency)
+		time.Sleep(c.spec.Latency)
+	}
+	if c.spec.TimeoutRate > 0 && rand.Float64() < c.spec.TimeoutRate {
+		c.count(ChaosTimeout)
+		return 0, nil, fmt.Errorf("%w injected timeout", errChaos)
+	}
+	if c.spec.ErrorRate > 0 && rand.Float64() < c.spec.ErrorRate {
+		c.count(ChaosError)
+		return http.StatusServiceUnavailable, chaosErrorBody, nil
+	}
+	return 0, nil, nil
+}
+
+// EnableChaos turns on failure injection for upstream calls. It must be
+// called before Start and is refused while the proxy talks to the default
+// backend.
+func (s *ProxyServer) EnableChaos(spec ChaosSpec) error {
+	if config.GetMCPURL() == config.DefaultMCPURL {
+		return fmt.Errorf("chaos injection is only allowed against a non-default MCP backend (set mcpUrl first)")
+	}
+	s.chaos = &chaosState{spec: spec, counts: make(map[string]int64)}
+	return nil
+}
+
+// Chaos returns the active chaos spec, if failure injection is on.
+func (s *ProxyServer) Chaos() (ChaosSpec, bool) {
+	if s.chaos == nil {
+		return ChaosSpec{}, false
+	}
+	return s.chaos.spec, true
+}
+
+// ChaosCounts returns how many failures of each kind were injected, kept
+// apart from real errors.
+func (s *ProxyServer) ChaosCounts() map[string]int64 {
+	if s.chaos == nil {
+		return nil
+	}
+	s.chaos.mu.Lock()
+	defer s.chaos.mu.Unlock()
+	out := make(map[string]int64, len(s.chaos.counts))
+	for k, v := range s.chaos.counts {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,217 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/client"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/metrics"
+)
+
+func TestParseChaosSpec(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+	}{
+		{"error=0.1,latency=500ms:0.2,timeout=0.05", "error=0.1,latency=500ms:0.2,timeout=0.05"},
+		{" timeout=1 , error=0.5 ", "error=0.5,timeout=1"},
+		{"latency=2s", "latency=2s:1"},
+	} {
+		spec, err := ParseChaosSpec(tc.in)
+		if err != nil {
+			t.Errorf("%q: %v", tc.in, err)
+			continue
+		}
+		if got := spec.String(); got != tc.want {
+			t.Errorf("%q: String() = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+	for _, bad := range []string{"", "error", "error=2", "error=-0.1", "latency=0s", "latency=fast", "latency=1s:x", "drop=0.1", "error=0"} {
+		if _, err := ParseChaosSpec(bad); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
+
+func TestChaosRefusedOnDefaultBackend(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+	if err := s.EnableChaos(ChaosSpec{ErrorRate: 1}); err == nil {
+		t.Error("chaos enabled against the default backend")
+	}
+	if _, on := s.Chaos(); on {
+		t.Error("chaos on after being refused")
+	}
+}
+
+// chaosServer returns a proxy in front of backend that is configured for a
+// non-default backend, as chaos mode requires.
+func chaosServer(t *testing.T, backend *fakeBackend) *ProxyServer {
+	t.Helper()
+	s := newTestServer(t, backend)
+	t.Setenv(config.EnvAllowAnyHost, "1")
+	t.Setenv(config.EnvMCPURL, s.backend.BaseURL)
+	return s
+}
+
+// lastEntry drains the activity log and returns the final entry of the
+// last call.
+func lastEntry(t *testing.T, s *ProxyServer) LogEntry {
+	t.Helper()
+	var last LogEntry
+	for {
+		select {
+		case e := <-s.LogChannel():
+			if !e.InFlight() {
+				last = e
+			}
+		default:
+			return last
+		}
+	}
+}
+
+// Injected failures go through the same retry and classification as real
+// ones, are marked [chaos] where the dashboard shows them, and are counted
+// apart from real errors, so they neither raise the error metrics nor mark
+// the backend offline.
+func TestChaosResilience(t *testing.T) {
+	backend := &fakeBackend{}
+	s := chaosServer(t, backend)
+
+	// An injected timeout of a read-only call is retried once.
+	if err := s.EnableChaos(ChaosSpec{TimeoutRate: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if w := serve(s, "POST", "/call", `{"tool":"get_token_info","args":{}}`); w.Code != http.StatusBadGateway {
+		t.Errorf("injected timeout: status %d, want 502", w.Code)
+	}
+	if e := lastEntry(t, s); !strings.Contains(e.Error, "[chaos]") || e.ErrorKind != ErrorNetwork {
+		t.Errorf("injected timeout logged as %q (%s), want a [chaos] network error", e.Error, e.ErrorKind)
+	}
+	if n := s.ChaosCounts()[ChaosTimeout]; n != 2 {
+		t.Errorf("read-only call attempted %d times, want a retry", n)
+	}
+
+	// A write call never is.
+	serve(s, "POST", "/call", `{"tool":"cancel_limit_order","args":{"order_id":"1"}}`)
+	if n := s.ChaosCounts()[ChaosTimeout]; n != 3 {
+		t.Errorf("write call retried: %d attempts in all, want 3", n)
+	}
+	lastEntry(t, s)
+	if out := serve(s, "GET", "/metrics", "").Body.String(); !strings.Contains(out, metrics.ChaosInjected+`{kind="timeout"} 3`) {
+		t.Errorf("/metrics doesn't count the injected timeouts:\n%s", out)
+	}
+
+	// An injected upstream error is an answer: not retried, classified as
+	// a server error.
+	s.EnableChaos(ChaosSpec{ErrorRate: 1})
+	if w := serve(s, "POST", "/call", `{"tool":"get_token_info","args":{}}`); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("injected error: status %d, want 503", w.Code)
+	}
+	if e := lastEntry(t, s); !strings.Contains(e.Error, "[chaos]") || e.ErrorKind != ErrorServer {
+		t.Errorf("injected error logged as %q (%s), want a [chaos] server error", e.Error, e.ErrorKind)
+	}
+	if n := s.ChaosCounts()[ChaosError]; n != 1 {
+		t.Errorf("injected error attempted %d times, want 1", n)
+	}
+
+	// Latency only delays the call, which then reaches the backend.
+	s.EnableChaos(ChaosSpec{Latency: 20 * time.Millisecond, LatencyRate: 1})
+	start := time.Now()
+	if w := serve(s, "POST", "/call", `{"tool":"get_token_info","args":{}}`); w.Code != http.StatusOK {
+		t.Errorf("injected latency: status %d", w.Code)
+	}
+	if time.Since(start) < 20*time.Millisecond || backend.calls.Load() != 1 {
+		t.Errorf("latency not injected or call not forwarded (%d backend calls)", backend.calls.Load())
+	}
+	lastEntry(t, s)
+
+	if backend.calls.Load() != 1 {
+		t.Errorf("backend saw %d calls, want only the delayed one", backend.calls.Load())
+	}
+	if offline, _ := s.BackendOffline(); offline {
+		t.Error("injected failures marked the backend offline")
+	}
+	out := serve(s, "GET", "/metrics", "").Body.String()
+	for _, want := range []string{
+		metrics.ToolErrors + `{tool="get_token_info",kind="network"} 0`,
+		metrics.ToolErrors + `{tool="get_token_info",kind="server"} 0`,
+		metrics.ToolErrors + `{tool="cancel_limit_order",kind="network"} 0`,
+		metrics.ChaosInjected + `{kind="latency"} 1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("/metrics lacks %q", want)
+		}
+	}
+}
+
+// The backend is marked offline after degradedAfter transport failures in
+// a row. Answers, even error statuses, reset the count; cancelled requests
+// say nothing about the backend.
+func TestBackendHealth(t *testing.T) {
+	var h backendHealth
+	refused := &client.TransportError{Err: errors.New("connection refused")}
+	for i := 1; i < degradedAfter; i++ {
+		h.observe(refused)
+	}
+	h.observe(context.Canceled)
+	if offline, _ := h.state(); offline {
+		t.Fatalf("offline after %d failures", degradedAfter-1)
+	}
+	h.observe(&client.UpstreamError{Status: 500})
+	for i := 1; i < degradedAfter; i++ {
+		h.observe(refused)
+	}
+	if offline, _ := h.state(); offline {
+		t.Fatal("an error status didn't reset the count")
+	}
+	h.observe(refused)
+	if offline, since := h.state(); !offline || since.IsZero() {
+		t.Fatalf("not offline after %d failures in a row", degradedAfter)
+	}
+	h.observe(nil)
+	if offline, _ := h.state(); offline {
+		t.Error("still offline after an answer")
+	}
+}
+
+// A backend nobody answers on is marked offline by the failing calls
+// themselves, and back online with the first call it answers.
+func TestBackendDegradedMode(t *testing.T) {
+	backend := &fakeBackend{}
+	s := newTestServer(t, backend)
+	up := s.backend.BaseURL
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.backend.BaseURL = "http://" + ln.Addr().String()
+	ln.Close()
+
+	// The schema fetch, the call and its retry all fail.
+	serve(s, "POST", "/call", `{"tool":"get_token_info","args":{}}`)
+	if e := lastEntry(t, s); e.ErrorKind != ErrorNetwork || strings.Contains(e.Error, "[chaos]") {
+		t.Errorf("refused connection logged as %q (%s), want a network error", e.Error, e.ErrorKind)
+	}
+	if offline, since := s.BackendOffline(); !offline || since.IsZero() {
+		t.Fatal("not offline after a call that couldn't reach the backend")
+	}
+	out := serve(s, "GET", "/metrics", "").Body.String()
+	if want := metrics.ToolErrors + `{tool="get_token_info",kind="network"} 1`; !strings.Contains(out, want) {
+		t.Errorf("/metrics lacks %q", want)
+	}
+
+	s.backend.BaseURL = up
+	if w := serve(s, "POST", "/call", `{"tool":"get_token_info","args":{}}`); w.Code != http.StatusOK {
+		t.Fatalf("status %d after recovery", w.Code)
+	}
+	if offline, _ := s.BackendOffline(); offline {
+		t.Error("still offline after the backend answered")
+	}
+}
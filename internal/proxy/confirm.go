@@ -0,0 +1,207 @@
+package proxy
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Confirmation mode holds tool calls that move funds or change orders until
+// the user approves them in the dashboard. Calls nobody answers are denied
+// once their deadline passes.
+
+// StatusAwaitingConfirmation is the LogEntry status of a call held for the
+// user's approval.
+const StatusAwaitingConfirmation = "awaiting-confirmation"
+
+// DefaultConfirmTimeout is how long a held call waits before it is denied.
+const DefaultConfirmTimeout = 60 * time.Second
+
+// NeedsConfirmation reports whether a tool writes: swaps, trades, and
+// creating, cancelling or updating orders.
+func NeedsConfirmation(tool string) bool {
+	switch tool {
+	case "execute_swap", "execute_trade":
+		return true
+	}
+	if strings.HasPrefix(tool, "create_") && strings.HasSuffix(tool, "_order") {
+		return true
+	}
+	return strings.HasPrefix(tool, "cancel_") || strings.HasPrefix(tool, "update_")
+}
+
+// PendingConfirmation is a call waiting for the user's decision.
+type PendingConfirmation struct {
+	ID        string // request ID, as on the call's log entries
+	Tool      string
+	Args      map[string]any
+	Requested time.Time
+	Deadline  time.Time
+}
+
+// Detail is one labelled piece of a held call, such as the amount.
+type Detail struct {
+	Label string
+	Value string
+}
+
+// Details pulls what the user needs to judge the call out of its arguments:
+// the tokens, amount, price and chain, under whichever names the tool uses.
+func (p PendingConfirmation) Details() []Detail {
+	fields := []struct {
+		label string
+		keys  []string
+	}{
+		{"order", []string{"order_id", "orderId"}},
+		{"sell", []string{"from_token", "fromToken", "input_token", "sell_token", "token_in", "input_mint"}},
+		{"buy", []string{"to_token", "toToken", "output_token", "buy_token", "token_out", "output_mint"}},
+		{"token", []string{"token_address", "token", "mint"}},
+		{"amount", []string{"amount", "from_amount", "input_amount", "amount_in", "total_amount"}},
+		{"price", []string{"trigger_price", "limit_price", "price"}},
+		{"chain", []string{"chain", "chain_id", "chainId"}},
+	}
+	var out []Detail
+	for _, f := range fields {
+		for _, k := range f.keys {
+			if v, ok := p.Args[k]; ok && v != nil && fmt.Sprint(v) != "" {
+				out = append(out, Detail{Label: f.label, Value: fmt.Sprint(v)})
+				break
+			}
+		}
+	}
+	return out
+}
+
+// confirmDecision is how a held call was resolved.
+type confirmDecision int
+
+const (
+	confirmApproved confirmDecision = iota
+	confirmDenied
+	confirmTimedOut
+	confirmAbandoned
+	confirmUnavailable
+)
+
+type heldCall struct {
+	PendingConfirmation
+	decide chan bool
+}
+
+// confirmQueue holds write calls in arrival order until they are approved,
+// denied or time out.
+type confirmQueue struct {
+	timeout time.Duration
+	mu      sync.Mutex
+	held    []*heldCall
+}
+
+// EnableConfirmations holds write calls for approval through Confirm,
+// denying any not answered within timeout. A timeout of zero means nobody
+// can answer, so write calls are denied straight away. It must be called
+// before Start.
+func (s *ProxyServer) EnableConfirmations(timeout time.Duration) {
+	s.confirm = &confirmQueue{timeout: timeout}
+}
+
+// ConfirmationsEnabled reports whether write calls are held for approval.
+func (s *ProxyServer) ConfirmationsEnabled() bool {
+	return s.confirm != nil
+}
+
+// PendingConfirmations returns the held calls, oldest first.
+func (s *ProxyServer) PendingConfirmations() []PendingConfirmation {
+	if s.confirm == nil {
+		return nil
+	}
+	s.confirm.mu.Lock()
+	defer s.confirm.mu.Unlock()
+	out := make([]PendingConfirmation, len(s.confirm.held))
+	for i, h := range s.confirm.held {
+		out[i] = h.PendingConfirmation
+	}
+	return out
+}
+
+// Confirm approves or denies the held call with the given request ID. It
+// returns false if no such call is waiting, e.g. because it timed out.
+func (s *ProxyServer) Confirm(id string, approve bool) bool {
+	if s.confirm == nil {
+		return false
+	}
+	h := s.confirm.remove(id)
+	if h == nil {
+		return false
+	}
+	h.decide <- approve
+	return true
+}
+
+// hold queues a call for the user's decision. It returns nil when nobody
+// can answer.
+func (q *confirmQueue) hold(id, tool string, args map[string]any) *heldCall {
+	if q.timeout <= 0 {
+		return nil
+	}
+	now := time.Now()
+	h := &heldCall{
+		PendingConfirmation: PendingConfirmation{
+			ID:        id,
+			Tool:      tool,
+			Args:      args,
+			Requested: now,
+			Deadline:  now.Add(q.timeout),
+		},
+		decide: make(chan bool, 1),
+	}
+	q.mu.Lock()
+	q.held = append(q.held, h)
+	q.mu.Unlock()
+	return h
+}
+
+// wait blocks until the held call is decided, its deadline passes or the
+// caller goes away.
+func (q *confirmQueue) wait(h *heldCall, done <-chan struct{}) confirmDecision {
+	if h == nil {
+		return confirmUnavailable
+	}
+	timer := time.NewTimer(time.Until(h.Deadline))
+	defer timer.Stop()
+	select {
+	case ok := <-h.decide:
+		if ok {
+			return confirmApproved
+		}
+		return confirmDenied
+	case <-timer.C:
+		return q.expire(h, confirmTimedOut)
+	case <-done:
+		return q.expire(h, confirmAbandoned)
+	}
+}
+
+// expire takes h off the queue once its wait ends undecided. A decision may
+// have landed at the same moment; it wins if so.
+func (q *confirmQueue) expire(h *heldCall, why confirmDecision) confirmDecision {
+	if q.remove(h.ID) != nil {
+		return why
+	}
+	if <-h.decide {
+		return confirmApproved
+	}
+	return confirmDenied
+}
+
+func (q *confirmQueue) remove(id string) *heldCall {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, h := range q.held {
+		if h.ID == id {
+			q.held = append(q.held[:i], q.held[i+1:]...)
+			return h
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package proxy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/logger"
+)
+
+// DebugEndpoint is where a running proxy's debug server listens. It is
+// written to a private file so `boba debug profile` can find it.
+type DebugEndpoint struct {
+	Addr  string `json:"addr"`
+	Token string `json:"token"`
+	PID   int    `json:"pid"`
+}
+
+// debugEndpointPath is the file holding the running debug server's endpoint.
+func debugEndpointPath() string {
+	return filepath.Join(config.DataDir(), "debug-server.json")
+}
+
+// LoadDebugEndpoint returns the endpoint of the running proxy's debug server.
+func LoadDebugEndpoint() (*DebugEndpoint, error) {
+	data, err := os.ReadFile(debugEndpointPath())
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no debug server running; start the proxy with 'boba start --debug-server' or BOBA_DEBUG=1")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ep DebugEndpoint
+	if err := json.Unmarshal(data, &ep); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", debugEndpointPath(), err)
+	}
+	return &ep, nil
+}
+
+// StartDebugServer exposes net/http/pprof and /debug/runtime on a separate
+// random localhost port guarded by its own token. It is only started when
+// asked for and is never mounted on the proxy port. The server shuts down
+// with the proxy.
+func (s *ProxyServer) StartDebugServer() error {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return fmt.Errorf("failed to generate debug token: %w", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/pprof/", requireBearer(token, pprof.Index))
+	mux.HandleFunc("GET /debug/pprof/cmdline", requireBearer(token, pprof.Cmdline))
+	mux.HandleFunc("GET /debug/pprof/profile", requireBearer(token, pprof.Profile))
+	mux.HandleFunc("GET /debug/pprof/symbol", requireBearer(token, pprof.Symbol))
+	mux.HandleFunc("GET /debug/pprof/trace", requireBearer(token, pprof.Trace))
+	mux.HandleFunc("GET /debug/runtime", requireBearer(token, s.handleDebugRuntime))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return fmt.Errorf("failed to start debug server: %w", err)
+	}
+
+	ep := DebugEndpoint{Addr: ln.Addr().String(), Token: token, PID: os.Getpid()}
+	data, _ := json.Marshal(ep)
+	if err := config.WritePrivateFile(debugEndpointPath(), data); err != nil {
+		ln.Close()
+		return fmt.Errorf("failed to record debug endpoint: %w", err)
+	}
+
+	// Profiles block for their whole duration, so there is no write timeout.
+	s.debugServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := s.debugServer.Serve(ln); err != nil && err != http.ErrServerClosed {
+			logger.Error("debug server error", "error", err)
+		}
+	}()
+
+	logger.Info("debug server listening", "addr", ep.Addr, "token", token)
+	return nil
+}
+
+// stopDebugServer shuts the debug server down and removes its endpoint file.
+func (s *ProxyServer) stopDebugServer(ctx context.Context) {
+	if s.debugServer == nil {
+		return
+	}
+	_ = s.debugServer.Shutdown(ctx)
+	_ = os.Remove(debugEndpointPath())
+}
+
+// handleDebugRuntime reports process health for diagnosing memory growth
+// and CPU spikes.
+func (s *ProxyServer) handleDebugRuntime(w http.ResponseWriter, r *http.Request) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	lastGC := ""
+	if mem.LastGC > 0 {
+		lastGC = time.Unix(0, int64(mem.LastGC)).UTC().Format(time.RFC3339)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"goroutines":      runtime.NumGoroutine(),
+		"heapInUse":       mem.HeapInuse,
+		"heapObjects":     mem.HeapObjects,
+		"numGC":           mem.NumGC,
+		"gcPauseTotalNs":  mem.PauseTotalNs,
+		"lastGC":          lastGC,
+		"logChannelDepth": len(s.logChan),
+		"logChannelCap":   cap(s.logChan),
+		"inFlight":        atomic.LoadInt64(&s.inFlight),
+		"requests":        s.getRequestCount(),
+	})
+}
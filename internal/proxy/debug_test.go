@@ -0,0 +1,97 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+)
+
+var debugPaths = []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/heap", "/debug/runtime"}
+
+// Without the flag there is no debug server and no endpoint file, and the
+// proxy port never serves the debug paths, token or not.
+func TestDebugEndpointsAbsentWithoutFlag(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+	for _, path := range debugPaths {
+		if w := serve(s, "GET", path, ""); w.Code != http.StatusNotFound {
+			t.Errorf("%s on the proxy port: status %d, want 404", path, w.Code)
+		}
+	}
+	if s.debugServer != nil {
+		t.Error("debug server started without being asked for")
+	}
+	if _, err := LoadDebugEndpoint(); err == nil {
+		t.Error("endpoint file exists without the debug server")
+	}
+}
+
+// With the flag the debug paths are served on their own localhost listener
+// behind their own token, and still not on the proxy port.
+func TestDebugServer(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+	if err := s.StartDebugServer(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.stopDebugServer(context.Background()) })
+	ep, err := LoadDebugEndpoint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host, _, _ := net.SplitHostPort(ep.Addr); host != "127.0.0.1" {
+		t.Errorf("debug server on %s, want localhost", ep.Addr)
+	}
+	if ep.Token == "" || ep.Token == testToken {
+		t.Errorf("debug token %q must be its own", ep.Token)
+	}
+	if info, err := os.Stat(debugEndpointPath()); err != nil || info.Mode().Perm()&0077 != 0 {
+		t.Errorf("endpoint file: %v, %v", info, err)
+	}
+
+	get := func(path, token string) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest("GET", "http://"+ep.Addr+path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	for _, path := range debugPaths {
+		for _, token := range []string{"", testToken} {
+			if resp := get(path, token); resp.StatusCode != http.StatusForbidden {
+				t.Errorf("%s with token %q: status %d, want 403", path, token, resp.StatusCode)
+			}
+		}
+		if resp := get(path, ep.Token); resp.StatusCode != http.StatusOK {
+			t.Errorf("%s: status %d", path, resp.StatusCode)
+		}
+		if w := serve(s, "GET", path, ""); w.Code != http.StatusNotFound {
+			t.Errorf("%s on the proxy port: status %d, want 404", path, w.Code)
+		}
+	}
+
+	var stats map[string]any
+	if err := json.NewDecoder(get("/debug/runtime", ep.Token).Body).Decode(&stats); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"goroutines", "heapInUse", "numGC", "logChannelDepth", "inFlight"} {
+		if _, ok := stats[key]; !ok {
+			t.Errorf("/debug/runtime lacks %s: %v", key, stats)
+		}
+	}
+
+	s.stopDebugServer(context.Background())
+	if _, err := LoadDebugEndpoint(); err == nil {
+		t.Error("endpoint file left after shutdown")
+	}
+	if _, err := http.Get("http://" + ep.Addr + "/debug/runtime"); err == nil {
+		t.Error("debug server still answering after shutdown")
+	}
+}
@@ -0,0 +1,125 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/tradeboba/boba-cli/internal/client"
+)
+
+// Kinds of failed tool call, in LogEntry.ErrorKind. Failures the proxy
+// decides on itself, such as a spent budget or a denied tool, have none.
+const (
+	ErrorRejected = "rejected" // the backend turned the call down, e.g. for an insufficient balance
+	ErrorAuth     = "auth"     // no valid credentials, or the backend refused them
+	ErrorTimeout  = "timeout"  // no answer within the tool's timeout
+	ErrorNetwork  = "network"  // the backend couldn't be reached
+	ErrorServer   = "server"   // the backend failed with a 5xx
+)
+
+// ErrorKinds lists the kinds in the order the dashboard counts them.
+var ErrorKinds = []string{ErrorRejected, ErrorAuth, ErrorTimeout, ErrorNetwork, ErrorServer}
+
+// classifyCallError returns the kind of a call that got no answer from the
+// backend.
+func classifyCallError(err error) string {
+	var authErr *client.AuthError
+	var timeout *TimeoutError
+	var netErr net.Error
+	var tooLarge *client.ResponseTooLargeError
+	switch {
+	case errors.As(err, &authErr):
+		return ErrorAuth
+	case errors.As(err, &tooLarge):
+		return ErrorServer
+	case errors.As(err, &timeout), errors.Is(err, context.DeadlineExceeded):
+		return ErrorTimeout
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTimeout
+	}
+	return ErrorNetwork
+}
+
+// classifyResponse returns the kind of a backend answer, or "" when the
+// call succeeded, with the backend's message when it gave one. A 2xx is a
+// rejection when its body says so, with success: false or an error.
+func classifyResponse(status int, body []byte) (kind, msg string) {
+	var data map[string]any
+	_ = json.Unmarshal(body, &data)
+	msg = errorMessage(data)
+
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrorAuth, msg
+	case status == http.StatusRequestTimeout || status == http.StatusGatewayTimeout:
+		return ErrorTimeout, msg
+	case status >= 500:
+		return ErrorServer, msg
+	case status >= 400:
+		return ErrorRejected, msg
+	}
+	if success, ok := data["success"].(bool); ok && !success {
+		return ErrorRejected, msg
+	}
+	if _, ok := data["error"].(map[string]any); ok {
+		return ErrorRejected, msg
+	}
+	if s, ok := data["error"].(string); ok && strings.TrimSpace(s) != "" {
+		return ErrorRejected, msg
+	}
+	return "", ""
+}
+
+// errorMessage digs the human-readable message out of an error body, in
+// any of the shapes the backend uses: {"error": "..."}, {"message": "..."},
+// {"error": {"message": "...", "code": "..."}}, or the same under "data".
+// The backend's code is appended when it adds something, e.g.
+// "insufficient SOL balance (INSUFFICIENT_BALANCE)".
+func errorMessage(data map[string]any) string {
+	if data == nil {
+		return ""
+	}
+	if inner, ok := data["error"].(map[string]any); ok {
+		if msg := errorMessage(inner); msg != "" {
+			return msg
+		}
+	}
+	var msg string
+	for _, k := range []string{"message", "error", "detail", "reason", "error_description"} {
+		if s, ok := data[k].(string); ok && strings.TrimSpace(s) != "" {
+			msg = cleanErrorMessage(s)
+			break
+		}
+	}
+	if msg == "" {
+		if inner, ok := data["data"].(map[string]any); ok {
+			return errorMessage(inner)
+		}
+		return ""
+	}
+	if code, ok := data["code"].(string); ok && code != "" && !strings.Contains(strings.ToLower(msg), strings.ToLower(code)) {
+		msg += " (" + code + ")"
+	}
+	return msg
+}
+
+// cleanErrorMessage tidies a backend message for a single log line:
+// whitespace collapsed, and "Error:" prefixes the backend stacked up
+// removed.
+func cleanErrorMessage(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	for {
+		trimmed := strings.TrimSpace(s)
+		for _, prefix := range []string{"Error:", "error:", "ERROR:"} {
+			trimmed = strings.TrimSpace(strings.TrimPrefix(trimmed, prefix))
+		}
+		if trimmed == s {
+			return s
+		}
+		s = trimmed
+	}
+}
@@ -0,0 +1,88 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/client"
+)
+
+func TestClassifyResponse(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		status int
+		body   string
+		kind   string
+		msg    string
+	}{
+		{"success", 200, `{"success":true,"data":{}}`, "", ""},
+		{"not json", 200, `ok`, "", ""},
+		{"empty error string", 200, `{"error":"  "}`, "", ""},
+		{"success false", 200, `{"success":false,"message":"insufficient SOL balance"}`, ErrorRejected, "insufficient SOL balance"},
+		{"error string on 2xx", 200, `{"error":"Error: slippage too high"}`, ErrorRejected, "slippage too high"},
+		{"error object", 400, `{"error":{"message":"not enough funds","code":"INSUFFICIENT_BALANCE"}}`, ErrorRejected, "not enough funds (INSUFFICIENT_BALANCE)"},
+		{"error object on 2xx", 200, `{"error":{"message":"bad mint"}}`, ErrorRejected, "bad mint"},
+		{"code already in message", 422, `{"message":"INSUFFICIENT_BALANCE: need 2 SOL","code":"INSUFFICIENT_BALANCE"}`, ErrorRejected, "INSUFFICIENT_BALANCE: need 2 SOL"},
+		{"nested under data", 400, `{"data":{"detail":"token not found"}}`, ErrorRejected, "token not found"},
+		{"401", 401, `{"error":"token expired"}`, ErrorAuth, "token expired"},
+		{"403", 403, ``, ErrorAuth, ""},
+		{"504", 504, `{"message":"upstream timed out"}`, ErrorTimeout, "upstream timed out"},
+		{"408", 408, ``, ErrorTimeout, ""},
+		{"500", 500, `{"error":"internal"}`, ErrorServer, "internal"},
+		{"503", 503, `<html>down</html>`, ErrorServer, ""},
+	} {
+		kind, msg := classifyResponse(tc.status, []byte(tc.body))
+		if kind != tc.kind || msg != tc.msg {
+			t.Errorf("%s: got (%q, %q), want (%q, %q)", tc.name, kind, msg, tc.kind, tc.msg)
+		}
+	}
+}
+
+func TestClassifyCallError(t *testing.T) {
+	// A port nothing listens on.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	_, refused := http.Get("http://" + addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "http://"+addr, nil)
+	_, deadline := http.DefaultClient.Do(req)
+
+	dns := &url.Error{Op: "Post", URL: "https://api.example.invalid/call", Err: &net.OpError{
+		Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "api.example.invalid", IsNotFound: true},
+	}}
+
+	for _, tc := range []struct {
+		name string
+		err  error
+		kind string
+	}{
+		{"dns failure", dns, ErrorNetwork},
+		{"connection refused", refused, ErrorNetwork},
+		{"context deadline", deadline, ErrorTimeout},
+		{"wrapped deadline", fmt.Errorf("call: %w", context.DeadlineExceeded), ErrorTimeout},
+		{"tool timeout", &TimeoutError{After: time.Second, Err: context.DeadlineExceeded}, ErrorTimeout},
+		{"auth", fmt.Errorf("call: %w", &client.AuthError{Err: errors.New("no tokens")}), ErrorAuth},
+		{"too large", &client.ResponseTooLargeError{Limit: 1 << 20}, ErrorServer},
+		{"other", errors.New("connection reset by peer"), ErrorNetwork},
+	} {
+		if tc.err == nil {
+			t.Fatalf("%s: no error to classify", tc.name)
+		}
+		if got := classifyCallError(tc.err); got != tc.kind {
+			t.Errorf("%s (%v): got %q, want %q", tc.name, tc.err, got, tc.kind)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package proxy
+
+import "sync"
+
+// errorRun tracks identical errors logged back to back. While the backend
+// is down every call fails the same way, and the activity log shows the run
+// as one entry with a count rather than hundreds of copies.
+type errorRun struct {
+	mu    sync.Mutex
+	tool  string
+	err   string
+	count int
+}
+
+// note records a log entry and returns how many identical errors in a row
+// it makes, or 0 if it isn't an error. Entries of requests still in flight
+// don't interrupt a run; any other outcome ends it.
+func (r *errorRun) note(entry LogEntry) int {
+	if entry.InFlight() {
+		return 0
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if entry.Status != "error" {
+		r.count = 0
+		return 0
+	}
+	if r.count > 0 && entry.Tool == r.tool && entry.Error == r.err {
+		r.count++
+	} else {
+		r.tool, r.err, r.count = entry.Tool, entry.Error, 1
+	}
+	return r.count
+}
+
+// InFlight reports whether the entry is an update on a request that hasn't
+// finished yet.
+func (e LogEntry) InFlight() bool {
+	switch e.Status {
+	case "pending", StatusStreaming, StatusAwaitingConfirmation:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package proxy
+
+import "testing"
+
+func TestErrorRun(t *testing.T) {
+	var r errorRun
+	down := "upstream request failed: dial tcp: connection refused"
+	for i, tc := range []struct {
+		entry LogEntry
+		want  int
+	}{
+		{LogEntry{Tool: "get_token_info", Status: "error", Error: down}, 1},
+		{LogEntry{Tool: "get_token_info", Status: "error", Error: down}, 2},
+		// Requests still in flight don't interrupt the run.
+		{LogEntry{Tool: "get_portfolio", Status: "pending"}, 0},
+		{LogEntry{Tool: "stream_prices", Status: StatusStreaming}, 0},
+		{LogEntry{Tool: "get_token_info", Status: "error", Error: down}, 3},
+		// Another tool or another error starts a new run.
+		{LogEntry{Tool: "get_portfolio", Status: "error", Error: down}, 1},
+		{LogEntry{Tool: "get_portfolio", Status: "error", Error: "timeout"}, 1},
+		{LogEntry{Tool: "get_token_info", Status: "error", Error: down}, 1},
+		{LogEntry{Tool: "get_token_info", Status: "error", Error: down}, 2},
+		// Successes are never folded, and end the run.
+		{LogEntry{Tool: "get_token_info", Status: "success"}, 0},
+		{LogEntry{Tool: "get_token_info", Status: "success"}, 0},
+		{LogEntry{Tool: "get_token_info", Status: "error", Error: down}, 1},
+		{LogEntry{Tool: "get_token_info", Status: StatusRateLimited, Error: down}, 0},
+		{LogEntry{Tool: "get_token_info", Status: "error", Error: down}, 1},
+	} {
+		if got := r.note(tc.entry); got != tc.want {
+			t.Errorf("entry %d (%s %s %q): %d, want %d", i, tc.entry.Tool, tc.entry.Status, tc.entry.Error, got, tc.want)
+		}
+	}
+}
+
+// The proxy marks repeats on the entries it sends, so headless logs see
+// them too.
+func TestSendLogRepeat(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+	for i, want := range []int{0, 2, 3} {
+		s.sendLog(LogEntry{ID: "req", Tool: "get_token_info", Status: "error", Error: "boom"})
+		if got := (<-s.LogChannel()).Repeat; got != want {
+			t.Errorf("entry %d: Repeat = %d, want %d", i, got, want)
+		}
+	}
+}
@@ -1,31 +1,25 @@
 package proxy
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 
-	"github.com/tradeboba/boba-cli/internal/auth"
+	"github.com/tradeboba/boba-cli/internal/client"
 	"github.com/tradeboba/boba-cli/internal/config"
 	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/journal"
 	"github.com/tradeboba/boba-cli/internal/logger"
+	"github.com/tradeboba/boba-cli/internal/tokencache"
 )
 
-// noRedirectClient returns an HTTP client that refuses to follow redirects,
-// preventing Authorization headers from being forwarded to unintended hosts.
-func noRedirectClient(timeout time.Duration) *http.Client {
-	return &http.Client{
-		Timeout: timeout,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return errors.New("redirects are not followed for requests carrying credentials")
-		},
-	}
-}
-
 // toolDescriptions maps tool names to human-friendly status strings shown in
 // the TUI while a request is in flight.
 var toolDescriptions = map[string]string{
@@ -87,60 +81,65 @@ func (s *ProxyServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]any{
+	health := map[string]any{
 		"status":   "ok",
 		"agent":    agentName,
 		"agentId":  agentID,
 		"requests": s.getRequestCount(),
-	})
+		"uptime":   int64(time.Since(s.metrics.started).Seconds()),
+		// Every other route requires the session token.
+		"authEnforced": s.sessionToken != "",
+		"backend":      "ok",
+	}
+	if offline, since := s.BackendOffline(); offline {
+		health["backend"] = "offline"
+		health["offlineSince"] = since.Format(time.RFC3339)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(health)
+}
+
+// handleReload re-reads the config file so credentials and settings changed
+// by another boba process (e.g. `boba login`) take effect without a restart.
+func (s *ProxyServer) handleReload(w http.ResponseWriter, r *http.Request) {
+	config.Reload()
+	logger.Info("config reloaded")
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // handleTools proxies the tool-list request to the MCP backend and returns the
-// response as-is. The agent's wallet addresses and sub-org are forwarded as
-// headers so the backend can filter the tool set.
+// response, less any tools the tool policy or --strict-tools blocks. The
+// agent's wallet addresses and sub-org are forwarded as headers so the
+// backend can filter the tool set.
 func (s *ProxyServer) handleTools(w http.ResponseWriter, r *http.Request) {
-	tokens, err := auth.EnsureAuthenticated()
-	if err != nil {
+	resp, err := s.backend.ListTools(r.Context())
+	s.health.observe(err)
+	var upstream *client.UpstreamError
+	if err != nil && !errors.As(err, &upstream) {
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("authentication failed: %v", err)})
+		w.WriteHeader(failureStatus(err))
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
 	}
 
-	client := noRedirectClient(30 * time.Second)
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/tools", config.GetMCPURL()), nil)
-	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("failed to create request: %v", err)})
-		return
+	s.recordManifest(resp.Status, resp.Body)
+	body := resp.Body
+	if resp.Status == http.StatusOK {
+		body = filterUnpinned(filterToolList(body, config.GetToolPolicy()), s.pinned)
 	}
-
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokens.AccessToken))
-	req.Header.Set("X-Agent-EVM-Address", tokens.EVMAddress)
-	req.Header.Set("X-Agent-Solana-Address", tokens.SolanaAddress)
-	req.Header.Set("X-Agent-Sub-Org-Id", tokens.SubOrganizationID)
-
-	resp, err := client.Do(req)
-	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("upstream request failed: %v", err)})
-		return
-	}
-	defer resp.Body.Close()
-
-	// Forward the response headers and body as-is.
 	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
-	w.WriteHeader(resp.StatusCode)
-	io.Copy(w, resp.Body)
+	w.WriteHeader(resp.Status)
+	w.Write(body)
 }
 
 // handleCall proxies a tool invocation to the MCP backend. It auto-fills
-// parameters, logs the request lifecycle, and retries once on auth errors.
+// parameters and logs the request lifecycle; the backend client retries
+// once on auth errors.
 func (s *ProxyServer) handleCall(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt64(&s.inFlight, 1)
+	defer atomic.AddInt64(&s.inFlight, -1)
+
 	// Limit request body to 1 MB to prevent memory exhaustion.
 	r.Body = http.MaxBytesReader(w, r.Body, 1<<20)
 
@@ -152,6 +151,14 @@ func (s *ProxyServer) handleCall(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Every log entry for this call carries the same ID so the TUI can show
+	// its lifecycle as one row.
+	id := s.nextRequestID()
+	// reqID ties the call to the backend's logs. It is sent upstream and
+	// back to the caller, and replaced by the backend's own if it has one.
+	reqID := client.NewRequestID()
+	w.Header().Set(client.RequestIDHeader, reqID)
+
 	// Normalize: merge tool/args into name/arguments
 	toolName := req.toolName()
 	args := req.toolArgs()
@@ -159,6 +166,59 @@ func (s *ProxyServer) handleCall(w http.ResponseWriter, r *http.Request) {
 		args = make(map[string]any)
 	}
 
+	// Tools the user has blocked never reach the backend.
+	policy := config.GetToolPolicy()
+	if allowed, why := ToolAllowed(policy, toolName); !allowed {
+		s.refuseBlocked(w, id, toolName, why, policy)
+		return
+	}
+	if s.pinned != nil && !s.pinned.Has(toolName) {
+		s.refuseUnpinned(w, id, toolName)
+		return
+	}
+
+	// Turn away calls that come too fast before they count against the
+	// budget or reach the backend.
+	if wait, reason := s.limiter.enter(toolName, time.Now()); reason != "" {
+		s.refuseRateLimited(w, id, toolName, wait, reason)
+		return
+	}
+	defer s.limiter.leave()
+
+	// A cacheable call can ask to skip the cache; the flag isn't forwarded.
+	fresh := false
+	if _, cacheable := cacheTTLs[toolName]; cacheable {
+		fresh = takeFresh(args)
+	}
+
+	// Record the outcome in the metrics once the call is answered. start is
+	// reset after a confirmation wait so only the call itself is timed.
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	w = rec
+	start := time.Now()
+	injected := false
+	cached := false
+	errKind := ""
+	defer func() {
+		if cached {
+			s.metrics.recordCacheHit(toolName)
+			return
+		}
+		failed := (rec.status >= 400 || errKind != "") && !injected
+		s.metrics.recordCall(toolName, time.Since(start), failed, errKind)
+	}()
+
+	// Count the call against the client's budget before doing any work.
+	used, allowed := s.budget.take(budgetClient(r))
+	w.Header().Set(BudgetCountHeader, s.budget.header(used))
+	if !allowed {
+		s.refuseOverBudget(w, id, toolName, used)
+		return
+	}
+	if note := s.budget.note(used); note != "" {
+		w.Header().Set(BudgetNoteHeader, note)
+	}
+
 	// Determine a friendly description for the log entry.
 	desc := toolDescriptions[toolName]
 	if desc == "" {
@@ -167,98 +227,183 @@ func (s *ProxyServer) handleCall(w http.ResponseWriter, r *http.Request) {
 
 	// Log a pending entry so the TUI can show progress immediately.
 	s.sendLog(LogEntry{
+		ID:      id,
 		Tool:    toolName,
 		Status:  "pending",
 		Preview: desc,
+		Args:    maps.Clone(args), // autofill edits args in place below
 	})
 
-	start := time.Now()
-
 	// Authenticate and auto-fill parameters.
-	tokens, err := auth.EnsureAuthenticated()
+	tokens, err := s.backend.Token()
 	if err != nil {
 		duration := time.Since(start)
-		errMsg := fmt.Sprintf("authentication failed: %v", err)
+		errMsg := err.Error()
 		s.sendLog(LogEntry{
-			Tool:     toolName,
-			Status:   "error",
-			Duration: duration,
-			Error:    errMsg,
+			ID:        id,
+			Tool:      toolName,
+			Status:    "error",
+			Duration:  duration,
+			Error:     errMsg,
+			RequestID: reqID,
 		})
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]string{"error": errMsg})
+		json.NewEncoder(w).Encode(map[string]string{"error": errMsg, "requestId": reqID})
 		return
 	}
 
-	AutoFillParams(toolName, args, tokens)
+	mods := AutoFillParams(toolName, args, tokens)
+	if len(mods) > 0 {
+		logger.Debug("autofilled tool arguments", "tool", toolName, "injected", Injected(mods))
+	}
+	w.Header().Set(ModifiedHeader, strconv.Itoa(len(mods)))
 
-	// Forward the call to the MCP backend.
-	respBody, statusCode, err := s.doMCPCall(toolName, args, tokens)
-	if err != nil {
+	// Check the arguments against the tool's input schema once autofill
+	// has filled what it can, so the agent hears exactly what to fix.
+	if schema := s.schemaFor(toolName); schema != nil {
+		if violations := validateArgs(schema, args); len(violations) > 0 {
+			s.refuseInvalidArgs(w, id, toolName, reqID, violations, mods)
+			return
+		}
+	}
+
+	// Check that a trade's wallet can pay the fees before it is held or
+	// forwarded. The warning goes along with the call unless strict.
+	warning := s.preflight(r.Context(), toolName, args)
+	if warning != "" {
+		if s.gasStrict {
+			s.refuseLowGas(w, id, toolName, warning, mods)
+			return
+		}
+		w.Header().Set(PreflightHeader, warning)
+	}
+
+	// Serve repeated read-only calls from the cache, keyed by the arguments
+	// as forwarded. Hits are left out of the latency metrics.
+	cacheID, cacheable := cacheKey(toolName, args)
+	if cacheable && s.cache != nil {
+		if body, ok := s.cache.get(cacheID, time.Now()); ok && !fresh {
+			cached = true
+			s.serveCached(w, id, toolName, body, mods, time.Since(start))
+			return
+		}
+	}
+
+	// In confirmation mode, hold write calls until the user approves them.
+	if s.confirm != nil && NeedsConfirmation(toolName) {
+		if !s.awaitConfirmation(w, r, id, toolName, args, mods, warning, start) {
+			return
+		}
+		start = time.Now() // time the upstream call, not the wait
+	}
+
+	// Forward the call to the MCP backend. A trade that reached the backend
+	// is seen through even if the agent hangs up, so its outcome is logged.
+	ctx := client.WithRequestID(r.Context(), reqID)
+	if NeedsConfirmation(toolName) {
+		ctx = context.WithoutCancel(ctx)
+	}
+	resp, err := s.doMCPCall(ctx, toolName, args)
+	reqID = resp.RequestID(reqID)
+	w.Header().Set(client.RequestIDHeader, reqID)
+	var upstream *client.UpstreamError
+	if err != nil && !errors.As(err, &upstream) {
+		injected = injectedFailure(err, nil)
 		duration := time.Since(start)
-		errMsg := fmt.Sprintf("upstream request failed: %v", err)
+		status := failureStatus(err)
+		errMsg := err.Error()
+		errKind = classifyCallError(err)
 		s.sendLog(LogEntry{
-			Tool:     toolName,
-			Status:   "error",
-			Duration: duration,
-			Error:    errMsg,
+			ID:            id,
+			Tool:          toolName,
+			Status:        "error",
+			Duration:      duration,
+			Error:         errMsg,
+			ErrorKind:     errKind,
+			Modifications: mods,
+			Warning:       warning,
+			RequestID:     reqID,
 		})
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": errMsg})
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": errMsg, "requestId": reqID})
 		return
 	}
-
-	// Retry once on auth errors (401 / 403).
-	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
-		logger.Debug("received auth error from upstream, re-authenticating", "status", statusCode)
-		newTokens, authErr := auth.Authenticate()
-		if authErr == nil {
-			tokens = newTokens
-			AutoFillParams(toolName, args, tokens)
-			respBody, statusCode, err = s.doMCPCall(toolName, args, tokens)
-			if err != nil {
-				duration := time.Since(start)
-				errMsg := fmt.Sprintf("upstream request failed after retry: %v", err)
-				s.sendLog(LogEntry{
-					Tool:     toolName,
-					Status:   "error",
-					Duration: duration,
-					Error:    errMsg,
-				})
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusBadGateway)
-				json.NewEncoder(w).Encode(map[string]string{"error": errMsg})
-				return
-			}
-		}
+	respBody, statusCode := resp.Body, resp.Status
+	injected = injectedFailure(nil, respBody)
+	if resp.Tokens != nil {
+		tokens = resp.Tokens
 	}
 
 	duration := time.Since(start)
 	s.incrementRequests()
 
-	// Parse the response for logging.
+	// Parse the response for logging. A 2xx can still be the backend
+	// turning the call down.
 	var responseData any
 	_ = json.Unmarshal(respBody, &responseData)
+	errKind, errMsg := classifyResponse(statusCode, respBody)
+	if errMsg == "" {
+		errMsg = string(respBody)
+	}
+
+	// Remember token symbols so addresses elsewhere can be labeled.
+	if statusCode < 400 && tokencache.Default.LearnFrom(responseData) {
+		if err := tokencache.Default.Save(); err != nil {
+			logger.Debug("failed to save token cache", "error", err)
+		}
+	}
 
 	preview := formatter.FormatToolPreview(toolName, responseData)
 	formatted := formatter.FormatToolResult(toolName, responseData)
 
-	if statusCode >= 200 && statusCode < 300 {
+	if statusCode >= 200 && statusCode < 300 && errKind == "" {
+		var txHash string
+		if data, ok := responseData.(map[string]any); ok && NeedsConfirmation(toolName) {
+			txHash = txHashOf(data)
+		}
 		s.sendLog(LogEntry{
+			ID:              id,
 			Tool:            toolName,
 			Status:          "success",
 			Duration:        duration,
 			Preview:         preview,
 			FormattedOutput: formatted,
+			Modifications:   mods,
+			Warning:         warning,
+			RequestID:       reqID,
+			TxHash:          txHash,
 		})
+		s.metrics.recordSuccess(toolName, responseData)
+		if journal.TradeTools[toolName] {
+			recordTrade(toolName, args, responseData, tokens)
+		}
+		if s.cache != nil {
+			switch {
+			case cacheable:
+				s.cache.put(cacheID, toolName, respBody, time.Now())
+			case NeedsConfirmation(toolName):
+				s.cache.clear() // balances and orders just changed
+			}
+		}
+		if NeedsConfirmation(toolName) {
+			s.natives.forget()
+		}
+		if toolName == "get_portfolio" {
+			s.sawPortfolio(args, respBody)
+		}
 	} else {
 		s.sendLog(LogEntry{
-			Tool:     toolName,
-			Status:   "error",
-			Duration: duration,
-			Error:    string(respBody),
+			ID:            id,
+			Tool:          toolName,
+			Status:        "error",
+			Duration:      duration,
+			Error:         errMsg,
+			ErrorKind:     errKind,
+			Modifications: mods,
+			Warning:       warning,
+			RequestID:     reqID,
 		})
 	}
 
@@ -267,83 +412,228 @@ func (s *ProxyServer) handleCall(w http.ResponseWriter, r *http.Request) {
 	w.Write(respBody)
 }
 
-// doMCPCall sends the tool call request to the MCP backend and returns the raw
-// response body, HTTP status code, and any transport error.
-// Uses "tool"/"args" field names matching the TS proxy format that the MCP backend expects.
-func (s *ProxyServer) doMCPCall(tool string, args map[string]any, tokens *config.AuthTokens) ([]byte, int, error) {
-	// Send as { "tool": ..., "args": ... } to match what the MCP backend expects
-	payload := map[string]any{
-		"tool": tool,
-		"args": args,
-	}
-	bodyBytes, err := json.Marshal(payload)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to marshal request: %w", err)
-	}
+// serveCached answers a call from the response cache and logs it like a
+// backend answer, marked as cached.
+func (s *ProxyServer) serveCached(w http.ResponseWriter, id, toolName string, body []byte, mods []Modification, duration time.Duration) {
+	s.incrementRequests()
+	var responseData any
+	_ = json.Unmarshal(body, &responseData)
+	s.sendLog(LogEntry{
+		ID:              id,
+		Tool:            toolName,
+		Status:          "success",
+		Duration:        duration,
+		Preview:         formatter.FormatToolPreview(toolName, responseData),
+		FormattedOutput: formatter.FormatToolResult(toolName, responseData),
+		Modifications:   mods,
+		Cached:          true,
+	})
+	w.Header().Set(CachedHeader, "1")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+}
 
-	client := noRedirectClient(60 * time.Second)
+// awaitConfirmation holds a write call until the user decides on it. When
+// the call is not approved it logs why, answers the caller with a
+// structured error and returns false.
+func (s *ProxyServer) awaitConfirmation(w http.ResponseWriter, r *http.Request, id, toolName string, args map[string]any, mods []Modification, warning string, start time.Time) bool {
+	// Queue the call before logging it, so the dashboard finds it held.
+	held := s.confirm.hold(id, toolName, args)
+	s.sendLog(LogEntry{
+		ID:            id,
+		Tool:          toolName,
+		Status:        StatusAwaitingConfirmation,
+		Preview:       "Waiting for confirmation...",
+		Modifications: mods,
+		Warning:       warning,
+	})
 
-	httpReq, err := http.NewRequest("POST", fmt.Sprintf("%s/call", config.GetMCPURL()), bytes.NewReader(bodyBytes))
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	var reason, msg string
+	switch s.confirm.wait(held, r.Context().Done()) {
+	case confirmApproved:
+		s.sendLog(LogEntry{
+			ID:      id,
+			Tool:    toolName,
+			Status:  "pending",
+			Preview: "Confirmed, executing...",
+			Warning: warning,
+		})
+		return true
+	case confirmDenied:
+		reason, msg = "denied", "the user declined this call"
+	case confirmTimedOut:
+		reason, msg = "timeout", fmt.Sprintf("the call was not confirmed within %s", s.confirm.timeout)
+	case confirmAbandoned:
+		reason, msg = "abandoned", "the caller disconnected before the call was confirmed"
+	default:
+		reason, msg = "unavailable", "trade confirmation is on but no dashboard is open to confirm calls"
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokens.AccessToken))
-	httpReq.Header.Set("X-Agent-EVM-Address", tokens.EVMAddress)
-	httpReq.Header.Set("X-Agent-Solana-Address", tokens.SolanaAddress)
-	httpReq.Header.Set("X-Agent-Sub-Org-Id", tokens.SubOrganizationID)
+	s.sendLog(LogEntry{
+		ID:            id,
+		Tool:          toolName,
+		Status:        "error",
+		Duration:      time.Since(start),
+		Error:         "not executed: " + msg,
+		Modifications: mods,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error":   "confirmation_required",
+		"reason":  reason,
+		"message": fmt.Sprintf("%s was not executed: %s. Do not retry it unless the user asks you to.", toolName, msg),
+	})
+	return false
+}
 
-	resp, err := client.Do(httpReq)
-	if err != nil {
-		return nil, 0, err
+// recordTrade appends an executed trade to the local journal so it can be
+// checked on-chain later with `boba verify-trade`.
+func recordTrade(toolName string, args map[string]any, responseData any, tokens *config.AuthTokens) {
+	resp, ok := responseData.(map[string]any)
+	if !ok {
+		return
 	}
-	defer resp.Body.Close()
+	entry, ok := journal.FromResult(toolName, args, resp, tokens)
+	if !ok {
+		return
+	}
+	if err := journal.Append(entry); err != nil {
+		logger.Debug("failed to journal trade", "tx", entry.TxHash, "error", err)
+	}
+}
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+// doMCPCall sends the tool call request to the MCP backend within the
+// timeout of the tool's category. A read-only call that couldn't reach the
+// backend is retried once, and a 429 once after the delay the backend asks
+// for. Write calls are recorded in the audit trail when it is on.
+func (s *ProxyServer) doMCPCall(parent context.Context, tool string, args map[string]any) (*client.Response, error) {
+	timeout := CallTimeout(tool)
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	resp, err := s.postCall(ctx, tool, args)
+	if retryableFailure(ctx, tool, err) {
+		logger.Debug("upstream call failed, retrying", "tool", tool, "error", err)
+		select {
+		case <-time.After(retryBackoff()):
+			resp, err = s.postCall(ctx, tool, args)
+		case <-ctx.Done():
+		}
+	}
+	if resp != nil && resp.Status == http.StatusTooManyRequests {
+		wait := upstreamRetryDelay(resp.Header)
+		logger.Debug("upstream rate limited the call, retrying", "tool", tool, "wait", wait)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return resp, err
+		}
+		resp, err = s.postCall(ctx, tool, args)
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && parent.Err() == nil {
+		err = &TimeoutError{After: timeout, Err: err}
+	}
+	var tokens *config.AuthTokens
+	if resp != nil {
+		tokens = resp.Tokens
+	}
+	s.audit.record(tool, args, resp.RequestID(client.RequestID(ctx)), resp, err, tokens)
+	return resp, err
+}
+
+// failureStatus is the status a proxy answers with when the backend
+// request failed before getting an answer.
+func failureStatus(err error) int {
+	var authErr *client.AuthError
+	if errors.As(err, &authErr) {
+		return http.StatusUnauthorized
+	}
+	var timeout *TimeoutError
+	if errors.As(err, &timeout) {
+		return http.StatusGatewayTimeout
 	}
+	return http.StatusBadGateway
+}
 
-	return respBody, resp.StatusCode, nil
+// postCall makes one attempt at a tool call, unless chaos mode answers it.
+func (s *ProxyServer) postCall(ctx context.Context, tool string, args map[string]any) (*client.Response, error) {
+	if s.chaos != nil {
+		if status, body, err := s.chaos.inject(); err != nil {
+			return nil, &client.TransportError{Err: err}
+		} else if status != 0 {
+			resp := &client.Response{Status: status, Body: body}
+			if !resp.OK() {
+				return resp, &client.UpstreamError{Status: status, Body: string(body)}
+			}
+			return resp, nil
+		}
+	}
+	resp, err := s.backend.CallTool(ctx, tool, args)
+	s.health.observe(err)
+	return resp, err
 }
 
 // handleStream proxies a Server-Sent Events stream from the MCP backend to the
 // client, flushing each chunk as it arrives.
 func (s *ProxyServer) handleStream(w http.ResponseWriter, r *http.Request) {
-	tokens, err := auth.EnsureAuthenticated()
-	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("authentication failed: %v", err)})
+	// The stream shows in the activity log as one row that counts events
+	// as they pass through.
+	id := s.nextRequestID()
+	toolName := r.URL.Query().Get("tool")
+	if toolName == "" {
+		toolName = "stream"
+	}
+	desc := toolDescriptions[toolName]
+	if desc == "" {
+		desc = "Streaming..."
+	}
+	policy := config.GetToolPolicy()
+	if allowed, why := ToolAllowed(policy, toolName); !allowed {
+		s.refuseBlocked(w, id, toolName, why, policy)
 		return
 	}
-
-	client := &http.Client{
-		// No timeout — SSE streams are long-lived.
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return errors.New("redirects are not followed for requests carrying credentials")
-		},
+	if s.pinned != nil && !s.pinned.Has(toolName) {
+		s.refuseUnpinned(w, id, toolName)
+		return
 	}
-
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/stream", config.GetMCPURL()), nil)
-	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("failed to create request: %v", err)})
+	// A stream takes a token from its tool's bucket and holds an in-flight
+	// slot until it ends, like a call.
+	if wait, reason := s.limiter.enter(toolName, time.Now()); reason != "" {
+		s.refuseRateLimited(w, id, toolName, wait, reason)
 		return
 	}
+	defer s.limiter.leave()
+	// Opening a stream counts against the client's budget.
+	used, allowed := s.budget.take(budgetClient(r))
+	w.Header().Set(BudgetCountHeader, s.budget.header(used))
+	if !allowed {
+		s.refuseOverBudget(w, id, toolName, used)
+		return
+	}
+	if note := s.budget.note(used); note != "" {
+		w.Header().Set(BudgetNoteHeader, note)
+	}
+	s.sendLog(LogEntry{ID: id, Tool: toolName, Status: "pending", Preview: desc})
+	start := time.Now()
+	fail := func(status int, errMsg string) {
+		s.sendLog(LogEntry{
+			ID:       id,
+			Tool:     toolName,
+			Status:   "error",
+			Duration: time.Since(start),
+			Error:    errMsg,
+		})
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": errMsg})
+	}
 
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", tokens.AccessToken))
-	req.Header.Set("X-Agent-EVM-Address", tokens.EVMAddress)
-	req.Header.Set("X-Agent-Solana-Address", tokens.SolanaAddress)
-	req.Header.Set("X-Agent-Sub-Org-Id", tokens.SubOrganizationID)
-
-	resp, err := client.Do(req)
+	resp, err := s.backend.Stream(r.Context(), r.URL.RawQuery)
+	s.health.observe(err)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("upstream request failed: %v", err)})
+		fail(failureStatus(err), err.Error())
 		return
 	}
 	defer resp.Body.Close()
@@ -354,10 +644,24 @@ func (s *ProxyServer) handleStream(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Connection", "keep-alive")
 	w.WriteHeader(resp.StatusCode)
 
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		io.Copy(w, resp.Body)
+		s.sendLog(LogEntry{
+			ID:       id,
+			Tool:     toolName,
+			Status:   "error",
+			Duration: time.Since(start),
+			Error:    fmt.Sprintf("upstream returned status %d", resp.StatusCode),
+		})
+		return
+	}
+
+	tap := newStreamTap(s, id, toolName)
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		// Fallback: copy the entire body at once if flushing is not supported.
-		io.Copy(w, resp.Body)
+		io.Copy(w, io.TeeReader(resp.Body, tapWriter{tap}))
+		tap.finish("")
 		return
 	}
 
@@ -365,15 +669,22 @@ func (s *ProxyServer) handleStream(w http.ResponseWriter, r *http.Request) {
 	for {
 		n, readErr := resp.Body.Read(buf)
 		if n > 0 {
+			tap.feed(buf[:n])
 			if _, writeErr := w.Write(buf[:n]); writeErr != nil {
 				logger.Debug("stream write error", "error", writeErr)
+				tap.finish("")
 				return
 			}
 			flusher.Flush()
 		}
 		if readErr != nil {
-			if readErr != io.EOF {
+			switch {
+			case readErr == io.EOF, r.Context().Err() != nil:
+				// Upstream ended the stream, or the client went away.
+				tap.finish("")
+			default:
 				logger.Debug("stream read error", "error", readErr)
+				tap.finish(fmt.Sprintf("stream failed: %v", readErr))
 			}
 			return
 		}
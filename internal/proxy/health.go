@@ -0,0 +1,112 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/client"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/logger"
+)
+
+// degradedAfter is how many transport failures in a row mark the backend
+// offline. A single dropped connection isn't enough.
+const degradedAfter = 3
+
+// probeInterval is how often the backend is probed while it is offline.
+const probeInterval = 15 * time.Second
+
+// backendHealth tracks whether the MCP backend is reachable, judging by the
+// transport errors of recent calls. HTTP error statuses don't count: the
+// backend answered.
+type backendHealth struct {
+	mu       sync.Mutex
+	failures int
+	since    time.Time // when the backend went offline; zero while healthy
+}
+
+// record notes the outcome of a round trip to the backend: nil if it
+// answered, the transport error if not.
+func (h *backendHealth) record(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err == nil {
+		if !h.since.IsZero() {
+			logger.Info("backend reachable again", "offline", time.Since(h.since).Round(time.Second))
+		}
+		h.failures = 0
+		h.since = time.Time{}
+		return
+	}
+	h.failures++
+	if h.failures == degradedAfter {
+		h.since = time.Now()
+		logger.Warn("backend unreachable, entering degraded mode", "error", err)
+	}
+}
+
+// observe records the outcome of a backend request. Only transport errors
+// count as failures: an error status is still an answer, and a cancelled
+// request or missing credentials say nothing about the backend.
+func (h *backendHealth) observe(err error) {
+	var transport *client.TransportError
+	var upstream *client.UpstreamError
+	switch {
+	case err == nil, errors.As(err, &upstream):
+		h.record(nil)
+	case errors.Is(err, context.Canceled):
+	case errors.As(err, &transport):
+		h.record(err)
+	}
+}
+
+// state reports whether the backend is offline, and since when.
+func (h *backendHealth) state() (bool, time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return !h.since.IsZero(), h.since
+}
+
+// BackendOffline reports whether recent calls couldn't reach the MCP
+// backend, and when that started.
+func (s *ProxyServer) BackendOffline() (bool, time.Time) {
+	return s.health.state()
+}
+
+// probeBackend runs until stop is closed. While the backend is offline it
+// checks every probeInterval whether it answers again, so recovery is
+// noticed even when nothing else is calling it.
+func (s *ProxyServer) probeBackend(stop <-chan struct{}) {
+	ticker := time.NewTicker(probeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		if offline, _ := s.health.state(); offline {
+			s.health.record(pingBackend())
+		}
+	}
+}
+
+// pingBackend makes a cheap unauthenticated request to the backend. Any
+// HTTP response, whatever its status, shows it is reachable.
+func pingBackend() error {
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(config.GetMCPURL() + "/tools")
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
@@ -0,0 +1,315 @@
+package proxy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/logger"
+)
+
+// StatusNotice marks something the user should know about that isn't a
+// request, such as the backend's tools changing. Like an alert, it stands
+// alone.
+const StatusNotice = "notice"
+
+// ManifestLogTool is the tool name tool manifest notices are logged under.
+const ManifestLogTool = "tool_manifest"
+
+// Manifest is the set of tools the backend offered, each with a hash of its
+// input schema so changed parameters show up as well as new tools.
+type Manifest struct {
+	Fetched time.Time      `json:"fetched"`
+	Tools   []ManifestTool `json:"tools"`
+}
+
+// ManifestTool is one tool of a manifest.
+type ManifestTool struct {
+	Name       string `json:"name"`
+	SchemaHash string `json:"schemaHash"`
+}
+
+// ParseManifest reads a tools/list response into a manifest, sorted by tool
+// name.
+func ParseManifest(body []byte) (*Manifest, error) {
+	var list struct {
+		Tools []struct {
+			Name        string          `json:"name"`
+			InputSchema json.RawMessage `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("invalid tool manifest: %w", err)
+	}
+	m := &Manifest{Fetched: time.Now().UTC(), Tools: []ManifestTool{}}
+	for _, t := range list.Tools {
+		if t.Name == "" {
+			continue
+		}
+		m.Tools = append(m.Tools, ManifestTool{Name: t.Name, SchemaHash: schemaHash(t.InputSchema)})
+	}
+	sort.Slice(m.Tools, func(i, j int) bool { return m.Tools[i].Name < m.Tools[j].Name })
+	return m, nil
+}
+
+// schemaHash hashes an input schema. The schema is re-encoded first so key
+// order and whitespace don't count as changes.
+func schemaHash(schema json.RawMessage) string {
+	canonical := bytes.TrimSpace(schema)
+	var v any
+	if json.Unmarshal(schema, &v) == nil {
+		if b, err := json.Marshal(v); err == nil {
+			canonical = b
+		}
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// Has reports whether the manifest offers tool.
+func (m *Manifest) Has(tool string) bool {
+	_, found := slices.BinarySearchFunc(m.Tools, tool, func(t ManifestTool, name string) int {
+		return strings.Compare(t.Name, name)
+	})
+	return found
+}
+
+// Names returns the manifest's tool names.
+func (m *Manifest) Names() []string {
+	names := make([]string, len(m.Tools))
+	for i, t := range m.Tools {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// ManifestDiff is how one manifest differs from an earlier one.
+type ManifestDiff struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"` // same name, different input schema
+}
+
+// DiffManifests compares manifest next against the earlier prev.
+func DiffManifests(prev, next *Manifest) ManifestDiff {
+	before := make(map[string]string, len(prev.Tools))
+	for _, t := range prev.Tools {
+		before[t.Name] = t.SchemaHash
+	}
+	d := ManifestDiff{Added: []string{}, Removed: []string{}, Changed: []string{}}
+	for _, t := range next.Tools {
+		hash, ok := before[t.Name]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, t.Name)
+		case hash != t.SchemaHash:
+			d.Changed = append(d.Changed, t.Name)
+		}
+		delete(before, t.Name)
+	}
+	for name := range before {
+		d.Removed = append(d.Removed, name)
+	}
+	sort.Strings(d.Removed)
+	return d
+}
+
+// Empty reports whether the manifests were the same.
+func (d ManifestDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// String summarizes the diff in one line, e.g. "3 tools added
+// (get_bridge_quote, ...), 1 removed".
+func (d ManifestDiff) String() string {
+	var parts []string
+	for _, g := range []struct {
+		verb  string
+		names []string
+	}{{"added", d.Added}, {"removed", d.Removed}, {"changed", d.Changed}} {
+		if len(g.names) == 0 {
+			continue
+		}
+		part := fmt.Sprintf("%d %s", len(g.names), g.verb)
+		if len(parts) == 0 {
+			noun := "tools"
+			if len(g.names) == 1 {
+				noun = "tool"
+			}
+			part = fmt.Sprintf("%d %s %s (%s)", len(g.names), noun, g.verb, abbreviate(g.names, 2))
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// abbreviate lists up to n names, with "..." standing in for the rest.
+func abbreviate(names []string, n int) string {
+	if len(names) <= n {
+		return strings.Join(names, ", ")
+	}
+	return strings.Join(names[:n], ", ") + ", ..."
+}
+
+// ManifestSnapshotPath is where the proxy keeps the manifest it last saw.
+// The one before it is kept alongside, so `boba tools diff` can show what
+// the last change was.
+func ManifestSnapshotPath() string {
+	return filepath.Join(config.DataDir(), "tools-snapshot.json")
+}
+
+// PreviousManifestPath is where the snapshot replaced by the last change is
+// kept.
+func PreviousManifestPath() string {
+	return filepath.Join(config.DataDir(), "tools-snapshot.prev.json")
+}
+
+// PinnedManifestPath is where `boba tools pin` records the manifest
+// --strict-tools enforces.
+func PinnedManifestPath() string {
+	return filepath.Join(config.DataDir(), "tools-pinned.json")
+}
+
+// LoadManifest reads a manifest saved at path. It returns nil, nil when
+// there is none.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("invalid tool manifest %s: %w", path, err)
+	}
+	sort.Slice(m.Tools, func(i, j int) bool { return m.Tools[i].Name < m.Tools[j].Name })
+	return &m, nil
+}
+
+// Save writes the manifest to path.
+func (m *Manifest) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return config.WritePrivateFile(path, append(data, '\n'))
+}
+
+// recordManifest snapshots the tool list the backend returned, and its
+// input schemas for checking calls, and when it differs from the last one
+// seen, logs a notice of what changed. body is
+// the unfiltered response, so the tool policy doesn't show up as tools
+// coming and going.
+func (s *ProxyServer) recordManifest(status int, body []byte) {
+	if status != http.StatusOK {
+		return
+	}
+	if s.schemas != nil {
+		s.schemas.record(body)
+	}
+	next, err := ParseManifest(body)
+	if err != nil {
+		return
+	}
+
+	s.manifestMu.Lock()
+	defer s.manifestMu.Unlock()
+	prev := s.manifest
+	if prev == nil {
+		// First fetch of the session: compare with the last session's.
+		if prev, err = LoadManifest(ManifestSnapshotPath()); err != nil {
+			logger.Warn("could not read tool snapshot", "error", err)
+		}
+	}
+	s.manifest = next
+	if prev == nil {
+		if err := next.Save(ManifestSnapshotPath()); err != nil {
+			logger.Warn("could not save tool snapshot", "error", err)
+		}
+		return
+	}
+	diff := DiffManifests(prev, next)
+	if diff.Empty() {
+		return
+	}
+	if err := prev.Save(PreviousManifestPath()); err != nil {
+		logger.Warn("could not save tool snapshot", "error", err)
+	}
+	if err := next.Save(ManifestSnapshotPath()); err != nil {
+		logger.Warn("could not save tool snapshot", "error", err)
+	}
+	logger.Info("tool manifest changed", "added", len(diff.Added), "removed", len(diff.Removed), "changed", len(diff.Changed))
+	s.sendLog(LogEntry{
+		Tool:    ManifestLogTool,
+		Status:  StatusNotice,
+		Preview: diff.String() + " · boba tools diff",
+	})
+}
+
+// SetStrictTools limits agents to the tools in the pinned manifest: others
+// are left out of /tools and refused by /call. It must be called before
+// Start.
+func (s *ProxyServer) SetStrictTools(pinned *Manifest) {
+	s.pinned = pinned
+}
+
+// refuseUnpinned answers a call to a tool --strict-tools doesn't allow.
+func (s *ProxyServer) refuseUnpinned(w http.ResponseWriter, id, toolName string) {
+	s.sendLog(LogEntry{
+		ID:     id,
+		Tool:   toolName,
+		Status: "error",
+		Error:  "blocked: not in the pinned tool manifest",
+	})
+	w.Header().Set(PolicyHeader, "pinned")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error":   "tool_not_pinned",
+		"policy":  "pinned",
+		"message": fmt.Sprintf("%s is not in the tool manifest the user pinned. Do not retry it; if it is needed, ask the user to review it with 'boba tools diff' and re-pin with 'boba tools pin'.", toolName),
+	})
+}
+
+// filterUnpinned removes the tools absent from the pinned manifest from a
+// tools/list response. Bodies that aren't a tool list are returned
+// unchanged.
+func filterUnpinned(body []byte, pinned *Manifest) []byte {
+	if pinned == nil {
+		return body
+	}
+	var list map[string]any
+	if err := json.Unmarshal(body, &list); err != nil {
+		return body
+	}
+	tools, ok := list["tools"].([]any)
+	if !ok {
+		return body
+	}
+	kept := make([]any, 0, len(tools))
+	for _, t := range tools {
+		tool, _ := t.(map[string]any)
+		if name, _ := tool["name"].(string); pinned.Has(name) {
+			kept = append(kept, t)
+		}
+	}
+	list["tools"] = kept
+	out, err := json.Marshal(list)
+	if err != nil {
+		return body
+	}
+	return out
+}
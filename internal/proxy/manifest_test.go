@@ -0,0 +1,39 @@
+package proxy
+
+import (
+	"net/http"
+	"testing"
+)
+
+// With --strict-tools, a tool missing from the pinned manifest is refused
+// on /call and /stream alike, before it reaches the backend.
+func TestStrictToolsRefusesUnpinned(t *testing.T) {
+	backend := &fakeBackend{}
+	s := newTestServer(t, backend)
+	s.SetStrictTools(&Manifest{Tools: []ManifestTool{{Name: "get_token_info"}, {Name: "stream_prices"}}})
+
+	for _, tc := range []struct {
+		name, method, path, body string
+	}{
+		{"call", "POST", "/call", `{"tool":"execute_swap","args":{}}`},
+		{"stream", "GET", "/stream?tool=execute_swap", ""},
+	} {
+		w := serve(s, tc.method, tc.path, tc.body)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s: status %d, want 403: %s", tc.name, w.Code, w.Body)
+		}
+		if got := w.Header().Get(PolicyHeader); got != "pinned" {
+			t.Errorf("%s: %s = %q, want pinned", tc.name, PolicyHeader, got)
+		}
+	}
+	if n := backend.calls.Load() + backend.streams.Load(); n != 0 {
+		t.Errorf("backend saw %d requests for unpinned tools", n)
+	}
+
+	if w := serve(s, "POST", "/call", `{"tool":"get_token_info","args":{}}`); w.Code != http.StatusOK {
+		t.Errorf("pinned call: status %d: %s", w.Code, w.Body)
+	}
+	if w := serve(s, "GET", "/stream?tool=stream_prices", ""); w.Code != http.StatusOK {
+		t.Errorf("pinned stream: status %d: %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,198 @@
+package proxy
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/auth"
+	"github.com/tradeboba/boba-cli/internal/metrics"
+)
+
+// durationBuckets are the upper bounds, in seconds, of the tool call
+// latency histogram.
+var durationBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// toolStats is what the metrics record about one tool.
+type toolStats struct {
+	calls     int64
+	errors    map[string]int64 // by ErrorKind, "other" for the proxy's own refusals
+	buckets   []int64          // cumulative counts per durationBuckets entry
+	sum       float64
+	cacheHits int64 // calls answered from the response cache, not in calls
+}
+
+// proxyMetrics holds the counters behind /metrics. They are updated as
+// calls complete rather than derived from the log channel, which drops
+// entries when the dashboard falls behind.
+type proxyMetrics struct {
+	started time.Time
+
+	mu     sync.Mutex
+	tools  map[string]*toolStats
+	totals sessionTotals
+
+	lastPortfolioPoll atomic.Int64 // unix seconds
+}
+
+func newProxyMetrics() *proxyMetrics {
+	return &proxyMetrics{started: time.Now(), tools: make(map[string]*toolStats)}
+}
+
+// recordCall counts a finished tool call, and a failed one by the kind of
+// failure. Failures injected by chaos testing pass failed=false so they
+// stay out of the error counts.
+func (m *proxyMetrics) recordCall(tool string, d time.Duration, failed bool, kind string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.tools[tool]
+	if st == nil {
+		st = &toolStats{buckets: make([]int64, len(durationBuckets))}
+		m.tools[tool] = st
+	}
+	st.calls++
+	if failed {
+		if kind == "" {
+			kind = otherErrorKind
+		}
+		if st.errors == nil {
+			st.errors = make(map[string]int64)
+		}
+		st.errors[kind]++
+	}
+	secs := d.Seconds()
+	st.sum += secs
+	for i, le := range durationBuckets {
+		if secs <= le {
+			st.buckets[i]++
+		}
+	}
+}
+
+// otherErrorKind labels the error count of failures without an ErrorKind.
+const otherErrorKind = "other"
+
+// metricErrorKinds are the kind labels of the error counts.
+var metricErrorKinds = append(slices.Clone(ErrorKinds), otherErrorKind)
+
+// errorCount returns the failed calls of every kind.
+func (st *toolStats) errorCount() int64 {
+	var n int64
+	for _, c := range st.errors {
+		n += c
+	}
+	return n
+}
+
+// recordCacheHit counts a call answered from the response cache. It stays
+// out of the call counts and latencies, which describe the backend.
+func (m *proxyMetrics) recordCacheHit(tool string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	st := m.tools[tool]
+	if st == nil {
+		st = &toolStats{buckets: make([]int64, len(durationBuckets))}
+		m.tools[tool] = st
+	}
+	st.cacheHits++
+}
+
+// portfolioPolled records a successful portfolio fetch.
+func (m *proxyMetrics) portfolioPolled() {
+	m.lastPortfolioPoll.Store(time.Now().Unix())
+}
+
+// SetMetricsPublic serves /metrics without the session token, for scrapers
+// that can't send one. While the proxy is exposed to the network this only
+// holds for scrapers on this machine. It must be called before Start.
+func (s *ProxyServer) SetMetricsPublic(public bool) {
+	s.openMetrics = public
+}
+
+func (s *ProxyServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if s.openMetrics && (!s.LANExposed() || fromLoopback(r)) {
+		s.writeMetrics(w, r)
+		return
+	}
+	s.withAuth(s.writeMetrics)(w, r)
+}
+
+// writeMetrics renders every metric in the registry in the Prometheus text
+// exposition format.
+func (s *ProxyServer) writeMetrics(w http.ResponseWriter, r *http.Request) {
+	m := s.metrics
+	m.mu.Lock()
+	names := make([]string, 0, len(m.tools))
+	stats := make(map[string]toolStats, len(m.tools))
+	for name, st := range m.tools {
+		names = append(names, name)
+		cp := *st
+		cp.buckets = append([]int64(nil), st.buckets...)
+		cp.errors = maps.Clone(st.errors)
+		stats[name] = cp
+	}
+	m.mu.Unlock()
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, def := range metrics.Registry {
+		fmt.Fprintf(&b, "# HELP %s %s\n", def.Name, def.Help)
+		fmt.Fprintf(&b, "# TYPE %s %s\n", def.Name, def.Type)
+		switch def.Name {
+		case metrics.ToolCalls:
+			for _, name := range names {
+				fmt.Fprintf(&b, "%s{tool=%q} %d\n", def.Name, name, stats[name].calls)
+			}
+		case metrics.ToolErrors:
+			for _, name := range names {
+				for _, kind := range metricErrorKinds {
+					fmt.Fprintf(&b, "%s{tool=%q,kind=%q} %d\n", def.Name, name, kind, stats[name].errors[kind])
+				}
+			}
+		case metrics.ToolDuration:
+			for _, name := range names {
+				st := stats[name]
+				for i, le := range durationBuckets {
+					fmt.Fprintf(&b, "%s_bucket{tool=%q,le=%q} %d\n", def.Name, name, formatFloat(le), st.buckets[i])
+				}
+				fmt.Fprintf(&b, "%s_bucket{tool=%q,le=\"+Inf\"} %d\n", def.Name, name, st.calls)
+				fmt.Fprintf(&b, "%s_sum{tool=%q} %s\n", def.Name, name, formatFloat(st.sum))
+				fmt.Fprintf(&b, "%s_count{tool=%q} %d\n", def.Name, name, st.calls)
+			}
+		case metrics.CacheHits:
+			for _, name := range names {
+				fmt.Fprintf(&b, "%s{tool=%q} %d\n", def.Name, name, stats[name].cacheHits)
+			}
+		case metrics.AuthFailures:
+			fmt.Fprintf(&b, "%s %d\n", def.Name, auth.Failures.Load())
+		case metrics.AuthRefreshes:
+			fmt.Fprintf(&b, "%s %d\n", def.Name, auth.Refreshes.Load())
+		case metrics.PortfolioPollOK:
+			fmt.Fprintf(&b, "%s %d\n", def.Name, m.lastPortfolioPoll.Load())
+		case metrics.UptimeSeconds:
+			fmt.Fprintf(&b, "%s %s\n", def.Name, formatFloat(time.Since(m.started).Seconds()))
+		case metrics.InFlightToolCalls:
+			fmt.Fprintf(&b, "%s %d\n", def.Name, atomic.LoadInt64(&s.inFlight))
+		case metrics.ChaosInjected:
+			if counts := s.ChaosCounts(); counts != nil {
+				for _, kind := range []string{ChaosError, ChaosLatency, ChaosTimeout} {
+					fmt.Fprintf(&b, "%s{kind=%q} %d\n", def.Name, kind, counts[kind])
+				}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
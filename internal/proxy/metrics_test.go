@@ -0,0 +1,154 @@
+package proxy
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/metrics"
+)
+
+var sampleLine = regexp.MustCompile(`^([a-z_]+)(?:\{(.*)\})? \S+$`)
+
+// Every series /metrics exposes is declared in the registry with the labels
+// it carries, so the generated alert rules and dashboards, which are
+// checked against the same registry, match what is scraped.
+func TestMetricsMatchRegistry(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+	serve(s, "POST", "/call", `{"tool":"get_token_info","args":{}}`)
+
+	w := serve(s, "GET", "/metrics", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	out := w.Body.String()
+	for _, def := range metrics.Registry {
+		for _, want := range []string{"# HELP " + def.Name + " ", "# TYPE " + def.Name + " " + string(def.Type) + "\n"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("/metrics lacks %q", want)
+			}
+		}
+	}
+
+	samples := 0
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		m := sampleLine.FindStringSubmatch(line)
+		if m == nil {
+			t.Errorf("malformed sample %q", line)
+			continue
+		}
+		samples++
+		name, labels := m[1], m[2]
+		def, ok := metrics.Lookup(name)
+		if !ok {
+			for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+				if base, cut := strings.CutSuffix(name, suffix); cut {
+					if def, ok = metrics.Lookup(base); ok && def.Type != metrics.Histogram {
+						ok = false
+					}
+					break
+				}
+			}
+		}
+		if !ok {
+			t.Errorf("%s is not in the registry", name)
+			continue
+		}
+		var got []string
+		for _, pair := range strings.Split(labels, ",") {
+			if label, _, found := strings.Cut(pair, "="); found && label != "le" {
+				got = append(got, label)
+			}
+		}
+		if !slices.Equal(got, def.Labels) {
+			t.Errorf("%s carries labels %v, registry says %v", line, got, def.Labels)
+		}
+	}
+	if samples == 0 {
+		t.Fatal("no samples")
+	}
+	if !strings.Contains(out, metrics.ToolCalls+`{tool="get_token_info"} 1`) {
+		t.Errorf("call not counted:\n%s", out)
+	}
+}
+
+// Calls are counted per tool as they are answered: errors by kind, and
+// every call in the latency histogram, whose buckets are cumulative.
+func TestMetricsCounters(t *testing.T) {
+	fail := false
+	backend := &fakeBackend{reply: func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"internal"}`))
+			return
+		}
+		w.Write([]byte(`{"success":true}`))
+	}}
+	s := newTestServer(t, backend)
+	// Different arguments each time, so no call is answered from the cache.
+	serve(s, "POST", "/call", `{"tool":"get_token_info","args":{"address":"a"}}`)
+	serve(s, "POST", "/call", `{"tool":"get_token_info","args":{"address":"b"}}`)
+	fail = true
+	serve(s, "POST", "/call", `{"tool":"get_token_info","args":{"address":"c"}}`)
+	serve(s, "POST", "/call", `{"tool":"search_tokens","args":{}}`)
+
+	out := serve(s, "GET", "/metrics", "").Body.String()
+	for _, want := range []string{
+		metrics.ToolCalls + `{tool="get_token_info"} 3`,
+		metrics.ToolCalls + `{tool="search_tokens"} 1`,
+		metrics.ToolErrors + `{tool="get_token_info",kind="server"} 1`,
+		metrics.ToolErrors + `{tool="get_token_info",kind="network"} 0`,
+		metrics.ToolErrors + `{tool="search_tokens",kind="server"} 1`,
+		metrics.ToolDuration + `_bucket{tool="get_token_info",le="+Inf"} 3`,
+		metrics.ToolDuration + `_count{tool="get_token_info"} 3`,
+		metrics.InFlightToolCalls + ` 0`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("/metrics lacks %q", want)
+		}
+	}
+
+	prev := -1
+	bucket := regexp.MustCompile(`^` + metrics.ToolDuration + `_bucket\{tool="get_token_info",le="[^"]+"\} (\d+)$`)
+	for _, line := range strings.Split(out, "\n") {
+		if m := bucket.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			if n < prev {
+				t.Errorf("bucket %q below the one before (%d)", line, prev)
+			}
+			prev = n
+		}
+	}
+	if prev != 3 {
+		t.Errorf("last bucket %d, want all 3 calls", prev)
+	}
+}
+
+// /metrics needs the session token like /tools, unless it was made public
+// for scrapers on this machine.
+func TestMetricsAuth(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+	for _, token := range []string{"", "wrong"} {
+		if w := serve(s, "GET", "/metrics", "", token); w.Code != http.StatusForbidden {
+			t.Errorf("token %q: status %d, want 403", token, w.Code)
+		}
+	}
+	if w := serve(s, "GET", "/metrics", ""); w.Code != http.StatusOK {
+		t.Errorf("with the token: status %d", w.Code)
+	}
+
+	s.SetMetricsPublic(true)
+	w := serve(s, "GET", "/metrics", "", "")
+	if w.Code != http.StatusOK {
+		t.Errorf("public: status %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+}
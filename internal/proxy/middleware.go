@@ -1,34 +1,83 @@
 package proxy
 
 import (
+	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
 )
 
-// withAuth wraps an http.HandlerFunc with Bearer-token authentication. The
-// incoming request must carry an Authorization header whose Bearer value
-// matches the proxy's session token. If the token is missing or does not match,
-// a 403 Forbidden JSON response is returned.
+// withAuth wraps an http.HandlerFunc with Bearer-token authentication. Every
+// route except /health and /livez goes through it, and /health does too for
+// callers on other machines (see openRoute). The incoming request must carry an
+// Authorization header whose Bearer value matches the proxy's session token.
+// If the token is missing or does not match, a 403 Forbidden JSON response is
+// returned.
 func (s *ProxyServer) withAuth(next http.HandlerFunc) http.HandlerFunc {
+	return requireToken(s.validToken, next)
+}
+
+// validToken accepts the session token and, for a short grace period after
+// a restart, the previous proxy's token so connected bridges keep working
+// until they pick up the new one.
+func (s *ProxyServer) validToken(token string) bool {
+	if tokenEqual(token, s.sessionToken) {
+		return true
+	}
+	return s.graceToken != "" && time.Now().Before(s.graceUntil) && tokenEqual(token, s.graceToken)
+}
+
+// requireBearer wraps next so it only runs for requests carrying want as
+// their Bearer value.
+func requireBearer(want string, next http.HandlerFunc) http.HandlerFunc {
+	return requireToken(func(token string) bool { return tokenEqual(token, want) }, next)
+}
+
+func requireToken(valid func(string) bool, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Forbidden"})
+			forbidden(w, "missing Authorization header; send 'Authorization: Bearer <session token>'")
 			return
 		}
 
-		token := strings.TrimPrefix(authHeader, "Bearer ")
-		if token == authHeader || subtle.ConstantTimeCompare([]byte(token), []byte(s.sessionToken)) != 1 {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-			json.NewEncoder(w).Encode(map[string]string{"error": "Forbidden"})
+		token, ok := strings.CutPrefix(authHeader, "Bearer ")
+		if !ok || token == "" || !valid(token) {
+			forbidden(w, "invalid session token")
 			return
 		}
 
 		next(w, r)
 	}
 }
+
+func forbidden(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]string{"error": "Forbidden", "message": msg})
+}
+
+// tokenEqual compares digests so the time taken reveals neither the
+// matching prefix nor the length of the expected token. An empty expected
+// token never matches.
+func tokenEqual(got, want string) bool {
+	if want == "" {
+		return false
+	}
+	a := sha256.Sum256([]byte(got))
+	b := sha256.Sum256([]byte(want))
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
+}
+
+// statusRecorder remembers the status code written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
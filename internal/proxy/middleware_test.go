@@ -0,0 +1,102 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Every authenticated route refuses a request without the session token,
+// on a real listener so the whole server stack is in the way.
+func TestAuthRequired(t *testing.T) {
+	backend := &fakeBackend{}
+	s := newTestServer(t, backend)
+	srv := httptest.NewServer(s.server.Handler)
+	defer srv.Close()
+
+	routes := []struct{ method, path, body string }{
+		{"GET", "/tools", ""},
+		{"POST", "/call", `{"tool":"get_token_info","args":{}}`},
+		{"GET", "/stream?tool=stream_prices", ""},
+	}
+	for _, rt := range routes {
+		for _, auth := range []string{"", "Bearer wrong-token", "Bearer ", testToken, "Basic " + testToken} {
+			req, _ := http.NewRequest(rt.method, srv.URL+rt.path, strings.NewReader(rt.body))
+			if auth != "" {
+				req.Header.Set("Authorization", auth)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var body map[string]string
+			json.NewDecoder(resp.Body).Decode(&body)
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusForbidden || body["error"] != "Forbidden" {
+				t.Errorf("%s %s with %q: status %d, body %v, want a 403", rt.method, rt.path, auth, resp.StatusCode, body)
+			}
+		}
+
+		req, _ := http.NewRequest(rt.method, srv.URL+rt.path, strings.NewReader(rt.body))
+		req.Header.Set("Authorization", "Bearer "+testToken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("%s %s with the session token: status %d", rt.method, rt.path, resp.StatusCode)
+		}
+	}
+	if n := backend.calls.Load() + backend.streams.Load(); n != 2 {
+		t.Errorf("backend saw %d tool requests, want only the 2 authorized ones", n)
+	}
+
+	resp, err := http.Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var health map[string]any
+	json.NewDecoder(resp.Body).Decode(&health)
+	if health["authEnforced"] != true {
+		t.Errorf("/health authEnforced = %v, want true", health["authEnforced"])
+	}
+}
+
+// The previous proxy's token keeps working only during the grace period.
+func TestGraceToken(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+	s.graceToken = "old-token"
+	s.graceUntil = time.Now().Add(time.Minute)
+	if w := serve(s, "GET", "/tools", "", "old-token"); w.Code != http.StatusOK {
+		t.Errorf("grace token refused: %d", w.Code)
+	}
+	s.graceUntil = time.Now().Add(-time.Second)
+	if w := serve(s, "GET", "/tools", "", "old-token"); w.Code != http.StatusForbidden {
+		t.Errorf("expired grace token: status %d, want 403", w.Code)
+	}
+}
+
+// /health reports the tool calls answered and the uptime, which 'boba
+// status --json' passes on.
+func TestHealthReportsActivity(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+	serve(s, "POST", "/call", `{"tool":"get_token_info","args":{"address":"a"}}`)
+	serve(s, "POST", "/call", `{"tool":"get_token_info","args":{"address":"b"}}`)
+
+	w := serve(s, "GET", "/health", "", "")
+	var health map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &health); err != nil {
+		t.Fatalf("%v: %s", err, w.Body)
+	}
+	if health["requests"] != 2.0 {
+		t.Errorf("requests = %v, want 2", health["requests"])
+	}
+	if _, ok := health["uptime"].(float64); !ok {
+		t.Errorf("uptime = %v, want seconds", health["uptime"])
+	}
+}
@@ -0,0 +1,85 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// PolicyHeader marks a 403 as a tool blocked by the tool policy rather than
+// a stale session token, so bridges don't refresh the token and retry.
+const PolicyHeader = "X-Boba-Policy"
+
+// ToolAllowed reports whether policy lets agents call tool, and if not, why.
+func ToolAllowed(policy config.ToolPolicy, tool string) (bool, string) {
+	if slices.Contains(policy.Deny, tool) {
+		return false, "it is on the deny list"
+	}
+	switch policy.Mode {
+	case config.ToolPolicyReadOnly:
+		if NeedsConfirmation(tool) {
+			return false, "the proxy is in read-only mode"
+		}
+	case config.ToolPolicyCustom:
+		if !slices.Contains(policy.Allow, tool) {
+			return false, "it is not on the allow list"
+		}
+	}
+	return true, ""
+}
+
+// refuseBlocked answers a call the tool policy doesn't allow.
+func (s *ProxyServer) refuseBlocked(w http.ResponseWriter, id, toolName, why string, policy config.ToolPolicy) {
+	errMsg := fmt.Sprintf("blocked by tool policy: %s", why)
+	s.sendLog(LogEntry{
+		ID:     id,
+		Tool:   toolName,
+		Status: "error",
+		Error:  errMsg,
+	})
+	w.Header().Set(PolicyHeader, policy.Mode)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error":   "tool_blocked",
+		"policy":  policy.Mode,
+		"message": fmt.Sprintf("%s is blocked by the user's tool policy (%s). Do not retry it; if it is needed, ask the user to change the policy with 'boba config tools'.", toolName, why),
+	})
+}
+
+// filterToolList removes the tools policy blocks from a tools/list
+// response, so agents aren't offered them. Bodies that aren't a tool list
+// are returned unchanged.
+func filterToolList(body []byte, policy config.ToolPolicy) []byte {
+	if !policy.Restricted() {
+		return body
+	}
+	var list map[string]any
+	if err := json.Unmarshal(body, &list); err != nil {
+		return body
+	}
+	tools, ok := list["tools"].([]any)
+	if !ok {
+		return body
+	}
+	kept := make([]any, 0, len(tools))
+	for _, t := range tools {
+		if tool, ok := t.(map[string]any); ok {
+			if name, _ := tool["name"].(string); name != "" {
+				if allowed, _ := ToolAllowed(policy, name); !allowed {
+					continue
+				}
+			}
+		}
+		kept = append(kept, t)
+	}
+	list["tools"] = kept
+	filtered, err := json.Marshal(list)
+	if err != nil {
+		return body
+	}
+	return filtered
+}
@@ -0,0 +1,128 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// policyTools are the tools of the upstream manifest the policy tests use.
+var policyTools = []string{"get_portfolio", "search_tokens", "execute_swap", "create_limit_order"}
+
+var policyMatrix = []struct {
+	name    string
+	policy  config.ToolPolicy
+	allowed []string
+}{
+	{"all", config.ToolPolicy{Mode: config.ToolPolicyAll}, policyTools},
+	{"all with deny", config.ToolPolicy{Mode: config.ToolPolicyAll, Deny: []string{"execute_swap"}},
+		[]string{"get_portfolio", "search_tokens", "create_limit_order"}},
+	{"read-only", config.ToolPolicy{Mode: config.ToolPolicyReadOnly},
+		[]string{"get_portfolio", "search_tokens"}},
+	{"read-only with deny", config.ToolPolicy{Mode: config.ToolPolicyReadOnly, Deny: []string{"search_tokens"}},
+		[]string{"get_portfolio"}},
+	{"custom", config.ToolPolicy{Mode: config.ToolPolicyCustom, Allow: []string{"search_tokens", "execute_swap"}},
+		[]string{"search_tokens", "execute_swap"}},
+	{"custom with deny", config.ToolPolicy{Mode: config.ToolPolicyCustom, Allow: []string{"search_tokens", "execute_swap"}, Deny: []string{"execute_swap"}},
+		[]string{"search_tokens"}},
+}
+
+func TestToolAllowed(t *testing.T) {
+	for _, tc := range policyMatrix {
+		for _, tool := range policyTools {
+			allowed, why := ToolAllowed(tc.policy, tool)
+			if want := slices.Contains(tc.allowed, tool); allowed != want {
+				t.Errorf("%s: %s allowed = %v, want %v", tc.name, tool, allowed, want)
+			}
+			if allowed == (why != "") {
+				t.Errorf("%s: %s: allowed %v with reason %q", tc.name, tool, allowed, why)
+			}
+		}
+	}
+}
+
+// manifestBackend serves a tool list of policyTools.
+type manifestBackend struct{ fakeBackend }
+
+func (b *manifestBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/tools" {
+		b.fakeBackend.ServeHTTP(w, r)
+		return
+	}
+	var tools []map[string]any
+	for _, name := range policyTools {
+		tools = append(tools, map[string]any{"name": name, "description": "d"})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"tools": tools, "nextCursor": "c"})
+}
+
+// In every mode the proxy offers exactly the allowed tools and refuses the
+// others with a 403 naming the policy, before they reach the backend.
+func TestPolicyEnforced(t *testing.T) {
+	backend := &manifestBackend{}
+	s := newTestServer(t, backend)
+
+	for _, tc := range policyMatrix {
+		if err := config.SetToolPolicy(tc.policy); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+
+		w := serve(s, "GET", "/tools", "")
+		var list struct {
+			Tools []struct {
+				Name string `json:"name"`
+			} `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+			t.Fatalf("%s: %v: %s", tc.name, err, w.Body)
+		}
+		var offered []string
+		for _, tool := range list.Tools {
+			offered = append(offered, tool.Name)
+		}
+		if !slices.Equal(offered, tc.allowed) {
+			t.Errorf("%s: /tools offers %v, want %v", tc.name, offered, tc.allowed)
+		}
+		if list.NextCursor != "c" {
+			t.Errorf("%s: filtering dropped nextCursor", tc.name)
+		}
+
+		for _, tool := range policyTools {
+			if slices.Contains(tc.allowed, tool) {
+				continue
+			}
+			before := backend.calls.Load()
+			w := serve(s, "POST", "/call", `{"tool":"`+tool+`","args":{}}`)
+			if w.Code != http.StatusForbidden || w.Header().Get(PolicyHeader) != tc.policy.Mode {
+				t.Errorf("%s: %s: status %d, %s %q", tc.name, tool, w.Code, PolicyHeader, w.Header().Get(PolicyHeader))
+			}
+			var refusal map[string]any
+			json.Unmarshal(w.Body.Bytes(), &refusal)
+			if refusal["error"] != "tool_blocked" || refusal["policy"] != tc.policy.Mode {
+				t.Errorf("%s: %s: body %s", tc.name, tool, w.Body)
+			}
+			if backend.calls.Load() != before {
+				t.Errorf("%s: blocked %s reached the backend", tc.name, tool)
+			}
+		}
+	}
+}
+
+// Bodies that aren't a tool list pass through unfiltered.
+func TestFilterToolListPassthrough(t *testing.T) {
+	policy := config.ToolPolicy{Mode: config.ToolPolicyReadOnly}
+	for _, body := range []string{`not json`, `{"error":"upstream"}`, `[1,2]`} {
+		if got := string(filterToolList([]byte(body), policy)); got != body {
+			t.Errorf("%s became %s", body, got)
+		}
+	}
+	all := `{"tools":[{"name":"execute_swap"}]}`
+	if got := string(filterToolList([]byte(all), config.ToolPolicy{Mode: config.ToolPolicyAll})); got != all {
+		t.Errorf("unrestricted policy rewrote the list: %s", got)
+	}
+}
@@ -0,0 +1,85 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// portfolioMaxAge is how old the portfolio GET /portfolio serves may be
+// before it is fetched again.
+const portfolioMaxAge = 15 * time.Second
+
+// portfolioFetchTimeout bounds the fetch GET /portfolio makes.
+const portfolioFetchTimeout = 10 * time.Second
+
+// portfolioCache holds the latest full portfolio seen, from the dashboard's
+// polls, agents' calls or GET /portfolio itself.
+type portfolioCache struct {
+	mu      sync.Mutex
+	body    json.RawMessage
+	fetched time.Time
+	// fetching serializes refreshes, so concurrent requests make one call.
+	fetching sync.Mutex
+}
+
+// store keeps body as the latest portfolio.
+func (p *portfolioCache) store(body []byte, now time.Time) {
+	if !json.Valid(body) {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.body, p.fetched = body, now
+}
+
+// latest returns the latest portfolio and when it was fetched.
+func (p *portfolioCache) latest() (json.RawMessage, time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.body, p.fetched
+}
+
+// sawPortfolio learns from a get_portfolio response: the native balances
+// for the fee check and, when it covers every chain, the portfolio itself.
+func (s *ProxyServer) sawPortfolio(args map[string]any, body []byte) {
+	now := time.Now()
+	s.natives.learn(body, now)
+	if args["chain"] == nil {
+		s.portfolio.store(body, now)
+	}
+}
+
+// handlePortfolio serves the latest portfolio, fetching it when the one
+// held is older than portfolioMaxAge. A failed fetch falls back to the
+// one held, marked stale.
+func (s *ProxyServer) handlePortfolio(w http.ResponseWriter, r *http.Request) {
+	body, fetched := s.portfolio.latest()
+	stale := false
+	if time.Since(fetched) >= portfolioMaxAge {
+		s.portfolio.fetching.Lock()
+		// Another request may have refreshed it while this one waited.
+		if body, fetched = s.portfolio.latest(); time.Since(fetched) >= portfolioMaxAge {
+			ctx, cancel := context.WithTimeout(r.Context(), portfolioFetchTimeout)
+			_, err := s.CallTool(ctx, "get_portfolio", map[string]any{"user_id": "me"})
+			cancel()
+			if err != nil && body == nil {
+				s.portfolio.fetching.Unlock()
+				writeJSONError(w, failureStatus(err), err.Error())
+				return
+			}
+			stale = err != nil
+			body, fetched = s.portfolio.latest()
+		}
+		s.portfolio.fetching.Unlock()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{
+		"fetchedAt": fetched,
+		"stale":     stale,
+		"portfolio": body,
+	})
+}
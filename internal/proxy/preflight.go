@@ -0,0 +1,237 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// PreflightHeader carries the pre-flight fee warning on a trade's /call
+// response, for the bridge to pass on to the agent.
+const PreflightHeader = "X-Boba-Preflight-Warning"
+
+// gasFloor is the native balance a trade on a chain is expected to need
+// for fees.
+type gasFloor struct {
+	Symbol string
+	Min    float64
+}
+
+// gasFloors are rough minimums per chain slug, with room for priority fees
+// and, on Solana, the rent of a new token account. They only decide when to
+// warn, so erring high is cheap.
+var gasFloors = map[string]gasFloor{
+	"solana":   {"SOL", 0.01},
+	"eth":      {"ETH", 0.003},
+	"base":     {"ETH", 0.0003},
+	"arb":      {"ETH", 0.0003},
+	"bsc":      {"BNB", 0.002},
+	"avax":     {"AVAX", 0.02},
+	"apechain": {"APE", 0.1},
+	"hyperevm": {"HYPE", 0.01},
+	"monad":    {"MON", 0.1},
+}
+
+// preflightTools are the calls checked for fees before they are forwarded.
+var preflightTools = map[string]bool{
+	"execute_swap":       true,
+	"execute_trade":      true,
+	"create_limit_order": true,
+	"create_dca_order":   true,
+	"create_twap_order":  true,
+}
+
+// chainParams are the arguments a trade's chain may be given in, the chain
+// the funds leave from first.
+var chainParams = []string{"from_chain", "fromChain", "src_chain", "chain", "chain_id", "chainId", "chain_name"}
+
+// tokenParams are the arguments naming a trade's tokens. Without a chain
+// argument, a Solana mint among them places the trade on Solana.
+var tokenParams = []string{"from_token", "fromToken", "input_mint", "inputMint", "sell_token", "token_in", "input_token", "token_address", "to_token", "toToken", "output_mint", "outputMint"}
+
+// tradeChain returns the slug of the chain a trade's fees are paid on, and
+// whether it could be told from args.
+func tradeChain(args map[string]any) (string, bool) {
+	for _, param := range chainParams {
+		if slug, ok := chainSlug(args[param]); ok {
+			return slug, true
+		}
+	}
+	for _, param := range tokenParams {
+		if s, ok := args[param].(string); ok && len(s) >= 32 && len(s) <= 44 && base58Re.MatchString(s) {
+			return "solana", true
+		}
+	}
+	return "", false
+}
+
+// chainSlug resolves a chain given by name, alias or numeric ID.
+func chainSlug(v any) (string, bool) {
+	var c config.Chain
+	var ok bool
+	switch t := v.(type) {
+	case float64:
+		c, ok = config.LookupChainID(int(t))
+	case string:
+		if id, err := strconv.Atoi(t); err == nil {
+			c, ok = config.LookupChainID(id)
+		} else {
+			c, ok = config.LookupChain(t)
+		}
+	}
+	return c.Slug, ok
+}
+
+// nativeBalanceTTL is how long a native balance seen in a portfolio is
+// trusted for the fee check.
+const nativeBalanceTTL = time.Minute
+
+// preflightTimeout bounds the portfolio lookup a fee check makes when no
+// recent balance is known.
+const preflightTimeout = 3 * time.Second
+
+// nativeBalances remembers the native balance of each chain from the
+// portfolios passing through the proxy, the dashboard's polls included.
+type nativeBalances struct {
+	mu     sync.Mutex
+	bySlug map[string]float64
+	seen   time.Time // when a portfolio was last read; zero after forget
+}
+
+// learn records the native balances listed in a get_portfolio response.
+// Chains it doesn't list are left unknown rather than taken as empty.
+func (n *nativeBalances) learn(body []byte, now time.Time) {
+	var raw map[string]any
+	if json.Unmarshal(body, &raw) != nil {
+		return
+	}
+	if inner, ok := raw["data"].(map[string]any); ok {
+		raw = inner
+	}
+	list, ok := raw["native_balances"].([]any)
+	if !ok {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.bySlug == nil || now.Sub(n.seen) >= nativeBalanceTTL {
+		n.bySlug = make(map[string]float64)
+	}
+	n.seen = now
+	for _, item := range list {
+		bal, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		slug, ok := "", false
+		for _, k := range []string{"chain_id", "chainId", "chain_name", "chain", "chainName", "network"} {
+			if slug, ok = chainSlug(bal[k]); ok {
+				break
+			}
+		}
+		if amount, known := numberArg(bal["balance"]); ok && known {
+			n.bySlug[slug] = amount
+		}
+	}
+}
+
+// get returns the native balance last seen on the chain with slug. fresh
+// is false when no portfolio was read within nativeBalanceTTL, in which
+// case ok is false too.
+func (n *nativeBalances) get(slug string, now time.Time) (amount float64, ok, fresh bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.seen.IsZero() || now.Sub(n.seen) >= nativeBalanceTTL {
+		return 0, false, false
+	}
+	amount, ok = n.bySlug[slug]
+	return amount, ok, true
+}
+
+// forget drops the balances, after a trade changed them.
+func (n *nativeBalances) forget() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.bySlug, n.seen = nil, time.Time{}
+}
+
+// numberArg reads a JSON number or numeric string.
+func numberArg(v any) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case string:
+		f, err := strconv.ParseFloat(t, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// SetStrictPreflight makes the proxy refuse trades the pre-flight check
+// warns about, instead of forwarding them with the warning. It must be
+// called before Start.
+func (s *ProxyServer) SetStrictPreflight(strict bool) {
+	s.gasStrict = strict
+}
+
+// preflight checks that the wallet holds enough of the chain's native token
+// to pay for a trade. It returns a warning, or "" when the balance covers
+// the fees or can't be told. The balance is the one last seen in a
+// portfolio, fetched when none was seen recently.
+func (s *ProxyServer) preflight(ctx context.Context, tool string, args map[string]any) string {
+	if !preflightTools[tool] {
+		return ""
+	}
+	slug, ok := tradeChain(args)
+	if !ok {
+		return ""
+	}
+	floor, ok := gasFloors[slug]
+	if !ok {
+		return ""
+	}
+	amount, ok, fresh := s.natives.get(slug, time.Now())
+	if !fresh {
+		ctx, cancel := context.WithTimeout(ctx, preflightTimeout)
+		defer cancel()
+		if _, err := s.CallTool(ctx, "get_portfolio", map[string]any{"user_id": "me"}); err != nil {
+			return ""
+		}
+		amount, ok, _ = s.natives.get(slug, time.Now())
+	}
+	if !ok || amount >= floor.Min {
+		return ""
+	}
+	name := slug
+	if c, found := config.LookupChain(slug); found {
+		name = c.Name
+	}
+	return fmt.Sprintf("low %s for fees on %s: %s %s held, about %s %s needed; the trade may fail",
+		floor.Symbol, name, strconv.FormatFloat(amount, 'g', 4, 64), floor.Symbol,
+		strconv.FormatFloat(floor.Min, 'g', -1, 64), floor.Symbol)
+}
+
+// refuseLowGas answers a trade the pre-flight check warned about in strict
+// mode, without forwarding it.
+func (s *ProxyServer) refuseLowGas(w http.ResponseWriter, id, toolName, warning string, mods []Modification) {
+	s.sendLog(LogEntry{
+		ID:            id,
+		Tool:          toolName,
+		Status:        "error",
+		Error:         "not executed: " + warning,
+		Modifications: mods,
+	})
+	w.Header().Set(PreflightHeader, warning)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusPreconditionFailed)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error":   "insufficient_gas",
+		"message": fmt.Sprintf("%s was not executed: %s. Tell the user to fund the wallet's native balance; do not retry until they have.", toolName, warning),
+	})
+}
@@ -0,0 +1,177 @@
+package proxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// Every supported chain has a fee floor in its own native token.
+func TestGasFloors(t *testing.T) {
+	for _, c := range config.Chains {
+		floor, ok := gasFloors[c.Slug]
+		if !ok {
+			t.Errorf("%s has no fee floor", c.Slug)
+			continue
+		}
+		if floor.Symbol != c.Native || floor.Min <= 0 {
+			t.Errorf("%s floor = %+v, native token %s", c.Slug, floor, c.Native)
+		}
+	}
+	if gasFloors["solana"].Symbol != "SOL" || gasFloors["base"].Symbol != "ETH" || gasFloors["bsc"].Symbol != "BNB" {
+		t.Error("wrong native token for solana, base or bsc")
+	}
+}
+
+func TestTradeChain(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		args map[string]any
+		want string // "" when the chain can't be told
+	}{
+		{"slug", map[string]any{"chain": "base"}, "base"},
+		{"alias", map[string]any{"chain_name": "Arbitrum"}, "arb"},
+		{"numeric ID", map[string]any{"chain_id": 56.0}, "bsc"},
+		{"ID as a string", map[string]any{"chainId": "8453"}, "base"},
+		{"source chain first", map[string]any{"from_chain": "eth", "chain": "base"}, "eth"},
+		{"unknown chain, then a known one", map[string]any{"chain": "dogechain", "chain_id": 1.0}, "eth"},
+		{"Solana mint", map[string]any{"input_mint": "So11111111111111111111111111111111111111112", "amount": 1.0}, "solana"},
+		{"EVM token only", map[string]any{"from_token": "0x4200000000000000000000000000000000000006"}, ""},
+		{"symbol only", map[string]any{"from_token": "SOL"}, ""},
+		{"unknown chain", map[string]any{"chain": "dogechain"}, ""},
+		{"nothing", map[string]any{}, ""},
+	} {
+		got, ok := tradeChain(tc.args)
+		if got != tc.want || ok != (tc.want != "") {
+			t.Errorf("%s: %q, %v; want %q", tc.name, got, ok, tc.want)
+		}
+	}
+}
+
+func TestNativeBalances(t *testing.T) {
+	var n nativeBalances
+	now := time.Now()
+	if _, ok, fresh := n.get("solana", now); ok || fresh {
+		t.Error("balance known before any portfolio")
+	}
+	n.learn([]byte(`{"data":{"native_balances":[
+		{"chain_name":"solana","balance":"0.004"},
+		{"chain_id":8453,"balance":0.5},
+		{"network":"dogechain","balance":9}
+	]}}`), now)
+	for _, tc := range []struct {
+		slug   string
+		amount float64
+		ok     bool
+	}{
+		{"solana", 0.004, true},
+		{"base", 0.5, true},
+		{"eth", 0, false}, // not listed: unknown, not empty
+	} {
+		if amount, ok, fresh := n.get(tc.slug, now); amount != tc.amount || ok != tc.ok || !fresh {
+			t.Errorf("%s: %v, %v, %v", tc.slug, amount, ok, fresh)
+		}
+	}
+	if _, _, fresh := n.get("solana", now.Add(nativeBalanceTTL)); fresh {
+		t.Error("balance trusted past nativeBalanceTTL")
+	}
+	n.forget()
+	if _, ok, _ := n.get("solana", now); ok {
+		t.Error("balance kept after forget")
+	}
+}
+
+// preflightBackend answers get_portfolio with a Solana balance of sol and
+// records the other tools called.
+type preflightBackend struct {
+	fakeBackend
+	mu    sync.Mutex
+	sol   string
+	tools []string
+}
+
+func newPreflightBackend(sol string) *preflightBackend {
+	b := &preflightBackend{sol: sol}
+	b.reply = func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Tool string }
+		json.NewDecoder(r.Body).Decode(&body)
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.tools = append(b.tools, body.Tool)
+		w.Header().Set("Content-Type", "application/json")
+		if body.Tool == "get_portfolio" {
+			w.Write([]byte(`{"native_balances":[{"chain":"solana","balance":` + b.sol + `}]}`))
+			return
+		}
+		w.Write([]byte(`{"success":true}`))
+	}
+	return b
+}
+
+func (b *preflightBackend) setBalance(sol string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sol = sol
+}
+
+func (b *preflightBackend) called() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Join(b.tools, ",")
+}
+
+// A trade from a wallet short of fees goes through with a warning for the
+// caller and the log, or is refused in strict mode. The balance is looked
+// up once and reused until the trade spends it.
+func TestPreflight(t *testing.T) {
+	swap := `{"tool":"execute_swap","args":{"chain":"solana","amount":1}}`
+
+	backend := newPreflightBackend("0.002")
+	s := newTestServer(t, backend)
+	w := serve(s, "POST", "/call", swap)
+	warning := w.Header().Get(PreflightHeader)
+	if w.Code != http.StatusOK || !strings.Contains(warning, "low SOL for fees on Solana: 0.002 SOL held, about 0.01 SOL needed") {
+		t.Errorf("low balance: %d, warning %q", w.Code, warning)
+	}
+	if e := lastEntry(t, s); e.Warning != warning || e.Status != "success" {
+		t.Errorf("logged %s with warning %q", e.Status, e.Warning)
+	}
+	if got := backend.called(); got != "get_portfolio,execute_swap" {
+		t.Errorf("backend calls = %s", got)
+	}
+
+	// Read-only tools aren't checked, and a trade elsewhere is checked
+	// against its own chain.
+	serve(s, "POST", "/call", `{"tool":"get_token_info","args":{"chain":"solana","address":"x"}}`)
+	if w := serve(s, "POST", "/call", `{"tool":"execute_swap","args":{"chain":"dogechain"}}`); w.Header().Get(PreflightHeader) != "" {
+		t.Errorf("warning for a chain without a floor: %q", w.Header().Get(PreflightHeader))
+	}
+	if got := backend.called(); got != "get_portfolio,execute_swap,get_token_info,execute_swap" {
+		t.Errorf("backend calls = %s", got)
+	}
+
+	// The trade spent the balance seen, so the next one looks it up again.
+	backend.setBalance("0.5")
+	if w := serve(s, "POST", "/call", swap); w.Header().Get(PreflightHeader) != "" {
+		t.Errorf("warning with enough balance: %q", w.Header().Get(PreflightHeader))
+	}
+
+	backend.setBalance("0.002")
+	s.SetStrictPreflight(true)
+	before := backend.called()
+	w = serve(s, "POST", "/call", swap)
+	if w.Code != http.StatusPreconditionFailed || !strings.Contains(w.Body.String(), "insufficient_gas") {
+		t.Errorf("strict: %d %s", w.Code, w.Body.String())
+	}
+	if got := strings.TrimPrefix(backend.called(), before); got != ",get_portfolio" {
+		t.Errorf("strict mode forwarded the trade: %s", got)
+	}
+	if e := lastEntry(t, s); e.Status != "error" || !strings.HasPrefix(e.Error, "not executed: low SOL") {
+		t.Errorf("strict: logged %s %q", e.Status, e.Error)
+	}
+}
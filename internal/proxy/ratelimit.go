@@ -0,0 +1,190 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// StatusRateLimited marks a call the proxy refused because the agent was
+// calling too fast. It is final, like "success" and "error".
+const StatusRateLimited = "rate_limited"
+
+// RateLimit bounds how hard agents can drive the backend: a token bucket per
+// tool and a cap on calls in flight at once. Zero turns either limit off.
+type RateLimit struct {
+	PerSecond   float64
+	Burst       int
+	MaxInFlight int
+}
+
+// DefaultRateLimit returns the limits from the config file.
+func DefaultRateLimit() RateLimit {
+	rate, burst := config.GetRateLimit()
+	return RateLimit{PerSecond: rate, Burst: burst, MaxInFlight: config.GetMaxInFlight()}
+}
+
+func (l RateLimit) String() string {
+	var parts []string
+	if l.PerSecond > 0 {
+		parts = append(parts, fmt.Sprintf("%s/s per tool, burst %d", strconv.FormatFloat(l.PerSecond, 'f', -1, 64), l.Burst))
+	}
+	if l.MaxInFlight > 0 {
+		parts = append(parts, fmt.Sprintf("%d in flight", l.MaxInFlight))
+	}
+	if len(parts) == 0 {
+		return "off"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseRateLimit applies a spec like "5", "2.5/s,burst=4" or "inflight=8"
+// to base. "off" turns every limit off; "0" turns off the per-tool rate.
+func ParseRateLimit(s string, base RateLimit) (RateLimit, error) {
+	l := base
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if part == "off" {
+			l = RateLimit{}
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			key, val = "rate", strings.TrimSuffix(part, "/s")
+		}
+		n, err := strconv.ParseFloat(val, 64)
+		if err != nil || n < 0 {
+			return base, fmt.Errorf("rate limit: %q is not a non-negative number", val)
+		}
+		switch key {
+		case "rate":
+			l.PerSecond = n
+		case "burst":
+			l.Burst = int(n)
+		case "inflight":
+			l.MaxInFlight = int(n)
+		default:
+			return base, fmt.Errorf("rate limit: unknown setting %q (want rate, burst or inflight)", key)
+		}
+	}
+	if l.PerSecond > 0 && l.Burst < 1 {
+		l.Burst = 1
+	}
+	return l, nil
+}
+
+// rateLimiter enforces a RateLimit.
+type rateLimiter struct {
+	mu       sync.Mutex
+	limit    RateLimit
+	buckets  map[string]*tokenBucket
+	inFlight int
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(l RateLimit) *rateLimiter {
+	return &rateLimiter{limit: l, buckets: make(map[string]*tokenBucket)}
+}
+
+// enter admits a call to tool, or says how long to wait before trying again
+// and why not. An admitted call must be followed by leave.
+func (rl *rateLimiter) enter(tool string, now time.Time) (retryAfter time.Duration, reason string) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if rl.limit.MaxInFlight > 0 && rl.inFlight >= rl.limit.MaxInFlight {
+		return time.Second, fmt.Sprintf("%d calls already in flight", rl.inFlight)
+	}
+	if rl.limit.PerSecond > 0 {
+		b := rl.buckets[tool]
+		if b == nil {
+			b = &tokenBucket{tokens: float64(rl.limit.Burst), last: now}
+			rl.buckets[tool] = b
+		}
+		b.tokens = math.Min(float64(rl.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*rl.limit.PerSecond)
+		b.last = now
+		if b.tokens < 1 {
+			wait := time.Duration((1 - b.tokens) / rl.limit.PerSecond * float64(time.Second))
+			return wait, fmt.Sprintf("%s called more than %s times a second", tool, strconv.FormatFloat(rl.limit.PerSecond, 'f', -1, 64))
+		}
+		b.tokens--
+	}
+	rl.inFlight++
+	return 0, ""
+}
+
+func (rl *rateLimiter) leave() {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.inFlight--
+}
+
+// SetRateLimit replaces the limits from the config file. It must be called
+// before Start.
+func (s *ProxyServer) SetRateLimit(l RateLimit) {
+	s.limiter = newRateLimiter(l)
+}
+
+// RateLimit returns the limits in force.
+func (s *ProxyServer) RateLimit() RateLimit {
+	return s.limiter.limit
+}
+
+// retryAfterSeconds renders a wait for the Retry-After header, which only
+// takes whole seconds.
+func retryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(max(1, int(math.Ceil(d.Seconds()))))
+}
+
+// Upstream 429s are retried once, after the Retry-After the backend asked
+// for, within these bounds.
+const (
+	upstreamRetryDefault = time.Second
+	upstreamRetryMax     = 10 * time.Second
+)
+
+// upstreamRetryDelay reads a Retry-After header given in seconds or as a date.
+func upstreamRetryDelay(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	d := upstreamRetryDefault
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if at, err := http.ParseTime(v); err == nil {
+		d = time.Until(at)
+	}
+	return min(max(d, 0), upstreamRetryMax)
+}
+
+// refuseRateLimited answers a call turned away by the limiter with a 429 and
+// logs it, so throttling shows in the activity log.
+func (s *ProxyServer) refuseRateLimited(w http.ResponseWriter, id, toolName string, wait time.Duration, reason string) {
+	errMsg := "rate limited: " + reason
+	s.sendLog(LogEntry{
+		ID:     id,
+		Tool:   toolName,
+		Status: StatusRateLimited,
+		Error:  errMsg,
+	})
+	w.Header().Set("Retry-After", retryAfterSeconds(wait))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error":       "rate_limited",
+		"message":     fmt.Sprintf("%s. Wait %s before calling %s again instead of retrying in a loop.", errMsg, retryAfterSeconds(wait)+"s", toolName),
+		"retry_after": wait.Seconds(),
+	})
+}
@@ -0,0 +1,132 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	rl := newRateLimiter(RateLimit{PerSecond: 2, Burst: 2})
+	now := time.Now()
+	for i := range 2 {
+		if _, reason := rl.enter("get_token_info", now); reason != "" {
+			t.Fatalf("call %d within the burst refused: %s", i+1, reason)
+		}
+		rl.leave()
+	}
+	wait, reason := rl.enter("get_token_info", now)
+	if reason == "" {
+		t.Fatal("call past the burst admitted")
+	}
+	if wait != 500*time.Millisecond {
+		t.Errorf("wait = %s, want 500ms", wait)
+	}
+	// Each tool has a bucket of its own.
+	if _, reason := rl.enter("get_portfolio", now); reason != "" {
+		t.Errorf("another tool refused: %s", reason)
+	}
+	rl.leave()
+	if _, reason := rl.enter("get_token_info", now.Add(wait)); reason != "" {
+		t.Errorf("call after the wait refused: %s", reason)
+	}
+}
+
+func TestInFlightCap(t *testing.T) {
+	rl := newRateLimiter(RateLimit{MaxInFlight: 1})
+	now := time.Now()
+	if _, reason := rl.enter("a", now); reason != "" {
+		t.Fatal(reason)
+	}
+	if _, reason := rl.enter("b", now); reason == "" {
+		t.Fatal("second call in flight admitted")
+	}
+	rl.leave()
+	if _, reason := rl.enter("b", now); reason != "" {
+		t.Errorf("call after leave refused: %s", reason)
+	}
+}
+
+func TestParseRateLimit(t *testing.T) {
+	base := RateLimit{PerSecond: 5, Burst: 10, MaxInFlight: 8}
+	for _, tc := range []struct {
+		spec    string
+		want    RateLimit
+		wantErr bool
+	}{
+		{"", base, false},
+		{"2", RateLimit{PerSecond: 2, Burst: 10, MaxInFlight: 8}, false},
+		{"2.5/s,burst=4", RateLimit{PerSecond: 2.5, Burst: 4, MaxInFlight: 8}, false},
+		{"inflight=3", RateLimit{PerSecond: 5, Burst: 10, MaxInFlight: 3}, false},
+		{"0", RateLimit{PerSecond: 0, Burst: 10, MaxInFlight: 8}, false},
+		{"off", RateLimit{}, false},
+		{"off,inflight=2", RateLimit{MaxInFlight: 2}, false},
+		{"1,burst=0", RateLimit{PerSecond: 1, Burst: 1, MaxInFlight: 8}, false},
+		{"fast", base, true},
+		{"-1", base, true},
+		{"speed=3", base, true},
+	} {
+		got, err := ParseRateLimit(tc.spec, base)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseRateLimit(%q) error = %v, want error %v", tc.spec, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseRateLimit(%q) = %+v, want %+v", tc.spec, got, tc.want)
+		}
+	}
+}
+
+// A 429 from the backend is retried once after its Retry-After, and no
+// more.
+func TestUpstream429RetriedOnce(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		limited    int64 // how many calls the backend turns away
+		wantStatus int
+	}{
+		{"recovers", 1, http.StatusOK},
+		{"keeps refusing", 5, http.StatusTooManyRequests},
+	} {
+		backend := &fakeBackend{}
+		backend.reply = func(w http.ResponseWriter, r *http.Request) {
+			if backend.calls.Load() <= tc.limited {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				io.WriteString(w, `{"error":"slow down"}`)
+				return
+			}
+			io.WriteString(w, `{"success":true}`)
+		}
+		s := newTestServer(t, backend)
+		w := serve(s, "POST", "/call", `{"tool":"get_token_info","args":{"address":"x"}}`)
+		if w.Code != tc.wantStatus {
+			t.Errorf("%s: status %d, want %d: %s", tc.name, w.Code, tc.wantStatus, w.Body)
+		}
+		if n := backend.calls.Load(); n != 2 {
+			t.Errorf("%s: backend called %d times, want 2", tc.name, n)
+		}
+	}
+}
+
+// Streams draw on the same limits as calls.
+func TestStreamRateLimited(t *testing.T) {
+	backend := &fakeBackend{}
+	s := newTestServer(t, backend)
+	s.SetRateLimit(RateLimit{PerSecond: 1, Burst: 1})
+
+	if w := serve(s, "GET", "/stream?tool=stream_prices", ""); w.Code != http.StatusOK {
+		t.Fatalf("first stream: status %d: %s", w.Code, w.Body)
+	}
+	w := serve(s, "GET", "/stream?tool=stream_prices", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("second stream: status %d, want 429", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("no Retry-After on a throttled stream")
+	}
+	if n := backend.streams.Load(); n != 1 {
+		t.Errorf("backend saw %d streams, want 1", n)
+	}
+}
@@ -0,0 +1,90 @@
+package proxy
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/auth"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/logger"
+)
+
+// The proxy refreshes the access token in the background a little before it
+// expires, so the first call after an idle spell doesn't wait for it.
+const (
+	// refreshLead is how long before expiry the token is refreshed, less up
+	// to refreshJitter so several proxies don't refresh in step.
+	refreshLead   = 2 * time.Minute
+	refreshJitter = 30 * time.Second
+	// refreshIdle is how often to look again when there is no token yet.
+	refreshIdle = time.Minute
+	// Failed refreshes are retried with backoff between these bounds; after
+	// refreshFullAfter failures in a row the proxy authenticates from
+	// scratch instead.
+	refreshRetryMin  = 5 * time.Second
+	refreshRetryMax  = 2 * time.Minute
+	refreshFullAfter = 3
+)
+
+// refreshTokens runs until stop is closed, refreshing the access token ahead
+// of its expiry.
+func (s *ProxyServer) refreshTokens(stop <-chan struct{}) {
+	failures := 0
+	backoff := refreshRetryMin
+	for {
+		wait := nextRefresh(time.Now())
+		if failures > 0 {
+			wait = backoff
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		expiresAt, ok, err := config.AccessTokenExpiry()
+		if !ok || err != nil {
+			continue
+		}
+		if failures == 0 && time.Until(expiresAt) > refreshLead+refreshJitter {
+			continue // refreshed elsewhere in the meantime
+		}
+
+		full := failures >= refreshFullAfter
+		start := time.Now()
+		tokens, err := auth.Refresh(full)
+		if err != nil {
+			failures++
+			backoff = min(refreshRetryMin<<(failures-1), refreshRetryMax)
+			logger.Warn("background token refresh failed", "error", err, "attempt", failures, "retry_in", backoff)
+			continue
+		}
+		failures = 0
+
+		how := "refreshed"
+		if full {
+			how = "re-authenticated"
+		}
+		logger.Debug("access token "+how+" ahead of expiry", "expires", tokens.AccessTokenExpiresAt)
+		if config.GetLogLevel() == "debug" {
+			s.sendLog(LogEntry{
+				Tool:     "auth",
+				Status:   "success",
+				Duration: time.Since(start),
+				Preview:  "Access token " + how + " ahead of expiry",
+			})
+		}
+	}
+}
+
+// nextRefresh returns how long to wait before refreshing the stored token.
+func nextRefresh(now time.Time) time.Duration {
+	expiresAt, ok, err := config.AccessTokenExpiry()
+	if !ok || err != nil {
+		return refreshIdle
+	}
+	lead := refreshLead + rand.N(refreshJitter)
+	return max(expiresAt.Add(-lead).Sub(now), refreshRetryMin)
+}
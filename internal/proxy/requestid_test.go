@@ -0,0 +1,99 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/client"
+)
+
+var uuidRe = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// Every call is sent upstream with a fresh request ID, which the caller
+// and the log get too, unless the backend answers with its own.
+func TestRequestID(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	backendID := ""
+	backend := &fakeBackend{reply: func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		sent = append(sent, r.Header.Get(client.RequestIDHeader))
+		if backendID != "" {
+			w.Header().Set(client.RequestIDHeader, backendID)
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"error":"swap failed"}`))
+			return
+		}
+		w.Write([]byte(`{"success":true}`))
+	}}
+	s := newTestServer(t, backend)
+
+	w := serve(s, "POST", "/call", `{"tool":"get_token_info","args":{"address":"a"}}`)
+	id := w.Header().Get(client.RequestIDHeader)
+	if !uuidRe.MatchString(id) || len(sent) != 1 || sent[0] != id {
+		t.Fatalf("returned %q, sent upstream %q", id, sent)
+	}
+	if e := lastEntry(t, s); e.RequestID != id {
+		t.Errorf("logged request ID %q, want %q", e.RequestID, id)
+	}
+	serve(s, "POST", "/call", `{"tool":"get_token_info","args":{"address":"b"}}`)
+	if sent[1] == sent[0] || !uuidRe.MatchString(sent[1]) {
+		t.Errorf("second call sent %q after %q", sent[1], sent[0])
+	}
+	lastEntry(t, s)
+
+	mu.Lock()
+	backendID = "backend-req-42"
+	mu.Unlock()
+	w = serve(s, "POST", "/call", `{"tool":"execute_swap","args":{"amount":1}}`)
+	if got := w.Header().Get(client.RequestIDHeader); got != "backend-req-42" {
+		t.Errorf("backend's request ID not echoed: %q", got)
+	}
+	if e := lastEntry(t, s); e.Status != "error" || e.RequestID != "backend-req-42" {
+		t.Errorf("logged %s with request ID %q", e.Status, e.RequestID)
+	}
+}
+
+// The proxy's own error bodies carry the request ID.
+func TestRequestIDInErrorBody(t *testing.T) {
+	s := newTestServer(t, &fakeBackend{})
+	s.SetMaxResponseSize(4) // smaller than any answer
+
+	w := serve(s, "POST", "/call", `{"tool":"get_token_info","args":{"address":"a"}}`)
+	var body struct {
+		Error     string `json:"error"`
+		RequestID string `json:"requestId"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("%v: %s", err, w.Body.String())
+	}
+	if body.Error == "" || body.RequestID == "" || body.RequestID != w.Header().Get(client.RequestIDHeader) {
+		t.Errorf("error body %+v, header %q", body, w.Header().Get(client.RequestIDHeader))
+	}
+}
+
+// Dashboard calls get a request ID too, and their errors end with it.
+func TestCallToolRequestID(t *testing.T) {
+	var sent string
+	s := newTestServer(t, &fakeBackend{reply: func(w http.ResponseWriter, r *http.Request) {
+		sent = r.Header.Get(client.RequestIDHeader)
+		w.WriteHeader(http.StatusBadGateway)
+	}})
+
+	_, err := s.CallTool(context.Background(), "get_portfolio", map[string]any{})
+	if !uuidRe.MatchString(sent) || err == nil || !strings.HasSuffix(err.Error(), "(request ID "+sent+")") {
+		t.Errorf("sent %q, error %v", sent, err)
+	}
+
+	ctx := client.WithRequestID(context.Background(), "given-id")
+	s.CallTool(ctx, "get_portfolio", map[string]any{})
+	if sent != "given-id" {
+		t.Errorf("the context's request ID wasn't used: %q", sent)
+	}
+}
@@ -0,0 +1,54 @@
+package proxy
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// A backend response over the size cap fails the call with an error naming
+// the limit, whether or not its length is announced up front. Streams are
+// exempt.
+func TestMaxResponseSize(t *testing.T) {
+	big := `{"holders":[` + strings.Repeat(`{"address":"0x0000000000000000000000000000000000000000"},`, 100) + `{}]}`
+	s := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/call":
+			// Announce the length when the call asks for it; otherwise
+			// the body is sent chunked.
+			if body, _ := io.ReadAll(r.Body); strings.Contains(string(body), "known") {
+				w.Header().Set("Content-Length", strconv.Itoa(len(big)))
+			}
+			io.WriteString(w, big)
+		case "/stream":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "data: %s\n\n", big)
+		}
+	}))
+	s.SetMaxResponseSize(1 << 10)
+
+	for i, length := range []string{"known", "chunked"} {
+		w := serve(s, "POST", "/call", fmt.Sprintf(`{"tool":"get_holders","args":{"length":%q,"n":%d}}`, length, i))
+		if w.Code == http.StatusOK || !strings.Contains(w.Body.String(), "response exceeded 1KB limit") {
+			t.Errorf("%s length: %d %s", length, w.Code, w.Body.String())
+		}
+		e := lastEntry(t, s)
+		if e.Tool != "get_holders" || e.Status != "error" || !strings.Contains(e.Error, "response exceeded 1KB limit") {
+			t.Errorf("%s length: logged %s %s %q", length, e.Tool, e.Status, e.Error)
+		}
+	}
+
+	w := serve(s, "GET", "/stream?tool=stream_prices", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), big) {
+		t.Errorf("stream cut at the cap: %d, %d bytes", w.Code, w.Body.Len())
+	}
+
+	// Under the cap, the same response goes through.
+	s.SetMaxResponseSize(1 << 20)
+	if w := serve(s, "POST", "/call", `{"tool":"get_holders","args":{"n":3}}`); w.Code != http.StatusOK {
+		t.Errorf("under the cap: %d %s", w.Code, w.Body.String())
+	}
+}
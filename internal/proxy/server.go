@@ -4,20 +4,25 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
+	"os"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/tradeboba/boba-cli/internal/auth"
+	"github.com/tradeboba/boba-cli/internal/client"
 	"github.com/tradeboba/boba-cli/internal/config"
 	"github.com/tradeboba/boba-cli/internal/logger"
 )
 
 // LogEntry represents a single proxy request log item displayed in the TUI.
 type LogEntry struct {
+	ID              string // Shared by every entry of one request; empty when an entry stands alone
 	Tool            string
 	Status          string // "pending", "success", "error"
 	Duration        time.Duration
@@ -25,21 +30,68 @@ type LogEntry struct {
 	FormattedOutput string // Full multi-line rich formatted output (charts, tables, boxes)
 	Timestamp       time.Time
 	Error           string
+	ErrorKind       string            // What failed, e.g. ErrorRejected or ErrorNetwork; empty when the proxy refused the call itself
+	Modifications   []Modification    // Argument changes the proxy made before forwarding
+	Args            map[string]any    // Arguments as the agent sent them; set on the first entry only
+	Events          int               // Events relayed so far, on stream entries
+	Cached          bool              // Answered from the proxy's response cache
+	Injected        map[string]string // Autofilled params, redacted; only with BOBA_DEBUG=1
+	Repeat          int               // Identical errors logged in a row, this one included; 0 when not repeated
+	Warning         string            // Pre-flight warning on a trade, such as too little native balance for fees
+	RequestID       string            // Sent to the backend as X-Request-Id, or the one it answered with
+	TxHash          string            // Transaction hash from an executed write call's response
 }
 
 // ProxyServer is an HTTP proxy that sits between AI agents and the Boba MCP
 // backend. It handles authentication, parameter auto-fill, and request logging.
 type ProxyServer struct {
 	server       *http.Server
+	host         string // address listened on; 127.0.0.1 unless SetBindAddress changed it
 	port         int
 	sessionToken string
+	graceToken   string
+	graceUntil   time.Time
 	logChan      chan LogEntry
+	errRun       errorRun
 	requestCount int64
+	requestSeq   int64
+	inFlight     int64
+	toolCount    int64
+	budget       *callBudget
+	limiter      *rateLimiter
+	debugServer  *http.Server
+	chaos        *chaosState
+	confirm      *confirmQueue
+	sessionLog   *sessionLog
+	audit        *auditTrail
+	backend      *client.BobaClient
+	metrics      *proxyMetrics
+	cache        *responseCache // nil when caching is off
+	schemas      *toolSchemas   // nil when argument checking is off
+	openMetrics  bool           // serve /metrics without the session token
+	debugArgs    bool           // BOBA_DEBUG=1: show autofilled params in the log
+	portFallback bool           // move to a free port when the configured one is taken
+	stopRefresh  chan struct{}  // stops the token refresher, alert poller and probe
+	background   sync.WaitGroup // those three and schema refreshes, for Stop to wait on
+	shutdown     chan struct{}  // closed by POST /shutdown
+	shutdownOnce sync.Once
+	health       backendHealth
+	manifest     *Manifest // last tool manifest seen, nil before the first fetch
+	manifestMu   sync.Mutex
+	pinned       *Manifest // --strict-tools: the only tools agents may call
+	natives      nativeBalances
+	portfolio    portfolioCache
+	activity     *activityRing // recent requests for GET /activity
+	gasStrict    bool          // --strict-preflight: refuse trades the fee check warns about
 	mu           sync.RWMutex
 }
 
+// sessionTokenGrace is how long the previous proxy's session token stays
+// valid after a restart.
+const sessionTokenGrace = 30 * time.Second
+
 // NewProxyServer creates a new proxy server bound to 127.0.0.1 on the given
-// port. A cryptographically random session token is generated and stored in the
+// port (see SetBindAddress). A cryptographically random session token is generated and stored in the
 // system keyring so that only authorised callers can reach the proxy.
 func NewProxyServer(port int) (*ProxyServer, error) {
 	// Verify the MCP URL uses HTTPS or localhost to prevent credential leakage.
@@ -61,16 +113,41 @@ func NewProxyServer(port int) (*ProxyServer, error) {
 	}
 
 	s := &ProxyServer{
+		host:         "127.0.0.1",
 		port:         port,
 		sessionToken: sessionToken,
 		logChan:      make(chan LogEntry, 100),
+		budget:       newCallBudget(config.GetToolCallBudget(), config.GetToolCallHardCap()),
+		limiter:      newRateLimiter(DefaultRateLimit()),
+		metrics:      newProxyMetrics(),
+		cache:        newResponseCache(),
+		schemas:      newToolSchemas(),
+		activity:     newActivityRing(config.GetLogHistory()),
+		backend:      newBackendClient(),
+		debugArgs:    os.Getenv("BOBA_DEBUG") == "1",
+		shutdown:     make(chan struct{}),
+	}
+
+	// Keep accepting the previous proxy's token for a moment after a restart
+	// so bridges mid-conversation don't lose tool access.
+	if prev, retiredAt := config.TakeRetiredSessionToken(); prev != "" && time.Since(retiredAt) < sessionTokenGrace {
+		s.graceToken = prev
+		s.graceUntil = retiredAt.Add(sessionTokenGrace)
 	}
 
 	mux := http.NewServeMux()
-	mux.HandleFunc("GET /health", s.handleHealth)
+	mux.HandleFunc("GET /health", s.openRoute(s.handleHealth))
+	mux.HandleFunc("GET /livez", s.handleLivez)
 	mux.HandleFunc("GET /tools", s.withAuth(s.handleTools))
 	mux.HandleFunc("POST /call", s.withAuth(s.handleCall))
 	mux.HandleFunc("GET /stream", s.withAuth(s.handleStream))
+	mux.HandleFunc("GET /activity", s.withAuth(s.handleActivity))
+	mux.HandleFunc("GET /portfolio", s.withAuth(s.handlePortfolio))
+	mux.HandleFunc("POST /bridge-log", s.withAuth(s.handleBridgeLog))
+	mux.HandleFunc("POST /budget/reset", s.withAuth(s.handleBudgetReset))
+	mux.HandleFunc("POST /reload", s.withAuth(s.handleReload))
+	mux.HandleFunc("POST /shutdown", s.withAuth(s.handleShutdown))
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
 
 	s.server = &http.Server{
 		Addr:              fmt.Sprintf("127.0.0.1:%d", port),
@@ -83,13 +160,43 @@ func NewProxyServer(port int) (*ProxyServer, error) {
 	return s, nil
 }
 
+// portFallbackRange is how many ports from the configured one Start tries
+// before settling for any free port.
+const portFallbackRange = 10
+
+// SetPortFallback lets Start move to a nearby free port, or any free port,
+// when the configured one is taken. It must be called before Start.
+func (s *ProxyServer) SetPortFallback(fallback bool) {
+	s.portFallback = fallback
+}
+
 // Start begins listening for connections in a background goroutine. It returns
-// an error if the listener cannot be created.
+// an error if the listener cannot be created. Port 0 picks any free port; the
+// bound port and this process are recorded in the proxy lock for `boba mcp`,
+// `boba status` and `boba launch` to find, and for `boba start` to detect a
+// second proxy.
 func (s *ProxyServer) Start() error {
-	ln, err := net.Listen("tcp", s.server.Addr)
+	ln, err := s.listen()
 	if err != nil {
 		return fmt.Errorf("failed to listen on %s: %w", s.server.Addr, err)
 	}
+	s.port = ln.Addr().(*net.TCPAddr).Port
+	s.server.Addr = ln.Addr().String()
+	if s.needsLoopback() {
+		loopback, err := s.listenLoopback()
+		if err != nil {
+			ln.Close()
+			return err
+		}
+		go func() {
+			if err := s.server.Serve(loopback); err != nil && err != http.ErrServerClosed {
+				logger.Error("proxy server error", "error", err)
+			}
+		}()
+	}
+	if err := config.WriteProxyLock(s.port); err != nil {
+		logger.Warn("could not write the proxy lock", "error", err)
+	}
 
 	go func() {
 		if err := s.server.Serve(ln); err != nil && err != http.ErrServerClosed {
@@ -97,23 +204,79 @@ func (s *ProxyServer) Start() error {
 		}
 	}()
 
+	stop := make(chan struct{})
+	s.stopRefresh = stop
+	for _, run := range []func(<-chan struct{}){s.refreshTokens, s.watchAlerts, s.probeBackend} {
+		s.background.Add(1)
+		go func() {
+			defer s.background.Done()
+			run(stop)
+		}()
+	}
+
 	return nil
 }
 
-// Stop gracefully shuts down the proxy server with a 5-second deadline and
-// clears the session token from the system keyring.
+// listen binds the configured port, falling back to the next few ports and
+// then to any free port when allowed.
+func (s *ProxyServer) listen() (net.Listener, error) {
+	ln, err := net.Listen("tcp", s.server.Addr)
+	if err == nil || !s.portFallback || s.port == 0 {
+		return ln, err
+	}
+	for port := s.port + 1; port < s.port+portFallbackRange; port++ {
+		if fallback, ferr := net.Listen("tcp", net.JoinHostPort(s.host, strconv.Itoa(port))); ferr == nil {
+			return fallback, nil
+		}
+	}
+	if fallback, ferr := net.Listen("tcp", net.JoinHostPort(s.host, "0")); ferr == nil {
+		return fallback, nil
+	}
+	return nil, err
+}
+
+// Stop gracefully shuts down the proxy server with a 5-second deadline,
+// waits for the goroutines Start launched, and retires the session token in
+// the system keyring.
 func (s *ProxyServer) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	err := s.server.Shutdown(ctx)
+	if s.stopRefresh != nil {
+		close(s.stopRefresh)
+		s.stopRefresh = nil
+	}
+	s.background.Wait()
+	s.stopDebugServer(ctx)
+	if s.sessionLog != nil {
+		s.sessionLog.close()
+	}
+	s.audit.close()
+
+	config.ReleaseProxyLock(s.port)
 
-	// Always attempt to clear the session token, even if shutdown had an error.
-	_ = config.ClearSessionToken()
+	// Always retire the session token, even if shutdown had an error. It
+	// is only honored by a proxy started within the grace period.
+	_ = config.RetireSessionToken()
 
 	return err
 }
 
+// ShutdownRequested is closed once a client asks the proxy to stop, as
+// `boba stop` does. Whoever started the proxy should then call Stop.
+func (s *ProxyServer) ShutdownRequested() <-chan struct{} {
+	return s.shutdown
+}
+
+// handleShutdown passes a stop request on to whoever runs the proxy. The
+// reply goes out before Stop drains connections.
+func (s *ProxyServer) handleShutdown(w http.ResponseWriter, r *http.Request) {
+	logger.Info("shutdown requested")
+	s.shutdownOnce.Do(func() { close(s.shutdown) })
+	w.WriteHeader(http.StatusAccepted)
+}
+
 // LogChannel returns a read-only channel that receives log entries for every
 // proxied request.
 func (s *ProxyServer) LogChannel() <-chan LogEntry {
@@ -138,6 +301,20 @@ func (s *ProxyServer) sendLog(entry LogEntry) {
 	if entry.Timestamp.IsZero() {
 		entry.Timestamp = time.Now()
 	}
+	if s.debugArgs && entry.Injected == nil {
+		entry.Injected = Injected(entry.Modifications)
+	}
+	if n := s.errRun.note(entry); n > 1 {
+		entry.Repeat = n
+	}
+	// Progress updates of a stream are left out; its final entry has the
+	// totals.
+	if s.sessionLog != nil && entry.Status != StatusStreaming {
+		s.sessionLog.write(entry)
+	}
+	if s.activity != nil {
+		s.activity.add(entry)
+	}
 	select {
 	case s.logChan <- entry:
 	default:
@@ -145,6 +322,18 @@ func (s *ProxyServer) sendLog(entry LogEntry) {
 	}
 }
 
+// Log adds an entry to the activity log, for actions taken from the
+// dashboard rather than by an agent.
+func (s *ProxyServer) Log(entry LogEntry) {
+	s.sendLog(entry)
+}
+
+// nextRequestID returns a new ID tying together the log entries of one
+// request.
+func (s *ProxyServer) nextRequestID() string {
+	return strconv.FormatInt(atomic.AddInt64(&s.requestSeq, 1), 10)
+}
+
 // incrementRequests atomically increments and returns the new request count.
 func (s *ProxyServer) incrementRequests() int64 {
 	return atomic.AddInt64(&s.requestCount, 1)
@@ -157,38 +346,85 @@ func (s *ProxyServer) getRequestCount() int64 {
 
 // CallTool makes an MCP tool call directly, bypassing the HTTP layer. This is
 // used by the TUI for background polling (e.g. portfolio updates) without going
-// through the HTTP loopback. It handles authentication, parameter auto-fill,
-// and retries once on 401/403 — the same logic as handleCall.
-func (s *ProxyServer) CallTool(tool string, args map[string]any) ([]byte, error) {
-	tokens, err := auth.EnsureAuthenticated()
+// through the HTTP loopback. It handles authentication and parameter
+// auto-fill like handleCall; a non-2xx answer is a *client.UpstreamError.
+// Errors end with the request ID, which the backend's logs know it by.
+func (s *ProxyServer) CallTool(ctx context.Context, tool string, args map[string]any) ([]byte, error) {
+	tokens, err := s.backend.Token()
 	if err != nil {
-		return nil, fmt.Errorf("authentication failed: %w", err)
+		return nil, err
 	}
 
 	AutoFillParams(tool, args, tokens)
 
-	respBody, statusCode, err := s.doMCPCall(tool, args, tokens)
+	if client.RequestID(ctx) == "" {
+		ctx = client.WithRequestID(ctx, client.NewRequestID())
+	}
+	resp, err := s.doMCPCall(ctx, tool, args)
 	if err != nil {
-		return nil, fmt.Errorf("upstream request failed: %w", err)
+		return nil, fmt.Errorf("%w (request ID %s)", err, resp.RequestID(client.RequestID(ctx)))
 	}
 
-	// Retry once on auth errors.
-	if statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden {
-		logger.Debug("CallTool: auth error from upstream, re-authenticating", "status", statusCode)
-		newTokens, authErr := auth.Authenticate()
-		if authErr != nil {
-			return nil, fmt.Errorf("re-authentication failed: %w", authErr)
-		}
-		AutoFillParams(tool, args, newTokens)
-		respBody, statusCode, err = s.doMCPCall(tool, args, newTokens)
-		if err != nil {
-			return nil, fmt.Errorf("upstream request failed after retry: %w", err)
-		}
+	if tool == "get_portfolio" {
+		s.metrics.portfolioPolled()
+		s.sawPortfolio(args, resp.Body)
 	}
+	return resp.Body, nil
+}
 
-	if statusCode < 200 || statusCode >= 300 {
-		return nil, fmt.Errorf("upstream returned status %d: %s", statusCode, string(respBody))
+// newBackendClient returns a backend client that authenticates with the
+// stored credentials.
+func newBackendClient() *client.BobaClient {
+	c := client.New(client.TokenFunc(auth.EnsureAuthenticated), client.TokenFunc(auth.Reauthenticate))
+	c.MaxResponseSize = config.GetMaxResponseSize()
+	return c
+}
+
+// SetMaxResponseSize sets the largest backend response, in bytes, a tool
+// call may return; a larger one fails the call instead of being read. Zero
+// means client.DefaultMaxResponseSize. The event stream isn't limited. It
+// must be called before Start.
+func (s *ProxyServer) SetMaxResponseSize(n int64) {
+	s.backend.MaxResponseSize = n
+}
+
+// CallToolDirect makes a one-off tool call to the backend without a running
+// proxy. Cancelling ctx abandons the call.
+func CallToolDirect(ctx context.Context, tool string, args map[string]any) ([]byte, error) {
+	return (&ProxyServer{backend: newBackendClient(), metrics: newProxyMetrics()}).CallTool(ctx, tool, args)
+}
+
+// SyncTools fetches the backend's tool manifest and records how many of its
+// tools the tool policy lets agents see.
+func (s *ProxyServer) SyncTools(ctx context.Context) (int, error) {
+	resp, err := s.backend.ListTools(ctx)
+	s.health.observe(err)
+	if err != nil {
+		return 0, err
+	}
+	s.recordManifest(resp.Status, resp.Body)
+	var manifest struct {
+		Tools []json.RawMessage `json:"tools"`
 	}
+	if err := json.Unmarshal(filterUnpinned(filterToolList(resp.Body, config.GetToolPolicy()), s.pinned), &manifest); err != nil {
+		return 0, fmt.Errorf("invalid tool manifest: %w", err)
+	}
+	atomic.StoreInt64(&s.toolCount, int64(len(manifest.Tools)))
+	return len(manifest.Tools), nil
+}
+
+// ToolCount returns how many tools the last SyncTools found, or 0 before it
+// has run.
+func (s *ProxyServer) ToolCount() int {
+	return int(atomic.LoadInt64(&s.toolCount))
+}
 
-	return respBody, nil
+// ListToolsDirect fetches the backend's tool manifest without a running
+// proxy. Unlike /tools it isn't filtered by the tool policy.
+func ListToolsDirect(ctx context.Context) ([]byte, error) {
+	resp, err := newBackendClient().ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
 }
@@ -0,0 +1,92 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/client"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/zalando/go-keyring"
+)
+
+// testToken is the session token of the proxies newTestServer makes.
+const testToken = "test-session-token"
+
+// fakeBackend stands in for the MCP backend. Tool calls get reply, or
+// {"success":true} when it is empty; the stream sends one event.
+type fakeBackend struct {
+	calls   atomic.Int64
+	streams atomic.Int64
+	reply   func(w http.ResponseWriter, r *http.Request)
+}
+
+func (b *fakeBackend) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/call":
+		b.calls.Add(1)
+		if b.reply != nil {
+			b.reply(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"success":true}`)
+	case "/stream":
+		b.streams.Add(1)
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: {\"price\":1}\n\n")
+	case "/tools":
+		io.WriteString(w, `{"tools":[]}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// newTestServer returns a proxy in front of backend, with its config and
+// keyring kept apart from the user's. It isn't listening; requests go to
+// its handler.
+func newTestServer(t *testing.T, backend http.Handler) *ProxyServer {
+	t.Helper()
+	keyring.MockInit()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	config.UseDir(filepath.Join(home, ".config"))
+
+	up := httptest.NewServer(backend)
+	t.Cleanup(up.Close)
+
+	s, err := NewProxyServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.sessionToken = testToken
+	s.backend = client.New(client.TokenFunc(func() (*config.AuthTokens, error) {
+		return &config.AuthTokens{AccessToken: "access"}, nil
+	}), nil)
+	s.backend.BaseURL = up.URL
+	// Schema refreshes started by calls read the config dir the next
+	// test replaces.
+	t.Cleanup(s.background.Wait)
+	return s
+}
+
+// serve sends a request to the proxy with the session token, or with
+// token when it is given, and returns the recorded response.
+func serve(s *ProxyServer, method, path, body string, token ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	r.RemoteAddr = "127.0.0.1:50000"
+	bearer := testToken
+	if len(token) > 0 {
+		bearer = token[0]
+	}
+	if bearer != "" {
+		r.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	w := httptest.NewRecorder()
+	s.server.Handler.ServeHTTP(w, r)
+	return w
+}
@@ -0,0 +1,218 @@
+package proxy
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/logger"
+)
+
+// Every proxy session appends its log entries to a JSON lines file, so the
+// activity can be reviewed with `boba logs` after the dashboard is gone.
+
+// SessionLogMaxAge is how long session logs are kept. Older files are
+// removed when a new session starts.
+const SessionLogMaxAge = 14 * 24 * time.Hour
+
+// SessionRecord is one log entry as written to a session log.
+type SessionRecord struct {
+	Time          time.Time         `json:"time"`
+	ID            string            `json:"id,omitempty"`
+	Tool          string            `json:"tool"`
+	Status        string            `json:"status"`
+	DurationMs    int64             `json:"durationMs,omitempty"`
+	Preview       string            `json:"preview,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	ErrorKind     string            `json:"errorKind,omitempty"`
+	Cached        bool              `json:"cached,omitempty"`
+	Repeat        int               `json:"repeat,omitempty"`
+	Warning       string            `json:"warning,omitempty"`
+	RequestID     string            `json:"requestId,omitempty"`
+	TxHash        string            `json:"txHash,omitempty"`
+	Modifications []Modification    `json:"modifications,omitempty"` // only with BOBA_DEBUG=1
+	Args          map[string]any    `json:"args,omitempty"`          // only with BOBA_DEBUG=1
+	Injected      map[string]string `json:"injected,omitempty"`      // only with BOBA_DEBUG=1
+}
+
+// LogEntry converts the record back into the entry it was written from.
+func (r SessionRecord) LogEntry() LogEntry {
+	return LogEntry{
+		ID:            r.ID,
+		Tool:          r.Tool,
+		Status:        r.Status,
+		Duration:      time.Duration(r.DurationMs) * time.Millisecond,
+		Preview:       r.Preview,
+		Timestamp:     r.Time,
+		Error:         r.Error,
+		ErrorKind:     r.ErrorKind,
+		Modifications: r.Modifications,
+		Cached:        r.Cached,
+		Injected:      r.Injected,
+		Repeat:        r.Repeat,
+		Warning:       r.Warning,
+		RequestID:     r.RequestID,
+		TxHash:        r.TxHash,
+	}
+}
+
+// sessionLog appends entries to the current session's file.
+type sessionLog struct {
+	mu    sync.Mutex
+	f     *os.File
+	path  string
+	debug bool
+}
+
+// SessionLogDir returns the directory holding the session logs.
+func SessionLogDir() string {
+	return filepath.Join(config.DataDir(), "logs")
+}
+
+// EnableSessionLog starts writing log entries to a new session file and
+// removes files older than SessionLogMaxAge. It returns the file's path.
+// Arguments and autofill changes can hold wallet addresses and amounts, so
+// they are only written when BOBA_DEBUG=1.
+func (s *ProxyServer) EnableSessionLog() (string, error) {
+	PruneSessionLogs(SessionLogMaxAge)
+
+	name := "session-" + time.Now().Format("20060102-150405") + ".jsonl"
+	path := filepath.Join(SessionLogDir(), name)
+	f, err := config.OpenPrivateAppend(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open session log: %w", err)
+	}
+	s.sessionLog = &sessionLog{f: f, path: path, debug: os.Getenv("BOBA_DEBUG") == "1"}
+	return path, nil
+}
+
+// Record converts the entry into a session log record. Arguments and
+// autofill changes are only included with debug set.
+func (e LogEntry) Record(debug bool) SessionRecord {
+	rec := SessionRecord{
+		Time:       e.Timestamp,
+		ID:         e.ID,
+		Tool:       e.Tool,
+		Status:     e.Status,
+		DurationMs: e.Duration.Milliseconds(),
+		Preview:    e.Preview,
+		Error:      e.Error,
+		ErrorKind:  e.ErrorKind,
+		Cached:     e.Cached,
+		Repeat:     e.Repeat,
+		Warning:    e.Warning,
+		RequestID:  e.RequestID,
+		TxHash:     e.TxHash,
+	}
+	if debug {
+		rec.Modifications = e.Modifications
+		rec.Args = e.Args
+		rec.Injected = e.Injected
+	}
+	return rec
+}
+
+func (l *sessionLog) write(entry LogEntry) {
+	data, err := json.Marshal(entry.Record(l.debug))
+	if err != nil {
+		logger.Warn("failed to encode session log entry", "error", err)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f == nil {
+		return
+	}
+	if _, err := l.f.Write(append(data, '\n')); err != nil {
+		logger.Warn("failed to write session log", "path", l.path, "error", err)
+	}
+}
+
+func (l *sessionLog) close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.f != nil {
+		l.f.Close()
+		l.f = nil
+	}
+}
+
+// SessionLogs returns the session log files, oldest first.
+func SessionLogs() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(SessionLogDir(), "session-*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	// The timestamp in the name sorts chronologically.
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// FindSessionLog resolves "latest", a file name in the log directory or a
+// path to a session log.
+func FindSessionLog(session string) (string, error) {
+	if session == "" || session == "latest" {
+		paths, err := SessionLogs()
+		if err != nil {
+			return "", err
+		}
+		if len(paths) == 0 {
+			return "", fmt.Errorf("no session logs in %s yet. Run 'boba start' first", SessionLogDir())
+		}
+		return paths[len(paths)-1], nil
+	}
+	if _, err := os.Stat(session); err == nil {
+		return session, nil
+	}
+	path := filepath.Join(SessionLogDir(), filepath.Base(session))
+	if !strings.HasSuffix(path, ".jsonl") {
+		path += ".jsonl"
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("no session log %q", session)
+	}
+	return path, nil
+}
+
+// ReadSessionLog reads a session log, oldest first. Malformed lines, such
+// as one cut short by a crash, are skipped.
+func ReadSessionLog(path string) ([]SessionRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []SessionRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4<<20)
+	for scanner.Scan() {
+		var rec SessionRecord
+		if json.Unmarshal(scanner.Bytes(), &rec) == nil && rec.Tool != "" {
+			records = append(records, rec)
+		}
+	}
+	return records, scanner.Err()
+}
+
+// PruneSessionLogs removes session logs last written more than maxAge ago.
+func PruneSessionLogs(maxAge time.Duration) {
+	paths, err := SessionLogs()
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-maxAge)
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && info.ModTime().Before(cutoff) {
+			_ = os.Remove(p)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// StatusStreaming marks a stream that is relaying events. Its entries carry
+// the running event count and a preview of the latest event, and are
+// repeated as events arrive until the stream ends.
+const StatusStreaming = "streaming"
+
+// streamLogInterval is the least time between streaming log updates, so a
+// busy stream doesn't flood the activity log.
+const streamLogInterval = time.Second
+
+// streamTap watches the SSE bytes relayed to a client, counting events and
+// reporting progress to the activity log.
+type streamTap struct {
+	s     *ProxyServer
+	id    string
+	tool  string
+	start time.Time
+
+	partial  string   // bytes after the last newline
+	data     []string // data lines of the event being read
+	event    string   // event name of the event being read
+	events   int
+	last     string // preview of the latest event
+	reported int    // events count at the last update
+	sentAt   time.Time
+}
+
+func newStreamTap(s *ProxyServer, id, tool string) *streamTap {
+	return &streamTap{s: s, id: id, tool: tool, start: time.Now()}
+}
+
+// feed scans a chunk of the stream for complete events and logs progress
+// at most once per streamLogInterval.
+func (t *streamTap) feed(chunk []byte) {
+	lines := strings.Split(t.partial+string(chunk), "\n")
+	t.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		line = strings.TrimSuffix(line, "\r")
+		switch {
+		case line == "":
+			t.dispatch()
+		case strings.HasPrefix(line, "data:"):
+			t.data = append(t.data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case strings.HasPrefix(line, "event:"):
+			t.event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		}
+	}
+
+	if t.events != t.reported && time.Since(t.sentAt) >= streamLogInterval {
+		t.reported = t.events
+		t.sentAt = time.Now()
+		t.s.sendLog(LogEntry{
+			ID:       t.id,
+			Tool:     t.tool,
+			Status:   StatusStreaming,
+			Duration: time.Since(t.start),
+			Preview:  t.last,
+			Events:   t.events,
+		})
+	}
+}
+
+// dispatch counts the event read so far, if it had any data.
+func (t *streamTap) dispatch() {
+	if len(t.data) > 0 {
+		t.events++
+		t.last = streamEventPreview(t.event, strings.Join(t.data, "\n"))
+	}
+	t.data = nil
+	t.event = ""
+}
+
+// finish logs the end of the stream with its totals. errMsg is empty when
+// the stream ended normally or the client went away.
+func (t *streamTap) finish(errMsg string) {
+	summary := fmt.Sprintf("%d events", t.events)
+	if t.events == 1 {
+		summary = "1 event"
+	}
+	if t.last != "" {
+		summary += ", last: " + t.last
+	}
+	entry := LogEntry{
+		ID:       t.id,
+		Tool:     t.tool,
+		Status:   "success",
+		Duration: time.Since(t.start),
+		Preview:  summary,
+		Events:   t.events,
+	}
+	if errMsg != "" {
+		entry.Status = "error"
+		entry.Error = errMsg + " after " + summary
+	}
+	t.s.sendLog(entry)
+}
+
+// tapWriter feeds a streamTap from an io.TeeReader.
+type tapWriter struct{ t *streamTap }
+
+func (w tapWriter) Write(p []byte) (int, error) {
+	w.t.feed(p)
+	return len(p), nil
+}
+
+// streamEventPreview summarizes an event in a few words, e.g. "$PEPE
+// launched", falling back to the start of its data.
+func streamEventPreview(event, data string) string {
+	var obj map[string]any
+	if json.Unmarshal([]byte(data), &obj) != nil {
+		return truncatePreview(data)
+	}
+	field := func(keys ...string) string {
+		for _, k := range keys {
+			if v, ok := obj[k].(string); ok && v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+	symbol := field("symbol", "token_symbol", "ticker")
+	kind := field("event", "type", "action", "side")
+	if kind == "" && event != "message" {
+		kind = event
+	}
+	switch {
+	case symbol != "" && kind != "":
+		return "$" + symbol + " " + kind
+	case symbol != "":
+		return "$" + symbol
+	case kind != "":
+		return kind
+	}
+	return truncatePreview(data)
+}
+
+func truncatePreview(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len(s) > 40 {
+		return s[:37] + "..."
+	}
+	return s
+}
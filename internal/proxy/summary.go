@@ -0,0 +1,117 @@
+package proxy
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/journal"
+)
+
+// summaryTopTools is how many of the most called tools a session summary
+// lists.
+const summaryTopTools = 5
+
+// SessionSummary recaps a proxy session: what the agent called and what it
+// traded.
+type SessionSummary struct {
+	Started         time.Time     `json:"started"`
+	Duration        time.Duration `json:"-"`
+	DurationSeconds float64       `json:"durationSeconds"`
+	Requests        int64         `json:"requests"`
+	Errors          int64         `json:"errors"`
+	Trades          int64         `json:"trades"`
+	OrdersCreated   int64         `json:"ordersCreated"`
+	OrdersCancelled int64         `json:"ordersCancelled"`
+	VolumeUSD       float64       `json:"volumeUsd"` // 0 when no trade reported its value
+	TopTools        []ToolCount   `json:"topTools"`
+}
+
+// ToolCount is how often the agent called a tool.
+type ToolCount struct {
+	Tool  string `json:"tool"`
+	Calls int64  `json:"calls"`
+}
+
+// sessionTotals are the trading outcomes a session summary reports.
+type sessionTotals struct {
+	trades          int64
+	ordersCreated   int64
+	ordersCancelled int64
+	volumeUSD       float64
+}
+
+// recordSuccess counts what a successful tool call did: an executed trade
+// and the USD value it reports, or an order created or cancelled.
+func (m *proxyMetrics) recordSuccess(tool string, responseData any) {
+	resp, _ := responseData.(map[string]any)
+	if data, ok := resp["data"].(map[string]any); ok {
+		resp = data
+	}
+	if ok, isBool := resp["success"].(bool); isBool && !ok {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case journal.TradeTools[tool]:
+		m.totals.trades++
+		m.totals.volumeUSD += tradeValueUSD(resp)
+	case strings.HasPrefix(tool, "create_") && strings.HasSuffix(tool, "_order"):
+		m.totals.ordersCreated++
+	case strings.HasPrefix(tool, "cancel_") && strings.HasSuffix(tool, "_order"):
+		m.totals.ordersCancelled++
+	}
+}
+
+// tradeValueUSD returns the USD value an executed trade reports, or 0 when
+// it reports none.
+func tradeValueUSD(resp map[string]any) float64 {
+	for _, k := range []string{"amount_usd", "value_usd", "volume_usd", "from_amount_usd", "input_value_usd", "usd_value"} {
+		switch v := resp[k].(type) {
+		case float64:
+			return v
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f
+			}
+		}
+	}
+	return 0
+}
+
+// Summary recaps the session so far. Like the metrics, it counts the
+// agent's calls only, not the dashboard's own.
+func (s *ProxyServer) Summary() SessionSummary {
+	m := s.metrics
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sum := SessionSummary{
+		Started:         m.started,
+		Duration:        time.Since(m.started).Round(time.Second),
+		Trades:          m.totals.trades,
+		OrdersCreated:   m.totals.ordersCreated,
+		OrdersCancelled: m.totals.ordersCancelled,
+		VolumeUSD:       m.totals.volumeUSD,
+	}
+	sum.DurationSeconds = sum.Duration.Seconds()
+	tools := make([]ToolCount, 0, len(m.tools))
+	for name, st := range m.tools {
+		sum.Requests += st.calls + st.cacheHits
+		sum.Errors += st.errorCount()
+		if n := st.calls + st.cacheHits; n > 0 {
+			tools = append(tools, ToolCount{Tool: name, Calls: n})
+		}
+	}
+	sort.Slice(tools, func(i, j int) bool {
+		if tools[i].Calls != tools[j].Calls {
+			return tools[i].Calls > tools[j].Calls
+		}
+		return tools[i].Tool < tools[j].Tool
+	})
+	sum.TopTools = tools[:min(len(tools), summaryTopTools)]
+	return sum
+}
@@ -0,0 +1,88 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/client"
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// Tool call timeout categories. Each can be overridden in the config's
+// "timeouts" object.
+const (
+	TimeoutDefault   = "default"
+	TimeoutLookup    = "lookup"
+	TimeoutPortfolio = "portfolio"
+	TimeoutAudit     = "audit"
+	TimeoutTrade     = "trade"
+)
+
+// DefaultTimeouts are how long a tool call may take, by category, before
+// the proxy gives up on it.
+var DefaultTimeouts = map[string]time.Duration{
+	TimeoutDefault:   client.CallTimeout,
+	TimeoutLookup:    15 * time.Second,
+	TimeoutPortfolio: 30 * time.Second,
+	TimeoutAudit:     120 * time.Second,
+	TimeoutTrade:     120 * time.Second,
+}
+
+// MaxCallTimeout is the longest a tool call timeout can be configured to.
+const MaxCallTimeout = 300 * time.Second
+
+// TimeoutCategories lists the categories in the order they are shown.
+var TimeoutCategories = []string{TimeoutDefault, TimeoutLookup, TimeoutPortfolio, TimeoutAudit, TimeoutTrade}
+
+// TimeoutCategory returns the timeout category of tool.
+func TimeoutCategory(tool string) string {
+	switch {
+	case NeedsConfirmation(tool):
+		return TimeoutTrade
+	case strings.Contains(tool, "audit"):
+		return TimeoutAudit
+	case strings.Contains(tool, "portfolio"), strings.Contains(tool, "pnl"), strings.Contains(tool, "balance"),
+		strings.HasSuffix(tool, "_orders"), strings.HasSuffix(tool, "_history"), strings.HasPrefix(tool, "get_user_"):
+		return TimeoutPortfolio
+	case strings.HasPrefix(tool, "search_"), strings.Contains(tool, "token"), strings.Contains(tool, "price"),
+		strings.Contains(tool, "launches"):
+		return TimeoutLookup
+	}
+	return TimeoutDefault
+}
+
+// CallTimeout returns how long a call to tool may take: the configured
+// timeout of its category, or the built-in one.
+func CallTimeout(tool string) time.Duration {
+	category := TimeoutCategory(tool)
+	if d := config.GetTimeout(category); d > 0 {
+		return d
+	}
+	return DefaultTimeouts[category]
+}
+
+// TimeoutError means a tool call didn't finish within its timeout.
+type TimeoutError struct {
+	After time.Duration
+	Err   error
+}
+
+func (e *TimeoutError) Error() string { return "timed out after " + e.After.String() }
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// retryableFailure reports whether a failed call to tool may be made again:
+// only read-only tools are, and only when the backend couldn't be reached.
+// A call that ran out of time is not retried.
+func retryableFailure(ctx context.Context, tool string, err error) bool {
+	var transport *client.TransportError
+	return !NeedsConfirmation(tool) && errors.As(err, &transport) && ctx.Err() == nil
+}
+
+// retryBackoff is the pause before retrying a call, jittered so clients
+// that failed together don't retry together.
+func retryBackoff() time.Duration {
+	return 250*time.Millisecond + time.Duration(rand.Int63n(int64(500*time.Millisecond)))
+}
@@ -0,0 +1,262 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/logger"
+)
+
+// How often the tool schemas are fetched again: every schemaRefresh, and
+// at most every schemaRetry when a call names a tool without one. A fetch
+// is given schemaFetchTimeout.
+const (
+	schemaRefresh      = time.Hour
+	schemaRetry        = time.Minute
+	schemaFetchTimeout = 15 * time.Second
+)
+
+// argSchema is the part of a JSON schema arguments are checked against:
+// type, required and enum, applied to nested properties and array items.
+// Everything else in the schema is ignored.
+type argSchema struct {
+	Type       schemaTypes           `json:"type"`
+	Required   []string              `json:"required"`
+	Enum       []any                 `json:"enum"`
+	Properties map[string]*argSchema `json:"properties"`
+	Items      *argSchema            `json:"items"`
+}
+
+// schemaTypes is a schema's type, which may be one name or a list of them.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var one string
+	if json.Unmarshal(data, &one) == nil {
+		*t = schemaTypes{one}
+		return nil
+	}
+	var list []string
+	if json.Unmarshal(data, &list) == nil {
+		*t = list
+	}
+	// Anything else can't be checked, so it allows every type.
+	return nil
+}
+
+// toolSchemas keeps the input schema of each tool in the last manifest, so
+// calls can be checked before they are forwarded.
+type toolSchemas struct {
+	mu      sync.Mutex
+	byTool  map[string]*argSchema
+	fetched time.Time // when byTool was last replaced
+	tried   time.Time // when a refresh was last started
+	loading bool
+}
+
+func newToolSchemas() *toolSchemas {
+	return &toolSchemas{byTool: make(map[string]*argSchema)}
+}
+
+// DisableValidation forwards calls without checking their arguments. It
+// must be called before Start.
+func (s *ProxyServer) DisableValidation() {
+	s.schemas = nil
+}
+
+// record replaces the schemas with those of a tools/list response. A tool
+// whose schema can't be read is left unchecked.
+func (t *toolSchemas) record(body []byte) {
+	var list struct {
+		Tools []struct {
+			Name        string          `json:"name"`
+			InputSchema json.RawMessage `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return
+	}
+	byTool := make(map[string]*argSchema, len(list.Tools))
+	for _, tool := range list.Tools {
+		var schema argSchema
+		if tool.Name == "" || json.Unmarshal(tool.InputSchema, &schema) != nil {
+			continue
+		}
+		byTool[tool.Name] = &schema
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.byTool = byTool
+	t.fetched = time.Now()
+}
+
+// schemaFor returns the input schema of tool, or nil when there is none to
+// check against. Stale schemas, or a tool missing from them, start a
+// refresh in the background; calls made meanwhile go unchecked or use the
+// old schema.
+func (s *ProxyServer) schemaFor(tool string) *argSchema {
+	t := s.schemas
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	schema := t.byTool[tool]
+	now := time.Now()
+	stale := now.Sub(t.fetched) > schemaRefresh
+	if !t.loading && (stale || schema == nil) && now.Sub(t.tried) > schemaRetry {
+		t.loading, t.tried = true, now
+		s.background.Add(1)
+		go s.refreshSchemas(t)
+	}
+	return schema
+}
+
+// refreshSchemas fetches the tool manifest again, recording the schemas
+// along with it, and marks t as no longer loading.
+func (s *ProxyServer) refreshSchemas(t *toolSchemas) {
+	defer s.background.Done()
+	defer func() {
+		t.mu.Lock()
+		t.loading = false
+		t.mu.Unlock()
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), schemaFetchTimeout)
+	defer cancel()
+	resp, err := s.backend.ListTools(ctx)
+	s.health.observe(err)
+	if err != nil {
+		logger.Debug("could not refresh tool schemas", "error", err)
+		return
+	}
+	s.recordManifest(resp.Status, resp.Body)
+}
+
+// validateArgs checks args against schema and returns what is wrong with
+// them, e.g. "arguments.amount: expected string, got number", sorted by
+// path.
+func validateArgs(schema *argSchema, args map[string]any) []string {
+	var violations []string
+	schema.check("arguments", args, &violations)
+	sort.Strings(violations)
+	return violations
+}
+
+func (sc *argSchema) check(path string, v any, violations *[]string) {
+	if len(sc.Type) > 0 && !sc.allows(v) {
+		*violations = append(*violations, fmt.Sprintf("%s: expected %s, got %s", path, strings.Join(sc.Type, " or "), jsonType(v)))
+		return
+	}
+	if len(sc.Enum) > 0 && !inEnum(sc.Enum, v) {
+		*violations = append(*violations, fmt.Sprintf("%s: must be one of %s", path, enumList(sc.Enum)))
+		return
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		for _, name := range sc.Required {
+			if _, ok := v[name]; !ok {
+				*violations = append(*violations, path+"."+name+": required")
+			}
+		}
+		for name, value := range v {
+			if prop := sc.Properties[name]; prop != nil {
+				prop.check(path+"."+name, value, violations)
+			}
+		}
+	case []any:
+		if sc.Items != nil {
+			for i, item := range v {
+				sc.Items.check(fmt.Sprintf("%s[%d]", path, i), item, violations)
+			}
+		}
+	}
+}
+
+// allows reports whether v has one of the schema's types. Types this
+// validator doesn't know allow anything.
+func (sc *argSchema) allows(v any) bool {
+	got := jsonType(v)
+	for _, want := range sc.Type {
+		switch want {
+		case got:
+			return true
+		case "integer":
+			if f, ok := v.(float64); ok && f == math.Trunc(f) && !math.IsInf(f, 0) {
+				return true
+			}
+		case "string", "number", "boolean", "object", "array", "null":
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64, json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func inEnum(enum []any, v any) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// enumList lists the allowed values as JSON, e.g. "solana", "base".
+func enumList(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		b, _ := json.Marshal(e)
+		parts[i] = string(b)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// refuseInvalidArgs answers a call whose arguments don't match the tool's
+// schema, listing what to fix instead of forwarding it for a vaguer 400
+// from the backend.
+func (s *ProxyServer) refuseInvalidArgs(w http.ResponseWriter, id, toolName, reqID string, violations []string, mods []Modification) {
+	summary := strings.Join(violations, "; ")
+	s.sendLog(LogEntry{
+		ID:            id,
+		Tool:          toolName,
+		Status:        "error",
+		Error:         "invalid arguments: " + summary,
+		Modifications: mods,
+		RequestID:     reqID,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error":      "invalid_arguments",
+		"message":    fmt.Sprintf("%s was not called; its arguments don't match the tool's input schema: %s. Fix them and call it again.", toolName, summary),
+		"violations": violations,
+		"requestId":  reqID,
+	})
+}
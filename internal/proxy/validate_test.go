@@ -0,0 +1,158 @@
+package proxy
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// swapSchema is an input schema using every part the validator checks.
+const swapSchema = `{
+	"type": "object",
+	"required": ["chain", "amount", "from_token"],
+	"properties": {
+		"chain": {"type": "string", "enum": ["solana", "base"]},
+		"amount": {"type": "string"},
+		"from_token": {"type": "string"},
+		"slippage_bps": {"type": "integer"},
+		"memo": {"type": ["string", "null"]},
+		"route": {
+			"type": "object",
+			"required": ["dex"],
+			"properties": {"dex": {"type": "string"}}
+		},
+		"legs": {"type": "array", "items": {"type": "object", "required": ["token"], "properties": {"token": {"type": "string"}}}},
+		"extra": {"type": "decimal"},
+		"anything": {}
+	}
+}`
+
+func mustSchema(t *testing.T, s string) *argSchema {
+	t.Helper()
+	var schema argSchema
+	if err := json.Unmarshal([]byte(s), &schema); err != nil {
+		t.Fatal(err)
+	}
+	return &schema
+}
+
+func TestValidateArgs(t *testing.T) {
+	schema := mustSchema(t, swapSchema)
+	valid := func() map[string]any {
+		return map[string]any{"chain": "solana", "amount": "1.5", "from_token": "SOL"}
+	}
+	for _, tc := range []struct {
+		name string
+		edit func(map[string]any)
+		want []string
+	}{
+		{"valid", func(map[string]any) {}, nil},
+		{"number for a string, missing chain", func(a map[string]any) { a["amount"] = 1.5; delete(a, "chain") },
+			[]string{"arguments.amount: expected string, got number", "arguments.chain: required"}},
+		{"not in enum", func(a map[string]any) { a["chain"] = "doge" },
+			[]string{`arguments.chain: must be one of "solana", "base"`}},
+		{"integer", func(a map[string]any) { a["slippage_bps"] = 50.0 }, nil},
+		{"fraction for an integer", func(a map[string]any) { a["slippage_bps"] = 0.5 },
+			[]string{"arguments.slippage_bps: expected integer, got number"}},
+		{"one of two types", func(a map[string]any) { a["memo"] = nil }, nil},
+		{"neither type", func(a map[string]any) { a["memo"] = true },
+			[]string{"arguments.memo: expected string or null, got boolean"}},
+		{"nested", func(a map[string]any) { a["route"] = map[string]any{"dex": 1.0} },
+			[]string{"arguments.route.dex: expected string, got number"}},
+		{"nested required", func(a map[string]any) { a["route"] = map[string]any{} },
+			[]string{"arguments.route.dex: required"}},
+		{"array items", func(a map[string]any) {
+			a["legs"] = []any{map[string]any{"token": "SOL"}, map[string]any{}, "WIF"}
+		}, []string{"arguments.legs[1].token: required", "arguments.legs[2]: expected object, got string"}},
+		{"unknown type allows anything", func(a map[string]any) { a["extra"] = "1.0" }, nil},
+		{"no type allows anything", func(a map[string]any) { a["anything"] = []any{1.0} }, nil},
+		{"properties not in the schema", func(a map[string]any) { a["unlisted"] = 1.0 }, nil},
+	} {
+		args := valid()
+		tc.edit(args)
+		if got := validateArgs(schema, args); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestSchemaTypes(t *testing.T) {
+	for in, want := range map[string]schemaTypes{
+		`"string"`:           {"string"},
+		`["string", "null"]`: {"string", "null"},
+		`{"oneOf": []}`:      nil,
+		`3`:                  nil,
+	} {
+		var got schemaTypes
+		if err := json.Unmarshal([]byte(in), &got); err != nil || !slices.Equal(got, want) {
+			t.Errorf("%s: %q, %v; want %q", in, got, err, want)
+		}
+	}
+}
+
+// schemaBackend lists one tool, execute_swap, with swapSchema, and answers
+// calls like fakeBackend.
+func schemaBackend() (*fakeBackend, http.Handler) {
+	calls := &fakeBackend{}
+	return calls, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tools" {
+			io.WriteString(w, `{"tools":[{"name":"execute_swap","inputSchema":`+swapSchema+`},{"name":"broken","inputSchema":"x"}]}`)
+			return
+		}
+		calls.ServeHTTP(w, r)
+	})
+}
+
+// Calls whose arguments don't match the tool's schema are refused with the
+// violations listed; unknown tools and those without a readable schema
+// pass through, as does everything with validation off.
+func TestValidateCall(t *testing.T) {
+	backend, handler := schemaBackend()
+	s := newTestServer(t, handler)
+	resp, err := s.backend.ListTools(t.Context())
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.recordManifest(resp.Status, resp.Body)
+
+	w := serve(s, "POST", "/call", `{"tool":"execute_swap","args":{"amount":1.5,"from_token":"SOL"}}`)
+	var body struct {
+		Error      string   `json:"error"`
+		Message    string   `json:"message"`
+		Violations []string `json:"violations"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	want := []string{"arguments.amount: expected string, got number", "arguments.chain: required"}
+	if w.Code != http.StatusBadRequest || body.Error != "invalid_arguments" || !slices.Equal(body.Violations, want) {
+		t.Errorf("invalid call: %d %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(body.Message, strings.Join(want, "; ")) {
+		t.Errorf("message = %q", body.Message)
+	}
+	if n := backend.calls.Load(); n != 0 {
+		t.Errorf("invalid call forwarded")
+	}
+	if e := lastEntry(t, s); e.Status != "error" || !strings.HasPrefix(e.Error, "invalid arguments: arguments.amount") {
+		t.Errorf("logged %s %q", e.Status, e.Error)
+	}
+
+	for _, call := range []string{
+		`{"tool":"execute_swap","args":{"chain":"base","amount":"1.5","from_token":"ETH"}}`,
+		`{"tool":"broken","args":{"amount":1}}`,
+		`{"tool":"get_token_info","args":{"amount":1}}`,
+	} {
+		if w := serve(s, "POST", "/call", call); w.Code != http.StatusOK {
+			t.Errorf("%s: %d %s", call, w.Code, w.Body.String())
+		}
+	}
+
+	// Let the schema refreshes those calls started finish first.
+	s.background.Wait()
+	s.DisableValidation()
+	if w := serve(s, "POST", "/call", `{"tool":"execute_swap","args":{"amount":1}}`); w.Code != http.StatusOK {
+		t.Errorf("with validation off: %d %s", w.Code, w.Body.String())
+	}
+}
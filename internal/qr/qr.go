@@ -0,0 +1,486 @@
+// Package qr encodes short strings, such as wallet addresses, as QR codes
+// and renders them for a terminal. It supports byte mode at error
+// correction level M in versions 1 to 10, which is enough for anything up
+// to 213 bytes.
+package qr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// block layout for one version at level M: error correction codewords per
+// block and the data codewords in each block.
+type version struct {
+	ecPerBlock int
+	blocks     []int
+	align      []int
+}
+
+var versions = []version{
+	1:  {10, []int{16}, nil},
+	2:  {16, []int{28}, []int{6, 18}},
+	3:  {26, []int{44}, []int{6, 22}},
+	4:  {18, []int{32, 32}, []int{6, 26}},
+	5:  {24, []int{43, 43}, []int{6, 30}},
+	6:  {16, []int{27, 27, 27, 27}, []int{6, 34}},
+	7:  {18, []int{31, 31, 31, 31}, []int{6, 22, 38}},
+	8:  {22, []int{38, 38, 39, 39}, []int{6, 24, 42}},
+	9:  {22, []int{36, 36, 36, 37, 37}, []int{6, 26, 46}},
+	10: {26, []int{43, 43, 43, 43, 44}, []int{6, 28, 50}},
+}
+
+func (v version) dataCodewords() int {
+	n := 0
+	for _, b := range v.blocks {
+		n += b
+	}
+	return n
+}
+
+// Code is an encoded QR symbol.
+type Code struct {
+	// Size is the width and height in modules, without a quiet zone.
+	Size     int
+	dark     []bool
+	function []bool
+}
+
+// Black reports whether the module at column x, row y is dark.
+func (c *Code) Black(x, y int) bool {
+	if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+		return false
+	}
+	return c.dark[y*c.Size+x]
+}
+
+// Encode returns the smallest QR code that holds text, with the mask that
+// scores best under the standard penalty rules.
+func Encode(text string) (*Code, error) {
+	return encode(text, -1)
+}
+
+func encode(text string, mask int) (*Code, error) {
+	ver := 0
+	for v := 1; v < len(versions); v++ {
+		if len(text) <= capacity(v) {
+			ver = v
+			break
+		}
+	}
+	if ver == 0 {
+		return nil, fmt.Errorf("qr: %d bytes is too long to encode", len(text))
+	}
+
+	data := dataCodewords(text, ver)
+	c := newCode(ver)
+	c.placeData(interleave(data, versions[ver]))
+
+	if mask < 0 {
+		best := -1
+		for m := 0; m < 8; m++ {
+			c.applyMask(m)
+			c.drawFormat(m)
+			if p := c.penalty(); best < 0 || p < best {
+				best, mask = p, m
+			}
+			c.applyMask(m) // masking is its own inverse
+		}
+	}
+	c.applyMask(mask)
+	c.drawFormat(mask)
+	return c, nil
+}
+
+// capacity is how many bytes fit in version v once the mode indicator and
+// character count are taken out.
+func capacity(v int) int {
+	return (versions[v].dataCodewords()*8 - 4 - countBits(v)) / 8
+}
+
+func countBits(v int) int {
+	if v < 10 {
+		return 8
+	}
+	return 16
+}
+
+// dataCodewords builds the byte mode segment for text, padded out to the
+// data capacity of version v.
+func dataCodewords(text string, v int) []byte {
+	var bb bitBuffer
+	bb.append(0b0100, 4)
+	bb.append(len(text), countBits(v))
+	for i := 0; i < len(text); i++ {
+		bb.append(int(text[i]), 8)
+	}
+
+	total := versions[v].dataCodewords() * 8
+	bb.append(0, min(4, total-len(bb)))
+	bb.append(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < total; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+	return bb.bytes()
+}
+
+type bitBuffer []bool
+
+func (bb *bitBuffer) append(val, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*bb = append(*bb, (val>>i)&1 == 1)
+	}
+}
+
+func (bb bitBuffer) bytes() []byte {
+	out := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out
+}
+
+// interleave splits data into blocks, adds error correction to each and
+// interleaves the result in the order the codewords are placed.
+func interleave(data []byte, v version) []byte {
+	gen := rsGenerator(v.ecPerBlock)
+	var blocks, ecs [][]byte
+	longest := 0
+	for _, n := range v.blocks {
+		blocks = append(blocks, data[:n])
+		ecs = append(ecs, rsRemainder(data[:n], gen))
+		data = data[n:]
+		longest = max(longest, n)
+	}
+
+	var out []byte
+	for i := 0; i < longest; i++ {
+		for _, b := range blocks {
+			if i < len(b) {
+				out = append(out, b[i])
+			}
+		}
+	}
+	for i := 0; i < v.ecPerBlock; i++ {
+		for _, ec := range ecs {
+			out = append(out, ec[i])
+		}
+	}
+	return out
+}
+
+// gfMul multiplies in GF(256) with the QR reducing polynomial 0x11D.
+func gfMul(x, y byte) byte {
+	var z int
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>i)&1) * int(x)
+	}
+	return byte(z)
+}
+
+// rsGenerator returns the coefficients of the Reed-Solomon generator
+// polynomial of the given degree, highest power first, leading 1 omitted.
+func rsGenerator(degree int) []byte {
+	gen := make([]byte, degree)
+	gen[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range gen {
+			gen[j] = gfMul(gen[j], root)
+			if j+1 < len(gen) {
+				gen[j] ^= gen[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+	return gen
+}
+
+func rsRemainder(data, gen []byte) []byte {
+	rem := make([]byte, len(gen))
+	for _, b := range data {
+		factor := b ^ rem[0]
+		copy(rem, rem[1:])
+		rem[len(rem)-1] = 0
+		for i := range rem {
+			rem[i] ^= gfMul(gen[i], factor)
+		}
+	}
+	return rem
+}
+
+// newCode draws the function patterns for version v.
+func newCode(v int) *Code {
+	size := 17 + 4*v
+	c := &Code{Size: size, dark: make([]bool, size*size), function: make([]bool, size*size)}
+
+	for i := 0; i < size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+	c.drawFinder(3, 3)
+	c.drawFinder(size-4, 3)
+	c.drawFinder(3, size-4)
+
+	align := versions[v].align
+	last := len(align) - 1
+	for i, ax := range align {
+		for j, ay := range align {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			c.drawAlignment(ax, ay)
+		}
+	}
+
+	c.drawFormat(0) // reserve the format area; redrawn once the mask is known
+	c.drawVersion(v)
+	return c
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.dark[y*c.Size+x] = dark
+	c.function[y*c.Size+x] = true
+}
+
+func (c *Code) drawFinder(cx, cy int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			x, y := cx+dx, cy+dy
+			if x < 0 || y < 0 || x >= c.Size || y >= c.Size {
+				continue
+			}
+			dist := max(abs(dx), abs(dy))
+			c.setFunction(x, y, dist != 2 && dist != 4)
+		}
+	}
+}
+
+func (c *Code) drawAlignment(cx, cy int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// drawFormat writes both copies of the format information for level M and
+// the given mask, plus the always-dark module.
+func (c *Code) drawFormat(mask int) {
+	const levelM = 0b00
+	data := levelM<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return (bits>>i)&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(i))
+	}
+	c.setFunction(8, 7, bit(6))
+	c.setFunction(8, 8, bit(7))
+	c.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(i))
+	}
+	c.setFunction(8, c.Size-8, true)
+}
+
+// drawVersion writes the version information blocks, present from
+// version 7 up.
+func (c *Code) drawVersion(v int) {
+	if v < 7 {
+		return
+	}
+	rem := v
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	bits := v<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := (bits>>i)&1 == 1
+		a, b := c.Size-11+i%3, i/3
+		c.setFunction(a, b, dark)
+		c.setFunction(b, a, dark)
+	}
+}
+
+// placeData fills the non-function modules in the two-column zigzag,
+// starting from the bottom right.
+func (c *Code) placeData(codewords []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if c.function[y*c.Size+x] || i >= len(codewords)*8 {
+					continue
+				}
+				c.dark[y*c.Size+x] = (codewords[i/8]>>(7-i%8))&1 == 1
+				i++
+			}
+		}
+	}
+}
+
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.function[y*c.Size+x] {
+				continue
+			}
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip {
+				c.dark[y*c.Size+x] = !c.dark[y*c.Size+x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol under the four mask evaluation rules; lower
+// is easier to scan.
+func (c *Code) penalty() int {
+	score := 0
+	line := make([]bool, c.Size)
+	for _, vertical := range []bool{false, true} {
+		for i := 0; i < c.Size; i++ {
+			for j := range line {
+				if vertical {
+					line[j] = c.Black(i, j)
+				} else {
+					line[j] = c.Black(j, i)
+				}
+			}
+			score += linePenalty(line)
+		}
+	}
+
+	darkCount := 0
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if c.Black(x, y) {
+				darkCount++
+			}
+			if x+1 < c.Size && y+1 < c.Size {
+				d := c.Black(x, y)
+				if d == c.Black(x+1, y) && d == c.Black(x, y+1) && d == c.Black(x+1, y+1) {
+					score += 3
+				}
+			}
+		}
+	}
+	percent := darkCount * 100 / (c.Size * c.Size)
+	score += abs(percent-50) / 5 * 10
+	return score
+}
+
+// finderLike is the 1:1:3:1:1 pattern with four light modules on one side.
+var finderLike = [][]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+func linePenalty(line []bool) int {
+	score := 0
+	run := 1
+	for i := 1; i <= len(line); i++ {
+		if i < len(line) && line[i] == line[i-1] {
+			run++
+			continue
+		}
+		if run >= 5 {
+			score += 3 + run - 5
+		}
+		run = 1
+	}
+	for i := 0; i+11 <= len(line); i++ {
+		for _, pat := range finderLike {
+			match := true
+			for j, want := range pat {
+				if line[i+j] != want {
+					match = false
+					break
+				}
+			}
+			if match {
+				score += 40
+			}
+		}
+	}
+	return score
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// quietZone is the light border, in modules, scanners need around the code.
+const quietZone = 4
+
+// Render draws the code with half-block characters, two rows of modules
+// per line. Light modules are drawn filled so the code scans on the dark
+// background most terminals use.
+func (c *Code) Render() string {
+	var sb strings.Builder
+	lo, hi := -quietZone, c.Size+quietZone
+	for y := lo; y < hi; y += 2 {
+		for x := lo; x < hi; x++ {
+			top := !c.Black(x, y)
+			bottom := y+1 < hi && !c.Black(x, y+1)
+			switch {
+			case top && bottom:
+				sb.WriteString("█")
+			case top:
+				sb.WriteString("▀")
+			case bottom:
+				sb.WriteString("▄")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+		if y+2 < hi {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,349 @@
+package qr
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Published format information strings for level M, by mask (ISO/IEC
+// 18004 Table C.1).
+var formatM = [8]int{0x5412, 0x5125, 0x5E7C, 0x5B4B, 0x45F9, 0x40CE, 0x4F97, 0x4AA0}
+
+// Published version information strings (ISO/IEC 18004 Table D.1).
+var versionInfo = map[int]int{7: 0x07C94, 8: 0x085BC, 9: 0x09A99, 10: 0x0A4D3}
+
+// Published alignment pattern centres.
+var alignment = map[int][]int{1: nil, 7: {6, 22, 38}, 10: {6, 28, 50}}
+
+// Published block layout at level M: error correction codewords per block
+// and the data codewords of each block.
+var blocksM = map[int]struct {
+	ec     int
+	blocks []int
+}{
+	1:  {10, []int{16}},
+	7:  {18, []int{31, 31, 31, 31}},
+	10: {26, []int{43, 43, 43, 43, 44}},
+}
+
+// The Reed-Solomon example from the standard's worked "HELLO WORLD" 1-M
+// symbol: its 16 data codewords and the 10 error correction codewords.
+func TestReedSolomonVector(t *testing.T) {
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := rsRemainder(data, rsGenerator(10)); !bytes.Equal(got, want) {
+		t.Errorf("error correction = %v, want %v", got, want)
+	}
+}
+
+// Codes of versions 1, 7 and 10 decode back to their text, under every mask.
+func TestRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		version int
+		text    string
+	}{
+		{1, "boba"},
+		{1, "Hello, world!!"},
+		{7, "0x" + strings.Repeat("a1b2c3d4e5", 11)},
+		{7, strings.Repeat("7xKXtg2CW87d97TXJSDpbD5jBkheTqA83TZRuJosgAsU", 2) + "?amount=0.5&label=boba"},
+		{10, "solana:" + strings.Repeat("9WzDXwBbmkg8ZTbNMqUxvQRAyrZzDsGYdLVL9zYtAWWM", 4) + "?amount=1"},
+		{10, strings.Repeat("x", 213)},
+	} {
+		for mask := 0; mask < 8; mask++ {
+			c, err := encode(tc.text, mask)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := 17 + 4*tc.version; c.Size != want {
+				t.Fatalf("%d bytes: size %d, want %d (version %d)", len(tc.text), c.Size, want, tc.version)
+			}
+			got, err := decode(c)
+			if err != nil {
+				t.Errorf("version %d mask %d: %v", tc.version, mask, err)
+				continue
+			}
+			if got != tc.text {
+				t.Errorf("version %d mask %d: decoded %q, want %q", tc.version, mask, got, tc.text)
+			}
+		}
+	}
+	if _, err := Encode(strings.Repeat("x", 214)); err == nil {
+		t.Error("214 bytes encoded; the limit is 213")
+	}
+}
+
+func TestRender(t *testing.T) {
+	c, err := Encode("boba")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(c.Render(), "\n")
+	width := c.Size + 2*quietZone
+	if len(lines) != (width+1)/2 {
+		t.Errorf("%d lines, want %d", len(lines), (width+1)/2)
+	}
+	for i, l := range lines {
+		if n := len([]rune(l)); n != width {
+			t.Errorf("line %d is %d wide, want %d", i, n, width)
+		}
+	}
+}
+
+// decode reads c back the way a scanner would, using only the published
+// layout: it checks the finder, timing, format and version patterns,
+// unmasks the data, verifies every block's error correction and parses the
+// byte mode segment.
+func decode(c *Code) (string, error) {
+	size := c.Size
+	ver := (size - 17) / 4
+	layout, ok := blocksM[ver]
+	if !ok {
+		return "", fmt.Errorf("no layout for version %d", ver)
+	}
+
+	for _, corner := range [][2]int{{0, 0}, {size - 7, 0}, {0, size - 7}} {
+		for dy := 0; dy < 7; dy++ {
+			for dx := 0; dx < 7; dx++ {
+				ring := max(abs(dx-3), abs(dy-3))
+				if c.Black(corner[0]+dx, corner[1]+dy) != (ring != 2) {
+					return "", fmt.Errorf("finder at %v is wrong", corner)
+				}
+			}
+		}
+	}
+	for i := 8; i < size-8; i++ {
+		if c.Black(i, 6) != (i%2 == 0) || c.Black(6, i) != (i%2 == 0) {
+			return "", fmt.Errorf("timing pattern is wrong at %d", i)
+		}
+	}
+	if !c.Black(8, size-8) {
+		return "", fmt.Errorf("dark module is light")
+	}
+
+	var format1, format2 int
+	for i := 0; i <= 5; i++ {
+		format1 |= bit(c.Black(8, i)) << i
+	}
+	format1 |= bit(c.Black(8, 7))<<6 | bit(c.Black(8, 8))<<7 | bit(c.Black(7, 8))<<8
+	for i := 9; i < 15; i++ {
+		format1 |= bit(c.Black(14-i, 8)) << i
+	}
+	for i := 0; i < 8; i++ {
+		format2 |= bit(c.Black(size-1-i, 8)) << i
+	}
+	for i := 8; i < 15; i++ {
+		format2 |= bit(c.Black(8, size-15+i)) << i
+	}
+	if format1 != format2 {
+		return "", fmt.Errorf("format copies differ: %#x, %#x", format1, format2)
+	}
+	mask := -1
+	for m, f := range formatM {
+		if f == format1 {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		return "", fmt.Errorf("format %#x is not a level M format", format1)
+	}
+
+	if want, ok := versionInfo[ver]; ok {
+		var v1, v2 int
+		for i := 0; i < 18; i++ {
+			a, b := size-11+i%3, i/3
+			v1 |= bit(c.Black(a, b)) << i
+			v2 |= bit(c.Black(b, a)) << i
+		}
+		if v1 != want || v2 != want {
+			return "", fmt.Errorf("version info %#x, %#x, want %#x", v1, v2, want)
+		}
+	}
+
+	function := functionModules(ver)
+	// Two-column strips from the right, alternately upward and downward,
+	// stepping over the vertical timing pattern.
+	var bits []bool
+	upward := true
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < size; vert++ {
+			y := vert
+			if upward {
+				y = size - 1 - vert
+			}
+			for _, x := range []int{right, right - 1} {
+				if function[y][x] {
+					continue
+				}
+				bits = append(bits, c.Black(x, y) != maskBit(mask, x, y))
+			}
+		}
+		upward = !upward
+	}
+	var raw []byte
+	for i := 0; i+8 <= len(bits); i += 8 {
+		var b byte
+		for _, v := range bits[i : i+8] {
+			b = b<<1 | byte(bit(v))
+		}
+		raw = append(raw, b)
+	}
+
+	// De-interleave: data codewords column by column across blocks, then
+	// the error correction codewords.
+	blocks := make([][]byte, len(layout.blocks))
+	pos := 0
+	for i := 0; i < layout.blocks[len(layout.blocks)-1]; i++ {
+		for b, n := range layout.blocks {
+			if i < n {
+				blocks[b] = append(blocks[b], raw[pos])
+				pos++
+			}
+		}
+	}
+	for i := 0; i < layout.ec; i++ {
+		for b := range blocks {
+			blocks[b] = append(blocks[b], raw[pos])
+			pos++
+		}
+	}
+	var data []byte
+	for b, block := range blocks {
+		if s := syndrome(block, layout.ec); s != 0 {
+			return "", fmt.Errorf("block %d fails error correction check", b)
+		}
+		data = append(data, block[:layout.blocks[b]]...)
+	}
+
+	r := bitReader{data: data}
+	if mode := r.read(4); mode != 0b0100 {
+		return "", fmt.Errorf("mode %04b, want byte mode", mode)
+	}
+	countBits := 8
+	if ver >= 10 {
+		countBits = 16
+	}
+	n := r.read(countBits)
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = byte(r.read(8))
+	}
+	return string(out), nil
+}
+
+// functionModules marks the modules a decoder skips when reading data.
+func functionModules(ver int) [][]bool {
+	size := 17 + 4*ver
+	f := make([][]bool, size)
+	for i := range f {
+		f[i] = make([]bool, size)
+	}
+	fill := func(x0, y0, w, h int) {
+		for y := y0; y < y0+h; y++ {
+			for x := x0; x < x0+w; x++ {
+				f[y][x] = true
+			}
+		}
+	}
+	// Finders with separators and format areas, and the timing patterns.
+	fill(0, 0, 9, 9)
+	fill(size-8, 0, 8, 9)
+	fill(0, size-8, 9, 8)
+	fill(6, 0, 1, size)
+	fill(0, 6, size, 1)
+	centres := alignment[ver]
+	for _, ay := range centres {
+		for _, ax := range centres {
+			if ax < 9 && (ay < 9 || ay > size-9) || ax > size-9 && ay < 9 {
+				continue // would overlap a finder
+			}
+			fill(ax-2, ay-2, 5, 5)
+		}
+	}
+	if ver >= 7 {
+		fill(size-11, 0, 3, 6)
+		fill(0, size-11, 6, 3)
+	}
+	return f
+}
+
+func maskBit(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (y+x)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (y+x)%3 == 0
+	case 4:
+		return (y/2+x/3)%2 == 0
+	case 5:
+		return (y*x)%2+(y*x)%3 == 0
+	case 6:
+		return ((y*x)%2+(y*x)%3)%2 == 0
+	default:
+		return ((y+x)%2+(y*x)%3)%2 == 0
+	}
+}
+
+// syndrome evaluates the block at α^0 … α^(ec-1) and ORs the results; a
+// valid codeword gives zero at every root of the generator.
+func syndrome(block []byte, ec int) byte {
+	var exp [255]byte
+	v := 1
+	for i := range exp {
+		exp[i] = byte(v)
+		v <<= 1
+		if v&0x100 != 0 {
+			v ^= 0x11D
+		}
+	}
+	mul := func(a, b byte) byte {
+		var z byte
+		for a != 0 {
+			if a&1 != 0 {
+				z ^= b
+			}
+			a >>= 1
+			b = b<<1 ^ (b>>7)*0x1D
+		}
+		return z
+	}
+	var all byte
+	for i := 0; i < ec; i++ {
+		var s byte
+		for _, c := range block {
+			s = mul(s, exp[i]) ^ c
+		}
+		all |= s
+	}
+	return all
+}
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) read(n int) int {
+	v := 0
+	for i := 0; i < n; i++ {
+		b := r.data[r.pos/8] >> (7 - r.pos%8) & 1
+		v = v<<1 | int(b)
+		r.pos++
+	}
+	return v
+}
+
+func bit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,140 @@
+// Package tokencache remembers token symbols seen in tool responses so
+// addresses can be labeled later without a lookup. It is persisted in the
+// data directory and only ever read from memory or disk.
+package tokencache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// maxEntries bounds the cache; once full, new tokens are not added.
+const maxEntries = 5000
+
+// Cache maps token addresses to symbols.
+type Cache struct {
+	path string
+
+	mu      sync.RWMutex
+	once    sync.Once
+	symbols map[string]string
+}
+
+// Default is the cache shared by the proxy and the formatters.
+var Default = New(filepath.Join(config.DataDir(), "token-cache.json"))
+
+// New returns a cache persisted at path.
+func New(path string) *Cache {
+	return &Cache{path: path}
+}
+
+func (c *Cache) load() {
+	c.once.Do(func() {
+		symbols := make(map[string]string)
+		if data, err := os.ReadFile(c.path); err == nil {
+			_ = json.Unmarshal(data, &symbols)
+		}
+		c.mu.Lock()
+		c.symbols = symbols
+		c.mu.Unlock()
+	})
+}
+
+// key normalizes an address: EVM addresses are case-insensitive, base58
+// addresses are not.
+func key(address string) string {
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return strings.ToLower(address)
+	}
+	return address
+}
+
+// Symbol returns the cached symbol for a token address.
+func (c *Cache) Symbol(address string) (string, bool) {
+	if address == "" {
+		return "", false
+	}
+	c.load()
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	s, ok := c.symbols[key(address)]
+	return s, ok
+}
+
+// Learn records a token's symbol and reports whether the cache changed.
+func (c *Cache) Learn(address, symbol string) bool {
+	if !looksLikeAddress(address) || symbol == "" || len(symbol) > 20 {
+		return false
+	}
+	c.load()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	k := key(address)
+	if old, ok := c.symbols[k]; ok && old == symbol {
+		return false
+	}
+	if _, ok := c.symbols[k]; !ok && len(c.symbols) >= maxEntries {
+		return false
+	}
+	c.symbols[k] = symbol
+	return true
+}
+
+// addressKeys are the fields that hold a token's address next to its
+// "symbol" in backend responses.
+var addressKeys = []string{"address", "token_address", "mint", "contract_address", "token"}
+
+// LearnFrom walks a decoded tool response and records every object that
+// pairs a token address with a symbol. It reports whether anything new was
+// learned.
+func (c *Cache) LearnFrom(v any) bool {
+	changed := false
+	switch t := v.(type) {
+	case map[string]any:
+		if symbol, ok := t["symbol"].(string); ok {
+			for _, k := range addressKeys {
+				if addr, ok := t[k].(string); ok && c.Learn(addr, symbol) {
+					changed = true
+					break
+				}
+			}
+		}
+		for _, child := range t {
+			if c.LearnFrom(child) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, child := range t {
+			if c.LearnFrom(child) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// Save writes the cache to disk.
+func (c *Cache) Save() error {
+	c.load()
+	c.mu.RLock()
+	data, err := json.Marshal(c.symbols)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return config.WritePrivateFile(c.path, data)
+}
+
+// looksLikeAddress accepts 0x-prefixed EVM addresses and base58 mints.
+func looksLikeAddress(s string) bool {
+	if strings.HasPrefix(s, "0x") {
+		return len(s) == 42
+	}
+	return len(s) >= 32 && len(s) <= 44 && !strings.ContainsAny(s, " 0OIl")
+}
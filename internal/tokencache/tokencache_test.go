@@ -0,0 +1,61 @@
+package tokencache
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+const (
+	pepe = "0x7A3B000000000000000000000000000000091C2A"
+	wif  = "EKpQGSJtjMFqKZ9KQanSqYXRcF8fBopzLHYxdM65zcjm"
+)
+
+// Symbols are learned from any object in a response that pairs an address
+// with a symbol, and survive a restart through the file.
+func TestLearnFromAndPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token-cache.json")
+	c := New(path)
+	resp := map[string]any{
+		"data": map[string]any{
+			"orders": []any{
+				map[string]any{"token_address": pepe, "symbol": "PEPE"},
+				map[string]any{"mint": wif, "symbol": "WIF"},
+				map[string]any{"address": "not-an-address", "symbol": "BAD"},
+			},
+		},
+	}
+	if !c.LearnFrom(resp) {
+		t.Fatal("nothing learned")
+	}
+	if c.LearnFrom(resp) {
+		t.Error("learning the same symbols again reported a change")
+	}
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := New(path)
+	for _, tc := range []struct {
+		addr, want string
+		ok         bool
+	}{
+		{pepe, "PEPE", true},
+		{"0x7a3b000000000000000000000000000000091c2a", "PEPE", true}, // EVM addresses ignore case
+		{wif, "WIF", true},
+		{"ekpqgsjtjmfqkz9kqansqyxrcf8fbopzlhyxdm65zcjm", "", false}, // base58 doesn't
+		{"not-an-address", "", false},
+		{"", "", false},
+	} {
+		if got, ok := reloaded.Symbol(tc.addr); got != tc.want || ok != tc.ok {
+			t.Errorf("Symbol(%q) = %q, %v; want %q, %v", tc.addr, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+// A missing or unreadable file is an empty cache, never an error.
+func TestMissingFile(t *testing.T) {
+	c := New(filepath.Join(t.TempDir(), "none.json"))
+	if _, ok := c.Symbol(pepe); ok {
+		t.Error("empty cache answered")
+	}
+}
@@ -0,0 +1,449 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+var bootStepLabels = []string{
+	"Generating session token",
+	"Binding to port",
+	"Authenticating agent",
+	"Syncing tool manifest",
+	"Proxy online",
+}
+
+var quitSteps = []string{
+	"Clearing session token...",
+	"Stopping proxy...",
+	"Goodbye!",
+}
+
+// bootStepHints suggest what to do when the boot step of the same index
+// fails.
+var bootStepHints = []string{
+	"Check that the system keyring is unlocked, then run 'boba start' again.",
+	"Stop whatever is using the port ('boba stop' if it's another proxy) or pick one with --port.",
+	"Run 'boba login' to sign in again.",
+	"Check your connection and the MCP URL in 'boba config', then run 'boba start' again.",
+}
+
+// BootTask is the real work behind one boot step. It returns a detail for
+// the step's line, such as the bound address, or the error that stops the
+// boot. A nil task has nothing left to do.
+type BootTask func() (detail string, err error)
+
+// bootResult is what a boot step's task reported.
+type bootResult struct {
+	done   bool
+	detail string
+	err    error
+}
+
+// bootFrames is how many 40ms frames the boot animation runs before the
+// proxy view takes over.
+const bootFrames = 40
+
+// bootStepFrames are the frames at which each boot step completes.
+var bootStepFrames = []int{5, 12, 19, 26, 33}
+
+// bootView is the startup animation and the shutdown sequence shown around
+// the running proxy view. The animation never gets ahead of the startup it
+// shows: a step is only ticked off once its task has succeeded, and a
+// failed task stops the boot with the error.
+type bootView struct {
+	step     int
+	frame    int
+	anim     int // keeps the bubbles rising while a step waits on its task
+	glitch   int
+	progress progress.Model
+	static   bool
+
+	tasks   []BootTask
+	results []bootResult
+	failed  int // index of the step that failed, or -1
+
+	quitStep int
+}
+
+func newBootView(static bool, tasks []BootTask) bootView {
+	return bootView{
+		progress: progress.New(
+			bootFill(),
+			progress.WithWidth(40),
+			progress.WithoutPercentage(),
+		),
+		static:  static,
+		tasks:   tasks,
+		results: make([]bootResult, len(tasks)),
+		failed:  -1,
+	}
+}
+
+// bootFill colors the progress bar. Gradients need hex colors, so the basic
+// and mono themes get a solid bar.
+func bootFill() progress.Option {
+	if !strings.HasPrefix(string(ui.ColorBoba), "#") {
+		return progress.WithSolidFill(string(ui.ColorBoba))
+	}
+	return progress.WithGradient(string(ui.ColorBoba), string(ui.ColorPending))
+}
+
+// tick advances the boot animation one frame and reports whether it has
+// finished.
+func (v *bootView) tick() bool {
+	v.anim++
+	v.glitch++
+	frames := 1
+	if v.static {
+		frames = bootFrames
+		v.anim = bootFrames
+	}
+
+	// Advance steps rapidly (every ~0.3s = every 7 frames), but hold on a
+	// step until its task has reported.
+	for range frames {
+		if v.step < len(bootStepFrames) && v.frame+1 >= bootStepFrames[v.step] {
+			if !v.stepDone(v.step) {
+				break
+			}
+			v.step++
+			v.glitch = 0
+		}
+		v.frame++
+	}
+
+	// Boot complete at frame 40 (~1.6s) then transition
+	return v.frame >= bootFrames
+}
+
+// stepDone reports whether boot step i's task has succeeded. Steps without
+// a task, such as the final "Proxy online", are done once reached.
+func (v bootView) stepDone(i int) bool {
+	if i >= len(v.tasks) || v.tasks[i] == nil {
+		return true
+	}
+	return v.results[i].done && v.results[i].err == nil
+}
+
+// runTask starts the task of boot step i, or skips to the next step that
+// has one.
+func (v bootView) runTask(i int) tea.Cmd {
+	for ; i < len(v.tasks); i++ {
+		if task := v.tasks[i]; task != nil {
+			return func() tea.Msg {
+				detail, err := task()
+				return BootStepMsg{Step: i, Detail: detail, Err: err}
+			}
+		}
+	}
+	return nil
+}
+
+// finish records a task's result and starts the next one. A failure stops
+// the boot.
+func (v *bootView) finish(msg BootStepMsg) tea.Cmd {
+	v.results[msg.Step] = bootResult{done: true, detail: msg.Detail, err: msg.Err}
+	if msg.Err != nil {
+		v.failed = msg.Step
+		return nil
+	}
+	return v.runTask(msg.Step + 1)
+}
+
+// err returns the error that stopped the boot, if any.
+func (v bootView) err() error {
+	if v.failed < 0 {
+		return nil
+	}
+	return v.results[v.failed].err
+}
+
+// quitTick advances the shutdown sequence and reports whether it is done.
+func (v *bootView) quitTick() bool {
+	v.quitStep++
+	return v.quitStep >= len(quitSteps)
+}
+
+// updateProgress animates the boot progress bar.
+func (v *bootView) updateProgress(msg progress.FrameMsg) tea.Cmd {
+	model, cmd := v.progress.Update(msg)
+	v.progress = model.(progress.Model)
+	return cmd
+}
+
+var bootBubbleChars = []string{".", "o", "O", "◯", "●", "◉"}
+
+func (v bootView) view(width int, spin string) string {
+	var b strings.Builder
+
+	// ---- Bubble field — particles rising upward ----
+	fieldH := 8
+	fieldW := width
+	if fieldW < 40 {
+		fieldW = 40
+	}
+
+	grid := make([][]rune, fieldH)
+	for y := range grid {
+		grid[y] = make([]rune, fieldW)
+		for x := range grid[y] {
+			grid[y][x] = ' '
+		}
+	}
+
+	numBubbles := fieldW / 2
+	if numBubbles < 40 {
+		numBubbles = 40
+	}
+	for i := range numBubbles {
+		seed := i*7 + 13
+		baseX := (seed*31 + i*17) % fieldW
+		speed := 1 + (seed % 4)
+		cycleLen := fieldH + 14
+		baseY := fieldH + 5 - (v.anim*speed/3+i*3)%cycleLen
+		wobble := int(math.Sin(float64(v.anim)*0.15+float64(i)*2.3) * 1.5)
+		x := baseX + wobble
+		y := baseY
+
+		if y >= 0 && y < fieldH && x >= 0 && x < fieldW {
+			charIdx := y * len(bootBubbleChars) / fieldH
+			if charIdx >= len(bootBubbleChars) {
+				charIdx = len(bootBubbleChars) - 1
+			}
+			ch := bootBubbleChars[charIdx]
+			for _, r := range ch {
+				grid[y][x] = r
+				break
+			}
+		}
+	}
+
+	gradColors := []lipgloss.Color{
+		lipgloss.Color("#3B1F6E"),
+		lipgloss.Color("#4B2D8E"),
+		lipgloss.Color("#6B3FA0"),
+		lipgloss.Color("#7B52B5"),
+		lipgloss.Color("#8A5FD1"),
+		lipgloss.Color("#9B72E0"),
+		lipgloss.Color("#B184F5"),
+		lipgloss.Color("#D4A5FF"),
+	}
+	for y := range fieldH {
+		colorIdx := y * (len(gradColors) - 1) / max(fieldH-1, 1)
+		if colorIdx >= len(gradColors) {
+			colorIdx = len(gradColors) - 1
+		}
+		style := lipgloss.NewStyle().Foreground(gradColors[colorIdx])
+		b.WriteString(style.Render(string(grid[y])))
+		b.WriteString("\n")
+	}
+
+	// ---- Logo fading in from block characters ----
+	b.WriteString("\n")
+	logoProgress := float64(v.anim-2) / 18.0
+	if logoProgress < 0 {
+		logoProgress = 0
+	}
+	if logoProgress > 1.0 {
+		logoProgress = 1.0
+	}
+
+	logoLines := ui.LogoLines()
+	for i, line := range logoLines {
+		lineP := logoProgress*1.3 - float64(i)*0.03
+		if lineP < 0 {
+			lineP = 0
+		}
+		if lineP > 1.0 {
+			lineP = 1.0
+		}
+
+		var result string
+		if lineP >= 1.0 {
+			color := ui.ColorBoba
+			if i < len(ui.GradientPurple) {
+				color = ui.GradientPurple[i]
+			}
+			result = lipgloss.NewStyle().Foreground(color).Bold(true).Render(line)
+		} else if lineP > 0 {
+			result = bootPartialReveal(line, lineP, i)
+		} else {
+			result = lipgloss.NewStyle().Foreground(lipgloss.Color("#1a1a2e")).Render(line)
+		}
+		b.WriteString(result + "\n")
+	}
+
+	b.WriteString("\n")
+
+	// ---- Boot steps with typing reveal ----
+	checkStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true)
+	doneStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	activeStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
+	pendingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#222222"))
+
+	failStyle := lipgloss.NewStyle().Foreground(ui.ColorRed).Bold(true)
+
+	for i, label := range bootStepLabels {
+		if i < v.step {
+			detail := ""
+			if i < len(v.results) && v.results[i].detail != "" {
+				detail = "  " + doneStyle.Faint(true).Render(v.results[i].detail)
+			}
+			fmt.Fprintf(&b, "  %s  %s%s\n",
+				checkStyle.Render("●"),
+				doneStyle.Render(label),
+				detail)
+		} else if i == v.failed {
+			fmt.Fprintf(&b, "  %s  %s\n",
+				failStyle.Render("✗"),
+				failStyle.Render(label))
+		} else if i == v.step && v.step < len(bootStepLabels) {
+			charsRevealed := v.glitch
+			if charsRevealed > len(label) {
+				charsRevealed = len(label)
+			}
+			revealed := label[:charsRevealed]
+			cursor := ""
+			if charsRevealed < len(label) {
+				cursor = "█"
+			}
+			fmt.Fprintf(&b, "  %s  %s%s\n",
+				spin,
+				activeStyle.Render(revealed),
+				lipgloss.NewStyle().Foreground(ui.ColorBoba).Render(cursor))
+		} else {
+			fmt.Fprintf(&b, "       %s\n",
+				pendingStyle.Render(strings.Repeat("·", len(label))))
+		}
+	}
+
+	// ---- Progress bar ----
+	b.WriteString("\n")
+	pct := float64(v.step) / float64(len(bootStepLabels))
+	b.WriteString("  ")
+	b.WriteString(v.progress.ViewAs(pct))
+	b.WriteString("\n")
+
+	if v.failed >= 0 {
+		b.WriteString("\n")
+		b.WriteString(v.viewFailure(width))
+		return b.String()
+	}
+
+	// ---- "CONNECTED" badge at the end ----
+	if v.frame >= 36 {
+		onlineStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#1a1a2e")).
+			Background(ui.ColorBoba).
+			Bold(true).
+			Padding(0, 2)
+		b.WriteString("\n  " + onlineStyle.Render(" CONNECTED "))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// viewFailure renders the panel that replaces the boot animation's ending
+// when a step fails: what failed, the error and what to try.
+func (v bootView) viewFailure(width int) string {
+	red := lipgloss.NewStyle().Foreground(ui.ColorRed).Bold(true)
+	dim := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	bright := lipgloss.NewStyle().Foreground(ui.ColorBright)
+
+	panelW := min(max(width-4, 40), 80)
+	lines := []string{
+		red.Render("STARTUP FAILED") + dim.Render("  "+bootStepLabels[v.failed]),
+		"",
+		bright.Width(panelW - 4).Render(v.err().Error()),
+	}
+	if v.failed < len(bootStepHints) {
+		lines = append(lines, "", dim.Width(panelW-4).Render(bootStepHints[v.failed]))
+	}
+	panel := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorRed).
+		Padding(0, 1).
+		Width(panelW - 2).
+		Render(strings.Join(lines, "\n"))
+
+	var b strings.Builder
+	for _, line := range strings.Split(panel, "\n") {
+		b.WriteString("  " + line + "\n")
+	}
+	b.WriteString("\n  " + dim.Render("Press q to exit") + "\n")
+	return b.String()
+}
+
+// bootPartialReveal resolves logo characters left-to-right with block chars for unresolved.
+func bootPartialReveal(line string, progress float64, lineIdx int) string {
+	runes := []rune(line)
+	totalNonSpace := 0
+	for _, r := range runes {
+		if r != ' ' {
+			totalNonSpace++
+		}
+	}
+	resolved := int(float64(totalNonSpace) * progress)
+
+	var result strings.Builder
+	nonSpaceIdx := 0
+	blockReplace := []rune{'░', '▒', '▓', '█'}
+
+	color := ui.ColorBoba
+	if lineIdx < len(ui.GradientPurple) {
+		color = ui.GradientPurple[lineIdx]
+	}
+	resolvedStyle := lipgloss.NewStyle().Foreground(color).Bold(true)
+	unresolvedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#333355"))
+
+	for _, r := range runes {
+		if r == ' ' {
+			result.WriteRune(' ')
+			continue
+		}
+		if nonSpaceIdx < resolved {
+			result.WriteString(resolvedStyle.Render(string(r)))
+		} else {
+			ch := blockReplace[rand.Intn(len(blockReplace))]
+			result.WriteString(unresolvedStyle.Render(string(ch)))
+		}
+		nonSpaceIdx++
+	}
+	return result.String()
+}
+
+func (v bootView) viewQuit(spin string) string {
+	var b strings.Builder
+
+	b.WriteString("\n")
+
+	titleStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true)
+	b.WriteString(titleStyle.Render("  SHUTTING DOWN"))
+	b.WriteString("\n\n")
+
+	checkStyle := lipgloss.NewStyle().Foreground(ui.ColorGreen).Bold(true)
+	activeStyle := lipgloss.NewStyle().Foreground(ui.ColorBright)
+	dimStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+
+	for i, step := range quitSteps {
+		if i < v.quitStep {
+			b.WriteString(fmt.Sprintf("  %s  %s\n", checkStyle.Render("[ok]"), lipgloss.NewStyle().Foreground(ui.ColorGreen).Render(step)))
+		} else if i == v.quitStep {
+			b.WriteString(fmt.Sprintf("  %s  %s\n", spin, activeStyle.Render(step)))
+		} else {
+			b.WriteString(fmt.Sprintf("       %s\n", dimStyle.Render(step)))
+		}
+	}
+
+	b.WriteString("\n")
+	return b.String()
+}
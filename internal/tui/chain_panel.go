@@ -0,0 +1,241 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// chainPanel is the full portfolio for the selected chain tab, fetched
+// separately from the All tab.
+type chainPanel struct {
+	data    *PortfolioData
+	loading bool
+	order   positionOrder
+	editing bool // the filter is being typed
+	pick    int  // position marked for adding to the watchlist, or -1
+}
+
+// open starts loading a newly selected chain, dropping whatever another
+// chain left behind.
+func (c *chainPanel) open() {
+	c.data = nil
+	c.loading = true
+}
+
+// receive stores a fetch result and reports whether it was for the chain
+// being shown; responses for a tab that is no longer selected are ignored.
+// Native balances the response leaves out are filled in from all, the All
+// tab's portfolio.
+func (c *chainPanel) receive(msg ChainPortfolioMsg, activeSlug string, all *PortfolioData) bool {
+	if msg.Slug != activeSlug {
+		return false
+	}
+	if msg.Data != nil {
+		msg.Data.fillFromAll(all, msg.Slug)
+	}
+	c.data = keepOnFailure(c.data, msg.Data)
+	c.loading = false
+	return true
+}
+
+// height returns the number of terminal lines the panel occupies,
+// including borders.
+func (c chainPanel) height() int {
+	if c.data == nil {
+		return 3 // loading state: border + content + border
+	}
+	if c.data.Error != "" {
+		return 3
+	}
+
+	contentLines := 2 // header + blank
+	nativeCount := len(c.order.natives(c.data.NativeBalances))
+	if nativeCount > 0 {
+		contentLines += nativeCount
+		contentLines++ // blank after natives
+	}
+	posCount := len(c.order.apply(c.data.Positions))
+	if posCount == 0 {
+		contentLines++
+	} else {
+		contentLines += posCount
+	}
+	if c.order.hiddenDust(c.data) > 0 {
+		contentLines++
+	}
+
+	return contentLines + 2 // +2 for borders
+}
+
+// view renders the portfolio panel for a specific chain, using data from
+// the chain-specific API call.
+func (c chainPanel) view(rc renderCtx, chainName string) string {
+	dimStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	symStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
+	titleStyle := lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true)
+
+	// Loading state — nothing fetched for this chain yet
+	if c.data == nil {
+		loadingMsg := dimStyle.Italic(true).
+			Render("  " + rc.spinner + " Loading " + chainName + " portfolio...")
+		return lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(ui.ColorGold).
+			Padding(0, 2).
+			Render(loadingMsg)
+	}
+
+	// Error state
+	if c.data.Error != "" {
+		errMsg := dimStyle.Italic(true).Render("  " + chainName + " portfolio unavailable")
+		return lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(ui.ColorDim).
+			Padding(0, 2).
+			Render(errMsg)
+	}
+
+	p := c.data
+	var lines []string
+
+	// Header: chain name + total value + age
+	headerLine := fmt.Sprintf("  %s  Total: %s  %s",
+		titleStyle.Render(strings.ToUpper(chainName)),
+		formatter.FormatUSD(p.TotalValueUSD),
+		refreshBadge(rc, p, c.loading)+orderTag(c.order, c.editing))
+	lines = append(lines, headerLine)
+	lines = append(lines, "")
+
+	// Native balances
+	natives := c.order.natives(p.NativeBalances)
+	if len(natives) > 0 {
+		maxSymLen := 0
+		for _, nb := range natives {
+			if len(nb.Symbol) > maxSymLen {
+				maxSymLen = len(nb.Symbol)
+			}
+		}
+		for _, nb := range natives {
+			dot := lipgloss.NewStyle().Foreground(ui.ColorCyan).Render("●")
+			goldStyle := lipgloss.NewStyle().Foreground(ui.ColorGold)
+			paddedSym := nb.Symbol + strings.Repeat(" ", maxSymLen-len(nb.Symbol))
+			balStr := fmt.Sprintf("%.3f", nb.Balance)
+			usdStr := goldStyle.Render(formatter.DisplayCurrency().Amount(nb.BalanceUSD, 2))
+			lines = append(lines, fmt.Sprintf("  %s %s  %s  %s",
+				dot,
+				symStyle.Render(paddedSym),
+				balStr,
+				usdStr))
+		}
+		lines = append(lines, "")
+	}
+
+	// Positions — all that match the filter, server already filtered by
+	// chain_id
+	positions := c.order.apply(p.Positions)
+	if len(positions) == 0 {
+		if c.order.filter != "" {
+			lines = append(lines, dimStyle.Render("  "+noPositions(c.order)+" on "+chainName))
+		} else {
+			lines = append(lines, dimStyle.Render("  No positions on "+chainName))
+		}
+	} else {
+		goldStyle := lipgloss.NewStyle().Foreground(ui.ColorGold)
+
+		// Find max symbol length for padding
+		maxPosSymLen := 0
+		for _, pos := range positions {
+			if len(pos.Symbol) > maxPosSymLen {
+				maxPosSymLen = len(pos.Symbol)
+			}
+		}
+
+		var posTotal float64
+		for _, pos := range p.Positions {
+			posTotal += pos.ValueUSD
+		}
+
+		// Pre-format all values to find max widths for alignment
+		type posRow struct {
+			symbol   string
+			valStr   string
+			allocStr string
+			pnlStr   string
+			pnlUSD   string
+			cost     string
+		}
+		var rows []posRow
+		maxValLen := 0
+		maxAllocLen := 0
+		maxPnlUSDLen := 0
+		maxPnlLen := 0
+		for _, pos := range positions {
+			alloc := 0.0
+			if posTotal > 0 {
+				alloc = (pos.ValueUSD / posTotal) * 100
+			}
+			valStr := formatter.DisplayCurrency().Amount(pos.ValueUSD, 2)
+			allocStr := fmt.Sprintf("%.0f%%", alloc)
+			pnlStr := formatter.FormatPercent(pos.PnlPercent)
+			maxValLen = max(maxValLen, lipgloss.Width(valStr))
+			if len(allocStr) > maxAllocLen {
+				maxAllocLen = len(allocStr)
+			}
+			row := posRow{
+				symbol:   pos.Symbol,
+				valStr:   valStr,
+				allocStr: allocStr,
+				pnlStr:   pnlStr,
+				pnlUSD:   dimStyle.Render("—"),
+			}
+			if pos.HasPnl {
+				row.pnlUSD = formatter.FormatPnLUSD(pos.PnlUSD)
+				row.cost = dimStyle.Render("cost " + formatter.FormatUSD(pos.CostBasisUSD))
+			}
+			maxPnlUSDLen = max(maxPnlUSDLen, lipgloss.Width(row.pnlUSD))
+			maxPnlLen = max(maxPnlLen, lipgloss.Width(row.pnlStr))
+			rows = append(rows, row)
+		}
+		wide := rc.width >= pnlColumnsWidth
+
+		var rendered []string
+		for _, r := range rows {
+			paddedSym := r.symbol + strings.Repeat(" ", maxPosSymLen-len(r.symbol))
+			paddedVal := padLeft(r.valStr, maxValLen)
+			paddedAlloc := strings.Repeat(" ", maxAllocLen-len(r.allocStr)) + r.allocStr
+			var line string
+			if wide {
+				line = strings.TrimRight(fmt.Sprintf("  %s  %s  %s  %s  %s  %s",
+					symStyle.Render(paddedSym),
+					goldStyle.Render(paddedVal),
+					dimStyle.Render(paddedAlloc),
+					padLeft(r.pnlUSD, maxPnlUSDLen),
+					padLeft(r.pnlStr, maxPnlLen),
+					r.cost), " ")
+			} else {
+				line = fmt.Sprintf("  %s  %s  %s  %s",
+					symStyle.Render(paddedSym),
+					goldStyle.Render(paddedVal),
+					dimStyle.Render(paddedAlloc),
+					r.pnlStr)
+			}
+			rendered = append(rendered, line)
+		}
+		lines = append(lines, markPick(rendered, c.pick)...)
+	}
+	if hidden := c.order.hiddenDust(p); hidden > 0 {
+		lines = append(lines, dimStyle.Render("  "+dustNote(c.order, hidden)))
+	}
+
+	content := strings.Join(lines, "\n")
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorGold).
+		BorderTop(true).
+		Padding(0, 2).
+		Render(content)
+}
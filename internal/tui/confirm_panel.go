@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// confirmPanel shows the oldest trade held for approval in confirmation
+// mode. The queue lives in the proxy; the panel only remembers how tall it
+// was at the last layout so the view can relay out when that changes.
+type confirmPanel struct {
+	shown int
+}
+
+// changed reports whether the panel's height differs from the last layout
+// and records the new one.
+func (p *confirmPanel) changed(rc renderCtx, pending []proxy.PendingConfirmation) bool {
+	h := p.height(rc, pending)
+	if h == p.shown {
+		return false
+	}
+	p.shown = h
+	return true
+}
+
+// height returns the lines the panel takes, including the blank line after
+// it, or 0 when nothing is held.
+func (p confirmPanel) height(rc renderCtx, pending []proxy.PendingConfirmation) int {
+	if len(pending) == 0 {
+		return 0
+	}
+	return lipgloss.Height(p.view(rc, pending)) + 1
+}
+
+func (p confirmPanel) view(rc renderCtx, pending []proxy.PendingConfirmation) string {
+	if len(pending) == 0 {
+		return ""
+	}
+	call := pending[0]
+
+	titleStyle := lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true)
+	toolStyle := lipgloss.NewStyle().Foreground(ui.ToolColor(call.Tool)).Bold(true)
+	labelStyle := lipgloss.NewStyle().Foreground(ui.ColorDim).Width(8)
+	valStyle := lipgloss.NewStyle().Foreground(ui.ColorBright)
+	dimStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	keyStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true)
+
+	left := int(call.Deadline.Sub(rc.now).Seconds() + 0.5)
+	if left < 0 {
+		left = 0
+	}
+	countdown := dimStyle.Render(fmt.Sprintf("%ds left", left))
+	if left <= 10 {
+		countdown = lipgloss.NewStyle().Foreground(ui.ColorRed).Render(fmt.Sprintf("%ds left", left))
+	}
+
+	lines := []string{fmt.Sprintf("  %s  %s  %s",
+		titleStyle.Render("CONFIRM"), toolStyle.Render(call.Tool), countdown)}
+	for _, d := range call.Details() {
+		lines = append(lines, fmt.Sprintf("  %s %s", labelStyle.Render(d.Label), valStyle.Render(d.Value)))
+	}
+
+	keys := "  " + keyStyle.Render("y") + dimStyle.Render(" approve  ") +
+		keyStyle.Render("n") + dimStyle.Render(" deny")
+	if more := len(pending) - 1; more > 0 {
+		keys += dimStyle.Render(fmt.Sprintf("   +%d more waiting", more))
+	}
+	lines = append(lines, keys)
+
+	return strings.Join(lines, "\n")
+}
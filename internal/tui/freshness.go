@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// portfolioPollInterval is how often the portfolio panels refresh.
+const portfolioPollInterval = 30 * time.Second
+
+// portfolioPollMaxInterval caps the poll backoff while the backend is
+// offline.
+const portfolioPollMaxInterval = 5 * time.Minute
+
+// refreshFlashWindow is how long a panel highlights freshly fetched data.
+const refreshFlashWindow = 3 * time.Second
+
+// Freshness describes how old a displayed dataset is. Every panel showing
+// fetched data renders its age the same way: dim while fresh, gold once it
+// is more than twice the poll interval old and red past five times. A failed
+// refresh leaves the old data on screen and lets the age keep growing.
+type Freshness struct {
+	FetchedAt time.Time
+	Interval  time.Duration
+	Failed    bool
+}
+
+type freshLevel int
+
+const (
+	freshOK freshLevel = iota
+	freshStale
+	freshExpired
+)
+
+// Age returns how long ago the data was fetched.
+func (f Freshness) Age(now time.Time) time.Duration {
+	if f.FetchedAt.IsZero() {
+		return 0
+	}
+	age := now.Sub(f.FetchedAt)
+	if age < 0 {
+		return 0
+	}
+	return age
+}
+
+func (f Freshness) level(now time.Time) freshLevel {
+	age := f.Age(now)
+	switch {
+	case f.Interval > 0 && age > 5*f.Interval:
+		return freshExpired
+	case f.Interval > 0 && age > 2*f.Interval, f.Failed:
+		return freshStale
+	}
+	return freshOK
+}
+
+// JustRefreshed reports whether the data arrived within the flash window.
+func (f Freshness) JustRefreshed(now time.Time) bool {
+	return !f.Failed && !f.FetchedAt.IsZero() && f.Age(now) < refreshFlashWindow
+}
+
+// Badge renders the age, e.g. "12s", colored by staleness. Data kept after
+// a failed refresh shows when it was fetched instead: "stale since 14:05".
+func (f Freshness) Badge(now time.Time) string {
+	if f.FetchedAt.IsZero() {
+		return ""
+	}
+	if f.Failed {
+		color := ui.ColorGold
+		if f.level(now) == freshExpired {
+			color = ui.ColorRed
+		}
+		return lipgloss.NewStyle().Foreground(color).Render("stale since " + f.FetchedAt.Format("15:04"))
+	}
+	style := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	switch f.level(now) {
+	case freshStale:
+		style = lipgloss.NewStyle().Foreground(ui.ColorGold)
+	case freshExpired:
+		style = lipgloss.NewStyle().Foreground(ui.ColorRed)
+	}
+	return style.Render(formatAge(f.Age(now)))
+}
+
+// Describe renders the age as words for accessible mode.
+func (f Freshness) Describe(now time.Time) string {
+	if f.FetchedAt.IsZero() {
+		return ""
+	}
+	s := "updated " + formatAge(f.Age(now)) + " ago"
+	switch {
+	case f.Failed:
+		s += ", last refresh failed"
+	case f.level(now) != freshOK:
+		s += ", stale"
+	}
+	return s
+}
+
+// formatAge renders a duration in its largest whole unit: 12s, 4m, 2h.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	return fmt.Sprintf("%dh", int(d.Hours()))
+}
+
+// freshness returns the freshness of d for a panel polled every interval.
+func (d *PortfolioData) freshness(interval time.Duration) Freshness {
+	if d == nil {
+		return Freshness{Interval: interval}
+	}
+	return Freshness{FetchedAt: d.LastUpdated, Interval: interval, Failed: d.RefreshFailed}
+}
+
+// keepOnFailure returns next, unless next is a failed refresh and prev holds
+// good data, in which case prev is kept and marked as failed so the panel
+// keeps showing it with a growing age instead of going blank.
+func keepOnFailure(prev, next *PortfolioData) *PortfolioData {
+	if next == nil || next.Error == "" || prev == nil || prev.Error != "" {
+		return next
+	}
+	kept := *prev
+	kept.RefreshFailed = true
+	return &kept
+}
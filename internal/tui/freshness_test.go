@@ -0,0 +1,100 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFreshnessLevels(t *testing.T) {
+	t0 := time.Date(2026, 5, 1, 14, 5, 0, 0, time.Local)
+	f := Freshness{FetchedAt: t0, Interval: 30 * time.Second}
+	for _, tc := range []struct {
+		after     time.Duration
+		level     freshLevel
+		badge     string
+		refreshed bool
+	}{
+		{time.Second, freshOK, "1s", true},
+		{12 * time.Second, freshOK, "12s", false},
+		{60 * time.Second, freshOK, "1m", false},
+		{61 * time.Second, freshStale, "1m", false},
+		{150 * time.Second, freshStale, "2m", false},
+		{151 * time.Second, freshExpired, "2m", false},
+		{3 * time.Hour, freshExpired, "3h", false},
+	} {
+		now := t0.Add(tc.after)
+		if got := f.level(now); got != tc.level {
+			t.Errorf("after %v: level %d, want %d", tc.after, got, tc.level)
+		}
+		if got := f.Badge(now); got != tc.badge {
+			t.Errorf("after %v: badge %q, want %q", tc.after, got, tc.badge)
+		}
+		if got := f.JustRefreshed(now); got != tc.refreshed {
+			t.Errorf("after %v: just refreshed %v, want %v", tc.after, got, tc.refreshed)
+		}
+	}
+
+	failed := Freshness{FetchedAt: t0, Interval: 30 * time.Second, Failed: true}
+	if got := failed.level(t0.Add(time.Second)); got != freshStale {
+		t.Errorf("failed refresh: level %d, want stale", got)
+	}
+	if got, want := failed.Badge(t0.Add(time.Second)), "stale since 14:05"; got != want {
+		t.Errorf("failed badge %q, want %q", got, want)
+	}
+	if failed.JustRefreshed(t0) {
+		t.Error("a failed refresh flashed as fresh")
+	}
+	if got, want := failed.Describe(t0.Add(90*time.Second)), "updated 1m ago, last refresh failed"; got != want {
+		t.Errorf("Describe = %q, want %q", got, want)
+	}
+	if got := (Freshness{}).Badge(t0); got != "" {
+		t.Errorf("badge without data: %q", got)
+	}
+}
+
+// The portfolio panel's age follows the model clock, and a failed refresh
+// keeps the old numbers on screen marked stale.
+func TestPortfolioPanelAge(t *testing.T) {
+	m := runningModel(t)
+	t0 := time.Date(2026, 5, 1, 14, 5, 0, 0, time.Local)
+	now := t0
+	m.clock = func() time.Time { return now }
+
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 50})
+	model, _ = model.Update(PortfolioMsg{Data: &PortfolioData{TotalValueUSD: 1234.5, LastUpdated: t0}})
+
+	for _, tc := range []struct {
+		after time.Duration
+		want  string
+	}{
+		{12 * time.Second, "12s"},
+		{2 * time.Minute, "2m"},
+	} {
+		now = t0.Add(tc.after)
+		if view := model.View(); !strings.Contains(view, "● "+tc.want) {
+			t.Errorf("after %v: view lacks age %q:\n%s", tc.after, tc.want, view)
+		}
+	}
+
+	now = t0.Add(40 * time.Second)
+	model, _ = model.Update(PortfolioMsg{Data: &PortfolioData{Error: "backend offline", LastUpdated: now}})
+	pv := model.(ProxyViewModel)
+	if pv.portfolio.data.TotalValueUSD != 1234.5 || !pv.portfolio.data.RefreshFailed {
+		t.Fatalf("failed refresh replaced the data: %+v", pv.portfolio.data)
+	}
+	view := model.View()
+	for _, want := range []string{"stale since 14:05", "$1.2K"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("after a failed refresh the view lacks %q:\n%s", want, view)
+		}
+	}
+
+	model, _ = model.Update(PortfolioMsg{Data: &PortfolioData{TotalValueUSD: 1300, LastUpdated: now}})
+	if d := model.(ProxyViewModel).portfolio.data; d.RefreshFailed || d.TotalValueUSD != 1300 {
+		t.Errorf("a good refresh after a failure: %+v", d)
+	}
+}
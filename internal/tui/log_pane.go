@@ -0,0 +1,801 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+const (
+	// collapseLines is how much of a long formatted result the activity
+	// log shows until the entry is expanded.
+	collapseLines = 30
+	// wheelLines is how far one notch of the mouse wheel scrolls.
+	wheelLines = 3
+	// foldedIDs is how many request IDs a row of repeated errors keeps
+	// for the requests folded into it.
+	foldedIDs = 50
+)
+
+// logPane is the scrolling activity log of proxied tool calls. It follows
+// new entries until the user scrolls up, and again once they return to the
+// bottom or press end. It keeps the newest maxRows requests.
+//
+// Results can run to hundreds of lines, so each settled row's lines are
+// rendered once and cached, and a frame only draws the lines in view.
+type logPane struct {
+	rows    []logRow
+	byID    map[string]int // request ID -> index in rows
+	maxRows int
+
+	// shown are the indexes of the rows that pass the filter, in order;
+	// starts and rowAt count in shown rows, not all rows.
+	shown  []int
+	starts []int // first line of each shown row
+	lines  int   // total lines across the shown rows
+	offset int   // first line in view
+
+	filter int    // index into logFilters
+	search string // substring n and N look for; empty when not searching
+	match  int    // index of the row found last, or -1
+
+	width      int
+	height     int
+	keys       viewport.KeyMap
+	ready      bool
+	autoScroll bool
+}
+
+// logRow is one logical request. Entries sharing a request ID update the
+// row in place; entries without an ID each get a row of their own.
+type logRow struct {
+	entry    proxy.LogEntry // latest state, stamped with the request's start
+	latest   time.Time      // timestamp of the latest entry applied
+	history  []statusChange
+	expanded bool // show the formatted output in full
+	// repeats counts the identical errors folded into this one, and
+	// lastRepeat is when the latest of them was logged. folded holds their
+	// request IDs.
+	repeats    int
+	lastRepeat time.Time
+	folded     []string
+
+	// rendered caches the row's lines at renderedWidth. Pending rows show
+	// the spinner, so they are never cached.
+	rendered      []string
+	renderedWidth int
+}
+
+// statusChange is one step in a request's lifecycle.
+type statusChange struct {
+	status string
+	note   string
+	at     time.Time
+}
+
+func newLogPane(maxRows int) logPane {
+	return logPane{
+		autoScroll: true,
+		byID:       make(map[string]int),
+		maxRows:    maxRows,
+		keys:       viewport.DefaultKeyMap(),
+		match:      -1,
+	}
+}
+
+// logFilters are the views f cycles through: every row, the rows of one
+// tool category, and failed calls.
+var logFilters = []string{"", "TRADE", "ORDER", "AUDIT", "ERRORS"}
+
+// passes reports whether a row is shown under the current filter.
+func (l logPane) passes(row logRow) bool {
+	switch f := logFilters[l.filter]; f {
+	case "":
+		return true
+	case "ERRORS":
+		return row.entry.Status == "error"
+	default:
+		return getToolTag(row.entry.Tool).label == f
+	}
+}
+
+// matches reports whether a row's tool, preview or error contains the
+// search text, ignoring case.
+func (l logPane) matches(row logRow) bool {
+	if l.search == "" {
+		return false
+	}
+	q := strings.ToLower(l.search)
+	for _, s := range []string{row.entry.Tool, row.entry.Preview, row.entry.Error} {
+		if strings.Contains(strings.ToLower(s), q) {
+			return true
+		}
+	}
+	return false
+}
+
+func finished(status string) bool {
+	return status == "success" || status == "error" || status == proxy.StatusRateLimited || status == proxy.StatusAlert || status == proxy.StatusNotice
+}
+
+// add merges an entry into its request's row, or starts a new row, and
+// lays the log out again, following it when live. It reports whether the
+// entry was applied: a finished request keeps its outcome, and an update
+// older than the row's latest state is stale, so both are dropped.
+func (l *logPane) add(rc renderCtx, entry proxy.LogEntry) bool {
+	change := statusChange{status: entry.Status, note: entry.Preview, at: entry.Timestamp}
+	if entry.Status != "pending" {
+		change.note = ""
+	}
+
+	i, seen := l.byID[entry.ID]
+	if entry.ID != "" && seen {
+		row := &l.rows[i]
+		if finished(row.entry.Status) || entry.Timestamp.Before(row.latest) {
+			return false
+		}
+		started := row.entry.Timestamp
+		row.entry = entry
+		row.entry.Timestamp = started
+		row.latest = entry.Timestamp
+		row.rendered = nil
+		// A stream's progress updates aren't steps in its lifecycle.
+		if entry.Status != proxy.StatusStreaming {
+			row.history = append(row.history, change)
+		}
+	} else {
+		l.rows = append(l.rows, logRow{entry: entry, latest: entry.Timestamp, history: []statusChange{change}})
+		if entry.ID != "" {
+			l.byID[entry.ID] = len(l.rows) - 1
+		}
+		l.trim()
+		i = len(l.rows) - 1
+	}
+	if entry.Status == "error" {
+		l.coalesce(i)
+	}
+
+	if l.ready {
+		l.layout(rc)
+	}
+	return true
+}
+
+// trim drops the oldest rows beyond maxRows.
+func (l *logPane) trim() {
+	drop := len(l.rows) - l.maxRows
+	if l.maxRows <= 0 || drop <= 0 {
+		return
+	}
+	l.rows = append(l.rows[:0:0], l.rows[drop:]...)
+	l.reindex()
+	l.match -= drop
+	if l.match < 0 {
+		l.match = -1
+	}
+	// Keep the same lines in view while scrolled back.
+	if first, _ := slices.BinarySearch(l.shown, drop); first < len(l.starts) {
+		l.offset = max(0, l.offset-l.starts[first])
+	}
+}
+
+// reindex maps request IDs to rows again after rows were removed.
+func (l *logPane) reindex() {
+	clear(l.byID)
+	for i, row := range l.rows {
+		if row.entry.ID != "" {
+			l.byID[row.entry.ID] = i
+		}
+		// Later updates of a folded request find it finished.
+		for _, id := range row.folded {
+			l.byID[id] = i
+		}
+	}
+}
+
+// coalesce folds row i, which just failed, into the previous finished row
+// when that failed the same way: same tool, same error. Rows of requests
+// still in flight are skipped over, so concurrent calls failing together
+// still fold into one; any other outcome in between keeps them apart.
+func (l *logPane) coalesce(i int) {
+	j := i - 1
+	for j >= 0 && !finished(l.rows[j].entry.Status) {
+		j--
+	}
+	if j < 0 {
+		return
+	}
+	prev, row := &l.rows[j], l.rows[i]
+	if prev.entry.Status != "error" || prev.entry.Tool != row.entry.Tool || prev.entry.Error != row.entry.Error {
+		return
+	}
+	prev.repeats += 1 + row.repeats
+	prev.lastRepeat = row.latest
+	prev.folded = append(prev.folded, row.folded...)
+	if row.entry.ID != "" {
+		prev.folded = append(prev.folded, row.entry.ID)
+	}
+	// Only recent requests can still send a late update.
+	if len(prev.folded) > foldedIDs {
+		prev.folded = slices.Clone(prev.folded[len(prev.folded)-foldedIDs:])
+	}
+	prev.rendered = nil
+	l.rows = slices.Delete(l.rows, i, i+1)
+	l.reindex()
+	switch {
+	case l.match == i:
+		l.match = j
+	case l.match > i:
+		l.match--
+	}
+}
+
+// block returns a row's lines, from the cache when they are current.
+func (l *logPane) block(rc renderCtx, row *logRow) []string {
+	if row.rendered != nil && row.renderedWidth == l.width {
+		return row.rendered
+	}
+	lines := strings.Split(formatLogEntry(rc, *row), "\n")
+	if row.entry.Status != "pending" {
+		row.rendered, row.renderedWidth = lines, l.width
+	}
+	return lines
+}
+
+// layout works out where each row starts, rendering only rows that changed,
+// and keeps the view at the bottom while following.
+func (l *logPane) layout(rc renderCtx) {
+	l.shown = l.shown[:0]
+	l.starts = l.starts[:0]
+	l.lines = 0
+	for i := range l.rows {
+		if !l.passes(l.rows[i]) {
+			continue
+		}
+		l.shown = append(l.shown, i)
+		l.starts = append(l.starts, l.lines)
+		l.lines += len(l.block(rc, &l.rows[i]))
+	}
+	if l.autoScroll {
+		l.offset = l.maxOffset()
+	} else {
+		l.scrollTo(l.offset)
+	}
+}
+
+func (l logPane) maxOffset() int {
+	return max(0, l.lines-l.height)
+}
+
+func (l logPane) atBottom() bool {
+	return l.offset >= l.maxOffset()
+}
+
+func (l *logPane) scrollTo(offset int) {
+	l.offset = min(max(offset, 0), l.maxOffset())
+}
+
+// rowAt returns the position in shown of the row on the given line.
+func (l logPane) rowAt(line int) int {
+	i, found := slices.BinarySearch(l.starts, line)
+	if !found {
+		i--
+	}
+	return max(i, 0)
+}
+
+// toggleExpanded expands or collapses the long result nearest the bottom
+// of the view. It reports whether there was one.
+func (l *logPane) toggleExpanded(rc renderCtx) bool {
+	if !l.ready || len(l.shown) == 0 {
+		return false
+	}
+	bottom := min(l.offset+l.height, l.lines)
+	for i := l.rowAt(bottom - 1); i >= 0 && i >= l.rowAt(l.offset); i-- {
+		row := &l.rows[l.shown[i]]
+		if !collapsible(row.entry) {
+			continue
+		}
+		row.expanded = !row.expanded
+		row.rendered = nil
+		l.layout(rc)
+		if !l.autoScroll && !row.expanded && l.starts[i] < l.offset {
+			// Keep the collapsed entry in view rather than the rows below it.
+			l.scrollTo(l.starts[i])
+		}
+		return true
+	}
+	return false
+}
+
+// resize gives the pane its share of the screen. Rows are rendered again
+// at a new width.
+func (l *logPane) resize(rc renderCtx, width, height int) {
+	if width != l.width {
+		for i := range l.rows {
+			l.rows[i].rendered = nil
+		}
+	}
+	l.width = width
+	l.height = height
+	l.ready = true
+	l.layout(rc)
+}
+
+// pause stops following new entries.
+func (l *logPane) pause() { l.autoScroll = false }
+
+// follow jumps to the newest entry and keeps following.
+func (l *logPane) follow() {
+	if !l.ready {
+		return
+	}
+	l.autoScroll = true
+	l.offset = l.maxOffset()
+}
+
+// update scrolls on the viewport's keys and the mouse wheel, and resumes
+// following once the user is back at the bottom.
+func (l *logPane) update(msg tea.Msg) tea.Cmd {
+	if !l.ready {
+		return nil
+	}
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, l.keys.PageDown):
+			l.scrollTo(l.offset + l.height)
+		case key.Matches(msg, l.keys.PageUp):
+			l.scrollTo(l.offset - l.height)
+		case key.Matches(msg, l.keys.HalfPageDown):
+			l.scrollTo(l.offset + l.height/2)
+		case key.Matches(msg, l.keys.HalfPageUp):
+			l.scrollTo(l.offset - l.height/2)
+		case key.Matches(msg, l.keys.Down):
+			l.scrollTo(l.offset + 1)
+		case key.Matches(msg, l.keys.Up):
+			l.scrollTo(l.offset - 1)
+		}
+	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionPress {
+			switch msg.Button {
+			case tea.MouseButtonWheelDown:
+				l.scrollTo(l.offset + wheelLines)
+			case tea.MouseButtonWheelUp:
+				l.scrollTo(l.offset - wheelLines)
+			}
+		}
+	}
+	if l.atBottom() {
+		l.autoScroll = true
+	}
+	return nil
+}
+
+// cycleFilter switches to the next filter in logFilters.
+func (l *logPane) cycleFilter(rc renderCtx) {
+	l.filter = (l.filter + 1) % len(logFilters)
+	if l.ready {
+		l.layout(rc)
+	}
+}
+
+// setSearch searches for text, jumping to the newest match.
+func (l *logPane) setSearch(text string) {
+	l.search = text
+	l.match = -1
+	if text != "" {
+		l.findMatch(-1)
+	}
+}
+
+// findMatch scrolls to the next shown row matching the search, newer with
+// dir 1 and older with dir -1, wrapping around at either end. It reports
+// whether there was one.
+func (l *logPane) findMatch(dir int) bool {
+	if !l.ready || l.search == "" || len(l.shown) == 0 {
+		return false
+	}
+	// Start from the current match, or from beyond the newest row.
+	from := len(l.shown)
+	if i, found := slices.BinarySearch(l.shown, l.match); found {
+		from = i
+	} else if dir > 0 {
+		from = -1
+	}
+	for step := 1; step <= len(l.shown); step++ {
+		i := ((from+dir*step)%len(l.shown) + len(l.shown)) % len(l.shown)
+		if l.matches(l.rows[l.shown[i]]) {
+			l.match = l.shown[i]
+			l.autoScroll = false
+			// Show the match near the top, with a little context above.
+			l.scrollTo(l.starts[i] - min(2, l.height/4))
+			return true
+		}
+	}
+	return false
+}
+
+// matchCount returns how many shown rows match the search and the position
+// of the current match among them, counting from 1, or 0 if none is current.
+func (l logPane) matchCount() (current, total int) {
+	for _, i := range l.shown {
+		if l.matches(l.rows[i]) {
+			total++
+			if i == l.match {
+				current = total
+			}
+		}
+	}
+	return current, total
+}
+
+// header describes the filter and search next to the ACTIVITY LOG title,
+// e.g. "[TRADE] 12/243 · /swap 2/5".
+func (l logPane) header() string {
+	var parts []string
+	if f := logFilters[l.filter]; f != "" {
+		parts = append(parts, lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render("["+f+"]")+
+			ui.DimStyle.Render(fmt.Sprintf(" %d/%d", len(l.shown), len(l.rows))))
+	}
+	if l.search != "" {
+		current, total := l.matchCount()
+		text := lipgloss.NewStyle().Foreground(ui.ColorBright).Render("/" + l.search)
+		if total == 0 {
+			parts = append(parts, text+lipgloss.NewStyle().Foreground(ui.ColorRed).Render(" no match"))
+		} else {
+			parts = append(parts, text+ui.DimStyle.Render(fmt.Sprintf(" %d/%d", current, total)))
+		}
+	}
+	return strings.Join(parts, ui.DimStyle.Render(" · "))
+}
+
+// hasPending reports whether a recent entry is still waiting on a response.
+// latestTxHash returns the transaction hash of the latest executed trade,
+// or "" when none is in the log.
+func (l logPane) latestTxHash() string {
+	for i := len(l.rows) - 1; i >= 0; i-- {
+		if e := l.rows[i].entry; e.Status == "success" && e.TxHash != "" {
+			return e.TxHash
+		}
+	}
+	return ""
+}
+
+func (l logPane) hasPending() bool {
+	// Only recent entries can still be pending.
+	for i := len(l.rows) - 1; i >= 0 && i >= len(l.rows)-50; i-- {
+		if l.rows[i].entry.Status == "pending" {
+			return true
+		}
+	}
+	return false
+}
+
+// badge shows LIVE while following, otherwise the row at the bottom of the
+// view.
+func (l logPane) badge() string {
+	if l.autoScroll {
+		return lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#1a1a2e")).
+			Background(ui.ColorGreen).
+			Bold(true).
+			Padding(0, 1).
+			Render("LIVE")
+	}
+	current := 0
+	if len(l.shown) > 0 {
+		current = l.rowAt(min(l.offset+l.height, l.lines)-1) + 1
+	}
+	return lipgloss.NewStyle().
+		Foreground(ui.ColorCyan).
+		Bold(true).
+		Render(fmt.Sprintf("[%d/%d]", current, len(l.shown)))
+}
+
+// view draws the lines in view, or idle text before the first request.
+func (l logPane) view(rc renderCtx) string {
+	if !l.ready {
+		return renderIdleText(rc.idleFrame)
+	}
+	var visible []string
+	if len(l.rows) == 0 {
+		visible = []string{renderIdleText(rc.idleFrame)}
+	} else if len(l.shown) == 0 {
+		what := logFilters[l.filter] + " calls"
+		if what == "ERRORS calls" {
+			what = "failed calls"
+		}
+		visible = []string{ui.DimStyle.Render("\n  No " + what + " yet. Press f for the next filter.")}
+	}
+	end := min(l.offset+l.height, l.lines)
+	for i := l.rowAt(l.offset); i < len(l.shown) && l.starts[i] < end; i++ {
+		block := l.block(rc, &l.rows[l.shown[i]])
+		from := max(l.offset-l.starts[i], 0)
+		to := min(end-l.starts[i], len(block))
+		if l.shown[i] == l.match && from == 0 && to > 0 {
+			// Mark the search match in the gutter.
+			marked := slices.Clone(block[from:to])
+			marked[0] = lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render("▸ ") + strings.TrimPrefix(marked[0], "  ")
+			visible = append(visible, marked...)
+			continue
+		}
+		visible = append(visible, block[from:to]...)
+	}
+	return lipgloss.NewStyle().
+		Width(l.width).
+		Height(l.height).
+		MaxWidth(l.width).
+		MaxHeight(l.height).
+		Render(strings.Join(visible, "\n"))
+}
+
+var idlePatterns = []string{
+	"  .       Waiting for requests",
+	"  ..      Waiting for requests",
+	"  ...     Waiting for requests",
+	"  ....    Waiting for requests",
+	"  ...     Waiting for requests",
+	"  ..      Waiting for requests",
+}
+
+func renderIdleText(idleFrame int) string {
+	frame := idleFrame % len(idlePatterns)
+	return lipgloss.NewStyle().Foreground(ui.ColorDim).Render("\n" + idlePatterns[frame] + "\n")
+}
+
+func formatLogEntry(rc renderCtx, row logRow) string {
+	entry := row.entry
+
+	// Timestamp — cyan for terminal-hacker aesthetic
+	ts := entry.Timestamp.Format("15:04:05")
+	tsStyle := lipgloss.NewStyle().Foreground(ui.ColorCyan)
+
+	// Category tag
+	tag := getToolTag(entry.Tool)
+	tagRendered := ui.TagStyle(*tag.color).Render(tag.label)
+
+	// Tool name
+	toolColor := ui.ToolColor(entry.Tool)
+	toolStyle := lipgloss.NewStyle().Foreground(toolColor).Bold(true)
+
+	var statusIcon string
+	var detail string
+
+	switch entry.Status {
+	case "pending":
+		statusIcon = rc.spinner
+		desc := toolDescriptions[entry.Tool]
+		if desc == "" {
+			desc = fmt.Sprintf("Calling %s...", entry.Tool)
+		}
+		detail = lipgloss.NewStyle().Foreground(ui.ColorDim).Italic(true).Render(desc)
+
+	case proxy.StatusAwaitingConfirmation:
+		statusIcon = lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render("HOLD")
+		detail = lipgloss.NewStyle().Foreground(ui.ColorGold).Italic(true).Render("awaiting your confirmation (y/n)")
+
+	case proxy.StatusStreaming:
+		statusIcon = lipgloss.NewStyle().Foreground(ui.ColorStreaming).Bold(true).Render("●")
+		events := "1 event"
+		if entry.Events != 1 {
+			events = fmt.Sprintf("%d events", entry.Events)
+		}
+		detail = lipgloss.NewStyle().Foreground(ui.ColorStreaming).Render(events)
+		if entry.Preview != "" {
+			detail += lipgloss.NewStyle().Foreground(ui.ColorDim).Render(", last: ") +
+				lipgloss.NewStyle().Foreground(ui.ColorBright).Render(entry.Preview)
+		}
+
+	case proxy.StatusRateLimited:
+		statusIcon = lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render("SLOW")
+		detail = lipgloss.NewStyle().Foreground(ui.ColorGold).Render(strings.TrimPrefix(entry.Error, "rate limited: "))
+
+	case proxy.StatusAlert:
+		statusIcon = lipgloss.NewStyle().Foreground(lipgloss.Color("#1a1a2e")).Background(ui.ColorGold).Bold(true).Render("ALERT")
+		detail = lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render(entry.Preview)
+
+	case proxy.StatusNotice:
+		statusIcon = lipgloss.NewStyle().Foreground(ui.ColorCyan).Bold(true).Render("NOTE")
+		detail = lipgloss.NewStyle().Foreground(ui.ColorBright).Render(entry.Preview)
+
+	case "success":
+		statusIcon = lipgloss.NewStyle().Foreground(ui.ColorGreen).Bold(true).Render("OK")
+		durBadge := renderDurationBadge(entry.Duration)
+		if entry.Cached {
+			durBadge += " " + lipgloss.NewStyle().Foreground(ui.ColorCyan).Render("(cached)")
+		}
+		detail = durBadge
+		if entry.Preview != "" {
+			previewStyle := lipgloss.NewStyle().Foreground(ui.ColorBright)
+			detail += " " + lipgloss.NewStyle().Foreground(ui.ColorDim).Render("->") + " " + previewStyle.Render(entry.Preview)
+		}
+
+	case "error":
+		label, color := errorBadge(entry.ErrorKind)
+		statusIcon = lipgloss.NewStyle().Foreground(color).Bold(true).Render(label)
+		durStr := formatDuration(entry.Duration)
+		errMsg := entry.Error
+		if len(errMsg) > 80 {
+			errMsg = errMsg[:77] + "..."
+		}
+		// A rejection is the backend answering as it should, with a reason
+		// to read rather than a fault to alarm about.
+		msgStyle := lipgloss.NewStyle().Foreground(color).Bold(entry.ErrorKind != proxy.ErrorRejected)
+		detail = lipgloss.NewStyle().Foreground(ui.ColorDim).Render(durStr) +
+			"  " + msgStyle.Render(errMsg)
+		if row.repeats > 0 {
+			detail += lipgloss.NewStyle().Foreground(ui.ColorDim).Render(
+				fmt.Sprintf("  ×%d, last %s", row.repeats+1, row.lastRepeat.Format("15:04:05")))
+		}
+		if entry.RequestID != "" {
+			detail += lipgloss.NewStyle().Foreground(ui.ColorDim).Render("  req " + shortRequestID(entry.RequestID))
+		}
+	}
+
+	statusLine := fmt.Sprintf("  %s %s %s %s %s",
+		tsStyle.Render(ts),
+		tagRendered,
+		toolStyle.Render(entry.Tool),
+		statusIcon,
+		detail,
+	)
+
+	// Put a pre-flight warning right under the trade it is about.
+	if entry.Warning != "" && entry.Status != "pending" {
+		statusLine += "\n" + indentBlock(lipgloss.NewStyle().Foreground(ui.ColorGold).Render("⚠ "+entry.Warning), "    ")
+	}
+
+	// List what the proxy changed in the arguments, so surprising results
+	// can be traced back to autofill.
+	if entry.Status != "pending" && len(entry.Modifications) > 0 {
+		modLines := []string{lipgloss.NewStyle().Foreground(ui.ColorGold).Render("proxy modifications")}
+		for _, mod := range entry.Modifications {
+			modLines = append(modLines, ui.DimStyle.Render("  "+mod.String()))
+		}
+		statusLine += "\n" + indentBlock(strings.Join(modLines, "\n"), "    ")
+	}
+
+	// With BOBA_DEBUG=1 the proxy also lists what autofill injected.
+	if entry.Status != "pending" && len(entry.Injected) > 0 {
+		var pairs []string
+		for _, k := range slices.Sorted(maps.Keys(entry.Injected)) {
+			pairs = append(pairs, k+"="+entry.Injected[k])
+		}
+		statusLine += "\n" + indentBlock(ui.DimStyle.Render("injected: "+strings.Join(pairs, ", ")), "    ")
+	}
+
+	// Spell out the lifecycle of requests that went through more than the
+	// usual pending -> done, such as an auth retry.
+	if entry.Status != "pending" && len(row.history) > 2 {
+		statusLine += "\n" + indentBlock(ui.DimStyle.Render(formatHistory(row.history)), "    ")
+	}
+
+	// Append the formatted output below the status line for successful
+	// calls, cut short until expanded when it is long.
+	if entry.Status == "success" && entry.FormattedOutput != "" {
+		output := entry.FormattedOutput
+		if collapsible(entry) {
+			if row.expanded {
+				output += "\n" + ui.DimStyle.Render("(o to collapse)")
+			} else {
+				lines := strings.Split(output, "\n")
+				hint := fmt.Sprintf("... %d more lines (o to expand)", len(lines)-collapseLines)
+				output = strings.Join(lines[:collapseLines], "\n") + "\n" + ui.DimStyle.Render(hint)
+			}
+		}
+		return statusLine + "\n" + indentBlock(output, "    ") + "\n"
+	}
+
+	return statusLine
+}
+
+// errorBadge returns the status label and color of a failed call, by its
+// proxy.ErrorKind.
+func errorBadge(kind string) (string, lipgloss.Color) {
+	switch kind {
+	case proxy.ErrorRejected:
+		return "REJ", ui.ColorGold
+	case proxy.ErrorAuth:
+		return "AUTH", ui.ColorRed
+	case proxy.ErrorTimeout:
+		return "T/O", ui.ColorRed
+	case proxy.ErrorNetwork:
+		return "NET", ui.ColorRed
+	case proxy.ErrorServer:
+		return "5XX", ui.ColorRed
+	}
+	return "ERR", ui.ColorRed
+}
+
+// collapsible reports whether an entry's formatted output is long enough to
+// be cut short in the activity log.
+func collapsible(entry proxy.LogEntry) bool {
+	return entry.Status == "success" && strings.Count(entry.FormattedOutput, "\n") >= collapseLines
+}
+
+// formatHistory renders a request's status changes with their offsets
+// from the start, e.g. "pending -> pending (retrying) +0.4s -> success +1.2s".
+func formatHistory(history []statusChange) string {
+	steps := make([]string, len(history))
+	for i, c := range history {
+		step := c.status
+		if i > 0 && c.note != "" {
+			step += " (" + c.note + ")"
+		}
+		if i > 0 {
+			step += " +" + formatDuration(c.at.Sub(history[0].at))
+		}
+		steps[i] = step
+	}
+	return "history: " + strings.Join(steps, " -> ")
+}
+
+// indentBlock prepends a prefix to every line of a multi-line string.
+func indentBlock(s string, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+func renderDurationBadge(d time.Duration) string {
+	ms := d.Milliseconds()
+	durStr := formatDuration(d)
+	icon := "~"
+
+	var color lipgloss.Color
+	switch {
+	case ms < 500:
+		color = ui.ColorGreen
+		icon = ">"
+	case ms < 2000:
+		color = ui.ColorGold
+		icon = "~"
+	default:
+		color = ui.ColorRed
+		icon = "!"
+	}
+
+	badgeStyle := lipgloss.NewStyle().
+		Foreground(color).
+		Bold(true)
+
+	return badgeStyle.Render(fmt.Sprintf("%s %s", icon, durStr))
+}
+
+// shortRequestID is the first block of a request ID, enough to tell calls
+// apart on screen; `boba logs` prints it in full.
+func shortRequestID(id string) string {
+	if i := strings.IndexByte(id, '-'); i > 0 {
+		return id[:i]
+	}
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
+
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// FormatLogEntry renders a finished request's entry as the activity log
+// shows it, for printing outside the dashboard.
+func FormatLogEntry(entry proxy.LogEntry) string {
+	rc := renderCtx{spinner: lipgloss.NewStyle().Foreground(ui.ColorDim).Render("..."), now: entry.Timestamp}
+	return formatLogEntry(rc, logRow{entry: entry, latest: entry.Timestamp, expanded: true})
+}
@@ -0,0 +1,241 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+)
+
+// BenchmarkLogPaneView draws a frame of an activity log holding 1000
+// requests with long results. Only the rows in view are drawn, so a frame
+// must not grow with the history.
+func BenchmarkLogPaneView(b *testing.B) {
+	rc := renderCtx{spinner: "⠋", now: time.Now(), width: 120}
+	l := newLogPane(1000)
+	l.resize(rc, 120, 40)
+
+	output := strings.Repeat("│  holder row with a long enough line of text to wrap │\n", 200)
+	start := time.Now().Add(-time.Hour)
+	for i := range 1000 {
+		l.add(rc, proxy.LogEntry{
+			ID:              fmt.Sprintf("req-%d", i),
+			Timestamp:       start.Add(time.Duration(i) * time.Second),
+			Tool:            "get_holders",
+			Status:          "success",
+			Preview:         "200 holders",
+			FormattedOutput: output,
+		})
+	}
+
+	b.ResetTimer()
+	for range b.N {
+		_ = l.view(rc)
+	}
+	if per := b.Elapsed() / time.Duration(b.N); per > 20*time.Millisecond {
+		b.Errorf("a frame took %s, want under 20ms", per)
+	}
+}
+
+// An expanded entry lists every argument the proxy changed, and a pending
+// one doesn't yet.
+func TestLogEntryModifications(t *testing.T) {
+	entry := proxy.LogEntry{
+		ID:        "req-1",
+		Timestamp: time.Date(2026, 1, 2, 14, 5, 0, 0, time.Local),
+		Tool:      "get_portfolio",
+		Status:    "success",
+		Modifications: []proxy.Modification{
+			{Field: "wallet", Original: "my-wallet-evm", Value: "0x5290", Reason: "autofill: wallet placeholder"},
+			{Field: "user_id", Absent: true, Value: "agent-1", Reason: "autofill: agent ID"},
+		},
+	}
+	out := FormatLogEntry(entry)
+	want := []string{
+		"proxy modifications",
+		"wallet: my-wallet-evm -> 0x5290 (autofill: wallet placeholder)",
+		"user_id: (absent) -> agent-1 (autofill: agent ID)",
+	}
+	last := -1
+	for _, w := range want {
+		i := strings.Index(out, w)
+		if i < 0 {
+			t.Fatalf("entry lacks %q:\n%s", w, out)
+		}
+		if i < last {
+			t.Errorf("%q out of order:\n%s", w, out)
+		}
+		last = i
+	}
+
+	entry.Status = "pending"
+	if out := FormatLogEntry(entry); strings.Contains(out, "proxy modifications") {
+		t.Errorf("pending entry lists modifications:\n%s", out)
+	}
+}
+
+// A request's updates through its whole lifecycle, including a stale and a
+// repeated one, make a single row that ends in its final state and is
+// counted once.
+func TestLogCoalescesLifecycle(t *testing.T) {
+	var model tea.Model = runningModel(t)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(ResizeSettledMsg{Seq: model.(ProxyViewModel).resizeSeq})
+
+	start := time.Date(2026, 1, 2, 14, 5, 0, 0, time.Local)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+	for _, e := range []proxy.LogEntry{
+		{ID: "req-1", Timestamp: at(0), Tool: "execute_swap", Status: "pending", Preview: "Swapping..."},
+		{ID: "req-1", Timestamp: at(200), Tool: "execute_swap", Status: proxy.StatusAwaitingConfirmation},
+		{ID: "req-1", Timestamp: at(100), Tool: "execute_swap", Status: "pending", Preview: "stale"},
+		{ID: "req-1", Timestamp: at(1500), Tool: "execute_swap", Status: "pending", Preview: "retrying"},
+		{ID: "req-1", Timestamp: at(2400), Tool: "execute_swap", Status: "success", Preview: "Swapped 1 SOL for 150 USDC"},
+		{ID: "req-1", Timestamp: at(2500), Tool: "execute_swap", Status: "success", Preview: "Swapped 1 SOL for 150 USDC"},
+		{ID: "req-1", Timestamp: at(2600), Tool: "execute_swap", Status: "pending", Preview: "late"},
+	} {
+		model, _ = model.Update(LogMsg(e))
+	}
+
+	m := model.(ProxyViewModel)
+	if len(m.log.rows) != 1 {
+		t.Fatalf("%d rows, want 1", len(m.log.rows))
+	}
+	row := m.log.rows[0]
+	if row.entry.Status != "success" || row.entry.Preview != "Swapped 1 SOL for 150 USDC" || !row.entry.Timestamp.Equal(start) {
+		t.Errorf("row ended as %s %q started %v", row.entry.Status, row.entry.Preview, row.entry.Timestamp)
+	}
+	if m.stats.requestCount != 1 || m.stats.errorCount != 0 {
+		t.Errorf("counted %d requests, %d errors; want 1, 0", m.stats.requestCount, m.stats.errorCount)
+	}
+	if view := m.View(); strings.Count(view, "execute_swap") != 1 {
+		t.Errorf("want the call once in the log:\n%s", view)
+	}
+
+	history := formatHistory(row.history)
+	want := "history: pending -> awaiting-confirmation +200ms -> pending (retrying) +1.5s -> success +2.4s"
+	if history != want {
+		t.Errorf("history = %q, want %q", history, want)
+	}
+	rc := m.renderCtx()
+	row.expanded = true
+	if expanded := formatLogEntry(rc, row); !strings.Contains(expanded, want) {
+		t.Errorf("expanded row lacks the history:\n%s", expanded)
+	}
+}
+
+// Entries without an ID, from older proxies, each get their own row.
+func TestLogEntriesWithoutID(t *testing.T) {
+	rc := renderCtx{spinner: "⠋", now: time.Now(), width: 120}
+	l := newLogPane(100)
+	for _, status := range []string{"pending", "success", "success"} {
+		if !l.add(rc, proxy.LogEntry{Timestamp: time.Now(), Tool: "get_token_info", Status: status}) {
+			t.Errorf("%s entry dropped", status)
+		}
+	}
+	if len(l.rows) != 3 {
+		t.Errorf("%d rows, want 3", len(l.rows))
+	}
+}
+
+// Identical errors in a row fold into one row with a count. Successes
+// never fold, rows still in flight are skipped over, and a different error
+// in between keeps runs apart.
+func TestLogFoldsRepeatedErrors(t *testing.T) {
+	rc := renderCtx{spinner: "⠋", now: time.Now(), width: 120}
+	l := newLogPane(100)
+	start := time.Date(2026, 1, 2, 14, 32, 0, 0, time.Local)
+	down := "upstream request failed: dial tcp: connection refused"
+	n := 0
+	add := func(tool, status, err string) {
+		n++
+		l.add(rc, proxy.LogEntry{ID: fmt.Sprintf("req-%d", n), Timestamp: start.Add(time.Duration(n) * time.Second), Tool: tool, Status: status, Error: err})
+	}
+
+	add("get_token_info", "error", down)
+	add("get_token_info", "error", down)
+	add("get_portfolio", "pending", "") // req-3, still running
+	add("get_token_info", "error", down)
+	add("get_token_info", "error", "timeout")
+	add("get_token_info", "error", down)
+	add("get_token_info", "success", "")
+	add("get_token_info", "success", "")
+	add("get_token_info", "error", down)
+
+	var got []string
+	for _, row := range l.rows {
+		got = append(got, fmt.Sprintf("%s %s+%d", row.entry.Status, row.entry.Error, row.repeats))
+	}
+	want := []string{
+		"error " + down + "+2",
+		"pending +0",
+		"error timeout+0",
+		"error " + down + "+0",
+		"success +0",
+		"success +0",
+		"error " + down + "+0",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("rows:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if line := formatLogEntry(rc, l.rows[0]); !strings.Contains(line, "×3, last 14:32:04") {
+		t.Errorf("folded row lacks its count:\n%s", line)
+	}
+
+	// The in-flight request still finds its row, and a late update of a
+	// folded one is dropped.
+	if !l.add(rc, proxy.LogEntry{ID: "req-3", Timestamp: start.Add(time.Minute), Tool: "get_portfolio", Status: "success"}) || l.rows[1].entry.Status != "success" {
+		t.Errorf("in-flight request not updated: %+v", l.rows[1].entry)
+	}
+	if l.add(rc, proxy.LogEntry{ID: "req-2", Timestamp: start.Add(time.Minute), Tool: "get_token_info", Status: "pending"}) {
+		t.Error("late update of a folded request applied")
+	}
+}
+
+// A pre-flight warning shows under the finished trade, not while pending.
+func TestLogEntryWarning(t *testing.T) {
+	entry := proxy.LogEntry{
+		ID:        "req-1",
+		Timestamp: time.Date(2026, 1, 2, 14, 5, 0, 0, time.Local),
+		Tool:      "execute_swap",
+		Status:    "success",
+		Preview:   "Swapped 1 SOL for 150 USDC",
+		Warning:   "low SOL for fees on Solana: 0.002 SOL held, about 0.01 SOL needed; the trade may fail",
+	}
+	lines := strings.Split(FormatLogEntry(entry), "\n")
+	if len(lines) < 2 || !strings.Contains(lines[0], "execute_swap") || !strings.Contains(lines[1], "⚠ low SOL for fees on Solana") {
+		t.Errorf("warning not under the trade:\n%s", strings.Join(lines, "\n"))
+	}
+
+	entry.Status = "pending"
+	if out := FormatLogEntry(entry); strings.Contains(out, "low SOL") {
+		t.Errorf("pending entry shows the warning:\n%s", out)
+	}
+}
+
+// A failed call shows the first block of its request ID.
+func TestLogEntryRequestID(t *testing.T) {
+	entry := proxy.LogEntry{
+		ID:        "req-1",
+		Timestamp: time.Date(2026, 1, 2, 14, 5, 0, 0, time.Local),
+		Tool:      "execute_swap",
+		Status:    "error",
+		Error:     "swap failed",
+		RequestID: "1a2b3c4d-5e6f-4a1b-8c2d-3e4f5a6b7c8d",
+	}
+	out := FormatLogEntry(entry)
+	if !strings.Contains(out, "req 1a2b3c4d") || strings.Contains(out, "5e6f") {
+		t.Errorf("want the short request ID:\n%s", out)
+	}
+	for id, want := range map[string]string{
+		"1a2b3c4d-5e6f": "1a2b3c4d",
+		"backendreq42":  "backendr",
+		"short":         "short",
+	} {
+		if got := shortRequestID(id); got != want {
+			t.Errorf("shortRequestID(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package tui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/orders"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/watchlist"
+)
+
+type LogMsg proxy.LogEntry
+type TickMsg time.Time
+type BootTickMsg struct{}
+type QuitStepMsg struct{}
+
+// BootStepMsg reports the result of a boot step's task.
+type BootStepMsg struct {
+	Step   int
+	Detail string
+	Err    error
+}
+
+// ShutdownMsg fires when `boba stop` asks the proxy to shut down.
+type ShutdownMsg struct{}
+type PortfolioMsg struct{ Data *PortfolioData }
+type ChainPortfolioMsg struct {
+	Slug string
+	Data *PortfolioData
+}
+
+// PortfolioPollMsg fires when the portfolio is due for a refresh. Seq drops
+// polls superseded by a newer schedule.
+type PortfolioPollMsg struct{ Seq int }
+
+// OrdersMsg carries the open orders; OrdersPollMsg fires when the Orders
+// tab is due for a refresh.
+type OrdersMsg struct {
+	Orders []orders.Order
+	Err    error
+}
+type OrdersPollMsg struct{}
+
+// WatchlistMsg carries the watched tokens with their prices;
+// WatchlistPollMsg fires when the Watchlist tab is due for a refresh.
+type WatchlistMsg struct {
+	Tokens []watchlist.Token
+	Err    error
+}
+type WatchlistPollMsg struct{}
+
+// TrendingMsg carries the trending tokens for the ticker; TrendingPollMsg
+// fires when the ticker is due for a refresh.
+type TrendingMsg struct {
+	Tokens []trendingToken
+	Err    error
+}
+type TrendingPollMsg struct{}
+
+// XPMsg carries the user's level and XP for the stats bar badge; OK is
+// false when they couldn't be fetched or read. XPPollMsg fires when the
+// badge is due for a refresh.
+type XPMsg struct {
+	XP formatter.UserXP
+	OK bool
+}
+type XPPollMsg struct{}
+
+// UpdateMsg tells the dashboard of a newer release, with the start of its
+// notes.
+type UpdateMsg struct {
+	Version string
+	Notes   string
+}
+
+// OrderCancelledMsg reports the outcome of cancelling an order from the
+// Orders tab.
+type OrderCancelledMsg struct {
+	ID  string
+	Err error
+}
+
+// OrdersCancelledMsg reports the outcome of cancelling every open order
+// with X on the Orders tab.
+type OrdersCancelledMsg struct {
+	Results []orders.Result
+}
+
+// WatchAddedMsg reports the outcome of adding a position's token to the
+// watchlist with w.
+type WatchAddedMsg struct {
+	Symbol string
+	Err    error
+}
+
+// CopiedMsg reports text copied to the clipboard with y, or why it couldn't
+// be.
+type CopiedMsg struct {
+	Text string
+	Err  error
+}
+
+// ToastDoneMsg fires when a stats bar toast expires, to redraw without it.
+type ToastDoneMsg struct{}
+
+// ResizeSettledMsg fires once the terminal has stopped resizing. Seq matches
+// the resize that scheduled it; stale ones are ignored.
+type ResizeSettledMsg struct{ Seq int }
+
+// resizeDebounce collapses a burst of WindowSizeMsg into one viewport recalc.
+const resizeDebounce = 100 * time.Millisecond
+
+func tickEvery(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg { return TickMsg(t) })
+}
+
+func bootTick() tea.Cmd {
+	return tea.Tick(40*time.Millisecond, func(_ time.Time) tea.Msg { return BootTickMsg{} })
+}
+
+func quitStep() tea.Cmd {
+	return tea.Tick(300*time.Millisecond, func(_ time.Time) tea.Msg { return QuitStepMsg{} })
+}
+
+func resizeSettled(seq int) tea.Cmd {
+	return tea.Tick(resizeDebounce, func(_ time.Time) tea.Msg { return ResizeSettledMsg{Seq: seq} })
+}
+
+func listenForShutdown(ch <-chan struct{}) tea.Cmd {
+	return func() tea.Msg {
+		<-ch
+		return ShutdownMsg{}
+	}
+}
+
+func listenForLogs(ch <-chan proxy.LogEntry) tea.Cmd {
+	return func() tea.Msg {
+		entry := <-ch
+		return LogMsg(entry)
+	}
+}
@@ -0,0 +1,326 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/orders"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// ordersPollInterval is how often the Orders tab refreshes while open.
+const ordersPollInterval = 60 * time.Second
+
+// ordersShown is how many order rows the panel lists at once; the window
+// scrolls to keep the cursor in view.
+const ordersShown = 8
+
+// orderColumn is one column of the orders table.
+type orderColumn struct {
+	title string
+	width int
+	cell  func(o orders.Order, c formatter.OrderCells) string
+}
+
+// orderColumns are the table's columns; the last two are dropped on narrow
+// panels.
+var orderColumns = []orderColumn{
+	{"Type", 7, func(o orders.Order, _ formatter.OrderCells) string { return strings.ToUpper(o.Kind.Name) }},
+	{"ID", 10, func(_ orders.Order, c formatter.OrderCells) string { return c.ID }},
+	{"Status", 11, func(_ orders.Order, c formatter.OrderCells) string { return c.Status }},
+	{"Side", 6, func(_ orders.Order, c formatter.OrderCells) string { return c.Side }},
+	{"Trigger $", 14, func(_ orders.Order, c formatter.OrderCells) string { return c.Trigger }},
+	{"To trigger", 14, func(_ orders.Order, c formatter.OrderCells) string { return c.Distance }},
+	{"Amount", 16, func(_ orders.Order, c formatter.OrderCells) string { return c.Amount }},
+	{"Est. value", 12, func(_ orders.Order, c formatter.OrderCells) string { return c.Est }},
+	{"Chain", 10, func(o orders.Order, _ formatter.OrderCells) string { return ui.DimStyle.Render(o.Chain) }},
+}
+
+// ordersPanel lists the open limit, DCA and TWAP orders on the Orders tab.
+// It is fetched once at startup for the tab's count, then polled only while
+// the tab is open.
+type ordersPanel struct {
+	list    []orders.Order
+	fetched time.Time
+	err     string // why the last refresh failed, if it did
+	loading bool
+	// polling is set while a poll is scheduled, so reopening the tab
+	// doesn't start a second poll loop.
+	polling bool
+
+	cursor int
+	detail bool // show the selected order in full
+
+	confirming bool   // asking whether to cancel the selected order
+	cancelling string // ID of the order being cancelled
+	cancelErr  string // why the last cancel failed, if it did
+
+	// cancelAll is the typed confirmation X asks for before cancelling
+	// every order, while it is open.
+	cancelAll     lineInput
+	cancellingAll int // how many orders X is cancelling, 0 when idle
+}
+
+func fetchOrders(server *proxy.ProxyServer) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		list, err := orders.Active(ctx, server.CallTool, orders.Cancel, orders.Filter{})
+		raw := make([]map[string]any, len(list))
+		for i, o := range list {
+			raw[i] = o.Raw
+		}
+		orders.FillCurrentPrices(ctx, server.CallTool, raw)
+		return OrdersMsg{Orders: list, Err: err}
+	}
+}
+
+// cancelOrder cancels o through the proxy and records the action in the
+// activity log.
+func cancelOrder(server *proxy.ProxyServer, o orders.Order) tea.Cmd {
+	return func() tea.Msg {
+		start := time.Now()
+		err := orders.Apply(context.Background(), server.CallTool, orders.Cancel, []orders.Order{o})[0].Err
+		logCancel(server, o, err, time.Since(start))
+		return OrderCancelledMsg{ID: o.ID, Err: err}
+	}
+}
+
+// cancelAllOrders cancels every order in list, as orders cancel-all does,
+// and records each in the activity log.
+func cancelAllOrders(server *proxy.ProxyServer, list []orders.Order) tea.Cmd {
+	return func() tea.Msg {
+		start := time.Now()
+		results := orders.Apply(context.Background(), server.CallTool, orders.Cancel, list)
+		for _, r := range results {
+			logCancel(server, r.Order, r.Err, time.Since(start))
+		}
+		return OrdersCancelledMsg{Results: results}
+	}
+}
+
+// logCancel records cancelling o from the dashboard in the activity log.
+func logCancel(server *proxy.ProxyServer, o orders.Order, err error, took time.Duration) {
+	entry := proxy.LogEntry{
+		Tool:     o.Kind.Cancel,
+		Status:   "success",
+		Duration: took,
+		Preview:  fmt.Sprintf("Cancelled %s order %s from the dashboard", o.Kind.Name, o.ID),
+		Args:     map[string]any{"order_id": o.ID},
+	}
+	if err != nil {
+		entry.Status = "error"
+		entry.Error = err.Error()
+	}
+	server.Log(entry)
+}
+
+// stillActive describes the orders a bulk cancel left open.
+func stillActive(failed []orders.Result, total int) string {
+	ids := make([]string, len(failed))
+	for i, r := range failed {
+		ids[i] = r.Order.ID
+	}
+	return fmt.Sprintf("%d of %d orders still active: %s", len(failed), total, strings.Join(ids, ", "))
+}
+
+func pollOrders() tea.Cmd {
+	return tea.Tick(ordersPollInterval, func(_ time.Time) tea.Msg { return OrdersPollMsg{} })
+}
+
+// receive stores a fetch result. A failed refresh keeps the orders already
+// on screen.
+func (p *ordersPanel) receive(msg OrdersMsg) {
+	p.loading = false
+	if msg.Err != nil {
+		p.err = msg.Err.Error()
+		return
+	}
+	p.err = ""
+	p.fetched = time.Now()
+	p.list = msg.Orders
+	if p.cursor >= len(p.list) {
+		p.cursor = len(p.list) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+	if len(p.list) == 0 {
+		p.detail = false
+	}
+}
+
+// count returns the number of open orders, or -1 before the first fetch.
+func (p ordersPanel) count() int {
+	if p.fetched.IsZero() {
+		return -1
+	}
+	return len(p.list)
+}
+
+// stale reports whether the orders are due for a refresh.
+func (p ordersPanel) stale() bool {
+	return time.Since(p.fetched) >= ordersPollInterval
+}
+
+func (p *ordersPanel) up() {
+	p.confirming = false
+	if p.cursor > 0 {
+		p.cursor--
+	}
+}
+
+func (p *ordersPanel) down() {
+	p.confirming = false
+	if p.cursor < len(p.list)-1 {
+		p.cursor++
+	}
+}
+
+// selected returns the order under the cursor.
+func (p ordersPanel) selected() (orders.Order, bool) {
+	if p.cursor < 0 || p.cursor >= len(p.list) {
+		return orders.Order{}, false
+	}
+	return p.list[p.cursor], true
+}
+
+// height returns the number of terminal lines the panel occupies.
+func (p ordersPanel) height(width int) int {
+	return lipgloss.Height(p.view(renderCtx{}, width))
+}
+
+func (p ordersPanel) view(rc renderCtx, width int) string {
+	dimStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	titleStyle := lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true)
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorGold).
+		Padding(0, 2)
+
+	if p.fetched.IsZero() {
+		if p.err != "" {
+			errMsg := lipgloss.NewStyle().Foreground(ui.ColorRed).Render("  Orders unavailable: " + ellipsize(p.err, 60))
+			return box.BorderForeground(ui.ColorDim).Render(errMsg)
+		}
+		return box.Render(dimStyle.Italic(true).Render("  " + rc.spinner + " Loading orders..."))
+	}
+
+	if p.detail {
+		if o, ok := p.selected(); ok {
+			out := formatter.FormatOrderDetail(o.Raw)
+			if line := p.cancelLine(rc); line != "" {
+				out += "\n" + line
+			}
+			return out + "\n" + dimStyle.Render("  enter back to the list · x cancel order")
+		}
+	}
+
+	badge := Freshness{FetchedAt: p.fetched, Interval: ordersPollInterval, Failed: p.err != ""}.Badge(rc.now)
+	summary := fmt.Sprintf("%d", len(p.list))
+	if total, _ := orders.TotalUSD(p.list); total > 0 {
+		summary += "  ≈ " + formatter.FormatUSD(total)
+	}
+	header := fmt.Sprintf("  %s  %s  %s", titleStyle.Render("OPEN ORDERS"), dimStyle.Render(summary), badge)
+	lines := []string{header, ""}
+
+	if len(p.list) == 0 {
+		lines = append(lines, dimStyle.Render("  No open orders"))
+		return box.Render(strings.Join(lines, "\n"))
+	}
+
+	// Columns as in formatter.FormatOrders, dropping the optional ones
+	// when the panel is narrow.
+	cols := orderColumns
+	if width-10 < 110 { // indent, borders and padding
+		cols = cols[:len(cols)-2]
+	}
+
+	headParts := []string{"  "}
+	for _, c := range cols {
+		headParts = append(headParts, lipgloss.NewStyle().Width(c.width).Bold(true).Render(c.title))
+	}
+	lines = append(lines, "  "+lipgloss.JoinHorizontal(lipgloss.Top, headParts...))
+
+	// Scroll the window so the cursor stays visible.
+	first := 0
+	if p.cursor >= ordersShown {
+		first = p.cursor - ordersShown + 1
+	}
+	last := first + ordersShown
+	if last > len(p.list) {
+		last = len(p.list)
+	}
+	cursorStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true)
+	for i := first; i < last; i++ {
+		o := p.list[i]
+		cells := formatter.OrderRow(o.Raw)
+		marker := "  "
+		if i == p.cursor {
+			marker = cursorStyle.Render("› ")
+		}
+		parts := []string{marker}
+		for _, c := range cols {
+			parts = append(parts, lipgloss.NewStyle().Width(c.width).MaxWidth(c.width).Render(c.cell(o, cells)))
+		}
+		lines = append(lines, "  "+lipgloss.JoinHorizontal(lipgloss.Top, parts...))
+	}
+	if len(p.list) > ordersShown {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("  %d-%d of %d", first+1, last, len(p.list))))
+	}
+	if footer := formatter.FormatCommitments(p.recurring()); footer != "" {
+		lines = append(lines, "", "  "+strings.ReplaceAll(footer, "\n", "\n  "))
+	}
+	if p.err != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorRed).Render("  Refresh failed: "+ellipsize(p.err, 60)))
+	}
+
+	if line := p.cancelLine(rc); line != "" {
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "", dimStyle.Render("  ↑↓ select · enter details · x cancel order · X cancel all"))
+	return box.Render(strings.Join(lines, "\n"))
+}
+
+// recurring returns the DCA and TWAP orders as the backend listed them,
+// for the committed-capital footer; limit orders commit nothing over time.
+func (p ordersPanel) recurring() []any {
+	var out []any
+	for _, o := range p.list {
+		if o.Kind.Name == "dca" || o.Kind.Name == "twap" {
+			out = append(out, o.Raw)
+		}
+	}
+	return out
+}
+
+// cancelLine is the prompt, progress or failure of cancelling an order, or
+// "" when no cancel is under way.
+func (p ordersPanel) cancelLine(rc renderCtx) string {
+	switch {
+	case p.cancelAll.active:
+		phrase := orders.Cancel.ConfirmPhrase(len(p.list))
+		return lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render(
+			fmt.Sprintf("  type %q to cancel every order, esc to abort: ", phrase)) + p.cancelAll.value + "▏"
+	case p.cancellingAll > 0:
+		return lipgloss.NewStyle().Foreground(ui.ColorDim).Italic(true).Render(
+			fmt.Sprintf("  %s Cancelling %d orders...", rc.spinner, p.cancellingAll))
+	case p.confirming:
+		if o, ok := p.selected(); ok {
+			return lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render(
+				fmt.Sprintf("  cancel %s order %s? y/n", o.Kind.Name, o.ID))
+		}
+	case p.cancelling != "":
+		return lipgloss.NewStyle().Foreground(ui.ColorDim).Italic(true).Render(
+			fmt.Sprintf("  %s Cancelling order %s...", rc.spinner, p.cancelling))
+	case p.cancelErr != "":
+		return lipgloss.NewStyle().Foreground(ui.ColorRed).Render("  Cancel failed: " + ellipsize(p.cancelErr, 80))
+	}
+	return ""
+}
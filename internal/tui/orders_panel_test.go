@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/orders"
+)
+
+// ordersModel returns a running dashboard on the Orders tab listing n
+// limit orders.
+func ordersModel(t *testing.T, n int) ProxyViewModel {
+	t.Helper()
+	m := runningModel(t)
+	m.tabs.active = len(m.tabs.tabs) - 1
+	limit, _ := orders.KindByName("limit")
+	var list []orders.Order
+	for i := range n {
+		id := string(rune('a' + i))
+		list = append(list, orders.Order{ID: id, Kind: limit, Status: "active", Raw: map[string]any{"id": id}})
+	}
+	m.orders.receive(OrdersMsg{Orders: list})
+	return m
+}
+
+// typeKeys sends text to the model a key at a time, then enter.
+func typeKeys(m tea.Model, text string) (tea.Model, tea.Cmd) {
+	for _, r := range text {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+// X cancels every open order only once "cancel N orders" is typed, and
+// lists the ones left active when some fail.
+func TestCancelAllOrders(t *testing.T) {
+	var model tea.Model = ordersModel(t, 3)
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	m := model.(ProxyViewModel)
+	if !m.orders.cancelAll.active {
+		t.Fatal("X didn't ask for confirmation")
+	}
+	if view := m.orders.view(m.renderCtx(), 120); !strings.Contains(view, `type "cancel 3 orders"`) {
+		t.Errorf("prompt not shown:\n%s", view)
+	}
+
+	// Keys go to the prompt, so q doesn't quit.
+	model, cmd := typeKeys(model, "cancel 3 orderq")
+	m = model.(ProxyViewModel)
+	if cmd != nil || m.orders.cancellingAll != 0 || !strings.Contains(m.orders.cancelErr, "nothing cancelled") {
+		t.Errorf("wrong phrase: cancelling %d, err %q", m.orders.cancellingAll, m.orders.cancelErr)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m := model.(ProxyViewModel); m.orders.cancelAll.active || m.orders.cancellingAll != 0 {
+		t.Error("esc didn't abort")
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")})
+	model, cmd = typeKeys(model, "cancel 3 orders")
+	m = model.(ProxyViewModel)
+	if cmd == nil || m.orders.cancellingAll != 3 || m.orders.cancelErr != "" {
+		t.Fatalf("right phrase: cmd %v, cancelling %d, err %q", cmd != nil, m.orders.cancellingAll, m.orders.cancelErr)
+	}
+	if model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("X")}); model.(ProxyViewModel).orders.cancelAll.active {
+		t.Error("second cancel-all started while one runs")
+	}
+
+	list := m.orders.list
+	model, _ = model.Update(OrdersCancelledMsg{Results: []orders.Result{
+		{Order: list[0]},
+		{Order: list[1], Err: errors.New("timeout")},
+		{Order: list[2]},
+	}})
+	m = model.(ProxyViewModel)
+	if m.orders.cancellingAll != 0 || m.orders.cancelErr != "1 of 3 orders still active: b" {
+		t.Errorf("after a partial failure: cancelling %d, err %q", m.orders.cancellingAll, m.orders.cancelErr)
+	}
+}
+
+// The Orders tab sums what the DCA and TWAP orders still commit, against
+// the cached balance, and leaves limit orders out of it.
+func TestOrdersPanelCommitments(t *testing.T) {
+	formatter.SetCachedBalances(map[string]float64{formatter.BalanceKey("base", "USDC"): 20})
+	t.Cleanup(func() { formatter.SetCachedBalances(nil) })
+	limit, _ := orders.KindByName("limit")
+	dca, _ := orders.KindByName("dca")
+	var p ordersPanel
+	p.receive(OrdersMsg{Orders: []orders.Order{
+		{ID: "l1", Kind: limit, Raw: map[string]any{"id": "l1", "status": "active", "chain": "base", "input_symbol": "USDC", "total_amount": 500.0}},
+		{ID: "d1", Kind: dca, Raw: map[string]any{"id": "d1", "status": "paused", "chain": "base", "input_symbol": "USDC",
+			"amount_per_interval": 10.0, "total_intervals": 5.0, "executed_intervals": 2.0}},
+	}})
+	view := p.view(renderCtx{now: time.Now()}, 160)
+	var footer string
+	for _, line := range strings.Split(view, "\n") {
+		if strings.Contains(line, "USDC") {
+			footer = line
+		}
+	}
+	// 3 intervals of 10 left; the limit order's 500 isn't counted.
+	for _, want := range []string{"30.00", "(1 order, 1 paused)", "avail 20.00 — exceeds balance"} {
+		if !strings.Contains(footer, want) {
+			t.Errorf("footer lacks %q:\n%s", want, view)
+		}
+	}
+
+	p.receive(OrdersMsg{Orders: p.list[:1]})
+	if view := p.view(renderCtx{now: time.Now()}, 160); strings.Contains(view, "Committed Capital") {
+		t.Errorf("footer without DCA or TWAP orders:\n%s", view)
+	}
+}
@@ -0,0 +1,345 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+)
+
+// PortfolioData holds the parsed portfolio state for the TUI panel.
+type PortfolioData struct {
+	TotalValueUSD    float64
+	PositionValueUSD float64
+	NativeValueUSD   float64
+	Positions        []PortfolioPosition
+	NativeBalances   []NativeBalance
+	LastUpdated      time.Time
+	Error            string
+	// RefreshFailed marks data kept from an earlier fetch after a refresh
+	// failed; LastUpdated still refers to that earlier fetch.
+	RefreshFailed bool
+}
+
+type PortfolioPosition struct {
+	ChainName    string
+	Symbol       string
+	TokenAddress string
+	Balance      float64
+	ValueUSD     float64
+	PnlPercent   float64
+	PriceUSD     float64
+	// PnlUSD and CostBasisUSD are only meaningful when HasPnl is set; the
+	// backend doesn't report them for every position.
+	PnlUSD       float64
+	CostBasisUSD float64
+	HasPnl       bool
+}
+
+// TotalPnl sums the PnL of the positions that report one. ok is false when
+// none do.
+func (d *PortfolioData) TotalPnl() (total float64, ok bool) {
+	for _, p := range d.Positions {
+		if p.HasPnl {
+			total += p.PnlUSD
+			ok = true
+		}
+	}
+	return total, ok
+}
+
+type NativeBalance struct {
+	ChainID    int
+	ChainName  string
+	Symbol     string
+	Balance    float64
+	BalanceUSD float64
+}
+
+func fetchPortfolio(server *proxy.ProxyServer) tea.Cmd {
+	return func() tea.Msg {
+		args := map[string]any{"user_id": "me"}
+		respBody, err := server.CallTool(context.Background(), "get_portfolio", args)
+		if err != nil {
+			return PortfolioMsg{Data: &PortfolioData{
+				Error:       err.Error(),
+				LastUpdated: time.Now(),
+			}}
+		}
+
+		var raw map[string]any
+		if err := json.Unmarshal(respBody, &raw); err != nil {
+			return PortfolioMsg{Data: &PortfolioData{
+				Error:       "failed to parse portfolio data",
+				LastUpdated: time.Now(),
+			}}
+		}
+
+		data := &PortfolioData{
+			TotalValueUSD:    parseFloat(raw, "total_value_usd"),
+			PositionValueUSD: parseFloat(raw, "position_value_usd"),
+			NativeValueUSD:   parseFloat(raw, "native_value_usd"),
+			LastUpdated:      time.Now(),
+		}
+
+		// Parse positions, in the order the backend sent them; the panels
+		// sort them for display.
+		if positions, ok := raw["positions"].([]any); ok {
+			for _, p := range positions {
+				pos, ok := p.(map[string]any)
+				if !ok {
+					continue
+				}
+				data.Positions = append(data.Positions, parsePosition(pos))
+			}
+		}
+
+		// Parse native balances
+		if balances, ok := raw["native_balances"].([]any); ok {
+			for _, b := range balances {
+				bal, ok := b.(map[string]any)
+				if !ok {
+					continue
+				}
+				data.NativeBalances = append(data.NativeBalances, parseNativeBalance(bal))
+			}
+		}
+
+		formatter.SetCachedBalances(data.balanceTable())
+
+		return PortfolioMsg{Data: data}
+	}
+}
+
+// balanceTable maps each chain's token addresses and symbols to held
+// balances so the formatter can compare order commitments against what the
+// agent holds. Rows are keyed by chain as well: USDC on Base doesn't fund an
+// order spending USDC on Solana.
+func (d *PortfolioData) balanceTable() map[string]float64 {
+	table := make(map[string]float64)
+	for _, p := range d.Positions {
+		if p.TokenAddress != "" {
+			table[formatter.BalanceKey(p.ChainName, p.TokenAddress)] += p.Balance
+		}
+		if p.Symbol != "" {
+			table[formatter.BalanceKey(p.ChainName, p.Symbol)] += p.Balance
+		}
+	}
+	for _, b := range d.NativeBalances {
+		if b.Symbol != "" {
+			table[formatter.BalanceKey(b.ChainName, b.Symbol)] += b.Balance
+		}
+	}
+	return table
+}
+
+// fetchChainPortfolio fetches portfolio data filtered to a specific chain.
+// The MCP get_portfolio tool accepts a "chain" string slug (e.g. "solana", "eth").
+func fetchChainPortfolio(server *proxy.ProxyServer, chainSlug string) tea.Cmd {
+	return func() tea.Msg {
+		args := map[string]any{
+			"user_id": "me",
+			"chain":   chainSlug,
+		}
+		respBody, err := server.CallTool(context.Background(), "get_portfolio", args)
+		if err != nil {
+			return ChainPortfolioMsg{Slug: chainSlug, Data: &PortfolioData{
+				Error:       err.Error(),
+				LastUpdated: time.Now(),
+			}}
+		}
+
+		var raw map[string]any
+		if err := json.Unmarshal(respBody, &raw); err != nil {
+			return ChainPortfolioMsg{Slug: chainSlug, Data: &PortfolioData{
+				Error:       "failed to parse chain portfolio",
+				LastUpdated: time.Now(),
+			}}
+		}
+
+		data := &PortfolioData{
+			TotalValueUSD:    parseFloat(raw, "total_value_usd"),
+			PositionValueUSD: parseFloat(raw, "position_value_usd"),
+			NativeValueUSD:   parseFloat(raw, "native_value_usd"),
+			LastUpdated:      time.Now(),
+		}
+
+		if positions, ok := raw["positions"].([]any); ok {
+			for _, p := range positions {
+				pos, ok := p.(map[string]any)
+				if !ok {
+					continue
+				}
+				data.Positions = append(data.Positions, parsePosition(pos))
+			}
+		}
+
+		if balances, ok := raw["native_balances"].([]any); ok {
+			for _, b := range balances {
+				bal, ok := b.(map[string]any)
+				if !ok {
+					continue
+				}
+				data.NativeBalances = append(data.NativeBalances, parseNativeBalance(bal))
+			}
+		}
+
+		return ChainPortfolioMsg{Slug: chainSlug, Data: data}
+	}
+}
+
+// parsePosition reads one entry of a portfolio's positions, accepting the
+// key names the backend has used for each field.
+func parsePosition(pos map[string]any) PortfolioPosition {
+	p := PortfolioPosition{
+		ChainName:    firstString(pos, "chain_name", "chain", "chainName", "network"),
+		Symbol:       parseString(pos, "symbol"),
+		TokenAddress: firstString(pos, "token_address", "address", "mint"),
+		ValueUSD:     parseFloat(pos, "value_usd"),
+		PnlPercent:   parseFloat(pos, "pnl_percent"),
+		PriceUSD:     parseFloat(pos, "price_usd"),
+	}
+	p.Balance, _ = firstFloat(pos, "balance", "amount", "quantity")
+
+	pnl, hasPnl := firstFloat(pos, "pnl_usd", "pnlUsd", "unrealized_pnl_usd", "pnl")
+	cost, hasCost := firstFloat(pos, "entry_value_usd", "entryValueUsd", "cost_basis", "cost_basis_usd", "costBasis")
+	switch {
+	case hasPnl:
+		p.PnlUSD, p.HasPnl = pnl, true
+		if hasCost {
+			p.CostBasisUSD = cost
+		} else {
+			p.CostBasisUSD = p.ValueUSD - pnl
+		}
+	case hasCost:
+		p.CostBasisUSD = cost
+		p.PnlUSD, p.HasPnl = p.ValueUSD-cost, true
+	}
+	return p
+}
+
+// parseNativeBalance reads one entry of a portfolio's native balances,
+// accepting the same chain keys as parsePosition.
+func parseNativeBalance(bal map[string]any) NativeBalance {
+	id, _ := firstFloat(bal, "chain_id", "chainId")
+	return NativeBalance{
+		ChainID:    int(id),
+		ChainName:  firstString(bal, "chain_name", "chain", "chainName", "network"),
+		Symbol:     parseString(bal, "symbol"),
+		Balance:    parseFloat(bal, "balance"),
+		BalanceUSD: parseFloat(bal, "balance_usd"),
+	}
+}
+
+// fillFromAll completes a chain's portfolio from the All portfolio when the
+// chain-filtered response leaves out the native balances, which the backend
+// sometimes does: the chain's balances are taken from all, and a missing
+// total is rebuilt from the positions and those balances.
+func (d *PortfolioData) fillFromAll(all *PortfolioData, slug string) {
+	if d.Error != "" || len(d.NativeBalances) > 0 || all == nil || all.Error != "" {
+		return
+	}
+	for _, nb := range all.NativeBalances {
+		if onChain(nb.ChainName, nb.ChainID, slug) {
+			d.NativeBalances = append(d.NativeBalances, nb)
+		}
+	}
+	if len(d.NativeBalances) == 0 || d.TotalValueUSD != 0 {
+		return
+	}
+	var positions, natives float64
+	for _, p := range d.Positions {
+		positions += p.ValueUSD
+	}
+	for _, nb := range d.NativeBalances {
+		natives += nb.BalanceUSD
+	}
+	d.NativeValueUSD = natives
+	if d.PositionValueUSD == 0 {
+		d.PositionValueUSD = positions
+	}
+	d.TotalValueUSD = positions + natives
+}
+
+// onChain reports whether a balance reported with the given chain name or
+// ID belongs to the chain with slug. Names are matched by any alias and
+// regardless of case.
+func onChain(name string, id int, slug string) bool {
+	if c, ok := config.LookupChainID(id); id != 0 && ok {
+		return c.Slug == slug
+	}
+	if c, ok := config.LookupChain(name); ok {
+		return c.Slug == slug
+	}
+	return name != "" && strings.EqualFold(strings.TrimSpace(name), slug)
+}
+
+// firstFloat returns the value of the first of keys that m holds a number
+// for, as a JSON number or a numeric string. ok is false when none does.
+func firstFloat(m map[string]any, keys ...string) (float64, bool) {
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case float64:
+			return v, true
+		case string:
+			s := strings.ReplaceAll(strings.TrimSuffix(strings.TrimSpace(v), "%"), ",", "")
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// firstString returns the first non-empty string among keys in m.
+func firstString(m map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if s := parseString(m, k); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// parseFloat safely extracts a float64 from a map, handling string values.
+func parseFloat(m map[string]any, key string) float64 {
+	v, ok := m[key]
+	if !ok {
+		return 0
+	}
+	switch n := v.(type) {
+	case float64:
+		return n
+	case string:
+		s := strings.TrimSpace(n)
+		s = strings.TrimSuffix(s, "%")
+		s = strings.ReplaceAll(s, ",", "")
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return 0
+		}
+		return f
+	default:
+		return 0
+	}
+}
+
+// parseString safely extracts a string from a map.
+func parseString(m map[string]any, key string) string {
+	v, ok := m[key]
+	if !ok {
+		return ""
+	}
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Sprintf("%v", v)
+	}
+	return s
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/formatter"
+)
+
+// Balances add up per chain and token, never across chains.
+func TestBalanceTable(t *testing.T) {
+	d := &PortfolioData{
+		Positions: []PortfolioPosition{
+			{ChainName: "Solana", Symbol: "USDC", TokenAddress: "EPjFWdd5", Balance: 100},
+			{ChainName: "Base", Symbol: "USDC", TokenAddress: "0x8335", Balance: 40},
+			{ChainName: "Base", Symbol: "USDC", TokenAddress: "0xd9aa", Balance: 2},
+		},
+		NativeBalances: []NativeBalance{
+			{ChainName: "Ethereum", Symbol: "ETH", Balance: 1.5},
+			{ChainName: "Base", Symbol: "ETH", Balance: 0.25},
+		},
+	}
+	want := map[string]float64{
+		formatter.BalanceKey("Solana", "EPjFWdd5"): 100,
+		formatter.BalanceKey("Solana", "USDC"):     100,
+		formatter.BalanceKey("Base", "0x8335"):     40,
+		formatter.BalanceKey("Base", "0xd9aa"):     2,
+		formatter.BalanceKey("Base", "USDC"):       42,
+		formatter.BalanceKey("Ethereum", "ETH"):    1.5,
+		formatter.BalanceKey("Base", "ETH"):        0.25,
+	}
+	got := d.balanceTable()
+	if len(got) != len(want) {
+		t.Errorf("got %d rows, want %d: %v", len(got), len(want), got)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+// Every key name the backend has used for a field is read, numbers may be
+// strings, and PnL and cost basis are derived from each other and the
+// value when only one is sent.
+func TestParsePosition(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		pos  map[string]any
+		want PortfolioPosition
+	}{
+		{
+			name: "snake case",
+			pos: map[string]any{
+				"chain_name": "Solana", "symbol": "WIF", "token_address": "EKpQ", "balance": 10.0,
+				"value_usd": 25.0, "pnl_percent": 25.0, "price_usd": 2.5,
+				"pnl_usd": 5.0, "entry_value_usd": 20.0,
+			},
+			want: PortfolioPosition{ChainName: "Solana", Symbol: "WIF", TokenAddress: "EKpQ", Balance: 10,
+				ValueUSD: 25, PnlPercent: 25, PriceUSD: 2.5, PnlUSD: 5, CostBasisUSD: 20, HasPnl: true},
+		},
+		{
+			name: "camel case and strings",
+			pos: map[string]any{
+				"chainName": "Base", "symbol": "BRETT", "address": "0x532f", "amount": "1,500",
+				"value_usd": "300.00", "pnl_percent": "-25%", "pnlUsd": "-100", "costBasis": "400",
+			},
+			want: PortfolioPosition{ChainName: "Base", Symbol: "BRETT", TokenAddress: "0x532f", Balance: 1500,
+				ValueUSD: 300, PnlPercent: -25, PnlUSD: -100, CostBasisUSD: 400, HasPnl: true},
+		},
+		{
+			name: "pnl only",
+			pos:  map[string]any{"network": "Ethereum", "mint": "0xabc", "quantity": 2.0, "value_usd": 50.0, "unrealized_pnl_usd": 10.0},
+			want: PortfolioPosition{ChainName: "Ethereum", TokenAddress: "0xabc", Balance: 2, ValueUSD: 50, PnlUSD: 10, CostBasisUSD: 40, HasPnl: true},
+		},
+		{
+			name: "cost basis only",
+			pos:  map[string]any{"chain": "solana", "value_usd": 80.0, "cost_basis_usd": "100"},
+			want: PortfolioPosition{ChainName: "solana", ValueUSD: 80, PnlUSD: -20, CostBasisUSD: 100, HasPnl: true},
+		},
+		{
+			name: "entryValueUsd and bare pnl",
+			pos:  map[string]any{"value_usd": 12.0, "pnl": 2.0, "entryValueUsd": 10.0},
+			want: PortfolioPosition{ValueUSD: 12, PnlUSD: 2, CostBasisUSD: 10, HasPnl: true},
+		},
+		{
+			name: "no pnl",
+			pos:  map[string]any{"symbol": "USDC", "balance": "100", "value_usd": 100.0, "cost_basis": "n/a"},
+			want: PortfolioPosition{Symbol: "USDC", Balance: 100, ValueUSD: 100},
+		},
+	} {
+		if got := parsePosition(tc.pos); got != tc.want {
+			t.Errorf("%s:\n got %+v\nwant %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTotalPnl(t *testing.T) {
+	d := &PortfolioData{Positions: []PortfolioPosition{
+		{PnlUSD: 5, HasPnl: true},
+		{PnlUSD: -12.5, HasPnl: true},
+		{ValueUSD: 100},
+	}}
+	if total, ok := d.TotalPnl(); !ok || total != -7.5 {
+		t.Errorf("TotalPnl = %v, %v; want -7.5", total, ok)
+	}
+	if _, ok := (&PortfolioData{Positions: []PortfolioPosition{{ValueUSD: 1}}}).TotalPnl(); ok {
+		t.Error("TotalPnl reported without any position's PnL")
+	}
+}
+
+// A chain's balances are recognized by any of its names, its slug or its
+// ID, whatever the case.
+func TestOnChain(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		id   int
+		slug string
+		want bool
+	}{
+		{"Base", 0, "base", true},
+		{" BASE ", 0, "base", true},
+		{"Ethereum", 0, "eth", true},
+		{"mainnet", 0, "eth", true},
+		{"", 1, "eth", true},
+		{"Binance", 0, "bsc", true},
+		{"Base", 0, "eth", false},
+		{"", 8453, "base", true},
+		{"Ethereum", 8453, "eth", false}, // the ID wins over the name
+		{"zora", 0, "ZORA", true},        // unknown chains match by name
+		{"", 0, "base", false},
+	} {
+		if got := onChain(tc.name, tc.id, tc.slug); got != tc.want {
+			t.Errorf("onChain(%q, %d, %q) = %v, want %v", tc.name, tc.id, tc.slug, got, tc.want)
+		}
+	}
+}
+
+// A chain response without native balances borrows them from the All
+// response, however either names the chain, and rebuilds a missing total.
+func TestFillFromAll(t *testing.T) {
+	all := &PortfolioData{NativeBalances: []NativeBalance{
+		parseNativeBalance(map[string]any{"chain": "ethereum", "symbol": "ETH", "balance": "1.5", "balance_usd": "4500"}),
+		parseNativeBalance(map[string]any{"chainName": "BASE", "symbol": "ETH", "balance": 0.1, "balance_usd": 300.0}),
+		parseNativeBalance(map[string]any{"network": "Base", "symbol": "ETH", "balance": 0.05, "balance_usd": 150.0}),
+		parseNativeBalance(map[string]any{"chainId": 8453.0, "symbol": "ETH", "balance": 0.01, "balance_usd": 30.0}),
+		parseNativeBalance(map[string]any{"chain_name": "Solana", "symbol": "SOL", "balance": 2.0, "balance_usd": 300.0}),
+	}}
+
+	base := &PortfolioData{Positions: []PortfolioPosition{{Symbol: "BRETT", ValueUSD: 20}, {Symbol: "DEGEN", ValueUSD: 5}}}
+	base.fillFromAll(all, "base")
+	if len(base.NativeBalances) != 3 {
+		t.Fatalf("base got %d native balances, want 3: %+v", len(base.NativeBalances), base.NativeBalances)
+	}
+	if base.TotalValueUSD != 505 || base.PositionValueUSD != 25 || base.NativeValueUSD != 480 {
+		t.Errorf("base totals: %v = %v + %v, want 505 = 25 + 480", base.TotalValueUSD, base.PositionValueUSD, base.NativeValueUSD)
+	}
+
+	eth := &PortfolioData{TotalValueUSD: 9999}
+	eth.fillFromAll(all, "eth")
+	if len(eth.NativeBalances) != 1 || eth.TotalValueUSD != 9999 {
+		t.Errorf("eth: %d balances, total %v; want 1 and the total kept", len(eth.NativeBalances), eth.TotalValueUSD)
+	}
+
+	// Balances the chain response has, failed fetches and chains with
+	// nothing in All are left alone.
+	own := &PortfolioData{NativeBalances: []NativeBalance{{ChainName: "Base", BalanceUSD: 1}}}
+	own.fillFromAll(all, "base")
+	failed := &PortfolioData{Error: "timeout"}
+	failed.fillFromAll(all, "base")
+	monad := &PortfolioData{}
+	monad.fillFromAll(all, "monad")
+	if len(own.NativeBalances) != 1 || len(failed.NativeBalances) != 0 || len(monad.NativeBalances) != 0 || monad.TotalValueUSD != 0 {
+		t.Errorf("filled what it shouldn't: own %v, failed %v, monad %+v", own.NativeBalances, failed.NativeBalances, monad)
+	}
+	(&PortfolioData{}).fillFromAll(nil, "base")
+}
@@ -0,0 +1,341 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// pnlColumnsWidth is the narrowest terminal the portfolio panels add PnL
+// and cost-basis columns on.
+const pnlColumnsWidth = 80
+
+// portfolioPanel is the compact portfolio summary on the All tab.
+type portfolioPanel struct {
+	data    *PortfolioData
+	loading bool
+	order   positionOrder
+	editing bool // the filter is being typed
+	pick    int  // position marked for adding to the watchlist, or -1
+}
+
+// visible reports whether the panel takes any space yet.
+func (p portfolioPanel) visible() bool {
+	return p.data != nil || p.loading
+}
+
+// receive stores a fetch result, keeping the old data if the refresh failed.
+func (p *portfolioPanel) receive(d *PortfolioData) {
+	p.data = keepOnFailure(p.data, d)
+	p.loading = false
+}
+
+// height returns the number of terminal lines the panel occupies,
+// including borders.
+func (p portfolioPanel) height() int {
+	if !p.visible() {
+		return 0
+	}
+	if p.data == nil || p.data.Error != "" {
+		return 3 // border top + content + border bottom
+	}
+
+	contentLines := 2 // header + blank
+	nativeCount := len(p.order.natives(p.data.NativeBalances))
+	if nativeCount > 0 {
+		contentLines += nativeCount
+		contentLines++ // blank after natives
+	}
+	posCount := len(p.order.apply(p.data.Positions))
+	if posCount == 0 {
+		contentLines++
+	} else {
+		shown := posCount
+		if shown > 4 {
+			shown = 4
+		}
+		contentLines += shown
+		if posCount > 4 {
+			contentLines++
+		}
+	}
+	if p.order.hiddenDust(p.data) > 0 {
+		contentLines++
+	}
+	return contentLines + 2 // +2 for borders
+}
+
+func (p portfolioPanel) view(rc renderCtx) string {
+	d := p.data
+
+	// Loading state — fetch in-flight, no data yet
+	if d == nil {
+		loadingMsg := lipgloss.NewStyle().Foreground(ui.ColorDim).Italic(true).
+			Render("  " + rc.spinner + " Loading portfolio...")
+		return lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(ui.ColorGold).
+			Padding(0, 2).
+			Render(loadingMsg)
+	}
+
+	// Error state
+	if d.Error != "" {
+		dimMsg := lipgloss.NewStyle().Foreground(ui.ColorDim).Italic(true).
+			Render("  Portfolio unavailable")
+		return lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(ui.ColorDim).
+			Padding(0, 2).
+			Render(dimMsg)
+	}
+
+	if formatter.Accessible {
+		return p.viewAccessible(rc)
+	}
+
+	var lines []string
+
+	// Header line: "PORTFOLIO  Total: $2,150.50    ↻ 25s"
+	titleStyle := lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true)
+	totalStr := formatter.FormatUSD(d.TotalValueUSD)
+
+	headerLine := fmt.Sprintf("  %s  Total: %s", titleStyle.Render("PORTFOLIO"), totalStr)
+	if pnl, ok := d.TotalPnl(); ok {
+		headerLine += "  PnL: " + formatter.FormatPnLUSD(pnl)
+	}
+	headerLine += "  " + refreshBadge(rc, d, p.loading) + orderTag(p.order, p.editing)
+	lines = append(lines, headerLine)
+	lines = append(lines, "")
+
+	// Native balances
+	natives := p.order.natives(d.NativeBalances)
+	if len(natives) > 0 {
+		// Find max symbol length for alignment
+		maxSymLen := 0
+		for _, nb := range natives {
+			if len(nb.Symbol) > maxSymLen {
+				maxSymLen = len(nb.Symbol)
+			}
+		}
+
+		for _, nb := range natives {
+			dot := lipgloss.NewStyle().Foreground(ui.ColorCyan).Render("●")
+			symStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
+			chainStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+			goldStyle := lipgloss.NewStyle().Foreground(ui.ColorGold)
+			// Pad symbol to max length for alignment
+			paddedSym := nb.Symbol + strings.Repeat(" ", maxSymLen-len(nb.Symbol))
+			balStr := fmt.Sprintf("%.3f", nb.Balance)
+			usdStr := goldStyle.Render(formatter.DisplayCurrency().Amount(nb.BalanceUSD, 2))
+			chain := ""
+			if nb.ChainName != "" {
+				chain = chainStyle.Render("  (" + nb.ChainName + ")")
+			}
+			line := fmt.Sprintf("  %s %s  %s  %s%s",
+				dot,
+				symStyle.Render(paddedSym),
+				balStr,
+				usdStr,
+				chain)
+			lines = append(lines, line)
+		}
+		lines = append(lines, "")
+	}
+
+	// Positions (max 4)
+	positions := p.order.apply(d.Positions)
+	if len(positions) == 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorDim).Render("  "+noPositions(p.order)))
+	} else {
+		shown := positions
+		if len(shown) > 4 {
+			shown = shown[:4]
+		}
+		var rows []string
+		if rc.width >= pnlColumnsWidth {
+			rows = positionRowsWithPnl(shown)
+		} else {
+			for _, pos := range shown {
+				symStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
+				valStr := formatter.FormatUSD(pos.ValueUSD)
+				pnlStr := formatter.FormatPercent(pos.PnlPercent)
+				line := fmt.Sprintf("  %s  %s  %s",
+					symStyle.Render(pos.Symbol),
+					valStr,
+					pnlStr)
+				rows = append(rows, line)
+			}
+		}
+		lines = append(lines, markPick(rows, p.pick)...)
+		if len(positions) > 4 {
+			more := len(positions) - 4
+			lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorDim).
+				Render(fmt.Sprintf("  +%d more", more)))
+		}
+	}
+	if hidden := p.order.hiddenDust(d); hidden > 0 {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorDim).Render("  "+dustNote(p.order, hidden)))
+	}
+
+	content := strings.Join(lines, "\n")
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorGold).
+		BorderTop(true).
+		Padding(0, 2).
+		Render(content)
+}
+
+// positionRowsWithPnl renders positions as aligned columns: symbol, value,
+// PnL in dollars, PnL percent and cost basis. Positions without PnL data
+// show a dash for both.
+func positionRowsWithPnl(positions []PortfolioPosition) []string {
+	symStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+
+	cells := make([][5]string, len(positions))
+	var widths [5]int
+	for i, pos := range positions {
+		cells[i][0] = symStyle.Render(pos.Symbol)
+		cells[i][1] = formatter.FormatUSD(pos.ValueUSD)
+		cells[i][3] = formatter.FormatPercent(pos.PnlPercent)
+		if pos.HasPnl {
+			cells[i][2] = formatter.FormatPnLUSD(pos.PnlUSD)
+			cells[i][4] = dimStyle.Render("cost " + formatter.FormatUSD(pos.CostBasisUSD))
+		} else {
+			cells[i][2] = dimStyle.Render("—")
+		}
+		for c := range widths {
+			widths[c] = max(widths[c], lipgloss.Width(cells[i][c]))
+		}
+	}
+
+	lines := make([]string, len(positions))
+	for i, row := range cells {
+		lines[i] = fmt.Sprintf("  %s  %s  %s  %s  %s",
+			padRight(row[0], widths[0]),
+			padLeft(row[1], widths[1]),
+			padLeft(row[2], widths[2]),
+			padLeft(row[3], widths[3]),
+			row[4])
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	return lines
+}
+
+// markPick points at row pick of a panel's position rows while a position
+// is being picked for the watchlist.
+func markPick(rows []string, pick int) []string {
+	if pick < 0 || pick >= len(rows) {
+		return rows
+	}
+	marker := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true).Render("› ")
+	rows[pick] = marker + strings.TrimPrefix(rows[pick], "  ")
+	return rows
+}
+
+// padRight pads a styled string with spaces to width display columns.
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-lipgloss.Width(s), 0))
+}
+
+// padLeft right-aligns a styled string in width display columns.
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(width-lipgloss.Width(s), 0)) + s
+}
+
+// refreshBadge renders a panel's refresh indicator followed by its age:
+// spinner while loading, green dot just after fresh data, pulsing dot
+// otherwise.
+func refreshBadge(rc renderCtx, d *PortfolioData, loading bool) string {
+	f := d.freshness(portfolioPollInterval)
+	now := rc.now
+
+	var dot string
+	switch {
+	case loading:
+		dot = rc.spinner
+	case f.JustRefreshed(now):
+		dot = lipgloss.NewStyle().Foreground(ui.ColorGreen).Bold(true).Render("●")
+	case rc.idleFrame%2 == 0:
+		dot = lipgloss.NewStyle().Foreground(ui.ColorDim).Render("●")
+	default:
+		dot = lipgloss.NewStyle().Foreground(ui.ColorPending).Render("●")
+	}
+
+	if badge := f.Badge(now); badge != "" {
+		return dot + " " + badge
+	}
+	return dot
+}
+
+// viewAccessible renders the portfolio panel as plain
+// sentences without borders, dots or color-only signals. Vertical padding
+// replaces the border so the panel keeps the same height.
+func (p portfolioPanel) viewAccessible(rc renderCtx) string {
+	d := p.data
+
+	header := "Portfolio total: " + formatter.FormatUSD(d.TotalValueUSD)
+	if pnl, ok := d.TotalPnl(); ok {
+		header += ", profit or loss " + formatter.FormatPnLUSD(pnl)
+	}
+	if age := d.freshness(portfolioPollInterval).Describe(rc.now); age != "" {
+		header += ", " + age
+	}
+	if p.loading {
+		header += ", refreshing"
+	}
+	if label := p.order.label(); label != "" {
+		header += ", sorted and filtered " + label
+	}
+	lines := []string{header, ""}
+
+	if natives := p.order.natives(d.NativeBalances); len(natives) > 0 {
+		for _, nb := range natives {
+			name := nb.Symbol
+			if nb.ChainName != "" {
+				name += " on " + nb.ChainName
+			}
+			lines = append(lines, fmt.Sprintf("%s: balance %.3f, value %s",
+				name, nb.Balance, formatter.FormatUSD(nb.BalanceUSD)))
+		}
+		lines = append(lines, "")
+	}
+
+	positions := p.order.apply(d.Positions)
+	if len(positions) == 0 {
+		lines = append(lines, noPositions(p.order))
+	} else {
+		shown := positions
+		if len(shown) > 4 {
+			shown = shown[:4]
+		}
+		for i, pos := range shown {
+			line := fmt.Sprintf("%s: value %s, %s",
+				pos.Symbol, formatter.FormatUSD(pos.ValueUSD), formatter.FormatPercent(pos.PnlPercent))
+			if pos.HasPnl {
+				line += fmt.Sprintf(", profit or loss %s, cost %s",
+					formatter.FormatPnLUSD(pos.PnlUSD), formatter.FormatUSD(pos.CostBasisUSD))
+			}
+			if i == p.pick {
+				line += ", selected for the watchlist"
+			}
+			lines = append(lines, line)
+		}
+		if len(positions) > 4 {
+			lines = append(lines, fmt.Sprintf("%d more positions", len(positions)-4))
+		}
+	}
+	if hidden := p.order.hiddenDust(d); hidden > 0 {
+		lines = append(lines, dustNote(p.order, hidden))
+	}
+
+	return lipgloss.NewStyle().
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// positionSort is the order the portfolio panels list positions in.
+type positionSort int
+
+const (
+	sortByValue positionSort = iota
+	sortByPnlDesc
+	sortByPnlAsc
+	sortBySymbol
+	positionSortCount
+)
+
+var positionSortLabels = [positionSortCount]string{
+	sortByValue:   "value",
+	sortByPnlDesc: "PnL% ↓",
+	sortByPnlAsc:  "PnL% ↑",
+	sortBySymbol:  "symbol",
+}
+
+// positionOrder is how the portfolio panels sort and filter positions. It
+// lasts for the session and applies to the All and chain tabs alike.
+type positionOrder struct {
+	sort   positionSort
+	filter string // symbol substring, matched case-insensitively
+	// dust hides positions and native balances worth less, in the display
+	// currency, unless showDust is set; 0 hides nothing.
+	dust     float64
+	showDust bool
+}
+
+// isDust reports whether a value in USD is hidden as dust.
+func (o positionOrder) isDust(usd float64) bool {
+	return !o.showDust && usd*formatter.DisplayCurrency().Rate < o.dust
+}
+
+// apply returns the positions that match the filter and aren't dust, in
+// sort order. The positions passed in are left as they are.
+func (o positionOrder) apply(positions []PortfolioPosition) []PortfolioPosition {
+	filter := strings.ToUpper(o.filter)
+	out := make([]PortfolioPosition, 0, len(positions))
+	for _, p := range positions {
+		if strings.Contains(strings.ToUpper(p.Symbol), filter) && !o.isDust(p.ValueUSD) {
+			out = append(out, p)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		switch o.sort {
+		case sortByPnlDesc:
+			return a.PnlPercent > b.PnlPercent
+		case sortByPnlAsc:
+			return a.PnlPercent < b.PnlPercent
+		case sortBySymbol:
+			return strings.ToUpper(a.Symbol) < strings.ToUpper(b.Symbol)
+		default:
+			return a.ValueUSD > b.ValueUSD
+		}
+	})
+	return out
+}
+
+// natives returns the native balances that aren't dust.
+func (o positionOrder) natives(balances []NativeBalance) []NativeBalance {
+	out := make([]NativeBalance, 0, len(balances))
+	for _, nb := range balances {
+		if !o.isDust(nb.BalanceUSD) {
+			out = append(out, nb)
+		}
+	}
+	return out
+}
+
+// hiddenDust counts the positions matching the filter and the native
+// balances that are hidden as dust.
+func (o positionOrder) hiddenDust(d *PortfolioData) int {
+	filter := strings.ToUpper(o.filter)
+	n := len(d.NativeBalances) - len(o.natives(d.NativeBalances))
+	for _, p := range d.Positions {
+		if strings.Contains(strings.ToUpper(p.Symbol), filter) && o.isDust(p.ValueUSD) {
+			n++
+		}
+	}
+	return n
+}
+
+// dustNote is the line under a panel's positions saying how many are
+// hidden as dust.
+func dustNote(o positionOrder, hidden int) string {
+	decimals := 2
+	if o.dust == float64(int64(o.dust)) {
+		decimals = 0
+	}
+	return fmt.Sprintf("+%d hidden (<%s)", hidden, formatter.DisplayCurrency().Format(o.dust, decimals))
+}
+
+// label describes a sort or filter other than the default, for the panel
+// header. It is empty for the default view.
+func (o positionOrder) label() string {
+	var parts []string
+	if o.sort != sortByValue {
+		parts = append(parts, "by "+positionSortLabels[o.sort])
+	}
+	if o.filter != "" {
+		parts = append(parts, fmt.Sprintf("%q", o.filter))
+	}
+	if o.showDust && o.dust > 0 {
+		parts = append(parts, "with dust")
+	}
+	return strings.Join(parts, " · ")
+}
+
+// noPositions is the placeholder for an empty position list.
+func noPositions(o positionOrder) string {
+	if o.filter != "" {
+		return fmt.Sprintf("No positions matching %q", o.filter)
+	}
+	return "No positions"
+}
+
+// orderTag renders the sort and filter in a panel header: the filter being
+// typed while editing, otherwise the label, if any.
+func orderTag(o positionOrder, editing bool) string {
+	style := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	if editing {
+		return "  " + style.Render("filter ") + lipgloss.NewStyle().Foreground(ui.ColorBright).Render(lineInput{value: o.filter}.view())
+	}
+	if label := o.label(); label != "" {
+		return "  " + style.Render("["+label+"]")
+	}
+	return ""
+}
+
+// lineInput is a one-line text field edited from key presses.
+type lineInput struct {
+	active bool
+	value  string
+}
+
+// key applies a key press to the field. done is set once enter or esc
+// closes it; esc also clears the value.
+func (in *lineInput) key(msg tea.KeyMsg) (done bool) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		in.active = false
+		return true
+	case tea.KeyEsc:
+		in.active, in.value = false, ""
+		return true
+	case tea.KeyBackspace:
+		if r := []rune(in.value); len(r) > 0 {
+			in.value = string(r[:len(r)-1])
+		}
+	case tea.KeyCtrlU:
+		in.value = ""
+	case tea.KeyRunes, tea.KeySpace:
+		in.value += string(msg.Runes)
+	}
+	return false
+}
+
+// view renders the field with a cursor.
+func (in lineInput) view() string {
+	return "/" + in.value + "▏"
+}
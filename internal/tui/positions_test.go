@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+)
+
+// Positions and native balances under the threshold are hidden and
+// counted, unless dust is shown; the threshold is in the display currency.
+func TestDust(t *testing.T) {
+	d := &PortfolioData{
+		Positions: []PortfolioPosition{
+			{Symbol: "WIF", ValueUSD: 50},
+			{Symbol: "PEPE", ValueUSD: 0.4},
+			{Symbol: "BONK", ValueUSD: 1.05},
+		},
+		NativeBalances: []NativeBalance{
+			{ChainName: "Base", BalanceUSD: 0.03},
+			{ChainName: "Solana", BalanceUSD: 12},
+		},
+	}
+	o := positionOrder{dust: 1}
+	if got := len(o.apply(d.Positions)); got != 2 {
+		t.Errorf("%d positions shown, want 2", got)
+	}
+	if got := len(o.natives(d.NativeBalances)); got != 1 {
+		t.Errorf("%d native balances shown, want 1", got)
+	}
+	if n := o.hiddenDust(d); n != 2 {
+		t.Errorf("hiddenDust = %d, want 2", n)
+	}
+	if got := dustNote(o, 2); got != "+2 hidden (<$1)" {
+		t.Errorf("dustNote = %q", got)
+	}
+
+	o.filter = "pe"
+	if n := o.hiddenDust(d); n != 2 {
+		t.Errorf("filtered: hiddenDust = %d, want 2 (PEPE and the Base balance)", n)
+	}
+	o.filter = ""
+
+	o.showDust = true
+	if got := len(o.apply(d.Positions)); got != 3 || o.hiddenDust(d) != 0 {
+		t.Errorf("with dust shown: %d positions, %d hidden", got, o.hiddenDust(d))
+	}
+	if o.label() != "with dust" {
+		t.Errorf("label = %q", o.label())
+	}
+	if n := (positionOrder{}).hiddenDust(d); n != 0 {
+		t.Errorf("threshold 0 hid %d", n)
+	}
+
+	// At 0.92 EUR to the dollar, BONK is under 1 €.
+	eur, _ := formatter.LookupCurrency("EUR")
+	formatter.SetDisplayCurrency(eur)
+	defer formatter.SetDisplayCurrency(formatter.Currencies["USD"])
+	o.showDust = false
+	if n := o.hiddenDust(d); n != 3 {
+		t.Errorf("in EUR: hiddenDust = %d, want 3", n)
+	}
+	o.dust = 0.5
+	if got := dustNote(o, 1); got != "+1 hidden (<0,50 €)" {
+		t.Errorf("in EUR: dustNote = %q", got)
+	}
+}
+
+// d toggles dust for both portfolio panels.
+func TestToggleDust(t *testing.T) {
+	m := runningModel(t)
+	if m.positions.dust != 1 || m.portfolio.order.showDust {
+		t.Fatalf("default: dust %v, shown %v", m.positions.dust, m.portfolio.order.showDust)
+	}
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	pv := model.(ProxyViewModel)
+	if !pv.portfolio.order.showDust || !pv.chain.order.showDust {
+		t.Error("d didn't show dust")
+	}
+	model, _ = pv.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if model.(ProxyViewModel).portfolio.order.showDust {
+		t.Error("second d didn't hide dust")
+	}
+}
@@ -1,1558 +1,1311 @@
 package tui
 
 import (
-	"encoding/json"
 	"fmt"
-	"math"
-	"math/rand"
-	"sort"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
-	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/clipboard"
+	"github.com/tradeboba/boba-cli/internal/config"
 	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/orders"
 	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/qr"
 	"github.com/tradeboba/boba-cli/internal/ui"
 	"github.com/tradeboba/boba-cli/internal/version"
+	"github.com/tradeboba/boba-cli/internal/wallet"
 )
 
-type LogMsg proxy.LogEntry
-type TickMsg time.Time
-type BootTickMsg struct{}
-type QuitStepMsg struct{}
-type PortfolioMsg struct{ Data *PortfolioData }
-type ChainPortfolioMsg struct{ Data *PortfolioData }
-type PortfolioPollMsg struct{}
-
-// PortfolioData holds the parsed portfolio state for the TUI panel.
-type PortfolioData struct {
-	TotalValueUSD    float64
-	PositionValueUSD float64
-	NativeValueUSD   float64
-	Positions        []PortfolioPosition
-	NativeBalances   []NativeBalance
-	LastUpdated      time.Time
-	Error            string
-}
-
-type PortfolioPosition struct {
-	ChainName  string
-	Symbol     string
-	ValueUSD   float64
-	PnlPercent float64
-	PriceUSD   float64
-}
-
-type NativeBalance struct {
-	ChainID    int
-	ChainName  string
-	Symbol     string
-	Balance    float64
-	BalanceUSD float64
-}
-
-func tickEvery(d time.Duration) tea.Cmd {
-	return tea.Tick(d, func(t time.Time) tea.Msg { return TickMsg(t) })
-}
-
-func bootTick() tea.Cmd {
-	return tea.Tick(40*time.Millisecond, func(_ time.Time) tea.Msg { return BootTickMsg{} })
-}
-
-func quitStep() tea.Cmd {
-	return tea.Tick(300*time.Millisecond, func(_ time.Time) tea.Msg { return QuitStepMsg{} })
-}
-
-func fetchPortfolio(server *proxy.ProxyServer) tea.Cmd {
-	return func() tea.Msg {
-		args := map[string]any{"user_id": "me"}
-		respBody, err := server.CallTool("get_portfolio", args)
-		if err != nil {
-			return PortfolioMsg{Data: &PortfolioData{
-				Error:       err.Error(),
-				LastUpdated: time.Now(),
-			}}
-		}
-
-		var raw map[string]any
-		if err := json.Unmarshal(respBody, &raw); err != nil {
-			return PortfolioMsg{Data: &PortfolioData{
-				Error:       "failed to parse portfolio data",
-				LastUpdated: time.Now(),
-			}}
-		}
-
-		data := &PortfolioData{
-			TotalValueUSD:    parseFloat(raw, "total_value_usd"),
-			PositionValueUSD: parseFloat(raw, "position_value_usd"),
-			NativeValueUSD:   parseFloat(raw, "native_value_usd"),
-			LastUpdated:      time.Now(),
-		}
-
-		// Parse positions
-		if positions, ok := raw["positions"].([]any); ok {
-			for _, p := range positions {
-				pos, ok := p.(map[string]any)
-				if !ok {
-					continue
-				}
-				chainName := parseString(pos, "chain_name")
-				if chainName == "" {
-					chainName = parseString(pos, "chain")
-				}
-				if chainName == "" {
-					chainName = parseString(pos, "chainName")
-				}
-				if chainName == "" {
-					chainName = parseString(pos, "network")
-				}
-				data.Positions = append(data.Positions, PortfolioPosition{
-					ChainName:  chainName,
-					Symbol:     parseString(pos, "symbol"),
-					ValueUSD:   parseFloat(pos, "value_usd"),
-					PnlPercent: parseFloat(pos, "pnl_percent"),
-					PriceUSD:   parseFloat(pos, "price_usd"),
-				})
-			}
-			// Sort by value descending
-			sort.Slice(data.Positions, func(i, j int) bool {
-				return data.Positions[i].ValueUSD > data.Positions[j].ValueUSD
-			})
-		}
-
-		// Parse native balances
-		if balances, ok := raw["native_balances"].([]any); ok {
-			for _, b := range balances {
-				bal, ok := b.(map[string]any)
-				if !ok {
-					continue
-				}
-				data.NativeBalances = append(data.NativeBalances, NativeBalance{
-					ChainID:    int(parseFloat(bal, "chain_id")),
-					ChainName:  parseString(bal, "chain_name"),
-					Symbol:     parseString(bal, "symbol"),
-					Balance:    parseFloat(bal, "balance"),
-					BalanceUSD: parseFloat(bal, "balance_usd"),
-				})
-			}
-		}
-
-		return PortfolioMsg{Data: data}
-	}
-}
-
-// fetchChainPortfolio fetches portfolio data filtered to a specific chain.
-// The MCP get_portfolio tool accepts a "chain" string slug (e.g. "solana", "eth").
-func fetchChainPortfolio(server *proxy.ProxyServer, chainSlug string) tea.Cmd {
-	return func() tea.Msg {
-		args := map[string]any{
-			"user_id": "me",
-			"chain":   chainSlug,
-		}
-		respBody, err := server.CallTool("get_portfolio", args)
-		if err != nil {
-			return ChainPortfolioMsg{Data: &PortfolioData{
-				Error:       err.Error(),
-				LastUpdated: time.Now(),
-			}}
-		}
-
-		var raw map[string]any
-		if err := json.Unmarshal(respBody, &raw); err != nil {
-			return ChainPortfolioMsg{Data: &PortfolioData{
-				Error:       "failed to parse chain portfolio",
-				LastUpdated: time.Now(),
-			}}
-		}
-
-		data := &PortfolioData{
-			TotalValueUSD:    parseFloat(raw, "total_value_usd"),
-			PositionValueUSD: parseFloat(raw, "position_value_usd"),
-			NativeValueUSD:   parseFloat(raw, "native_value_usd"),
-			LastUpdated:      time.Now(),
-		}
-
-		if positions, ok := raw["positions"].([]any); ok {
-			for _, p := range positions {
-				pos, ok := p.(map[string]any)
-				if !ok {
-					continue
-				}
-				data.Positions = append(data.Positions, PortfolioPosition{
-					ChainName:  parseString(pos, "chain_name"),
-					Symbol:     parseString(pos, "symbol"),
-					ValueUSD:   parseFloat(pos, "value_usd"),
-					PnlPercent: parseFloat(pos, "pnl_percent"),
-					PriceUSD:   parseFloat(pos, "price_usd"),
-				})
-			}
-			sort.Slice(data.Positions, func(i, j int) bool {
-				return data.Positions[i].ValueUSD > data.Positions[j].ValueUSD
-			})
-		}
-
-		if balances, ok := raw["native_balances"].([]any); ok {
-			for _, b := range balances {
-				bal, ok := b.(map[string]any)
-				if !ok {
-					continue
-				}
-				data.NativeBalances = append(data.NativeBalances, NativeBalance{
-					ChainID:    int(parseFloat(bal, "chain_id")),
-					ChainName:  parseString(bal, "chain_name"),
-					Symbol:     parseString(bal, "symbol"),
-					Balance:    parseFloat(bal, "balance"),
-					BalanceUSD: parseFloat(bal, "balance_usd"),
-				})
-			}
-		}
-
-		return ChainPortfolioMsg{Data: data}
-	}
-}
-
-// parseFloat safely extracts a float64 from a map, handling string values.
-func parseFloat(m map[string]any, key string) float64 {
-	v, ok := m[key]
-	if !ok {
-		return 0
-	}
-	switch n := v.(type) {
-	case float64:
-		return n
-	case string:
-		s := strings.TrimSpace(n)
-		s = strings.TrimSuffix(s, "%")
-		s = strings.ReplaceAll(s, ",", "")
-		f, err := strconv.ParseFloat(s, 64)
-		if err != nil {
-			return 0
-		}
-		return f
-	default:
-		return 0
-	}
-}
-
-// parseString safely extracts a string from a map.
-func parseString(m map[string]any, key string) string {
-	v, ok := m[key]
-	if !ok {
-		return ""
-	}
-	s, ok := v.(string)
-	if !ok {
-		return fmt.Sprintf("%v", v)
-	}
-	return s
-}
-
-var bootStepLabels = []string{
-	"Generating session token",
-	"Binding to port",
-	"Authenticating agent",
-	"Syncing tool manifest",
-	"Proxy online",
-}
-
-var quitSteps = []string{
-	"Clearing session token...",
-	"Stopping proxy...",
-	"Goodbye!",
-}
-
-type toolTag struct {
-	label string
-	color lipgloss.Color
-}
-
-var toolCategoryMap = map[string]toolTag{
-	// Trading
-	"get_swap_price":      {label: "TRADE", color: ui.ColorTrading},
-	"get_swap_quote":      {label: "TRADE", color: ui.ColorTrading},
-	"execute_swap":        {label: "TRADE", color: ui.ColorTrading},
-	"execute_trade":       {label: "TRADE", color: ui.ColorTrading},
-	"get_agent_balances":  {label: "TRADE", color: ui.ColorTrading},
-	// Portfolio
-	"get_portfolio":              {label: "FOLIO", color: ui.ColorPortfolio},
-	"get_portfolio_summary":      {label: "FOLIO", color: ui.ColorPortfolio},
-	"get_portfolio_pnl":          {label: "FOLIO", color: ui.ColorPortfolio},
-	"get_trade_history":          {label: "FOLIO", color: ui.ColorPortfolio},
-	"get_pnl_chart":              {label: "FOLIO", color: ui.ColorPortfolio},
-	"get_user_xp":                {label: "FOLIO", color: ui.ColorPortfolio},
-	"start_portfolio_stream":     {label: "FOLIO", color: ui.ColorPortfolio},
-	"get_portfolio_price_updates": {label: "FOLIO", color: ui.ColorPortfolio},
-	"stop_portfolio_stream":      {label: "FOLIO", color: ui.ColorPortfolio},
-	// Token
-	"get_token_info":         {label: "TOKEN", color: ui.ColorTokenInfo},
-	"get_token_details":      {label: "TOKEN", color: ui.ColorTokenInfo},
-	"search_tokens":          {label: "TOKEN", color: ui.ColorTokenInfo},
-	"get_tokens_by_category": {label: "TOKEN", color: ui.ColorTokenInfo},
-	"get_trending_tokens":    {label: "TOKEN", color: ui.ColorTokenInfo},
-	"get_token_chart":        {label: "TOKEN", color: ui.ColorTokenInfo},
-	"get_token_ohlc":         {label: "TOKEN", color: ui.ColorTokenInfo},
-	"get_ohlc":               {label: "TOKEN", color: ui.ColorTokenInfo},
-	"get_price_chart":        {label: "TOKEN", color: ui.ColorTokenInfo},
-	"search_token_by_slug":   {label: "TOKEN", color: ui.ColorTokenInfo},
-	"get_token_price":        {label: "TOKEN", color: ui.ColorTokenInfo},
-	"get_category_tokens":    {label: "TOKEN", color: ui.ColorTokenInfo},
-	// Wallet
-	"get_wallet_balance":      {label: "WALLET", color: ui.ColorWallet},
-	"get_transfers":           {label: "WALLET", color: ui.ColorWallet},
-	"refresh_native_balances": {label: "WALLET", color: ui.ColorWallet},
-	// Brewing
-	"get_brewing_status":  {label: "BREW", color: ui.ColorBrewing},
-	"get_recent_launches": {label: "BREW", color: ui.ColorBrewing},
-	"get_brewing_tokens":  {label: "BREW", color: ui.ColorBrewing},
-	"get_launch_feed":     {label: "BREW", color: ui.ColorBrewing},
-	// Security
-	"audit_token":        {label: "AUDIT", color: ui.ColorSecurity},
-	"audit_tokens_batch": {label: "AUDIT", color: ui.ColorSecurity},
-	"is_token_verified":  {label: "AUDIT", color: ui.ColorSecurity},
-	// Orders
-	"create_limit_order":  {label: "ORDER", color: ui.ColorOrders},
-	"get_limit_orders":    {label: "ORDER", color: ui.ColorOrders},
-	"get_limit_order":     {label: "ORDER", color: ui.ColorOrders},
-	"update_limit_order":  {label: "ORDER", color: ui.ColorOrders},
-	"cancel_limit_order":  {label: "ORDER", color: ui.ColorOrders},
-	"create_dca_order":    {label: "ORDER", color: ui.ColorOrders},
-	"get_dca_orders":      {label: "ORDER", color: ui.ColorOrders},
-	"get_dca_order":       {label: "ORDER", color: ui.ColorOrders},
-	"pause_dca_order":     {label: "ORDER", color: ui.ColorOrders},
-	"resume_dca_order":    {label: "ORDER", color: ui.ColorOrders},
-	"cancel_dca_order":    {label: "ORDER", color: ui.ColorOrders},
-	"create_twap_order":   {label: "ORDER", color: ui.ColorOrders},
-	"get_twap_orders":     {label: "ORDER", color: ui.ColorOrders},
-	"get_twap_order":      {label: "ORDER", color: ui.ColorOrders},
-	"pause_twap_order":    {label: "ORDER", color: ui.ColorOrders},
-	"resume_twap_order":   {label: "ORDER", color: ui.ColorOrders},
-	"cancel_twap_order":   {label: "ORDER", color: ui.ColorOrders},
-	"get_positions":       {label: "ORDER", color: ui.ColorOrders},
-	"get_position":        {label: "ORDER", color: ui.ColorOrders},
-	// Analytics
-	"get_deployer_tokens":   {label: "STATS", color: ui.ColorAnalytics},
-	"get_deployer_activity": {label: "STATS", color: ui.ColorAnalytics},
-	"get_network_volume":    {label: "STATS", color: ui.ColorAnalytics},
-	"get_network_stats":     {label: "STATS", color: ui.ColorAnalytics},
-	"search_wallets":        {label: "STATS", color: ui.ColorAnalytics},
-	"get_wallet_stats":      {label: "STATS", color: ui.ColorAnalytics},
-	"get_maker_trades":      {label: "STATS", color: ui.ColorAnalytics},
-	"get_holders":           {label: "STATS", color: ui.ColorAnalytics},
-	// Tracking
-	"get_live_swaps":             {label: "TRACK", color: ui.ColorTracking},
-	"get_user_swaps":             {label: "TRACK", color: ui.ColorTracking},
-	"get_watchlist":              {label: "TRACK", color: ui.ColorTracking},
-	"add_to_watchlist":           {label: "TRACK", color: ui.ColorTracking},
-	"remove_from_watchlist":      {label: "TRACK", color: ui.ColorTracking},
-	"get_kol_wallets":            {label: "TRACK", color: ui.ColorTracking},
-	"get_kol_swaps":              {label: "TRACK", color: ui.ColorTracking},
-	"get_kol_info":               {label: "TRACK", color: ui.ColorTracking},
-	"check_if_kol":               {label: "TRACK", color: ui.ColorTracking},
-	"get_deployer_history":       {label: "TRACK", color: ui.ColorTracking},
-	"track_deployer":             {label: "TRACK", color: ui.ColorTracking},
-	"stop_tracking_deployer":     {label: "TRACK", color: ui.ColorTracking},
-	"add_wallet_to_tracker":      {label: "TRACK", color: ui.ColorTracking},
-	"get_tracked_wallets":        {label: "TRACK", color: ui.ColorTracking},
-	"remove_wallet_from_tracker": {label: "TRACK", color: ui.ColorTracking},
-	// Streaming
-	"stream_launches":        {label: "STREAM", color: ui.ColorStreaming},
-	"stream_kol_swaps":       {label: "STREAM", color: ui.ColorStreaming},
-	"stream_wallet_swaps":    {label: "STREAM", color: ui.ColorStreaming},
-	"stream_watchlist_swaps": {label: "STREAM", color: ui.ColorStreaming},
-	"get_streaming_status":   {label: "STREAM", color: ui.ColorStreaming},
-}
-
-var defaultTag = toolTag{label: "TOOL", color: ui.ColorBoba}
-
-// chainOrder defines the fixed display order for chain tabs.
-var chainOrder = []string{
-	"Solana", "Base", "BSC", "Ethereum", "Arbitrum",
-	"Avalanche", "Ape Chain", "HyperEVM", "Monad",
-}
-
-// chainNameToSlug maps display chain names to the MCP tool's chain parameter slugs.
-// The MCP get_portfolio tool accepts these string slugs (not numeric chain IDs).
-var chainNameToSlug = map[string]string{
-	"Solana":    "solana",
-	"Ethereum":  "eth",
-	"Ape Chain": "apechain",
-	"BSC":       "bsc",
-	"Avalanche": "avax",
-	"Base":      "base",
-	"Arbitrum":  "arb",
-	"HyperEVM":  "hyperevm",
-	"Monad":     "monad",
-}
-
-func getToolTag(tool string) toolTag {
-	if t, ok := toolCategoryMap[tool]; ok {
-		return t
-	}
-	return defaultTag
-}
-
-// toolDescriptions maps tool names to human-readable descriptions shown while
-// a request is pending.
-var toolDescriptions = map[string]string{
-	// Trading
-	"get_swap_price":     "Getting swap quote...",
-	"get_swap_quote":     "Getting swap quote...",
-	"execute_swap":       "Executing trade...",
-	"execute_trade":      "Executing trade...",
-	"get_agent_balances": "Fetching balances...",
-	// Portfolio
-	"get_portfolio":         "Fetching portfolio...",
-	"get_portfolio_summary": "Fetching portfolio...",
-	"get_portfolio_pnl":     "Getting P&L data...",
-	"get_pnl_chart":         "Loading P&L chart...",
-	// Token
-	"get_token_info":         "Looking up token...",
-	"get_token_details":      "Looking up token...",
-	"search_tokens":          "Searching tokens...",
-	"get_tokens_by_category": "Searching tokens...",
-	"get_trending_tokens":    "Getting trending...",
-	"get_token_chart":        "Loading price chart...",
-	"get_token_ohlc":         "Loading price chart...",
-	"get_ohlc":               "Loading price chart...",
-	"get_price_chart":        "Loading price chart...",
-	"search_token_by_slug":   "Searching tokens...",
-	"get_token_price":        "Getting token price...",
-	"get_category_tokens":    "Searching by category...",
-	// Wallet
-	"get_wallet_balance": "Checking wallet...",
-	// Brewing
-	"get_brewing_tokens":  "Getting brewing tokens...",
-	"get_brewing_status":  "Checking new launches...",
-	"get_recent_launches": "Getting recent launches...",
-	"get_launch_feed":     "Loading launch feed...",
-	// Security
-	"audit_token":        "Auditing token...",
-	"audit_tokens_batch": "Auditing tokens...",
-	"is_token_verified":  "Checking verification...",
-	// Orders
-	"create_limit_order": "Creating limit order...",
-	"get_limit_orders":   "Getting limit orders...",
-	"get_limit_order":    "Getting order detail...",
-	"update_limit_order": "Updating order...",
-	"cancel_limit_order": "Cancelling order...",
-	"create_dca_order":   "Creating DCA order...",
-	"get_dca_orders":     "Getting DCA orders...",
-	"get_dca_order":      "Getting DCA detail...",
-	"pause_dca_order":    "Pausing DCA...",
-	"resume_dca_order":   "Resuming DCA...",
-	"cancel_dca_order":   "Cancelling DCA...",
-	"create_twap_order":  "Creating TWAP order...",
-	"get_twap_orders":    "Getting TWAP orders...",
-	"get_twap_order":     "Getting TWAP detail...",
-	"pause_twap_order":   "Pausing TWAP...",
-	"resume_twap_order":  "Resuming TWAP...",
-	"cancel_twap_order":  "Cancelling TWAP...",
-	"get_positions":      "Getting positions...",
-	"get_position":       "Getting position...",
-	// Analytics
-	"get_deployer_tokens":   "Getting deployer tokens...",
-	"get_deployer_activity": "Getting dev activity...",
-	"get_network_volume":    "Getting network volume...",
-	"get_network_stats":     "Getting network stats...",
-	"search_wallets":        "Searching wallets...",
-	"get_wallet_stats":      "Getting wallet stats...",
-	"get_maker_trades":      "Getting maker trades...",
-	"get_holders":           "Getting holders...",
-	// Tracking
-	"get_live_swaps":             "Getting live swaps...",
-	"get_user_swaps":             "Getting user swaps...",
-	"get_watchlist":              "Getting watchlist...",
-	"add_to_watchlist":           "Adding to watchlist...",
-	"remove_from_watchlist":      "Removing from watchlist...",
-	"get_kol_wallets":            "Getting KOL wallets...",
-	"get_kol_swaps":              "Getting KOL swaps...",
-	"get_kol_info":               "Getting KOL info...",
-	"check_if_kol":               "Checking KOL...",
-	"get_deployer_history":       "Getting dev history...",
-	"track_deployer":             "Starting tracker...",
-	"stop_tracking_deployer":     "Stopping tracker...",
-	"add_wallet_to_tracker":      "Adding wallet...",
-	"get_tracked_wallets":        "Getting tracked wallets...",
-	"remove_wallet_from_tracker": "Removing wallet...",
-	// Streaming
-	"stream_launches":        "Streaming launches...",
-	"stream_kol_swaps":       "Streaming KOL swaps...",
-	"stream_wallet_swaps":    "Streaming wallet...",
-	"stream_watchlist_swaps": "Streaming watchlist...",
-	"get_streaming_status":   "Checking streams...",
-}
-
+// ProxyViewModel is the full-screen proxy view. It owns the shared pieces
+// (spinner, clock, window size, phase) and composes the boot animation, tab
+// bar, portfolio panels, stats bar and log pane, each of which keeps its own
+// state. Update routes every message to the component that handles it.
 type ProxyViewModel struct {
-	logo      string
 	agentName string
 	evmAddr   string
 	solAddr   string
 	port      int
+	server    *proxy.ProxyServer
+
+	boot      bootView
+	tabs      tabBar
+	portfolio portfolioPanel
+	chain     chainPanel
+	stats     statsBar
+	log       logPane
+	confirm   confirmPanel
+	orders    ordersPanel
+	watch     watchlistPanel
+	ticker    tickerStrip
 
-	logEntries []proxy.LogEntry
-	viewport   viewport.Model
 	spinner    spinner.Model
-	server     *proxy.ProxyServer
-	autoScroll bool
-
-	activeTab             int
-	tabs                  []string
-	chainSlugs            map[string]string
-	chainPortfolio        *PortfolioData
-	chainPortfolioLoading bool
-
-	startTime    time.Time
-	requestCount int
-	errorCount   int
-
-	portfolio        *PortfolioData
-	portfolioLoading bool
-	portfolioFlash   int
-
 	showConfig bool
+	// addrView is how the config panel shows wallet addresses: truncated
+	// (0), in full (1), or in full with the QR code of address addrView-2.
+	addrView int
 
 	// phases: "boot" -> "running" -> "quitting"
 	phase string
+	// summary recaps the session once the shutdown sequence starts.
+	summary *proxy.SessionSummary
 
-	bootStep     int
-	bootFrame    int
-	bootGlitch   int
-	bootProgress progress.Model
+	idleFrame int
 
-	quitStep int
+	// static renders single frames with no animation ticks for slow links.
+	static         bool
+	heartbeat      time.Duration
+	spinnerRunning bool
+
+	width     int
+	height    int
+	resizeSeq int
+
+	// pollInterval is the current portfolio poll interval; it backs off
+	// while the backend is offline. pollSeq identifies the scheduled poll.
+	pollInterval time.Duration
+	pollSeq      int
+
+	// positions is how the portfolio panels sort and filter positions;
+	// filterInput edits its filter after '/'.
+	positions   positionOrder
+	filterInput lineInput
+	// searchInput edits the activity log search after '/'.
+	searchInput lineInput
+	// picking is set while w has a position marked for adding to the
+	// watchlist; pick is its index among the positions shown.
+	picking bool
+	pick    int
+
+	// clock returns the current time; nil means time.Now.
+	clock func() time.Time
+}
 
+// renderCtx is the per-frame state shared by every component's view.
+type renderCtx struct {
+	spinner   string
 	idleFrame int
+	now       time.Time
+	width     int
+}
+
+func (m ProxyViewModel) now() time.Time {
+	if m.clock != nil {
+		return m.clock()
+	}
+	return time.Now()
+}
 
-	width  int
-	height int
-	ready  bool
+func (m ProxyViewModel) renderCtx() renderCtx {
+	return renderCtx{spinner: m.spinner.View(), idleFrame: m.idleFrame, now: m.now(), width: m.width}
 }
 
-func NewProxyViewModel(server *proxy.ProxyServer, agentName, evmAddr, solAddr string, port int) ProxyViewModel {
+func NewProxyViewModel(server *proxy.ProxyServer, agentName, evmAddr, solAddr string, port int, boot []BootTask) ProxyViewModel {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(ui.ColorBoba)
 
-	prog := progress.New(
-		progress.WithGradient(string(ui.ColorBoba), "#333333"),
-		progress.WithWidth(40),
-		progress.WithoutPercentage(),
-	)
+	static := ui.SlowTerminal()
+	heartbeat := time.Second
+	if static {
+		heartbeat = 5 * time.Second
+	}
+	if secs := config.GetHeartbeatSeconds(); secs > 0 {
+		heartbeat = time.Duration(secs) * time.Second
+	}
+
+	positions := positionOrder{dust: config.GetDustThreshold()}
 
 	return ProxyViewModel{
-		logo:         ui.RenderLogo(),
-		autoScroll:   true,
-		agentName:    agentName,
-		evmAddr:      evmAddr,
-		solAddr:      solAddr,
-		port:         port,
-		spinner:      s,
-		server:       server,
-		startTime:    time.Now(),
-		phase:        "boot",
-		bootStep:     0,
-		bootFrame:    0,
-		bootProgress: prog,
-		tabs:       []string{"All"},
-		activeTab:  0,
-		chainSlugs: make(map[string]string),
+		agentName:      agentName,
+		evmAddr:        evmAddr,
+		solAddr:        solAddr,
+		port:           port,
+		server:         server,
+		boot:           newBootView(static, boot),
+		tabs:           newTabBar(),
+		stats:          statsBar{startTime: time.Now()},
+		log:            newLogPane(config.GetLogHistory()),
+		spinner:        s,
+		phase:          "boot",
+		static:         static,
+		heartbeat:      heartbeat,
+		spinnerRunning: !static,
+		pollInterval:   portfolioPollInterval,
+		positions:      positions,
+		portfolio:      portfolioPanel{order: positions, pick: -1},
+		chain:          chainPanel{order: positions, pick: -1},
 	}
 }
 
 func (m ProxyViewModel) Init() tea.Cmd {
+	shutdown := listenForShutdown(m.server.ShutdownRequested())
+	if m.static {
+		// Skip the boot animation entirely.
+		return tea.Batch(m.boot.runTask(0), func() tea.Msg { return BootTickMsg{} }, shutdown)
+	}
 	return tea.Batch(
 		m.spinner.Tick,
+		m.boot.runTask(0),
 		bootTick(),
+		shutdown,
 	)
 }
 
+// BootErr returns the error that stopped the boot sequence, or nil if the
+// proxy came up.
+func (m ProxyViewModel) BootErr() error {
+	return m.boot.err()
+}
+
+// Summary returns the session recap taken when the shutdown sequence
+// started, or nil if the dashboard exited during boot.
+func (m ProxyViewModel) Summary() *proxy.SessionSummary {
+	return m.summary
+}
+
+// keyBindings maps keys to their handlers while the proxy is running. Quit
+// keys are handled separately because they also apply during boot.
+var keyBindings = map[string]func(m *ProxyViewModel) tea.Cmd{
+	"tab":       (*ProxyViewModel).nextTab,
+	"right":     (*ProxyViewModel).nextTab,
+	"shift+tab": (*ProxyViewModel).prevTab,
+	"left":      (*ProxyViewModel).prevTab,
+	"c":         (*ProxyViewModel).toggleConfig,
+	"a":         (*ProxyViewModel).cycleAddresses,
+	"up":        (*ProxyViewModel).pauseLog,
+	"k":         (*ProxyViewModel).pauseLog,
+	"pgup":      (*ProxyViewModel).pauseLog,
+	"end":       (*ProxyViewModel).followLog,
+	"G":         (*ProxyViewModel).followLog,
+	"o":         (*ProxyViewModel).toggleLogEntry,
+	"s":         (*ProxyViewModel).cyclePositionSort,
+	"/":         (*ProxyViewModel).startFilterOrSearch,
+	"f":         (*ProxyViewModel).cycleLogFilter,
+	"N":         (*ProxyViewModel).prevLogMatch,
+	"d":         (*ProxyViewModel).toggleDust,
+	"t":         (*ProxyViewModel).toggleTicker,
+	"w":         (*ProxyViewModel).startPick,
+	"y":         (*ProxyViewModel).approveCallOrCopy,
+	"n":         (*ProxyViewModel).denyCallOrNextMatch,
+}
+
+// orderKeyBindings take precedence over keyBindings while the Orders tab is
+// open, moving the selection instead of scrolling the log.
+var orderKeyBindings = map[string]func(m *ProxyViewModel) tea.Cmd{
+	"up":    (*ProxyViewModel).orderUp,
+	"k":     (*ProxyViewModel).orderUp,
+	"down":  (*ProxyViewModel).orderDown,
+	"j":     (*ProxyViewModel).orderDown,
+	"enter": (*ProxyViewModel).toggleOrderDetail,
+	"x":     (*ProxyViewModel).promptCancelOrder,
+	"X":     (*ProxyViewModel).promptCancelAll,
+}
+
+// pickKeyBindings take precedence over keyBindings while a position is
+// being picked for the watchlist.
+var pickKeyBindings = map[string]func(m *ProxyViewModel) tea.Cmd{
+	"up":    (*ProxyViewModel).pickUp,
+	"k":     (*ProxyViewModel).pickUp,
+	"down":  (*ProxyViewModel).pickDown,
+	"j":     (*ProxyViewModel).pickDown,
+	"enter": (*ProxyViewModel).watchPicked,
+	"w":     (*ProxyViewModel).watchPicked,
+	"y":     (*ProxyViewModel).copyPicked,
+	"esc":   (*ProxyViewModel).cancelPick,
+}
+
+// cancelPromptBindings answer the "cancel order?" prompt while it is shown,
+// ahead of the trade confirmation keys.
+var cancelPromptBindings = map[string]func(m *ProxyViewModel) tea.Cmd{
+	"y":   (*ProxyViewModel).confirmCancelOrder,
+	"n":   (*ProxyViewModel).abortCancelOrder,
+	"esc": (*ProxyViewModel).abortCancelOrder,
+}
+
 func (m ProxyViewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
-
 	case tea.KeyMsg:
 		key := msg.String()
-		switch key {
-		case "q", "ctrl+c":
-			if m.phase == "boot" {
-				return m, tea.Quit
-			}
-			if m.phase == "quitting" {
-				return m, nil
-			}
-			m.phase = "quitting"
-			m.quitStep = 0
-			return m, quitStep()
-		case "tab", "right":
-			if m.phase == "running" && len(m.tabs) > 1 {
-				prevTab := m.activeTab
-				m.activeTab++
-				if m.activeTab >= len(m.tabs) {
-					m.activeTab = len(m.tabs) - 1
-				}
-				if m.activeTab != prevTab {
-					m.recalcViewport()
-					if m.activeTab > 0 {
-						chainName := m.tabs[m.activeTab]
-						if slug, ok := m.chainSlugs[chainName]; ok {
-							m.chainPortfolioLoading = true
-							m.chainPortfolio = nil
-							return m, fetchChainPortfolio(m.server, slug)
-						}
-					}
-				}
-			}
-		case "shift+tab", "left":
-			if m.phase == "running" && m.activeTab > 0 {
-				m.activeTab--
-				m.recalcViewport()
-				if m.activeTab > 0 {
-					chainName := m.tabs[m.activeTab]
-					if slug, ok := m.chainSlugs[chainName]; ok {
-						m.chainPortfolioLoading = true
-						m.chainPortfolio = nil
-						return m, fetchChainPortfolio(m.server, slug)
-					}
-				}
-			}
-		case "c":
-			if m.phase == "running" {
-				m.showConfig = !m.showConfig
-				m.recalcViewport()
+		if m.filterInput.active && key != "ctrl+c" {
+			// Every key edits the filter, so none scrolls the log.
+			m.editPositionFilter(msg)
+			return m, nil
+		}
+		if m.searchInput.active && key != "ctrl+c" {
+			m.editLogSearch(msg)
+			return m, nil
+		}
+		if m.orders.cancelAll.active && key != "ctrl+c" {
+			return m, m.editCancelAll(msg)
+		}
+		if key == "q" || key == "ctrl+c" {
+			return m.quit()
+		}
+		if handle, ok := pickKeyBindings[key]; ok && m.phase == "running" && m.picking {
+			return m, handle(&m)
+		}
+		if m.phase == "running" && m.tabs.ordersActive() {
+			if handle, ok := cancelPromptBindings[key]; ok && m.orders.confirming {
+				return m, handle(&m)
 			}
-		case "up", "k", "pgup":
-			if m.phase == "running" {
-				m.autoScroll = false
+			if handle, ok := orderKeyBindings[key]; ok {
+				return m, handle(&m)
 			}
-		case "end", "G":
-			if m.phase == "running" && m.ready {
-				m.autoScroll = true
-				m.viewport.GotoBottom()
+		}
+		if handle, ok := keyBindings[key]; ok && m.phase == "running" {
+			if cmd := handle(&m); cmd != nil {
+				return m, cmd
 			}
 		}
-
 	case tea.WindowSizeMsg:
-		m.width = msg.Width
-		m.height = msg.Height
-		formatter.TermWidth = msg.Width
-		// boot phase doesn't need resize handling
-		if m.phase == "running" {
+		cmds = append(cmds, m.onResize(msg))
+	case ResizeSettledMsg:
+		if m.phase == "running" && msg.Seq == m.resizeSeq {
 			m.recalcViewport()
 		}
-
-	// -- boot sequence tick (40ms per frame) --------------------------------
 	case BootTickMsg:
-		if m.phase != "boot" {
-			break
-		}
-		m.bootFrame++
-		m.bootGlitch++
-
-		// Advance steps rapidly (every ~0.3s = every 7 frames)
-		stepFrames := []int{5, 12, 19, 26, 33}
-		for i, f := range stepFrames {
-			if m.bootFrame == f && i < len(bootStepLabels) {
-				m.bootStep = i + 1
-				m.bootGlitch = 0
+		if m.phase == "boot" {
+			return m, m.onBootTick()
+		}
+	case BootStepMsg:
+		if m.phase == "boot" {
+			cmds = append(cmds, m.boot.finish(msg))
+			if m.static {
+				// Without animation ticks, each result moves the boot on.
+				cmds = append(cmds, func() tea.Msg { return BootTickMsg{} })
 			}
 		}
-
-		// Boot complete at frame 40 (~1.6s) then transition
-		if m.bootFrame >= 40 {
-			m.phase = "running"
-			m.startTime = time.Now()
-			m.portfolioLoading = true
-			m.recalcViewport()
-			return m, tea.Batch(
-				tickEvery(time.Second),
-				listenForLogs(m.server.LogChannel()),
-				fetchPortfolio(m.server),
-			)
-		}
-		return m, bootTick()
-
-	// -- quit sequence tick ------------------------------------------------
+	case ShutdownMsg:
+		return m.quit()
 	case QuitStepMsg:
-		if m.phase != "quitting" {
-			break
-		}
-		m.quitStep++
-		if m.quitStep >= len(quitSteps) {
-			return m, tea.Quit
+		if m.phase == "quitting" {
+			if m.boot.quitTick() {
+				return m, tea.Quit
+			}
+			return m, quitStep()
 		}
-		return m, quitStep()
-
-	// -- portfolio data received -------------------------------------------
 	case PortfolioMsg:
-		m.portfolio = msg.Data
-		m.portfolioLoading = false
-		m.portfolioFlash = 3 // flash for 3 ticks after refresh
-		// Build dynamic tabs from portfolio data
-		m.buildTabs()
-		if m.phase == "running" {
-			m.recalcViewport()
-		}
-		// Schedule next poll in 30 seconds
-		cmds = append(cmds, tea.Tick(30*time.Second, func(_ time.Time) tea.Msg {
-			return PortfolioPollMsg{}
-		}))
-
-	// -- chain-specific portfolio data received ----------------------------
+		cmds = append(cmds, m.onPortfolio(msg))
 	case ChainPortfolioMsg:
-		m.chainPortfolio = msg.Data
-		m.chainPortfolioLoading = false
-		if m.phase == "running" {
+		if m.chain.receive(msg, m.tabs.activeSlug(), m.portfolio.data) && m.phase == "running" {
 			m.recalcViewport()
 		}
-
-	// -- portfolio poll timer fired ----------------------------------------
 	case PortfolioPollMsg:
+		if msg.Seq == m.pollSeq {
+			cmds = append(cmds, m.onPortfolioPoll())
+		}
+	case OrdersMsg:
+		cmds = append(cmds, m.onOrders(msg))
+	case OrdersPollMsg:
+		cmds = append(cmds, m.onOrdersPoll())
+	case OrderCancelledMsg:
+		cmds = append(cmds, m.onOrderCancelled(msg))
+	case OrdersCancelledMsg:
+		cmds = append(cmds, m.onOrdersCancelled(msg))
+	case WatchlistMsg:
+		cmds = append(cmds, m.onWatchlist(msg))
+	case WatchlistPollMsg:
+		cmds = append(cmds, m.onWatchlistPoll())
+	case CopiedMsg:
+		cmds = append(cmds, m.onCopied(msg))
+	case ToastDoneMsg:
+		// Redraws the stats bar without the toast.
+	case WatchAddedMsg:
+		cmds = append(cmds, m.onWatchAdded(msg))
+	case TrendingMsg:
+		cmds = append(cmds, m.onTrending(msg))
+	case TrendingPollMsg:
+		cmds = append(cmds, m.onTrendingPoll())
+	case XPMsg:
+		cmds = append(cmds, m.onXP(msg))
+	case UpdateMsg:
+		first, _, _ := strings.Cut(msg.Notes, "\n")
+		m.stats.update, m.stats.updateNote = msg.Version, first
+	case XPPollMsg:
 		if m.phase == "running" {
-			m.portfolioLoading = true
-			cmds = append(cmds, fetchPortfolio(m.server))
+			cmds = append(cmds, fetchXP(m.server))
 		}
-
-	// -- 1-second heartbeat ------------------------------------------------
 	case TickMsg:
 		if m.phase == "running" {
 			m.idleFrame++
-			if m.portfolioFlash > 0 {
-				m.portfolioFlash--
-			}
-			if m.ready {
-				m.viewport.SetContent(m.renderViewportContent())
-			}
+			m.ticker.rotate(m.idleFrame)
+			m.relayoutConfirm()
+			cmds = append(cmds, m.resumePolling())
 		}
-		cmds = append(cmds, tickEvery(time.Second))
-
-	// -- proxy log entry ---------------------------------------------------
+		cmds = append(cmds, tickEvery(m.heartbeat))
 	case LogMsg:
 		entry := proxy.LogEntry(msg)
-		m.logEntries = append(m.logEntries, entry)
-		if entry.Status == "success" {
-			m.requestCount++
-		}
-		if entry.Status == "error" {
-			m.errorCount++
+		if m.log.add(m.renderCtx(), entry) {
+			m.stats.count(entry)
 		}
-		if m.ready {
-			m.viewport.SetContent(m.renderViewportContent())
-			if m.autoScroll {
-				m.viewport.GotoBottom()
-			}
+		if m.phase == "running" {
+			m.relayoutConfirm()
 		}
 		cmds = append(cmds, listenForLogs(m.server.LogChannel()))
-
-	// -- spinner -----------------------------------------------------------
 	case spinner.TickMsg:
+		// Let the spinner stop when nothing on screen shows it; it is
+		// restarted below as soon as something does.
+		if !m.spinnerVisible() {
+			m.spinnerRunning = false
+			break
+		}
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		cmds = append(cmds, cmd)
-
-	// -- progress bar animation --------------------------------------------
 	case progress.FrameMsg:
-		progressModel, cmd := m.bootProgress.Update(msg)
-		m.bootProgress = progressModel.(progress.Model)
-		cmds = append(cmds, cmd)
+		cmds = append(cmds, m.boot.updateProgress(msg))
 	}
 
 	// viewport passthrough
-	if m.ready && m.phase == "running" {
-		var cmd tea.Cmd
-		m.viewport, cmd = m.viewport.Update(msg)
-		cmds = append(cmds, cmd)
-		if m.viewport.AtBottom() {
-			m.autoScroll = true
-		}
+	if m.phase == "running" {
+		cmds = append(cmds, m.log.update(msg))
+	}
+
+	if !m.spinnerRunning && m.spinnerVisible() {
+		m.spinnerRunning = true
+		cmds = append(cmds, m.spinner.Tick)
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
-func (m *ProxyViewModel) recalcViewport() {
-	portfolioHeight := m.portfolioPanelHeight()
-	if portfolioHeight > 0 {
-		portfolioHeight++ // +1 for the "\n" after the panel
+// quit starts the shutdown sequence, or exits at once during boot.
+func (m ProxyViewModel) quit() (tea.Model, tea.Cmd) {
+	switch m.phase {
+	case "boot":
+		return m, tea.Quit
+	case "quitting":
+		return m, nil
 	}
+	m.phase = "quitting"
+	m.boot.quitStep = 0
+	sum := m.server.Summary()
+	m.summary = &sum
+	return m, quitStep()
+}
 
-	configHeight := 0
-	if m.showConfig {
-		configHeight = m.configPanelHeight() + 1 // +1 for "\n" after panel
-	}
+// onBootTick advances the boot animation and switches to the running view
+// once it finishes.
+func (m *ProxyViewModel) onBootTick() tea.Cmd {
+	if !m.boot.tick() {
+		if m.static || m.boot.failed >= 0 {
+			return nil
+		}
+		return bootTick()
+	}
+	m.phase = "running"
+	m.port = m.server.Port()
+	m.stats.startTime = time.Now()
+	m.portfolio.loading = true
+	m.orders.loading = true
+	m.recalcViewport()
+	return tea.Batch(
+		tickEvery(m.heartbeat),
+		listenForLogs(m.server.LogChannel()),
+		fetchPortfolio(m.server),
+		fetchOrders(m.server), // for the count on the Orders tab
+		fetchXP(m.server),
+	)
+}
 
-	headerHeight := 1 + // compact logo line
-		1 + // blank after logo
-		2 + // tab bar (tabs + border)
-		portfolioHeight +
-		configHeight +
-		1 + // stats bar
-		1 + // blank
-		1 + // spec line
-		1 + // blank
-		1 + // activity header
-		1 // separator
-	footerHeight := 2 // footer separator + hint line
+// onResize records the new size. Dragging a window corner sends a storm of
+// these, so the layout waits for it to settle before recalculating.
+func (m *ProxyViewModel) onResize(msg tea.WindowSizeMsg) tea.Cmd {
+	m.width = msg.Width
+	m.height = msg.Height
+	formatter.TermWidth = msg.Width
+	// boot phase doesn't need resize handling.
+	if m.phase != "running" {
+		return nil
+	}
+	m.resizeSeq++
+	return resizeSettled(m.resizeSeq)
+}
 
-	vpHeight := m.height - headerHeight - footerHeight
-	if vpHeight < 3 {
-		vpHeight = 3
+func (m *ProxyViewModel) onPortfolio(msg PortfolioMsg) tea.Cmd {
+	m.portfolio.receive(msg.Data)
+	// Build dynamic tabs from portfolio data
+	m.tabs.build(m.portfolio.data)
+	if m.phase == "running" {
+		m.recalcViewport()
 	}
-
-	if !m.ready {
-		m.viewport = viewport.New(m.width, vpHeight)
-		m.viewport.Style = lipgloss.NewStyle()
-		m.ready = true
+	// Schedule next poll, backing off while the backend is offline.
+	if offline, _ := m.server.BackendOffline(); offline {
+		m.pollInterval = min(2*m.pollInterval, portfolioPollMaxInterval)
 	} else {
-		m.viewport.Width = m.width
-		m.viewport.Height = vpHeight
+		m.pollInterval = portfolioPollInterval
 	}
-	m.viewport.SetContent(m.renderViewportContent())
+	m.pollSeq++
+	seq := m.pollSeq
+	return tea.Tick(m.pollInterval, func(_ time.Time) tea.Msg {
+		return PortfolioPollMsg{Seq: seq}
+	})
 }
 
-// portfolioPanelHeight returns the number of terminal lines the portfolio panel
-// will occupy, including borders. Accounts for active tab.
-func (m *ProxyViewModel) portfolioPanelHeight() int {
-	if m.portfolio == nil && !m.portfolioLoading {
-		return 0
-	}
-	if m.portfolio == nil || m.portfolio.Error != "" {
-		return 3 // border top + content + border bottom
+// resumePolling refreshes the portfolio right away once the backend is back
+// after polling backed off, rather than waiting out the long interval.
+func (m *ProxyViewModel) resumePolling() tea.Cmd {
+	if m.pollInterval <= portfolioPollInterval {
+		return nil
 	}
-
-	if m.activeTab == 0 {
-		// "All" tab: compact panel
-		contentLines := 2 // header + blank
-		nativeCount := len(m.portfolio.NativeBalances)
-		if nativeCount > 0 {
-			contentLines += nativeCount
-			contentLines++ // blank after natives
-		}
-		posCount := len(m.portfolio.Positions)
-		if posCount == 0 {
-			contentLines++
-		} else {
-			shown := posCount
-			if shown > 4 {
-				shown = 4
-			}
-			contentLines += shown
-			if posCount > 4 {
-				contentLines++
-			}
-		}
-		return contentLines + 2 // +2 for borders
+	if offline, _ := m.server.BackendOffline(); offline {
+		return nil
 	}
+	m.pollInterval = portfolioPollInterval
+	m.pollSeq++
+	seq := m.pollSeq
+	return func() tea.Msg { return PortfolioPollMsg{Seq: seq} }
+}
 
-	// Chain tab: uses chainPortfolio data
-	if m.chainPortfolio == nil || m.chainPortfolioLoading {
-		return 3 // loading state: border + content + border
+func (m *ProxyViewModel) onPortfolioPoll() tea.Cmd {
+	if m.phase != "running" {
+		return nil
 	}
-	if m.chainPortfolio.Error != "" {
-		return 3
+	// Pick up `boba config chains` edits without a restart.
+	config.ReloadEnabledChains()
+	m.portfolio.loading = true
+	cmds := []tea.Cmd{fetchPortfolio(m.server)}
+	// Refresh the open chain tab too, keeping its data on screen.
+	if slug := m.tabs.activeSlug(); slug != "" && !m.chain.loading {
+		m.chain.loading = true
+		cmds = append(cmds, fetchChainPortfolio(m.server, slug))
 	}
+	return tea.Batch(cmds...)
+}
 
-	contentLines := 2 // header + blank
-	nativeCount := len(m.chainPortfolio.NativeBalances)
-	if nativeCount > 0 {
-		contentLines += nativeCount
-		contentLines++ // blank after natives
+// onOrders stores the open orders and keeps polling while the Orders tab
+// is open.
+func (m *ProxyViewModel) onOrders(msg OrdersMsg) tea.Cmd {
+	m.orders.receive(msg)
+	m.tabs.orderCount = m.orders.count()
+	if m.phase == "running" {
+		m.recalcViewport()
 	}
-	posCount := len(m.chainPortfolio.Positions)
-	if posCount == 0 {
-		contentLines++
-	} else {
-		contentLines += posCount
+	if !m.tabs.ordersActive() || m.orders.polling {
+		return nil
 	}
-
-	return contentLines + 2 // +2 for borders
+	m.orders.polling = true
+	return pollOrders()
 }
 
+// onOrdersPoll refreshes the orders, or lets polling lapse until the tab is
+// opened again.
+func (m *ProxyViewModel) onOrdersPoll() tea.Cmd {
+	m.orders.polling = false
+	if m.phase != "running" || !m.tabs.ordersActive() || m.orders.loading {
+		return nil
+	}
+	m.orders.loading = true
+	return fetchOrders(m.server)
+}
 
-func (m ProxyViewModel) View() string {
-	switch m.phase {
-	case "boot":
-		return m.viewBoot()
-	case "quitting":
-		return m.viewQuit()
-	default:
-		return m.viewRunning()
+// onWatchlist stores the watched tokens and keeps polling while the
+// Watchlist tab is open.
+func (m *ProxyViewModel) onWatchlist(msg WatchlistMsg) tea.Cmd {
+	m.watch.receive(msg)
+	if m.phase == "running" {
+		m.recalcViewport()
 	}
+	if !m.tabs.watchlistActive() || m.watch.polling {
+		return nil
+	}
+	m.watch.polling = true
+	return pollWatchlist()
 }
 
-var bootBubbleChars = []string{".", "o", "O", "◯", "●", "◉"}
+// onWatchlistPoll refreshes the watchlist, or lets polling lapse until the
+// tab is opened again.
+func (m *ProxyViewModel) onWatchlistPoll() tea.Cmd {
+	m.watch.polling = false
+	if m.phase != "running" || !m.tabs.watchlistActive() || m.watch.loading {
+		return nil
+	}
+	m.watch.loading = true
+	return fetchWatchlist(m.server)
+}
 
-func (m ProxyViewModel) viewBoot() string {
-	var b strings.Builder
+// onWatchAdded has the watchlist refetched the next time it is shown. The
+// outcome itself is in the activity log.
+func (m *ProxyViewModel) onWatchAdded(msg WatchAddedMsg) tea.Cmd {
+	if msg.Err == nil {
+		m.watch.dirty = true
+	}
+	return nil
+}
 
-	// ---- Bubble field — particles rising upward ----
-	fieldH := 8
-	fieldW := m.width
-	if fieldW < 40 {
-		fieldW = 40
+// onTrending shows the trending tokens, hiding the ticker if the fetch
+// failed, and schedules the next refresh while it is shown.
+func (m *ProxyViewModel) onTrending(msg TrendingMsg) tea.Cmd {
+	m.ticker.receive(msg)
+	if m.phase == "running" {
+		m.recalcViewport()
 	}
+	if !m.ticker.visible || m.ticker.polling {
+		return nil
+	}
+	m.ticker.polling = true
+	return pollTrending()
+}
 
-	grid := make([][]rune, fieldH)
-	for y := range grid {
-		grid[y] = make([]rune, fieldW)
-		for x := range grid[y] {
-			grid[y][x] = ' '
-		}
+// onXP updates the stats bar badge, hiding it when the fetch failed, and
+// schedules the next refresh.
+func (m *ProxyViewModel) onXP(msg XPMsg) tea.Cmd {
+	m.stats.xp = nil
+	if msg.OK {
+		m.stats.xp = &msg.XP
 	}
+	return pollXP()
+}
 
-	numBubbles := fieldW / 2
-	if numBubbles < 40 {
-		numBubbles = 40
-	}
-	for i := range numBubbles {
-		seed := i*7 + 13
-		baseX := (seed*31 + i*17) % fieldW
-		speed := 1 + (seed % 4)
-		cycleLen := fieldH + 14
-		baseY := fieldH + 5 - (m.bootFrame*speed/3+i*3)%cycleLen
-		wobble := int(math.Sin(float64(m.bootFrame)*0.15+float64(i)*2.3) * 1.5)
-		x := baseX + wobble
-		y := baseY
-
-		if y >= 0 && y < fieldH && x >= 0 && x < fieldW {
-			charIdx := y * len(bootBubbleChars) / fieldH
-			if charIdx >= len(bootBubbleChars) {
-				charIdx = len(bootBubbleChars) - 1
-			}
-			ch := bootBubbleChars[charIdx]
-			for _, r := range ch {
-				grid[y][x] = r
-				break
-			}
-		}
+// onTrendingPoll refreshes the ticker, or lets polling lapse until it is
+// shown again.
+func (m *ProxyViewModel) onTrendingPoll() tea.Cmd {
+	m.ticker.polling = false
+	if m.phase != "running" || !m.ticker.visible || m.ticker.loading {
+		return nil
 	}
+	m.ticker.loading = true
+	return fetchTrending(m.server)
+}
 
-	gradColors := []lipgloss.Color{
-		lipgloss.Color("#3B1F6E"),
-		lipgloss.Color("#4B2D8E"),
-		lipgloss.Color("#6B3FA0"),
-		lipgloss.Color("#7B52B5"),
-		lipgloss.Color("#8A5FD1"),
-		lipgloss.Color("#9B72E0"),
-		lipgloss.Color("#B184F5"),
-		lipgloss.Color("#D4A5FF"),
-	}
-	for y := range fieldH {
-		colorIdx := y * (len(gradColors) - 1) / max(fieldH-1, 1)
-		if colorIdx >= len(gradColors) {
-			colorIdx = len(gradColors) - 1
-		}
-		style := lipgloss.NewStyle().Foreground(gradColors[colorIdx])
-		b.WriteString(style.Render(string(grid[y])))
-		b.WriteString("\n")
+// toggleTicker shows or hides the trending tokens ticker, fetching them if
+// they are stale.
+func (m *ProxyViewModel) toggleTicker() tea.Cmd {
+	m.ticker.visible = !m.ticker.visible
+	m.recalcViewport()
+	switch {
+	case !m.ticker.visible || m.ticker.loading:
+		return nil
+	case m.ticker.due():
+		m.ticker.loading = true
+		return fetchTrending(m.server)
+	case !m.ticker.polling:
+		m.ticker.polling = true
+		return pollTrending()
+	}
+	return nil
+}
+
+// onOrderCancelled shows a failed cancel and refreshes the list either way.
+func (m *ProxyViewModel) onOrderCancelled(msg OrderCancelledMsg) tea.Cmd {
+	m.orders.cancelling = ""
+	if msg.Err != nil {
+		m.orders.cancelErr = msg.Err.Error()
+	}
+	if m.phase == "running" {
+		m.recalcViewport()
 	}
+	if m.orders.loading {
+		return nil
+	}
+	m.orders.loading = true
+	return fetchOrders(m.server)
+}
 
-	// ---- Logo fading in from block characters ----
-	b.WriteString("\n")
-	logoProgress := float64(m.bootFrame-2) / 18.0
-	if logoProgress < 0 {
-		logoProgress = 0
+// onOrdersCancelled lists the orders X couldn't cancel and refreshes the
+// list.
+func (m *ProxyViewModel) onOrdersCancelled(msg OrdersCancelledMsg) tea.Cmd {
+	m.orders.cancellingAll = 0
+	if failed := orders.Failed(msg.Results); len(failed) > 0 {
+		m.orders.cancelErr = stillActive(failed, len(msg.Results))
+	}
+	if m.phase == "running" {
+		m.recalcViewport()
 	}
-	if logoProgress > 1.0 {
-		logoProgress = 1.0
+	if m.orders.loading {
+		return nil
 	}
+	m.orders.loading = true
+	return fetchOrders(m.server)
+}
 
-	logoLines := ui.LogoLines()
-	for i, line := range logoLines {
-		lineP := logoProgress*1.3 - float64(i)*0.03
-		if lineP < 0 {
-			lineP = 0
-		}
-		if lineP > 1.0 {
-			lineP = 1.0
-		}
+func (m *ProxyViewModel) nextTab() tea.Cmd {
+	if !m.tabs.next() {
+		return nil
+	}
+	return m.tabChanged()
+}
 
-		var result string
-		if lineP >= 1.0 {
-			color := ui.ColorBoba
-			if i < len(ui.GradientPurple) {
-				color = ui.GradientPurple[i]
-			}
-			result = lipgloss.NewStyle().Foreground(color).Bold(true).Render(line)
-		} else if lineP > 0 {
-			result = bootPartialReveal(line, lineP, i)
-		} else {
-			result = lipgloss.NewStyle().Foreground(lipgloss.Color("#1a1a2e")).Render(line)
-		}
-		b.WriteString(result + "\n")
+func (m *ProxyViewModel) prevTab() tea.Cmd {
+	if !m.tabs.prev() {
+		return nil
 	}
+	return m.tabChanged()
+}
 
-	b.WriteString("\n")
+// tabChanged relays out the screen and starts loading the new chain tab, or
+// refreshes the orders or watchlist when their tab is opened.
+func (m *ProxyViewModel) tabChanged() tea.Cmd {
+	m.orders.confirming = false
+	m.setPick(false, 0)
+	m.recalcViewport()
+	if m.tabs.watchlistActive() {
+		switch {
+		case m.watch.loading || m.watch.polling && !m.watch.dirty:
+			// onWatchlist resumes polling when the fetch lands.
+			return nil
+		case m.watch.stale():
+			m.watch.loading = true
+			return fetchWatchlist(m.server)
+		}
+		m.watch.polling = true
+		return pollWatchlist()
+	}
+	if m.tabs.ordersActive() {
+		switch {
+		case m.orders.loading || m.orders.polling:
+			// onOrders resumes polling when the fetch lands.
+			return nil
+		case m.orders.stale():
+			m.orders.loading = true
+			return fetchOrders(m.server)
+		}
+		m.orders.polling = true
+		return pollOrders()
+	}
+	slug := m.tabs.activeSlug()
+	if slug == "" {
+		return nil
+	}
+	m.chain.open()
+	return fetchChainPortfolio(m.server, slug)
+}
 
-	// ---- Boot steps with typing reveal ----
-	checkStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true)
-	doneStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
-	activeStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
-	pendingStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#222222"))
-
-	for i, label := range bootStepLabels {
-		if i < m.bootStep {
-			fmt.Fprintf(&b, "  %s  %s\n",
-				checkStyle.Render("●"),
-				doneStyle.Render(label))
-		} else if i == m.bootStep && m.bootStep < len(bootStepLabels) {
-			charsRevealed := m.bootGlitch
-			if charsRevealed > len(label) {
-				charsRevealed = len(label)
-			}
-			revealed := label[:charsRevealed]
-			cursor := ""
-			if charsRevealed < len(label) {
-				cursor = "█"
-			}
-			fmt.Fprintf(&b, "  %s  %s%s\n",
-				m.spinner.View(),
-				activeStyle.Render(revealed),
-				lipgloss.NewStyle().Foreground(ui.ColorBoba).Render(cursor))
-		} else {
-			fmt.Fprintf(&b, "       %s\n",
-				pendingStyle.Render(strings.Repeat("·", len(label))))
-		}
+// cyclePositionSort switches the portfolio panels to the next sort order.
+func (m *ProxyViewModel) cyclePositionSort() tea.Cmd {
+	if m.tabs.listActive() {
+		return nil
 	}
+	m.positions.sort = (m.positions.sort + 1) % positionSortCount
+	m.applyPositionOrder()
+	return nil
+}
 
-	// ---- Progress bar ----
-	b.WriteString("\n")
-	pct := float64(m.bootStep) / float64(len(bootStepLabels))
-	b.WriteString("  ")
-	b.WriteString(m.bootProgress.ViewAs(pct))
-	b.WriteString("\n")
-
-	// ---- "CONNECTED" badge at the end ----
-	if m.bootFrame >= 36 {
-		onlineStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#1a1a2e")).
-			Background(ui.ColorBoba).
-			Bold(true).
-			Padding(0, 2)
-		b.WriteString("\n  " + onlineStyle.Render(" CONNECTED "))
-		b.WriteString("\n")
+// toggleDust shows or hides the positions below the dust threshold.
+func (m *ProxyViewModel) toggleDust() tea.Cmd {
+	if m.tabs.listActive() || m.positions.dust == 0 {
+		return nil
 	}
+	m.positions.showDust = !m.positions.showDust
+	m.applyPositionOrder()
+	return nil
+}
 
-	return b.String()
+// startFilterOrSearch opens the activity log search once the log has been
+// scrolled back or searched, or when there is no portfolio panel to filter;
+// otherwise it filters the positions.
+func (m *ProxyViewModel) startFilterOrSearch() tea.Cmd {
+	if m.log.autoScroll && m.log.search == "" && !m.tabs.listActive() && m.portfolio.visible() {
+		return m.startPositionFilter()
+	}
+	m.searchInput = lineInput{active: true, value: m.log.search}
+	return nil
 }
 
-// bootPartialReveal resolves logo characters left-to-right with block chars for unresolved.
-func bootPartialReveal(line string, progress float64, lineIdx int) string {
-	runes := []rune(line)
-	totalNonSpace := 0
-	for _, r := range runes {
-		if r != ' ' {
-			totalNonSpace++
-		}
+// editLogSearch applies a key press to the open search input. The log
+// jumps to the newest match once enter closes it.
+func (m *ProxyViewModel) editLogSearch(msg tea.KeyMsg) {
+	if m.searchInput.key(msg) {
+		m.log.setSearch(strings.TrimSpace(m.searchInput.value))
 	}
-	resolved := int(float64(totalNonSpace) * progress)
+}
 
-	var result strings.Builder
-	nonSpaceIdx := 0
-	blockReplace := []rune{'░', '▒', '▓', '█'}
+// cycleLogFilter shows the next category of calls in the activity log.
+func (m *ProxyViewModel) cycleLogFilter() tea.Cmd {
+	m.log.cycleFilter(m.renderCtx())
+	return nil
+}
 
-	color := ui.ColorBoba
-	if lineIdx < len(ui.GradientPurple) {
-		color = ui.GradientPurple[lineIdx]
+// denyCallOrNextMatch denies the oldest call held for confirmation, or
+// with none held, jumps to the next newer search match.
+func (m *ProxyViewModel) denyCallOrNextMatch() tea.Cmd {
+	if len(m.server.PendingConfirmations()) > 0 {
+		return m.denyCall()
 	}
-	resolvedStyle := lipgloss.NewStyle().Foreground(color).Bold(true)
-	unresolvedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#333355"))
+	m.log.findMatch(1)
+	return nil
+}
 
-	for _, r := range runes {
-		if r == ' ' {
-			result.WriteRune(' ')
-			continue
-		}
-		if nonSpaceIdx < resolved {
-			result.WriteString(resolvedStyle.Render(string(r)))
-		} else {
-			ch := blockReplace[rand.Intn(len(blockReplace))]
-			result.WriteString(unresolvedStyle.Render(string(ch)))
-		}
-		nonSpaceIdx++
-	}
-	return result.String()
+func (m *ProxyViewModel) prevLogMatch() tea.Cmd {
+	m.log.findMatch(-1)
+	return nil
 }
 
-func (m ProxyViewModel) viewQuit() string {
-	var b strings.Builder
+// startPositionFilter opens the filter input in the portfolio panel header.
+func (m *ProxyViewModel) startPositionFilter() tea.Cmd {
+	if m.tabs.listActive() || !m.portfolio.visible() {
+		return nil
+	}
+	m.filterInput = lineInput{active: true, value: m.positions.filter}
+	m.applyPositionOrder()
+	return nil
+}
 
-	b.WriteString("\n")
+// editPositionFilter applies a key press to the open filter input,
+// filtering the positions as it is typed.
+func (m *ProxyViewModel) editPositionFilter(msg tea.KeyMsg) {
+	m.filterInput.key(msg)
+	m.positions.filter = strings.TrimSpace(m.filterInput.value)
+	m.applyPositionOrder()
+}
 
-	titleStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true)
-	b.WriteString(titleStyle.Render("  SHUTTING DOWN"))
-	b.WriteString("\n\n")
+// applyPositionOrder hands the sort, filter and input state to both
+// portfolio panels, then relays out for their new heights.
+func (m *ProxyViewModel) applyPositionOrder() {
+	m.portfolio.order, m.portfolio.editing = m.positions, m.filterInput.active
+	m.chain.order, m.chain.editing = m.positions, m.filterInput.active
+	m.recalcViewport()
+}
 
-	checkStyle := lipgloss.NewStyle().Foreground(ui.ColorGreen).Bold(true)
-	activeStyle := lipgloss.NewStyle().Foreground(ui.ColorBright)
-	dimStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+func (m *ProxyViewModel) toggleConfig() tea.Cmd {
+	m.showConfig = !m.showConfig
+	m.addrView = 0
+	m.recalcViewport()
+	return nil
+}
 
-	for i, step := range quitSteps {
-		if i < m.quitStep {
-			b.WriteString(fmt.Sprintf("  %s  %s\n", checkStyle.Render("[ok]"), lipgloss.NewStyle().Foreground(ui.ColorGreen).Render(step)))
-		} else if i == m.quitStep {
-			b.WriteString(fmt.Sprintf("  %s  %s\n", m.spinner.View(), activeStyle.Render(step)))
-		} else {
-			b.WriteString(fmt.Sprintf("       %s\n", dimStyle.Render(step)))
-		}
+// cycleAddresses steps the config panel through full addresses and a QR
+// code for each, opening the panel if needed.
+func (m *ProxyViewModel) cycleAddresses() tea.Cmd {
+	if !m.showConfig {
+		m.showConfig = true
+		m.addrView = 1
+	} else {
+		m.addrView = (m.addrView + 1) % (2 + len(m.walletAddresses()))
 	}
+	m.recalcViewport()
+	return nil
+}
 
-	b.WriteString("\n")
-	return b.String()
+func (m ProxyViewModel) walletAddresses() []wallet.Address {
+	addrs, _ := wallet.Addresses(&config.AuthTokens{EVMAddress: m.evmAddr, SolanaAddress: m.solAddr}, "")
+	return addrs
 }
 
-func (m ProxyViewModel) viewRunning() string {
-	var b strings.Builder
-
-	verStyle := lipgloss.NewStyle().Foreground(ui.ColorGold)
-	b.WriteString("  " + ui.RenderLogoCompact() + "  " + verStyle.Render(version.Version))
-	b.WriteString("\n\n")
-
-	b.WriteString(m.renderTabBar())
-	b.WriteString("\n")
+func (m *ProxyViewModel) approveCall() tea.Cmd {
+	return m.decideCall(true)
+}
 
-	if m.portfolio != nil || m.portfolioLoading {
-		if m.activeTab == 0 {
-			b.WriteString(m.renderPortfolioPanel())
-		} else if m.activeTab < len(m.tabs) {
-			b.WriteString(m.renderChainPortfolio(m.tabs[m.activeTab]))
-		}
-		b.WriteString("\n")
+// approveCallOrCopy approves the held call when there is one, and copies
+// the latest trade's transaction hash otherwise.
+func (m *ProxyViewModel) approveCallOrCopy() tea.Cmd {
+	if len(m.server.PendingConfirmations()) > 0 {
+		return m.approveCall()
 	}
-
-	if m.showConfig {
-		b.WriteString(m.renderConfigPanel())
-		b.WriteString("\n")
+	hash := m.log.latestTxHash()
+	if hash == "" {
+		return m.stats.showToast("no transaction to copy yet", true, m.now())
 	}
+	return copyText(hash)
+}
 
-	b.WriteString(m.renderStatsBar())
-	b.WriteString("\n\n")
+// copyText copies text to the clipboard in the background.
+func copyText(text string) tea.Cmd {
+	return func() tea.Msg {
+		return CopiedMsg{Text: text, Err: clipboard.Copy(text)}
+	}
+}
 
-	b.WriteString(m.renderSpecLine())
-	b.WriteString("\n")
+// onCopied confirms a copy on the stats bar.
+func (m *ProxyViewModel) onCopied(msg CopiedMsg) tea.Cmd {
+	if msg.Err != nil {
+		return m.stats.showToast("copy failed: "+msg.Err.Error(), true, m.now())
+	}
+	return m.stats.showToast("copied "+shortHash(msg.Text)+" ✓", false, m.now())
+}
 
-	headerStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true)
+func (m *ProxyViewModel) denyCall() tea.Cmd {
+	return m.decideCall(false)
+}
 
-	var badge string
-	if m.autoScroll {
-		badge = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#1a1a2e")).
-			Background(ui.ColorGreen).
-			Bold(true).
-			Padding(0, 1).
-			Render("LIVE")
-	} else {
-		totalLines := strings.Count(m.viewport.View(), "\n") + 1
-		currentLine := m.viewport.YOffset + m.viewport.Height
-		if currentLine > totalLines {
-			currentLine = totalLines
-		}
-		badge = lipgloss.NewStyle().
-			Foreground(ui.ColorCyan).
-			Bold(true).
-			Render(fmt.Sprintf("[%d/%d]", currentLine, len(m.logEntries)))
+// decideCall answers the oldest call held for confirmation.
+func (m *ProxyViewModel) decideCall(approve bool) tea.Cmd {
+	pending := m.server.PendingConfirmations()
+	if len(pending) == 0 {
+		return nil
 	}
-	b.WriteString(fmt.Sprintf("  %s  %s\n", headerStyle.Render("ACTIVITY LOG"), badge))
+	m.server.Confirm(pending[0].ID, approve)
+	m.relayoutConfirm()
+	return nil
+}
 
-	// Separator width
-	sepLen := 50
-	if m.width > 4 {
-		sepLen = m.width - 4
-	}
-	if sepLen > 80 {
-		sepLen = 80
+// relayoutConfirm relays out the screen when the confirmation panel grows,
+// shrinks or goes away as calls are held and answered.
+func (m *ProxyViewModel) relayoutConfirm() {
+	if m.confirm.changed(m.renderCtx(), m.server.PendingConfirmations()) {
+		m.recalcViewport()
 	}
+}
 
-	sepStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
-	b.WriteString(sepStyle.Render("  " + strings.Repeat("━", sepLen)))
-	b.WriteString("\n")
+func (m *ProxyViewModel) orderUp() tea.Cmd {
+	m.orders.up()
+	m.recalcViewport()
+	return nil
+}
 
-	if m.ready {
-		b.WriteString(m.viewport.View())
-	} else {
-		b.WriteString(m.renderIdleText())
+func (m *ProxyViewModel) orderDown() tea.Cmd {
+	m.orders.down()
+	m.recalcViewport()
+	return nil
+}
+
+func (m *ProxyViewModel) toggleOrderDetail() tea.Cmd {
+	if _, ok := m.orders.selected(); ok {
+		m.orders.detail = !m.orders.detail
 	}
+	m.recalcViewport()
+	return nil
+}
 
-	b.WriteString("\n")
-	footerSep := lipgloss.NewStyle().Foreground(ui.ColorDim)
-	hintDim := lipgloss.NewStyle().Foreground(lipgloss.Color("#555555"))
-	hintKey := lipgloss.NewStyle().Foreground(ui.ColorBoba)
-	b.WriteString(footerSep.Render("  " + strings.Repeat("━", sepLen)))
-	b.WriteString("\n")
-	b.WriteString(hintDim.Render("  ") +
-		hintKey.Render("q") + hintDim.Render(" quit  ") +
-		hintKey.Render("←→") + hintDim.Render(" tabs  ") +
-		hintKey.Render("↑↓") + hintDim.Render(" scroll  ") +
-		hintKey.Render("end") + hintDim.Render(" follow  ") +
-		hintKey.Render("c") + hintDim.Render(" config"))
+// promptCancelOrder asks whether to cancel the selected order.
+func (m *ProxyViewModel) promptCancelOrder() tea.Cmd {
+	if _, ok := m.orders.selected(); !ok || m.orders.cancelling != "" {
+		return nil
+	}
+	m.orders.confirming = true
+	m.orders.cancelErr = ""
+	m.recalcViewport()
+	return nil
+}
 
-	return b.String()
+// promptCancelAll asks for the typed confirmation before cancelling every
+// open order.
+func (m *ProxyViewModel) promptCancelAll() tea.Cmd {
+	if len(m.orders.list) == 0 || m.orders.cancelling != "" || m.orders.cancellingAll > 0 {
+		return nil
+	}
+	m.orders.confirming = false
+	m.orders.cancelAll = lineInput{active: true}
+	m.orders.cancelErr = ""
+	m.recalcViewport()
+	return nil
 }
 
-// buildTabs rebuilds the tab list from the current portfolio data using the fixed chain order.
-func (m *ProxyViewModel) buildTabs() {
-	if m.portfolio == nil || m.portfolio.Error != "" {
-		m.tabs = []string{"All"}
-		if m.activeTab >= len(m.tabs) {
-			m.activeTab = 0
-		}
-		return
-	}
+// editCancelAll applies a key press to the cancel-all confirmation, and
+// cancels every order listed once enter is pressed on the exact phrase.
+func (m *ProxyViewModel) editCancelAll(msg tea.KeyMsg) tea.Cmd {
+	done := m.orders.cancelAll.key(msg)
+	if !done {
+		return nil
+	}
+	typed := strings.TrimSpace(m.orders.cancelAll.value)
+	m.orders.cancelAll = lineInput{}
+	defer m.recalcViewport()
+	if msg.Type != tea.KeyEnter {
+		return nil
+	}
+	phrase := orders.Cancel.ConfirmPhrase(len(m.orders.list))
+	if typed != phrase {
+		m.orders.cancelErr = fmt.Sprintf("typed %q, not %q; nothing cancelled", typed, phrase)
+		return nil
+	}
+	list := append([]orders.Order(nil), m.orders.list...)
+	m.orders.cancellingAll = len(list)
+	return cancelAllOrders(m.server, list)
+}
 
-	// Collect chains present in portfolio data
-	present := make(map[string]bool)
-	for _, nb := range m.portfolio.NativeBalances {
-		if nb.ChainName != "" {
-			present[nb.ChainName] = true
-		}
-	}
-	for _, pos := range m.portfolio.Positions {
-		if pos.ChainName != "" {
-			present[pos.ChainName] = true
-		}
+func (m *ProxyViewModel) confirmCancelOrder() tea.Cmd {
+	m.orders.confirming = false
+	o, ok := m.orders.selected()
+	if !ok {
+		m.recalcViewport()
+		return nil
 	}
+	m.orders.cancelling = o.ID
+	m.recalcViewport()
+	return cancelOrder(m.server, o)
+}
 
-	// Build tabs in fixed order, only including chains that are present
-	m.chainSlugs = make(map[string]string)
-	var chainNames []string
-	for _, name := range chainOrder {
-		if present[name] {
-			chainNames = append(chainNames, name)
-			if slug, ok := chainNameToSlug[name]; ok {
-				m.chainSlugs[name] = slug
-			} else {
-				m.chainSlugs[name] = strings.ToLower(name)
-			}
-		}
-	}
-	// Add any chains not in chainOrder (future-proofing)
-	for name := range present {
-		alreadyAdded := false
-		for _, n := range chainNames {
-			if n == name {
-				alreadyAdded = true
-				break
-			}
+func (m *ProxyViewModel) abortCancelOrder() tea.Cmd {
+	m.orders.confirming = false
+	m.recalcViewport()
+	return nil
+}
+
+// pickable returns the positions w can mark on the open portfolio panel:
+// those the All tab shows, or all of the chain tab's.
+func (m ProxyViewModel) pickable() []PortfolioPosition {
+	switch {
+	case m.tabs.listActive():
+		return nil
+	case m.tabs.active == 0:
+		if m.portfolio.data == nil || m.portfolio.data.Error != "" {
+			return nil
 		}
-		if !alreadyAdded {
-			chainNames = append(chainNames, name)
-			m.chainSlugs[name] = strings.ToLower(name)
+		positions := m.portfolio.order.apply(m.portfolio.data.Positions)
+		if len(positions) > 4 {
+			positions = positions[:4]
 		}
+		return positions
+	case m.chain.data == nil || m.chain.data.Error != "":
+		return nil
 	}
+	return m.chain.order.apply(m.chain.data.Positions)
+}
 
-	m.tabs = append([]string{"All"}, chainNames...)
-	if m.activeTab >= len(m.tabs) {
-		m.activeTab = len(m.tabs) - 1
+// startPick marks the first position shown, to pick one for the watchlist.
+func (m *ProxyViewModel) startPick() tea.Cmd {
+	if len(m.pickable()) == 0 {
+		return nil
 	}
+	m.setPick(true, 0)
+	return nil
 }
 
-// renderTabBar renders the tab bar as a sliding marquee — the active tab is
-// pinned to the left with the next tabs visible to its right. Arrows indicate
-// more tabs off-screen.
-func (m ProxyViewModel) renderTabBar() string {
-	activeStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(ui.ColorBoba).
-		Bold(true).
-		Padding(0, 2)
-
-	inactiveStyle := lipgloss.NewStyle().
-		Foreground(ui.ColorDim).
-		Padding(0, 2)
+func (m *ProxyViewModel) pickUp() tea.Cmd {
+	if m.pick > 0 {
+		m.setPick(true, m.pick-1)
+	}
+	return nil
+}
 
-	arrowStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true)
+func (m *ProxyViewModel) pickDown() tea.Cmd {
+	if m.pick < len(m.pickable())-1 {
+		m.setPick(true, m.pick+1)
+	}
+	return nil
+}
 
-	// Tab widths (label + 4 padding chars)
-	tabWidths := make([]int, len(m.tabs))
-	totalWidth := 0
-	for i, tab := range m.tabs {
-		tabWidths[i] = len(tab) + 4
-		totalWidth += tabWidths[i]
+// watchPicked adds the marked position's token to the watchlist.
+func (m *ProxyViewModel) watchPicked() tea.Cmd {
+	positions := m.pickable()
+	pick := m.pick
+	m.setPick(false, 0)
+	if pick >= len(positions) || positions[pick].TokenAddress == "" {
+		return nil
 	}
+	return addToWatchlist(m.server, positions[pick])
+}
 
-	availWidth := m.width - 4
-	if availWidth < 20 {
-		availWidth = 20
+// copyPicked copies the marked position's token address.
+func (m *ProxyViewModel) copyPicked() tea.Cmd {
+	positions := m.pickable()
+	pick := m.pick
+	m.setPick(false, 0)
+	if pick >= len(positions) || positions[pick].TokenAddress == "" {
+		return nil
 	}
+	return copyText(positions[pick].TokenAddress)
+}
 
-	// If everything fits, render all tabs normally
-	if totalWidth <= availWidth {
-		var tabs []string
-		for i, tab := range m.tabs {
-			if i == m.activeTab {
-				tabs = append(tabs, activeStyle.Render(tab))
-			} else {
-				tabs = append(tabs, inactiveStyle.Render(tab))
-			}
-		}
-		row := lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+func (m *ProxyViewModel) cancelPick() tea.Cmd {
+	m.setPick(false, 0)
+	return nil
+}
 
-		sepLen := m.width - 4
-		if sepLen > 80 {
-			sepLen = 80
-		}
-		border := lipgloss.NewStyle().Foreground(ui.ColorDim).Render("  " + strings.Repeat("━", sepLen))
-		return "  " + row + "\n" + border
+// setPick starts, moves or ends picking a position, marking it on the
+// portfolio panel it is on.
+func (m *ProxyViewModel) setPick(picking bool, pick int) {
+	m.picking, m.pick = picking, pick
+	m.portfolio.pick, m.chain.pick = -1, -1
+	switch {
+	case !picking:
+	case m.tabs.active == 0:
+		m.portfolio.pick = pick
+	default:
+		m.chain.pick = pick
 	}
+}
 
-	// Marquee: active tab pinned left, fill remaining width with tabs to the right
-	hasLeft := m.activeTab > 0
-	leftArrow := "◀ "
-	rightArrow := " ▶"
-	arrowW := 3
+func (m *ProxyViewModel) pauseLog() tea.Cmd {
+	m.log.pause()
+	return nil
+}
 
-	// Reserve space for arrows
-	windowW := availWidth
-	if hasLeft {
-		windowW -= arrowW
-	}
-	windowW -= arrowW // always reserve right arrow space
+func (m *ProxyViewModel) followLog() tea.Cmd {
+	m.log.follow()
+	return nil
+}
 
-	// Fill from active tab rightward
-	var visible []int
-	usedWidth := 0
-	for i := m.activeTab; i < len(m.tabs); i++ {
-		if usedWidth+tabWidths[i] > windowW && len(visible) > 0 {
-			break
-		}
-		visible = append(visible, i)
-		usedWidth += tabWidths[i]
-	}
+func (m *ProxyViewModel) toggleLogEntry() tea.Cmd {
+	m.log.toggleExpanded(m.renderCtx())
+	return nil
+}
 
-	hasRight := visible[len(visible)-1] < len(m.tabs)-1
+// spinnerVisible reports whether anything currently rendered uses the
+// spinner, so its ticks aren't spent redrawing an unchanged screen.
+func (m ProxyViewModel) spinnerVisible() bool {
+	if m.static {
+		return false
+	}
+	switch m.phase {
+	case "boot":
+		return m.boot.failed < 0
+	case "running":
+	default:
+		return false
+	}
+	if m.portfolio.loading || (m.tabs.activeSlug() != "" && (m.chain.data == nil || m.chain.loading)) {
+		return true
+	}
+	if m.tabs.ordersActive() && ((m.orders.loading && m.orders.count() < 0) || m.orders.cancelling != "" || m.orders.cancellingAll > 0) {
+		return true
+	}
+	if m.tabs.watchlistActive() && m.watch.loading && m.watch.fetched.IsZero() {
+		return true
+	}
+	return m.log.hasPending()
+}
 
-	// Render
-	var row string
-	if hasLeft {
-		row += arrowStyle.Render(leftArrow)
+// panelHeight returns the lines taken by the portfolio panel for the active
+// tab, or 0 when it is hidden.
+func (m ProxyViewModel) panelHeight() int {
+	if m.tabs.ordersActive() {
+		return m.orders.height(m.width)
 	}
+	if m.tabs.watchlistActive() {
+		return m.watch.height(m.width)
+	}
+	if !m.portfolio.visible() {
+		return 0
+	}
+	if m.tabs.active == 0 {
+		return m.portfolio.height()
+	}
+	if m.portfolio.data == nil || m.portfolio.data.Error != "" {
+		return 3
+	}
+	return m.chain.height()
+}
 
-	var parts []string
-	for _, idx := range visible {
-		if idx == m.activeTab {
-			parts = append(parts, activeStyle.Render(m.tabs[idx]))
-		} else {
-			parts = append(parts, inactiveStyle.Render(m.tabs[idx]))
-		}
+// recalcViewport lays out the running view, giving the log pane whatever
+// height the fixed components leave over.
+func (m *ProxyViewModel) recalcViewport() {
+	portfolioHeight := m.panelHeight()
+	if portfolioHeight > 0 {
+		portfolioHeight++ // +1 for the "\n" after the panel
 	}
-	row += lipgloss.JoinHorizontal(lipgloss.Top, parts...)
 
-	if hasRight {
-		row += arrowStyle.Render(rightArrow)
+	configHeight := 0
+	if m.showConfig {
+		configHeight = m.configPanelHeight() + 1 // +1 for "\n" after panel
 	}
 
-	sepLen := m.width - 4
-	if sepLen > 80 {
-		sepLen = 80
+	confirmHeight := m.confirm.height(m.renderCtx(), m.server.PendingConfirmations())
+
+	headerHeight := 1 + // compact logo line
+		1 + // blank after logo
+		tabHeight +
+		portfolioHeight +
+		configHeight +
+		confirmHeight +
+		1 + // stats bar
+		m.ticker.height() +
+		1 + // blank
+		1 + // spec line
+		1 + // blank
+		1 + // activity header
+		1 // separator
+	footerHeight := 2 // footer separator + hint line
+
+	vpHeight := m.height - headerHeight - footerHeight
+	if vpHeight < 3 {
+		vpHeight = 3
 	}
-	border := lipgloss.NewStyle().Foreground(ui.ColorDim).Render("  " + strings.Repeat("━", sepLen))
 
-	return "  " + row + "\n" + border
+	m.log.resize(m.renderCtx(), m.width, vpHeight)
 }
 
-// renderViewportContent returns the activity log for the scrollable viewport.
-func (m ProxyViewModel) renderViewportContent() string {
-	return m.renderLog()
+func (m ProxyViewModel) View() string {
+	switch m.phase {
+	case "boot":
+		return m.boot.view(m.width, m.spinner.View())
+	case "quitting":
+		return m.boot.viewQuit(m.spinner.View()) + RenderSessionSummary(*m.summary)
+	default:
+		return m.viewRunning()
+	}
 }
 
-// renderChainPortfolio renders the portfolio panel for a specific chain,
-// using data from the chain-specific API call (chainPortfolio).
-func (m ProxyViewModel) renderChainPortfolio(chainName string) string {
-	dimStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
-	symStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
-	titleStyle := lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true)
-
-	// Loading state
-	if m.chainPortfolio == nil || m.chainPortfolioLoading {
-		loadingMsg := dimStyle.Italic(true).
-			Render("  " + m.spinner.View() + " Loading " + chainName + " portfolio...")
-		return lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ui.ColorGold).
-			Padding(0, 2).
-			Render(loadingMsg)
-	}
-
-	// Error state
-	if m.chainPortfolio.Error != "" {
-		errMsg := dimStyle.Italic(true).Render("  " + chainName + " portfolio unavailable")
-		return lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ui.ColorDim).
-			Padding(0, 2).
-			Render(errMsg)
-	}
-
-	p := m.chainPortfolio
-	var lines []string
+func (m ProxyViewModel) viewRunning() string {
+	rc := m.renderCtx()
+	var b strings.Builder
 
-	// Header: chain name + total value
-	headerLine := fmt.Sprintf("  %s  Total: %s",
-		titleStyle.Render(strings.ToUpper(chainName)),
-		formatter.FormatUSD(p.TotalValueUSD))
-	lines = append(lines, headerLine)
-	lines = append(lines, "")
-
-	// Native balances
-	if len(p.NativeBalances) > 0 {
-		maxSymLen := 0
-		for _, nb := range p.NativeBalances {
-			if len(nb.Symbol) > maxSymLen {
-				maxSymLen = len(nb.Symbol)
-			}
-		}
-		for _, nb := range p.NativeBalances {
-			dot := lipgloss.NewStyle().Foreground(ui.ColorCyan).Render("●")
-			goldStyle := lipgloss.NewStyle().Foreground(ui.ColorGold)
-			paddedSym := nb.Symbol + strings.Repeat(" ", maxSymLen-len(nb.Symbol))
-			balStr := fmt.Sprintf("%.3f", nb.Balance)
-			usdStr := goldStyle.Render(fmt.Sprintf("$%.2f", nb.BalanceUSD))
-			lines = append(lines, fmt.Sprintf("  %s %s  %s  %s",
-				dot,
-				symStyle.Render(paddedSym),
-				balStr,
-				usdStr))
-		}
-		lines = append(lines, "")
-	}
+	verStyle := lipgloss.NewStyle().Foreground(ui.ColorGold)
+	b.WriteString("  " + ui.RenderLogoCompact() + "  " + verStyle.Render(version.Version))
+	b.WriteString("\n\n")
 
-	// Positions — all of them, server already filtered by chain_id
-	if len(p.Positions) == 0 {
-		lines = append(lines, dimStyle.Render("  No positions on "+chainName))
-	} else {
-		goldStyle := lipgloss.NewStyle().Foreground(ui.ColorGold)
+	b.WriteString(m.tabs.view(m.width))
+	b.WriteString("\n")
 
-		// Find max symbol length for padding
-		maxPosSymLen := 0
-		for _, pos := range p.Positions {
-			if len(pos.Symbol) > maxPosSymLen {
-				maxPosSymLen = len(pos.Symbol)
-			}
+	if m.tabs.ordersActive() {
+		b.WriteString(m.orders.view(rc, m.width))
+		b.WriteString("\n")
+	} else if m.tabs.watchlistActive() {
+		b.WriteString(m.watch.view(rc, m.width))
+		b.WriteString("\n")
+	} else if m.portfolio.visible() {
+		if m.tabs.active == 0 {
+			b.WriteString(m.portfolio.view(rc))
+		} else if m.tabs.active < len(m.tabs.tabs) {
+			b.WriteString(m.chain.view(rc, m.tabs.activeName()))
 		}
+		b.WriteString("\n")
+	}
 
-		var posTotal float64
-		for _, pos := range p.Positions {
-			posTotal += pos.ValueUSD
-		}
+	if m.showConfig {
+		b.WriteString(m.renderConfigPanel())
+		b.WriteString("\n")
+	}
 
-		// Pre-format all values to find max widths for alignment
-		type posRow struct {
-			symbol   string
-			valStr   string
-			allocStr string
-			pnlStr   string
-		}
-		var rows []posRow
-		maxValLen := 0
-		maxAllocLen := 0
-		for _, pos := range p.Positions {
-			alloc := 0.0
-			if posTotal > 0 {
-				alloc = (pos.ValueUSD / posTotal) * 100
-			}
-			valStr := fmt.Sprintf("$%.2f", pos.ValueUSD)
-			allocStr := fmt.Sprintf("%.0f%%", alloc)
-			pnlStr := formatter.FormatPercent(pos.PnlPercent)
-			if len(valStr) > maxValLen {
-				maxValLen = len(valStr)
-			}
-			if len(allocStr) > maxAllocLen {
-				maxAllocLen = len(allocStr)
-			}
-			rows = append(rows, posRow{
-				symbol:   pos.Symbol,
-				valStr:   valStr,
-				allocStr: allocStr,
-				pnlStr:   pnlStr,
-			})
-		}
+	if pending := m.server.PendingConfirmations(); len(pending) > 0 {
+		b.WriteString(m.confirm.view(rc, pending))
+		b.WriteString("\n")
+	}
 
-		for _, r := range rows {
-			paddedSym := r.symbol + strings.Repeat(" ", maxPosSymLen-len(r.symbol))
-			paddedVal := strings.Repeat(" ", maxValLen-len(r.valStr)) + r.valStr
-			paddedAlloc := strings.Repeat(" ", maxAllocLen-len(r.allocStr)) + r.allocStr
-			line := fmt.Sprintf("  %s  %s  %s  %s",
-				symStyle.Render(paddedSym),
-				goldStyle.Render(paddedVal),
-				dimStyle.Render(paddedAlloc),
-				r.pnlStr)
-			lines = append(lines, line)
-		}
+	b.WriteString(m.stats.view(rc, m.server))
+	b.WriteString("\n")
+	if m.ticker.height() > 0 {
+		b.WriteString(m.ticker.view(m.width))
+		b.WriteString("\n")
 	}
+	b.WriteString("\n")
 
-	content := strings.Join(lines, "\n")
-	return lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(ui.ColorGold).
-		BorderTop(true).
-		Padding(0, 2).
-		Render(content)
-}
+	b.WriteString(m.renderSpecLine())
+	b.WriteString("\n")
+
+	headerStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true)
+	logHeader := headerStyle.Render("ACTIVITY LOG")
+	if m.searchInput.active {
+		logHeader += " " + lipgloss.NewStyle().Foreground(ui.ColorBright).Render(m.searchInput.view())
+	} else if h := m.log.header(); h != "" {
+		logHeader += " " + h
+	}
+	b.WriteString(fmt.Sprintf("  %s  %s\n", logHeader, m.log.badge()))
 
-// renderLog renders the activity log entries for the viewport.
-func (m ProxyViewModel) renderLog() string {
-	if len(m.logEntries) == 0 {
-		return m.renderIdleText()
+	// Separator width
+	sepLen := 50
+	if m.width > 4 {
+		sepLen = m.width - 4
 	}
+	if sepLen > 80 {
+		sepLen = 80
+	}
+
+	sepStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	b.WriteString(sepStyle.Render("  " + strings.Repeat("━", sepLen)))
+	b.WriteString("\n")
+
+	b.WriteString(m.log.view(rc))
 
-	var blocks []string
-	for _, entry := range m.logEntries {
-		block := m.formatLogEntry(entry)
-		blocks = append(blocks, block)
+	b.WriteString("\n")
+	footerSep := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	hintDim := lipgloss.NewStyle().Foreground(lipgloss.Color("#555555"))
+	hintKey := lipgloss.NewStyle().Foreground(ui.ColorBoba)
+	b.WriteString(footerSep.Render("  " + strings.Repeat("━", sepLen)))
+	b.WriteString("\n")
+	if m.filterInput.active {
+		b.WriteString(hintDim.Render("  ") +
+			hintDim.Render("type to filter positions  ") +
+			hintKey.Render("enter") + hintDim.Render(" keep  ") +
+			hintKey.Render("esc") + hintDim.Render(" clear"))
+		return b.String()
+	}
+	if m.searchInput.active {
+		b.WriteString(hintDim.Render("  ") +
+			hintDim.Render("type to search tools and results  ") +
+			hintKey.Render("enter") + hintDim.Render(" search  ") +
+			hintKey.Render("esc") + hintDim.Render(" clear"))
+		return b.String()
+	}
+	if m.picking {
+		b.WriteString(hintDim.Render("  ") +
+			hintKey.Render("↑↓") + hintDim.Render(" choose position  ") +
+			hintKey.Render("enter") + hintDim.Render(" add to watchlist  ") +
+			hintKey.Render("y") + hintDim.Render(" copy address  ") +
+			hintKey.Render("esc") + hintDim.Render(" cancel"))
+		return b.String()
+	}
+	if m.log.search != "" {
+		b.WriteString(hintDim.Render("  ") +
+			hintKey.Render("n") + hintDim.Render(" newer  ") +
+			hintKey.Render("N") + hintDim.Render(" older  ") +
+			hintKey.Render("/") + hintDim.Render(" edit  ") +
+			hintKey.Render("end") + hintDim.Render(" follow  ") +
+			hintKey.Render("f") + hintDim.Render(" filter  ") +
+			hintKey.Render("q") + hintDim.Render(" quit"))
+		return b.String()
 	}
-	return strings.Join(blocks, "\n")
+	b.WriteString(hintDim.Render("  ") +
+		hintKey.Render("q") + hintDim.Render(" quit  ") +
+		hintKey.Render("←→") + hintDim.Render(" tabs  ") +
+		hintKey.Render("↑↓") + hintDim.Render(" scroll  ") +
+		hintKey.Render("end") + hintDim.Render(" follow  ") +
+		hintKey.Render("o") + hintDim.Render(" expand  ") +
+		hintKey.Render("s") + hintDim.Render(" sort  ") +
+		hintKey.Render("/") + hintDim.Render(" filter/search  ") +
+		hintKey.Render("f") + hintDim.Render(" calls  ") +
+		hintKey.Render("d") + hintDim.Render(" dust  ") +
+		hintKey.Render("t") + hintDim.Render(" ticker  ") +
+		hintKey.Render("w") + hintDim.Render(" watch  ") +
+		hintKey.Render("y") + hintDim.Render(" copy tx  ") +
+		hintKey.Render("c") + hintDim.Render(" config"))
+
+	return b.String()
 }
 
 func (m ProxyViewModel) renderConfigPanel() string {
 	dimStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
 	labelStyle := lipgloss.NewStyle().Foreground(ui.ColorDim).Width(8)
 	valStyle := lipgloss.NewStyle().Foreground(ui.ColorBright)
+	warnStyle := lipgloss.NewStyle().Foreground(ui.ColorGold)
 
 	var lines []string
 	lines = append(lines, fmt.Sprintf("  %s %s",
 		labelStyle.Render("Proxy"),
-		valStyle.Render(fmt.Sprintf("http://127.0.0.1:%d", m.port))))
+		valStyle.Render(m.server.URL())))
+	if m.server.LANExposed() {
+		lines = append(lines, "  "+strings.Repeat(" ", 9)+warnStyle.Render("reachable from your network; share the session token only with your own machines"))
+	}
 	if m.agentName != "" {
 		lines = append(lines, fmt.Sprintf("  %s %s",
 			labelStyle.Render("Agent"),
 			valStyle.Render(m.agentName)))
 	}
-	if m.evmAddr != "" {
-		lines = append(lines, fmt.Sprintf("  %s %s",
-			labelStyle.Render("EVM"),
-			valStyle.Render(truncate(m.evmAddr))))
-	}
-	if m.solAddr != "" {
+	addrs := m.walletAddresses()
+	for _, a := range addrs {
+		if m.addrView == 0 {
+			lines = append(lines, fmt.Sprintf("  %s %s",
+				labelStyle.Render(a.Label),
+				valStyle.Render(truncate(a.Address))))
+			continue
+		}
 		lines = append(lines, fmt.Sprintf("  %s %s",
-			labelStyle.Render("Solana"),
-			valStyle.Render(truncate(m.solAddr))))
+			labelStyle.Render(a.Label),
+			valStyle.Render(a.Address)))
+		lines = append(lines, "  "+strings.Repeat(" ", 9)+warnStyle.Render(a.Networks()))
+	}
+	if i := m.addrView - 2; i >= 0 && i < len(addrs) {
+		if code, err := qr.Encode(addrs[i].Address); err == nil {
+			lines = append(lines, "", "  "+dimStyle.Render("Scan to fund the "+addrs[i].Label+" address"))
+			for _, l := range strings.Split(code.Render(), "\n") {
+				lines = append(lines, "  "+l)
+			}
+		}
 	}
 
 	content := strings.Join(lines, "\n")
-	closeLine := dimStyle.Render("  press c to close")
+	hint := "a full addresses · c close"
+	if m.addrView > 0 {
+		hint = "a next QR · c close"
+		if m.addrView-1 == len(addrs) {
+			hint = "a short addresses · c close"
+		}
+	}
+	closeLine := dimStyle.Render("  press " + hint)
 
 	return content + "\n" + closeLine
 }
 
 // configPanelHeight returns the number of terminal lines the config panel uses.
 func (m ProxyViewModel) configPanelHeight() int {
-	lines := 1 // proxy line (always shown)
-	if m.agentName != "" {
-		lines++
-	}
-	if m.evmAddr != "" {
-		lines++
-	}
-	if m.solAddr != "" {
-		lines++
-	}
-	lines++ // "press c to close" line
-	return lines
+	return lipgloss.Height(m.renderConfigPanel())
 }
 
 func (m ProxyViewModel) renderSpecLine() string {
@@ -1566,7 +1319,16 @@ func (m ProxyViewModel) renderSpecLine() string {
 		parts = append(parts, dim.Render("agent ")+val.Render(m.agentName))
 	}
 
-	parts = append(parts, dim.Render("proxy ")+val.Render(fmt.Sprintf(":%d", m.port)))
+	if m.server.LANExposed() {
+		// Other machines need the whole address, not just the port.
+		lan := lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true)
+		parts = append(parts, dim.Render("proxy ")+val.Render(strings.TrimPrefix(m.server.URL(), "http://"))+" "+lan.Render("LAN"))
+	} else {
+		parts = append(parts, dim.Render("proxy ")+val.Render(fmt.Sprintf(":%d", m.port)))
+	}
+	if n := m.server.ToolCount(); n > 0 {
+		parts = append(parts, dim.Render("tools ")+val.Render(fmt.Sprint(n)))
+	}
 
 	if m.solAddr != "" {
 		parts = append(parts, dim.Render("sol ")+val.Render(truncate(m.solAddr)))
@@ -1578,306 +1340,9 @@ func (m ProxyViewModel) renderSpecLine() string {
 	return "  " + strings.Join(parts, sep)
 }
 
-func (m ProxyViewModel) renderStatsBar() string {
-	// Pulsing alive indicator -- alternates between bright and dim each second
-	var aliveDot string
-	if m.idleFrame%2 == 0 {
-		aliveDot = lipgloss.NewStyle().Foreground(ui.ColorGreen).Bold(true).Render("●")
-	} else {
-		aliveDot = lipgloss.NewStyle().Foreground(lipgloss.Color("#2D8B46")).Render("●")
-	}
-
-	uptime := time.Since(m.startTime)
-	uptimeStr := formatUptime(uptime)
-
-	reqStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
-	uptimeStyle := lipgloss.NewStyle().Foreground(ui.ColorCyan)
-	errStyle := lipgloss.NewStyle().Foreground(ui.ColorRed)
-	dimStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
-
-	parts := []string{
-		fmt.Sprintf("  %s %s", aliveDot, reqStyle.Render(fmt.Sprintf("%d requests", m.requestCount))),
-		fmt.Sprintf("%s %s", dimStyle.Render("^"), uptimeStyle.Render(uptimeStr)),
-	}
-
-	if m.errorCount > 0 {
-		parts = append(parts, fmt.Sprintf("%s %s",
-			lipgloss.NewStyle().Foreground(ui.ColorRed).Render("!"),
-			errStyle.Render(fmt.Sprintf("%d errors", m.errorCount))))
-	} else {
-		parts = append(parts, fmt.Sprintf("%s %s",
-			dimStyle.Render("~"),
-			lipgloss.NewStyle().Foreground(ui.ColorGreen).Render("0 errors")))
-	}
-
-	return strings.Join(parts, "  ")
-}
-
-func (m ProxyViewModel) renderPortfolioPanel() string {
-	p := m.portfolio
-
-	// Loading state — fetch in-flight, no data yet
-	if p == nil {
-		loadingMsg := lipgloss.NewStyle().Foreground(ui.ColorDim).Italic(true).
-			Render("  " + m.spinner.View() + " Loading portfolio...")
-		return lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ui.ColorGold).
-			Padding(0, 2).
-			Render(loadingMsg)
-	}
-
-	// Error state
-	if p.Error != "" {
-		dimMsg := lipgloss.NewStyle().Foreground(ui.ColorDim).Italic(true).
-			Render("  Portfolio unavailable")
-		return lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(ui.ColorDim).
-			Padding(0, 2).
-			Render(dimMsg)
-	}
-
-	var lines []string
-
-	// Header line: "PORTFOLIO  Total: $2,150.50    ↻ 25s"
-	titleStyle := lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true)
-	totalStr := formatter.FormatUSD(p.TotalValueUSD)
-
-	// Refresh indicator: spinner when loading, pulsing dot otherwise, flash green on fresh data
-	var refreshBadge string
-	if m.portfolioLoading {
-		refreshBadge = m.spinner.View()
-	} else if m.portfolioFlash > 0 {
-		refreshBadge = lipgloss.NewStyle().Foreground(ui.ColorGreen).Bold(true).Render("●")
-	} else {
-		// Pulsing dim dot
-		if m.idleFrame%2 == 0 {
-			refreshBadge = lipgloss.NewStyle().Foreground(ui.ColorDim).Render("●")
-		} else {
-			refreshBadge = lipgloss.NewStyle().Foreground(lipgloss.Color("#333333")).Render("●")
-		}
-	}
-
-	headerLine := fmt.Sprintf("  %s  Total: %s  %s", titleStyle.Render("PORTFOLIO"), totalStr, refreshBadge)
-	lines = append(lines, headerLine)
-	lines = append(lines, "")
-
-	// Native balances
-	if len(p.NativeBalances) > 0 {
-		// Find max symbol length for alignment
-		maxSymLen := 0
-		for _, nb := range p.NativeBalances {
-			if len(nb.Symbol) > maxSymLen {
-				maxSymLen = len(nb.Symbol)
-			}
-		}
-
-		for _, nb := range p.NativeBalances {
-			dot := lipgloss.NewStyle().Foreground(ui.ColorCyan).Render("●")
-			symStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
-			chainStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
-			goldStyle := lipgloss.NewStyle().Foreground(ui.ColorGold)
-			// Pad symbol to max length for alignment
-			paddedSym := nb.Symbol + strings.Repeat(" ", maxSymLen-len(nb.Symbol))
-			balStr := fmt.Sprintf("%.3f", nb.Balance)
-			usdStr := goldStyle.Render(fmt.Sprintf("$%.2f", nb.BalanceUSD))
-			chain := ""
-			if nb.ChainName != "" {
-				chain = chainStyle.Render("  (" + nb.ChainName + ")")
-			}
-			line := fmt.Sprintf("  %s %s  %s  %s%s",
-				dot,
-				symStyle.Render(paddedSym),
-				balStr,
-				usdStr,
-				chain)
-			lines = append(lines, line)
-		}
-		lines = append(lines, "")
-	}
-
-	// Positions (max 4)
-	if len(p.Positions) == 0 {
-		lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorDim).Render("  No positions"))
-	} else {
-		shown := p.Positions
-		if len(shown) > 4 {
-			shown = shown[:4]
-		}
-		for _, pos := range shown {
-			symStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
-			valStr := formatter.FormatUSD(pos.ValueUSD)
-			pnlStr := formatter.FormatPercent(pos.PnlPercent)
-			line := fmt.Sprintf("  %s  %s  %s",
-				symStyle.Render(pos.Symbol),
-				valStr,
-				pnlStr)
-			lines = append(lines, line)
-		}
-		if len(p.Positions) > 4 {
-			more := len(p.Positions) - 4
-			lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorDim).
-				Render(fmt.Sprintf("  +%d more", more)))
-		}
-	}
-
-	content := strings.Join(lines, "\n")
-
-	return lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(ui.ColorGold).
-		BorderTop(true).
-		Padding(0, 2).
-		Render(content)
-}
-
-func formatUptime(d time.Duration) string {
-	totalSec := int(d.Seconds())
-	h := totalSec / 3600
-	min := (totalSec % 3600) / 60
-	sec := totalSec % 60
-	if h > 0 {
-		return fmt.Sprintf("%dh %dm %ds", h, min, sec)
-	}
-	if min > 0 {
-		return fmt.Sprintf("%dm %ds", min, sec)
-	}
-	return fmt.Sprintf("%ds", sec)
-}
-
-var idlePatterns = []string{
-	"  .       Waiting for requests",
-	"  ..      Waiting for requests",
-	"  ...     Waiting for requests",
-	"  ....    Waiting for requests",
-	"  ...     Waiting for requests",
-	"  ..      Waiting for requests",
-}
-
-func (m ProxyViewModel) renderIdleText() string {
-	frame := m.idleFrame % len(idlePatterns)
-	return lipgloss.NewStyle().Foreground(ui.ColorDim).Render("\n" + idlePatterns[frame] + "\n")
-}
-
-func (m ProxyViewModel) formatLogEntry(entry proxy.LogEntry) string {
-	// Timestamp — cyan for terminal-hacker aesthetic
-	ts := entry.Timestamp.Format("15:04:05")
-	tsStyle := lipgloss.NewStyle().Foreground(ui.ColorCyan)
-
-	// Category tag
-	tag := getToolTag(entry.Tool)
-	tagStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1a1a2e")).
-		Background(tag.color).
-		Bold(true).
-		Padding(0, 1)
-	tagRendered := tagStyle.Render(tag.label)
-
-	// Tool name
-	toolColor := ui.ToolColor(entry.Tool)
-	toolStyle := lipgloss.NewStyle().Foreground(toolColor).Bold(true)
-
-	var statusIcon string
-	var detail string
-
-	switch entry.Status {
-	case "pending":
-		statusIcon = m.spinner.View()
-		desc := toolDescriptions[entry.Tool]
-		if desc == "" {
-			desc = fmt.Sprintf("Calling %s...", entry.Tool)
-		}
-		detail = lipgloss.NewStyle().Foreground(ui.ColorDim).Italic(true).Render(desc)
-
-	case "success":
-		statusIcon = lipgloss.NewStyle().Foreground(ui.ColorGreen).Bold(true).Render("OK")
-		durBadge := renderDurationBadge(entry.Duration)
-		detail = durBadge
-		if entry.Preview != "" {
-			previewStyle := lipgloss.NewStyle().Foreground(ui.ColorBright)
-			detail += " " + lipgloss.NewStyle().Foreground(ui.ColorDim).Render("->") + " " + previewStyle.Render(entry.Preview)
-		}
-
-	case "error":
-		statusIcon = lipgloss.NewStyle().Foreground(ui.ColorRed).Bold(true).Render("ERR")
-		durStr := formatDuration(entry.Duration)
-		errMsg := entry.Error
-		if len(errMsg) > 80 {
-			errMsg = errMsg[:77] + "..."
-		}
-		detail = lipgloss.NewStyle().Foreground(ui.ColorDim).Render(durStr) +
-			"  " + lipgloss.NewStyle().Foreground(ui.ColorRed).Bold(true).Render(errMsg)
-	}
-
-	statusLine := fmt.Sprintf("  %s %s %s %s %s",
-		tsStyle.Render(ts),
-		tagRendered,
-		toolStyle.Render(entry.Tool),
-		statusIcon,
-		detail,
-	)
-
-	// Append full formatted output below the status line for successful calls
-	if entry.Status == "success" && entry.FormattedOutput != "" {
-		indented := indentBlock(entry.FormattedOutput, "    ")
-		return statusLine + "\n" + indented + "\n"
-	}
-
-	return statusLine
-}
-
-// indentBlock prepends a prefix to every line of a multi-line string.
-func indentBlock(s string, prefix string) string {
-	lines := strings.Split(s, "\n")
-	for i, l := range lines {
-		lines[i] = prefix + l
-	}
-	return strings.Join(lines, "\n")
-}
-
-func renderDurationBadge(d time.Duration) string {
-	ms := d.Milliseconds()
-	durStr := formatDuration(d)
-	icon := "~"
-
-	var color lipgloss.Color
-	switch {
-	case ms < 500:
-		color = ui.ColorGreen
-		icon = ">"
-	case ms < 2000:
-		color = ui.ColorGold
-		icon = "~"
-	default:
-		color = ui.ColorRed
-		icon = "!"
-	}
-
-	badgeStyle := lipgloss.NewStyle().
-		Foreground(color).
-		Bold(true)
-
-	return badgeStyle.Render(fmt.Sprintf("%s %s", icon, durStr))
-}
-
-func listenForLogs(ch <-chan proxy.LogEntry) tea.Cmd {
-	return func() tea.Msg {
-		entry := <-ch
-		return LogMsg(entry)
-	}
-}
-
 func truncate(addr string) string {
 	if len(addr) >= 10 {
 		return addr[:6] + "..." + addr[len(addr)-4:]
 	}
 	return addr
 }
-
-func formatDuration(d time.Duration) string {
-	if d < time.Second {
-		return fmt.Sprintf("%dms", d.Milliseconds())
-	}
-	return fmt.Sprintf("%.1fs", d.Seconds())
-}
@@ -0,0 +1,262 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+)
+
+// runningModel returns a dashboard past its boot, with a tab per chain.
+func runningModel(t *testing.T) ProxyViewModel {
+	t.Helper()
+	useTempConfig(t)
+	server, err := proxy.NewProxyServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := NewProxyViewModel(server, "agent", "", "", 0, nil)
+	m.phase = "running"
+	var balances []NativeBalance
+	for _, name := range []string{"Solana", "Base", "BSC", "Ethereum", "Arbitrum", "Avalanche", "Monad"} {
+		balances = append(balances, NativeBalance{ChainName: name, Symbol: "X"})
+	}
+	m.tabs.build(&PortfolioData{NativeBalances: balances})
+	return m
+}
+
+// showsTab reports whether view has the tab label, whole or ellipsized.
+func showsTab(view, label string) bool {
+	for n := len(label); n >= 1; n-- {
+		if strings.Contains(view, ellipsize(label, n)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Shrinking the terminal a column at a time down to 10 wide never loses the
+// active tab, and the whole storm costs one viewport recalculation.
+func TestResizeStorm(t *testing.T) {
+	m := runningModel(t)
+	for m.tabs.activeName() != "Avalanche" {
+		if !m.tabs.next() {
+			t.Fatalf("no Avalanche tab in %v", m.tabs.tabs)
+		}
+	}
+	var model tea.Model = m
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+	model, _ = model.Update(ResizeSettledMsg{Seq: model.(ProxyViewModel).resizeSeq})
+
+	for w := 119; w >= 10; w-- {
+		var cmd tea.Cmd
+		model, cmd = model.Update(tea.WindowSizeMsg{Width: w, Height: 40})
+		if cmd == nil {
+			t.Fatalf("width %d: no settle scheduled", w)
+		}
+		view := model.View()
+		if !showsTab(view, "Avalanche") {
+			t.Fatalf("width %d: active tab missing from\n%s", w, view)
+		}
+	}
+
+	pv := model.(ProxyViewModel)
+	if pv.log.width != 120 {
+		t.Errorf("viewport recalculated mid-storm at width %d", pv.log.width)
+	}
+	model, _ = model.Update(ResizeSettledMsg{Seq: pv.resizeSeq - 1})
+	if w := model.(ProxyViewModel).log.width; w != 120 {
+		t.Errorf("a stale settle recalculated the viewport to width %d", w)
+	}
+	model, _ = model.Update(ResizeSettledMsg{Seq: pv.resizeSeq})
+	if w := model.(ProxyViewModel).log.width; w != 10 {
+		t.Errorf("after the storm the viewport is %d wide, want 10", w)
+	}
+}
+
+// Every tab, selected at every narrow width, still renders.
+func TestTabBarNarrow(t *testing.T) {
+	m := runningModel(t)
+	tb := m.tabs
+	for i := range tb.tabs {
+		tb.active = i
+		for w := 1; w <= 40; w++ {
+			if view := tb.view(w); !showsTab(view, tb.label(i)) {
+				t.Errorf("tab %q at width %d: missing from %q", tb.label(i), w, view)
+			}
+		}
+	}
+}
+
+// bootModel returns a dashboard booting with one task, whose result the
+// test delivers.
+func bootModel(t *testing.T) tea.Model {
+	t.Helper()
+	useTempConfig(t)
+	server, err := proxy.NewProxyServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := func() (string, error) { return "token ready", nil }
+	return NewProxyViewModel(server, "agent", "", "", 0, []BootTask{task})
+}
+
+// tickBoot sends up to n boot ticks and returns the model once it is
+// running, or after the last tick.
+func tickBoot(model tea.Model, n int) tea.Model {
+	for range n {
+		if model.(ProxyViewModel).phase != "boot" {
+			break
+		}
+		model, _ = model.Update(BootTickMsg{})
+	}
+	return model
+}
+
+// The boot animation waits on its task, then hands over to the running
+// view, which starts listening for logs and fetching the portfolio.
+func TestBootToRunning(t *testing.T) {
+	model := bootModel(t)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 40})
+
+	model = tickBoot(model, 2*bootFrames)
+	if phase := model.(ProxyViewModel).phase; phase != "boot" {
+		t.Fatalf("phase %q before the first task reported", phase)
+	}
+	if view := model.View(); !strings.Contains(view, bootStepLabels[0]) {
+		t.Errorf("boot view lacks its first step:\n%s", view)
+	}
+
+	model, _ = model.Update(BootStepMsg{Step: 0, Detail: "token ready"})
+	var cmd tea.Cmd
+	for range 2 * bootFrames {
+		if model.(ProxyViewModel).phase != "boot" {
+			break
+		}
+		model, cmd = model.Update(BootTickMsg{})
+	}
+	m := model.(ProxyViewModel)
+	if m.phase != "running" {
+		t.Fatalf("phase %q after the boot finished", m.phase)
+	}
+	if cmd == nil || !m.portfolio.loading || !m.log.ready {
+		t.Errorf("running view not started: cmd %v, portfolio loading %v, log ready %v", cmd != nil, m.portfolio.loading, m.log.ready)
+	}
+	if m.BootErr() != nil {
+		t.Errorf("BootErr() = %v", m.BootErr())
+	}
+	if view := m.View(); strings.Contains(view, bootStepLabels[0]) {
+		t.Errorf("boot steps still shown when running:\n%s", view)
+	}
+}
+
+// A failed task stops the boot where it is and reports the error.
+func TestBootFailure(t *testing.T) {
+	model := bootModel(t)
+	model, _ = model.Update(BootStepMsg{Step: 0, Err: errors.New("keyring locked")})
+	model = tickBoot(model, 2*bootFrames)
+	m := model.(ProxyViewModel)
+	if m.phase != "boot" {
+		t.Fatalf("phase %q after a failed step", m.phase)
+	}
+	if err := m.BootErr(); err == nil || err.Error() != "keyring locked" {
+		t.Errorf("BootErr() = %v", err)
+	}
+}
+
+// Tab and shift+tab move between tabs and stop at either end; the arrow
+// keys do the same.
+func TestTabSwitching(t *testing.T) {
+	var model tea.Model = runningModel(t)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	model, _ = model.Update(ResizeSettledMsg{Seq: model.(ProxyViewModel).resizeSeq})
+	tabs := model.(ProxyViewModel).tabs
+	n := len(tabs.tabs)
+	if n < 3 {
+		t.Fatalf("want several tabs, got %v", tabs.tabs)
+	}
+
+	press := func(key tea.KeyMsg) {
+		model, _ = model.Update(key)
+	}
+	active := func() int { return model.(ProxyViewModel).tabs.active }
+
+	press(tea.KeyMsg{Type: tea.KeyTab})
+	if active() != 1 {
+		t.Fatalf("tab: active %d, want 1", active())
+	}
+	if name := model.(ProxyViewModel).tabs.activeName(); !showsTab(model.View(), name) {
+		t.Errorf("view lacks the active tab %q", name)
+	}
+	press(tea.KeyMsg{Type: tea.KeyRight})
+	if active() != 2 {
+		t.Fatalf("right: active %d, want 2", active())
+	}
+	press(tea.KeyMsg{Type: tea.KeyShiftTab})
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	press(tea.KeyMsg{Type: tea.KeyLeft})
+	if active() != 0 {
+		t.Errorf("back past the first tab: active %d, want 0", active())
+	}
+	for range n + 2 {
+		press(tea.KeyMsg{Type: tea.KeyTab})
+	}
+	if active() != n-1 {
+		t.Errorf("past the last tab: active %d, want %d", active(), n-1)
+	}
+}
+
+// New log entries keep the log at the bottom until the user scrolls up;
+// then it stays put until they jump back to the end.
+func TestLogAutoscroll(t *testing.T) {
+	var model tea.Model = runningModel(t)
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 120, Height: 30})
+	model, _ = model.Update(ResizeSettledMsg{Seq: model.(ProxyViewModel).resizeSeq})
+
+	start := time.Now()
+	n := 0
+	appendLogs := func(count int) {
+		for range count {
+			n++
+			model, _ = model.Update(LogMsg(proxy.LogEntry{
+				ID:        fmt.Sprintf("req-%d", n),
+				Timestamp: start.Add(time.Duration(n) * time.Second),
+				Tool:      "get_token_info",
+				Status:    "success",
+				Preview:   fmt.Sprintf("result %d", n),
+			}))
+		}
+	}
+	log := func() logPane { return model.(ProxyViewModel).log }
+
+	appendLogs(40)
+	if l := log(); l.maxOffset() == 0 || !l.atBottom() || !l.autoScroll {
+		t.Fatalf("not following: offset %d of %d", l.offset, l.maxOffset())
+	}
+	if view := model.View(); !strings.Contains(view, "result 40") {
+		t.Errorf("newest entry not in view:\n%s", view)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyUp})
+	paused := log().offset
+	if log().autoScroll || log().atBottom() {
+		t.Fatalf("still following after scrolling up (offset %d)", paused)
+	}
+	appendLogs(10)
+	if l := log(); l.offset != paused || l.autoScroll {
+		t.Errorf("scrolled while paused: offset %d, was %d", l.offset, paused)
+	}
+
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")})
+	appendLogs(5)
+	if l := log(); !l.atBottom() || !l.autoScroll {
+		t.Errorf("not following after G: offset %d of %d", l.offset, l.maxOffset())
+	}
+	if view := model.View(); !strings.Contains(view, "result 55") {
+		t.Errorf("newest entry not in view after G:\n%s", view)
+	}
+}
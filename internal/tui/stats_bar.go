@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// statsBar is the one-line summary of uptime, request and error counts.
+type statsBar struct {
+	startTime    time.Time
+	requestCount int
+	errorCount   int
+	errorKinds   map[string]int // errorCount by proxy.ErrorKind; "" for the proxy's own refusals
+	// toast is a short note, such as what was just copied, shown until
+	// toastUntil; toastFailed shows it as an error.
+	toast       string
+	toastUntil  time.Time
+	toastFailed bool
+	// xp is the user's level and XP, or nil while unknown or when the
+	// last fetch failed.
+	xp *formatter.UserXP
+	// update is a newer release and the first line of its notes, empty
+	// until the update check finds one.
+	update, updateNote string
+}
+
+// toastDuration is how long a toast stays on the stats bar.
+const toastDuration = 2 * time.Second
+
+// showToast puts note on the stats bar for toastDuration. The returned
+// command redraws the bar once it is gone.
+func (b *statsBar) showToast(note string, failed bool, now time.Time) tea.Cmd {
+	b.toast, b.toastUntil, b.toastFailed = note, now.Add(toastDuration), failed
+	return tea.Tick(toastDuration, func(_ time.Time) tea.Msg { return ToastDoneMsg{} })
+}
+
+// count tallies a finished log entry.
+func (b *statsBar) count(entry proxy.LogEntry) {
+	switch entry.Status {
+	case "success":
+		b.requestCount++
+	case "error":
+		b.errorCount++
+		if b.errorKinds == nil {
+			b.errorKinds = make(map[string]int)
+		}
+		b.errorKinds[entry.ErrorKind]++
+	}
+}
+
+func (b statsBar) view(rc renderCtx, server *proxy.ProxyServer) string {
+	// Pulsing alive indicator -- alternates between bright and dim each second
+	var aliveDot string
+	if rc.idleFrame%2 == 0 {
+		aliveDot = lipgloss.NewStyle().Foreground(ui.ColorGreen).Bold(true).Render("●")
+	} else {
+		aliveDot = lipgloss.NewStyle().Foreground(lipgloss.Color("#2D8B46")).Render("●")
+	}
+
+	uptime := time.Since(b.startTime)
+	uptimeStr := formatUptime(uptime)
+
+	reqStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
+	uptimeStyle := lipgloss.NewStyle().Foreground(ui.ColorCyan)
+	errStyle := lipgloss.NewStyle().Foreground(ui.ColorRed)
+	dimStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+
+	parts := []string{
+		fmt.Sprintf("  %s %s", aliveDot, reqStyle.Render(fmt.Sprintf("%d requests", b.requestCount))),
+		fmt.Sprintf("%s %s", dimStyle.Render("^"), uptimeStyle.Render(uptimeStr)),
+	}
+
+	// The backend stopped answering; panels show their last data.
+	if server != nil {
+		if offline, _ := server.BackendOffline(); offline {
+			parts = append(parts, lipgloss.NewStyle().Foreground(ui.ColorRed).Bold(true).Render("BACKEND OFFLINE"))
+		}
+	}
+
+	// With several agents attached, show how many tool calls each made.
+	if server != nil {
+		if clients := server.ClientCalls(); len(clients) > 1 {
+			var counts []string
+			for _, c := range clients {
+				counts = append(counts, fmt.Sprintf("%s %d", c.Client, c.Calls))
+			}
+			parts = append(parts, dimStyle.Render(strings.Join(counts, " · ")))
+		}
+	}
+
+	// A tool policy limits what agents can do; say so.
+	if policy := config.GetToolPolicy(); policy.Restricted() {
+		parts = append(parts, lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).
+			Render("[tools: "+policy.String()+"]"))
+	}
+
+	// Injected failures are counted apart from real errors.
+	if server != nil {
+		if _, on := server.Chaos(); on {
+			var injected int64
+			for _, n := range server.ChaosCounts() {
+				injected += n
+			}
+			parts = append(parts, lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).
+				Render(fmt.Sprintf("[chaos] %d injected", injected)))
+		}
+	}
+
+	if b.xp != nil {
+		parts = append(parts, xpBadge(*b.xp))
+	}
+
+	if b.update != "" {
+		note := b.update + " available · boba update"
+		if b.updateNote != "" {
+			note += " · " + ellipsize(b.updateNote, 40)
+		}
+		parts = append(parts, dimStyle.Render(note))
+	}
+
+	if b.toast != "" && rc.now.Before(b.toastUntil) {
+		color := ui.ColorGreen
+		if b.toastFailed {
+			color = ui.ColorRed
+		}
+		parts = append(parts, lipgloss.NewStyle().Foreground(color).Bold(true).Render(b.toast))
+	}
+
+	if b.errorCount > 0 {
+		parts = append(parts, fmt.Sprintf("%s %s",
+			lipgloss.NewStyle().Foreground(ui.ColorRed).Render("!"),
+			b.errorBreakdown(errStyle, dimStyle)))
+	} else {
+		parts = append(parts, fmt.Sprintf("%s %s",
+			dimStyle.Render("~"),
+			lipgloss.NewStyle().Foreground(ui.ColorGreen).Render("0 errors")))
+	}
+
+	return strings.Join(parts, "  ")
+}
+
+// errorBreakdown splits the error count by kind, e.g. "2 rejected · 1
+// network". Rejections are gold: the backend answered, just not with a yes.
+func (b statsBar) errorBreakdown(errStyle, dimStyle lipgloss.Style) string {
+	var counts []string
+	for _, kind := range proxy.ErrorKinds {
+		n := b.errorKinds[kind]
+		if n == 0 {
+			continue
+		}
+		style := errStyle
+		if kind == proxy.ErrorRejected {
+			style = lipgloss.NewStyle().Foreground(ui.ColorGold)
+		}
+		counts = append(counts, style.Render(fmt.Sprintf("%d %s", n, kind)))
+	}
+	if n := b.errorKinds[""]; n > 0 {
+		counts = append(counts, errStyle.Render(fmt.Sprintf("%d other", n)))
+	}
+	return strings.Join(counts, dimStyle.Render(" · "))
+}
+
+// shortHash abbreviates a hash or address for a toast, as a1b2…f9e8.
+func shortHash(s string) string {
+	if len(s) <= 12 {
+		return s
+	}
+	return s[:6] + "…" + s[len(s)-4:]
+}
+
+func formatUptime(d time.Duration) string {
+	totalSec := int(d.Seconds())
+	h := totalSec / 3600
+	min := (totalSec % 3600) / 60
+	sec := totalSec % 60
+	if h > 0 {
+		return fmt.Sprintf("%dh %dm %ds", h, min, sec)
+	}
+	if min > 0 {
+		return fmt.Sprintf("%dm %ds", min, sec)
+	}
+	return fmt.Sprintf("%ds", sec)
+}
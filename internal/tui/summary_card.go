@@ -0,0 +1,60 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// summaryCardWidth is the outer width of the session summary card.
+const summaryCardWidth = 52
+
+// RenderSessionSummary renders the recap of a proxy session shown while the
+// dashboard shuts down and printed once it has exited.
+func RenderSessionSummary(sum proxy.SessionSummary) string {
+	title := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true)
+	label := lipgloss.NewStyle().Foreground(ui.ColorDim).Width(18)
+	value := lipgloss.NewStyle().Foreground(ui.ColorBright)
+	dim := lipgloss.NewStyle().Foreground(ui.ColorDim)
+
+	row := func(name, v string) string { return label.Render(name) + value.Render(v) }
+	errs := value.Render("0")
+	if sum.Errors > 0 {
+		errs = lipgloss.NewStyle().Foreground(ui.ColorRed).Render(fmt.Sprint(sum.Errors))
+	}
+	lines := []string{
+		title.Render("SESSION SUMMARY"),
+		"",
+		row("duration", formatUptime(sum.Duration)),
+		row("requests", fmt.Sprint(sum.Requests)),
+		label.Render("errors") + errs,
+		row("trades", fmt.Sprint(sum.Trades)),
+		row("orders", fmt.Sprintf("%d created · %d cancelled", sum.OrdersCreated, sum.OrdersCancelled)),
+	}
+	if sum.VolumeUSD > 0 {
+		lines = append(lines, row("volume", formatter.DisplayCurrency().Amount(sum.VolumeUSD, 2)))
+	}
+	if len(sum.TopTools) > 0 {
+		lines = append(lines, "", dim.Render("top tools"))
+		for _, t := range sum.TopTools {
+			lines = append(lines, "  "+label.Width(32).Render(t.Tool)+value.Render(fmt.Sprint(t.Calls)))
+		}
+	}
+
+	card := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorBoba).
+		Padding(0, 1).
+		Width(summaryCardWidth - 2).
+		Render(strings.Join(lines, "\n"))
+
+	var b strings.Builder
+	for _, line := range strings.Split(card, "\n") {
+		b.WriteString("  " + line + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,287 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// tabHeight is the tab row plus its border.
+const tabHeight = 2
+
+// ordersTab is the label of the last tab, which lists open orders.
+const ordersTab = "Orders"
+
+// watchlistTab is the label of the tab before Orders, which lists the
+// watched tokens.
+const watchlistTab = "Watchlist"
+
+// tabBar is the row of tabs above the portfolio panel. Tab 0 is "All", then
+// come the chains present in the portfolio, then the Watchlist tab, and the
+// Orders tab is last.
+type tabBar struct {
+	tabs   []string
+	active int
+	slugs  map[string]string
+	// orderCount is shown on the Orders tab; -1 until orders are fetched.
+	orderCount int
+}
+
+func newTabBar() tabBar {
+	return tabBar{tabs: []string{"All", watchlistTab, ordersTab}, slugs: make(map[string]string), orderCount: -1}
+}
+
+// ordersActive reports whether the Orders tab is selected.
+func (t tabBar) ordersActive() bool {
+	return t.active == len(t.tabs)-1 && t.tabs[t.active] == ordersTab
+}
+
+// watchlistActive reports whether the Watchlist tab is selected.
+func (t tabBar) watchlistActive() bool {
+	return t.active == len(t.tabs)-2 && t.tabs[t.active] == watchlistTab
+}
+
+// listActive reports whether a tab without a portfolio panel is selected.
+func (t tabBar) listActive() bool {
+	return t.ordersActive() || t.watchlistActive()
+}
+
+// label returns the text shown on tab i.
+func (t tabBar) label(i int) string {
+	if t.tabs[i] == ordersTab && t.orderCount >= 0 {
+		return fmt.Sprintf("%s (%d)", ordersTab, t.orderCount)
+	}
+	return t.tabs[i]
+}
+
+// activeSlug returns the chain slug for the selected tab, or "" on All,
+// Watchlist and Orders.
+func (t tabBar) activeSlug() string {
+	if t.active <= 0 || t.active >= len(t.tabs) {
+		return ""
+	}
+	return t.slugs[t.tabs[t.active]]
+}
+
+// activeName returns the label of the selected tab.
+func (t tabBar) activeName() string {
+	if t.active < 0 || t.active >= len(t.tabs) {
+		return ""
+	}
+	return t.tabs[t.active]
+}
+
+// next selects the tab to the right and reports whether the selection moved.
+func (t *tabBar) next() bool {
+	if len(t.tabs) <= 1 {
+		return false
+	}
+	prev := t.active
+	t.active++
+	if t.active >= len(t.tabs) {
+		t.active = len(t.tabs) - 1
+	}
+	return t.active != prev
+}
+
+// prev selects the tab to the left and reports whether the selection moved.
+func (t *tabBar) prev() bool {
+	if t.active <= 0 {
+		return false
+	}
+	t.active--
+	return true
+}
+
+// buildTabs rebuilds the tab list from the current portfolio data using the fixed chain order.
+func (t *tabBar) build(portfolio *PortfolioData) {
+	onOrders, onWatchlist := t.ordersActive(), t.watchlistActive()
+	if portfolio == nil || portfolio.Error != "" {
+		t.tabs = []string{"All", watchlistTab, ordersTab}
+		t.keepListTab(onOrders, onWatchlist)
+		return
+	}
+
+	// Collect enabled chains present in portfolio data
+	present := make(map[string]bool)
+	for _, nb := range portfolio.NativeBalances {
+		if nb.ChainName != "" && config.IsChainEnabled(nb.ChainName) {
+			present[nb.ChainName] = true
+		}
+	}
+	for _, pos := range portfolio.Positions {
+		if pos.ChainName != "" && config.IsChainEnabled(pos.ChainName) {
+			present[pos.ChainName] = true
+		}
+	}
+
+	// Build tabs in fixed order, only including chains that are present
+	t.slugs = make(map[string]string)
+	var chainNames []string
+	for _, c := range config.Chains {
+		if present[c.Name] {
+			chainNames = append(chainNames, c.Name)
+			t.slugs[c.Name] = c.Slug
+		}
+	}
+	// Add any chains not in config.Chains (future-proofing)
+	for name := range present {
+		alreadyAdded := false
+		for _, n := range chainNames {
+			if n == name {
+				alreadyAdded = true
+				break
+			}
+		}
+		if !alreadyAdded {
+			chainNames = append(chainNames, name)
+			t.slugs[name] = strings.ToLower(name)
+		}
+	}
+
+	t.tabs = append(append([]string{"All"}, chainNames...), watchlistTab, ordersTab)
+	t.keepListTab(onOrders, onWatchlist)
+}
+
+// keepListTab keeps the Orders or Watchlist tab selected after the chain
+// tabs before them change.
+func (t *tabBar) keepListTab(onOrders, onWatchlist bool) {
+	switch {
+	case onOrders || t.active >= len(t.tabs):
+		t.active = len(t.tabs) - 1
+	case onWatchlist:
+		t.active = len(t.tabs) - 2
+	}
+}
+
+// view renders the tab bar as a sliding marquee — the active tab is
+// pinned to the left with the next tabs visible to its right. Arrows indicate
+// more tabs off-screen.
+func (t tabBar) view(width int) string {
+	activeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#FFFFFF")).
+		Background(ui.ColorBoba).
+		Bold(true).
+		Padding(0, 2)
+
+	inactiveStyle := lipgloss.NewStyle().
+		Foreground(ui.ColorDim).
+		Padding(0, 2)
+
+	arrowStyle := lipgloss.NewStyle().Foreground(ui.ColorBoba).Bold(true)
+
+	// Tab widths (label + 4 padding chars)
+	tabWidths := make([]int, len(t.tabs))
+	totalWidth := 0
+	for i := range t.tabs {
+		tabWidths[i] = len(t.label(i)) + 4
+		totalWidth += tabWidths[i]
+	}
+
+	availWidth := width - 4
+	if availWidth < 1 {
+		availWidth = 1
+	}
+
+	sepLen := width - 4
+	if sepLen > 80 {
+		sepLen = 80
+	}
+	if sepLen < 0 {
+		sepLen = 0
+	}
+	border := lipgloss.NewStyle().Foreground(ui.ColorDim).Render("  " + strings.Repeat("━", sepLen))
+
+	if len(t.tabs) == 0 {
+		return "\n" + border
+	}
+	active := t.active
+	if active < 0 {
+		active = 0
+	}
+	if active >= len(t.tabs) {
+		active = len(t.tabs) - 1
+	}
+
+	// If everything fits, render all tabs normally
+	if totalWidth <= availWidth {
+		var tabs []string
+		for i := range t.tabs {
+			if i == t.active {
+				tabs = append(tabs, activeStyle.Render(t.label(i)))
+			} else {
+				tabs = append(tabs, inactiveStyle.Render(t.label(i)))
+			}
+		}
+		row := lipgloss.JoinHorizontal(lipgloss.Top, tabs...)
+		return "  " + row + "\n" + border
+	}
+
+	// Marquee: active tab pinned left, fill remaining width with tabs to the right
+	hasLeft := active > 0
+	leftArrow := "◀ "
+	rightArrow := " ▶"
+	arrowW := 3
+
+	// Reserve space for arrows
+	windowW := availWidth
+	if hasLeft {
+		windowW -= arrowW
+	}
+	windowW -= arrowW // always reserve right arrow space
+
+	// The active tab is always shown, truncated if the window is too narrow
+	// for it. Then fill rightward with whatever else fits.
+	activeLabel := t.label(active)
+	if tabWidths[active] > windowW {
+		activeLabel = ellipsize(activeLabel, windowW-4)
+	}
+	visible := []int{active}
+	usedWidth := tabWidths[active]
+	for i := active + 1; i < len(t.tabs); i++ {
+		if usedWidth+tabWidths[i] > windowW {
+			break
+		}
+		visible = append(visible, i)
+		usedWidth += tabWidths[i]
+	}
+
+	hasRight := visible[len(visible)-1] < len(t.tabs)-1
+
+	// Render
+	var row string
+	if hasLeft {
+		row += arrowStyle.Render(leftArrow)
+	}
+
+	var parts []string
+	for _, idx := range visible {
+		if idx == active {
+			parts = append(parts, activeStyle.Render(activeLabel))
+		} else {
+			parts = append(parts, inactiveStyle.Render(t.label(idx)))
+		}
+	}
+	row += lipgloss.JoinHorizontal(lipgloss.Top, parts...)
+
+	if hasRight {
+		row += arrowStyle.Render(rightArrow)
+	}
+
+	return "  " + row + "\n" + border
+}
+
+// ellipsize shortens s to at most max runes, ending in "…" when cut.
+func ellipsize(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	if max <= 1 {
+		return "…"
+	}
+	return string(r[:max-1]) + "…"
+}
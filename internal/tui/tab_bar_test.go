@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/zalando/go-keyring"
+)
+
+// useTempConfig keeps the test's config and keyring apart from the user's.
+func useTempConfig(t *testing.T) {
+	t.Helper()
+	keyring.MockInit()
+	t.Setenv("HOME", t.TempDir())
+	config.UseDir(t.TempDir())
+}
+
+// Only enabled chains get a tab, and a changed list shows on the next build.
+func TestTabBarChainFilter(t *testing.T) {
+	useTempConfig(t)
+	portfolio := &PortfolioData{
+		NativeBalances: []NativeBalance{{ChainName: "Ethereum"}, {ChainName: "Solana"}},
+		Positions:      []PortfolioPosition{{ChainName: "Base"}, {ChainName: "Solana"}},
+	}
+
+	tb := newTabBar()
+	tb.build(portfolio)
+	if want := []string{"All", "Solana", "Base", "Ethereum", watchlistTab, ordersTab}; !reflect.DeepEqual(tb.tabs, want) {
+		t.Errorf("all chains enabled: tabs %v, want %v", tb.tabs, want)
+	}
+
+	if err := config.SetEnabledChains([]string{"sol", "base"}); err != nil {
+		t.Fatal(err)
+	}
+	tb.build(portfolio)
+	if want := []string{"All", "Solana", "Base", watchlistTab, ordersTab}; !reflect.DeepEqual(tb.tabs, want) {
+		t.Errorf("Ethereum disabled: tabs %v, want %v", tb.tabs, want)
+	}
+	if _, ok := tb.slugs["Ethereum"]; ok {
+		t.Error("disabled chain kept a slug")
+	}
+}
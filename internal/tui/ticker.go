@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// trendingPollInterval is how often the ticker refreshes while shown.
+const trendingPollInterval = 5 * time.Minute
+
+// trendingMax is how many trending tokens the ticker cycles through.
+const trendingMax = 10
+
+// tickerRotateTicks is how many heartbeats each token leads the strip.
+const tickerRotateTicks = 3
+
+// trendingToken is one token on the ticker.
+type trendingToken struct {
+	symbol string
+	price  float64
+	change float64 // 24h change in percent
+}
+
+// tickerStrip is the optional one-line market pulse under the stats bar,
+// cycling through the trending tokens. It is off by default so an idle
+// proxy makes no extra backend calls, and is only fetched while shown.
+type tickerStrip struct {
+	visible bool
+	tokens  []trendingToken // nil while unknown or when the last fetch failed
+	fetched time.Time
+	loading bool
+	// polling is set while a refresh is scheduled, so toggling the strip
+	// doesn't start a second poll loop.
+	polling bool
+	offset  int // index of the token shown first
+}
+
+func fetchTrending(server *proxy.ProxyServer) tea.Cmd {
+	return func() tea.Msg {
+		body, err := server.CallTool(context.Background(), "get_trending_tokens", map[string]any{})
+		if err != nil {
+			return TrendingMsg{Err: err}
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return TrendingMsg{Err: err}
+		}
+		list, _ := raw["tokens"].([]any)
+		if list == nil {
+			list, _ = raw["results"].([]any)
+		}
+		var tokens []trendingToken
+		for _, t := range list {
+			token, ok := t.(map[string]any)
+			if !ok {
+				continue
+			}
+			symbol, _ := token["symbol"].(string)
+			if symbol == "" {
+				continue
+			}
+			price := parseFloat(token, "price_usd")
+			if price == 0 {
+				price = parseFloat(token, "price")
+			}
+			change := parseFloat(token, "price_change_24h")
+			if change == 0 {
+				change = parseFloat(token, "change_24h")
+			}
+			tokens = append(tokens, trendingToken{symbol: symbol, price: price, change: change})
+			if len(tokens) == trendingMax {
+				break
+			}
+		}
+		return TrendingMsg{Tokens: tokens}
+	}
+}
+
+func pollTrending() tea.Cmd {
+	return tea.Tick(trendingPollInterval, func(_ time.Time) tea.Msg { return TrendingPollMsg{} })
+}
+
+// receive records a fetch. A failed fetch hides the strip until the next
+// one succeeds.
+func (t *tickerStrip) receive(msg TrendingMsg) {
+	t.loading = false
+	t.fetched = time.Now()
+	t.tokens = msg.Tokens
+	if msg.Err != nil {
+		t.tokens = nil
+	}
+	if t.offset >= len(t.tokens) {
+		t.offset = 0
+	}
+}
+
+// due reports whether the tokens are missing or older than the poll
+// interval.
+func (t tickerStrip) due() bool {
+	return t.fetched.IsZero() || time.Since(t.fetched) >= trendingPollInterval
+}
+
+// rotate moves the next token to the front every few heartbeats.
+func (t *tickerStrip) rotate(idleFrame int) {
+	if len(t.tokens) > 0 && idleFrame%tickerRotateTicks == 0 {
+		t.offset = (t.offset + 1) % len(t.tokens)
+	}
+}
+
+// height is the lines the strip takes: one while it has tokens to show.
+func (t tickerStrip) height() int {
+	if !t.visible || len(t.tokens) == 0 {
+		return 0
+	}
+	return 1
+}
+
+// view renders as many tokens as fit the width, starting at the current
+// offset.
+func (t tickerStrip) view(width int) string {
+	if t.height() == 0 {
+		return ""
+	}
+	label := lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render("TRENDING")
+	symbolStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
+	sep := ui.DimStyle.Render("  ·  ")
+
+	line := "  " + label + "  "
+	room := width - lipgloss.Width(line)
+	var items []string
+	used := 0
+	for i := range t.tokens {
+		tok := t.tokens[(t.offset+i)%len(t.tokens)]
+		item := symbolStyle.Render(tok.symbol) + " " +
+			formatter.DisplayCurrency().Compact(tok.price) + " " +
+			formatter.FormatPercent(tok.change)
+		w := lipgloss.Width(item)
+		if len(items) > 0 {
+			w += lipgloss.Width(sep)
+		}
+		// Always show one token, even if it has to be cut short.
+		if len(items) > 0 && used+w > room {
+			break
+		}
+		items = append(items, item)
+		used += w
+	}
+	return lipgloss.NewStyle().MaxWidth(max(width, 1)).Render(line + strings.Join(items, sep))
+}
@@ -0,0 +1,211 @@
+package tui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+type toolTag struct {
+	label string
+	color *lipgloss.Color // read when rendered, so it follows the theme
+}
+
+var toolCategoryMap = map[string]toolTag{
+	// Trading
+	"get_swap_price":     {label: "TRADE", color: &ui.ColorTrading},
+	"get_swap_quote":     {label: "TRADE", color: &ui.ColorTrading},
+	"execute_swap":       {label: "TRADE", color: &ui.ColorTrading},
+	"execute_trade":      {label: "TRADE", color: &ui.ColorTrading},
+	"get_agent_balances": {label: "TRADE", color: &ui.ColorTrading},
+	// Portfolio
+	"get_portfolio":               {label: "FOLIO", color: &ui.ColorPortfolio},
+	"get_portfolio_summary":       {label: "FOLIO", color: &ui.ColorPortfolio},
+	"get_portfolio_pnl":           {label: "FOLIO", color: &ui.ColorPortfolio},
+	"get_trade_history":           {label: "FOLIO", color: &ui.ColorPortfolio},
+	"get_pnl_chart":               {label: "FOLIO", color: &ui.ColorPortfolio},
+	"get_user_xp":                 {label: "FOLIO", color: &ui.ColorPortfolio},
+	"start_portfolio_stream":      {label: "FOLIO", color: &ui.ColorPortfolio},
+	"get_portfolio_price_updates": {label: "FOLIO", color: &ui.ColorPortfolio},
+	"stop_portfolio_stream":       {label: "FOLIO", color: &ui.ColorPortfolio},
+	// Token
+	"get_token_info":         {label: "TOKEN", color: &ui.ColorTokenInfo},
+	"get_token_details":      {label: "TOKEN", color: &ui.ColorTokenInfo},
+	"search_tokens":          {label: "TOKEN", color: &ui.ColorTokenInfo},
+	"get_tokens_by_category": {label: "TOKEN", color: &ui.ColorTokenInfo},
+	"get_trending_tokens":    {label: "TOKEN", color: &ui.ColorTokenInfo},
+	"get_token_chart":        {label: "TOKEN", color: &ui.ColorTokenInfo},
+	"get_token_ohlc":         {label: "TOKEN", color: &ui.ColorTokenInfo},
+	"get_ohlc":               {label: "TOKEN", color: &ui.ColorTokenInfo},
+	"get_price_chart":        {label: "TOKEN", color: &ui.ColorTokenInfo},
+	"search_token_by_slug":   {label: "TOKEN", color: &ui.ColorTokenInfo},
+	"get_token_price":        {label: "TOKEN", color: &ui.ColorTokenInfo},
+	"get_category_tokens":    {label: "TOKEN", color: &ui.ColorTokenInfo},
+	// Wallet
+	"get_wallet_balance":      {label: "WALLET", color: &ui.ColorWallet},
+	"get_transfers":           {label: "WALLET", color: &ui.ColorWallet},
+	"refresh_native_balances": {label: "WALLET", color: &ui.ColorWallet},
+	// Brewing
+	"get_brewing_status":  {label: "BREW", color: &ui.ColorBrewing},
+	"get_recent_launches": {label: "BREW", color: &ui.ColorBrewing},
+	"get_brewing_tokens":  {label: "BREW", color: &ui.ColorBrewing},
+	"get_launch_feed":     {label: "BREW", color: &ui.ColorBrewing},
+	// Security
+	"audit_token":        {label: "AUDIT", color: &ui.ColorSecurity},
+	"audit_tokens_batch": {label: "AUDIT", color: &ui.ColorSecurity},
+	"is_token_verified":  {label: "AUDIT", color: &ui.ColorSecurity},
+	// Orders
+	"create_limit_order": {label: "ORDER", color: &ui.ColorOrders},
+	"get_limit_orders":   {label: "ORDER", color: &ui.ColorOrders},
+	"get_limit_order":    {label: "ORDER", color: &ui.ColorOrders},
+	"update_limit_order": {label: "ORDER", color: &ui.ColorOrders},
+	"cancel_limit_order": {label: "ORDER", color: &ui.ColorOrders},
+	"create_dca_order":   {label: "ORDER", color: &ui.ColorOrders},
+	"get_dca_orders":     {label: "ORDER", color: &ui.ColorOrders},
+	"get_dca_order":      {label: "ORDER", color: &ui.ColorOrders},
+	"pause_dca_order":    {label: "ORDER", color: &ui.ColorOrders},
+	"resume_dca_order":   {label: "ORDER", color: &ui.ColorOrders},
+	"cancel_dca_order":   {label: "ORDER", color: &ui.ColorOrders},
+	"create_twap_order":  {label: "ORDER", color: &ui.ColorOrders},
+	"get_twap_orders":    {label: "ORDER", color: &ui.ColorOrders},
+	"get_twap_order":     {label: "ORDER", color: &ui.ColorOrders},
+	"pause_twap_order":   {label: "ORDER", color: &ui.ColorOrders},
+	"resume_twap_order":  {label: "ORDER", color: &ui.ColorOrders},
+	"cancel_twap_order":  {label: "ORDER", color: &ui.ColorOrders},
+	"get_positions":      {label: "ORDER", color: &ui.ColorOrders},
+	"get_position":       {label: "ORDER", color: &ui.ColorOrders},
+	// Analytics
+	"get_deployer_tokens":   {label: "STATS", color: &ui.ColorAnalytics},
+	"get_deployer_activity": {label: "STATS", color: &ui.ColorAnalytics},
+	"get_network_volume":    {label: "STATS", color: &ui.ColorAnalytics},
+	"get_network_stats":     {label: "STATS", color: &ui.ColorAnalytics},
+	"search_wallets":        {label: "STATS", color: &ui.ColorAnalytics},
+	"get_wallet_stats":      {label: "STATS", color: &ui.ColorAnalytics},
+	"get_maker_trades":      {label: "STATS", color: &ui.ColorAnalytics},
+	"get_holders":           {label: "STATS", color: &ui.ColorAnalytics},
+	// Tracking
+	"get_live_swaps":             {label: "TRACK", color: &ui.ColorTracking},
+	"get_user_swaps":             {label: "TRACK", color: &ui.ColorTracking},
+	"get_watchlist":              {label: "TRACK", color: &ui.ColorTracking},
+	"add_to_watchlist":           {label: "TRACK", color: &ui.ColorTracking},
+	"remove_from_watchlist":      {label: "TRACK", color: &ui.ColorTracking},
+	"get_kol_wallets":            {label: "TRACK", color: &ui.ColorTracking},
+	"get_kol_swaps":              {label: "TRACK", color: &ui.ColorTracking},
+	"get_kol_info":               {label: "TRACK", color: &ui.ColorTracking},
+	"check_if_kol":               {label: "TRACK", color: &ui.ColorTracking},
+	"get_deployer_history":       {label: "TRACK", color: &ui.ColorTracking},
+	"track_deployer":             {label: "TRACK", color: &ui.ColorTracking},
+	"stop_tracking_deployer":     {label: "TRACK", color: &ui.ColorTracking},
+	"add_wallet_to_tracker":      {label: "TRACK", color: &ui.ColorTracking},
+	"get_tracked_wallets":        {label: "TRACK", color: &ui.ColorTracking},
+	"remove_wallet_from_tracker": {label: "TRACK", color: &ui.ColorTracking},
+	// Streaming
+	"stream_launches":        {label: "STREAM", color: &ui.ColorStreaming},
+	"stream_kol_swaps":       {label: "STREAM", color: &ui.ColorStreaming},
+	"stream_wallet_swaps":    {label: "STREAM", color: &ui.ColorStreaming},
+	"stream_watchlist_swaps": {label: "STREAM", color: &ui.ColorStreaming},
+	"get_streaming_status":   {label: "STREAM", color: &ui.ColorStreaming},
+	// Price alerts raised by the proxy itself
+	"price_alert": {label: "PRICE", color: &ui.ColorGold},
+	// Problems reported by the boba mcp stdio bridge
+	"bridge": {label: "BRIDGE", color: &ui.ColorRed},
+}
+
+var defaultTag = toolTag{label: "TOOL", color: &ui.ColorBoba}
+
+func getToolTag(tool string) toolTag {
+	if t, ok := toolCategoryMap[tool]; ok {
+		return t
+	}
+	return defaultTag
+}
+
+// toolDescriptions maps tool names to human-readable descriptions shown while
+// a request is pending.
+var toolDescriptions = map[string]string{
+	// Trading
+	"get_swap_price":     "Getting swap quote...",
+	"get_swap_quote":     "Getting swap quote...",
+	"execute_swap":       "Executing trade...",
+	"execute_trade":      "Executing trade...",
+	"get_agent_balances": "Fetching balances...",
+	// Portfolio
+	"get_portfolio":         "Fetching portfolio...",
+	"get_portfolio_summary": "Fetching portfolio...",
+	"get_portfolio_pnl":     "Getting P&L data...",
+	"get_pnl_chart":         "Loading P&L chart...",
+	// Token
+	"get_token_info":         "Looking up token...",
+	"get_token_details":      "Looking up token...",
+	"search_tokens":          "Searching tokens...",
+	"get_tokens_by_category": "Searching tokens...",
+	"get_trending_tokens":    "Getting trending...",
+	"get_token_chart":        "Loading price chart...",
+	"get_token_ohlc":         "Loading price chart...",
+	"get_ohlc":               "Loading price chart...",
+	"get_price_chart":        "Loading price chart...",
+	"search_token_by_slug":   "Searching tokens...",
+	"get_token_price":        "Getting token price...",
+	"get_category_tokens":    "Searching by category...",
+	// Wallet
+	"get_wallet_balance": "Checking wallet...",
+	// Brewing
+	"get_brewing_tokens":  "Getting brewing tokens...",
+	"get_brewing_status":  "Checking new launches...",
+	"get_recent_launches": "Getting recent launches...",
+	"get_launch_feed":     "Loading launch feed...",
+	// Security
+	"audit_token":        "Auditing token...",
+	"audit_tokens_batch": "Auditing tokens...",
+	"is_token_verified":  "Checking verification...",
+	// Orders
+	"create_limit_order": "Creating limit order...",
+	"get_limit_orders":   "Getting limit orders...",
+	"get_limit_order":    "Getting order detail...",
+	"update_limit_order": "Updating order...",
+	"cancel_limit_order": "Cancelling order...",
+	"create_dca_order":   "Creating DCA order...",
+	"get_dca_orders":     "Getting DCA orders...",
+	"get_dca_order":      "Getting DCA detail...",
+	"pause_dca_order":    "Pausing DCA...",
+	"resume_dca_order":   "Resuming DCA...",
+	"cancel_dca_order":   "Cancelling DCA...",
+	"create_twap_order":  "Creating TWAP order...",
+	"get_twap_orders":    "Getting TWAP orders...",
+	"get_twap_order":     "Getting TWAP detail...",
+	"pause_twap_order":   "Pausing TWAP...",
+	"resume_twap_order":  "Resuming TWAP...",
+	"cancel_twap_order":  "Cancelling TWAP...",
+	"get_positions":      "Getting positions...",
+	"get_position":       "Getting position...",
+	// Analytics
+	"get_deployer_tokens":   "Getting deployer tokens...",
+	"get_deployer_activity": "Getting dev activity...",
+	"get_network_volume":    "Getting network volume...",
+	"get_network_stats":     "Getting network stats...",
+	"search_wallets":        "Searching wallets...",
+	"get_wallet_stats":      "Getting wallet stats...",
+	"get_maker_trades":      "Getting maker trades...",
+	"get_holders":           "Getting holders...",
+	// Tracking
+	"get_live_swaps":             "Getting live swaps...",
+	"get_user_swaps":             "Getting user swaps...",
+	"get_watchlist":              "Getting watchlist...",
+	"add_to_watchlist":           "Adding to watchlist...",
+	"remove_from_watchlist":      "Removing from watchlist...",
+	"get_kol_wallets":            "Getting KOL wallets...",
+	"get_kol_swaps":              "Getting KOL swaps...",
+	"get_kol_info":               "Getting KOL info...",
+	"check_if_kol":               "Checking KOL...",
+	"get_deployer_history":       "Getting dev history...",
+	"track_deployer":             "Starting tracker...",
+	"stop_tracking_deployer":     "Stopping tracker...",
+	"add_wallet_to_tracker":      "Adding wallet...",
+	"get_tracked_wallets":        "Getting tracked wallets...",
+	"remove_wallet_from_tracker": "Removing wallet...",
+	// Streaming
+	"stream_launches":        "Streaming launches...",
+	"stream_kol_swaps":       "Streaming KOL swaps...",
+	"stream_wallet_swaps":    "Streaming wallet...",
+	"stream_watchlist_swaps": "Streaming watchlist...",
+	"get_streaming_status":   "Checking streams...",
+}
@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+	"github.com/tradeboba/boba-cli/internal/watchlist"
+)
+
+// watchlistPollInterval is how often the Watchlist tab refreshes while open.
+const watchlistPollInterval = 60 * time.Second
+
+// watchlistShown is how many tokens the panel lists before summing up the
+// rest.
+const watchlistShown = 10
+
+// watchlistPanel lists the watched tokens with their prices on the
+// Watchlist tab. It is only fetched and polled while the tab is open.
+type watchlistPanel struct {
+	list    []watchlist.Token
+	fetched time.Time
+	err     string // why the last refresh failed, if it did
+	loading bool
+	// polling is set while a poll is scheduled, so reopening the tab
+	// doesn't start a second poll loop.
+	polling bool
+	// dirty is set when a token was added from the dashboard, so the list
+	// is refetched the next time it is shown.
+	dirty bool
+}
+
+func fetchWatchlist(server *proxy.ProxyServer) tea.Cmd {
+	return func() tea.Msg {
+		ctx := context.Background()
+		list, err := watchlist.List(ctx, server.CallTool)
+		if err == nil {
+			watchlist.FillPrices(ctx, server.CallTool, list)
+		}
+		return WatchlistMsg{Tokens: list, Err: err}
+	}
+}
+
+// addToWatchlist adds the token of pos to the watchlist through the proxy
+// and records the action in the activity log.
+func addToWatchlist(server *proxy.ProxyServer, pos PortfolioPosition) tea.Cmd {
+	return func() tea.Msg {
+		chain := strings.ToLower(pos.ChainName)
+		if c, ok := config.LookupChain(pos.ChainName); ok {
+			chain = c.Slug
+		}
+		start := time.Now()
+		err := watchlist.Add(context.Background(), server.CallTool, pos.TokenAddress, chain)
+		entry := proxy.LogEntry{
+			Tool:     "add_to_watchlist",
+			Status:   "success",
+			Duration: time.Since(start),
+			Preview:  fmt.Sprintf("Added %s to the watchlist from the dashboard", pos.Symbol),
+			Args:     map[string]any{"token_address": pos.TokenAddress, "chain": chain},
+		}
+		if err != nil {
+			entry.Status = "error"
+			entry.Error = err.Error()
+		}
+		server.Log(entry)
+		return WatchAddedMsg{Symbol: pos.Symbol, Err: err}
+	}
+}
+
+func pollWatchlist() tea.Cmd {
+	return tea.Tick(watchlistPollInterval, func(_ time.Time) tea.Msg { return WatchlistPollMsg{} })
+}
+
+// receive stores a fetch result. A failed refresh keeps the tokens already
+// on screen.
+func (p *watchlistPanel) receive(msg WatchlistMsg) {
+	p.loading = false
+	if msg.Err != nil {
+		p.err = msg.Err.Error()
+		return
+	}
+	p.err = ""
+	p.dirty = false
+	p.fetched = time.Now()
+	p.list = msg.Tokens
+}
+
+// stale reports whether the watchlist is due for a refresh.
+func (p watchlistPanel) stale() bool {
+	return p.dirty || time.Since(p.fetched) >= watchlistPollInterval
+}
+
+// height returns the number of terminal lines the panel occupies.
+func (p watchlistPanel) height(width int) int {
+	return lipgloss.Height(p.view(renderCtx{}, width))
+}
+
+func (p watchlistPanel) view(rc renderCtx, width int) string {
+	dimStyle := lipgloss.NewStyle().Foreground(ui.ColorDim)
+	titleStyle := lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true)
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ui.ColorGold).
+		Padding(0, 2)
+
+	// Loading and error states, as on the portfolio panel.
+	if p.fetched.IsZero() {
+		if p.err != "" {
+			errMsg := dimStyle.Italic(true).Render("  Watchlist unavailable")
+			return box.BorderForeground(ui.ColorDim).Render(errMsg)
+		}
+		return box.Render(dimStyle.Italic(true).Render("  " + rc.spinner + " Loading watchlist..."))
+	}
+
+	if formatter.Accessible {
+		return p.viewAccessible(rc)
+	}
+
+	badge := Freshness{FetchedAt: p.fetched, Interval: watchlistPollInterval, Failed: p.err != ""}.Badge(rc.now)
+	header := fmt.Sprintf("  %s  %s  %s", titleStyle.Render("WATCHLIST"), dimStyle.Render(fmt.Sprintf("%d", len(p.list))), badge)
+	lines := []string{header, ""}
+
+	if len(p.list) == 0 {
+		lines = append(lines, dimStyle.Render("  No tokens on the watchlist · w on a position or boba watch add"))
+		return box.Render(strings.Join(lines, "\n"))
+	}
+
+	symStyle := lipgloss.NewStyle().Foreground(ui.ColorBright).Bold(true)
+	goldStyle := lipgloss.NewStyle().Foreground(ui.ColorGold)
+	shown := p.list
+	if len(shown) > watchlistShown {
+		shown = shown[:watchlistShown]
+	}
+	cells := make([][4]string, len(shown))
+	var widths [4]int
+	for i, t := range shown {
+		cells[i][0] = symStyle.Render(t.Label())
+		cells[i][1] = dimStyle.Render("—")
+		if t.Price > 0 {
+			cells[i][1] = goldStyle.Render(formatter.DisplayCurrency().Compact(t.Price))
+		}
+		cells[i][2] = dimStyle.Render("—")
+		if t.HasChange {
+			cells[i][2] = formatter.FormatPercent(t.Change)
+		}
+		cells[i][3] = dimStyle.Render(t.Chain)
+		for c := range widths {
+			widths[c] = max(widths[c], lipgloss.Width(cells[i][c]))
+		}
+	}
+	for _, row := range cells {
+		lines = append(lines, strings.TrimRight(fmt.Sprintf("  %s  %s  %s  %s",
+			padRight(row[0], widths[0]),
+			padLeft(row[1], widths[1]),
+			padLeft(row[2], widths[2]),
+			row[3]), " "))
+	}
+	if more := len(p.list) - len(shown); more > 0 {
+		lines = append(lines, dimStyle.Render(fmt.Sprintf("  +%d more · boba watch list", more)))
+	}
+	if p.err != "" {
+		lines = append(lines, lipgloss.NewStyle().Foreground(ui.ColorRed).Render("  Refresh failed: "+ellipsize(p.err, max(width-30, 20))))
+	}
+	return box.Render(strings.Join(lines, "\n"))
+}
+
+// viewAccessible renders the watchlist as plain sentences, like the
+// portfolio panel's accessible view.
+func (p watchlistPanel) viewAccessible(rc renderCtx) string {
+	header := fmt.Sprintf("Watchlist: %d tokens", len(p.list))
+	if age := (Freshness{FetchedAt: p.fetched, Interval: watchlistPollInterval}).Describe(rc.now); age != "" {
+		header += ", " + age
+	}
+	if p.err != "" {
+		header += ", refresh failed"
+	}
+	lines := []string{header, ""}
+	if len(p.list) == 0 {
+		lines = append(lines, "No tokens on the watchlist")
+	}
+	shown := p.list
+	if len(shown) > watchlistShown {
+		shown = shown[:watchlistShown]
+	}
+	for _, t := range shown {
+		line := t.Label()
+		if t.Chain != "" {
+			line += " on " + t.Chain
+		}
+		if t.Price > 0 {
+			line += ": price " + formatter.DisplayCurrency().Compact(t.Price)
+		} else {
+			line += ": price unknown"
+		}
+		if t.HasChange {
+			line += ", 24h " + formatter.FormatPercent(t.Change)
+		}
+		lines = append(lines, line)
+	}
+	if more := len(p.list) - len(shown); more > 0 {
+		lines = append(lines, fmt.Sprintf("%d more tokens", more))
+	}
+	return lipgloss.NewStyle().
+		Padding(1, 2).
+		Render(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,75 @@
+package tui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/tradeboba/boba-cli/internal/formatter"
+	"github.com/tradeboba/boba-cli/internal/proxy"
+	"github.com/tradeboba/boba-cli/internal/ui"
+)
+
+// xpPollInterval is how often the XP badge refreshes. XP moves slowly, so
+// this is much less often than the panels.
+const xpPollInterval = 10 * time.Minute
+
+// xpBadgeCells is how many cells the badge's progress meter has.
+const xpBadgeCells = 4
+
+// fetchXP asks the backend for the user's level and XP. A failed call or
+// an unrecognized response comes back as XPMsg{OK: false}.
+func fetchXP(server *proxy.ProxyServer) tea.Cmd {
+	return func() tea.Msg {
+		body, err := server.CallTool(context.Background(), "get_user_xp", map[string]any{"user_id": "me"})
+		if err != nil {
+			return XPMsg{}
+		}
+		var raw map[string]any
+		if err := json.Unmarshal(body, &raw); err != nil {
+			return XPMsg{}
+		}
+		xp, ok := formatter.ParseUserXP(raw)
+		return XPMsg{XP: xp, OK: ok}
+	}
+}
+
+func pollXP() tea.Cmd {
+	return tea.Tick(xpPollInterval, func(_ time.Time) tea.Msg { return XPPollMsg{} })
+}
+
+// xpBadge renders the stats bar badge, e.g. "LVL 12 ▰▰▰▱ 340/500". The
+// meter and count are left out when the next level's XP isn't known.
+func xpBadge(xp formatter.UserXP) string {
+	var parts []string
+	if xp.HasLevel {
+		parts = append(parts, fmt.Sprintf("LVL %d", xp.Level))
+	}
+	if xp.Needed <= 0 {
+		parts = append(parts, formatter.FormatXP(xp.XP)+" XP")
+		return lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render(strings.Join(parts, " "))
+	}
+
+	count := fmt.Sprintf("%s/%s", formatter.FormatXP(xp.Progress), formatter.FormatXP(xp.Needed))
+	if formatter.Accessible {
+		parts = append(parts, count+" XP")
+		return strings.Join(parts, " ")
+	}
+	full, empty := "▰", "▱"
+	if formatter.Plain {
+		full, empty = "#", "-"
+	}
+	filled := int(math.Round(math.Min(1, xp.Progress/xp.Needed) * xpBadgeCells))
+	meter := strings.Repeat(full, filled) + strings.Repeat(empty, xpBadgeCells-filled)
+	badge := lipgloss.NewStyle().Foreground(ui.ColorGold).Render(meter) + " " +
+		lipgloss.NewStyle().Foreground(ui.ColorDim).Render(count)
+	if len(parts) == 0 {
+		return badge
+	}
+	return lipgloss.NewStyle().Foreground(ui.ColorGold).Bold(true).Render(strings.Join(parts, " ")) + " " + badge
+}
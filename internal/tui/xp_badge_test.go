@@ -0,0 +1,55 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/formatter"
+)
+
+func TestXPBadge(t *testing.T) {
+	for _, tc := range []struct {
+		xp   formatter.UserXP
+		want string
+	}{
+		{formatter.UserXP{Level: 12, HasLevel: true, XP: 1340, Progress: 340, Needed: 500}, "LVL 12 ▰▰▰▱ 340/500"},
+		{formatter.UserXP{Level: 1, HasLevel: true, Progress: 0, Needed: 100}, "LVL 1 ▱▱▱▱ 0/100"},
+		{formatter.UserXP{XP: 2000, Progress: 900, Needed: 500}, "▰▰▰▰ 900/500"},
+		{formatter.UserXP{Level: 3, HasLevel: true, XP: 1250}, "LVL 3 1,250 XP"},
+	} {
+		if got := xpBadge(tc.xp); got != tc.want {
+			t.Errorf("xpBadge(%+v) = %q, want %q", tc.xp, got, tc.want)
+		}
+	}
+
+	formatter.Plain = true
+	defer func() { formatter.Plain = false }()
+	if got := xpBadge(formatter.UserXP{Level: 12, HasLevel: true, Progress: 340, Needed: 500}); got != "LVL 12 ###- 340/500" {
+		t.Errorf("plain badge = %q", got)
+	}
+}
+
+// The badge shows once the XP arrives and disappears when a refresh fails;
+// either way the next refresh is scheduled.
+func TestStatsBarXP(t *testing.T) {
+	m := runningModel(t)
+	rc := renderCtx{now: time.Now(), width: 160}
+	model, cmd := m.Update(XPMsg{XP: formatter.UserXP{Level: 12, HasLevel: true, Progress: 340, Needed: 500}, OK: true})
+	m = model.(ProxyViewModel)
+	if cmd == nil {
+		t.Error("no refresh scheduled")
+	}
+	if bar := m.stats.view(rc, m.server); !strings.Contains(bar, "LVL 12 ▰▰▰▱ 340/500") {
+		t.Errorf("stats bar lacks the badge: %q", bar)
+	}
+
+	model, cmd = m.Update(XPMsg{})
+	m = model.(ProxyViewModel)
+	if cmd == nil {
+		t.Error("no refresh scheduled after a failure")
+	}
+	if bar := m.stats.view(rc, m.server); strings.Contains(bar, "LVL") || strings.Contains(bar, " XP") {
+		t.Errorf("badge shown after a failed refresh: %q", bar)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package ui
+
+// enableVirtualTerminal is a no-op outside Windows, where terminals handle
+// ANSI escapes natively.
+func enableVirtualTerminal() bool { return true }
@@ -0,0 +1,31 @@
+//go:build windows
+
+package ui
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// enableVirtualTerminal turns on ANSI escape handling for the console behind
+// stdout and stderr. Windows Terminal has it on already; legacy conhost only
+// supports it once asked, and very old builds not at all. Output that isn't
+// a console (pipes, files) needs nothing and reports success.
+func enableVirtualTerminal() bool {
+	ok := true
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		h := windows.Handle(f.Fd())
+		var mode uint32
+		if err := windows.GetConsoleMode(h, &mode); err != nil {
+			continue
+		}
+		if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+			continue
+		}
+		if err := windows.SetConsoleMode(h, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+			ok = false
+		}
+	}
+	return ok
+}
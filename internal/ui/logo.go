@@ -1,7 +1,6 @@
 package ui
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -31,7 +30,7 @@ var logoGradient = []lipgloss.Color{
 
 // PrintLogo prints the BOBA AGENTS ASCII logo with a purple gradient.
 func PrintLogo() {
-	fmt.Println(RenderLogo())
+	Decor(RenderLogo())
 }
 
 // RenderLogo returns the colored logo as a string.
@@ -83,11 +83,13 @@ func (m *MatrixRain) Tick() {
 	}
 }
 
-// Render returns the current frame as a string with ANSI color codes.
+// Render returns the current frame as a string with ANSI color codes, or
+// plain characters in no-color mode.
 // Uses raw ANSI for performance (renders ~2400 chars per frame at 30fps).
 func (m *MatrixRain) Render() string {
 	var b strings.Builder
 	b.Grow(m.Width * m.Height * 8)
+	plain := NoColor()
 
 	for y := 0; y < m.Height; y++ {
 		for x := 0; x < m.Width; x++ {
@@ -101,6 +103,10 @@ func (m *MatrixRain) Render() string {
 
 			dist := c.pos - y
 			ch := c.chars[y]
+			if plain {
+				b.WriteByte(ch)
+				continue
+			}
 
 			switch {
 			case dist == 0:
@@ -0,0 +1,228 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Output discipline shared by every command. Decorative output (logos,
+// animations, cards, hints) is only shown on an interactive terminal and
+// never in quiet mode. Essential results always go to stdout; verbose step
+// timings go to stderr so they never pollute piped output.
+var (
+	quietMode      bool
+	verboseMode    bool
+	accessibleMode bool
+	slowMode       bool
+	noANSI         bool
+	noColor        bool
+
+	outputMu sync.Mutex
+	stdout   io.Writer = os.Stdout
+	stderr   io.Writer = os.Stderr
+
+	stdoutIsTTY = sync.OnceValue(func() bool {
+		return isTerminal(os.Stdout)
+	})
+)
+
+// SetQuiet enables or disables quiet mode.
+func SetQuiet(v bool) { quietMode = v }
+
+// SetVerbose enables or disables verbose step output.
+func SetVerbose(v bool) { verboseMode = v }
+
+// SetAccessible enables or disables screen-reader friendly output.
+func SetAccessible(v bool) { accessibleMode = v }
+
+// Accessible reports whether screen-reader friendly output is enabled.
+// Animations are skipped and boxes omitted in this mode.
+func Accessible() bool { return accessibleMode }
+
+// SetSlowTerminal enables or disables static rendering for slow links.
+func SetSlowTerminal(v bool) { slowMode = v }
+
+// SlowTerminal reports whether animations should be replaced with a single
+// static frame, as set in config or with BOBA_SLOW_TERMINAL=1. Over a
+// high-latency SSH link every animation frame is a full redraw the user has
+// to wait for before their keystrokes are echoed.
+func SlowTerminal() bool {
+	if v := os.Getenv("BOBA_SLOW_TERMINAL"); v != "" && v != "0" {
+		return true
+	}
+	return slowMode
+}
+
+// SetNoColor turns colors and text styling off for every lipgloss style, as
+// requested with --no-color. NO_COLOR (https://no-color.org) in the
+// environment does the same.
+func SetNoColor(v bool) {
+	noColor = v
+	if NoColor() {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+// NoColor reports whether output is plain text: no colors, no styling and no
+// escape codes at all, so it stays readable when captured to a file.
+func NoColor() bool {
+	return noColor || noANSI || os.Getenv("NO_COLOR") != ""
+}
+
+// InitConsole prepares the terminal for styled output. On Windows this
+// enables virtual terminal processing; when the console can't do it (legacy
+// conhost on old builds) escape codes would print as garbage, so styling is
+// turned off, decorations fall back to plain output and the user is pointed
+// at Windows Terminal.
+func InitConsole() {
+	if enableVirtualTerminal() {
+		return
+	}
+	noANSI = true
+	lipgloss.SetColorProfile(termenv.Ascii)
+	Errorln("warning: this console can't display colors or the dashboard; using plain output.\n" +
+		"         Windows Terminal (https://aka.ms/terminal) supports the full interface.")
+}
+
+// ANSI reports whether the terminal understands escape codes. When false,
+// full-screen views must not start.
+func ANSI() bool { return !noANSI }
+
+// StdoutIsTerminal reports whether stdout is a terminal.
+func StdoutIsTerminal() bool { return stdoutIsTTY() }
+
+// Hyperlinks reports whether addresses should be printed as clickable
+// links: on decorated, colored output to a terminal that isn't dumb.
+// BOBA_HYPERLINKS=0 turns them off for terminals that print the escape
+// codes, and BOBA_HYPERLINKS=1 forces them on.
+func Hyperlinks() bool {
+	switch os.Getenv("BOBA_HYPERLINKS") {
+	case "0":
+		return false
+	case "1":
+		return true
+	}
+	return Decorate() && !NoColor() && os.Getenv("TERM") != "dumb"
+}
+
+// Quiet reports whether quiet mode was requested explicitly.
+func Quiet() bool { return quietMode }
+
+// Verbose reports whether verbose step output is enabled.
+func Verbose() bool { return verboseMode }
+
+// Decorate reports whether decorative output should be rendered. It is false
+// in quiet or accessible mode, whenever stdout is not a terminal and on
+// consoles without ANSI support.
+func Decorate() bool {
+	return !quietMode && !accessibleMode && !noANSI && stdoutIsTTY()
+}
+
+// Animate reports whether multi-frame animations and spinners should run.
+// Verbose mode prints step timings instead, and slow terminals get the
+// same plain step output rather than a redraw per frame.
+func Animate() bool {
+	return Decorate() && !verboseMode && !SlowTerminal()
+}
+
+// SetOutput redirects stdout and stderr writes made through this package.
+func SetOutput(out, errOut io.Writer) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	stdout = out
+	stderr = errOut
+}
+
+// Println writes essential output to stdout regardless of mode.
+func Println(a ...any) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	fmt.Fprintln(stdout, a...)
+}
+
+// Printf writes essential formatted output to stdout regardless of mode.
+func Printf(format string, a ...any) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	fmt.Fprintf(stdout, format, a...)
+}
+
+// Field writes a plain "key: value" line used by quiet and non-TTY output.
+func Field(key, value string) {
+	Printf("%s: %s\n", key, value)
+}
+
+// Decor writes decorative output to stdout only when decoration is enabled.
+func Decor(a ...any) {
+	if !Decorate() {
+		return
+	}
+	Println(a...)
+}
+
+// Errorln writes to stderr regardless of mode.
+func Errorln(a ...any) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	fmt.Fprintln(stderr, a...)
+}
+
+// Verbosef writes a diagnostic line to stderr when verbose mode is enabled.
+func Verbosef(format string, a ...any) {
+	if !verboseMode {
+		return
+	}
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	fmt.Fprintf(stderr, format+"\n", a...)
+}
+
+// Step runs fn without any animation. In verbose mode it reports the step
+// and how long it took on stderr; otherwise it is silent.
+func Step(label string, fn func() error) error {
+	label = strings.TrimSuffix(label, "...")
+	Verbosef("→ %s", label)
+	start := time.Now()
+	err := fn()
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		Verbosef("✗ %s (%s): %v", label, elapsed, err)
+		return err
+	}
+	Verbosef("✓ %s (%s)", label, elapsed)
+	return nil
+}
+
+// ReducedMotion reports whether the environment or OS asks for reduced
+// motion, which is used to suggest accessible mode. It is checked once, since
+// on macOS that runs `defaults`.
+func ReducedMotion() bool { return reducedMotion() }
+
+var reducedMotion = sync.OnceValue(func() bool {
+	if os.Getenv("BOBA_REDUCED_MOTION") != "" || os.Getenv("REDUCE_MOTION") != "" {
+		return true
+	}
+	if runtime.GOOS == "darwin" {
+		out, err := exec.Command("defaults", "read", "com.apple.universalaccess", "reduceMotion").Output()
+		return err == nil && strings.TrimSpace(string(out)) == "1"
+	}
+	return false
+})
+
+// isTerminal reports whether f is attached to a character device.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Palette is a set of colors the Color variables and styles are drawn
+// from. An empty color leaves the terminal's own color in place.
+type Palette struct {
+	Boba, Dim, Bright, Gold, Red, Green, Cyan, Pearl, Brown lipgloss.Color
+	// Pending marks steps that haven't run yet; Warning is WarningStyle's.
+	Pending, Warning lipgloss.Color
+	// TagText is the text on the tool category badges, and the category
+	// colors their backgrounds.
+	TagText                                          lipgloss.Color
+	Trading, Portfolio, TokenInfo, Wallet, Brewing   lipgloss.Color
+	Security, Orders, Analytics, Tracking, Streaming lipgloss.Color
+}
+
+// ColorPending marks steps that haven't run yet, and ColorTagText is the
+// text on tool category badges.
+var (
+	ColorPending = DarkPalette.Pending
+	ColorTagText = DarkPalette.TagText
+)
+
+// DarkPalette is the default look, made for dark backgrounds.
+var DarkPalette = Palette{
+	Boba: "#B184F5", Dim: "#8A5FD1", Bright: "#D4A5FF", Gold: "#FFD700",
+	Red: "#FF6B6B", Green: "#50FA7B", Cyan: "#00CED1", Pearl: "#F5F5DC", Brown: "#8B4513",
+	Pending: "#333333", Warning: "#FFE66D", TagText: "#1a1a2e",
+	Trading: "#4ECDC4", Portfolio: "#9B59B6", TokenInfo: "#F39C12", Wallet: "#3498DB",
+	Brewing: "#E74C3C", Security: "#E67E22", Orders: "#1ABC9C", Analytics: "#2ECC71",
+	Tracking: "#E84393", Streaming: "#0984E3",
+}
+
+// LightPalette darkens the text colors so they read on light backgrounds.
+// The category badges keep their colors, since they carry their own
+// background.
+var LightPalette = Palette{
+	Boba: "#7B2FBE", Dim: "#6C3483", Bright: "#4A235A", Gold: "#B8860B",
+	Red: "#C0392B", Green: "#1E8449", Cyan: "#00838F", Pearl: "#3E2723", Brown: "#8B4513",
+	Pending: "#A0A0A0", Warning: "#9A7D0A", TagText: "#1a1a2e",
+	Trading: "#4ECDC4", Portfolio: "#9B59B6", TokenInfo: "#F39C12", Wallet: "#3498DB",
+	Brewing: "#E74C3C", Security: "#E67E22", Orders: "#1ABC9C", Analytics: "#2ECC71",
+	Tracking: "#E84393", Streaming: "#0984E3",
+}
+
+// BasicPalette uses the 16 standard ANSI colors, for terminals that can't
+// show more. Mapped down from the dark palette, most of the purples would
+// land on the same color.
+var BasicPalette = Palette{
+	Boba: "5", Dim: "4", Bright: "13", Gold: "3",
+	Red: "1", Green: "2", Cyan: "6", Pearl: "7", Brown: "3",
+	Pending: "8", Warning: "11", TagText: "0",
+	Trading: "6", Portfolio: "5", TokenInfo: "3", Wallet: "4",
+	Brewing: "1", Security: "3", Orders: "6", Analytics: "2",
+	Tracking: "5", Streaming: "4",
+}
+
+// MonoPalette has no colors at all; bold, italics and reversed badges
+// still set things apart.
+var MonoPalette = Palette{}
+
+// SetTheme switches the colors to the named theme. auto, and any name it
+// doesn't know, uses the dark palette unless the terminal has a light
+// background or only 16 colors. It must be called before anything is
+// rendered, as the Color variables and styles are read while rendering.
+func SetTheme(name string) {
+	switch name {
+	case "dark":
+		ApplyPalette(DarkPalette)
+	case "light":
+		ApplyPalette(LightPalette)
+	case "mono":
+		ApplyPalette(MonoPalette)
+	default:
+		ApplyPalette(autoPalette())
+	}
+}
+
+// autoPalette picks a palette for the terminal on stdout. The background
+// is only asked for on a terminal, since the query reads its answer from
+// the terminal.
+func autoPalette() Palette {
+	if NoColor() || !stdoutIsTTY() {
+		return DarkPalette
+	}
+	light := !lipgloss.HasDarkBackground()
+	switch {
+	case lipgloss.ColorProfile() == termenv.ANSI && !light:
+		return BasicPalette
+	case light:
+		return LightPalette
+	}
+	return DarkPalette
+}
+
+// ApplyPalette points the Color variables and the package styles at p.
+func ApplyPalette(p Palette) {
+	ColorBoba, ColorDim, ColorBright, ColorGold = p.Boba, p.Dim, p.Bright, p.Gold
+	ColorRed, ColorGreen, ColorCyan, ColorPearl, ColorBrown = p.Red, p.Green, p.Cyan, p.Pearl, p.Brown
+	ColorPending, ColorTagText = p.Pending, p.TagText
+	ColorTrading, ColorPortfolio, ColorTokenInfo, ColorWallet, ColorBrewing = p.Trading, p.Portfolio, p.TokenInfo, p.Wallet, p.Brewing
+	ColorSecurity, ColorOrders, ColorAnalytics, ColorTracking, ColorStreaming = p.Security, p.Orders, p.Analytics, p.Tracking, p.Streaming
+
+	TitleStyle = TitleStyle.Foreground(ColorBoba)
+	SubtitleStyle = SubtitleStyle.Foreground(ColorDim)
+	BrightStyle = BrightStyle.Foreground(ColorBright)
+	GoldStyle = GoldStyle.Foreground(ColorGold)
+	SuccessStyle = SuccessStyle.Foreground(ColorGreen)
+	ErrorStyle = ErrorStyle.Foreground(ColorRed)
+	WarningStyle = WarningStyle.Foreground(p.Warning)
+	InfoStyle = InfoStyle.Foreground(ColorCyan)
+	DimStyle = DimStyle.Foreground(ColorDim)
+	BoxBorder = BoxBorder.BorderForeground(ColorBoba)
+	SuccessBoxBorder = SuccessBoxBorder.BorderForeground(ColorGreen)
+	ErrorBoxBorder = ErrorBoxBorder.BorderForeground(ColorRed)
+	GoldBoxBorder = GoldBoxBorder.BorderForeground(ColorGold)
+}
+
+// TagStyle is the style of a category badge on background bg. Without
+// colors the badge is shown reversed.
+func TagStyle(bg lipgloss.Color) lipgloss.Style {
+	s := lipgloss.NewStyle().Bold(true).Padding(0, 1)
+	if bg == "" {
+		return s.Reverse(true)
+	}
+	return s.Foreground(ColorTagText).Background(bg)
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -8,6 +9,10 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
+// ErrInterrupted is returned by RunWithSpinner when the user presses Ctrl+C
+// before the task finishes.
+var ErrInterrupted = errors.New("interrupted")
+
 // errMsg wraps an error returned from the background function.
 type errMsg struct{ err error }
 
@@ -57,6 +62,7 @@ func (m spinnerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
+			m.err = ErrInterrupted
 			m.quitting = true
 			return m, tea.Quit
 		}
@@ -87,8 +93,12 @@ func (m spinnerModel) View() string {
 }
 
 // RunWithSpinner displays a spinner while fn executes. It shows a success
-// or failure message when the function completes.
+// or failure message when the function completes. Without decoration, in
+// verbose mode or on a slow terminal, fn runs as a plain Step instead.
 func RunWithSpinner(msg string, fn func() error) error {
+	if !Animate() {
+		return Step(msg, fn)
+	}
 	model := newSpinnerModel(msg, fn)
 	p := tea.NewProgram(model, tea.WithInputTTY())
 	finalModel, err := p.Run()
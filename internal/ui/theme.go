@@ -220,12 +220,13 @@ func isStreaming(name string) bool {
 // ToolTag returns a styled category tag like [TRADE] for a tool name.
 func ToolTag(toolName string) string {
 	tag, color := toolTagInfo(toolName)
-	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#1a1a2e")).
-		Background(color).
-		Bold(true).
-		Padding(0, 1).
-		Render(tag)
+	return TagStyle(color).Render(tag)
+}
+
+// ToolCategory returns the category of a tool name, the label ToolTag shows.
+func ToolCategory(toolName string) string {
+	tag, _ := toolTagInfo(toolName)
+	return tag
 }
 
 func toolTagInfo(toolName string) (string, lipgloss.Color) {
@@ -0,0 +1,122 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// The update check lives here rather than in package version, next to the
+// release feed it reads: version only holds the ldflags-set build strings,
+// and the bridge and every command import it without pulling in the
+// network or config. Callers pass version.Version in as current.
+
+// The background check asks for the latest release at most every
+// CheckInterval, and gives up after CheckTimeout.
+const (
+	CheckInterval = 24 * time.Hour
+	CheckTimeout  = 2 * time.Second
+)
+
+// notesLines is how many lines of the release notes a notice keeps.
+const notesLines = 3
+
+// Notice tells of a release newer than the running version.
+type Notice struct {
+	Version string
+	Notes   string // the first lines of its release notes; may be empty
+}
+
+// checkState is the outcome of the last check, kept between runs so the
+// releases are fetched at most once per CheckInterval.
+type checkState struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	Channel   string    `json:"channel"`
+	Latest    string    `json:"latest,omitempty"`
+	Notes     string    `json:"notes,omitempty"`
+}
+
+func checkStatePath() string {
+	return filepath.Join(config.DataDir(), "update-check.json")
+}
+
+// Check returns a notice when channel has a release newer than current, or
+// nil. Within CheckInterval of the last check it answers from that one
+// without going online, unless force is set. A failed fetch is remembered
+// too, so an offline machine doesn't try on every run; until the next try
+// the release seen before still counts. Only a forced check reports the
+// error.
+func Check(ctx context.Context, current, channel string, force bool) (*Notice, error) {
+	var state checkState
+	if data, err := os.ReadFile(checkStatePath()); err == nil {
+		_ = json.Unmarshal(data, &state)
+	}
+	if force || state.Channel != channel || time.Since(state.CheckedAt) > CheckInterval {
+		if state.Channel != channel {
+			// The release seen on the other channel doesn't count on this one.
+			state.Latest, state.Notes = "", ""
+		}
+		release, err := latest(ctx, channel)
+		state.CheckedAt, state.Channel = time.Now(), channel
+		if err == nil {
+			state.Latest, state.Notes = release.Version(), NotesExcerpt(release.Body)
+		}
+		if data, merr := json.Marshal(state); merr == nil {
+			_ = config.WritePrivateFile(checkStatePath(), data)
+		}
+		if err != nil && force {
+			return nil, err
+		}
+	}
+	if state.Latest == "" || !IsNewer(current, state.Latest) {
+		return nil, nil
+	}
+	return &Notice{Version: state.Latest, Notes: state.Notes}, nil
+}
+
+// CheckInBackground runs Check within CheckTimeout and sends its notice on
+// the returned channel: nil when there is no newer release or the check
+// failed. Development builds aren't checked.
+func CheckInBackground(current, channel string) <-chan *Notice {
+	ch := make(chan *Notice, 1)
+	if current == "" || current == "dev" {
+		ch <- nil
+		return ch
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), CheckTimeout)
+		defer cancel()
+		notice, _ := Check(ctx, current, channel, false)
+		ch <- notice
+	}()
+	return ch
+}
+
+// NotesExcerpt returns the first lines of Markdown release notes as plain
+// text, leaving out headings, blank lines and list markers.
+func NotesExcerpt(body string) string {
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "<!--") {
+			continue
+		}
+		for _, marker := range []string{"- ", "* ", "+ "} {
+			line = strings.TrimPrefix(line, marker)
+		}
+		line = strings.NewReplacer("**", "", "__", "", "`", "").Replace(line)
+		if r := []rune(line); len(r) > 100 {
+			line = string(r[:99]) + "…"
+		}
+		lines = append(lines, line)
+		if len(lines) == notesLines {
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
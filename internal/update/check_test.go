@@ -0,0 +1,145 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// releaseServer serves a release list, or a 500 while failing is set, and
+// counts the fetches.
+type releaseServer struct {
+	fetches atomic.Int64
+	failing atomic.Bool
+}
+
+func (s *releaseServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.fetches.Add(1)
+	if s.failing.Load() {
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode([]Release{
+		{TagName: "v1.3.0-beta.1", Prerelease: true, Body: "Beta notes"},
+		{TagName: "v1.2.0", Body: "## What's new\n\n- **Faster** portfolio\n- Fixes"},
+		{TagName: "v1.1.0"},
+	})
+}
+
+func newReleaseServer(t *testing.T) *releaseServer {
+	t.Helper()
+	config.UseDir(t.TempDir())
+	rs := &releaseServer{}
+	srv := httptest.NewServer(rs)
+	t.Cleanup(srv.Close)
+	t.Setenv("BOBA_RELEASES_URL", srv.URL)
+	return rs
+}
+
+// age moves the last check back by d.
+func age(t *testing.T, d time.Duration) {
+	t.Helper()
+	var state checkState
+	data, err := os.ReadFile(checkStatePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	json.Unmarshal(data, &state)
+	state.CheckedAt = state.CheckedAt.Add(-d)
+	data, _ = json.Marshal(state)
+	if err := os.WriteFile(checkStatePath(), data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func check(t *testing.T, channel string, force bool) *Notice {
+	t.Helper()
+	n, err := Check(context.Background(), "1.1.0", channel, force)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestCheckCachesForADay(t *testing.T) {
+	rs := newReleaseServer(t)
+
+	n := check(t, ChannelStable, false)
+	if n == nil || n.Version != "1.2.0" || n.Notes != "Faster portfolio\nFixes" {
+		t.Fatalf("notice = %+v", n)
+	}
+	if n := check(t, ChannelStable, false); n == nil || n.Version != "1.2.0" {
+		t.Errorf("cached notice = %+v", n)
+	}
+	if got := rs.fetches.Load(); got != 1 {
+		t.Errorf("%d fetches within a day, want 1", got)
+	}
+
+	age(t, CheckInterval+time.Minute)
+	check(t, ChannelStable, false)
+	if got := rs.fetches.Load(); got != 2 {
+		t.Errorf("%d fetches after a day, want 2", got)
+	}
+	check(t, ChannelStable, true)
+	if got := rs.fetches.Load(); got != 3 {
+		t.Errorf("%d fetches after a forced check, want 3", got)
+	}
+
+	if n, _ := Check(context.Background(), "1.2.0", ChannelStable, false); n != nil {
+		t.Errorf("notice for the running version: %+v", n)
+	}
+}
+
+func TestCheckChannelSwitch(t *testing.T) {
+	rs := newReleaseServer(t)
+	check(t, ChannelStable, false)
+
+	// The switch checks again at once, and a failure doesn't leave the
+	// stable release standing in for the beta one.
+	rs.failing.Store(true)
+	if n := check(t, ChannelBeta, false); n != nil {
+		t.Errorf("beta notice from the stable check: %+v", n)
+	}
+	if got := rs.fetches.Load(); got != 2 {
+		t.Errorf("%d fetches, want the switch to fetch", got)
+	}
+
+	rs.failing.Store(false)
+	age(t, CheckInterval+time.Minute)
+	if n := check(t, ChannelBeta, false); n == nil || n.Version != "1.3.0-beta.1" {
+		t.Errorf("beta notice = %+v", n)
+	}
+	if n := check(t, ChannelStable, false); n == nil || n.Version != "1.2.0" {
+		t.Errorf("stable notice after switching back = %+v", n)
+	}
+}
+
+func TestCheckRemembersFailure(t *testing.T) {
+	rs := newReleaseServer(t)
+	check(t, ChannelStable, false)
+
+	rs.failing.Store(true)
+	age(t, CheckInterval+time.Minute)
+	n, err := Check(context.Background(), "1.1.0", ChannelStable, false)
+	if err != nil {
+		t.Fatalf("background check failed: %v", err)
+	}
+	if n == nil || n.Version != "1.2.0" {
+		t.Errorf("the release seen before no longer counts: %+v", n)
+	}
+	check(t, ChannelStable, false)
+	if got := rs.fetches.Load(); got != 2 {
+		t.Errorf("%d fetches, want the failure remembered", got)
+	}
+
+	if _, err := Check(context.Background(), "1.1.0", ChannelStable, true); err == nil {
+		t.Error("forced check hid the failure")
+	}
+}
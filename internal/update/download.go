@@ -0,0 +1,211 @@
+package update
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/logger"
+)
+
+// DownloadOptions controls retry behaviour for Download.
+type DownloadOptions struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int
+	// Backoff is the delay before the first retry; it doubles each retry.
+	Backoff time.Duration
+	// Progress, if set, is called with bytes written so far and the total
+	// size (0 when unknown).
+	Progress func(done, total int64)
+}
+
+// DefaultDownloadOptions retries five times starting at a one second backoff.
+var DefaultDownloadOptions = DownloadOptions{
+	MaxAttempts: 5,
+	Backoff:     time.Second,
+}
+
+// errChecksum marks a completed download whose digest did not match.
+var errChecksum = errors.New("checksum mismatch")
+
+// Download fetches url into dest, verifying its SHA-256 digest against
+// wantSHA. Data is written to dest+".part" first; when a connection drops the
+// next attempt resumes with an HTTP Range request instead of starting over.
+// The digest is computed incrementally as bytes arrive. A lock file guards
+// the partial download so concurrent updaters don't interleave writes.
+func Download(url, dest, wantSHA string, opts DownloadOptions) error {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 1
+	}
+
+	unlock, err := lockFile(dest + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	part := dest + ".part"
+	backoff := opts.Backoff
+
+	var lastErr error
+	for attempt := 1; attempt <= opts.MaxAttempts; attempt++ {
+		if attempt > 1 {
+			logger.Debug("retrying download", "attempt", attempt, "error", lastErr)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		lastErr = downloadAttempt(url, part, wantSHA, opts.Progress)
+		if lastErr == nil {
+			return os.Rename(part, dest)
+		}
+		if errors.Is(lastErr, errChecksum) {
+			// The partial file is corrupt; start from scratch next time.
+			_ = os.Remove(part)
+		}
+	}
+	return fmt.Errorf("download failed after %d attempts: %w", opts.MaxAttempts, lastErr)
+}
+
+// downloadAttempt resumes or starts a single transfer into part.
+func downloadAttempt(url, part, wantSHA string, progress func(done, total int64)) error {
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Re-hash whatever was already downloaded so verification covers the
+	// whole file, not just the resumed tail.
+	h := sha256.New()
+	offset, err := io.Copy(h, f)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	client := &http.Client{Timeout: 10 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var total int64
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		total = offset + resp.ContentLength
+		if start := rangeStart(resp.Header.Get("Content-Range")); start != offset {
+			return fmt.Errorf("server resumed at byte %d, expected %d", start, offset)
+		}
+	case http.StatusOK:
+		// Server ignored the Range header; restart from the beginning.
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		h.Reset()
+		offset = 0
+		total = resp.ContentLength
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Already have every byte; fall through to verification.
+		return verify(h, wantSHA)
+	default:
+		return fmt.Errorf("download failed: HTTP %d", resp.StatusCode)
+	}
+	if total < 0 {
+		total = 0
+	}
+
+	w := io.MultiWriter(f, h)
+	buf := make([]byte, 32*1024)
+	done := offset
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+			done += int64(n)
+			if progress != nil {
+				progress(done, total)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+
+	if total > 0 && done < total {
+		return io.ErrUnexpectedEOF
+	}
+	return verify(h, wantSHA)
+}
+
+func verify(h hash.Hash, wantSHA string) error {
+	if wantSHA == "" {
+		return nil
+	}
+	got := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(got, wantSHA) {
+		return fmt.Errorf("%w: got %s, want %s", errChecksum, got, wantSHA)
+	}
+	return nil
+}
+
+// rangeStart parses the first byte offset from a Content-Range header such
+// as "bytes 100-199/200". Returns -1 when the header is malformed.
+func rangeStart(header string) int64 {
+	header = strings.TrimPrefix(header, "bytes ")
+	dash := strings.IndexByte(header, '-')
+	if dash < 0 {
+		return -1
+	}
+	n, err := strconv.ParseInt(header[:dash], 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// lockFile creates path exclusively and returns a func that removes it.
+// Locks older than ten minutes are treated as abandoned.
+func lockFile(path string) (func(), error) {
+	for i := 0; i < 2; i++ {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		info, statErr := os.Stat(path)
+		if statErr != nil || time.Since(info.ModTime()) < 10*time.Minute {
+			break
+		}
+		_ = os.Remove(path)
+	}
+	return nil, fmt.Errorf("another update is already downloading (lock %s)", path)
+}
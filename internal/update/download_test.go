@@ -0,0 +1,168 @@
+package update
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flakyServer serves payload, dropping the connection halfway through the
+// first response. It records the Range header of every request.
+type flakyServer struct {
+	payload []byte
+	// ignoreRange serves the whole file to every request after the drop.
+	ignoreRange bool
+
+	mu     sync.Mutex
+	ranges []string
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	first := len(s.ranges) == 0
+	s.ranges = append(s.ranges, r.Header.Get("Range"))
+	s.mu.Unlock()
+
+	if first {
+		w.Header().Set("Content-Length", strconv.Itoa(len(s.payload)))
+		w.Write(s.payload[:len(s.payload)/2])
+		w.(http.Flusher).Flush()
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err == nil {
+			conn.Close()
+		}
+		return
+	}
+	if s.ignoreRange {
+		r.Header.Del("Range")
+	}
+	http.ServeContent(w, r, "boba", time.Time{}, bytes.NewReader(s.payload))
+}
+
+func testPayload(t *testing.T) ([]byte, string) {
+	t.Helper()
+	payload := make([]byte, 256*1024)
+	if _, err := rand.Read(payload); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(payload)
+	return payload, hex.EncodeToString(sum[:])
+}
+
+var testOptions = DownloadOptions{MaxAttempts: 3, Backoff: time.Millisecond}
+
+func TestDownloadResumesAfterDrop(t *testing.T) {
+	payload, sum := testPayload(t)
+	fs := &flakyServer{payload: payload}
+	srv := httptest.NewServer(fs)
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "boba")
+	var last int64
+	opts := testOptions
+	opts.Progress = func(done, total int64) { last = done }
+	if err := Download(srv.URL, dest, sum, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatal("downloaded file differs from the payload")
+	}
+	if want := []string{"", "bytes=" + strconv.Itoa(len(payload)/2) + "-"}; len(fs.ranges) != 2 || fs.ranges[0] != want[0] || fs.ranges[1] != want[1] {
+		t.Errorf("requests sent Range %q, want %q", fs.ranges, want)
+	}
+	if last != int64(len(payload)) {
+		t.Errorf("progress ended at %d, want %d", last, len(payload))
+	}
+	for _, leftover := range []string{dest + ".part", dest + ".lock"} {
+		if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+			t.Errorf("%s left behind", filepath.Base(leftover))
+		}
+	}
+}
+
+// A server that answers the resume with the whole file restarts the
+// download, and the digest covers only the new bytes.
+func TestDownloadRangeIgnored(t *testing.T) {
+	payload, sum := testPayload(t)
+	fs := &flakyServer{payload: payload, ignoreRange: true}
+	srv := httptest.NewServer(fs)
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "boba")
+	if err := Download(srv.URL, dest, sum, testOptions); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Fatal("downloaded file differs from the payload")
+	}
+}
+
+// The digest is taken over the whole file, so a corrupt partial download
+// fails verification even though the resumed tail is good. The part file
+// is then dropped and the next attempt starts over.
+func TestDownloadChecksumCoversResumedBytes(t *testing.T) {
+	payload, sum := testPayload(t)
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "boba", time.Time{}, bytes.NewReader(payload))
+	}))
+	defer srv.Close()
+
+	dest := filepath.Join(t.TempDir(), "boba")
+	corrupt := bytes.Repeat([]byte{0xff}, len(payload)/2)
+	if err := os.WriteFile(dest+".part", corrupt, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := testOptions
+	opts.MaxAttempts = 1
+	err := Download(srv.URL, dest, sum, opts)
+	if !errors.Is(err, errChecksum) {
+		t.Fatalf("corrupt resume: err = %v, want a checksum mismatch", err)
+	}
+	if _, err := os.Stat(dest + ".part"); !os.IsNotExist(err) {
+		t.Error("corrupt part file kept")
+	}
+
+	if err := os.WriteFile(dest+".part", corrupt, 0600); err != nil {
+		t.Fatal(err)
+	}
+	ranges = nil
+	if err := Download(srv.URL, dest, sum, testOptions); err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) != 2 || ranges[0] == "" || ranges[1] != "" {
+		t.Errorf("requests sent Range %q, want a resume then a fresh start", ranges)
+	}
+	if got, _ := os.ReadFile(dest); !bytes.Equal(got, payload) {
+		t.Fatal("downloaded file differs from the payload")
+	}
+}
+
+func TestDownloadLocked(t *testing.T) {
+	dest := filepath.Join(t.TempDir(), "boba")
+	unlock, err := lockFile(dest + ".lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+	if err := Download("http://127.0.0.1:1", dest, "", testOptions); err == nil {
+		t.Error("download ran while another held the lock")
+	}
+}
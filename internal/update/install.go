@@ -0,0 +1,133 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// NPMPackage is the npm package that ships the CLI.
+const NPMPackage = "@tradeboba/cli"
+
+// IsNPMManaged reports whether the binary at path was installed by npm.
+// npm installs the platform binary under node_modules and, on Windows,
+// fronts it with a .cmd shim. Replacing such a file in place would be
+// clobbered by the next npm update, so self-update refuses to touch it.
+func IsNPMManaged(path string) bool {
+	p := filepath.ToSlash(strings.ToLower(path))
+	return strings.Contains(p, "/node_modules/") || strings.HasSuffix(p, ".cmd")
+}
+
+// NPMCommand returns the npm command that installs the given version.
+func NPMCommand(version string) string {
+	if version == "" {
+		return fmt.Sprintf("npm install -g %s@latest", NPMPackage)
+	}
+	return fmt.Sprintf("npm install -g %s@%s", NPMPackage, version)
+}
+
+// binaryName is the executable name inside release archives.
+func binaryName() string {
+	if runtime.GOOS == "windows" {
+		return "boba.exe"
+	}
+	return "boba"
+}
+
+// ExtractBinary pulls the boba executable out of a downloaded release
+// archive and writes it to dest with executable permissions.
+func ExtractBinary(archive, dest string) error {
+	if strings.HasSuffix(archive, ".zip") {
+		return extractZip(archive, dest)
+	}
+	return extractTarGz(archive, dest)
+}
+
+func extractTarGz(archive, dest string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == binaryName() {
+			return writeExecutable(dest, tr)
+		}
+	}
+	return fmt.Errorf("%s not found in archive", binaryName())
+}
+
+func extractZip(archive, dest string) error {
+	zr, err := zip.OpenReader(archive)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	defer zr.Close()
+
+	for _, zf := range zr.File {
+		if filepath.Base(zf.Name) != binaryName() {
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return err
+		}
+		defer rc.Close()
+		return writeExecutable(dest, rc)
+	}
+	return fmt.Errorf("%s not found in archive", binaryName())
+}
+
+func writeExecutable(dest string, r io.Reader) error {
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReplaceExecutable swaps the running binary at target for newBinary. The old
+// binary is moved aside first because Windows cannot overwrite a running
+// executable; it is cleaned up on the next update.
+func ReplaceExecutable(target, newBinary string) error {
+	old := target + ".old"
+	_ = os.Remove(old)
+
+	if err := os.Rename(target, old); err != nil {
+		return fmt.Errorf("failed to move current binary aside: %w", err)
+	}
+	if err := os.Rename(newBinary, target); err != nil {
+		// Put the original back so the user isn't left without a binary.
+		_ = os.Rename(old, target)
+		return fmt.Errorf("failed to install new binary: %w", err)
+	}
+	if runtime.GOOS != "windows" {
+		_ = os.Remove(old)
+	}
+	return nil
+}
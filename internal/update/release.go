@@ -0,0 +1,193 @@
+package update
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ReleasesURL lists GitHub releases for the CLI. BOBA_RELEASES_URL
+// overrides it, for a mirror or for testing.
+var ReleasesURL = "https://api.github.com/repos/Able-labs-xyz/Boba-CLI/releases"
+
+// Release channels.
+const (
+	ChannelStable = "stable"
+	ChannelBeta   = "beta"
+)
+
+// Asset is a downloadable file attached to a release.
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// Release is the subset of a GitHub release the updater needs.
+type Release struct {
+	TagName    string  `json:"tag_name"`
+	Prerelease bool    `json:"prerelease"`
+	Draft      bool    `json:"draft"`
+	Body       string  `json:"body"` // release notes, in Markdown
+	Assets     []Asset `json:"assets"`
+}
+
+// Version returns the release tag without a leading "v".
+func (r *Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// ValidChannel reports whether name is a known release channel.
+func ValidChannel(name string) bool {
+	return name == ChannelStable || name == ChannelBeta
+}
+
+// Latest returns the newest release on the given channel. The stable channel
+// ignores prereleases; beta considers every published release.
+func Latest(channel string) (*Release, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	return latest(ctx, channel)
+}
+
+// latest is Latest within ctx.
+func latest(ctx context.Context, channel string) (*Release, error) {
+	url := ReleasesURL
+	if v := os.Getenv("BOBA_RELEASES_URL"); v != "" {
+		url = v
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch releases: HTTP %d", resp.StatusCode)
+	}
+
+	var releases []Release
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("failed to parse releases: %w", err)
+	}
+
+	// GitHub returns releases newest first.
+	for i := range releases {
+		r := &releases[i]
+		if r.Draft {
+			continue
+		}
+		if r.Prerelease && channel != ChannelBeta {
+			continue
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("no releases found on the %s channel", channel)
+}
+
+// ArchiveName returns the goreleaser archive name for this platform.
+func ArchiveName() string {
+	ext := "tar.gz"
+	if runtime.GOOS == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("boba_%s_%s.%s", runtime.GOOS, runtime.GOARCH, ext)
+}
+
+// FindAsset returns the asset with the given name.
+func (r *Release) FindAsset(name string) (*Asset, error) {
+	for i := range r.Assets {
+		if r.Assets[i].Name == name {
+			return &r.Assets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("release %s has no asset %s", r.TagName, name)
+}
+
+// Checksum downloads checksums.txt from the release and returns the SHA-256
+// hex digest listed for the named file.
+func (r *Release) Checksum(name string) (string, error) {
+	asset, err := r.FindAsset("checksums.txt")
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(asset.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch checksums: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch checksums: HTTP %d", resp.StatusCode)
+	}
+
+	return parseChecksums(resp.Body, name)
+}
+
+// parseChecksums finds name in a sha256sum-style listing.
+func parseChecksums(r io.Reader, name string) (string, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no checksum listed for %s", name)
+}
+
+// IsNewer reports whether latest is a higher semantic version than current.
+// Development builds are always considered out of date.
+func IsNewer(current, latest string) bool {
+	current = strings.TrimPrefix(current, "v")
+	latest = strings.TrimPrefix(latest, "v")
+	if current == "dev" || current == "" {
+		return true
+	}
+	cp, cPre := splitVersion(current)
+	lp, lPre := splitVersion(latest)
+	for i := 0; i < 3; i++ {
+		if lp[i] != cp[i] {
+			return lp[i] > cp[i]
+		}
+	}
+	// Same core version: a release beats a prerelease of it.
+	if cPre != "" && lPre == "" {
+		return true
+	}
+	if cPre != "" && lPre != "" {
+		return lPre > cPre
+	}
+	return false
+}
+
+func splitVersion(v string) ([3]int, string) {
+	var parts [3]int
+	pre := ""
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		pre = v[i+1:]
+		v = v[:i]
+	}
+	for i, p := range strings.SplitN(v, ".", 3) {
+		fmt.Sscanf(p, "%d", &parts[i])
+	}
+	return parts, pre
+}
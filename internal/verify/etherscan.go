@@ -0,0 +1,184 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// EtherscanURL is the Etherscan v2 multichain API; the chain is picked with
+// the chainid parameter.
+var EtherscanURL = "https://api.etherscan.io/v2/api"
+
+// transferTopic is keccak256("Transfer(address,address,uint256)").
+const transferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+
+// etherscan reads EVM transactions through the Etherscan proxy module. ERC-20
+// transfers come from receipt logs and the native leg from the tx value.
+type etherscan struct {
+	chain   string
+	chainID int
+}
+
+type evmLog struct {
+	Address string   `json:"address"`
+	Topics  []string `json:"topics"`
+	Data    string   `json:"data"`
+}
+
+func (e etherscan) Transaction(hash string) (*Transaction, error) {
+	var receipt struct {
+		Status string   `json:"status"`
+		Logs   []evmLog `json:"logs"`
+	}
+	if err := e.call("eth_getTransactionReceipt", url.Values{"txhash": {hash}}, &receipt); err != nil {
+		return nil, err
+	}
+	if receipt.Status == "" {
+		return nil, fmt.Errorf("transaction %s not found on %s", hash, e.chain)
+	}
+
+	var txn struct {
+		From  string `json:"from"`
+		To    string `json:"to"`
+		Value string `json:"value"`
+	}
+	if err := e.call("eth_getTransactionByHash", url.Values{"txhash": {hash}}, &txn); err != nil {
+		return nil, err
+	}
+
+	tx := &Transaction{
+		Hash:    hash,
+		Chain:   e.chain,
+		Success: receipt.Status == "0x1",
+		// Unwrapped native output is paid out in an internal call.
+		NativeOutHidden: true,
+	}
+
+	if v := hexInt(txn.Value); v.Sign() > 0 {
+		tx.Transfers = append(tx.Transfers, Transfer{
+			Token:  Native,
+			From:   txn.From,
+			To:     txn.To,
+			Amount: scale(v, 18),
+		})
+	}
+
+	decimals := make(map[string]int)
+	for _, l := range receipt.Logs {
+		if len(l.Topics) != 3 || !strings.EqualFold(l.Topics[0], transferTopic) {
+			continue
+		}
+		token := strings.ToLower(l.Address)
+		d, ok := decimals[token]
+		if !ok {
+			d = e.decimals(token)
+			decimals[token] = d
+		}
+		tx.Transfers = append(tx.Transfers, Transfer{
+			Token:  token,
+			From:   topicAddress(l.Topics[1]),
+			To:     topicAddress(l.Topics[2]),
+			Amount: scale(hexInt(l.Data), d),
+		})
+	}
+	return tx, nil
+}
+
+// decimals asks the token contract for its decimals, assuming 18 when the
+// call fails.
+func (e etherscan) decimals(token string) int {
+	var result string
+	err := e.call("eth_call", url.Values{
+		"to":   {token},
+		"data": {"0x313ce567"}, // decimals()
+		"tag":  {"latest"},
+	}, &result)
+	if err != nil {
+		return 18
+	}
+	if d := hexInt(result); d.IsInt64() && d.Int64() > 0 && d.Int64() <= 36 {
+		return int(d.Int64())
+	}
+	return 18
+}
+
+// call runs one proxy-module action and decodes its JSON-RPC result.
+// Etherscan reports errors (including a missing API key) as a string result.
+func (e etherscan) call(action string, params url.Values, out any) error {
+	params.Set("chainid", strconv.Itoa(e.chainID))
+	params.Set("module", "proxy")
+	params.Set("action", action)
+	if key := config.GetExplorerAPIKey(e.chain); key != "" {
+		params.Set("apikey", key)
+	}
+
+	client := &http.Client{Timeout: 20 * time.Second}
+	resp, err := client.Get(EtherscanURL + "?" + params.Encode())
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: HTTP %d", ErrUnavailable, resp.StatusCode)
+	}
+
+	var body struct {
+		Result json.RawMessage `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if body.Error != nil {
+		return fmt.Errorf("%w: %s", ErrUnavailable, body.Error.Message)
+	}
+
+	// A null result means the transaction isn't known (yet).
+	if len(body.Result) == 0 || string(body.Result) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(body.Result, out); err != nil {
+		var msg string
+		if json.Unmarshal(body.Result, &msg) == nil {
+			return fmt.Errorf("%w: %s", ErrUnavailable, msg)
+		}
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	return nil
+}
+
+func hexInt(s string) *big.Int {
+	n := new(big.Int)
+	s = strings.TrimPrefix(s, "0x")
+	if s == "" {
+		return n
+	}
+	n.SetString(s, 16)
+	return n
+}
+
+func scale(v *big.Int, decimals int) float64 {
+	f := new(big.Float).SetInt(v)
+	f.Quo(f, new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	out, _ := f.Float64()
+	return out
+}
+
+// topicAddress extracts the address from a 32-byte indexed topic.
+func topicAddress(topic string) string {
+	topic = strings.TrimPrefix(topic, "0x")
+	if len(topic) < 40 {
+		return ""
+	}
+	return "0x" + strings.ToLower(topic[len(topic)-40:])
+}
@@ -0,0 +1,149 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SolanaRPCURL is the public mainnet RPC endpoint. BOBA_SOLANA_RPC_URL
+// overrides it, e.g. with a provider URL that embeds an API key.
+var SolanaRPCURL = "https://api.mainnet-beta.solana.com"
+
+const lamportsPerSOL = 1e9
+
+// solanaRPC reads transactions with getTransaction and reports per-owner
+// balance changes as transfers, since Solana has no transfer logs.
+type solanaRPC struct{}
+
+type solanaTokenBalance struct {
+	AccountIndex  int    `json:"accountIndex"`
+	Mint          string `json:"mint"`
+	Owner         string `json:"owner"`
+	UITokenAmount struct {
+		UIAmountString string `json:"uiAmountString"`
+	} `json:"uiTokenAmount"`
+}
+
+type solanaTxResult struct {
+	Meta *struct {
+		Err               any                  `json:"err"`
+		Fee               int64                `json:"fee"`
+		PreBalances       []int64              `json:"preBalances"`
+		PostBalances      []int64              `json:"postBalances"`
+		PreTokenBalances  []solanaTokenBalance `json:"preTokenBalances"`
+		PostTokenBalances []solanaTokenBalance `json:"postTokenBalances"`
+	} `json:"meta"`
+	Transaction struct {
+		Message struct {
+			AccountKeys []struct {
+				Pubkey string `json:"pubkey"`
+			} `json:"accountKeys"`
+		} `json:"message"`
+	} `json:"transaction"`
+}
+
+func (solanaRPC) Transaction(sig string) (*Transaction, error) {
+	url := SolanaRPCURL
+	if v := os.Getenv("BOBA_SOLANA_RPC_URL"); v != "" {
+		url = v
+	}
+
+	payload, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "getTransaction",
+		"params": []any{sig, map[string]any{
+			"encoding":                       "jsonParsed",
+			"commitment":                     "confirmed",
+			"maxSupportedTransactionVersion": 0,
+		}},
+	})
+
+	client := &http.Client{Timeout: 20 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: HTTP %d", ErrUnavailable, resp.StatusCode)
+	}
+
+	var out struct {
+		Result *solanaTxResult `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if out.Error != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnavailable, out.Error.Message)
+	}
+	if out.Result == nil || out.Result.Meta == nil {
+		return nil, fmt.Errorf("transaction %s not found on solana", sig)
+	}
+
+	return solanaTransfers(sig, out.Result), nil
+}
+
+// solanaTransfers turns balance deltas into transfers: a decrease for an
+// owner is a transfer from it, an increase a transfer to it. Wrapped SOL is
+// folded into native SOL, and the fee is added back for the fee payer so it
+// doesn't count against the swap amount.
+func solanaTransfers(sig string, r *solanaTxResult) *Transaction {
+	meta := r.Meta
+	tx := &Transaction{Hash: sig, Chain: "solana", Success: meta.Err == nil}
+
+	type key struct{ owner, mint string }
+	deltas := make(map[key]float64)
+
+	keys := r.Transaction.Message.AccountKeys
+	for i := range keys {
+		if i >= len(meta.PreBalances) || i >= len(meta.PostBalances) {
+			break
+		}
+		d := meta.PostBalances[i] - meta.PreBalances[i]
+		if i == 0 {
+			d += meta.Fee
+		}
+		if d != 0 {
+			deltas[key{keys[i].Pubkey, Native}] += float64(d) / lamportsPerSOL
+		}
+	}
+
+	for _, b := range meta.PreTokenBalances {
+		deltas[key{b.Owner, tokenKey(b.Mint)}] -= parseAmount(b.UITokenAmount.UIAmountString)
+	}
+	for _, b := range meta.PostTokenBalances {
+		deltas[key{b.Owner, tokenKey(b.Mint)}] += parseAmount(b.UITokenAmount.UIAmountString)
+	}
+
+	for k, d := range deltas {
+		switch {
+		case d < 0:
+			tx.Transfers = append(tx.Transfers, Transfer{Token: k.mint, From: k.owner, Amount: -d})
+		case d > 0:
+			tx.Transfers = append(tx.Transfers, Transfer{Token: k.mint, To: k.owner, Amount: d})
+		}
+	}
+	return tx
+}
+
+func tokenKey(mint string) string {
+	if isNative(mint) {
+		return Native
+	}
+	return mint
+}
+
+func parseAmount(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
@@ -0,0 +1,235 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+	"github.com/tradeboba/boba-cli/internal/journal"
+)
+
+// ErrUnavailable is returned when a chain's explorer can't be reached or
+// refuses the request (e.g. a missing API key).
+var ErrUnavailable = errors.New("explorer unavailable")
+
+// Native is the token address used for a chain's native asset.
+const Native = "native"
+
+// Transfer is a token movement in a transaction. Token is a contract address,
+// mint, or Native.
+type Transfer struct {
+	Token  string
+	From   string
+	To     string
+	Amount float64
+}
+
+// Transaction is what an explorer reports about a transaction.
+type Transaction struct {
+	Hash      string
+	Chain     string
+	Success   bool
+	Transfers []Transfer
+	// NativeOutHidden is set on chains where native payouts happen in
+	// internal calls that don't appear as transfers.
+	NativeOutHidden bool
+}
+
+// Explorer looks up a transaction on one chain.
+type Explorer interface {
+	Transaction(hash string) (*Transaction, error)
+}
+
+// explorers maps chain slugs to their explorer. Solana uses RPC; every EVM
+// chain in config.Chains uses the Etherscan-compatible API.
+var explorers = map[string]Explorer{}
+
+func init() {
+	for _, c := range config.Chains {
+		switch {
+		case c.Slug == "solana":
+			explorers[c.Slug] = solanaRPC{}
+		case c.ChainID > 0:
+			explorers[c.Slug] = etherscan{chain: c.Slug, chainID: c.ChainID}
+		}
+	}
+}
+
+// Register adds or replaces the explorer for a chain slug.
+func Register(chain string, e Explorer) {
+	explorers[chain] = e
+}
+
+// ForChain returns the explorer for a chain slug.
+func ForChain(chain string) (Explorer, error) {
+	e, ok := explorers[chain]
+	if !ok {
+		return nil, fmt.Errorf("no explorer for chain %s", chain)
+	}
+	return e, nil
+}
+
+// Check is one line of a verification report.
+type Check struct {
+	Name   string
+	OK     bool
+	Warn   bool // couldn't be checked; not counted as a failure
+	Detail string
+}
+
+// Report is the result of comparing a journal entry with the chain.
+type Report struct {
+	Entry  *journal.Entry
+	Tx     *Transaction
+	Checks []Check
+}
+
+// Passed reports whether every check passed; warnings don't count.
+func (r *Report) Passed() bool {
+	for _, c := range r.Checks {
+		if !c.OK && !c.Warn {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare checks a journaled trade against the on-chain transaction: that it
+// succeeded, the wallet sent the input amount and the output amount arrived
+// at the wallet. tolerance is relative, e.g. 0.02 allows 2% slippage.
+func Compare(e *journal.Entry, tx *Transaction, tolerance float64) *Report {
+	r := &Report{Entry: e, Tx: tx}
+
+	r.Checks = append(r.Checks, Check{
+		Name: "Transaction succeeded",
+		OK:   tx.Success,
+	})
+
+	if e == nil {
+		r.Checks = append(r.Checks, Check{
+			Name:   "Journal entry",
+			Detail: "no local record of this trade to compare against",
+		})
+		return r
+	}
+
+	r.Checks = append(r.Checks, compareLeg(
+		"Sent "+symbolOr(e.FromSymbol, e.FromToken),
+		tx, e.FromToken, e.FromAmount, tolerance,
+		func(t Transfer) bool { return sameAddress(t.From, e.Wallet) },
+		func(t Transfer) string { return t.From },
+		false,
+	))
+
+	r.Checks = append(r.Checks, compareLeg(
+		"Received "+symbolOr(e.ToSymbol, e.ToToken),
+		tx, e.ToToken, e.ToAmount, tolerance,
+		func(t Transfer) bool { return sameAddress(t.To, e.Wallet) },
+		func(t Transfer) string { return t.To },
+		tx.NativeOutHidden && isNative(e.ToToken),
+	))
+
+	return r
+}
+
+// compareLeg sums the transfers of token that match the wallet side and
+// compares the total with want. When nothing reached the wallet but the
+// token moved elsewhere, the other parties are listed so a wrong recipient
+// is obvious.
+func compareLeg(name string, tx *Transaction, token string, want, tolerance float64,
+	ours func(Transfer) bool, party func(Transfer) string, hidden bool) Check {
+	if token == "" {
+		return Check{Name: name, Warn: true, Detail: "token address not journaled"}
+	}
+
+	var got float64
+	var others []string
+	for _, t := range tx.Transfers {
+		if !sameToken(t.Token, token) {
+			continue
+		}
+		if ours(t) {
+			got += t.Amount
+		} else if p := party(t); p != "" {
+			others = append(others, p)
+		}
+	}
+
+	switch {
+	case got == 0 && hidden:
+		return Check{Name: name, Warn: true, Detail: "native payout is an internal transfer; not visible to the explorer"}
+	case got == 0 && len(others) > 0:
+		return Check{Name: name, Detail: "went to " + strings.Join(dedupe(others), ", ") + ", not the agent wallet"}
+	case got == 0:
+		return Check{Name: name, Detail: "no matching transfer found"}
+	case want == 0:
+		return Check{Name: name, OK: true, Detail: fmt.Sprintf("%g on-chain (amount not journaled)", got)}
+	}
+
+	diff := math.Abs(got-want) / want
+	detail := fmt.Sprintf("%g on-chain vs %g journaled", got, want)
+	if diff > tolerance {
+		return Check{Name: name, Detail: fmt.Sprintf("%s (%.2f%% off)", detail, diff*100)}
+	}
+	return Check{Name: name, OK: true, Detail: detail}
+}
+
+func symbolOr(symbol, token string) string {
+	if symbol != "" {
+		return symbol
+	}
+	if token == "" {
+		return "token"
+	}
+	return token
+}
+
+// nativeAliases are the addresses backends use for a chain's native asset,
+// including wrapped SOL which swaps wrap and unwrap transparently.
+var nativeAliases = map[string]bool{
+	"":     true,
+	Native: true,
+	"sol":  true,
+	"eth":  true,
+	"0x0000000000000000000000000000000000000000":  true,
+	"0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee":  true,
+	"so11111111111111111111111111111111111111112": true,
+	"11111111111111111111111111111111":            true,
+}
+
+func isNative(token string) bool {
+	return nativeAliases[strings.ToLower(token)]
+}
+
+func sameToken(a, b string) bool {
+	if isNative(a) || isNative(b) {
+		return isNative(a) && isNative(b)
+	}
+	return sameAddress(a, b)
+}
+
+// sameAddress compares EVM addresses case-insensitively and anything else
+// (base58) exactly.
+func sameAddress(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if strings.HasPrefix(a, "0x") {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+func dedupe(in []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, s := range in {
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
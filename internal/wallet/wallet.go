@@ -0,0 +1,83 @@
+// Package wallet describes the agent wallet's receive addresses and the
+// networks each one can be funded on.
+package wallet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tradeboba/boba-cli/internal/config"
+)
+
+// Address is one receive address of the agent wallet.
+type Address struct {
+	// Family is "evm" or "solana".
+	Family  string
+	Label   string
+	Address string
+}
+
+// Addresses returns the receive addresses the agent has, EVM first. Filter
+// narrows them to one family and accepts "evm", "solana" or any chain name,
+// so "base" selects the EVM address.
+func Addresses(tokens *config.AuthTokens, filter string) ([]Address, error) {
+	family := ""
+	if filter != "" {
+		var err error
+		if family, err = familyOf(filter); err != nil {
+			return nil, err
+		}
+	}
+
+	var out []Address
+	if tokens.EVMAddress != "" && family != "solana" {
+		out = append(out, Address{Family: "evm", Label: "EVM", Address: tokens.EVMAddress})
+	}
+	if tokens.SolanaAddress != "" && family != "evm" {
+		out = append(out, Address{Family: "solana", Label: "Solana", Address: tokens.SolanaAddress})
+	}
+	if len(out) == 0 {
+		if family != "" {
+			return nil, fmt.Errorf("no %s address for this agent", strings.ToUpper(family))
+		}
+		return nil, fmt.Errorf("no wallet addresses for this agent. Run 'boba auth' to refresh them")
+	}
+	return out, nil
+}
+
+func familyOf(filter string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(filter)) {
+	case "evm":
+		return "evm", nil
+	case "solana", "sol":
+		return "solana", nil
+	}
+	c, ok := config.LookupChain(filter)
+	if !ok {
+		return "", fmt.Errorf("unknown chain %q (use solana, evm or a chain name)", filter)
+	}
+	if c.ChainID == config.SolanaChainID {
+		return "solana", nil
+	}
+	return "evm", nil
+}
+
+// Networks says which networks the address is valid on and what must not
+// be sent to it.
+func (a Address) Networks() string {
+	if a.Family == "solana" {
+		return "Valid on Solana only. Never send EVM tokens here."
+	}
+	return "Valid on every EVM chain. Never send Solana tokens here."
+}
+
+// Chains returns the names of the supported chains the address receives on.
+func (a Address) Chains() []string {
+	var names []string
+	for _, c := range config.Chains {
+		if (c.ChainID == config.SolanaChainID) == (a.Family == "solana") {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,209 @@
+// Package watchlist reads and edits the agent's token watchlist and looks up
+// the prices of the tokens on it. Like the orders package it only needs a
+// way to call tools, so the CLI and the TUI can share it.
+package watchlist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tradeboba/boba-cli/internal/formatter"
+)
+
+// CallFunc makes one tool call and returns the raw response body. It gives
+// up when ctx is cancelled.
+type CallFunc func(ctx context.Context, tool string, args map[string]any) ([]byte, error)
+
+// Token is one token on the watchlist.
+type Token struct {
+	Address string
+	Symbol  string
+	Chain   string
+	// Price is the USD price, 0 when unknown. Change is the 24h change in
+	// percent; HasChange is false when neither the watchlist nor the price
+	// lookup reported one.
+	Price     float64
+	Change    float64
+	HasChange bool
+	// Raw is the token as the backend listed it.
+	Raw map[string]any
+}
+
+// Label names the token by symbol, or by its shortened address.
+func (t Token) Label() string {
+	if t.Symbol != "" {
+		return t.Symbol
+	}
+	return formatter.TruncateAddress(t.Address)
+}
+
+// List fetches the watchlist.
+func List(ctx context.Context, call CallFunc) ([]Token, error) {
+	body, err := call(ctx, "get_watchlist", map[string]any{})
+	if err != nil {
+		return nil, err
+	}
+	var data map[string]any
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("invalid watchlist: %w", err)
+	}
+	if inner, ok := data["data"].(map[string]any); ok {
+		data = inner
+	}
+	var items []any
+	for _, k := range []string{"watchlist", "tokens", "items"} {
+		if list, ok := data[k].([]any); ok {
+			items = list
+			break
+		}
+	}
+
+	out := make([]Token, 0, len(items))
+	for _, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		t := Token{
+			Address: pick(m, "token_address", "address", "mint"),
+			Symbol:  pick(m, "token_symbol", "symbol"),
+			Chain:   pick(m, "chain", "chain_name"),
+			Raw:     m,
+		}
+		if tok, ok := m["token"].(map[string]any); ok {
+			if t.Address == "" {
+				t.Address = pick(tok, "address", "token_address", "mint")
+			}
+			if t.Symbol == "" {
+				t.Symbol = pick(tok, "symbol")
+			}
+		}
+		if t.Address == "" {
+			continue
+		}
+		t.Price, _ = number(m, "price_usd", "price", "current_price")
+		t.Change, t.HasChange = number(m, "price_change_24h", "change_24h", "price_change_percent_24h")
+		out = append(out, t)
+	}
+	return out, nil
+}
+
+// Add puts the token at address on chain on the watchlist.
+func Add(ctx context.Context, call CallFunc, address, chain string) error {
+	args := map[string]any{"token_address": address}
+	if chain != "" {
+		args["chain"] = chain
+	}
+	return edit(ctx, call, "add_to_watchlist", args)
+}
+
+// Remove takes the token at address off the watchlist.
+func Remove(ctx context.Context, call CallFunc, address, chain string) error {
+	args := map[string]any{"token_address": address}
+	if chain != "" {
+		args["chain"] = chain
+	}
+	return edit(ctx, call, "remove_from_watchlist", args)
+}
+
+// edit makes a watchlist change, turning a success:false response into an
+// error.
+func edit(ctx context.Context, call CallFunc, tool string, args map[string]any) error {
+	body, err := call(ctx, tool, args)
+	if err != nil {
+		return err
+	}
+	var resp map[string]any
+	if json.Unmarshal(body, &resp) != nil {
+		return nil
+	}
+	if ok, present := resp["success"].(bool); present && !ok {
+		msg := pick(resp, "error", "message")
+		if msg == "" {
+			msg = "backend refused the watchlist change"
+		}
+		return fmt.Errorf("%s", msg)
+	}
+	return nil
+}
+
+// FillPrices looks up the current price and 24h change of every token with
+// get_token_price, one call per chain. Tokens whose price can't be found
+// keep what the watchlist said.
+func FillPrices(ctx context.Context, call CallFunc, list []Token) {
+	byChain := make(map[string][]int)
+	for i, t := range list {
+		if t.Chain != "" {
+			byChain[t.Chain] = append(byChain[t.Chain], i)
+		}
+	}
+	for chain, idx := range byChain {
+		tokens := make([]string, len(idx))
+		for j, i := range idx {
+			tokens[j] = list[i].Address
+		}
+		body, err := call(ctx, "get_token_price", map[string]any{"tokens": tokens, "chain": chain})
+		if err != nil {
+			continue
+		}
+		var data map[string]any
+		if json.Unmarshal(body, &data) != nil {
+			continue
+		}
+		prices := make(map[string]float64)
+		for address, price := range formatter.TokenPrices(data) {
+			prices[addressKey(address)] = price
+		}
+		changes := make(map[string]float64)
+		for address, change := range formatter.TokenPriceChanges(data) {
+			changes[addressKey(address)] = change
+		}
+		for _, i := range idx {
+			key := addressKey(list[i].Address)
+			if price := prices[key]; price > 0 {
+				list[i].Price = price
+			}
+			if change, ok := changes[key]; ok {
+				list[i].Change, list[i].HasChange = change, true
+			}
+		}
+	}
+}
+
+// addressKey normalizes an address for lookups: EVM addresses are
+// case-insensitive, base58 addresses are not.
+func addressKey(address string) string {
+	if strings.HasPrefix(address, "0x") || strings.HasPrefix(address, "0X") {
+		return strings.ToLower(address)
+	}
+	return address
+}
+
+// pick returns the first non-empty string among keys.
+func pick(m map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// number returns the first of keys present in m as a number, given as a
+// JSON number or a numeric string.
+func number(m map[string]any, keys ...string) (float64, bool) {
+	for _, k := range keys {
+		switch v := m[k].(type) {
+		case float64:
+			return v, true
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, true
+			}
+		}
+	}
+	return 0, false
+}